@@ -0,0 +1,67 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// ResponseCache - Stores the results of slow lookups that rarely change, like
+// organization and region lists, so they aren't fetched repeatedly
+type ResponseCache interface {
+	// Get loads the unexpired value stored under key into v
+	Get(key string, v interface{}) bool
+	// Set stores v under key for ttl
+	Set(key string, v interface{}, ttl time.Duration)
+	// Delete removes every value whose key starts with prefix
+	Delete(prefix string)
+}
+
+var responseCache ResponseCache
+
+// SetResponseCache - Sets the cache used for lookups, nil to disable caching
+func SetResponseCache(cache ResponseCache) {
+	responseCache = cache
+}
+
+// TTLs for cached lookups
+const (
+	organizationsCacheTTL = 5 * time.Minute
+	platformCacheTTL      = 1 * time.Hour
+	// region capabilities include capacity, which changes more often
+	regionCapacityCacheTTL = 5 * time.Minute
+	// names offered by shell completion, kept briefly since every keypress
+	// that completes looks them up
+	completionCacheTTL = 1 * time.Minute
+)
+
+// cached loads the value for key into v, calling fetch to fill v and storing
+// the result when it isn't cached
+func cached(key string, ttl time.Duration, v interface{}, fetch func() error) error {
+	if responseCache != nil && responseCache.Get(key, v) {
+		return nil
+	}
+
+	if err := fetch(); err != nil {
+		return err
+	}
+
+	if responseCache != nil {
+		responseCache.Set(key, v, ttl)
+	}
+
+	return nil
+}
+
+func forgetCached(prefix string) {
+	if responseCache != nil {
+		responseCache.Delete(prefix)
+	}
+}
+
+// userCacheKey scopes a cache key to the current user, without storing their
+// access token
+func (c *Client) userCacheKey(key string) string {
+	sum := sha256.Sum256([]byte(c.accessToken))
+	return key + "-" + hex.EncodeToString(sum[:8])
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/machinebox/graphql"
 	"github.com/superfly/flyctl/flyname"
@@ -17,6 +18,7 @@ import (
 
 var baseURL string
 var errorLog bool
+var timingLog bool
 
 // SetBaseURL - Sets the base URL for the API
 func SetBaseURL(url string) {
@@ -28,24 +30,44 @@ func SetErrorLog(log bool) {
 	errorLog = log
 }
 
+// SetTimingLog - Sets whether the time taken by each request should be logged
+func SetTimingLog(log bool) {
+	timingLog = log
+}
+
 // Client - API client encapsulating the http and GraphQL clients
 type Client struct {
-	httpClient  *http.Client
-	client      *graphql.Client
-	accessToken string
-	userAgent   string
+	httpClient    *http.Client
+	client        *graphql.Client
+	baseURL       string
+	accessToken   string
+	userAgent     string
+	impersonation string
 }
 
 // NewClient - creates a new Client, takes an access token
 func NewClient(accessToken string, version string) *Client {
+	return NewClientWithBaseURL(baseURL, accessToken, version)
+}
+
+// NewClientWithBaseURL - creates a new Client of the API at base, rather
+// than the one set with SetBaseURL
+func NewClientWithBaseURL(base string, accessToken string, version string) *Client {
 
 	httpClient, _ := newHTTPClient()
 
-	url := fmt.Sprintf("%s/graphql", baseURL)
+	url := fmt.Sprintf("%s/graphql", base)
 
 	client := graphql.NewClient(url, graphql.WithHTTPClient(httpClient))
 	userAgent := fmt.Sprintf("%s/%s", flyname.Name(), version)
-	return &Client{httpClient, client, accessToken, userAgent}
+	return &Client{httpClient: httpClient, client: client, baseURL: base, accessToken: accessToken, userAgent: userAgent}
+}
+
+// SetImpersonation - Sends requests as part of an impersonation session,
+// empty to stop. The API evaluates them with the impersonated member's role
+// and records them in the org's audit log. They aren't limited to reads.
+func (c *Client) SetImpersonation(sessionID string) {
+	c.impersonation = sessionID
 }
 
 // NewRequest - creates a new GraphQL request
@@ -63,9 +85,21 @@ func (c *Client) Run(req *graphql.Request) (Query, error) {
 func (c *Client) RunWithContext(ctx context.Context, req *graphql.Request) (Query, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
 	req.Header.Set("User-Agent", c.userAgent)
+	if c.impersonation != "" {
+		req.Header.Set("Fly-Impersonation-Session", c.impersonation)
+	}
+
+	operation := operationName(req.Query())
+	if strings.HasPrefix(operation, "query ") {
+		ctx = withIdempotent(ctx)
+	}
 
 	var resp Query
+	start := time.Now()
 	err := c.client.Run(ctx, req, &resp)
+	if timingLog {
+		fmt.Fprintf(os.Stderr, "API %s took %s\n", operation, time.Since(start).Round(time.Millisecond))
+	}
 	if err != nil && strings.HasPrefix(err.Error(), "graphql: ") {
 		return resp, errors.New(strings.TrimPrefix(err.Error(), "graphql: "))
 	}
@@ -79,6 +113,21 @@ func (c *Client) RunWithContext(ctx context.Context, req *graphql.Request) (Quer
 
 var compactPattern = regexp.MustCompile(`\s+`)
 
+var operationPattern = regexp.MustCompile(`^\s*(query|mutation)?[^{]*\{\s*(\w+)`)
+
+// operationName - describes a GraphQL query by its type and first field,
+// e.g. "query app"
+func operationName(q string) string {
+	m := operationPattern.FindStringSubmatch(q)
+	if m == nil {
+		return "request"
+	}
+	if m[1] == "" {
+		return "query " + m[2]
+	}
+	return m[1] + " " + m[2]
+}
+
 func compactQueryString(q string) string {
 	q = strings.TrimSpace(q)
 	return compactPattern.ReplaceAllString(q, " ")
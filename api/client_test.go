@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient - a client of a fake API that answers each GraphQL query with
+// the data respond returns, or respond's error as a GraphQL error
+func newTestClient(t *testing.T, respond func(query string) (interface{}, error)) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		data, err := respond(body.Query)
+		if err != nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": []map[string]string{{"message": err.Error()}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	previous := baseURL
+	SetBaseURL(server.URL)
+	t.Cleanup(func() { SetBaseURL(previous) })
+
+	return NewClient("token", "test")
+}
+
+// decodeJSON - decodes a fake API response
+func decodeJSON(t *testing.T, s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
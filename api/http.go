@@ -3,8 +3,12 @@ package api
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/PuerkitoBio/rehttp"
@@ -14,17 +18,35 @@ import (
 
 var retryErrors = []string{"INTERNAL_ERROR", "read: connection reset by peer"}
 
+// RetryPolicy - How API requests are retried after server errors, rate
+// limiting and network failures. Queries are retried after any of these;
+// mutations only when the API can't have processed them.
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried, 0 to never retry
+	MaxRetries int
+	// MaxDelay caps the wait between attempts, including waits asked for by
+	// a Retry-After header
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy - The retry policy used unless config.yml overrides it
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, MaxDelay: 5 * time.Second}
+
+var retryPolicy = DefaultRetryPolicy
+
+// SetRetryPolicy - Sets the retry policy for clients created afterwards
+func SetRetryPolicy(policy RetryPolicy) {
+	retryPolicy = policy
+}
+
 func newHTTPClient() (*http.Client, error) {
 	retryTransport := rehttp.NewTransport(
 		http.DefaultTransport,
 		rehttp.RetryAll(
-			rehttp.RetryMaxRetries(3),
-			rehttp.RetryAny(
-				rehttp.RetryTemporaryErr(),
-				rehttp.RetryStatuses(502, 503),
-			),
+			rehttp.RetryMaxRetries(retryPolicy.MaxRetries),
+			shouldRetry,
 		),
-		rehttp.ExpJitterDelay(100*time.Millisecond, 1*time.Second),
+		retryDelay(retryPolicy.MaxDelay),
 	)
 
 	transport := &LoggingTransport{
@@ -38,6 +60,105 @@ func newHTTPClient() (*http.Client, error) {
 	return httpClient, nil
 }
 
+// shouldRetry decides whether a failed attempt is retried. A refused
+// connection or a 429 with a Retry-After never reached the API, so any
+// request is retried. Other failures may have happened after the API
+// processed the request, so only idempotent requests are retried.
+func shouldRetry(attempt rehttp.Attempt) bool {
+	if rehttp.RetryIsErr(isConnectionRefusedErr)(attempt) || isRateLimited(attempt) {
+		return true
+	}
+
+	if !isIdempotent(attempt.Request) {
+		return false
+	}
+
+	return rehttp.RetryAny(
+		rehttp.RetryTemporaryErr(),
+		rehttp.RetryIsErr(isTimeoutErr),
+		rehttp.RetryStatusInterval(500, 600),
+		rehttp.RetryStatuses(http.StatusTooManyRequests),
+	)(attempt)
+}
+
+var contextKeyIdempotent = &contextKey{"Idempotent"}
+
+// withIdempotent - marks the requests made with ctx as safe to repeat, like
+// GraphQL queries
+func withIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyIdempotent, true)
+}
+
+func isIdempotent(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+	idempotent, _ := req.Context().Value(contextKeyIdempotent).(bool)
+	return idempotent
+}
+
+func isRateLimited(attempt rehttp.Attempt) bool {
+	if attempt.Response == nil || attempt.Response.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	_, ok := parseRetryAfter(attempt.Response.Header.Get("Retry-After"), time.Now())
+	return ok
+}
+
+func isConnectionRefusedErr(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func isTimeoutErr(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryDelay waits as long as a Retry-After header asks, otherwise backs off
+// exponentially with jitter, never waiting longer than max
+func retryDelay(max time.Duration) rehttp.DelayFn {
+	backoff := rehttp.ExpJitterDelay(100*time.Millisecond, max)
+
+	return func(attempt rehttp.Attempt) time.Duration {
+		if attempt.Response != nil {
+			if delay, ok := parseRetryAfter(attempt.Response.Header.Get("Retry-After"), time.Now()); ok {
+				if delay > max {
+					delay = max
+				}
+				return delay
+			}
+		}
+		return backoff(attempt)
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := at.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
 type LoggingTransport struct {
 	innerTransport http.RoundTripper
 }
@@ -54,7 +175,13 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 
 	t.logRequest(req)
 
-	resp, err := t.innerTransport.RoundTrip(req)
+	var resp *http.Response
+	var err error
+	if tracer != nil {
+		resp, err = tracer.traceRoundTrip(req, t.innerTransport)
+	} else {
+		resp, err = t.innerTransport.RoundTrip(req)
+	}
 	if err != nil {
 		return resp, err
 	}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/rehttp"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{now.Add(-10 * time.Second).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, c := range cases {
+		delay, ok := parseRetryAfter(c.value, now)
+		if delay != c.delay || ok != c.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", c.value, delay, ok, c.delay, c.ok)
+		}
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}
+	timeout := &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	cases := []struct {
+		name      string
+		operation string
+		response  *http.Response
+		err       error
+		retry     bool
+	}{
+		{"query refused", "query app", nil, refused, true},
+		{"mutation refused", "mutation deployImage", nil, refused, true},
+		{"query rate limited", "query app", response(429, ""), nil, true},
+		{"mutation rate limited with retry-after", "mutation deployImage", response(429, "2"), nil, true},
+		{"mutation rate limited", "mutation deployImage", response(429, ""), nil, false},
+		{"query server error", "query app", response(502, ""), nil, true},
+		{"mutation server error", "mutation deployImage", response(502, ""), nil, false},
+		{"query timeout", "query app", nil, timeout, true},
+		{"mutation timeout", "mutation deployImage", nil, timeout, false},
+		{"query ok", "query app", response(200, ""), nil, false},
+		{"query not found", "query app", response(404, ""), nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			if strings.HasPrefix(c.operation, "query ") {
+				ctx = withIdempotent(ctx)
+			}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.fly.io/graphql", nil)
+
+			attempt := rehttp.Attempt{Request: req, Response: c.response, Error: c.err}
+			if got := shouldRetry(attempt); got != c.retry {
+				t.Errorf("shouldRetry() = %t, want %t", got, c.retry)
+			}
+		})
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://api.fly.io/api/v1/user", nil)
+	if !isIdempotent(get) {
+		t.Error("expected GET requests to be idempotent")
+	}
+
+	post, _ := http.NewRequest(http.MethodPost, "https://api.fly.io/graphql", nil)
+	if isIdempotent(post) {
+		t.Error("expected unmarked POST requests not to be idempotent")
+	}
+
+	if !isIdempotent(post.WithContext(withIdempotent(context.Background()))) {
+		t.Error("expected marked POST requests to be idempotent")
+	}
+}
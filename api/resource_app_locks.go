@@ -0,0 +1,58 @@
+package api
+
+const appLockFields = `
+	id
+	name
+	holder
+	acquiredAt
+	expiresAt
+`
+
+// AcquireAppLock - takes the app's advisory lock called name for holder,
+// until it's released or ttl passes. When someone else holds it, acquired
+// is false and the lock returned is theirs.
+func (client *Client) AcquireAppLock(appName string, name string, holder string, ttl int) (lock *AppLock, acquired bool, err error) {
+	query := `
+		mutation($input: AcquireAppLockInput!) {
+			acquireAppLock(input: $input) {
+				acquired
+				lock {
+					` + appLockFields + `
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", AcquireAppLockInput{
+		AppID:      appName,
+		Name:       name,
+		Holder:     holder,
+		TTLSeconds: ttl,
+	})
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &data.AcquireAppLock.Lock, data.AcquireAppLock.Acquired, nil
+}
+
+func (client *Client) ReleaseAppLock(id string) error {
+	query := `
+		mutation($input: ReleaseAppLockInput!) {
+			releaseAppLock(input: $input) {
+				app {
+					id
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{"lockId": id})
+
+	_, err := client.Run(req)
+	return err
+}
@@ -34,6 +34,65 @@ func (client *Client) GetApps(role *string) ([]App, error) {
 	return data.Apps.Nodes, nil
 }
 
+// GetAppNetworks returns the private network of each app the user can
+// access by app name, empty for its organization's default network
+func (client *Client) GetAppNetworks() (map[string]string, error) {
+	query := `
+		query {
+			apps(type: "container", first: 400) {
+				nodes {
+					name
+					network
+				}
+			}
+		}
+	`
+
+	data, err := client.Run(client.NewRequest(query))
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make(map[string]string, len(data.Apps.Nodes))
+	for _, app := range data.Apps.Nodes {
+		networks[app.Name] = app.Network
+	}
+
+	return networks, nil
+}
+
+// GetAppNames returns the names of the apps the user can access, cached
+// briefly for shell completion
+func (client *Client) GetAppNames() ([]string, error) {
+	query := `
+		query {
+			apps(type: "container", first: 400) {
+				nodes {
+					name
+				}
+			}
+		}
+	`
+
+	var names []string
+	err := cached(client.userCacheKey("app-names"), completionCacheTTL, &names, func() error {
+		data, err := client.Run(client.NewRequest(query))
+		if err != nil {
+			return err
+		}
+		names = make([]string, 0, len(data.Apps.Nodes))
+		for _, app := range data.Apps.Nodes {
+			names = append(names, app.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
 func (client *Client) GetAppID(appName string) (string, error) {
 	query := `
 		query ($appName: String!) {
@@ -148,6 +207,16 @@ func (client *Client) GetAppCompact(appName string) (*AppCompact, error) {
 }
 
 func (client *Client) CreateApp(name string, orgId string, preferredRegionCode *string) (*App, error) {
+	return client.CreateAppWithInput(CreateAppInput{
+		Name:            name,
+		OrganizationID:  orgId,
+		PreferredRegion: preferredRegionCode,
+	})
+}
+
+// CreateAppWithInput creates an app with all the options of CreateAppInput,
+// such as the private network to isolate it in
+func (client *Client) CreateAppWithInput(input CreateAppInput) (*App, error) {
 	query := `
 		mutation($input: CreateAppInput!) {
 			createApp(input: $input) {
@@ -171,18 +240,16 @@ func (client *Client) CreateApp(name string, orgId string, preferredRegionCode *
 
 	req := client.NewRequest(query)
 
-	req.Var("input", CreateAppInput{
-		Name:            name,
-		Runtime:         "FIRECRACKER",
-		OrganizationID:  orgId,
-		PreferredRegion: preferredRegionCode,
-	})
+	input.Runtime = "FIRECRACKER"
+	req.Var("input", input)
 
 	data, err := client.Run(req)
 	if err != nil {
 		return nil, err
 	}
 
+	forgetCached("app-names")
+
 	return &data.CreateApp.App, nil
 }
 
@@ -201,8 +268,13 @@ func (client *Client) DeleteApp(appName string) error {
 
 	req.Var("appId", appName)
 
-	_, err := client.Run(req)
-	return err
+	if _, err := client.Run(req); err != nil {
+		return err
+	}
+
+	forgetCached("app-names")
+
+	return nil
 }
 
 func (client *Client) MoveApp(appName string, orgID string) (*App, error) {
@@ -227,6 +299,88 @@ func (client *Client) MoveApp(appName string, orgID string) (*App, error) {
 	return &data.App, err
 }
 
+// RenameApp - Send GQL mutation to rename app
+func (client *Client) RenameApp(appName string, newName string) (*App, error) {
+	query := `
+		mutation ($input: RenameAppInput!) {
+			renameApp(input: $input) {
+				app {
+					id
+					name
+					hostname
+					appUrl
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+
+	req.Var("input", map[string]string{
+		"appId": appName,
+		"name":  newName,
+	})
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.RenameApp.App, nil
+}
+
+// GetAppMetadata - the key/value annotations in an app's metadata. It's a
+// query of its own so commands showing them still work where the API
+// predates app metadata.
+func (client *Client) GetAppMetadata(appName string) (map[string]string, error) {
+	query := `
+		query ($appName: String!) {
+			app(name: $appName) {
+				metadata
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.Metadata, nil
+}
+
+// SetAppMetadata - Send GQL mutation to merge key/value annotations into an
+// app's metadata. Keys set to an empty string are removed.
+func (client *Client) SetAppMetadata(appName string, metadata map[string]string) (map[string]string, error) {
+	query := `
+		mutation ($input: SetAppMetadataInput!) {
+			setAppMetadata(input: $input) {
+				app {
+					id
+					metadata
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+
+	req.Var("input", map[string]interface{}{
+		"appId":    appName,
+		"metadata": metadata,
+	})
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.SetAppMetadata.App.Metadata, nil
+}
+
 // SuspendApp - Send GQL mutation to suspend app
 func (client *Client) SuspendApp(appName string) (*App, error) {
 	query := `
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAppNetworksAskedForApart(t *testing.T) {
+	client := newTestClient(t, func(query string) (interface{}, error) {
+		switch {
+		case strings.Contains(query, "network"):
+			if strings.Contains(query, "createApp") || strings.Contains(query, "metadata") {
+				t.Errorf("expected networks to be asked for apart from the apps, got %s", query)
+			}
+			return decodeJSON(t, `{"apps": {"nodes": [{"name": "web", "network": "staging"}, {"name": "api", "network": ""}]}}`), nil
+		case strings.Contains(query, "createApp"):
+			return decodeJSON(t, `{"createApp": {"app": {"id": "1", "name": "web"}}}`), nil
+		}
+		return decodeJSON(t, `{"apps": {"nodes": [{"name": "web"}, {"name": "api"}]}}`), nil
+	})
+
+	if _, err := client.GetApps(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateAppWithInput(CreateAppInput{Name: "web", OrganizationID: "org"}); err != nil {
+		t.Fatal(err)
+	}
+
+	networks, err := client.GetAppNetworks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if networks["web"] != "staging" || networks["api"] != "" || len(networks) != 2 {
+		t.Errorf("got %v", networks)
+	}
+}
+
+func TestCreateAppInputNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		network *string
+		want    bool
+	}{
+		{name: "default network"},
+		{name: "named network", network: StringPointer("staging"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(CreateAppInput{Name: "web", OrganizationID: "org", Network: tt.network})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(data), `"network"`); got != tt.want {
+				t.Errorf("got %s, want network sent: %t", data, tt.want)
+			}
+		})
+	}
+}
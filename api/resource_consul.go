@@ -0,0 +1,43 @@
+package api
+
+// AttachConsul - provisions a Consul cluster URL for the app, for LiteFS
+// leases and other leader election. Attaching again returns the same URL.
+func (client *Client) AttachConsul(appID string) (*ConsulAttachment, error) {
+	query := `
+		mutation($input: AttachConsulInput!) {
+			attachConsul(input: $input) {
+				consulUrl
+				region
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{"appId": appID})
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.AttachConsul, nil
+}
+
+// DetachConsul - revokes the app's Consul URL, deleting its keys
+func (client *Client) DetachConsul(appID string) error {
+	query := `
+		mutation($input: DetachConsulInput!) {
+			detachConsul(input: $input) {
+				app {
+					id
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{"appId": appID})
+
+	_, err := client.Run(req)
+	return err
+}
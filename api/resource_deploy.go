@@ -36,7 +36,10 @@ func (client *Client) DeployImage(input DeployImageInput) (*Release, *ReleaseCom
 		return nil, nil, err
 	}
 
-	return &data.DeployImage.Release, data.DeployImage.ReleaseCommand, nil
+	release := data.DeployImage.Release
+	release.Message, release.Labels = input.Message, input.Labels
+
+	return &release, data.DeployImage.ReleaseCommand, nil
 }
 
 func (c *Client) GetDeploymentStatus(appName string, deploymentID string) (*DeploymentStatus, error) {
@@ -118,3 +121,67 @@ func (c *Client) GetReleaseCommand(ctx context.Context, id string) (*ReleaseComm
 
 	return data.ReleaseCommandNode, nil
 }
+
+// PrewarmImage - starts pulling the image's layers onto hosts in each of the
+// app's regions
+func (c *Client) PrewarmImage(appName string, image string) (*ImagePrewarm, error) {
+	query := `
+		mutation($input: PrewarmImageInput!) {
+			prewarmImage(input: $input) {
+				imagePrewarm {
+					id
+					image
+					regions {
+						region
+						status
+						bytesPulled
+						bytesTotal
+						error
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{"appId": appName, "image": image})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.PrewarmImage.ImagePrewarm, nil
+}
+
+func (c *Client) GetImagePrewarm(ctx context.Context, id string) (*ImagePrewarm, error) {
+	query := `
+		query ($id: ID!) {
+			imagePrewarmNode: node(id: $id) {
+				... on ImagePrewarm {
+					id
+					image
+					regions {
+						region
+						status
+						bytesPulled
+						bytesTotal
+						error
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("id", id)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.ImagePrewarmNode, nil
+}
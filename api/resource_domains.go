@@ -156,3 +156,33 @@ func (c *Client) CreateAndRegisterDomain(organizationID string, name string) (*D
 
 	return data.CreateAndRegisterDomain.Domain, nil
 }
+
+func (c *Client) SetDomainAutoRenew(domainID string, autoRenew bool) (*Domain, error) {
+	query := `
+		mutation($input: SetDomainAutoRenewInput!) {
+			setDomainAutoRenew(input: $input) {
+				domain {
+					id
+					name
+					registrationStatus
+					autoRenew
+					expiresAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]interface{}{
+		"domainId":  domainID,
+		"autoRenew": autoRenew,
+	})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.SetDomainAutoRenew.Domain, nil
+}
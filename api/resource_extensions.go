@@ -0,0 +1,63 @@
+package api
+
+const extensionFields = `
+	id
+	name
+	provider
+	status
+	dashboardUrl
+	createdAt
+	app {
+		name
+	}
+`
+
+// ProvisionExtension - creates an extension with a partner for an app. The
+// environment returned, the variables the app needs to use it, is only
+// ever returned here.
+func (client *Client) ProvisionExtension(input ProvisionExtensionInput) (*Extension, error) {
+	query := `
+		mutation($input: ProvisionExtensionInput!) {
+			provisionExtension(input: $input) {
+				extension {
+					` + extensionFields + `
+					environment
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.ProvisionExtension.Extension, nil
+}
+
+func (client *Client) GetExtensions(appName string) ([]Extension, error) {
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				extensions {
+					nodes {
+						` + extensionFields + `
+					}
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.Extensions.Nodes, nil
+}
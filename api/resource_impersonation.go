@@ -0,0 +1,55 @@
+package api
+
+// StartImpersonation - Starts acting as an org member. Requests made with the
+// session are evaluated with the member's role, without changing anything, and
+// recorded in the org's audit log.
+func (c *Client) StartImpersonation(input StartImpersonationInput) (*ImpersonationSession, error) {
+	query := `
+		mutation($input: StartImpersonationInput!) {
+			startImpersonation(input: $input) {
+				session {
+					id
+					role
+					expiresAt
+					user {
+						id
+						email
+						name
+					}
+					organization {
+						id
+						slug
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.StartImpersonation.Session, nil
+}
+
+func (c *Client) EndImpersonation(sessionID string) error {
+	query := `
+		mutation($input: EndImpersonationInput!) {
+			endImpersonation(input: $input) {
+				session {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{"sessionId": sessionID})
+
+	_, err := c.Run(req)
+	return err
+}
@@ -9,6 +9,7 @@ func (c *Client) GetIPAddresses(appName string) ([]IPAddress, error) {
 						id
 						address
 						type
+						region
 						createdAt
 					}
 				}
@@ -35,6 +36,7 @@ func (c *Client) FindIPAddress(appName string, address string) (*IPAddress, erro
 					id
 					address
 					type
+					region
 					createdAt
 				}
 			}
@@ -54,7 +56,9 @@ func (c *Client) FindIPAddress(appName string, address string) (*IPAddress, erro
 	return data.App.IPAddress, nil
 }
 
-func (c *Client) AllocateIPAddress(appName string, addrType string) (*IPAddress, error) {
+// AllocateIPAddress allocates an address of addrType, v4, v6 or shared_v4, to
+// the app. An empty region allocates one announced from every region.
+func (c *Client) AllocateIPAddress(appName string, addrType string, region string) (*IPAddress, error) {
 	query := `
 		mutation($input: AllocateIPAddressInput!) {
 			allocateIpAddress(input: $input) {
@@ -62,6 +66,7 @@ func (c *Client) AllocateIPAddress(appName string, addrType string) (*IPAddress,
 					id
 					address
 					type
+					region
 					createdAt
 				}
 			}
@@ -70,7 +75,7 @@ func (c *Client) AllocateIPAddress(appName string, addrType string) (*IPAddress,
 
 	req := c.NewRequest(query)
 
-	req.Var("input", AllocateIPAddressInput{AppID: appName, Type: addrType})
+	req.Var("input", AllocateIPAddressInput{AppID: appName, Type: addrType, Region: region})
 
 	data, err := c.Run(req)
 	if err != nil {
@@ -100,3 +105,33 @@ func (c *Client) ReleaseIPAddress(id string) error {
 
 	return nil
 }
+
+// MoveIPAddress moves a dedicated address to another app in the same
+// organization. It's routed to the target before it stops being routed to
+// the source, so connections aren't refused while it moves.
+func (c *Client) MoveIPAddress(id string, appName string) (*IPAddress, error) {
+	query := `
+		mutation($input: MoveIPAddressInput!) {
+			moveIpAddress(input: $input) {
+				ipAddress {
+					id
+					address
+					type
+					region
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", MoveIPAddressInput{IPAddressID: id, AppID: appName})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.MoveIPAddress.IPAddress, nil
+}
@@ -0,0 +1,78 @@
+package api
+
+func (c *Client) CreateLogShipper(input CreateLogShipperInput) (*LogShipper, error) {
+	query := `
+		mutation($input: CreateLogShipperInput!) {
+			createLogShipper(input: $input) {
+				logShipper {
+					id
+					name
+					destination
+					apps
+					status
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateLogShipper.LogShipper, nil
+}
+
+func (c *Client) GetLogShippers(orgSlug string) ([]LogShipper, error) {
+	query := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				logShippers {
+					nodes {
+						id
+						name
+						destination
+						apps
+						status
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("slug", orgSlug)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.Organization == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Organization.LogShippers.Nodes, nil
+}
+
+func (c *Client) DeleteLogShipper(id string) error {
+	query := `
+		mutation($input: DeleteLogShipperInput!) {
+			deleteLogShipper(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{"logShipperId": id})
+
+	_, err := c.Run(req)
+	return err
+}
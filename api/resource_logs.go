@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type getLogsResponse struct {
@@ -28,7 +29,22 @@ func (c *Client) GetAppLogs(appName string, nextToken string, region string, ins
 		data.Set("region", region)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/apps/%s/logs?%s", baseURL, appName, data.Encode())
+	return c.getAppLogs(appName, data)
+}
+
+// GetAppLogsBetween - a page of the app's logs from start up to end, oldest
+// first. Keep passing the returned token until a page comes back empty.
+func (c *Client) GetAppLogsBetween(appName string, start time.Time, end time.Time, nextToken string) ([]LogEntry, string, error) {
+	data := url.Values{}
+	data.Set("next_token", nextToken)
+	data.Set("start_time", start.UTC().Format(time.RFC3339Nano))
+	data.Set("end_time", end.UTC().Format(time.RFC3339Nano))
+
+	return c.getAppLogs(appName, data)
+}
+
+func (c *Client) getAppLogs(appName string, data url.Values) ([]LogEntry, string, error) {
+	url := fmt.Sprintf("%s/api/v1/apps/%s/logs?%s", c.baseURL, appName, data.Encode())
 	entries := []LogEntry{}
 
 	req, err := http.NewRequest("GET", url, nil)
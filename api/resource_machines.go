@@ -0,0 +1,334 @@
+package api
+
+import "github.com/superfly/flyctl/terminal"
+
+// Machine states reported by the machines API
+const (
+	MachineStateCreated    = "created"
+	MachineStateStarting   = "starting"
+	MachineStateStarted    = "started"
+	MachineStateStopping   = "stopping"
+	MachineStateStopped    = "stopped"
+	MachineStateReplacing  = "replacing"
+	MachineStateDestroying = "destroying"
+	MachineStateDestroyed  = "destroyed"
+)
+
+func (c *Client) GetMachines(appName string) ([]Machine, error) {
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				machines {
+					nodes {
+						id
+						name
+						state
+						region
+						createdAt
+						updatedAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	machines := data.App.Machines.Nodes
+	c.addMachineImages(appName, machines)
+	c.addMachineIPs(appName, machines)
+
+	return machines, nil
+}
+
+// addMachineImages fills in the image and GPUs of machines. They're asked for
+// apart from the machines' other fields, which can still be read where the
+// API doesn't report them.
+func (c *Client) addMachineImages(appName string, machines []Machine) {
+	if len(machines) == 0 {
+		return
+	}
+
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				machines {
+					nodes {
+						id
+						image
+						gpuKind
+						gpus
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		terminal.Debugf("error fetching machine images: %v\n", err)
+		return
+	}
+
+	images := map[string]Machine{}
+	for _, m := range data.App.Machines.Nodes {
+		images[m.ID] = m
+	}
+	for i := range machines {
+		if m, ok := images[machines[i].ID]; ok {
+			machines[i].Image, machines[i].GPUKind, machines[i].GPUs = m.Image, m.GPUKind, m.GPUs
+		}
+	}
+}
+
+// addMachineIPs fills in the addresses of machines, like addMachineImages
+func (c *Client) addMachineIPs(appName string, machines []Machine) {
+	if len(machines) == 0 {
+		return
+	}
+
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				machines {
+					nodes {
+						id
+						ips {
+							nodes {
+								family
+								kind
+								ip
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		terminal.Debugf("error fetching machine IPs: %v\n", err)
+		return
+	}
+
+	ips := map[string]Machine{}
+	for _, m := range data.App.Machines.Nodes {
+		ips[m.ID] = m
+	}
+	for i := range machines {
+		if m, ok := ips[machines[i].ID]; ok {
+			machines[i].IPs = m.IPs
+		}
+	}
+}
+
+// GetMachine returns the app's machine with the given ID, or nil once it has
+// been destroyed and removed
+func (c *Client) GetMachine(appName string, machineID string) (*Machine, error) {
+	query := `
+		query($appName: String!, $machineId: String!) {
+			app(name: $appName) {
+				machine(id: $machineId) {
+					id
+					name
+					state
+					region
+					createdAt
+					updatedAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("machineId", machineID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	machine := data.App.Machine
+	if machine != nil {
+		c.addMachineImage(appName, machine)
+	}
+
+	return machine, nil
+}
+
+// GetMachineServices - the ports a machine exposes and the version of its
+// config they belong to, nil when the machine doesn't exist
+func (c *Client) GetMachineServices(appName string, machineID string) (*Machine, error) {
+	query := `
+		query($appName: String!, $machineId: String!) {
+			app(name: $appName) {
+				machine(id: $machineId) {
+					id
+					version
+					services {
+						protocol
+						internalPort
+						ports {
+							port
+							handlers
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("machineId", machineID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.Machine, nil
+}
+
+// addMachineImage fills in the image and GPUs of a machine, like
+// addMachineImages
+func (c *Client) addMachineImage(appName string, machine *Machine) {
+	query := `
+		query($appName: String!, $machineId: String!) {
+			app(name: $appName) {
+				machine(id: $machineId) {
+					image
+					gpuKind
+					gpus
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("machineId", machine.ID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		terminal.Debugf("error fetching machine image: %v\n", err)
+		return
+	}
+
+	if m := data.App.Machine; m != nil {
+		machine.Image, machine.GPUKind, machine.GPUs = m.Image, m.GPUKind, m.GPUs
+	}
+}
+
+func (c *Client) LaunchMachine(input LaunchMachineInput) (*Machine, error) {
+	query := `
+		mutation($input: LaunchMachineInput!) {
+			launchMachine(input: $input) {
+				machine {
+					id
+					name
+					state
+					region
+					image
+					gpuKind
+					gpus
+					createdAt
+					updatedAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.LaunchMachine.Machine, nil
+}
+
+// CloneMachine creates a machine with the same configuration as another,
+// optionally in another region or with different GPUs
+func (c *Client) CloneMachine(input CloneMachineInput) (*Machine, error) {
+	query := `
+		mutation($input: CloneMachineInput!) {
+			cloneMachine(input: $input) {
+				machine {
+					id
+					name
+					state
+					region
+					image
+					gpuKind
+					gpus
+					createdAt
+					updatedAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CloneMachine.Machine, nil
+}
+
+// UpdateMachineServices replaces the services a machine exposes without
+// recreating it
+func (c *Client) UpdateMachineServices(input UpdateMachineServicesInput) (*Machine, error) {
+	query := `
+		mutation($input: UpdateMachineServicesInput!) {
+			updateMachineServices(input: $input) {
+				machine {
+					id
+					name
+					state
+					region
+					image
+					version
+					services {
+						protocol
+						internalPort
+						ports {
+							port
+							handlers
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.UpdateMachineServices.Machine, nil
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetMachinesImages(t *testing.T) {
+	machines := `{"app": {"machines": {"nodes": [{"id": "m1", "name": "web", "state": "started"}, {"id": "m2", "name": "worker", "state": "stopped"}]}}}`
+	images := `{"app": {"machines": {"nodes": [{"id": "m2", "image": "flyio/worker:1", "gpuKind": "a100", "gpus": 2}]}}}`
+
+	tests := []struct {
+		name      string
+		imagesErr error
+		want      []Machine
+	}{
+		{
+			name: "images reported",
+			want: []Machine{
+				{ID: "m1", Name: "web", State: "started"},
+				{ID: "m2", Name: "worker", State: "stopped", Image: "flyio/worker:1", GPUKind: "a100", GPUs: 2},
+			},
+		},
+		{
+			name:      "images not reported",
+			imagesErr: errors.New("Field 'gpuKind' doesn't exist on type 'Machine'"),
+			want: []Machine{
+				{ID: "m1", Name: "web", State: "started"},
+				{ID: "m2", Name: "worker", State: "stopped"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				if strings.Contains(query, "gpuKind") {
+					if strings.Contains(query, " state ") {
+						t.Errorf("expected images to be asked for apart from the machines, got %s", query)
+					}
+					if tt.imagesErr != nil {
+						return nil, tt.imagesErr
+					}
+					return decodeJSON(t, images), nil
+				}
+				if strings.Contains(query, "ips") {
+					return decodeJSON(t, `{"app": {"machines": {"nodes": []}}}`), nil
+				}
+				return decodeJSON(t, machines), nil
+			})
+
+			got, err := client.GetMachines("myapp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d machines, want %d", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				if got[i].ID != tt.want[i].ID || got[i].Image != tt.want[i].Image || got[i].GPUKind != tt.want[i].GPUKind || got[i].GPUs != tt.want[i].GPUs {
+					t.Errorf("got %+v, want %+v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetMachineImage(t *testing.T) {
+	tests := []struct {
+		name      string
+		imagesErr error
+		wantImage string
+		wantGPUs  int
+	}{
+		{name: "image reported", wantImage: "flyio/web:1", wantGPUs: 1},
+		{name: "image not reported", imagesErr: errors.New("Field 'gpuKind' doesn't exist on type 'Machine'")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				if strings.Contains(query, "gpuKind") {
+					if tt.imagesErr != nil {
+						return nil, tt.imagesErr
+					}
+					return decodeJSON(t, `{"app": {"machine": {"image": "flyio/web:1", "gpuKind": "a100", "gpus": 1}}}`), nil
+				}
+				return decodeJSON(t, `{"app": {"machine": {"id": "m1", "name": "web", "state": "started"}}}`), nil
+			})
+
+			got, err := client.GetMachine("myapp", "m1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != "m1" || got.State != "started" || got.Image != tt.wantImage || got.GPUs != tt.wantGPUs {
+				t.Errorf("got %+v", got)
+			}
+		})
+	}
+}
+
+func TestGetMachineServices(t *testing.T) {
+	client := newTestClient(t, func(query string) (interface{}, error) {
+		if strings.Contains(query, "services") {
+			if strings.Contains(query, " state ") {
+				t.Errorf("expected services to be asked for apart from the machine, got %s", query)
+			}
+			return decodeJSON(t, `{"app": {"machine": {"id": "m1", "version": "v2", "services": [{"protocol": "tcp", "internalPort": 8080, "ports": [{"port": 443, "handlers": ["tls", "http"]}]}]}}}`), nil
+		}
+		if strings.Contains(query, "version") {
+			t.Errorf("expected the machine's version to be asked for with its services, got %s", query)
+		}
+		return decodeJSON(t, `{"app": {"machine": {"id": "m1", "name": "web", "state": "started"}}}`), nil
+	})
+
+	if _, err := client.GetMachine("myapp", "m1"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.GetMachineServices("myapp", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "v2" || len(got.Services) != 1 || got.Services[0].InternalPort != 8080 || got.Services[0].Ports[0].Port != 443 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestGetMachinesIPs(t *testing.T) {
+	machines := `{"app": {"machines": {"nodes": [{"id": "m1", "name": "web", "state": "started"}, {"id": "m2", "name": "worker", "state": "stopped"}]}}}`
+	ips := `{"app": {"machines": {"nodes": [{"id": "m1", "ips": {"nodes": [{"family": "v6", "kind": "privatenet", "ip": "fdaa::3"}]}}]}}}`
+
+	tests := []struct {
+		name   string
+		ipsErr error
+		want   map[string][]string
+	}{
+		{name: "IPs reported", want: map[string][]string{"m1": {"fdaa::3"}, "m2": nil}},
+		{name: "IPs not reported", ipsErr: errors.New("Field 'ips' doesn't exist on type 'Machine'"), want: map[string][]string{"m1": nil, "m2": nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				switch {
+				case strings.Contains(query, "ips"):
+					if strings.Contains(query, " state ") || strings.Contains(query, "gpuKind") {
+						t.Errorf("expected IPs to be asked for apart from the machines, got %s", query)
+					}
+					if tt.ipsErr != nil {
+						return nil, tt.ipsErr
+					}
+					return decodeJSON(t, ips), nil
+				case strings.Contains(query, "gpuKind"):
+					return decodeJSON(t, `{"app": {"machines": {"nodes": []}}}`), nil
+				}
+				return decodeJSON(t, machines), nil
+			})
+
+			got, err := client.GetMachines("myapp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("got %d machines, want 2", len(got))
+			}
+			for _, m := range got {
+				var addrs []string
+				for _, ip := range m.IPs.Nodes {
+					addrs = append(addrs, ip.IP)
+				}
+				if !reflect.DeepEqual(addrs, tt.want[m.ID]) {
+					t.Errorf("machine %s got IPs %v, want %v", m.ID, addrs, tt.want[m.ID])
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "github.com/superfly/flyctl/terminal"
+
 func (c *Client) GetAppStatus(appName string, showCompleted bool) (*AppStatus, error) {
 	query := `
 		query($appName: String!, $showCompleted: Boolean!) {
@@ -30,6 +32,7 @@ func (c *Client) GetAppStatus(appName string, showCompleted bool) (*AppStatus, e
 					idShort
 					version
 					latestVersion
+					taskName
 					status
 					desiredStatus
 					totalCheckCount
@@ -48,6 +51,11 @@ func (c *Client) GetAppStatus(appName string, showCompleted bool) (*AppStatus, e
 						output
 						name
 					}
+					events {
+						timestamp
+						type
+						message
+					}
 				}
 			}
 		}
@@ -62,7 +70,16 @@ func (c *Client) GetAppStatus(appName string, showCompleted bool) (*AppStatus, e
 		return nil, err
 	}
 
-	return &data.AppStatus, nil
+	status := data.AppStatus
+
+	autostop, err := c.AppAutostopConfig(appName)
+	if err != nil {
+		terminal.Debugf("error fetching autostop config: %v\n", err)
+	} else {
+		status.Autostop = autostop
+	}
+
+	return &status, nil
 }
 
 func (c *Client) GetAllocationStatus(appName string, allocID string, logLimit int) (*AllocationStatus, error) {
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetAppStatusAutostop(t *testing.T) {
+	tests := []struct {
+		name        string
+		autostopErr error
+		want        *AutostopConfig
+	}{
+		{name: "autostop reported", want: &AutostopConfig{AutoStop: true, AutoStart: true, MinMachinesRunning: 1}},
+		{name: "autostop not reported", autostopErr: errors.New("Field 'autostop' doesn't exist on type 'App'")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				if strings.Contains(query, "autostop") {
+					if strings.Contains(query, "allocations") {
+						t.Errorf("expected autostop to be asked for apart from the status, got %s", query)
+					}
+					if tt.autostopErr != nil {
+						return nil, tt.autostopErr
+					}
+					return decodeJSON(t, `{"app": {"autostop": {"autoStop": true, "autoStart": true, "minMachinesRunning": 1}}}`), nil
+				}
+				return decodeJSON(t, `{"appstatus": {"name": "myapp", "deployed": true, "allocations": [{"id": "a1", "status": "running"}]}}`), nil
+			})
+
+			status, err := client.GetAppStatus("myapp", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Name != "myapp" || len(status.Allocations) != 1 {
+				t.Errorf("got %+v", status)
+			}
+			if (status.Autostop == nil) != (tt.want == nil) || (tt.want != nil && *status.Autostop != *tt.want) {
+				t.Errorf("got autostop %+v, want %+v", status.Autostop, tt.want)
+			}
+		})
+	}
+}
@@ -22,16 +22,26 @@ func (client *Client) GetOrganizations(typeFilter *OrganizationType) ([]Organiza
 	`
 
 	req := client.NewRequest(q)
+	key := "organizations"
 	if typeFilter != nil {
 		req.Var("orgType", *typeFilter)
+		key += "-" + string(*typeFilter)
 	}
 
-	data, err := client.Run(req)
+	var orgs []Organization
+	err := cached(client.userCacheKey(key), organizationsCacheTTL, &orgs, func() error {
+		data, err := client.Run(req)
+		if err != nil {
+			return err
+		}
+		orgs = data.Organizations.Nodes
+		return nil
+	})
 	if err != nil {
 		return []Organization{}, err
 	}
 
-	return data.Organizations.Nodes, nil
+	return orgs, nil
 }
 
 func (client *Client) FindOrganizationBySlug(slug string) (*Organization, error) {
@@ -124,6 +134,60 @@ func (client *Client) GetOrganizationBySlug(slug string) (*OrganizationDetails,
 	return &data.OrganizationDetails, nil
 }
 
+// GetOrganizationApps - Lists the apps of an organization, a page at a time
+func (client *Client) GetOrganizationApps(slug string) ([]App, error) {
+	query := `query($slug: String!, $after: String) {
+		organizationdetails: organization(slug: $slug) {
+			id
+			slug
+			apps(type: "container", first: 400, after: $after) {
+				nodes {
+					id
+					name
+					deployed
+					hostname
+					organization {
+						slug
+					}
+					currentRelease {
+						createdAt
+					}
+					status
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	}
+	`
+
+	apps := []App{}
+	after := ""
+
+	for {
+		req := client.NewRequest(query)
+		req.Var("slug", slug)
+		if after != "" {
+			req.Var("after", after)
+		}
+
+		data, err := client.Run(req)
+		if err != nil {
+			return nil, err
+		}
+
+		page := data.OrganizationDetails.Apps
+		apps = append(apps, page.Nodes...)
+
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			return apps, nil
+		}
+		after = page.PageInfo.EndCursor
+	}
+}
+
 func (c *Client) CreateOrganization(organizationname string) (*Organization, error) {
 	query := `
 		mutation($input: CreateOrganizationInput!) {
@@ -150,6 +214,8 @@ func (c *Client) CreateOrganization(organizationname string) (*Organization, err
 		return nil, err
 	}
 
+	forgetCached("organizations")
+
 	return &data.CreateOrganization.Organization, nil
 }
 
@@ -174,9 +240,67 @@ func (c *Client) DeleteOrganization(id string) (deletedid string, err error) {
 		return "", err
 	}
 
+	forgetCached("organizations")
+
 	return data.DeleteOrganization.DeletedOrganizationId, nil
 }
 
+func (c *Client) LeaveOrganization(id string) error {
+	query := `
+	mutation($input: LeaveOrganizationInput!) {
+		leaveOrganization(input: $input) {
+			organization {
+				id
+			}
+		}
+	}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{
+		"organizationId": id,
+	})
+
+	if _, err := c.Run(req); err != nil {
+		return err
+	}
+
+	forgetCached("organizations")
+
+	return nil
+}
+
+func (c *Client) TransferOrganizationOwnership(id string, userID string) (*Organization, error) {
+	query := `
+	mutation($input: TransferOrganizationOwnershipInput!) {
+		transferOrganizationOwnership(input: $input) {
+			organization {
+				id
+				slug
+				name
+				type
+				viewerRole
+			}
+		}
+	}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{
+		"organizationId": id,
+		"userId":         userID,
+	})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.TransferOrganizationOwnership.Organization, nil
+}
+
 func (c *Client) CreateOrganizationInvite(id, email string) (*Invitation, error) {
 	query := `
 	mutation($input: CreateOrganizationInvitationInput!){
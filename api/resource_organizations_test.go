@@ -0,0 +1,37 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetOrganizationAppsPages(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(query string) (interface{}, error) {
+		if strings.Contains(query, "metadata") {
+			t.Errorf("expected metadata to be left out of the organization's apps, got %s", query)
+		}
+
+		calls++
+		if calls == 1 {
+			return decodeJSON(t, `{"organizationdetails": {"slug": "acme", "apps": {"nodes": [{"name": "web"}, {"name": "worker"}], "pageInfo": {"hasNextPage": true, "endCursor": "c2"}}}}`), nil
+		}
+		return decodeJSON(t, `{"organizationdetails": {"slug": "acme", "apps": {"nodes": [{"name": "db"}], "pageInfo": {"hasNextPage": false, "endCursor": "c3"}}}}`), nil
+	})
+
+	apps, err := client.GetOrganizationApps("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected 2 pages to be fetched, got %d", calls)
+	}
+	var names []string
+	for _, app := range apps {
+		names = append(names, app.Name)
+	}
+	if got := strings.Join(names, ","); got != "web,worker,db" {
+		t.Errorf("expected web,worker,db, got %s", got)
+	}
+}
@@ -1,5 +1,8 @@
 package api
 
+// PlatformRegions - Lists the regions along with the one the API request
+// was routed through, the nearest to the user. The regions are cached, the
+// request region is looked up every time as it depends on where the user is.
 func (c *Client) PlatformRegions() ([]Region, *Region, error) {
 	query := `
 		query {
@@ -8,31 +11,84 @@ func (c *Client) PlatformRegions() ([]Region, *Region, error) {
 				regions {
 					name
 					code
+					latitude
+					longitude
 					gatewayAvailable
 				}
 			}
 		}
 	`
 
-	req := c.NewRequest(query)
-
-	data, err := c.Run(req)
+	var regions []Region
+	var requestRegionCode string
+	fetched := false
+	err := cached("platform-regions", platformCacheTTL, &regions, func() error {
+		data, err := c.Run(c.NewRequest(query))
+		if err != nil {
+			return err
+		}
+		regions = data.Platform.Regions
+		requestRegionCode = data.Platform.RequestRegion
+		fetched = true
+		return nil
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if !fetched {
+		data, err := c.Run(c.NewRequest(`query { platform { requestRegion } }`))
+		if err != nil {
+			return nil, nil, err
+		}
+		requestRegionCode = data.Platform.RequestRegion
+	}
+
 	var requestRegion *Region
 
-	if data.Platform.RequestRegion != "" {
-		for _, region := range data.Platform.Regions {
-			if region.Code == data.Platform.RequestRegion {
+	if requestRegionCode != "" {
+		for _, region := range regions {
+			if region.Code == requestRegionCode {
 				requestRegion = &region
 				break
 			}
 		}
 	}
 
-	return data.Platform.Regions, requestRegion, nil
+	return regions, requestRegion, nil
+}
+
+// PlatformRegionList - Lists the regions, cached, for callers that don't
+// need the request region
+func (c *Client) PlatformRegionList() ([]Region, error) {
+	query := `
+		query {
+			platform {
+				regions {
+					name
+					code
+					latitude
+					longitude
+					gatewayAvailable
+				}
+			}
+		}
+	`
+
+	var regions []Region
+	err := cached("platform-regions", platformCacheTTL, &regions, func() error {
+		data, err := c.Run(c.NewRequest(query))
+		if err != nil {
+			return err
+		}
+		regions = data.Platform.Regions
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return regions, nil
 }
 
 func (c *Client) PlatformRegionsAll() ([]Region, error) {
@@ -60,6 +116,46 @@ func (c *Client) PlatformRegionsAll() ([]Region, error) {
 	return data.Platform.Regions, nil
 }
 
+// PlatformRegionCapabilities - Lists regions along with what each supports
+func (c *Client) PlatformRegionCapabilities() ([]Region, error) {
+	query := `
+		query {
+			platform {
+				regions {
+					name
+					code
+					gatewayAvailable
+					capabilities {
+						volumes
+						dedicatedIps
+						machines
+						gpus
+						gpuKinds
+						capacity
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	var regions []Region
+	err := cached("platform-region-capabilities", regionCapacityCacheTTL, &regions, func() error {
+		data, err := c.Run(req)
+		if err != nil {
+			return err
+		}
+		regions = data.Platform.Regions
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
 func (c *Client) PlatformVMSizes() ([]VMSize, error) {
 	query := `
 		query {
@@ -79,10 +175,51 @@ func (c *Client) PlatformVMSizes() ([]VMSize, error) {
 
 	req := c.NewRequest(query)
 
-	data, err := c.Run(req)
+	var sizes []VMSize
+	err := cached("platform-vm-sizes", platformCacheTTL, &sizes, func() error {
+		data, err := c.Run(req)
+		if err != nil {
+			return err
+		}
+		sizes = data.Platform.VMSizes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sizes, nil
+}
+
+// PlatformGPUKinds - Lists the GPUs that can be attached to VMs
+func (c *Client) PlatformGPUKinds() ([]GPUKind, error) {
+	query := `
+		query {
+			platform {
+				gpuKinds {
+					name
+					memoryGb
+					priceMonth
+					priceSecond
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	var kinds []GPUKind
+	err := cached("platform-gpu-kinds", platformCacheTTL, &kinds, func() error {
+		data, err := c.Run(req)
+		if err != nil {
+			return err
+		}
+		kinds = data.Platform.GPUKinds
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return data.Platform.VMSizes, nil
+	return kinds, nil
 }
@@ -212,3 +212,119 @@ func (client *Client) ListPostgresUsers(appName string) ([]PostgresClusterUser,
 
 // 	return *data.App.PostgresAppRole.Users, nil
 // }
+
+// ListPostgresMembers - the cluster's instances, with which is the leader
+// and how far each replica lags behind it
+func (client *Client) ListPostgresMembers(appName string) ([]PostgresClusterMember, error) {
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				postgresAppRole: role {
+					name
+					... on PostgresClusterAppRole {
+						members {
+							id
+							region
+							role
+							healthy
+							lagBytes
+							privateIp
+							volumeId
+						}
+					}
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	role := data.App.PostgresAppRole
+	if role == nil || role.Members == nil {
+		return nil, ErrNotFound
+	}
+
+	return *role.Members, nil
+}
+
+// FailoverPostgresCluster - promotes the replica memberID to leader,
+// demoting the current leader to a replica
+func (client *Client) FailoverPostgresCluster(appName string, memberID string) error {
+	query := `
+		mutation($input: FailoverPostgresClusterInput!) {
+			failoverPostgresCluster(input: $input) {
+				clientMutationId
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{
+		"appId":          appName,
+		"targetMemberId": memberID,
+	})
+
+	_, err := client.Run(req)
+	return err
+}
+
+// AddPostgresReplica - adds a read replica in region, with a volume of its
+// own. A zero volume size matches the leader's.
+func (client *Client) AddPostgresReplica(appName string, region string, volumeSizeGB int) (*PostgresClusterMember, error) {
+	query := `
+		mutation($input: AddPostgresReplicaInput!) {
+			addPostgresReplica(input: $input) {
+				member {
+					id
+					region
+					role
+					healthy
+					lagBytes
+					privateIp
+					volumeId
+				}
+			}
+		}
+		`
+
+	input := AddPostgresReplicaInput{AppID: appName, Region: region}
+	if volumeSizeGB > 0 {
+		input.VolumeSizeGB = IntPointer(volumeSizeGB)
+	}
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.AddPostgresReplica.Member, nil
+}
+
+// RemovePostgresReplica - stops the replica memberID and deletes its volume
+func (client *Client) RemovePostgresReplica(appName string, memberID string) error {
+	query := `
+		mutation($input: RemovePostgresReplicaInput!) {
+			removePostgresReplica(input: $input) {
+				clientMutationId
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{
+		"appId":    appName,
+		"memberId": memberID,
+	})
+
+	_, err := client.Run(req)
+	return err
+}
@@ -0,0 +1,159 @@
+package api
+
+const postgresBackupFields = `
+	id
+	status
+	trigger
+	volumeId
+	volumeSnapshotId
+	size
+	walStart
+	startedAt
+	completedAt
+`
+
+// GetPostgresBackups - a cluster's base backups, newest first, and the state
+// of its WAL archiving
+func (client *Client) GetPostgresBackups(appName string) ([]PostgresBackup, *PostgresWALStatus, error) {
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				postgresAppRole: role {
+					name
+					... on PostgresClusterAppRole {
+						backups {
+							` + postgresBackupFields + `
+						}
+						walStatus {
+							archiving
+							lastArchivedWal
+							lastArchivedAt
+							lastFailedWal
+							lastFailedAt
+						}
+					}
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	role := data.App.PostgresAppRole
+	if role == nil || role.Backups == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	return *role.Backups, role.WALStatus, nil
+}
+
+func (client *Client) GetPostgresBackup(id string) (*PostgresBackup, error) {
+	query := `
+		query($id: ID!) {
+			postgresBackup: node(id: $id) {
+				... on PostgresBackup {
+					` + postgresBackupFields + `
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("id", id)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.PostgresBackup == nil || data.PostgresBackup.ID == "" {
+		return nil, ErrNotFound
+	}
+
+	return data.PostgresBackup, nil
+}
+
+// StartPostgresBackup - puts the cluster's leader in backup mode. The
+// backup's VolumeID is the leader's volume, to be snapshotted before the
+// backup is finished with FinishPostgresBackup.
+func (client *Client) StartPostgresBackup(appName string) (*PostgresBackup, error) {
+	query := `
+		mutation($input: StartPostgresBackupInput!) {
+			startPostgresBackup(input: $input) {
+				backup {
+					` + postgresBackupFields + `
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{"appId": appName})
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.StartPostgresBackup.Backup, nil
+}
+
+// FinishPostgresBackup - takes the leader out of backup mode, recording the
+// snapshot of its volume, or failing the backup with failure when the
+// snapshot couldn't be taken
+func (client *Client) FinishPostgresBackup(id string, snapshotID string, failure string) (*PostgresBackup, error) {
+	query := `
+		mutation($input: FinishPostgresBackupInput!) {
+			finishPostgresBackup(input: $input) {
+				backup {
+					` + postgresBackupFields + `
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", FinishPostgresBackupInput{
+		BackupID:         id,
+		VolumeSnapshotID: snapshotID,
+		Error:            failure,
+	})
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.FinishPostgresBackup.Backup, nil
+}
+
+// RestorePostgresCluster - creates a cluster from a base backup of another,
+// replaying its archived WAL up to the target time
+func (client *Client) RestorePostgresCluster(input RestorePostgresClusterInput) (*CreatePostgresClusterPayload, error) {
+	query := `
+		mutation($input: RestorePostgresClusterInput!) {
+			restorePostgresCluster(input: $input) {
+				app {
+					name
+				}
+				username
+				password
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.RestorePostgresCluster, nil
+}
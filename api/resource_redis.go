@@ -0,0 +1,109 @@
+package api
+
+const redisInstanceFields = `
+	id
+	name
+	status
+	plan
+	primaryRegion
+	readRegions
+	privateUrl
+	evictionEnabled
+	createdAt
+	organization {
+		id
+		slug
+	}
+`
+
+func (client *Client) CreateRedisInstance(input CreateRedisInstanceInput) (*RedisInstance, error) {
+	query := `
+		mutation($input: CreateRedisInstanceInput!) {
+			createRedisInstance(input: $input) {
+				redisInstance {
+					` + redisInstanceFields + `
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateRedisInstance.RedisInstance, nil
+}
+
+func (client *Client) GetRedisInstances(orgSlug string) ([]RedisInstance, error) {
+	query := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				redisInstances {
+					nodes {
+						` + redisInstanceFields + `
+					}
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("slug", orgSlug)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.Organization == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Organization.RedisInstances.Nodes, nil
+}
+
+// GetRedisInstance - the Redis instance named name, ErrNotFound when there's
+// none the user can see
+func (client *Client) GetRedisInstance(name string) (*RedisInstance, error) {
+	query := `
+		query($name: String!) {
+			redisInstance(name: $name) {
+				` + redisInstanceFields + `
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("name", name)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.RedisInstance == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.RedisInstance, nil
+}
+
+func (client *Client) DeleteRedisInstance(id string) error {
+	query := `
+		mutation($input: DeleteRedisInstanceInput!) {
+			deleteRedisInstance(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{"redisInstanceId": id})
+
+	_, err := client.Run(req)
+	return err
+}
@@ -1,39 +1,211 @@
 package api
 
+import (
+	"strings"
+
+	"github.com/superfly/flyctl/terminal"
+)
+
+// MaxReleasesPageSize - the most releases fetched in one request
+const MaxReleasesPageSize = 100
+
 func (c *Client) GetAppReleases(appName string, limit int) ([]Release, error) {
+	releases, _, err := c.GetAppReleasesPage(appName, limit, "")
+	return releases, err
+}
+
+// GetAppReleasesPage - Fetches up to limit releases, newest first, after the
+// cursor of a previous page. An empty cursor starts from the latest release.
+func (c *Client) GetAppReleasesPage(appName string, limit int, after string) ([]Release, PageInfo, error) {
 	query := `
-		query ($appName: String!, $limit: Int!) {
+		query ($appName: String!, $limit: Int!, $after: String) {
 			app(name: $appName) {
-				releases(first: $limit) {
+				releases(first: $limit, after: $after) {
 					nodes {
 						id
 						version
 						reason
 						description
-						reason
 						status
 						stable
 						user {
 							id
 							email
 							name
-						}	
+						}
 						createdAt
 					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
 				}
 			}
 		}
 	`
 
+	if limit > MaxReleasesPageSize {
+		limit = MaxReleasesPageSize
+	}
+
 	req := c.NewRequest(query)
 
 	req.Var("appName", appName)
 	req.Var("limit", limit)
+	if after != "" {
+		req.Var("after", after)
+	}
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	releases := data.App.Releases.Nodes
+	c.addReleasesFields(appName, limit, after, releases, "imageRef", "imageDigest")
+	c.addReleasesFields(appName, limit, after, releases, "message", "labels")
+
+	return releases, data.App.Releases.PageInfo, nil
+}
+
+// addReleasesFields fills in fields of the page of releases fetched with the
+// same limit and cursor. The fields are asked for apart from the releases'
+// other fields, which can still be read where the API doesn't report them.
+func (c *Client) addReleasesFields(appName string, limit int, after string, releases []Release, fields ...string) {
+	if len(releases) == 0 {
+		return
+	}
+
+	query := `
+		query ($appName: String!, $limit: Int!, $after: String) {
+			app(name: $appName) {
+				releases(first: $limit, after: $after) {
+					nodes {
+						id
+						` + strings.Join(fields, "\n") + `
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+	req.Var("limit", limit)
+	if after != "" {
+		req.Var("after", after)
+	}
+
+	data, err := c.Run(req)
+	if err != nil {
+		terminal.Debugf("error fetching release %s: %v\n", strings.Join(fields, ", "), err)
+		return
+	}
+
+	byID := map[string]Release{}
+	for _, r := range data.App.Releases.Nodes {
+		byID[r.ID] = r
+	}
+	for i := range releases {
+		if r, ok := byID[releases[i].ID]; ok {
+			copyReleaseFields(&releases[i], r)
+		}
+	}
+}
+
+// addReleaseFields fills in fields of a release, like addReleasesFields
+func (c *Client) addReleaseFields(appName string, release *Release, fields ...string) {
+	query := `
+		query ($appName: String!, $version: Int!) {
+			app(name: $appName) {
+				release(version: $version) {
+					` + strings.Join(fields, "\n") + `
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("version", release.Version)
+
+	data, err := c.Run(req)
+	if err != nil {
+		terminal.Debugf("error fetching release %s: %v\n", strings.Join(fields, ", "), err)
+		return
+	}
+
+	if data.App.Release != nil {
+		copyReleaseFields(release, *data.App.Release)
+	}
+}
+
+// copyReleaseFields copies the fields asked for apart from a release's other
+// fields, those that are set in src
+func copyReleaseFields(dst *Release, src Release) {
+	if src.ImageRef != "" {
+		dst.ImageRef, dst.ImageDigest = src.ImageRef, src.ImageDigest
+	}
+	if src.Message != "" {
+		dst.Message = src.Message
+	}
+	if src.Labels != nil {
+		dst.Labels = src.Labels
+	}
+}
+
+// GetAppRelease - a release by version, with the config it deployed and how
+// its deployment went
+func (c *Client) GetAppRelease(appName string, version int) (*Release, error) {
+	query := `
+		query ($appName: String!, $version: Int!) {
+			app(name: $appName) {
+				release(version: $version) {
+					id
+					version
+					reason
+					description
+					status
+					stable
+					user {
+						id
+						email
+						name
+					}
+					createdAt
+					config {
+						definition
+					}
+					deploymentStatus {
+						id
+						status
+						description
+						placedCount
+						desiredCount
+						healthyCount
+						unhealthyCount
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("version", version)
 
 	data, err := c.Run(req)
 	if err != nil {
 		return nil, err
 	}
+	if data.App.Release == nil {
+		return nil, ErrNotFound
+	}
+
+	release := data.App.Release
+	c.addReleaseFields(appName, release, "imageRef", "imageDigest")
+	c.addReleaseFields(appName, release, "message", "labels")
 
-	return data.App.Releases.Nodes, nil
+	return release, nil
 }
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetAppReleasesPageAnnotations(t *testing.T) {
+	releases := `{"app": {"releases": {"nodes": [{"id": "r2", "version": 2}, {"id": "r1", "version": 1}], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}`
+	annotations := `{"app": {"releases": {"nodes": [{"id": "r2", "message": "bump deps", "labels": {"ticket": "OPS-1"}}, {"id": "r1"}]}}}`
+
+	tests := []struct {
+		name           string
+		annotationsErr error
+		wantMessage    string
+		wantLabels     map[string]string
+	}{
+		{name: "annotations reported", wantMessage: "bump deps", wantLabels: map[string]string{"ticket": "OPS-1"}},
+		{name: "annotations not reported", annotationsErr: errors.New("Field 'message' doesn't exist on type 'Release'")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				if strings.Contains(query, "labels") {
+					if strings.Contains(query, "pageInfo") {
+						t.Errorf("expected annotations to be asked for apart from the releases, got %s", query)
+					}
+					if tt.annotationsErr != nil {
+						return nil, tt.annotationsErr
+					}
+					return decodeJSON(t, annotations), nil
+				}
+				return decodeJSON(t, releases), nil
+			})
+
+			got, page, err := client.GetAppReleasesPage("myapp", 2, "c0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 2 || !page.HasNextPage || page.EndCursor != "c1" {
+				t.Fatalf("got %+v and %+v", got, page)
+			}
+			if got[0].Message != tt.wantMessage || !reflect.DeepEqual(got[0].Labels, tt.wantLabels) {
+				t.Errorf("got %q and %v, want %q and %v", got[0].Message, got[0].Labels, tt.wantMessage, tt.wantLabels)
+			}
+			if got[1].Message != "" || got[1].Labels != nil {
+				t.Errorf("expected the unannotated release to stay so, got %+v", got[1])
+			}
+		})
+	}
+}
+
+func TestDeployImageAnnotations(t *testing.T) {
+	client := newTestClient(t, func(query string) (interface{}, error) {
+		if strings.Contains(query, "labels") {
+			t.Errorf("expected the annotations not to be asked for, got %s", query)
+		}
+		return decodeJSON(t, `{"deployImage": {"release": {"id": "r3", "version": 3}}}`), nil
+	})
+
+	release, _, err := client.DeployImage(DeployImageInput{AppID: "myapp", Message: "bump deps", Labels: map[string]string{"ticket": "OPS-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Version != 3 || release.Message != "bump deps" || release.Labels["ticket"] != "OPS-1" {
+		t.Errorf("got %+v", release)
+	}
+}
+
+func TestGetAppReleasesPageImages(t *testing.T) {
+	tests := []struct {
+		name      string
+		imagesErr error
+		wantRef   string
+	}{
+		{name: "images reported", wantRef: "registry.fly.io/myapp:deployment-1"},
+		{name: "images not reported", imagesErr: errors.New("Field 'imageRef' doesn't exist on type 'Release'")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				switch {
+				case strings.Contains(query, "imageRef"):
+					if strings.Contains(query, "pageInfo") || strings.Contains(query, "labels") {
+						t.Errorf("expected images to be asked for on their own, got %s", query)
+					}
+					if tt.imagesErr != nil {
+						return nil, tt.imagesErr
+					}
+					return decodeJSON(t, `{"app": {"releases": {"nodes": [{"id": "r1", "imageRef": "registry.fly.io/myapp:deployment-1", "imageDigest": "sha256:abc"}]}}}`), nil
+				case strings.Contains(query, "labels"):
+					return decodeJSON(t, `{"app": {"releases": {"nodes": [{"id": "r1", "message": "bump deps"}]}}}`), nil
+				}
+				return decodeJSON(t, `{"app": {"releases": {"nodes": [{"id": "r1", "version": 1}]}}}`), nil
+			})
+
+			got, err := client.GetAppReleases("myapp", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 || got[0].ImageRef != tt.wantRef || got[0].Message != "bump deps" {
+				t.Errorf("got %+v", got)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "github.com/superfly/flyctl/terminal"
+
 func (c *Client) ScaleApp(appID string, regions []ScaleRegionInput) ([]ScaleRegionChange, error) {
 	query := `
 		mutation ($input: ScaleAppInput!) {
@@ -93,6 +95,31 @@ func (c *Client) AppAutoscalingConfig(appName string) (*AutoscalingConfig, error
 	return data.App.Autoscaling, nil
 }
 
+func (c *Client) AppAutostopConfig(appName string) (*AutostopConfig, error) {
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				autostop {
+					autoStop
+					autoStart
+					minMachinesRunning
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.Autostop, nil
+}
+
 func (c *Client) AppVMResources(appName string) (VMSize, []TaskGroupCount, error) {
 	query := `
 		query($appName: String!) {
@@ -122,10 +149,45 @@ func (c *Client) AppVMResources(appName string) (VMSize, []TaskGroupCount, error
 		return VMSize{}, []TaskGroupCount{}, err
 	}
 
-	return data.App.VMSize, data.App.TaskGroupCounts, nil
+	size := data.App.VMSize
+	c.addVMGPUs(appName, &size)
+
+	return size, data.App.TaskGroupCounts, nil
+}
+
+// addVMGPUs fills in the GPUs attached to the app's VMs. They're asked for
+// apart from the VM size, which can still be read where the API doesn't
+// report them.
+func (c *Client) addVMGPUs(appName string, size *VMSize) {
+	query := `
+		query($appName: String!) {
+			app(name: $appName) {
+				vmSize {
+					gpuKind
+					gpus
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		terminal.Debugf("error fetching VM GPUs: %v\n", err)
+		return
+	}
+
+	size.GPUKind, size.GPUs = data.App.VMSize.GPUKind, data.App.VMSize.GPUs
 }
 
 func (c *Client) SetAppVMSize(appID string, sizeName string, memoryMb int64) (VMSize, error) {
+	return c.SetAppVMSizeWithInput(SetVMSizeInput{AppID: appID, SizeName: sizeName, MemoryMb: memoryMb})
+}
+
+func (c *Client) SetAppVMSizeWithInput(input SetVMSizeInput) (VMSize, error) {
 	query := `
 		mutation ($input: SetVMSizeInput!) {
 			setVmSize(input: $input) {
@@ -143,14 +205,17 @@ func (c *Client) SetAppVMSize(appID string, sizeName string, memoryMb int64) (VM
 
 	req := c.NewRequest(query)
 
-	req.Var("input", SetVMSizeInput{AppID: appID, SizeName: sizeName, MemoryMb: memoryMb})
+	req.Var("input", input)
 
 	data, err := c.Run(req)
 	if err != nil {
 		return VMSize{}, err
 	}
 
-	return *data.SetVMSize.VMSize, nil
+	size := *data.SetVMSize.VMSize
+	size.GPUKind, size.GPUs = input.GPUKind, input.GPUs
+
+	return size, nil
 }
 
 func (c *Client) GetAppVMCount(appID string) ([]TaskGroupCount, error) {
@@ -180,6 +245,13 @@ func (c *Client) GetAppVMCount(appID string) ([]TaskGroupCount, error) {
 }
 
 func (c *Client) SetAppVMCount(appID string, count int, maxPerRegion *int) ([]TaskGroupCount, []string, error) {
+	return c.SetAppGroupVMCounts(appID, []VMCountInput{
+		{Group: "app", Count: count, MaxPerRegion: maxPerRegion},
+	})
+}
+
+// SetAppGroupVMCounts sets the VM count of several process groups at once
+func (c *Client) SetAppGroupVMCounts(appID string, groupCounts []VMCountInput) ([]TaskGroupCount, []string, error) {
 	query := `
 		mutation ($input: SetVMCountInput!) {
 			setVmCount(input: $input) {
@@ -195,10 +267,9 @@ func (c *Client) SetAppVMCount(appID string, count int, maxPerRegion *int) ([]Ta
 	req := c.NewRequest(query)
 
 	req.Var("input", SetVMCountInput{
-		AppID: appID,
-		GroupCounts: []VMCountInput{
-			{Group: "app", Count: count, MaxPerRegion: maxPerRegion},
-		}})
+		AppID:       appID,
+		GroupCounts: groupCounts,
+	})
 
 	data, err := c.Run(req)
 	if err != nil {
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAppVMResourcesGPUs(t *testing.T) {
+	tests := []struct {
+		name     string
+		gpusErr  error
+		wantKind string
+		wantGPUs int
+	}{
+		{name: "GPUs reported", wantKind: "a100", wantGPUs: 2},
+		{name: "GPUs not reported", gpusErr: errors.New("Field 'gpuKind' doesn't exist on type 'VMSize'")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(query string) (interface{}, error) {
+				if strings.Contains(query, "gpuKind") {
+					if strings.Contains(query, "taskGroupCounts") {
+						t.Errorf("expected GPUs to be asked for apart from the VM size, got %s", query)
+					}
+					if tt.gpusErr != nil {
+						return nil, tt.gpusErr
+					}
+					return decodeJSON(t, `{"app": {"vmSize": {"gpuKind": "a100", "gpus": 2}}}`), nil
+				}
+				return decodeJSON(t, `{"app": {"vmSize": {"name": "dedicated-cpu-1x", "memoryMb": 2048}, "taskGroupCounts": [{"name": "app", "count": 3}]}}`), nil
+			})
+
+			size, counts, err := client.AppVMResources("myapp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size.Name != "dedicated-cpu-1x" || len(counts) != 1 || counts[0].Count != 3 {
+				t.Errorf("got %+v and %+v", size, counts)
+			}
+			if size.GPUKind != tt.wantKind || size.GPUs != tt.wantGPUs {
+				t.Errorf("got %d x %q, want %d x %q", size.GPUs, size.GPUKind, tt.wantGPUs, tt.wantKind)
+			}
+		})
+	}
+}
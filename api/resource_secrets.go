@@ -34,6 +34,8 @@ func (c *Client) SetSecrets(appName string, secrets map[string]string) (*Release
 		return nil, err
 	}
 
+	forgetCached(secretNamesCacheKey(appName))
+
 	return &data.SetSecrets.Release, nil
 }
 
@@ -66,9 +68,80 @@ func (c *Client) UnsetSecrets(appName string, keys []string) (*Release, error) {
 		return nil, err
 	}
 
+	forgetCached(secretNamesCacheKey(appName))
+
 	return &data.UnsetSecrets.Release, nil
 }
 
+// UpdateSecrets sets and unsets secrets together, in a single release
+func (c *Client) UpdateSecrets(appName string, secrets map[string]string, unsetKeys []string) (*Release, error) {
+	query := `
+		mutation($input: UpdateSecretsInput!) {
+			updateSecrets(input: $input) {
+				release {
+					id
+					version
+					reason
+					description
+					user {
+						id
+						email
+						name
+					}
+					createdAt
+				}
+			}
+		}
+	`
+
+	input := UpdateSecretsInput{AppID: appName, UnsetKeys: unsetKeys}
+	for k, v := range secrets {
+		input.Secrets = append(input.Secrets, SetSecretsInputSecret{Key: k, Value: v})
+	}
+
+	req := c.NewRequest(query)
+
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	forgetCached(secretNamesCacheKey(appName))
+
+	return &data.UpdateSecrets.Release, nil
+}
+
+// CopySecrets copies every secret from one app to another server side, since
+// secret values can't be read back through the API
+func (c *Client) CopySecrets(sourceAppName string, appName string) (*Release, error) {
+	query := `
+		mutation($input: CopySecretsInput!) {
+			copySecrets(input: $input) {
+				release {
+					id
+					version
+					reason
+					description
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", CopySecretsInput{SourceAppID: sourceAppName, AppID: appName})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CopySecrets.Release, nil
+}
+
 func (c *Client) GetAppSecrets(appName string) ([]Secret, error) {
 	query := `
 		query ($appName: String!) {
@@ -93,3 +166,67 @@ func (c *Client) GetAppSecrets(appName string) ([]Secret, error) {
 
 	return data.App.Secrets, nil
 }
+
+// GetAppSecretNames returns the names of an app's secrets, cached briefly
+// for shell completion
+func (c *Client) GetAppSecretNames(appName string) ([]string, error) {
+	var names []string
+	err := cached(c.userCacheKey(secretNamesCacheKey(appName)), completionCacheTTL, &names, func() error {
+		secrets, err := c.GetAppSecrets(appName)
+		if err != nil {
+			return err
+		}
+		names = make([]string, 0, len(secrets))
+		for _, secret := range secrets {
+			names = append(names, secret.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func secretNamesCacheKey(appName string) string {
+	return "secret-names-" + appName + "-"
+}
+
+// GetAppSecretChanges returns every time a secret of appName was set or unset,
+// newest first, with the release that carried the change
+func (c *Client) GetAppSecretChanges(appName string) ([]SecretChange, error) {
+	query := `
+		query ($appName: String!) {
+			app(name: $appName) {
+				secretChanges {
+					nodes {
+						name
+						action
+						digest
+						createdAt
+						user {
+							id
+							email
+						}
+						release {
+							id
+							version
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.SecretChanges.Nodes, nil
+}
@@ -0,0 +1,76 @@
+package api
+
+// GetSSHKeys - the public SSH keys on the current user's account
+func (c *Client) GetSSHKeys() ([]SSHKey, error) {
+	query := `
+		query {
+			currentUser {
+				sshKeys {
+					nodes {
+						id
+						name
+						publicKey
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.CurrentUser.SSHKeys.Nodes, nil
+}
+
+// AddSSHKey - adds a public key, in authorized_keys format, to the current
+// user's account
+func (c *Client) AddSSHKey(name string, publicKey string) (*SSHKey, error) {
+	query := `
+		mutation($input: AddSshKeyInput!) {
+			addSshKey(input: $input) {
+				sshKey {
+					id
+					name
+					publicKey
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{
+		"name":      name,
+		"publicKey": publicKey,
+	})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.AddSSHKey.SSHKey, nil
+}
+
+func (c *Client) DeleteSSHKey(id string) error {
+	query := `
+		mutation($input: DeleteSshKeyInput!) {
+			deleteSshKey(input: $input) {
+				user {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{"sshKeyId": id})
+
+	_, err := c.Run(req)
+	return err
+}
@@ -0,0 +1,115 @@
+package api
+
+const storageBucketFields = `
+	id
+	name
+	region
+	public
+	endpoint
+	createdAt
+	organization {
+		id
+		slug
+	}
+	app {
+		name
+	}
+`
+
+// CreateStorageBucket - creates a bucket and an access key for it. The
+// key's secret is only ever returned here.
+func (client *Client) CreateStorageBucket(input CreateStorageBucketInput) (*StorageBucket, *StorageCredentials, error) {
+	query := `
+		mutation($input: CreateStorageBucketInput!) {
+			createStorageBucket(input: $input) {
+				bucket {
+					` + storageBucketFields + `
+				}
+				credentials {
+					accessKeyId
+					secretAccessKey
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &data.CreateStorageBucket.Bucket, &data.CreateStorageBucket.Credentials, nil
+}
+
+func (client *Client) GetStorageBuckets(orgSlug string) ([]StorageBucket, error) {
+	query := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				storageBuckets {
+					nodes {
+						` + storageBucketFields + `
+					}
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("slug", orgSlug)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.Organization == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Organization.StorageBuckets.Nodes, nil
+}
+
+// GetStorageBucket - the bucket named name, ErrNotFound when there's none
+// the user can see
+func (client *Client) GetStorageBucket(name string) (*StorageBucket, error) {
+	query := `
+		query($name: String!) {
+			storageBucket(name: $name) {
+				` + storageBucketFields + `
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("name", name)
+
+	data, err := client.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.StorageBucket == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.StorageBucket, nil
+}
+
+func (client *Client) DeleteStorageBucket(id string) error {
+	query := `
+		mutation($input: DeleteStorageBucketInput!) {
+			deleteStorageBucket(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+		`
+
+	req := client.NewRequest(query)
+	req.Var("input", map[string]string{"bucketId": id})
+
+	_, err := client.Run(req)
+	return err
+}
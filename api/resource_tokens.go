@@ -0,0 +1,129 @@
+package api
+
+import "time"
+
+// CreateMetricsToken - creates a read-only token for the organization's
+// metrics, returned with the token's secret
+func (c *Client) CreateMetricsToken(orgID string, name string) (*MetricsToken, error) {
+	query := `
+		mutation($input: CreateMetricsTokenInput!) {
+			createMetricsToken(input: $input) {
+				metricsToken {
+					id
+					name
+					token
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{
+		"organizationId": orgID,
+		"name":           name,
+	})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateMetricsToken.MetricsToken, nil
+}
+
+func (c *Client) GetMetricsTokens(orgSlug string) ([]MetricsToken, error) {
+	query := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				metricsTokens {
+					nodes {
+						id
+						name
+						createdAt
+						lastUsedAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("slug", orgSlug)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.Organization == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Organization.MetricsTokens.Nodes, nil
+}
+
+func (c *Client) DeleteMetricsToken(id string) error {
+	query := `
+		mutation($input: DeleteMetricsTokenInput!) {
+			deleteMetricsToken(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{"metricsTokenId": id})
+
+	_, err := c.Run(req)
+	return err
+}
+
+// GetTokenUsage - what each of the organization's tokens has been used for
+// since since, and from where. Tokens unused since then are included with
+// no operations.
+func (c *Client) GetTokenUsage(orgSlug string, since time.Time) ([]TokenUsage, error) {
+	query := `
+		query($slug: String!, $since: ISO8601DateTime!) {
+			organization(slug: $slug) {
+				tokenUsage(since: $since) {
+					nodes {
+						token {
+							id
+							name
+							type
+							createdAt
+						}
+						lastUsedAt
+						operations {
+							name
+							count
+							lastUsedAt
+						}
+						sourceIps {
+							ip
+							country
+							count
+							lastUsedAt
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("slug", orgSlug)
+	req.Var("since", since)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.Organization == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Organization.TokenUsage.Nodes, nil
+}
@@ -34,6 +34,10 @@ func (c *Client) GetVolumes(appName string) ([]Volume, error) {
 }
 
 func (c *Client) CreateVolume(appName string, volname string, region string, sizeGb int, encrypted bool) (*Volume, error) {
+	return c.CreateVolumeWithInput(CreateVolumeInput{AppID: appName, Name: volname, Region: region, SizeGb: sizeGb, Encrypted: encrypted})
+}
+
+func (c *Client) CreateVolumeWithInput(input CreateVolumeInput) (*Volume, error) {
 	query := `
 		mutation($input: CreateVolumeInput!) {
 			createVolume(input: $input) {
@@ -52,8 +56,6 @@ func (c *Client) CreateVolume(appName string, volname string, region string, siz
 		}
 	`
 
-	input := CreateVolumeInput{AppID: appName, Name: volname, Region: region, SizeGb: sizeGb, Encrypted: encrypted}
-
 	req := c.NewRequest(query)
 
 	req.Var("input", input)
@@ -117,3 +119,175 @@ func (c *Client) GetVolume(volID string) (Volume *Volume, err error) {
 
 	return &data.Volume, nil
 }
+
+// GetVolumePlacement - the volume with only the hardware zone and host it
+// was placed on. It's a query of its own so the other volume commands still
+// work where the API doesn't report placement.
+func (c *Client) GetVolumePlacement(volID string) (*Volume, error) {
+	query := `
+	query($id: ID!) {
+		volume: node(id: $id) {
+			... on Volume {
+				id
+				zone
+				host {
+					id
+				}
+			}
+		}
+	}`
+
+	req := c.NewRequest(query)
+
+	req.Var("id", volID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.Volume, nil
+}
+
+// GetVolumesPlacement - an app's volumes with only their names and the
+// hardware zones and hosts they were placed on
+func (c *Client) GetVolumesPlacement(appName string) ([]Volume, error) {
+	query := `
+	query($appName: String!) {
+		app(name: $appName) {
+			volumes {
+				nodes {
+					id
+					name
+					zone
+					host {
+						id
+					}
+				}
+			}
+		}
+	}
+`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.Volumes.Nodes, nil
+}
+
+func (c *Client) CreateVolumeSnapshot(volID string) (*VolumeSnapshot, error) {
+	query := `
+		mutation($input: CreateVolumeSnapshotInput!) {
+			createVolumeSnapshot(input: $input) {
+				volumeSnapshot {
+					id
+					size
+					digest
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{"volumeId": volID})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateVolumeSnapshot.VolumeSnapshot, nil
+}
+
+func (c *Client) GetVolumeSnapshots(volID string) ([]VolumeSnapshot, error) {
+	query := `
+	query($id: ID!) {
+		volume: node(id: $id) {
+			... on Volume {
+				id
+				snapshots {
+					nodes {
+						id
+						size
+						digest
+						createdAt
+					}
+				}
+			}
+		}
+	}`
+
+	req := c.NewRequest(query)
+
+	req.Var("id", volID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Volume.Snapshots.Nodes, nil
+}
+
+// GetVolumeSnapshot - a snapshot by ID
+func (c *Client) GetVolumeSnapshot(snapshotID string) (*VolumeSnapshot, error) {
+	query := `
+	query($id: ID!) {
+		volumeSnapshot: node(id: $id) {
+			... on VolumeSnapshot {
+				id
+				size
+				digest
+				createdAt
+			}
+		}
+	}`
+
+	req := c.NewRequest(query)
+
+	req.Var("id", snapshotID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.VolumeSnapshot.ID == "" {
+		return nil, ErrNotFound
+	}
+
+	return &data.VolumeSnapshot, nil
+}
+
+// GetVolumeSnapshotDownloadURL - a short lived URL to download the snapshot's
+// data from. The API only offers this to some organizations, so it's asked
+// for apart from the snapshot and fails where it isn't offered.
+func (c *Client) GetVolumeSnapshotDownloadURL(snapshotID string) (string, error) {
+	query := `
+	query($id: ID!) {
+		volumeSnapshot: node(id: $id) {
+			... on VolumeSnapshot {
+				downloadUrl
+			}
+		}
+	}`
+
+	req := c.NewRequest(query)
+
+	req.Var("id", snapshotID)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return "", err
+	}
+
+	return data.VolumeSnapshot.DownloadURL, nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVolumePlacementAskedForApart(t *testing.T) {
+	client := newTestClient(t, func(query string) (interface{}, error) {
+		placement := strings.Contains(query, "zone")
+		switch {
+		case strings.Contains(query, "createVolume"):
+			if placement {
+				t.Errorf("expected placement to be asked for apart from creating volumes, got %s", query)
+			}
+			return decodeJSON(t, `{"createVolume": {"volume": {"id": "vol_1", "name": "data"}}}`), nil
+		case strings.Contains(query, "node(id"):
+			if placement {
+				return decodeJSON(t, `{"volume": {"id": "vol_1", "zone": "b5e3", "host": {"id": "h1"}}}`), nil
+			}
+			return decodeJSON(t, `{"volume": {"id": "vol_1", "name": "data"}}`), nil
+		}
+		if placement {
+			return decodeJSON(t, `{"app": {"volumes": {"nodes": [{"id": "vol_1", "name": "data", "zone": "b5e3", "host": {"id": "h1"}}, {"id": "vol_2", "name": "data", "zone": "a1c2", "host": {"id": "h1"}}]}}}`), nil
+		}
+		return decodeJSON(t, `{"app": {"volumes": {"nodes": [{"id": "vol_1", "name": "data"}, {"id": "vol_2", "name": "data"}]}}}`), nil
+	})
+
+	if _, err := client.GetVolumes("web"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetVolume("vol_1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateVolume("web", "data", "iad", 10, true); err != nil {
+		t.Fatal(err)
+	}
+
+	placement, err := client.GetVolumePlacement("vol_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placement.Zone != "b5e3" || placement.Host.ID != "h1" {
+		t.Errorf("got %+v", placement)
+	}
+
+	volumes, err := client.GetVolumesPlacement("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(volumes) != 2 || volumes[1].Zone != "a1c2" || volumes[1].Host.ID != "h1" {
+		t.Errorf("got %+v", volumes)
+	}
+}
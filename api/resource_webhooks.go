@@ -0,0 +1,105 @@
+package api
+
+func (c *Client) CreateWebhook(input CreateWebhookInput) (*Webhook, error) {
+	query := `
+		mutation($input: CreateWebhookInput!) {
+			createWebhook(input: $input) {
+				webhook {
+					id
+					url
+					events
+					appName
+					format
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateWebhook.Webhook, nil
+}
+
+// GetWebhooks returns the webhooks of an organization, including those
+// scoped to one of its apps
+func (c *Client) GetWebhooks(orgSlug string) ([]Webhook, error) {
+	query := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				webhooks {
+					nodes {
+						id
+						url
+						events
+						appName
+						format
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("slug", orgSlug)
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+	if data.Organization == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.Organization.Webhooks.Nodes, nil
+}
+
+func (c *Client) DeleteWebhook(id string) error {
+	query := `
+		mutation($input: DeleteWebhookInput!) {
+			deleteWebhook(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{"webhookId": id})
+
+	_, err := c.Run(req)
+	return err
+}
+
+// TestWebhook has the platform post a test notification to a webhook
+func (c *Client) TestWebhook(id string) (*WebhookDelivery, error) {
+	query := `
+		mutation($input: TestWebhookInput!) {
+			testWebhook(input: $input) {
+				delivery {
+					statusCode
+					error
+					durationMs
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("input", map[string]string{"webhookId": id})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.TestWebhook.Delivery, nil
+}
@@ -59,6 +59,31 @@ mutation($input: AddWireGuardPeerInput!) {
 	return &data.AddWireGuardPeer, nil
 }
 
+func (c *Client) RelocateWireGuardPeer(org *Organization, name, region string) (*CreatedWireGuardPeer, error) {
+	req := c.NewRequest(`
+mutation($input: RelocateWireGuardPeerInput!) { 
+  relocateWireGuardPeer(input: $input) { 
+    peerip
+    endpointip
+    pubkey
+  } 
+}
+`)
+
+	req.Var("input", map[string]interface{}{
+		"organizationId": org.ID,
+		"name":           name,
+		"region":         region,
+	})
+
+	data, err := c.Run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.RelocateWireGuardPeer, nil
+}
+
 func (c *Client) RemoveWireGuardPeer(org *Organization, name string) error {
 	req := c.NewRequest(`
 mutation($input: RemoveWireGuardPeerInput!) { 
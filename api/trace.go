@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var tracer *httpTracer
+
+// SetHTTPTrace - Sets where every API request and response should be traced
+// to, nil to stop tracing. Headers and bodies are included, with credentials
+// and secret values redacted, when withBodies is set.
+func SetHTTPTrace(w io.Writer, withBodies bool) {
+	if w == nil {
+		tracer = nil
+		return
+	}
+	tracer = &httpTracer{w: w, bodies: withBodies}
+}
+
+type httpTracer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	bodies bool
+}
+
+func (t *httpTracer) printf(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.w, "%s "+format+"\n", append([]interface{}{time.Now().Format(time.RFC3339)}, args...)...)
+}
+
+// traceRoundTrip performs the request with next, tracing it and its response
+func (t *httpTracer) traceRoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	op := traceOperation(reqBody)
+	t.printf("--> %s %s%s", req.Method, req.URL, op)
+	if t.bodies {
+		t.printf("    %s", redactHeaders(req.Header))
+		if len(reqBody) > 0 {
+			t.printf("    %s", redactBody(reqBody))
+		}
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.printf("<-- error %s %s%s (%s) %s", req.Method, req.URL, op, elapsed, err)
+		return resp, err
+	}
+
+	t.printf("<-- %d %s %s%s (%s)", resp.StatusCode, req.Method, req.URL, op, elapsed)
+	if t.bodies {
+		t.printf("    %s", redactHeaders(resp.Header))
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if readErr == nil && len(respBody) > 0 {
+			t.printf("    %s", redactBody(respBody))
+		}
+	}
+
+	return resp, err
+}
+
+// traceOperation names the GraphQL operation in a request body, if it has one
+func traceOperation(body []byte) string {
+	var gql struct {
+		Query string `json:"query"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &gql) != nil || gql.Query == "" {
+		return ""
+	}
+	return " " + operationName(gql.Query)
+}
+
+// redactedKeyPattern matches the JSON keys and headers whose values are never
+// traced, wherever they appear
+var redactedKeyPattern = regexp.MustCompile(`(?i)token|password|secret|otp|private|apikey|auth|cookie|credential|^value$`)
+
+const redacted = "[REDACTED]"
+
+// redactBody returns a JSON body with credentials and secret values replaced,
+// or a size summary when it isn't JSON
+func redactBody(body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+
+	out, err := json.Marshal(redactValue(data))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(out)
+}
+
+// redactHeaders returns headers as "Name: value" pairs, sorted, with the
+// values of credentials replaced
+func redactHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if redactedKeyPattern.MatchString(name) {
+			value = redacted
+		}
+		pairs = append(pairs, name+": "+value)
+	}
+	return strings.Join(pairs, "; ")
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value != nil && redactedKeyPattern.MatchString(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `{"query":"mutation($input: SetSecretsInput!) { setSecrets(input: $input) { release { id } } }","variables":{"input":{"appId":"app","secrets":[{"key":"DATABASE_URL","value":"postgres://u:p@host"}]}},"accessToken":"abc"}`
+
+	got := redactBody([]byte(body))
+	want := `{"accessToken":"[REDACTED]","query":"mutation($input: SetSecretsInput!) { setSecrets(input: $input) { release { id } } }","variables":{"input":{"appId":"app","secrets":"[REDACTED]"}}}`
+	if got != want {
+		t.Errorf("redactBody() = %s, want %s", got, want)
+	}
+
+	if got := redactBody([]byte("not json")); got != "[8 bytes]" {
+		t.Errorf("redactBody() = %s, want a size summary", got)
+	}
+}
+
+func TestRedactBodyByKey(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "value in a list of variables",
+			body: `{"variables":{"input":{"env":[{"key":"PORT","value":"8080"}]}}}`,
+			want: `{"variables":{"input":{"env":[{"key":"PORT","value":"[REDACTED]"}]}}}`,
+		},
+		{
+			name: "credential objects",
+			body: `{"data":{"createAccessToken":{"accessToken":{"id":"1","token":"fo1_x"}}}}`,
+			want: `{"data":{"createAccessToken":"[REDACTED]"}}`,
+		},
+		{
+			name: "numbers and lists",
+			body: `{"variables":{"otp":123456,"passwords":["a","b"],"count":2}}`,
+			want: `{"variables":{"count":2,"otp":"[REDACTED]","passwords":"[REDACTED]"}}`,
+		},
+		{
+			name: "auth keys in any case",
+			body: `{"Authorization":"Bearer x","clientCredentials":{"id":"a"},"name":"web"}`,
+			want: `{"Authorization":"[REDACTED]","clientCredentials":"[REDACTED]","name":"web"}`,
+		},
+		{
+			name: "null values kept",
+			body: `{"data":{"app":{"name":"web","secrets":null}}}`,
+			want: `{"data":{"app":{"name":"web","secrets":null}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer fo1_secret")
+	header.Set("Cookie", "session=abc")
+	header.Set("Content-Type", "application/json")
+	header.Set("Fly-Access-Token", "fo1_other")
+
+	got := redactHeaders(header)
+	want := "Authorization: [REDACTED]; Content-Type: application/json; Cookie: [REDACTED]; Fly-Access-Token: [REDACTED]"
+	if got != want {
+		t.Errorf("redactHeaders() = %s, want %s", got, want)
+	}
+}
+
+func TestTraceOperation(t *testing.T) {
+	if got := traceOperation([]byte(`{"query":"query($appName: String!) { app(name: $appName) { id } }"}`)); got != " query app" {
+		t.Errorf("traceOperation() = %q", got)
+	}
+	if got := traceOperation([]byte(`{}`)); got != "" {
+		t.Errorf("traceOperation() = %q, want none", got)
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"math"
 	"time"
 )
 
@@ -26,6 +27,7 @@ type Query struct {
 	OrganizationDetails OrganizationDetails
 	Build               Build
 	Volume              Volume
+	VolumeSnapshot      VolumeSnapshot
 	Domain              *Domain
 
 	Node  interface{}
@@ -35,6 +37,7 @@ type Query struct {
 		RequestRegion string
 		Regions       []Region
 		VMSizes       []VMSize
+		GPUKinds      []GPUKind
 	}
 
 	NearestRegion *Region
@@ -43,6 +46,7 @@ type Query struct {
 
 	TemplateDeploymentNode *TemplateDeployment
 	ReleaseCommandNode     *ReleaseCommand
+	ImagePrewarmNode       *ImagePrewarm
 
 	// hack to let us alias node to a type
 	// DNSZone *DNSZone
@@ -60,11 +64,65 @@ type Query struct {
 		Release Release
 	}
 
+	UpdateSecrets struct {
+		Release Release
+	}
+
+	CopySecrets struct {
+		Release Release
+	}
+
 	DeployImage struct {
 		Release        Release
 		ReleaseCommand *ReleaseCommand
 	}
 
+	PrewarmImage struct {
+		ImagePrewarm ImagePrewarm
+	}
+
+	CreateMetricsToken struct {
+		MetricsToken MetricsToken
+	}
+
+	CreateLogShipper struct {
+		LogShipper LogShipper
+	}
+
+	CreateWebhook struct {
+		Webhook Webhook
+	}
+
+	TestWebhook struct {
+		Delivery WebhookDelivery
+	}
+
+	AddSSHKey struct {
+		SSHKey SSHKey
+	}
+
+	CreateRedisInstance struct {
+		RedisInstance RedisInstance
+	}
+	RedisInstance *RedisInstance
+
+	CreateStorageBucket struct {
+		Bucket      StorageBucket
+		Credentials StorageCredentials
+	}
+	StorageBucket *StorageBucket
+
+	ProvisionExtension struct {
+		Extension Extension
+	}
+
+	AttachConsul ConsulAttachment
+
+	AcquireAppLock struct {
+		Acquired bool
+		Lock     AppLock
+	}
+
 	EnsureRemoteBuilder *struct {
 		App     *App
 		URL     string
@@ -97,6 +155,9 @@ type Query struct {
 	ReleaseIPAddress struct {
 		App App
 	}
+	MoveIPAddress struct {
+		IPAddress IPAddress
+	}
 	ScaleApp struct {
 		App       App
 		Placement []RegionPlacement
@@ -112,6 +173,26 @@ type Query struct {
 		VMSize *VMSize
 	}
 
+	LaunchMachine struct {
+		Machine Machine
+	}
+
+	CloneMachine struct {
+		Machine Machine
+	}
+
+	UpdateMachineServices struct {
+		Machine Machine
+	}
+
+	StartImpersonation struct {
+		Session ImpersonationSession
+	}
+
+	EndImpersonation struct {
+		Session ImpersonationSession
+	}
+
 	SetVMCount struct {
 		App             App
 		TaskGroupCounts []TaskGroupCount
@@ -136,6 +217,14 @@ type Query struct {
 		App App
 	}
 
+	RenameApp struct {
+		App App
+	}
+
+	SetAppMetadata struct {
+		App App
+	}
+
 	CreateDomain struct {
 		Domain *Domain
 	}
@@ -143,6 +232,10 @@ type Query struct {
 		Domain *Domain
 	}
 
+	SetDomainAutoRenew struct {
+		Domain *Domain
+	}
+
 	CheckDomain *CheckDomainResult
 
 	ExportDnsZone struct {
@@ -159,7 +252,12 @@ type Query struct {
 	CreateVolume CreateVolumePayload
 	DeleteVolume DeleteVolumePayload
 
+	CreateVolumeSnapshot struct {
+		VolumeSnapshot VolumeSnapshot
+	}
+
 	AddWireGuardPeer              CreatedWireGuardPeer
+	RelocateWireGuardPeer         CreatedWireGuardPeer
 	EstablishSSHKey               SSHCertificate
 	IssueCertificate              IssuedCertificate
 	CreateDelegatedWireGuardToken DelegatedWireGuardToken
@@ -179,10 +277,26 @@ type Query struct {
 
 	CreatePostgresCluster *CreatePostgresClusterPayload
 
+	RestorePostgresCluster *CreatePostgresClusterPayload
+	PostgresBackup         *PostgresBackup
+	StartPostgresBackup    struct {
+		Backup PostgresBackup
+	}
+	FinishPostgresBackup struct {
+		Backup PostgresBackup
+	}
+	AddPostgresReplica struct {
+		Member PostgresClusterMember
+	}
+
 	AttachPostgresCluster *AttachPostgresClusterPayload
 
 	CreateOrganizationInvitation CreateOrganizationInvitation
 
+	TransferOrganizationOwnership struct {
+		Organization Organization
+	}
+
 	ValidateWireGuardPeers struct {
 		InvalidPeerIPs []string
 	}
@@ -198,6 +312,94 @@ type DelegatedWireGuardToken struct {
 	Token string
 }
 
+// LogShipper forwards the logs of an organization's apps to an external
+// destination. An empty Apps ships the logs of every app.
+type LogShipper struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Destination string    `json:"destination"`
+	Apps        []string  `json:"apps"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CreateLogShipperInput struct {
+	OrganizationID string   `json:"organizationId"`
+	Name           string   `json:"name"`
+	Destination    string   `json:"destination"`
+	Apps           []string `json:"apps,omitempty"`
+	// Config holds the destination's settings and credentials
+	Config map[string]string `json:"config"`
+}
+
+// Webhook is a URL the platform posts notifications of events to, like
+// releases succeeding or failing. An empty AppName notifies of every app in
+// the organization.
+type Webhook struct {
+	ID      string   `json:"id"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	AppName string   `json:"appName,omitempty"`
+	// Format is slack for Slack incoming webhooks, json otherwise
+	Format    string    `json:"format"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateWebhookInput struct {
+	OrganizationID string   `json:"organizationId"`
+	AppID          string   `json:"appId,omitempty"`
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	Format         string   `json:"format"`
+}
+
+// WebhookDelivery is the outcome of posting a notification to a webhook
+type WebhookDelivery struct {
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int    `json:"durationMs"`
+}
+
+// MetricsToken is a read-only token for an organization's Prometheus
+// metrics. Token is only set when it's created.
+type MetricsToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// TokenUsage is what an organization's token was used for over a period,
+// and where from. LastUsedAt is nil for tokens never used.
+type TokenUsage struct {
+	Token struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		Type      string    `json:"type"`
+		CreatedAt time.Time `json:"createdAt"`
+	} `json:"token"`
+	LastUsedAt *time.Time            `json:"lastUsedAt"`
+	Operations []TokenOperationUsage `json:"operations"`
+	SourceIPs  []TokenSourceIPUsage  `json:"sourceIps"`
+}
+
+// TokenOperationUsage counts the calls of a command or API endpoint made
+// with a token
+type TokenOperationUsage struct {
+	Name       string    `json:"name"`
+	Count      int       `json:"count"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// TokenSourceIPUsage counts the calls made with a token from an address
+type TokenSourceIPUsage struct {
+	IP         string    `json:"ip"`
+	Country    string    `json:"country"`
+	Count      int       `json:"count"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
 type DelegatedWireGuardTokenHandle /* whatever */ struct {
 	Name string
 }
@@ -218,21 +420,44 @@ func DefinitionPtr(in map[string]interface{}) *Definition {
 	return &x
 }
 
+// AppAnnotation - a note users describe an app with, kept in its metadata
+type AppAnnotation struct {
+	// Name is what it's set as, e.g. owner=payments
+	Name string
+	// Key is its metadata key
+	Key string
+	// Title labels it in tables
+	Title string
+}
+
+// AppAnnotations - the annotations apps can be described with, in the order
+// they're shown
+var AppAnnotations = []AppAnnotation{
+	{Name: "description", Key: "fly.description", Title: "Description"},
+	{Name: "owner", Key: "fly.owner_team", Title: "Owner Team"},
+	{Name: "repository", Key: "fly.repository", Title: "Repository"},
+	{Name: "oncall", Key: "fly.oncall", Title: "On-call"},
+}
+
 type App struct {
-	ID             string
-	Name           string
-	State          string
-	Status         string
-	Deployed       bool
-	Hostname       string
-	AppURL         string
-	Version        int
+	ID       string
+	Name     string
+	State    string
+	Status   string
+	Deployed bool
+	Hostname string
+	AppURL   string
+	Version  int
+	// Network is the private network (6PN) the app is isolated in, empty for
+	// its organization's default network
+	Network        string
 	Release        *Release
 	Organization   Organization
 	Secrets        []Secret
 	CurrentRelease *Release
 	Releases       struct {
-		Nodes []Release
+		Nodes    []Release
+		PageInfo PageInfo
 	}
 	IPAddresses struct {
 		Nodes []IPAddress
@@ -244,6 +469,9 @@ type App struct {
 	Changes struct {
 		Nodes []AppChange
 	}
+	SecretChanges struct {
+		Nodes []SecretChange
+	}
 	Certificates struct {
 		Nodes []AppCertificate
 	}
@@ -254,6 +482,7 @@ type App struct {
 	Allocation       *AllocationStatus
 	DeploymentStatus *DeploymentStatus
 	Autoscaling      *AutoscalingConfig
+	Autostop         *AutostopConfig
 	VMSize           VMSize
 	Regions          *[]Region
 	BackupRegions    *[]Region
@@ -261,14 +490,85 @@ type App struct {
 		Nodes []Volume
 	}
 	TaskGroupCounts []TaskGroupCount
+	Metadata        map[string]string
 	HealthChecks    *struct {
 		Nodes []CheckState
 	}
 	PostgresAppRole *struct {
 		Databases *[]PostgresClusterDatabase
 		Users     *[]PostgresClusterUser
+		Backups   *[]PostgresBackup
+		WALStatus *PostgresWALStatus
+		Members   *[]PostgresClusterMember
 	}
-	Image *Image
+	Image    *Image
+	Machines struct {
+		Nodes []Machine
+	}
+	Machine    *Machine
+	Extensions struct {
+		Nodes []Extension
+	}
+}
+
+// Machine - A VM run directly on the machines API rather than by a release
+type Machine struct {
+	ID        string
+	Name      string
+	State     string
+	Region    string
+	Image     string
+	GPUKind   string
+	GPUs      int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Version changes whenever the machine's config does
+	Version  string
+	Services []MachineService
+	IPs      struct {
+		Nodes []MachineIP
+	}
+}
+
+// MachineIP - an address of a machine, e.g. its private 6PN address
+type MachineIP struct {
+	Family string `json:"family"`
+	Kind   string `json:"kind"`
+	IP     string `json:"ip"`
+}
+
+// MachineService - Ports on the edge routed to an internal port of a machine
+type MachineService struct {
+	Protocol     string        `json:"protocol"`
+	InternalPort int           `json:"internalPort"`
+	Ports        []PortHandler `json:"ports"`
+}
+
+// UpdateMachineServicesInput - Replaces a machine's services. The update is
+// rejected if the machine's config changed since ExpectedVersion.
+type UpdateMachineServicesInput struct {
+	AppID           string           `json:"appId"`
+	MachineID       string           `json:"machineId"`
+	ExpectedVersion string           `json:"expectedVersion,omitempty"`
+	Services        []MachineService `json:"services"`
+}
+
+type LaunchMachineInput struct {
+	AppID   string `json:"appId"`
+	Name    string `json:"name,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Image   string `json:"image"`
+	Size    string `json:"size,omitempty"`
+	GPUKind string `json:"gpuKind,omitempty"`
+	GPUs    int    `json:"gpus,omitempty"`
+}
+
+type CloneMachineInput struct {
+	AppID     string `json:"appId"`
+	MachineID string `json:"machineId"`
+	Region    string `json:"region,omitempty"`
+	GPUKind   string `json:"gpuKind,omitempty"`
+	GPUs      int    `json:"gpus,omitempty"`
 }
 
 type TaskGroupCount struct {
@@ -285,14 +585,38 @@ type Volume struct {
 	Encrypted          bool
 	CreatedAt          time.Time
 	AttachedAllocation *AllocationStatus
+	// Zone is the hardware zone the volume was placed in
+	Zone string
+	Host struct {
+		ID string
+	}
+	Snapshots struct {
+		Nodes []VolumeSnapshot
+	}
 }
 
 type CreateVolumeInput struct {
-	AppID     string `json:"appId"`
-	Name      string `json:"name"`
-	Region    string `json:"region"`
-	SizeGb    int    `json:"sizeGb"`
-	Encrypted bool   `json:"encrypted"`
+	AppID      string  `json:"appId"`
+	Name       string  `json:"name"`
+	Region     string  `json:"region"`
+	SizeGb     int     `json:"sizeGb"`
+	Encrypted  bool    `json:"encrypted"`
+	SnapshotID *string `json:"snapshotId,omitempty"`
+	// RequireUniqueZone places the volume in a hardware zone without any of
+	// the app's other volumes of the same name. Defaults to true.
+	RequireUniqueZone *bool `json:"requireUniqueZone,omitempty"`
+	// HostAffinityVolumeID places the volume on the same host as another
+	HostAffinityVolumeID *string `json:"hostAffinityVolumeId,omitempty"`
+}
+
+type VolumeSnapshot struct {
+	ID        string
+	Size      string
+	Digest    string
+	CreatedAt time.Time
+	// DownloadURL is a short lived URL the snapshot's data can be read
+	// from. It's only set by GetVolumeSnapshotDownloadURL.
+	DownloadURL string
 }
 
 type CreateVolumePayload struct {
@@ -347,6 +671,8 @@ type AppStatus struct {
 	Organization     Organization
 	DeploymentStatus *DeploymentStatus
 	Allocations      []*AllocationStatus
+	Autostop         *AutostopConfig
+	Metadata         map[string]string
 }
 
 type AppConfig struct {
@@ -356,6 +682,23 @@ type AppConfig struct {
 	Errors     []string
 }
 
+// ImpersonationSession - An org admin acting as a member, with the member's
+// role, to debug their permissions
+type ImpersonationSession struct {
+	ID           string
+	User         User
+	Organization Organization
+	Role         string
+	ExpiresAt    time.Time
+}
+
+type StartImpersonationInput struct {
+	OrganizationID string `json:"organizationId"`
+	Email          string `json:"email"`
+	Reason         string `json:"reason"`
+	TTLSeconds     int    `json:"ttlSeconds,omitempty"`
+}
+
 type Organization struct {
 	ID   string
 	Name string
@@ -386,6 +729,30 @@ type Organization struct {
 		}
 	}
 
+	MetricsTokens struct {
+		Nodes []MetricsToken
+	}
+
+	TokenUsage struct {
+		Nodes []TokenUsage
+	}
+
+	LogShippers struct {
+		Nodes []LogShipper
+	}
+
+	Webhooks struct {
+		Nodes []Webhook
+	}
+
+	RedisInstances struct {
+		Nodes []RedisInstance
+	}
+
+	StorageBuckets struct {
+		Nodes []StorageBucket
+	}
+
 	HealthCheckHandlers *struct {
 		Nodes []HealthCheckHandler
 	}
@@ -406,7 +773,8 @@ type OrganizationDetails struct {
 	Type       string
 	ViewerRole string
 	Apps       struct {
-		Nodes []App
+		Nodes    []App
+		PageInfo PageInfo
 	}
 	Members struct {
 		Edges []OrganizationMembershipEdge
@@ -443,9 +811,14 @@ type DNSRecords struct {
 }
 
 type IPAddress struct {
-	ID        string
-	Address   string
-	Type      string
+	ID      string
+	Address string
+	// Type is v4, v6 or shared_v4. Shared IPv4 addresses are used by many
+	// apps and routed by hostname.
+	Type string
+	// Region is where the address is announced, empty for addresses
+	// announced everywhere
+	Region    string
 	CreatedAt time.Time
 }
 
@@ -453,6 +826,18 @@ type User struct {
 	ID    string
 	Name  string
 	Email string
+
+	SSHKeys struct {
+		Nodes []SSHKey
+	}
+}
+
+// SSHKey is a public key on a user's account, for SSH access to VMs
+type SSHKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	PublicKey string    `json:"publicKey"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Secret struct {
@@ -461,11 +846,31 @@ type Secret struct {
 	CreatedAt time.Time
 }
 
+// SecretChange records a secret being set or unset. Values are never exposed,
+// only the digest of the value that was set.
+type SecretChange struct {
+	Name      string
+	Action    string
+	Digest    string
+	CreatedAt time.Time
+	User      User
+	Release   *struct {
+		ID      string
+		Version int
+	}
+}
+
 type SetSecretsInput struct {
 	AppID   string                  `json:"appId"`
 	Secrets []SetSecretsInputSecret `json:"secrets"`
 }
 
+type UpdateSecretsInput struct {
+	AppID     string                  `json:"appId"`
+	Secrets   []SetSecretsInputSecret `json:"secrets"`
+	UnsetKeys []string                `json:"unsetKeys"`
+}
+
 type SetSecretsInputSecret struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -525,8 +930,23 @@ type Release struct {
 	Description        string
 	Status             string
 	DeploymentStrategy string
-	User               User
-	CreatedAt          time.Time
+	// ImageRef and ImageDigest identify the image deployed by the release
+	ImageRef    string
+	ImageDigest string
+	// Message and Labels annotate the release, set with deploy --message and --label
+	Message   string
+	Labels    map[string]string
+	User      User
+	CreatedAt time.Time
+	// Config and DeploymentStatus are only fetched for a single release
+	Config           *AppConfig        `json:",omitempty"`
+	DeploymentStatus *DeploymentStatus `json:",omitempty"`
+}
+
+// PageInfo - Where a page of a connection ends, to fetch the next one
+type PageInfo struct {
+	HasNextPage bool
+	EndCursor   string
 }
 
 type Build struct {
@@ -621,11 +1041,25 @@ type DeleteCertificatePayload struct {
 }
 
 type DeployImageInput struct {
-	AppID      string      `json:"appId"`
-	Image      string      `json:"image"`
-	Services   *[]Service  `json:"services"`
-	Definition *Definition `json:"definition"`
-	Strategy   *string     `json:"strategy"`
+	AppID              string                   `json:"appId"`
+	Image              string                   `json:"image,omitempty"`
+	ProcessGroupImages []ProcessGroupImageInput `json:"processGroupImages,omitempty"`
+	Services           *[]Service               `json:"services"`
+	Definition         *Definition              `json:"definition"`
+	Strategy           *string                  `json:"strategy"`
+	Message            string                   `json:"message,omitempty"`
+	Labels             map[string]string        `json:"labels,omitempty"`
+	// WaitTimeoutSeconds is how long allocations get to pass their health
+	// checks before the deployment fails, zero for the default
+	WaitTimeoutSeconds int `json:"waitTimeoutSeconds,omitempty"`
+	// PrimaryRegion is where the release command runs and new VMs are placed
+	// first
+	PrimaryRegion string `json:"primaryRegion,omitempty"`
+}
+
+type ProcessGroupImageInput struct {
+	Group string `json:"group"`
+	Image string `json:"image"`
 }
 
 type Service struct {
@@ -636,6 +1070,11 @@ type Service struct {
 	Checks          []Check       `json:"checks,omitempty"`
 	SoftConcurrency int           `json:"softConcurrency,omitempty"`
 	HardConcurrency int           `json:"hardConcurrency,omitempty"`
+	// AutoStopMachines stops idle VMs, AutoStartMachines starts them again
+	// when a request arrives
+	AutoStopMachines   bool `json:"autoStopMachines,omitempty"`
+	AutoStartMachines  bool `json:"autoStartMachines,omitempty"`
+	MinMachinesRunning int  `json:"minMachinesRunning,omitempty"`
 }
 
 type PortHandler struct {
@@ -660,14 +1099,20 @@ type HTTPHeader struct {
 }
 
 type AllocateIPAddressInput struct {
-	AppID string `json:"appId"`
-	Type  string `json:"type"`
+	AppID  string `json:"appId"`
+	Type   string `json:"type"`
+	Region string `json:"region,omitempty"`
 }
 
 type ReleaseIPAddressInput struct {
 	IPAddressID string `json:"ipAddressId"`
 }
 
+type MoveIPAddressInput struct {
+	IPAddressID string `json:"ipAddressId"`
+	AppID       string `json:"appId"`
+}
+
 type ScaleAppInput struct {
 	AppID   string             `json:"appId"`
 	Regions []ScaleRegionInput `json:"regions"`
@@ -693,6 +1138,7 @@ type AllocationStatus struct {
 	ID                 string
 	IDShort            string
 	Version            int
+	TaskName           string
 	Region             string
 	Status             string
 	DesiredStatus      string
@@ -738,6 +1184,39 @@ type Region struct {
 	Latitude         float32
 	Longitude        float32
 	GatewayAvailable bool
+	Capabilities     *RegionCapabilities `json:",omitempty"`
+}
+
+// EstimateLatency - a rough round trip time between two regions, from the
+// great circle distance between them at the speed of light in fiber
+func (r Region) EstimateLatency(to Region) time.Duration {
+	const earthRadiusKm = 6371
+	const kmPerMs = 100 // round trip, light covers ~200km/ms in fiber
+
+	lat1, lat2 := degToRad(r.Latitude), degToRad(to.Latitude)
+	dLat := lat2 - lat1
+	dLon := degToRad(to.Longitude) - degToRad(r.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	km := 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+
+	ms := math.Max(1, math.Round(km/kmPerMs))
+	return time.Duration(ms) * time.Millisecond
+}
+
+func degToRad(deg float32) float64 {
+	return float64(deg) * math.Pi / 180
+}
+
+// RegionCapabilities - What can be run in a region
+type RegionCapabilities struct {
+	Volumes      bool
+	DedicatedIPs bool
+	Machines     bool
+	GPUs         bool
+	GPUKinds     []string
+	// Capacity hints at how busy the region is: normal, constrained or full
+	Capacity string
 }
 
 type AutoscalingConfig struct {
@@ -748,6 +1227,14 @@ type AutoscalingConfig struct {
 	Regions        []AutoscalingRegionConfig
 }
 
+// AutostopConfig - whether an app's idle VMs are stopped and started again
+// when requests arrive
+type AutostopConfig struct {
+	AutoStop           bool
+	AutoStart          bool
+	MinMachinesRunning int
+}
+
 type AutoscalingRegionConfig struct {
 	Code     string
 	MinCount int
@@ -779,12 +1266,25 @@ type VMSize struct {
 	PriceMonth         float32
 	PriceSecond        float32
 	MemoryIncrementsMB []int
+	// GPUKind and GPUs are set when the VMs have GPUs attached
+	GPUKind string
+	GPUs    int
+}
+
+// GPUKind - A model of GPU that can be attached to VMs, priced per GPU
+type GPUKind struct {
+	Name        string
+	MemoryGB    int
+	PriceMonth  float32
+	PriceSecond float32
 }
 
 type SetVMSizeInput struct {
 	AppID    string `json:"appId"`
 	SizeName string `json:"sizeName"`
 	MemoryMb int64  `json:"memoryMb"`
+	GPUKind  string `json:"gpuKind,omitempty"`
+	GPUs     int    `json:"gpus,omitempty"`
 }
 
 type SetVMCountInput struct {
@@ -811,6 +1311,11 @@ type BuildArgInput struct {
 	Value string `json:"value"`
 }
 
+type CopySecretsInput struct {
+	SourceAppID string `json:"sourceAppId"`
+	AppID       string `json:"appId"`
+}
+
 type ConfigureRegionsInput struct {
 	AppID         string   `json:"appId"`
 	AllowRegions  []string `json:"allowRegions"`
@@ -932,6 +1437,110 @@ type SetPagerdutyHandlerInput struct {
 	PagerdutyToken string `json:"pagerdutyToken"`
 }
 
+// RedisInstance is a managed Redis database, reached from an organization's
+// private network at PrivateURL
+type RedisInstance struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Status          string    `json:"status"`
+	Plan            string    `json:"plan"`
+	PrimaryRegion   string    `json:"primaryRegion"`
+	ReadRegions     []string  `json:"readRegions"`
+	PrivateURL      string    `json:"privateUrl"`
+	EvictionEnabled bool      `json:"evictionEnabled"`
+	CreatedAt       time.Time `json:"createdAt"`
+	Organization    struct {
+		ID   string `json:"id"`
+		Slug string `json:"slug"`
+	} `json:"organization"`
+}
+
+type CreateRedisInstanceInput struct {
+	OrganizationID  string   `json:"organizationId"`
+	Name            string   `json:"name"`
+	PrimaryRegion   string   `json:"primaryRegion"`
+	ReadRegions     []string `json:"readRegions,omitempty"`
+	Plan            string   `json:"plan,omitempty"`
+	EvictionEnabled bool     `json:"evictionEnabled"`
+}
+
+// Extension is a service provisioned for an app with a partner, like error
+// tracking with Sentry. Environment is only set when it's provisioned.
+type Extension struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Provider     string            `json:"provider"`
+	Status       string            `json:"status"`
+	DashboardURL string            `json:"dashboardUrl"`
+	Environment  map[string]string `json:"environment,omitempty"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	App          struct {
+		Name string `json:"name"`
+	} `json:"app"`
+}
+
+type ProvisionExtensionInput struct {
+	AppID    string            `json:"appId"`
+	Provider string            `json:"provider"`
+	Name     string            `json:"name,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+}
+
+// AppLock is an advisory lock on an app, held by whoever acquired it until
+// it's released or expires
+type AppLock struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type AcquireAppLockInput struct {
+	AppID      string `json:"appId"`
+	Name       string `json:"name"`
+	Holder     string `json:"holder"`
+	TTLSeconds int    `json:"ttlSeconds"`
+}
+
+// StorageBucket is an S3 compatible object storage bucket, optionally
+// created for an app
+type StorageBucket struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Region       string    `json:"region"`
+	Public       bool      `json:"public"`
+	Endpoint     string    `json:"endpoint"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Organization struct {
+		ID   string `json:"id"`
+		Slug string `json:"slug"`
+	} `json:"organization"`
+	App *struct {
+		Name string `json:"name"`
+	} `json:"app"`
+}
+
+// ConsulAttachment - an app's access to a Consul cluster, with the URL's
+// token included
+type ConsulAttachment struct {
+	ConsulURL string `json:"consulUrl"`
+	Region    string `json:"region"`
+}
+
+type StorageCredentials struct {
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+}
+
+type CreateStorageBucketInput struct {
+	OrganizationID string  `json:"organizationId"`
+	AppID          *string `json:"appId,omitempty"`
+	Name           string  `json:"name"`
+	Region         string  `json:"region"`
+	Public         bool    `json:"public"`
+}
+
 type CreatePostgresClusterInput struct {
 	OrganizationID string  `json:"organizationId"`
 	Name           string  `json:"name"`
@@ -961,6 +1570,9 @@ type AttachPostgresClusterInput struct {
 	PostgresClusterAppID string  `json:"postgresClusterAppId"`
 	DatabaseName         *string `json:"databaseName,omitempty"`
 	VariableName         *string `json:"variableName,omitempty"`
+	// Region the app connects from, the connection string prefers the
+	// cluster's instances there
+	Region *string `json:"region,omitempty"`
 }
 
 type AttachPostgresClusterPayload struct {
@@ -986,6 +1598,66 @@ type PostgresClusterDatabase struct {
 	Users []string
 }
 
+// PostgresClusterMember is an instance of a postgres cluster, its leader or
+// a replica streaming from it. LagBytes is how far a replica is behind.
+type PostgresClusterMember struct {
+	ID        string `json:"id"`
+	Region    string `json:"region"`
+	Role      string `json:"role"`
+	Healthy   bool   `json:"healthy"`
+	LagBytes  int64  `json:"lagBytes"`
+	PrivateIP string `json:"privateIp"`
+	VolumeID  string `json:"volumeId"`
+}
+
+type AddPostgresReplicaInput struct {
+	AppID        string `json:"appId"`
+	Region       string `json:"region"`
+	VolumeSizeGB *int   `json:"volumeSizeGb,omitempty"`
+}
+
+// PostgresBackup is a base backup of a postgres cluster, a snapshot of its
+// leader's volume taken in backup mode. Archived WAL from WALStart on can be
+// replayed over it to restore to a later point in time.
+type PostgresBackup struct {
+	ID               string     `json:"id"`
+	Status           string     `json:"status"`
+	Trigger          string     `json:"trigger"`
+	VolumeID         string     `json:"volumeId"`
+	VolumeSnapshotID string     `json:"volumeSnapshotId"`
+	Size             string     `json:"size"`
+	WALStart         string     `json:"walStart"`
+	StartedAt        time.Time  `json:"startedAt"`
+	CompletedAt      *time.Time `json:"completedAt"`
+}
+
+// PostgresWALStatus is how far a cluster's WAL has been archived, which
+// bounds how recent a point in time it can be restored to
+type PostgresWALStatus struct {
+	Archiving       bool       `json:"archiving"`
+	LastArchivedWAL string     `json:"lastArchivedWal"`
+	LastArchivedAt  *time.Time `json:"lastArchivedAt"`
+	LastFailedWAL   string     `json:"lastFailedWal"`
+	LastFailedAt    *time.Time `json:"lastFailedAt"`
+}
+
+type FinishPostgresBackupInput struct {
+	BackupID         string `json:"backupId"`
+	VolumeSnapshotID string `json:"volumeSnapshotId,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+type RestorePostgresClusterInput struct {
+	SourceAppID    string  `json:"sourceAppId"`
+	OrganizationID string  `json:"organizationId"`
+	Name           string  `json:"name"`
+	BackupID       string  `json:"backupId"`
+	TargetTime     *string `json:"targetTime,omitempty"`
+	Region         *string `json:"region,omitempty"`
+	VMSize         *string `json:"vmSize,omitempty"`
+	VolumeSizeGB   *int    `json:"volumeSizeGb,omitempty"`
+}
+
 type Image struct {
 	ID             string
 	Digest         string
@@ -1004,6 +1676,29 @@ type ReleaseCommand struct {
 	Failed     bool
 }
 
+// ImagePrewarm is the pulling of an image's layers onto the hosts of each of
+// an app's regions ahead of a release
+type ImagePrewarm struct {
+	ID      string
+	Image   string
+	Regions []ImagePrewarmRegion
+}
+
+// ImagePrewarmRegion is the progress of a prewarm in one region. Status is
+// one of pending, pulling, complete or failed.
+type ImagePrewarmRegion struct {
+	Region      string
+	Status      string
+	BytesPulled int64
+	BytesTotal  int64
+	Error       string
+}
+
+// Done reports whether the region has finished, successfully or not
+func (r ImagePrewarmRegion) Done() bool {
+	return r.Status == "complete" || r.Status == "failed"
+}
+
 type Invitation struct {
 	ID           string
 	Email        string
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// userAliases - the aliases defined in config.yml, by name, e.g.
+//
+//	alias:
+//	  dep: deploy --remote-only --strategy rolling
+func userAliases() map[string]string {
+	aliases := map[string]string{}
+	prefix := flyctl.ConfigAliases + "."
+	for _, key := range viper.AllKeys() {
+		if strings.HasPrefix(key, prefix) {
+			aliases[strings.TrimPrefix(key, prefix)] = viper.GetString(key)
+		}
+	}
+	return aliases
+}
+
+// ExpandAlias - args with an alias from config.yml in the first argument
+// replaced by the command it stands for, so "dep -a myapp" runs
+// "deploy --remote-only --strategy rolling -a myapp". Aliases named after a
+// command are ignored, commands can't be redefined.
+func ExpandAlias(root *cobra.Command, args []string) ([]string, error) {
+	// completion requests carry the command line after their own name
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		expanded, err := ExpandAlias(root, args[1:])
+		return append([]string{args[0]}, expanded...), err
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return args, nil
+	}
+
+	name := args[0]
+	expansion, ok := userAliases()[name]
+	if !ok {
+		return args, nil
+	}
+
+	root.InitDefaultHelpCmd()
+	if cmd, _, err := root.Find([]string{name}); err == nil && cmd != root {
+		terminal.Warnf("Ignoring the alias %s in %s, %s is a command\n", name, flyctl.ConfigFilePath(), name)
+		return args, nil
+	}
+
+	words, err := shlex.Split(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias %s in %s: %w", name, flyctl.ConfigFilePath(), err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("the alias %s in %s is empty", name, flyctl.ConfigFilePath())
+	}
+
+	terminal.Debugf("expanded alias %s to %s\n", name, expansion)
+	return append(words, args[1:]...), nil
+}
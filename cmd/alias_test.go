@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func TestExpandAlias(t *testing.T) {
+	root := &cobra.Command{Use: "flyctl"}
+	root.AddCommand(&cobra.Command{Use: "deploy", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(&cobra.Command{Use: "status", Run: func(*cobra.Command, []string) {}})
+
+	aliases := map[string]string{
+		"dep":    "deploy --remote-only --strategy rolling",
+		"status": "status --all",
+		"quoted": `deploy --build-arg "GREETING=hello world" --env 'A=b c'`,
+		"empty":  " ",
+		"broken": `deploy --env "unterminated`,
+	}
+	for name, expansion := range aliases {
+		viper.Set("alias."+name, expansion)
+	}
+	t.Cleanup(func() {
+		for name := range aliases {
+			viper.Set("alias."+name, nil)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no args", args: []string{}, want: []string{}},
+		{name: "not an alias", args: []string{"deploy", "-a", "web"}, want: []string{"deploy", "-a", "web"}},
+		{name: "alias", args: []string{"dep", "-a", "web"}, want: []string{"deploy", "--remote-only", "--strategy", "rolling", "-a", "web"}},
+		{name: "leading flag", args: []string{"--verbose", "dep"}, want: []string{"--verbose", "dep"}},
+		{name: "shadows a command", args: []string{"status", "-a", "web"}, want: []string{"status", "-a", "web"}},
+		{name: "quoting", args: []string{"quoted"}, want: []string{"deploy", "--build-arg", "GREETING=hello world", "--env", "A=b c"}},
+		{
+			name: "completion request",
+			args: []string{cobra.ShellCompRequestCmd, "dep", "--strategy", ""},
+			want: []string{cobra.ShellCompRequestCmd, "deploy", "--remote-only", "--strategy", "rolling", "--strategy", ""},
+		},
+		{
+			name: "completion request without descriptions",
+			args: []string{cobra.ShellCompNoDescRequestCmd, "dep"},
+			want: []string{cobra.ShellCompNoDescRequestCmd, "deploy", "--remote-only", "--strategy", "rolling"},
+		},
+		{name: "empty alias", args: []string{"empty"}, wantErr: true},
+		{name: "unterminated quote", args: []string{"broken"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandAlias(root, tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/terminal"
 )
 
 func newAppsCommand(client *client.Client) *Command {
@@ -23,7 +30,19 @@ func newAppsCommand(client *client.Client) *Command {
 
 	appsListStrings := docstrings.Get("apps.list")
 
-	BuildCommand(cmd, runAppsList, appsListStrings.Usage, appsListStrings.Short, appsListStrings.Long, client, requireSession)
+	list := BuildCommand(cmd, runAppsList, appsListStrings.Usage, appsListStrings.Short, appsListStrings.Long, client, requireSession)
+	list.AddBoolFlag(BoolFlagOpts{
+		Name:        "all-orgs",
+		Description: "Fetch the apps of every organization you belong to in parallel, rather than the first 400 apps",
+	})
+	list.AddStringFlag(StringFlagOpts{
+		Name:        "group-by",
+		Description: "Group the apps, with totals for each group. Only org is supported",
+	})
+	list.AddBoolFlag(BoolFlagOpts{
+		Name:        "wide",
+		Description: "Show each app's description, owner team, repository and on-call contact",
+	})
 
 	appsCreateStrings := docstrings.Get("apps.create")
 
@@ -57,11 +76,14 @@ func newAppsCommand(client *client.Client) *Command {
 		Description: "Never write a fly.toml file",
 	})
 
+	addNetworkFlag(create)
+
 	appsDestroyStrings := docstrings.Get("apps.destroy")
 	destroy := BuildCommand(cmd, runDestroy, appsDestroyStrings.Usage, appsDestroyStrings.Short, appsDestroyStrings.Long, client, requireSession)
 	destroy.Args = cobra.ExactArgs(1)
 	// TODO: Move flag descriptions into the docStrings
 	destroy.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+	addAppOrgFlag(destroy)
 
 	appsMoveStrings := docstrings.Get("apps.move")
 	move := BuildCommand(cmd, runMove, appsMoveStrings.Usage, appsMoveStrings.Short, appsMoveStrings.Long, client, requireSession)
@@ -72,6 +94,65 @@ func newAppsCommand(client *client.Client) *Command {
 		Name:        "org",
 		Description: `The organization to move the app to`,
 	})
+	move.AddBoolFlag(BoolFlagOpts{
+		Name:        "recreate-volumes",
+		Description: "Recreate the app's volumes from snapshots in the destination organization",
+	})
+
+	appsCloneStrings := docstrings.Get("apps.clone")
+	clone := BuildCommandKS(cmd, runClone, appsCloneStrings, client, requireSession)
+	clone.Args = cobra.ExactArgs(2)
+	clone.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Description: `The organization that will own the copy, defaults to the source app's organization`,
+	})
+	clone.AddBoolFlag(BoolFlagOpts{
+		Name:        "copy-secrets",
+		Description: "Copy the source app's secrets to the new app",
+	})
+	clone.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	appsForkStrings := docstrings.Get("apps.fork")
+	fork := BuildCommandKS(cmd, runFork, appsForkStrings, client, requireSession)
+	fork.Args = cobra.ExactArgs(1)
+	fork.AddStringFlag(StringFlagOpts{
+		Name:        "pr",
+		Description: "Pull request number, used to name the fork <APPNAME>-pr-<PR>",
+	})
+	fork.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Description: "Name of the forked app, overrides --pr",
+	})
+	fork.AddStringFlag(StringFlagOpts{
+		Name:        "ttl",
+		Description: "How long the fork lives before it can be reaped, e.g. 72h or 7d",
+		Default:     "7d",
+	})
+	fork.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Description: `The organization that will own the fork, defaults to the source app's organization`,
+	})
+	fork.AddBoolFlag(BoolFlagOpts{
+		Name:        "copy-secrets",
+		Description: "Copy the source app's secrets to the fork",
+	})
+
+	appsReapStrings := docstrings.Get("apps.reap")
+	reap := BuildCommandKS(cmd, runReap, appsReapStrings, client, requireSession)
+	reap.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Description: "Only reap apps in this organization",
+	})
+	reap.AddBoolFlag(BoolFlagOpts{
+		Name:        "dry-run",
+		Description: "List expired apps without destroying them",
+	})
+	reap.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	appsRenameStrings := docstrings.Get("apps.rename")
+	rename := BuildCommandKS(cmd, runRename, appsRenameStrings, client, requireSession)
+	rename.Args = cobra.ExactArgs(2)
+	rename.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
 
 	appsSuspendStrings := docstrings.Get("apps.suspend")
 	appsSuspendCmd := BuildCommand(cmd, runSuspend, appsSuspendStrings.Usage, appsSuspendStrings.Short, appsSuspendStrings.Long, client, requireSession, requireAppNameAsArg)
@@ -82,17 +163,201 @@ func newAppsCommand(client *client.Client) *Command {
 	appsResumeCmd.Args = cobra.RangeArgs(0, 1)
 
 	appsRestartStrings := docstrings.Get("apps.restart")
-	appsRestartCmd := BuildCommand(cmd, runRestart, appsRestartStrings.Usage, appsRestartStrings.Short, appsRestartStrings.Long, client, requireSession, requireAppNameAsArg)
+	appsRestartCmd := BuildCommand(cmd, runRestart, appsRestartStrings.Usage, appsRestartStrings.Short, appsRestartStrings.Long, client, requireSession, requireAppNameAsArgUnlessSelecting)
 	appsRestartCmd.Args = cobra.RangeArgs(0, 1)
+	addRestartSelectFlag(appsRestartCmd)
+
+	appsErrorsStrings := docstrings.Get("apps.errors")
+	appsErrorsCmd := BuildCommandKS(cmd, runAppsErrors, appsErrorsStrings, client, requireSession, requireAppNameAsArg)
+	appsErrorsCmd.Args = cobra.RangeArgs(0, 1)
+	appsErrorsCmd.AddStringFlag(StringFlagOpts{
+		Name:        "window",
+		Shorthand:   "w",
+		Description: "How far back to look for crashes, e.g. 6h or 7d",
+		Default:     "24h",
+	})
+
+	newAppsMetadataCommands(cmd, client)
 
 	return cmd
 }
 
 func runAppsList(ctx *cmdctx.CmdContext) error {
-	listapps, err := ctx.Client.API().GetApps(nil)
+	groupBy := ctx.Config.GetString("group-by")
+	if groupBy != "" && groupBy != "org" {
+		return &ValidationError{fmt.Errorf("can't group by %q, only org is supported", groupBy)}
+	}
+
+	var listapps []api.App
+	var err error
+	if ctx.Config.GetBool("all-orgs") {
+		listapps, err = fetchAllOrgApps(ctx.Client.API())
+	} else {
+		listapps, err = ctx.Client.API().GetApps(nil)
+	}
 	if err != nil {
 		return err
 	}
 
-	return ctx.Render(&presenters.Apps{Apps: listapps})
+	wide := ctx.Config.GetBool("wide")
+	if wide {
+		// annotations only add columns, so the list is shown without them
+		// where the API predates app metadata
+		if err := fetchAppsMetadata(ctx.Client.API(), listapps); err != nil {
+			terminal.Debugf("failed fetching app metadata: %v\n", err)
+		}
+	}
+	if groupBy == "org" {
+		return renderAppsByOrg(ctx, listapps, wide)
+	}
+
+	return ctx.Render(&presenters.Apps{Apps: listapps, Wide: wide})
+}
+
+// fetchAllOrgApps - fetches the apps of every organization the user belongs
+// to, in parallel
+func fetchAllOrgApps(client *api.Client) ([]api.App, error) {
+	orgs, err := client.GetOrganizations(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	orgApps := make([][]api.App, len(orgs))
+
+	var g errgroup.Group
+	for i, org := range orgs {
+		i, slug := i, org.Slug
+		g.Go(func() error {
+			apps, err := client.GetOrganizationApps(slug)
+			if err != nil {
+				return fmt.Errorf("failed listing apps of %s: %w", slug, err)
+			}
+			orgApps[i] = apps
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	apps := []api.App{}
+	for _, a := range orgApps {
+		apps = append(apps, a...)
+	}
+
+	return apps, nil
+}
+
+// appsMetadataConcurrency is how many apps' metadata is fetched at once
+const appsMetadataConcurrency = 8
+
+// fetchAppsMetadata - fills in the metadata of apps, which the app lists
+// leave out so they still work where the API predates app metadata
+func fetchAppsMetadata(client *api.Client, apps []api.App) error {
+	sem := make(chan struct{}, appsMetadataConcurrency)
+
+	var g errgroup.Group
+	for i := range apps {
+		app := &apps[i]
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			metadata, err := client.GetAppMetadata(app.Name)
+			if err != nil {
+				return fmt.Errorf("failed fetching the metadata of %s: %w", app.Name, err)
+			}
+			app.Metadata = metadata
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// appsOrgGroup - the apps of one organization, with their statuses totalled
+type appsOrgGroup struct {
+	Organization string         `json:"organization"`
+	Total        int            `json:"total"`
+	Statuses     map[string]int `json:"statuses"`
+	Apps         []api.App      `json:"apps"`
+}
+
+func groupAppsByOrg(apps []api.App) []appsOrgGroup {
+	bySlug := map[string]*appsOrgGroup{}
+	for _, app := range apps {
+		slug := app.Organization.Slug
+		group, ok := bySlug[slug]
+		if !ok {
+			group = &appsOrgGroup{Organization: slug, Statuses: map[string]int{}}
+			bySlug[slug] = group
+		}
+		group.Apps = append(group.Apps, app)
+		group.Total++
+		group.Statuses[app.Status]++
+	}
+
+	groups := make([]appsOrgGroup, 0, len(bySlug))
+	for _, group := range bySlug {
+		sort.Slice(group.Apps, func(i, j int) bool { return group.Apps[i].Name < group.Apps[j].Name })
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Organization < groups[j].Organization })
+
+	return groups
+}
+
+// formatStatusRollup - describes status counts, most common first, e.g.
+// "3 running, 1 suspended"
+func formatStatusRollup(statuses map[string]int) string {
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if statuses[names[i]] != statuses[names[j]] {
+			return statuses[names[i]] > statuses[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			parts = append(parts, fmt.Sprintf("%d unknown", statuses[name]))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", statuses[name], name))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func renderAppsByOrg(ctx *cmdctx.CmdContext, apps []api.App, wide bool) error {
+	groups := groupAppsByOrg(apps)
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(groups)
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(ctx.Out)
+		}
+		noun := "apps"
+		if group.Total == 1 {
+			noun = "app"
+		}
+		err := ctx.Frender(cmdctx.PresenterOption{
+			Title:       fmt.Sprintf("%s: %d %s (%s)", group.Organization, group.Total, noun, formatStatusRollup(group.Statuses)),
+			Presentable: &presenters.Apps{Apps: group.Apps, Wide: wide},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(ctx.Out, "\n%d apps in %d organizations\n", len(apps), len(groups))
+
+	return nil
 }
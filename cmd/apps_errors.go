@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/deployment"
+)
+
+func runAppsErrors(ctx *cmdctx.CmdContext) error {
+	window, err := helpers.ParseDuration(ctx.Config.GetString("window"))
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid window: %w", err)}
+	}
+	if window <= 0 {
+		return &ValidationError{fmt.Errorf("--window must be positive")}
+	}
+
+	status, err := ctx.Client.API().GetAppStatus(ctx.AppName, true)
+	if err != nil {
+		return err
+	}
+
+	summary := deployment.SummarizeCrashes(status.Allocations, time.Now().Add(-window))
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(summary)
+	}
+
+	if len(summary) == 0 {
+		ctx.Statusf("apps", cmdctx.SDONE, "No crashes in %s in the last %s\n", ctx.AppName, window)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Cause", "Count", "Instances", "Regions", "First Seen", "Last Seen", "Latest"})
+	for _, g := range summary {
+		table.Append([]string{
+			g.Cause,
+			strconv.Itoa(g.Count),
+			strings.Join(g.Instances, ", "),
+			strings.Join(g.Regions, ", "),
+			humanize.Time(g.FirstSeen),
+			humanize.Time(g.LastSeen),
+			g.Example,
+		})
+	}
+	table.Render()
+
+	ctx.StatusLn()
+	ctx.Statusf("apps", cmdctx.SINFO, "See an instance's events and logs with `flyctl status instance <id>`\n")
+
+	return nil
+}
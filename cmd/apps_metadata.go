@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/cmdutil"
+)
+
+func newAppsMetadataCommands(parent *Command, client *client.Client) {
+	descriptionStrings := docstrings.Get("apps.set-description")
+	descriptionCmd := BuildCommandKS(parent, runAppsSetDescription, descriptionStrings, client, requireSession, requireAppName)
+	descriptionCmd.Args = cobra.ExactArgs(1)
+
+	metadataStrings := docstrings.Get("apps.set-metadata")
+	metadataCmd := BuildCommandKS(parent, runAppsSetMetadata, metadataStrings, client, requireSession, requireAppName)
+	metadataCmd.Args = cobra.MinimumNArgs(1)
+}
+
+func runAppsSetDescription(ctx *cmdctx.CmdContext) error {
+	return setAppAnnotations(ctx, map[string]string{"description": ctx.Args[0]})
+}
+
+func runAppsSetMetadata(ctx *cmdctx.CmdContext) error {
+	values, err := cmdutil.ParseKVStringsToMap(ctx.Args)
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid annotation, use KEY=VALUE: %w", err)}
+	}
+	return setAppAnnotations(ctx, values)
+}
+
+// setAppAnnotations - saves annotations by name, e.g. owner, in the app's
+// metadata. Empty values remove them.
+func setAppAnnotations(ctx *cmdctx.CmdContext, values map[string]string) error {
+	metadata, err := appAnnotationMetadata(values)
+	if err != nil {
+		return err
+	}
+
+	saved, err := ctx.Client.API().SetAppMetadata(ctx.AppName, metadata)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(appAnnotationValues(saved))
+	}
+
+	ctx.Statusf("apps", cmdctx.SDONE, "Updated the annotations of %s\n", ctx.AppName)
+	for _, a := range api.AppAnnotations {
+		if value := saved[a.Key]; value != "" {
+			ctx.Statusf("apps", cmdctx.SDETAIL, "%-12s %s\n", a.Title, value)
+		}
+	}
+
+	return nil
+}
+
+// appAnnotationMetadata - annotations by name as the metadata keys and values
+// they're saved as, once they're known and valid
+func appAnnotationMetadata(values map[string]string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for name, value := range values {
+		annotation, ok := findAppAnnotation(name)
+		if !ok {
+			return nil, &ValidationError{fmt.Errorf("unknown annotation %q, use one of %s", name, strings.Join(appAnnotationNames(), ", "))}
+		}
+		value = strings.TrimSpace(value)
+		if annotation.Name == "repository" && value != "" {
+			if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+				return nil, &ValidationError{fmt.Errorf("invalid repository %q, use a URL like https://github.com/acme/web", value)}
+			}
+		}
+		metadata[annotation.Key] = value
+	}
+	return metadata, nil
+}
+
+func findAppAnnotation(name string) (api.AppAnnotation, bool) {
+	for _, a := range api.AppAnnotations {
+		if a.Name == strings.ToLower(name) {
+			return a, true
+		}
+	}
+	return api.AppAnnotation{}, false
+}
+
+func appAnnotationNames() []string {
+	names := make([]string, 0, len(api.AppAnnotations))
+	for _, a := range api.AppAnnotations {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// appAnnotationValues - an app's annotations by name, for --json
+func appAnnotationValues(metadata map[string]string) map[string]string {
+	values := map[string]string{}
+	for _, a := range api.AppAnnotations {
+		if value := metadata[a.Key]; value != "" {
+			values[a.Name] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+func TestFindAppAnnotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantKey string
+		wantOK  bool
+	}{
+		{"description", "fly.description", true},
+		{"owner", "fly.owner_team", true},
+		{"Owner", "fly.owner_team", true},
+		{"repository", "fly.repository", true},
+		{"oncall", "fly.oncall", true},
+		{"owner_team", "", false},
+		{"fly.owner_team", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		annotation, ok := findAppAnnotation(tt.name)
+		if ok != tt.wantOK || annotation.Key != tt.wantKey {
+			t.Errorf("findAppAnnotation(%q) = %q, %t, want %q, %t", tt.name, annotation.Key, ok, tt.wantKey, tt.wantOK)
+		}
+	}
+}
+
+func TestAppAnnotationMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "annotations",
+			values: map[string]string{"description": " Payments API ", "owner": "payments"},
+			want:   map[string]string{"fly.description": "Payments API", "fly.owner_team": "payments"},
+		},
+		{
+			name:   "empty values remove",
+			values: map[string]string{"oncall": ""},
+			want:   map[string]string{"fly.oncall": ""},
+		},
+		{
+			name:   "repository URL",
+			values: map[string]string{"repository": "https://github.com/acme/web"},
+			want:   map[string]string{"fly.repository": "https://github.com/acme/web"},
+		},
+		{
+			name:   "repository removed",
+			values: map[string]string{"repository": ""},
+			want:   map[string]string{"fly.repository": ""},
+		},
+		{
+			name:    "repository without scheme",
+			values:  map[string]string{"repository": "github.com/acme/web"},
+			wantErr: true,
+		},
+		{
+			name:    "repository without host",
+			values:  map[string]string{"repository": "https://"},
+			wantErr: true,
+		},
+		{
+			name:    "repository in capitals",
+			values:  map[string]string{"Repository": "acme/web"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown annotation",
+			values:  map[string]string{"team": "payments"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appAnnotationMetadata(tt.values)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppAnnotationValues(t *testing.T) {
+	got := appAnnotationValues(map[string]string{
+		"fly.owner_team":   "payments",
+		"fly.oncall":       "",
+		"fly.preview_fork": "web",
+	})
+
+	want := map[string]string{"owner": "payments"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFetchAppsMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["appName"] == "old" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": []map[string]string{{"message": "Field 'metadata' doesn't exist on type 'App'"}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"app": map[string]interface{}{"metadata": map[string]string{"fly.owner_team": req.Variables["appName"] + "-team"}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	api.SetBaseURL(server.URL)
+	t.Cleanup(func() { api.SetBaseURL("") })
+	t.Setenv("FLY_ACCESS_TOKEN", "token")
+
+	apiClient := client.NewClient().API()
+
+	apps := []api.App{{Name: "web"}, {Name: "worker"}}
+	if err := fetchAppsMetadata(apiClient, apps); err != nil {
+		t.Fatal(err)
+	}
+	for _, app := range apps {
+		if got := app.Metadata["fly.owner_team"]; got != app.Name+"-team" {
+			t.Errorf("got owner %q for %s, want %s-team", got, app.Name, app.Name)
+		}
+	}
+
+	apps = []api.App{{Name: "web"}, {Name: "old"}}
+	if err := fetchAppsMetadata(apiClient, apps); err == nil {
+		t.Error("expected an error where the API has no metadata")
+	}
+	if apps[0].Metadata == nil {
+		t.Error("expected the metadata of the apps that have it")
+	}
+}
@@ -13,12 +13,15 @@ import (
 	"github.com/superfly/flyctl/internal/client"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/briandowns/spinner"
 	"github.com/logrusorgru/aurora"
 	"github.com/skratchdot/open-golang/open"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/pkg/iostreams"
 	"github.com/superfly/flyctl/terminal"
 )
 
@@ -65,6 +68,30 @@ func newAuthCommand(client *client.Client) *Command {
 	authSignupStrings := docstrings.Get("auth.signup")
 	BuildCommand(cmd, runSignup, authSignupStrings.Usage, authSignupStrings.Short, authSignupStrings.Long, client)
 
+	authImpersonateStrings := docstrings.Get("auth.impersonate")
+	impersonate := BuildCommandKS(cmd, runImpersonate, authImpersonateStrings, client, requireSession)
+	impersonate.Args = cobra.MaximumNArgs(1)
+	impersonate.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization the member belongs to",
+	})
+	impersonate.AddStringFlag(StringFlagOpts{
+		Name:        "reason",
+		Description: "Why you're impersonating the member, recorded in the audit log",
+	})
+	impersonate.AddStringFlag(StringFlagOpts{
+		Name:        "duration",
+		Description: "How long the impersonation session lasts",
+		Default:     "1h",
+	})
+	impersonate.AddBoolFlag(BoolFlagOpts{
+		Name:        "stop",
+		Description: "Stop impersonating",
+	})
+
+	newAuthSSHKeysCommand(cmd, client)
+
 	return cmd
 }
 
@@ -74,9 +101,90 @@ func runWhoami(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 	fmt.Printf("Current user: %s\n", user.Email)
+	if imp := flyctl.GetImpersonation(); imp != nil {
+		fmt.Printf("Impersonating: %s in %s (%s role)\n", imp.User, imp.Org, imp.Role)
+	}
 	return nil
 }
 
+func runImpersonate(ctx *cmdctx.CmdContext) error {
+	current := flyctl.GetImpersonation()
+
+	if ctx.Config.GetBool("stop") {
+		if current == nil {
+			ctx.Status("auth", cmdctx.SINFO, "Not impersonating anyone")
+			return nil
+		}
+		if err := ctx.Client.API().EndImpersonation(current.ID); err != nil {
+			return err
+		}
+		if err := flyctl.SetImpersonation(nil); err != nil {
+			return err
+		}
+		ctx.Statusf("auth", cmdctx.SDONE, "Stopped impersonating %s\n", current.User)
+		return nil
+	}
+
+	if len(ctx.Args) == 0 {
+		return &ValidationError{errors.New("a member's email is required, or --stop to stop impersonating")}
+	}
+	if current != nil {
+		return fmt.Errorf("already impersonating %s in %s, run 'flyctl auth impersonate --stop' first", current.User, current.Org)
+	}
+
+	reason := ctx.Config.GetString("reason")
+	if reason == "" {
+		return &ValidationError{errors.New("--reason is required, it's recorded in the organization's audit log")}
+	}
+
+	duration, err := helpers.ParseDuration(ctx.Config.GetString("duration"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid duration")}
+	}
+
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	session, err := ctx.Client.API().StartImpersonation(api.StartImpersonationInput{
+		OrganizationID: org.ID,
+		Email:          ctx.Args[0],
+		Reason:         reason,
+		TTLSeconds:     int(duration.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	imp := &flyctl.Impersonation{
+		ID:        session.ID,
+		User:      session.User.Email,
+		Org:       org.Slug,
+		Role:      session.Role,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if err := flyctl.SetImpersonation(imp); err != nil {
+		return err
+	}
+
+	ctx.Statusf("auth", cmdctx.SDONE, "Impersonating %s in %s with their %s role until %s\n", imp.User, imp.Org, imp.Role, imp.ExpiresAt.Local().Format(time.Kitchen))
+	ctx.Status("auth", cmdctx.SDETAIL, "Commands are evaluated with their permissions without changing anything, and recorded in the audit log")
+	ctx.Status("auth", cmdctx.SDETAIL, "Run 'flyctl auth impersonate --stop' to stop")
+
+	return nil
+}
+
+// warnImpersonating - shows a banner on every command run while impersonating
+func warnImpersonating(ctx *cmdctx.CmdContext, cmd *cobra.Command) {
+	imp := flyctl.GetImpersonation()
+	if imp == nil || commandPath(cmd) == "auth impersonate" {
+		return
+	}
+
+	ctx.Statusf("auth", cmdctx.SWARN, "IMPERSONATING %s in %s (%s role), changes take effect and are recorded in the audit log\n", imp.User, imp.Org, imp.Role)
+}
+
 func runLogin(ctx *cmdctx.CmdContext) error {
 	if ctx.Config.GetBool("interactive") {
 		return runInteractiveLogin(ctx)
@@ -145,9 +253,7 @@ func waitForCLISession(id string) <-chan api.CLISessionAuth {
 	done := make(chan api.CLISessionAuth)
 
 	go func() {
-		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-		s.Writer = os.Stderr
-		s.Prefix = "Waiting for session..."
+		s := iostreams.NewSpinner("Waiting for session...")
 		s.FinalMSG = "Waiting for session...Done\n"
 		s.Start()
 		defer s.Stop()
@@ -169,37 +275,40 @@ func waitForCLISession(id string) <-chan api.CLISessionAuth {
 func runInteractiveLogin(ctx *cmdctx.CmdContext) error {
 	email := ctx.Config.GetString("email")
 	if email == "" {
-		prompt := &survey.Input{
+		emailPrompt := &survey.Input{
 			Message: "Email:",
 		}
-		if err := survey.AskOne(prompt, &email, survey.WithValidator(survey.Required)); err != nil {
+		if err := prompt.Ask(emailPrompt, &email, "email", survey.WithValidator(survey.Required)); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
 	password := ctx.Config.GetString("password")
 	if password == "" {
-		prompt := &survey.Password{
+		passwordPrompt := &survey.Password{
 			Message: "Password:",
 		}
-		if err := survey.AskOne(prompt, &password, survey.WithValidator(survey.Required)); err != nil {
+		if err := prompt.Ask(passwordPrompt, &password, "password", survey.WithValidator(survey.Required)); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
 	otp := ctx.Config.GetString("otp")
 	if otp == "" {
-		prompt := &survey.Password{
+		otpPrompt := &survey.Password{
 			Message: "One Time Password (if any):",
 		}
-		if err := survey.AskOne(prompt, &otp); err != nil {
+		if err := prompt.Ask(otpPrompt, &otp, "otp"); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
@@ -243,9 +352,8 @@ func runLogout(ctx *cmdctx.CmdContext) error {
 func runAuthToken(ctx *cmdctx.CmdContext) error {
 	token := flyctl.GetAPIToken()
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(map[string]string{"flyctlAuthToken": token})
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(map[string]string{"flyctlAuthToken": token})
 	}
 	fmt.Fprintln(ctx.Out, token)
 
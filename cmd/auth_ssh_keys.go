@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"golang.org/x/crypto/ssh"
+)
+
+// githubKeysURL - where a GitHub user's public SSH keys are published
+const githubKeysURL = "https://github.com/%s.keys"
+
+func newAuthSSHKeysCommand(parent *Command, client *client.Client) {
+	keysStrings := docstrings.Get("auth.ssh-keys")
+	keysCmd := BuildCommandKS(parent, nil, keysStrings, client, requireSession)
+
+	listStrings := docstrings.Get("auth.ssh-keys.list")
+	listCmd := BuildCommandKS(keysCmd, runSSHKeysList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+
+	addStrings := docstrings.Get("auth.ssh-keys.add")
+	addCmd := BuildCommandKS(keysCmd, runSSHKeysAdd, addStrings, client, requireSession)
+	addCmd.Args = cobra.MaximumNArgs(1)
+	addCmd.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Shorthand:   "n",
+		Description: "A name for the key, defaults to the key's comment",
+	})
+	addCmd.AddStringFlag(StringFlagOpts{
+		Name:        "from-github",
+		Description: "Import the public keys of this GitHub user",
+	})
+
+	removeStrings := docstrings.Get("auth.ssh-keys.remove")
+	removeCmd := BuildCommandKS(keysCmd, runSSHKeysRemove, removeStrings, client, requireSession)
+	removeCmd.Aliases = []string{"rm"}
+	removeCmd.Args = cobra.ExactArgs(1)
+	removeCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+}
+
+// sshKeyFingerprint - the SHA256 fingerprint of a public key in
+// authorized_keys format, as ssh-keygen -l shows it
+func sshKeyFingerprint(publicKey string) string {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return "invalid"
+	}
+	return ssh.FingerprintSHA256(key)
+}
+
+func runSSHKeysList(ctx *cmdctx.CmdContext) error {
+	keys, err := ctx.Client.API().GetSSHKeys()
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		type keyWithFingerprint struct {
+			api.SSHKey
+			Fingerprint string `json:"fingerprint"`
+		}
+		data := make([]keyWithFingerprint, 0, len(keys))
+		for _, key := range keys {
+			data = append(data, keyWithFingerprint{key, sshKeyFingerprint(key.PublicKey)})
+		}
+		return ctx.WriteData(data)
+	}
+
+	if len(keys) == 0 {
+		ctx.Status("auth", cmdctx.SINFO, "No SSH keys, add one with `flyctl auth ssh-keys add`")
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Name", "Type", "Fingerprint", "Added"})
+	for _, key := range keys {
+		keyType := strings.SplitN(key.PublicKey, " ", 2)[0]
+		table.Append([]string{key.ID, key.Name, keyType, sshKeyFingerprint(key.PublicKey), humanize.Time(key.CreatedAt)})
+	}
+	table.Render()
+
+	return nil
+}
+
+// sshKeyToAdd - a parsed public key and the name to add it with
+type sshKeyToAdd struct {
+	name        string
+	publicKey   string
+	fingerprint string
+}
+
+func runSSHKeysAdd(ctx *cmdctx.CmdContext) error {
+	githubUser := ctx.Config.GetString("from-github")
+
+	var keys []sshKeyToAdd
+	var err error
+	switch {
+	case githubUser != "" && len(ctx.Args) > 0:
+		return &ValidationError{errors.New("give either a public key file or --from-github, not both")}
+	case githubUser != "":
+		keys, err = githubSSHKeys(githubUser)
+	case len(ctx.Args) > 0:
+		keys, err = readSSHKeys(ctx.Args[0])
+	default:
+		return &ValidationError{errors.New("a public key file, or - for stdin, is required, or use --from-github")}
+	}
+	if err != nil {
+		return err
+	}
+
+	if name := ctx.Config.GetString("name"); name != "" {
+		for i := range keys {
+			keys[i].name = name
+			if len(keys) > 1 {
+				keys[i].name = fmt.Sprintf("%s-%d", name, i+1)
+			}
+		}
+	}
+
+	existing, err := ctx.Client.API().GetSSHKeys()
+	if err != nil {
+		return err
+	}
+	added := map[string]bool{}
+	for _, key := range existing {
+		added[sshKeyFingerprint(key.PublicKey)] = true
+	}
+
+	for _, key := range keys {
+		if added[key.fingerprint] {
+			ctx.Statusf("auth", cmdctx.SINFO, "%s (%s) is already on your account\n", key.name, key.fingerprint)
+			continue
+		}
+		if _, err := ctx.Client.API().AddSSHKey(key.name, key.publicKey); err != nil {
+			return errors.Wrapf(err, "could not add %s", key.name)
+		}
+		added[key.fingerprint] = true
+		ctx.Statusf("auth", cmdctx.SDONE, "Added %s (%s)\n", key.name, key.fingerprint)
+	}
+
+	return nil
+}
+
+// readSSHKeys - the public keys in an authorized_keys style file, or stdin
+// when path is -
+func readSSHKeys(path string) ([]sshKeyToAdd, error) {
+	var r io.Reader = os.Stdin
+	source := "stdin"
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+		source = strings.TrimSuffix(filepath.Base(path), ".pub")
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(data), "PRIVATE KEY") {
+		return nil, &ValidationError{fmt.Errorf("%s is a private key, add the public key instead", path)}
+	}
+
+	return parseSSHKeys(data, source)
+}
+
+func githubSSHKeys(user string) ([]sshKeyToAdd, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(githubKeysURL, user), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch keys from GitHub")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("GitHub user %s not found", user)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch keys from GitHub: %s", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := parseSSHKeys(data, "github-"+user)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("GitHub user %s has no public SSH keys", user)
+	}
+	return keys, nil
+}
+
+// parseSSHKeys - a key per line, skipping blanks and comments. Keys without
+// a comment are named after source.
+func parseSSHKeys(data []byte, source string) ([]sshKeyToAdd, error) {
+	keys := []sshKeyToAdd{}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(text))
+		if err != nil {
+			return nil, &ValidationError{fmt.Errorf("line %d of %s isn't a public SSH key: %w", line, source, err)}
+		}
+
+		name := comment
+		if name == "" {
+			name = source
+		}
+		keys = append(keys, sshKeyToAdd{
+			name:        name,
+			publicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))),
+			fingerprint: ssh.FingerprintSHA256(key),
+		})
+	}
+
+	if len(keys) > 1 {
+		for i := range keys {
+			if keys[i].name == source {
+				keys[i].name = fmt.Sprintf("%s-%d", source, i+1)
+			}
+		}
+	}
+
+	return keys, scanner.Err()
+}
+
+func runSSHKeysRemove(ctx *cmdctx.CmdContext) error {
+	keys, err := ctx.Client.API().GetSSHKeys()
+	if err != nil {
+		return err
+	}
+
+	// keys can be picked by ID, name or fingerprint
+	target := ctx.Args[0]
+	var matches []api.SSHKey
+	for _, key := range keys {
+		if key.ID == target || key.Name == target || sshKeyFingerprint(key.PublicKey) == target {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no SSH key %s on your account", target)
+	case 1:
+	default:
+		return fmt.Errorf("%d SSH keys are named %s, remove one by ID or fingerprint", len(matches), target)
+	}
+	key := matches[0]
+	fingerprint := sshKeyFingerprint(key.PublicKey)
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Remove SSH key %s (%s)?", key.Name, fingerprint), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DeleteSSHKey(key.ID); err != nil {
+		return err
+	}
+
+	ctx.Statusf("auth", cmdctx.SDONE, "Removed SSH key %s (%s)\n", key.Name, fingerprint)
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const (
+	testSSHKey1 = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHdbbXTUb9B9nrqhutYI/IY2YdxcFXesnBLFvgUzgoqC"
+	testSSHKey2 = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKQQ8iqKBJuEAQZvhiQbE6z2it2nSwBiLGoL/MazpqSQ"
+)
+
+func TestParseSSHKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "empty", in: "", want: []string{}},
+		{name: "comment as name", in: testSSHKey1 + " me@laptop\n", want: []string{"me@laptop"}},
+		{name: "source as name", in: testSSHKey1 + "\n", want: []string{"github-me"}},
+		{
+			name: "numbered sources",
+			in:   "# keys\n\n" + testSSHKey1 + "\n" + testSSHKey2 + " work\n" + testSSHKey2 + "\n",
+			want: []string{"github-me-1", "work", "github-me-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		keys, err := parseSSHKeys([]byte(tt.in), "github-me")
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		names := []string{}
+		for _, key := range keys {
+			names = append(names, key.name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, names, tt.want)
+		}
+	}
+}
+
+func TestParseSSHKeysNormalizes(t *testing.T) {
+	keys, err := parseSSHKeys([]byte("  "+testSSHKey1+" me@laptop  "), "id_ed25519.pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("got %d keys, want 1", len(keys))
+	}
+	if keys[0].publicKey != testSSHKey1 {
+		t.Errorf("got public key %q, want %q", keys[0].publicKey, testSSHKey1)
+	}
+	if want := "SHA256:BZ5hYRshpGyrtQZmJqPF6qc3Sww8Dii5FiXYieOUmtE"; keys[0].fingerprint != want {
+		t.Errorf("got fingerprint %s, want %s", keys[0].fingerprint, want)
+	}
+}
+
+func TestParseSSHKeysInvalid(t *testing.T) {
+	_, err := parseSSHKeys([]byte(testSSHKey1+"\nnot a key\n"), "keys.txt")
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
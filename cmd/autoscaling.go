@@ -63,9 +63,7 @@ func runDisableAutoscaling(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
-	printScaleConfig(commandContext, cfg)
-
-	return nil
+	return printScaleConfig(commandContext, cfg)
 }
 
 func actualScale(commandContext *cmdctx.CmdContext, balanceRegions bool, setParamsOnly bool) error {
@@ -135,9 +133,7 @@ func actualScale(commandContext *cmdctx.CmdContext, balanceRegions bool, setPara
 		return err
 	}
 
-	printScaleConfig(commandContext, cfg)
-
-	return nil
+	return printScaleConfig(commandContext, cfg)
 }
 
 func runAutoscalingShow(commandContext *cmdctx.CmdContext) error {
@@ -146,32 +142,30 @@ func runAutoscalingShow(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
-	printScaleConfig(commandContext, cfg)
-
-	return nil
+	return printScaleConfig(commandContext, cfg)
 }
 
-func printScaleConfig(commandContext *cmdctx.CmdContext, cfg *api.AutoscalingConfig) {
+func printScaleConfig(commandContext *cmdctx.CmdContext, cfg *api.AutoscalingConfig) error {
 
-	asJSON := commandContext.OutputJSON()
+	if commandContext.OutputStructured() {
+		return commandContext.WriteData(cfg)
+	}
 
-	if asJSON {
-		commandContext.WriteJSON(cfg)
+	var mode string
+
+	if !cfg.Enabled {
+		mode = "Disabled"
+	} else if cfg.BalanceRegions {
+		mode = "Balanced"
 	} else {
-		var mode string
-
-		if !cfg.Enabled {
-			mode = "Disabled"
-		} else if cfg.BalanceRegions {
-			mode = "Balanced"
-		} else {
-			mode = "Standard"
-		}
+		mode = "Standard"
+	}
 
-		fmt.Fprintf(commandContext.Out, "%15s: %s\n", "Scale Mode", mode)
-		if cfg.Enabled {
-			fmt.Fprintf(commandContext.Out, "%15s: %d\n", "Min Count", cfg.MinCount)
-			fmt.Fprintf(commandContext.Out, "%15s: %d\n", "Max Count", cfg.MaxCount)
-		}
+	fmt.Fprintf(commandContext.Out, "%15s: %s\n", "Scale Mode", mode)
+	if cfg.Enabled {
+		fmt.Fprintf(commandContext.Out, "%15s: %d\n", "Min Count", cfg.MinCount)
+		fmt.Fprintf(commandContext.Out, "%15s: %d\n", "Max Count", cfg.MaxCount)
 	}
+
+	return nil
 }
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/cache"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+func newCacheCommand(client *client.Client) *Command {
+	cacheStrings := docstrings.Get("cache")
+	cmd := BuildCommandKS(nil, nil, cacheStrings, client)
+
+	cacheClearStrings := docstrings.Get("cache.clear")
+	BuildCommandKS(cmd, runCacheClear, cacheClearStrings, client)
+
+	return cmd
+}
+
+func runCacheClear(ctx *cmdctx.CmdContext) error {
+	if err := cache.New(flyctl.CacheDir()).Clear(); err != nil {
+		return err
+	}
+
+	ctx.Status("cache", cmdctx.SDONE, "Cleared cached organization, region and VM size lists")
+
+	return nil
+}
@@ -11,8 +11,8 @@ import (
 	"github.com/superfly/flyctl/internal/client"
 
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/prompt"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/publicsuffix"
 )
@@ -66,11 +66,12 @@ func runCertShow(commandContext *cmdctx.CmdContext) error {
 
 	if cert.ClientStatus == "Ready" {
 		commandContext.Statusf("certs", cmdctx.STITLE, "The certificate for %s has been issued.\n\n", hostname)
-		printCertificate(commandContext, cert)
-		return nil
+		return printCertificate(commandContext, cert)
 	}
 	commandContext.Statusf("certs", cmdctx.STITLE, "The certificate for %s has not been issued yet.\n\n", hostname)
-	printCertificate(commandContext, cert)
+	if err := printCertificate(commandContext, cert); err != nil {
+		return err
+	}
 	return reportNextStepCert(commandContext, hostname, cert, hostcheck)
 
 }
@@ -111,16 +112,12 @@ func runCertDelete(commandContext *cmdctx.CmdContext) error {
 	hostname := commandContext.Args[0]
 
 	if !commandContext.Config.GetBool("yes") {
-		confirm := false
-		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Remove certificate %s from app %s?", hostname, commandContext.AppName),
-		}
-		err := survey.AskOne(prompt, &confirm)
+		confirmed, err := prompt.Confirm(fmt.Sprintf("Remove certificate %s from app %s?", hostname, commandContext.AppName), "yes")
 		if err != nil {
 			return err
 		}
 
-		if !confirm {
+		if !confirmed {
 			return nil
 		}
 	}
@@ -279,10 +276,9 @@ func reportNextStepCert(commandContext *cmdctx.CmdContext, hostname string, cert
 	return nil
 }
 
-func printCertificate(commandContext *cmdctx.CmdContext, cert *api.AppCertificate) {
-	if commandContext.OutputJSON() {
-		commandContext.WriteJSON(cert)
-		return
+func printCertificate(commandContext *cmdctx.CmdContext, cert *api.AppCertificate) error {
+	if commandContext.OutputStructured() {
+		return commandContext.WriteData(cert)
 	}
 
 	myprnt := func(label string, value string) {
@@ -301,6 +297,8 @@ func printCertificate(commandContext *cmdctx.CmdContext, cert *api.AppCertificat
 	myprnt("Issued", strings.Join(certtypes, ","))
 	myprnt("Added to App", humanize.Time(cert.CreatedAt))
 	myprnt("Source", cert.Source)
+
+	return nil
 }
 
 func readableCertAuthority(ca string) string {
@@ -311,9 +309,8 @@ func readableCertAuthority(ca string) string {
 }
 
 func printCertificates(commandContext *cmdctx.CmdContext, certs []api.AppCertificateCompact) error {
-	if commandContext.OutputJSON() {
-		commandContext.WriteJSON(certs)
-		return nil
+	if commandContext.OutputStructured() {
+		return commandContext.WriteData(certs)
 	}
 
 	commandContext.Statusf("certs", cmdctx.STITLE, "%-25s %-20s %s\n", "Host Name", "Added", "Status")
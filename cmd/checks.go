@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmd/presenters"
@@ -11,6 +16,7 @@ import (
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func newChecksCommand(client *client.Client) *Command {
@@ -22,39 +28,62 @@ func newChecksCommand(client *client.Client) *Command {
 
 	handlersListStrings := docstrings.Get("checks.handlers.list")
 	listHandlersCmd := BuildCommandKS(handlersCmd, runListChecksHandlers, handlersListStrings, client, requireSession)
-	listHandlersCmd.Args = cobra.ExactArgs(1)
+	listHandlersCmd.Args = cobra.MaximumNArgs(1)
 
 	handlersCreateStrings := docstrings.Get("checks.handlers.create")
 	createHandlersCmd := BuildCommandKS(handlersCmd, runCreateChecksHandler, handlersCreateStrings, client, requireSession)
 	createHandlersCmd.AddStringFlag(StringFlagOpts{Name: "type", Description: "The type of handler to create, can be slack or pagerduty"})
 	createHandlersCmd.AddStringFlag(StringFlagOpts{Name: "organization", Shorthand: "o", Description: "The organization to add the handler to"})
+	createHandlersCmd.AddStringFlag(StringFlagOpts{Name: "name", Description: "The name of the handler"})
+	createHandlersCmd.AddStringFlag(StringFlagOpts{Name: "webhook-url", Description: "The Slack webhook URL, for slack handlers"})
+	createHandlersCmd.AddStringFlag(StringFlagOpts{Name: "slack-channel", Description: "The Slack channel to post to, for slack handlers"})
+	createHandlersCmd.AddStringFlag(StringFlagOpts{Name: "pagerduty-token", Description: "The PagerDuty integration key, for pagerduty handlers"})
 
 	handlersDeleteStrings := docstrings.Get("checks.handlers.delete")
 	deleteHandlerCmd := BuildCommandKS(handlersCmd, runDeleteChecksHandler, handlersDeleteStrings, client, requireSession)
 	deleteHandlerCmd.Args = cobra.ExactArgs(2)
+	deleteHandlerCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
 
 	checksListStrings := docstrings.Get("checks.list")
 	listChecksCmd := BuildCommandKS(cmd, runAppCheckList, checksListStrings, client, requireSession, requireAppName)
 	listChecksCmd.AddStringFlag(StringFlagOpts{Name: "check-name", Description: "Filter checks by name"})
 
+	checksWaitStrings := docstrings.Get("checks.wait")
+	waitChecksCmd := BuildCommandKS(cmd, runAppCheckWait, checksWaitStrings, client, requireSession, requireAppName)
+	waitChecksCmd.AddStringFlag(StringFlagOpts{Name: "check-name", Description: "Only wait for checks with this name"})
+	waitChecksCmd.AddStringFlag(StringFlagOpts{Name: "timeout", Description: "How long to wait for the checks to pass", Default: "5m"})
+
 	return cmd
 }
 
 func runListChecksHandlers(ctx *cmdctx.CmdContext) error {
-	slug := ctx.Args[0]
+	var slug string
+	if len(ctx.Args) > 0 {
+		slug = ctx.Args[0]
+	}
+
+	org, err := selectOrganization(ctx.Client.API(), slug, nil)
+	if err != nil {
+		return err
+	}
+	slug = org.Slug
 
 	handlers, err := ctx.Client.API().GetHealthCheckHandlers(slug)
 	if err != nil {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(handlers)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(handlers)
 	}
 
 	fmt.Fprintf(ctx.Out, "Health Check Handlers for %s\n", slug)
 
+	if len(handlers) == 0 {
+		fmt.Fprintln(ctx.Out, "No handlers, create one with 'flyctl checks handlers create'")
+		return nil
+	}
+
 	table := helpers.MakeSimpleTable(ctx.Out, []string{"Name", "Type"})
 
 	for _, handler := range handlers {
@@ -68,16 +97,32 @@ func runListChecksHandlers(ctx *cmdctx.CmdContext) error {
 
 type createHandlerFn func(*cmdctx.CmdContext, *api.Organization, string) error
 
+// checksHandlerTypes are the handler types that can be created, in prompt order
+var checksHandlerTypes = []string{"slack", "pagerduty"}
+
 func runCreateChecksHandler(ctx *cmdctx.CmdContext) error {
 	handlerFn := map[string]createHandlerFn{
 		"slack":     setSlackChecksHandler,
 		"pagerduty": setPagerDutyChecksHandler,
 	}
 
-	handlerType := ctx.Config.GetString("type")
+	handlerType := strings.ToLower(ctx.Config.GetString("type"))
+	if handlerType == "" {
+		typePrompt := &survey.Select{
+			Message: "Handler type:",
+			Options: checksHandlerTypes,
+		}
+		if err := prompt.Ask(typePrompt, &handlerType, "type"); err != nil {
+			if isInterrupt(err) {
+				return nil
+			}
+			return err
+		}
+	}
+
 	fn, ok := handlerFn[handlerType]
 	if !ok {
-		return fmt.Errorf("\"%s\" is not a valid handler type", handlerType)
+		return &ValidationError{fmt.Errorf("\"%s\" is not a valid handler type, use one of %s", handlerType, strings.Join(checksHandlerTypes, ", "))}
 	}
 
 	orgSlug := ctx.Config.GetString("organization")
@@ -89,13 +134,14 @@ func runCreateChecksHandler(ctx *cmdctx.CmdContext) error {
 
 	name := ctx.Config.GetString("name")
 	if name == "" {
-		prompt := &survey.Input{
+		namePrompt := &survey.Input{
 			Message: "Name:",
 		}
-		if err := survey.AskOne(prompt, &name, survey.WithValidator(survey.Required)); err != nil {
+		if err := prompt.Ask(namePrompt, &name, "name", survey.WithValidator(survey.Required)); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
@@ -105,25 +151,27 @@ func runCreateChecksHandler(ctx *cmdctx.CmdContext) error {
 func setSlackChecksHandler(ctx *cmdctx.CmdContext, org *api.Organization, name string) error {
 	webhookURL := ctx.Config.GetString("webhook-url")
 	if webhookURL == "" {
-		prompt := &survey.Input{
+		webhookURLPrompt := &survey.Input{
 			Message: "Webhook URL:",
 		}
-		if err := survey.AskOne(prompt, &webhookURL, survey.WithValidator(survey.Required)); err != nil {
+		if err := prompt.Ask(webhookURLPrompt, &webhookURL, "webhook-url", survey.WithValidator(survey.Required)); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
 	slackChannel := ctx.Config.GetString("slack-channel")
 	if slackChannel == "" {
-		prompt := &survey.Input{
+		slackChannelPrompt := &survey.Input{
 			Message: "Slack Channel (defaults to webhook's configured channel):",
 		}
-		if err := survey.AskOne(prompt, &slackChannel); err != nil {
+		if err := prompt.Ask(slackChannelPrompt, &slackChannel, "slack-channel"); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
@@ -172,21 +220,20 @@ func setSlackChecksHandler(ctx *cmdctx.CmdContext, org *api.Organization, name s
 		return err
 	}
 
-	fmt.Fprintf(ctx.Out, "Created %s handler named %s\n", handler.Type, handler.Name)
-
-	return nil
+	return printCreatedChecksHandler(ctx, handler)
 }
 
 func setPagerDutyChecksHandler(ctx *cmdctx.CmdContext, org *api.Organization, name string) error {
 	pagerDutyToken := ctx.Config.GetString("pagerduty-token")
 	if pagerDutyToken == "" {
-		prompt := &survey.Input{
-			Message: "PagerDuty Token:",
+		pagerDutyTokenPrompt := &survey.Password{
+			Message: "PagerDuty Integration Key:",
 		}
-		if err := survey.AskOne(prompt, &pagerDutyToken, survey.WithValidator(survey.Required)); err != nil {
+		if err := prompt.Ask(pagerDutyTokenPrompt, &pagerDutyToken, "pagerduty-token", survey.WithValidator(survey.Required)); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	}
 
@@ -202,6 +249,14 @@ func setPagerDutyChecksHandler(ctx *cmdctx.CmdContext, org *api.Organization, na
 		return err
 	}
 
+	return printCreatedChecksHandler(ctx, handler)
+}
+
+func printCreatedChecksHandler(ctx *cmdctx.CmdContext, handler *api.HealthCheckHandler) error {
+	if ctx.OutputStructured() {
+		return ctx.WriteData(handler)
+	}
+
 	fmt.Fprintf(ctx.Out, "Created %s handler named %s\n", handler.Type, handler.Name)
 
 	return nil
@@ -214,6 +269,12 @@ func runDeleteChecksHandler(ctx *cmdctx.CmdContext) error {
 	}
 	handlerName := ctx.Args[1]
 
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Delete handler %s from organization %s? Failing checks will no longer notify it", handlerName, org.Slug), "yes") {
+			return nil
+		}
+	}
+
 	err = ctx.Client.API().DeleteHealthCheckHandler(org.ID, handlerName)
 
 	if err != nil {
@@ -237,24 +298,126 @@ func runAppCheckList(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if err != nil {
-		return err
-	}
-
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(checks)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(checks)
 	}
 
 	fmt.Fprintf(ctx.Out, "Health Checks for %s\n", ctx.AppName)
+	printChecksTable(ctx, checks)
+
+	return nil
+}
+
+// printChecksTable - shows checks grouped by allocation
+func printChecksTable(ctx *cmdctx.CmdContext, checks []api.CheckState) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checkAllocationID(checks[i]) < checkAllocationID(checks[j])
+	})
 
 	table := helpers.MakeSimpleTable(ctx.Out, []string{"Name", "Status", "Allocation", "Region", "Type", "Last Updated", "Output"})
 
 	for _, check := range checks {
-		table.Append([]string{check.Name, check.Status, check.Allocation.IDShort, check.Allocation.Region, check.Type, presenters.FormatRelativeTime(check.UpdatedAt), check.Output})
+		var allocID, region string
+		if check.Allocation != nil {
+			allocID, region = check.Allocation.IDShort, check.Allocation.Region
+		}
+		table.Append([]string{check.Name, check.Status, allocID, region, check.Type, presenters.FormatRelativeTime(check.UpdatedAt), check.Output})
 	}
 
 	table.Render()
+}
+
+func checkAllocationID(check api.CheckState) string {
+	if check.Allocation == nil {
+		return ""
+	}
+	return check.Allocation.IDShort
+}
+
+// checksWaitInterval - how often checks wait polls the app's checks
+const checksWaitInterval = 2 * time.Second
+
+func runAppCheckWait(ctx *cmdctx.CmdContext) error {
+	var nameFilter *string
+	if val := ctx.Config.GetString("check-name"); val != "" {
+		nameFilter = api.StringPointer(val)
+	}
+
+	timeout, err := helpers.ParseDuration(ctx.Config.GetString("timeout"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid timeout")}
+	}
+
+	waitCtx, cancel := context.WithTimeout(createCancellableContext(), timeout)
+	defer cancel()
+
+	ctx.Statusf("checks", cmdctx.SBEGIN, "Waiting up to %s for %s's health checks to pass\n", timeout, ctx.AppName)
+
+	var checks []api.CheckState
+	lastSummary := ""
+
+	for {
+		checks, err = ctx.Client.API().GetAppHealthChecks(ctx.AppName, nameFilter, nil, api.BoolPointer(true))
+		if err != nil {
+			return err
+		}
+
+		passing := 0
+		for _, check := range checks {
+			if check.Status == "passing" {
+				passing++
+			}
+		}
+
+		if summary := fmt.Sprintf("%d of %d checks passing", passing, len(checks)); len(checks) > 0 && summary != lastSummary {
+			ctx.Status("checks", cmdctx.SDETAIL, summary)
+			lastSummary = summary
+		}
+
+		if passing == len(checks) {
+			break
+		}
+
+		select {
+		case <-time.After(checksWaitInterval):
+			continue
+		case <-waitCtx.Done():
+		}
+
+		if ctx.OutputStructured() {
+			if err := ctx.WriteData(checks); err != nil {
+				return err
+			}
+		} else {
+			printChecksTable(ctx, failingChecks(checks))
+		}
+
+		if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s waiting for health checks to pass", timeout)
+		}
+		return waitCtx.Err()
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(checks)
+	}
+
+	if len(checks) == 0 {
+		ctx.Statusf("checks", cmdctx.SDONE, "%s has no health checks to wait for\n", ctx.AppName)
+		return nil
+	}
+
+	ctx.Statusf("checks", cmdctx.SDONE, "All %d health checks are passing\n", len(checks))
 
 	return nil
 }
+
+func failingChecks(checks []api.CheckState) []api.CheckState {
+	out := []api.CheckState{}
+	for _, check := range checks {
+		if check.Status != "passing" {
+			out = append(out, check)
+		}
+	}
+	return out
+}
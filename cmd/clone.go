@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/helpers"
+)
+
+func runClone(cmdCtx *cmdctx.CmdContext) error {
+	sourceName := cmdCtx.Args[0]
+	destName := cmdCtx.Args[1]
+
+	app, sourceConfig, err := cloneApp(cmdCtx, sourceName, destName, cmdCtx.Config.GetString("org"))
+	if err != nil {
+		return err
+	}
+
+	if cmdCtx.Config.GetBool("copy-secrets") {
+		if err := cloneSecrets(cmdCtx, sourceName, app.Name); err != nil {
+			return err
+		}
+	}
+
+	appConfig := flyctl.NewAppConfig()
+	appConfig.AppName = app.Name
+	appConfig.Definition = sourceConfig.Definition
+
+	configPath := filepath.Join(cmdCtx.WorkingDir, fmt.Sprintf("fly.%s.toml", app.Name))
+	if helpers.FileExists(configPath) && !confirm(fmt.Sprintf("Overwrite file '%s'", helpers.PathRelativeToCWD(configPath)), "") {
+		return nil
+	}
+	if err := writeAppConfig(configPath, appConfig); err != nil {
+		return err
+	}
+
+	cmdCtx.Statusf("clone", cmdctx.SINFO, "Deploy the copy with '%s deploy -c %s'\n", flyname.Name(), helpers.PathRelativeToCWD(configPath))
+
+	return nil
+}
+
+// cloneApp creates destName with the regions, VM size and instance count of
+// sourceName, returning the new app and the source app's config. The new app
+// goes in orgSlug, or the source app's organization when it's empty.
+func cloneApp(cmdCtx *cmdctx.CmdContext, sourceName string, destName string, orgSlug string) (*api.App, *api.AppConfig, error) {
+	client := cmdCtx.Client.API()
+
+	source, err := client.GetApp(sourceName)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error fetching app")
+	}
+
+	sourceConfig, err := client.GetConfig(sourceName)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error fetching app config")
+	}
+
+	regions, backupRegions, err := client.ListAppRegions(sourceName)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error fetching app regions")
+	}
+
+	vmSize, taskGroupCounts, err := client.AppVMResources(sourceName)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error fetching app scale")
+	}
+
+	if orgSlug == "" {
+		orgSlug = source.Organization.Slug
+	}
+	org, err := selectOrganization(client, orgSlug, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var preferredRegion *string
+	if len(regions) > 0 {
+		preferredRegion = &regions[0].Code
+	}
+
+	app, err := client.CreateApp(destName, org.ID, preferredRegion)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "Failed to create app")
+	}
+	cmdCtx.Statusf("clone", cmdctx.SBEGIN, "Created %s in organization %s\n", app.Name, org.Slug)
+
+	input := api.ConfigureRegionsInput{AppID: app.Name}
+	for _, r := range regions {
+		input.AllowRegions = append(input.AllowRegions, r.Code)
+	}
+	for _, r := range backupRegions {
+		input.BackupRegions = append(input.BackupRegions, r.Code)
+	}
+	if _, _, err := client.ConfigureRegions(input); err != nil {
+		return nil, nil, errors.WithMessage(err, "Failed to configure regions")
+	}
+	cmdCtx.Statusf("clone", cmdctx.SDONE, "Configured regions %s\n", regionCodes(regions))
+
+	if vmSize.Name != "" {
+		if _, err := client.SetAppVMSize(app.Name, vmSize.Name, int64(vmSize.MemoryMB)); err != nil {
+			return nil, nil, errors.WithMessage(err, "Failed to set VM size")
+		}
+		cmdCtx.Statusf("clone", cmdctx.SDONE, "Set VM size to %s\n", vmSize.Name)
+	}
+
+	if len(taskGroupCounts) > 0 {
+		groupCounts := make([]api.VMCountInput, 0, len(taskGroupCounts))
+		counts := map[string]int{}
+		for _, tg := range taskGroupCounts {
+			groupCounts = append(groupCounts, api.VMCountInput{Group: tg.Name, Count: tg.Count})
+			counts[tg.Name] = tg.Count
+		}
+		if _, _, err := client.SetAppGroupVMCounts(app.Name, groupCounts); err != nil {
+			return nil, nil, errors.WithMessage(err, "Failed to set VM counts")
+		}
+		cmdCtx.Statusf("clone", cmdctx.SDONE, "Set VM counts to %s\n", formatProcessCounts(counts))
+	}
+
+	return app, sourceConfig, nil
+}
+
+func cloneSecrets(cmdCtx *cmdctx.CmdContext, sourceName string, destName string) error {
+	secrets, err := cmdCtx.Client.API().GetAppSecrets(sourceName)
+	if err != nil {
+		return err
+	}
+
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	if !cmdCtx.Config.GetBool("yes") && !confirm(fmt.Sprintf("Copy %d secrets from %s to %s", len(secrets), sourceName, destName), "yes") {
+		return nil
+	}
+
+	if _, err := cmdCtx.Client.API().CopySecrets(sourceName, destName); err != nil {
+		return errors.WithMessage(err, "Failed to copy secrets")
+	}
+	cmdCtx.Statusf("clone", cmdctx.SDONE, "Copied %d secrets\n", len(secrets))
+
+	return nil
+}
+
+func regionCodes(regions []api.Region) []string {
+	codes := make([]string, 0, len(regions))
+	for _, r := range regions {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"path"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/superfly/flyctl/cmdctx"
@@ -14,10 +15,13 @@ import (
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/flyname"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/cmdfmt"
 	"github.com/superfly/flyctl/terminal"
 )
 
@@ -196,6 +200,21 @@ func BuildCommand(parent *Command, fn RunFn, usageText string, shortHelpText str
 				return err
 			}
 
+			if err := applyOutputLevel(ctx); err != nil {
+				return err
+			}
+
+			stopHTTPTrace, err := applyHTTPTrace(ctx)
+			if err != nil {
+				return err
+			}
+			defer stopHTTPTrace()
+
+			warnDeprecated(ctx, cmd)
+			warnImpersonating(ctx, cmd)
+
+			applyProjectContext(ctx, cmd)
+
 			for _, init := range initializers {
 				if init.Setup != nil {
 					if err := init.Setup(ctx); err != nil {
@@ -222,8 +241,122 @@ func BuildCommand(parent *Command, fn RunFn, usageText string, shortHelpText str
 	return flycmd
 }
 
+// applyProjectContext - makes the organization and environment selected for
+// the project the defaults of --org and --environment, which the flags and
+// their environment variables still override. So does the app, except for
+// commands with an app config, which fall back to it after fly.toml.
+func applyProjectContext(ctx *cmdctx.CmdContext, cmd *cobra.Command) {
+	project, err := flyctl.FindProjectContext(ctx.WorkingDir)
+	if err != nil {
+		// warn rather than fail, so `context set` can still fix the file
+		terminal.Warnf("Ignoring the project context, failed to read it: %v\n", err)
+		return
+	}
+	if project == nil {
+		return
+	}
+	terminal.Debugf("Project Context: %s\n", project.Path)
+
+	defaults := map[string]string{"org": project.Org, "environment": project.Environment}
+	if cmd.Flags().Lookup("config") == nil {
+		defaults["app"] = project.App
+	}
+	for name, value := range defaults {
+		if value != "" && cmd.Flags().Lookup(name) != nil {
+			viper.SetDefault(namespace(cmd)+"."+name, value)
+		}
+	}
+}
+
+// projectContextApp - the app selected for the project, which commands with
+// an app config use when neither -a nor fly.toml name one
+func projectContextApp(ctx *cmdctx.CmdContext) string {
+	project, err := flyctl.FindProjectContext(ctx.WorkingDir)
+	if err != nil || project == nil {
+		return ""
+	}
+	return project.App
+}
+
+// applyOutputLevel - configures output outside the command context, from
+// packages that print directly, for --quiet and --verbose
+func applyOutputLevel(ctx *cmdctx.CmdContext) error {
+	quiet := ctx.Quiet()
+	verbose := ctx.Verbose()
+
+	if quiet && verbose {
+		return &ValidationError{fmt.Errorf("--quiet and --verbose can't be used together")}
+	}
+
+	cmdfmt.SetQuiet(quiet)
+	ctx.IO.SetQuiet(quiet)
+	api.SetTimingLog(verbose)
+
+	if ctx.GlobalConfig.GetBool(flyctl.ConfigASCIIOutput) {
+		ctx.IO.ForcePlain()
+	}
+
+	if quiet {
+		terminal.SetLogLevel(terminal.LevelWarn)
+	}
+
+	return nil
+}
+
+// applyHTTPTrace - turns on API request tracing for --debug-http, its
+// companion flags or FLY_DEBUG=http. The returned func stops it, closing the
+// trace file.
+func applyHTTPTrace(ctx *cmdctx.CmdContext) (func(), error) {
+	path := ctx.GlobalConfig.GetString(flyctl.ConfigDebugHTTPFile)
+	bodies := ctx.GlobalConfig.GetBool(flyctl.ConfigDebugHTTPBodies)
+
+	enabled := ctx.GlobalConfig.GetBool(flyctl.ConfigDebugHTTP) || bodies || path != ""
+	for _, v := range strings.Split(os.Getenv("FLY_DEBUG"), ",") {
+		if strings.TrimSpace(v) == "http" {
+			enabled = true
+		}
+	}
+
+	if !enabled {
+		return func() {}, nil
+	}
+
+	if path == "" {
+		api.SetHTTPTrace(ctx.IO.ErrOut, bodies)
+		return func() { api.SetHTTPTrace(nil, false) }, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't open HTTP trace file")
+	}
+	api.SetHTTPTrace(f, bodies)
+
+	return func() {
+		api.SetHTTPTrace(nil, false)
+		f.Close()
+	}, nil
+}
+
 const defaultConfigFilePath = "./fly.toml"
 
+// unlessFlag - skips the checks opt makes before running when the bool flag
+// is set, for commands that can also run offline. Its setup still runs.
+func unlessFlag(flag string, opt Option) Option {
+	return func(cmd *Command) Initializer {
+		init := opt(cmd)
+		if preRun := init.PreRun; preRun != nil {
+			init.PreRun = func(ctx *cmdctx.CmdContext) error {
+				if ctx.Config.GetBool(flag) {
+					return nil
+				}
+				return preRun(ctx)
+			}
+		}
+		return init
+	}
+}
+
 func requireSession(cmd *Command) Initializer {
 	return Initializer{
 		PreRun: func(ctx *cmdctx.CmdContext) error {
@@ -251,6 +384,11 @@ func requireAppName(cmd *Command) Initializer {
 		Default:     defaultConfigFilePath,
 		EnvName:     "FLY_APP_CONFIG",
 	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "environment",
+		Description: "Apply this environment from the config's [environments] section, e.g. staging",
+		EnvName:     "FLY_ENVIRONMENT",
+	})
 
 	return Initializer{
 		Setup: func(ctx *cmdctx.CmdContext) error {
@@ -272,16 +410,8 @@ func requireAppName(cmd *Command) Initializer {
 			}
 			ctx.ConfigFile = resolvedPath
 
-			// load the config file if it exists
-			if helpers.FileExists(ctx.ConfigFile) {
-				terminal.Debug("Loading app config from", ctx.ConfigFile)
-				appConfig, err := flyctl.LoadAppConfig(ctx.ConfigFile)
-				if err != nil {
-					return err
-				}
-				ctx.AppConfig = appConfig
-			} else {
-				ctx.AppConfig = flyctl.NewAppConfig()
+			if err := loadAppConfig(ctx); err != nil {
+				return err
 			}
 
 			// set the app name if provided
@@ -291,6 +421,9 @@ func requireAppName(cmd *Command) Initializer {
 			} else if ctx.AppConfig != nil {
 				ctx.AppName = ctx.AppConfig.AppName
 			}
+			if ctx.AppName == "" {
+				ctx.AppName = projectContextApp(ctx)
+			}
 
 			return nil
 		},
@@ -299,14 +432,10 @@ func requireAppName(cmd *Command) Initializer {
 				return fmt.Errorf("No app specified. Specify an app or create an app with '" + flyname.Name() + " init'")
 			}
 
-			if ctx.AppConfig == nil {
-				return nil
-			}
-
-			if ctx.AppConfig.AppName != "" && ctx.AppConfig.AppName != ctx.AppName {
+			if ctx.AppConfig != nil && ctx.AppConfig.AppName != "" && ctx.AppConfig.AppName != ctx.AppName {
 				terminal.Warnf("app flag '%s' does not match app name in config file '%s'\n", ctx.AppName, ctx.AppConfig.AppName)
 
-				if !confirm(fmt.Sprintf("Continue using '%s'", ctx.AppName)) {
+				if !confirm(fmt.Sprintf("Continue using '%s'", ctx.AppName), "") {
 					return ErrAbort
 				}
 			}
@@ -316,6 +445,29 @@ func requireAppName(cmd *Command) Initializer {
 	}
 }
 
+// loadAppConfig - loads the resolved config file, if it exists, with the
+// environment selected by --environment applied
+func loadAppConfig(ctx *cmdctx.CmdContext) error {
+	environment := ctx.Config.GetString("environment")
+
+	if !helpers.FileExists(ctx.ConfigFile) {
+		if environment != "" {
+			return &ValidationError{fmt.Errorf("environment %q needs a config file, none found at %s", environment, helpers.PathRelativeToCWD(ctx.ConfigFile))}
+		}
+		ctx.AppConfig = flyctl.NewAppConfig()
+		return nil
+	}
+
+	terminal.Debug("Loading app config from", ctx.ConfigFile)
+	appConfig, err := flyctl.LoadAppConfigEnvironment(ctx.ConfigFile, environment)
+	if err != nil {
+		return err
+	}
+	ctx.AppConfig = appConfig
+
+	return nil
+}
+
 func requireAppNameAsArg(cmd *Command) Initializer {
 	cmd.AddStringFlag(StringFlagOpts{
 		Name:        "app",
@@ -331,6 +483,11 @@ func requireAppNameAsArg(cmd *Command) Initializer {
 		Default:     defaultConfigFilePath,
 		EnvName:     "FLY_APP_CONFIG",
 	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "environment",
+		Description: "Apply this environment from the config's [environments] section, e.g. staging",
+		EnvName:     "FLY_ENVIRONMENT",
+	})
 
 	return Initializer{
 		Setup: func(ctx *cmdctx.CmdContext) error {
@@ -352,16 +509,8 @@ func requireAppNameAsArg(cmd *Command) Initializer {
 			}
 			ctx.ConfigFile = resolvedPath
 
-			// load the config file if it exists
-			if helpers.FileExists(ctx.ConfigFile) {
-				terminal.Debug("Loading app config from", ctx.ConfigFile)
-				appConfig, err := flyctl.LoadAppConfig(ctx.ConfigFile)
-				if err != nil {
-					return err
-				}
-				ctx.AppConfig = appConfig
-			} else {
-				ctx.AppConfig = flyctl.NewAppConfig()
+			if err := loadAppConfig(ctx); err != nil {
+				return err
 			}
 
 			// set the app name if provided
@@ -371,6 +520,9 @@ func requireAppNameAsArg(cmd *Command) Initializer {
 			} else if ctx.AppConfig != nil {
 				ctx.AppName = ctx.AppConfig.AppName
 			}
+			if ctx.AppName == "" {
+				ctx.AppName = projectContextApp(ctx)
+			}
 
 			return nil
 		},
@@ -383,14 +535,10 @@ func requireAppNameAsArg(cmd *Command) Initializer {
 				return fmt.Errorf("No app specified")
 			}
 
-			if ctx.AppConfig == nil {
-				return nil
-			}
-
-			if ctx.AppConfig.AppName != "" && ctx.AppConfig.AppName != ctx.AppName {
+			if ctx.AppConfig != nil && ctx.AppConfig.AppName != "" && ctx.AppConfig.AppName != ctx.AppName {
 				terminal.Warnf("app flag '%s' does not match app name in config file '%s'\n", ctx.AppName, ctx.AppConfig.AppName)
 
-				if !confirm(fmt.Sprintf("Continue using '%s'", ctx.AppName)) {
+				if !confirm(fmt.Sprintf("Continue using '%s'", ctx.AppName), "") {
 					return ErrAbort
 				}
 			}
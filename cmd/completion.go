@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+// completionShells - the shells completion scripts can be generated for
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+func newCompletionCommand(client *client.Client) *Command {
+	completionStrings := docstrings.Get("completion")
+	cmd := BuildCommandKS(nil, nil, completionStrings, client)
+	cmd.Args = cobra.ExactValidArgs(1)
+	cmd.ValidArgs = completionShells
+	// the script is generated from the whole command tree, so this runs
+	// with the cobra command rather than a command context
+	cmd.RunE = func(c *cobra.Command, args []string) error {
+		return writeCompletionScript(c.Root(), c.OutOrStdout(), args[0])
+	}
+	return cmd
+}
+
+func writeCompletionScript(root *cobra.Command, w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletion(w)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell":
+		return root.GenPowerShellCompletion(w)
+	}
+
+	return &ValidationError{fmt.Errorf("unknown shell %q, use one of %s", shell, strings.Join(completionShells, ", "))}
+}
+
+// completionFunc completes a flag's value or a command's arguments
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// registerCompletions - completes the values of -a, --org and --region with
+// the apps, organizations and regions the user can pick, on every command
+// that has them
+func registerCompletions(root *cobra.Command, client *client.Client) {
+	flags := map[string]completionFunc{
+		"app":    completeAppNames(client),
+		"org":    completeOrgSlugs(client),
+		"region": completeRegionCodes(client),
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for name, fn := range flags {
+			if cmd.Flags().Lookup(name) != nil {
+				checkErr(cmd.RegisterFlagCompletionFunc(name, fn))
+			}
+		}
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// completions - the candidates starting with what's typed so far
+func completions(candidates []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	out := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			out = append(out, c)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeAppNames(client *client.Client) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !client.Authenticated() {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, err := client.API().GetAppNames()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return completions(names, toComplete)
+	}
+}
+
+func completeOrgSlugs(client *client.Client) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !client.Authenticated() {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		orgs, err := client.API().GetOrganizations(nil)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		slugs := make([]string, 0, len(orgs))
+		for _, org := range orgs {
+			slugs = append(slugs, org.Slug)
+		}
+		return completions(slugs, toComplete)
+	}
+}
+
+func completeRegionCodes(client *client.Client) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !client.Authenticated() {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		regions, err := client.API().PlatformRegionList()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		codes := make([]string, 0, len(regions))
+		for _, region := range regions {
+			codes = append(codes, region.Code)
+		}
+		return completions(codes, toComplete)
+	}
+}
+
+// completeSecretNames - completes the names of the app's secrets, skipping
+// those already given
+func completeSecretNames(client *client.Client) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		appName := completionAppName(cmd)
+		if !client.Authenticated() || appName == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, err := client.API().GetAppSecretNames(appName)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		given := map[string]bool{}
+		for _, arg := range args {
+			given[arg] = true
+		}
+		remaining := []string{}
+		for _, name := range names {
+			if !given[name] {
+				remaining = append(remaining, name)
+			}
+		}
+		return completions(remaining, toComplete)
+	}
+}
+
+// completionAppName - the app a command being completed would operate on,
+// found the same way requireAppName does, or "" when there's none
+func completionAppName(cmd *cobra.Command) string {
+	if name, _ := cmd.Flags().GetString("app"); name != "" {
+		return name
+	}
+	if name := os.Getenv("FLY_APP"); name != "" {
+		return name
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = wd
+	}
+	if resolved, err := flyctl.ResolveConfigFileFromPath(configPath); err == nil {
+		if appConfig, err := flyctl.LoadAppConfig(resolved); err == nil && appConfig.AppName != "" {
+			return appConfig.AppName
+		}
+	}
+
+	if project, err := flyctl.FindProjectContext(wd); err == nil && project != nil {
+		return project.App
+	}
+	return ""
+}
@@ -3,6 +3,12 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/internal/tomledit"
 
 	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/cmdctx"
@@ -14,6 +20,8 @@ import (
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/pkg/iostreams"
+	"github.com/superfly/flyctl/terminal"
 )
 
 func newConfigCommand(client *client.Client) *Command {
@@ -23,21 +31,127 @@ func newConfigCommand(client *client.Client) *Command {
 	cmd := BuildCommandKS(nil, nil, configStrings, client, requireSession, requireAppName)
 
 	configDisplayStrings := docstrings.Get("config.display")
-	BuildCommandKS(cmd, runDisplayConfig, configDisplayStrings, client, requireSession, requireAppName)
+	displayCmd := BuildCommandKS(cmd, runDisplayConfig, configDisplayStrings, client, requireSession, requireAppName)
+	displayCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "resolved",
+		Description: "Display the local config file with its env templates resolved, as deploy would",
+	})
 
 	configSaveStrings := docstrings.Get("config.save")
 	BuildCommandKS(cmd, runSaveConfig, configSaveStrings, client, requireSession, requireAppName)
 
 	configValidateStrings := docstrings.Get("config.validate")
-	BuildCommandKS(cmd, runValidateConfig, configValidateStrings, client, requireSession, requireAppName)
+	validateCmd := BuildCommandKS(cmd, runValidateConfig, configValidateStrings, client, unlessFlag("local", requireSession), unlessFlag("local", requireAppName))
+	validateCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "local",
+		Description: "Validate against the schema bundled with flyctl, without contacting the Fly platform",
+	})
+
+	configSchemaStrings := docstrings.Get("config.schema")
+	BuildCommandKS(cmd, runConfigSchema, configSchemaStrings, client)
 
 	configEnvStrings := docstrings.Get("config.env")
 	BuildCommandKS(cmd, runEnvConfig, configEnvStrings, client, requireSession, requireAppName)
 
+	configSetStrings := docstrings.Get("config.set")
+	setCmd := BuildCommandKS(cmd, runConfigSet, configSetStrings, client)
+	setCmd.Args = cobra.MinimumNArgs(1)
+	addLocalConfigFlag(setCmd)
+
+	configUnsetStrings := docstrings.Get("config.unset")
+	unsetCmd := BuildCommandKS(cmd, runConfigUnset, configUnsetStrings, client)
+	unsetCmd.Args = cobra.MinimumNArgs(1)
+	addLocalConfigFlag(unsetCmd)
+
 	return cmd
 }
 
+// addLocalConfigFlag - adds --config to commands that edit the config file
+// without needing an app
+func addLocalConfigFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "config",
+		Shorthand:   "c",
+		Description: "Path to an app config file or directory containing one",
+		Default:     defaultConfigFilePath,
+		EnvName:     "FLY_APP_CONFIG",
+	})
+}
+
+func runConfigSet(ctx *cmdctx.CmdContext) error {
+	edits := make([]func([]byte) ([]byte, error), 0, len(ctx.Args))
+	for _, arg := range ctx.Args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return &ValidationError{fmt.Errorf("%q must be in the form KEY=VALUE", arg)}
+		}
+		key, value := parts[0], parts[1]
+		edits = append(edits, func(doc []byte) ([]byte, error) { return tomledit.Set(doc, key, value) })
+	}
+
+	return editConfigFile(ctx, edits, "Set")
+}
+
+func runConfigUnset(ctx *cmdctx.CmdContext) error {
+	edits := make([]func([]byte) ([]byte, error), 0, len(ctx.Args))
+	for _, key := range ctx.Args {
+		key := key
+		edits = append(edits, func(doc []byte) ([]byte, error) { return tomledit.Unset(doc, key) })
+	}
+
+	return editConfigFile(ctx, edits, "Unset")
+}
+
+// editConfigFile - applies edits to the config file in turn, writing it only
+// if they all succeed
+func editConfigFile(ctx *cmdctx.CmdContext, edits []func([]byte) ([]byte, error), verb string) error {
+	configPath := ctx.Config.GetString("config")
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(ctx.WorkingDir, configPath)
+	}
+	configPath, err := flyctl.ResolveConfigFileFromPath(configPath)
+	if err != nil {
+		return err
+	}
+	if !helpers.FileExists(configPath) {
+		return &ValidationError{fmt.Errorf("App config file not found at %s", helpers.PathRelativeToCWD(configPath))}
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+	doc, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	for i, edit := range edits {
+		if doc, err = edit(doc); err != nil {
+			return &ValidationError{fmt.Errorf("%s: %w", ctx.Args[i], err)}
+		}
+	}
+
+	if err := os.WriteFile(configPath, doc, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	ctx.Statusf("config", cmdctx.SDONE, "%s %s in %s\n", verb, strings.Join(ctx.Args, ", "), helpers.PathRelativeToCWD(configPath))
+
+	return nil
+}
+
 func runDisplayConfig(ctx *cmdctx.CmdContext) error {
+	if ctx.Config.GetBool("resolved") {
+		if ctx.AppConfig == nil {
+			return &ValidationError{errors.New("--resolved needs an app config file")}
+		}
+		if _, err := resolveEnvTemplates(ctx); err != nil {
+			return err
+		}
+		return ctx.WriteData(ctx.AppConfig.Definition)
+	}
+
 	cfg, err := ctx.Client.API().GetConfig(ctx.AppName)
 	if err != nil {
 		return err
@@ -46,8 +160,28 @@ func runDisplayConfig(ctx *cmdctx.CmdContext) error {
 	//encoder := json.NewEncoder(os.Stdout)
 	//encoder.SetIndent("", "  ")
 	//encoder.Encode(cfg.Definition)
-	ctx.WriteJSON(cfg.Definition)
-	return nil
+	return ctx.WriteData(cfg.Definition)
+}
+
+// resolveEnvTemplates - resolves the templates in the app config's env
+// values, looking up the primary region only when they need it
+func resolveEnvTemplates(ctx *cmdctx.CmdContext) ([]string, error) {
+	if !ctx.AppConfig.HasEnvTemplates() {
+		return nil, nil
+	}
+
+	vars := flyctl.EnvTemplateVars{AppName: ctx.AppName}
+	region, err := appPrimaryRegion(ctx)
+	if err != nil {
+		terminal.Debugf("looking up the primary region failed: %v\n", err)
+	}
+	vars.Region = region
+
+	resolved, err := ctx.AppConfig.ResolveEnvTemplates(vars)
+	if err != nil {
+		return nil, &ValidationError{err}
+	}
+	return resolved, nil
 }
 
 func runSaveConfig(ctx *cmdctx.CmdContext) error {
@@ -59,7 +193,7 @@ func runSaveConfig(ctx *cmdctx.CmdContext) error {
 
 	if helpers.FileExists(configfilename) {
 		ctx.Status("create", cmdctx.SERROR, "An existing configuration file has been found.")
-		confirmation := confirm(fmt.Sprintf("Overwrite file '%s'", configfilename))
+		confirmation := confirm(fmt.Sprintf("Overwrite file '%s'", configfilename), "")
 		if !confirmation {
 			return nil
 		}
@@ -81,29 +215,60 @@ func runSaveConfig(ctx *cmdctx.CmdContext) error {
 }
 
 func runValidateConfig(commandContext *cmdctx.CmdContext) error {
-	if commandContext.AppConfig == nil {
-		return errors.New("App config file not found")
+	if commandContext.AppConfig == nil || !helpers.FileExists(commandContext.ConfigFile) {
+		return &ValidationError{errors.New("App config file not found")}
 	}
 
 	commandContext.Status("config", cmdctx.STITLE, "Validating", commandContext.ConfigFile)
 
+	if commandContext.Config.GetBool("local") {
+		return validateConfigLocally(commandContext)
+	}
+
 	serverCfg, err := commandContext.Client.API().ParseConfig(commandContext.AppName, commandContext.AppConfig.Definition)
 	if err != nil {
 		return err
 	}
 
 	if commandContext.GlobalConfig.GetBool("verbose") {
-		commandContext.WriteJSON(serverCfg.Definition)
+		if err := commandContext.WriteData(serverCfg.Definition); err != nil {
+			return err
+		}
 	}
 
 	if serverCfg.Valid {
-		fmt.Println(aurora.Green("✓").String(), "Configuration is valid")
+		commandContext.Status("config", cmdctx.SDONE, "Configuration is valid")
 		return nil
 	}
 
 	printAppConfigErrors(*serverCfg)
 
-	return errors.New("App configuration is not valid")
+	return &ValidationError{errors.New("App configuration is not valid")}
+}
+
+func validateConfigLocally(commandContext *cmdctx.CmdContext) error {
+	problems, unknown, err := commandContext.AppConfig.ValidateLocally()
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range unknown {
+		commandContext.Statusf("config", cmdctx.SWARN, "Unknown setting %s, check it isn't a typo\n", setting)
+	}
+
+	if len(problems) == 0 {
+		commandContext.Status("config", cmdctx.SDONE, "Configuration is valid")
+		return nil
+	}
+
+	printAppConfigErrors(api.AppConfig{Errors: problems})
+
+	return &ValidationError{errors.New("App configuration is not valid")}
+}
+
+func runConfigSchema(commandContext *cmdctx.CmdContext) error {
+	_, err := commandContext.IO.Out.Write(flyctl.AppConfigSchema)
+	return err
 }
 
 func runEnvConfig(ctx *cmdctx.CmdContext) error {
@@ -146,7 +311,7 @@ func runEnvConfig(ctx *cmdctx.CmdContext) error {
 func printAppConfigErrors(cfg api.AppConfig) {
 	fmt.Println()
 	for _, error := range cfg.Errors {
-		fmt.Println("   ", aurora.Red("✘").String(), error)
+		fmt.Println("   ", aurora.Red(iostreams.Glyph("✘")).String(), error)
 	}
 	fmt.Println()
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
+)
+
+// defaultConsulVariable - the secret LiteFS configs read the Consul URL from
+const defaultConsulVariable = "FLY_CONSUL_URL"
+
+func newConsulCommand(client *client.Client) *Command {
+	consulStrings := docstrings.Get("consul")
+	cmd := BuildCommandKS(nil, nil, consulStrings, client, requireSession)
+
+	attachStrings := docstrings.Get("consul.attach")
+	attachCmd := BuildCommandKS(cmd, runConsulAttach, attachStrings, client, requireSession, requireAppName)
+	addConsulVariableFlag(attachCmd)
+	attachCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	detachStrings := docstrings.Get("consul.detach")
+	detachCmd := BuildCommandKS(cmd, runConsulDetach, detachStrings, client, requireSession, requireAppName)
+	addConsulVariableFlag(detachCmd)
+	detachCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	return cmd
+}
+
+func addConsulVariableFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "variable-name",
+		Description: "The secret the Consul URL is set as",
+		Default:     defaultConsulVariable,
+	})
+}
+
+func runConsulAttach(ctx *cmdctx.CmdContext) error {
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	variable := ctx.Config.GetString("variable-name")
+	if ok, err := confirmSecretsReplaced(ctx, app.Name, []string{variable}); err != nil || !ok {
+		return err
+	}
+
+	return attachConsul(ctx, app, variable)
+}
+
+// attachConsul - provisions a Consul URL for app and sets it as the secret
+// variable
+func attachConsul(ctx *cmdctx.CmdContext, app *api.App, variable string) error {
+	s := iostreams.NewSpinner("Attaching Consul...")
+	s.Start()
+	attachment, err := ctx.Client.API().AttachConsul(app.Name)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	release, err := ctx.Client.API().SetSecrets(app.Name, map[string]string{variable: attachment.ConsulURL})
+	if err != nil {
+		// attaching again returns the same URL, so there's nothing to undo
+		ctx.Statusf("consul", cmdctx.SERROR, "Attached Consul but couldn't set %s, run `flyctl consul attach` again\n", variable)
+		return err
+	}
+
+	ctx.Statusf("consul", cmdctx.SDONE, "Attached Consul in %s to %s, its URL is set as %s\n", attachment.Region, app.Name, variable)
+	if app.Deployed {
+		ctx.Statusf("consul", cmdctx.SINFO, "Release v%d created\n", release.Version)
+	}
+
+	return nil
+}
+
+func runConsulDetach(ctx *cmdctx.CmdContext) error {
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Detach Consul from %s? Its keys are deleted, and LiteFS can't elect a primary without them", app.Name), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DetachConsul(app.Name); err != nil {
+		return err
+	}
+	ctx.Statusf("consul", cmdctx.SDONE, "Detached Consul from %s\n", app.Name)
+
+	variable := ctx.Config.GetString("variable-name")
+	secrets, err := ctx.Client.API().GetAppSecrets(app.Name)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if secret.Name != variable {
+			continue
+		}
+		release, err := ctx.Client.API().UnsetSecrets(app.Name, []string{variable})
+		if err != nil {
+			return err
+		}
+		ctx.Statusf("consul", cmdctx.SINFO, "Unset %s\n", variable)
+		if app.Deployed {
+			ctx.Statusf("consul", cmdctx.SINFO, "Release v%d created\n", release.Version)
+		}
+	}
+
+	return nil
+}
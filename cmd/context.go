@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/cmdutil"
+)
+
+func newContextCommand(client *client.Client) *Command {
+	contextStrings := docstrings.Get("context")
+	cmd := BuildCommandKS(nil, nil, contextStrings, client)
+
+	showStrings := docstrings.Get("context.show")
+	BuildCommandKS(cmd, runContextShow, showStrings, client)
+
+	setStrings := docstrings.Get("context.set")
+	setCmd := BuildCommandKS(cmd, runContextSet, setStrings, client, requireSession)
+	setCmd.Args = cobra.MinimumNArgs(1)
+
+	return cmd
+}
+
+func runContextShow(ctx *cmdctx.CmdContext) error {
+	project, err := flyctl.FindProjectContext(ctx.WorkingDir)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		if ctx.OutputStructured() {
+			return ctx.WriteData(flyctl.ProjectContext{})
+		}
+		ctx.Statusf("context", cmdctx.SINFO, "No project context, select an app with `%s context set app=<name>`\n", flyname.Name())
+		return nil
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(project)
+	}
+
+	fmt.Fprintf(ctx.Out, "%15s: %s\n", "App", project.App)
+	fmt.Fprintf(ctx.Out, "%15s: %s\n", "Organization", project.Org)
+	fmt.Fprintf(ctx.Out, "%15s: %s\n", "Environment", project.Environment)
+	fmt.Fprintf(ctx.Out, "%15s: %s\n", "Saved in", helpers.PathRelativeToCWD(project.Path))
+
+	return nil
+}
+
+func runContextSet(ctx *cmdctx.CmdContext) error {
+	values, err := cmdutil.ParseKVStringsToMap(ctx.Args)
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid setting, use KEY=VALUE: %w", err)}
+	}
+
+	project, err := flyctl.FindProjectContext(ctx.WorkingDir)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		// ~/.fly is flyctl's config directory, not a project's
+		if home, err := os.UserHomeDir(); err == nil && filepath.Clean(ctx.WorkingDir) == filepath.Clean(home) {
+			return &ValidationError{fmt.Errorf("the home directory can't be a project, run this in the project's directory")}
+		}
+		project = flyctl.NewProjectContext(ctx.WorkingDir)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		switch key {
+		case "app":
+			if value != "" {
+				if _, err := ctx.Client.API().GetAppCompact(value); err != nil {
+					return fmt.Errorf("failed to find app %s: %w", value, err)
+				}
+			}
+			project.App = value
+		case "org":
+			if value != "" {
+				if _, err := selectOrganization(ctx.Client.API(), value, nil); err != nil {
+					return err
+				}
+			}
+			project.Org = value
+		case "environment":
+			project.Environment = value
+		default:
+			return &ValidationError{fmt.Errorf("unknown setting %q, use app, org or environment", key)}
+		}
+	}
+
+	if err := project.Save(); err != nil {
+		return err
+	}
+
+	ctx.Statusf("context", cmdctx.SDONE, "Saved %s in %s\n", strings.Join(ctx.Args, " "), helpers.PathRelativeToCWD(project.Path))
+
+	return nil
+}
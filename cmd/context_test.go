@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+)
+
+func TestApplyProjectContext(t *testing.T) {
+	dir := t.TempDir()
+	project := flyctl.NewProjectContext(dir)
+	project.App = "web-staging"
+	project.Org = "acme"
+	if err := project.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(name string, flags ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: name}
+		for _, flag := range flags {
+			cmd.Flags().String(flag, "", "")
+		}
+		t.Cleanup(func() {
+			for _, flag := range flags {
+				viper.SetDefault(namespace(cmd)+"."+flag, "")
+			}
+		})
+		return cmd
+	}
+	ctx := &cmdctx.CmdContext{WorkingDir: dir}
+
+	// without an app config, the context's app is the default of -a
+	webhooks := newCmd("contexttestwebhooks", "app", "org")
+	applyProjectContext(ctx, webhooks)
+	if got := viper.GetString(namespace(webhooks) + ".app"); got != "web-staging" {
+		t.Errorf("got app %q, want web-staging", got)
+	}
+	if got := viper.GetString(namespace(webhooks) + ".org"); got != "acme" {
+		t.Errorf("got org %q, want acme", got)
+	}
+
+	// with one, fly.toml's app comes first, so -a is left unset
+	status := newCmd("contextteststatus", "app", "config")
+	applyProjectContext(ctx, status)
+	if got := viper.GetString(namespace(status) + ".app"); got != "" {
+		t.Errorf("got app %q, want none", got)
+	}
+	if got := projectContextApp(ctx); got != "web-staging" {
+		t.Errorf("got context app %q, want web-staging", got)
+	}
+
+	// a malformed context is ignored
+	if err := os.WriteFile(filepath.Join(dir, ".fly", "context.toml"), []byte("app = "), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := newCmd("contexttestbroken", "app")
+	applyProjectContext(ctx, broken)
+	if got := viper.GetString(namespace(broken) + ".app"); got != "" {
+		t.Errorf("got app %q, want none", got)
+	}
+	if got := projectContextApp(ctx); got != "" {
+		t.Errorf("got context app %q, want none", got)
+	}
+}
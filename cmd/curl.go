@@ -8,16 +8,20 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 
 	"github.com/dustin/go-humanize"
-	"github.com/logrusorgru/aurora"
+	"github.com/olekukonko/tablewriter"
 	"github.com/superfly/flyctl/docstrings"
 
 	"github.com/spf13/cobra"
@@ -27,13 +31,24 @@ func newCurlCommand(client *client.Client) *Command {
 	curlStrings := docstrings.Get("curl")
 	cmd := BuildCommandKS(nil, runCurl, curlStrings, client, requireSession)
 	cmd.Args = cobra.ExactArgs(1)
-	cmd.Hidden = true
+
+	cmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "regions",
+		Shorthand:   "r",
+		Description: "Only time requests from these regions, e.g. --regions iad,lhr",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "select",
+		Description: "Choose the regions to time requests from a list",
+	})
+
 	return cmd
 }
 
 // TimingResponse - Results from timing a curl operations
 type TimingResponse struct {
-	Err               error
+	Err               error   `json:"-"`
+	Error             string  `json:"error,omitempty"`
 	HTTPCode          int     `json:"http_code"`
 	SpeedDownload     int     `json:"speed_download"`
 	TimeTotal         float64 `json:"time_total"`
@@ -54,12 +69,9 @@ type TimingRequest struct {
 	Region string `json:"region"`
 }
 
-func TimeRegions(ctx *cmdctx.CmdContext, url string, includeNoGateway bool) ([]api.Region, <-chan TimingResponse, error) {
-	regions, _, err := ctx.Client.API().PlatformRegions()
-	if err != nil {
-		return nil, nil, err
-	}
-
+// TimeRegions - times a request to url from each of regions in parallel,
+// sending the results as they arrive
+func TimeRegions(url string, regions []api.Region) <-chan TimingResponse {
 	results := make(chan TimingResponse, len(regions))
 
 	client := &http.Client{
@@ -67,19 +79,11 @@ func TimeRegions(ctx *cmdctx.CmdContext, url string, includeNoGateway bool) ([]a
 	}
 
 	var wg sync.WaitGroup
-	for _, r := range regions {
-		if includeNoGateway || r.GatewayAvailable {
-			wg.Add(1)
-		}
-	}
+	wg.Add(len(regions))
 
 	for _, region := range regions {
 		region := region
 
-		if !includeNoGateway && !region.GatewayAvailable {
-			continue
-		}
-
 		go func() {
 			defer wg.Done()
 
@@ -122,6 +126,10 @@ func TimeRegions(ctx *cmdctx.CmdContext, url string, includeNoGateway bool) ([]a
 				}
 			}
 
+			if timingResp.Err != nil {
+				timingResp.Error = timingResp.Err.Error()
+			}
+
 			results <- timingResp
 		}()
 	}
@@ -131,90 +139,144 @@ func TimeRegions(ctx *cmdctx.CmdContext, url string, includeNoGateway bool) ([]a
 		close(results)
 	}()
 
-	return regions, results, nil
+	return results
 }
 
 func runCurl(ctx *cmdctx.CmdContext) error {
 	url := ctx.Args[0]
 
-	_, results, err := TimeRegions(ctx, url, true)
+	regions, err := curlRegions(ctx)
 	if err != nil {
 		return err
 	}
 
-	var timingRowFormat = "%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
-	var timingRowErrorFormat = "%s\t%s\n"
-
+	timings := []TimingResponse{}
 	failures := []TimingResponse{}
-
-	fmt.Printf(timingRowFormat, "Region", "Status", "DNS", "Connect", "TLS", "TTFB", "Total")
-	for result := range results {
+	for result := range TimeRegions(url, regions) {
 		if result.Err != nil {
 			failures = append(failures, result)
 			continue
 		}
+		timings = append(timings, result)
+	}
+
+	// fastest first, so the best placed regions are at the top
+	sort.Slice(timings, func(i, j int) bool { return timings[i].TimeStartTransfer < timings[j].TimeStartTransfer })
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Region < failures[j].Region })
 
-		fmt.Printf(timingRowFormat,
+	if ctx.OutputStructured() {
+		return ctx.WriteData(append(timings, failures...))
+	}
+
+	names := map[string]string{}
+	for _, r := range regions {
+		names[r.Code] = r.Name
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Region", "Name", "Status", "DNS", "Connect", "TLS", "TTFB", "Total"})
+	for _, result := range timings {
+		row := []string{
 			result.Region,
-			formatHTTPStatus(result.HTTPCode),
-			formatDNS(result),
-			formatConnect(result),
-			formatTLS(result),
-			formatTTFB(result),
-			formatTotal(result),
-		)
+			names[result.Region],
+			strconv.Itoa(result.HTTPCode),
+			formatTiming(result.TimeNameLookup),
+			formatTiming(result.TimeConnect),
+			formatTiming(result.TimeAppConnect + result.TimePreTransfer),
+			formatTiming(result.TimeStartTransfer),
+			formatTiming(result.TimeTotal),
+		}
+		if !ctx.IO.ColorEnabled() {
+			table.Append(row)
+			continue
+		}
+		// the table colors cells after sizing the columns
+		table.Rich(row, []tablewriter.Colors{
+			{}, {},
+			statusColor(float64(result.HTTPCode), 299, 399),
+			{},
+			statusColor(result.TimeConnect*1000, 200, 500),
+			{},
+			statusColor(result.TimeStartTransfer*1000, 400, 1000),
+			{},
+		})
 	}
+	table.Render()
 
 	if len(failures) > 0 {
-		fmt.Println("\nFailures:")
+		fmt.Fprintln(ctx.Out, "\nFailures:")
 		for _, result := range failures {
-			fmt.Printf(timingRowErrorFormat, result.Region, result.Err)
+			fmt.Fprintf(ctx.Out, "%s\t%s\n", result.Region, result.Err)
 		}
 	}
 
 	return nil
 }
 
-func formatHTTPStatus(status int) interface{} {
-	text := strconv.Itoa(status)
-	return colorize(text, float64(status), 299, 399)
-}
+// curlRegions - the regions to time requests from: those given with
+// --regions, chosen with --select, or every region
+func curlRegions(ctx *cmdctx.CmdContext) ([]api.Region, error) {
+	regions, err := ctx.Client.API().PlatformRegionList()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i].Code < regions[j].Code })
 
-func formatDNS(result TimingResponse) interface{} {
-	return humanize.FtoaWithDigits(result.TimeNameLookup*1000, 1) + "ms"
-}
+	if codes := ctx.Config.GetStringSlice("regions"); len(codes) > 0 {
+		byCode := map[string]api.Region{}
+		for _, r := range regions {
+			byCode[r.Code] = r
+		}
 
-func formatConnect(result TimingResponse) interface{} {
-	timing := result.TimeConnect * 1000
-	text := humanize.FtoaWithDigits(timing, 1) + "ms"
-	return colorize(text, timing, 200, 500)
-}
+		selected := []api.Region{}
+		for _, code := range codes {
+			code = strings.ToLower(strings.TrimSpace(code))
+			region, ok := byCode[code]
+			if !ok {
+				return nil, &ValidationError{fmt.Errorf("unknown region %q, see `flyctl platform regions`", code)}
+			}
+			selected = append(selected, region)
+		}
+		return selected, nil
+	}
 
-func formatTLS(result TimingResponse) interface{} {
-	return humanize.FtoaWithDigits((result.TimeAppConnect+result.TimePreTransfer)*1000, 1) + "ms"
-}
+	if !ctx.Config.GetBool("select") {
+		return regions, nil
+	}
+
+	options := make([]string, 0, len(regions))
+	for _, r := range regions {
+		options = append(options, fmt.Sprintf("%s  %s", r.Code, r.Name))
+	}
 
-func formatTTFB(result TimingResponse) interface{} {
-	timing := result.TimeStartTransfer * 1000
-	text := humanize.FtoaWithDigits(timing, 1) + "ms"
-	return colorize(text, timing, 400, 1000)
+	indexes, err := prompt.MultiSelect("Select the regions to time requests from:", options, nil, "regions")
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no regions selected")
+	}
+
+	selected := make([]api.Region, 0, len(indexes))
+	for _, i := range indexes {
+		selected = append(selected, regions[i])
+	}
+	return selected, nil
 }
 
-func formatTotal(result TimingResponse) interface{} {
-	timing := result.TimeTotal * 1000
-	return humanize.FtoaWithDigits(timing, 1) + "ms"
+// formatTiming - a timing in seconds as milliseconds
+func formatTiming(seconds float64) string {
+	return humanize.FtoaWithDigits(seconds*1000, 1) + "ms"
 }
 
-func colorize(text string, val float64, greenCutoff float64, yellowCutoff float64) interface{} {
-	var color aurora.Color
+// statusColor - green up to greenCutoff, yellow up to yellowCutoff and red
+// beyond
+func statusColor(val float64, greenCutoff float64, yellowCutoff float64) tablewriter.Colors {
 	switch {
 	case val <= greenCutoff:
-		color = aurora.GreenFg
+		return tablewriter.Colors{tablewriter.FgGreenColor}
 	case val <= yellowCutoff:
-		color = aurora.YellowFg
+		return tablewriter.Colors{tablewriter.FgYellowColor}
 	default:
-		color = aurora.RedFg
+		return tablewriter.Colors{tablewriter.FgRedColor}
 	}
-
-	return aurora.Colorize(text, color)
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+func TestStatusColor(t *testing.T) {
+	tests := []struct {
+		val  float64
+		want int
+	}{
+		{val: 120, want: tablewriter.FgGreenColor},
+		{val: 200, want: tablewriter.FgGreenColor},
+		{val: 200.1, want: tablewriter.FgYellowColor},
+		{val: 500, want: tablewriter.FgYellowColor},
+		{val: 1500, want: tablewriter.FgRedColor},
+	}
+
+	for _, tt := range tests {
+		if got := statusColor(tt.val, 200, 500); !reflect.DeepEqual(got, tablewriter.Colors{tt.want}) {
+			t.Errorf("statusColor(%v) = %v, want %v", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTiming(t *testing.T) {
+	if got := formatTiming(0.0123); got != "12.3ms" {
+		t.Errorf("got %q, want 12.3ms", got)
+	}
+}
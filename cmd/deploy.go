@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,10 +11,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/dustin/go-humanize"
 	"github.com/logrusorgru/aurora"
-	"github.com/morikuni/aec"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/api"
@@ -21,12 +20,15 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/build/imgsrc"
 	"github.com/superfly/flyctl/internal/client"
 	"github.com/superfly/flyctl/internal/cmdfmt"
 	"github.com/superfly/flyctl/internal/cmdutil"
 	"github.com/superfly/flyctl/internal/deployment"
 	"github.com/superfly/flyctl/internal/monitor"
+	"github.com/superfly/flyctl/pkg/iostreams"
 	"github.com/superfly/flyctl/terminal"
 	"golang.org/x/sync/errgroup"
 )
@@ -41,12 +43,20 @@ func newDeployCommand(client *client.Client) *Command {
 	})
 	cmd.AddBoolFlag(BoolFlagOpts{
 		Name:        "detach",
-		Description: "Return immediately instead of monitoring deployment progress",
+		Description: "Return once the release is created instead of monitoring deployment progress, reattach with releases watch",
 	})
 	cmd.AddBoolFlag(BoolFlagOpts{
 		Name:   "build-only",
 		Hidden: true,
 	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "dry-run",
+		Description: "Build the image and validate the config, then show what the release would change without creating it",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "skip-build",
+		Description: "With --dry-run, only diff the config and the --image, if any, without building",
+	})
 	cmd.AddBoolFlag(BoolFlagOpts{
 		Name:        "remote-only",
 		Description: "Perform builds remotely without using the local docker daemon",
@@ -59,6 +69,10 @@ func newDeployCommand(client *client.Client) *Command {
 		Name:        "strategy",
 		Description: "The strategy for replacing running instances. Options are canary, rolling, bluegreen, or immediate. Default is canary",
 	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "process-group",
+		Description: "Only build and deploy the image for this process group",
+	})
 	cmd.AddStringFlag(StringFlagOpts{
 		Name:        "dockerfile",
 		Description: "Path to a Dockerfile. Defaults to the Dockerfile in the working directory.",
@@ -72,6 +86,18 @@ func newDeployCommand(client *client.Client) *Command {
 		Shorthand:   "e",
 		Description: "Set of environment variables in the form of NAME=VALUE pairs. Can be specified multiple times.",
 	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "env-file",
+		Description: "Read environment variables for this release from a file of NAME=VALUE lines. --env values take precedence.",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "message",
+		Description: "A message describing the release, shown in 'fly releases'",
+	})
+	cmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "label",
+		Description: "Annotate the release with a KEY=VALUE label. Can be specified multiple times.",
+	})
 	cmd.AddStringFlag(StringFlagOpts{
 		Name:        "image-label",
 		Description: "Image label to use when tagging and pushing to the fly registry. Defaults to \"deployment-{timestamp}\".",
@@ -85,29 +111,94 @@ func newDeployCommand(client *client.Client) *Command {
 		Description: "Do not use the cache when building the image",
 		Hidden:      true,
 	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "monitor-timeout",
+		Description: "How long to keep reconnecting to the deployment monitor after losing the connection",
+		Default:     "2m",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "wait-timeout",
+		Description: "How long instances get to pass their health checks before the deployment fails, overrides deploy.wait_timeout in fly.toml",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "health-check-grace",
+		Description: "Grace period for every health check in this deployment, overrides deploy.health_check_grace in fly.toml",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "guard",
+		Description: "Refuse to deploy while the app's health checks are failing or its instances are crashing, as deploy.guard in fly.toml",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "force",
+		Description: "Deploy even if the guard finds the app degraded",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "prewarm",
+		Description: "Pull the image onto hosts in every region before the release starts replacing instances",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "prewarm-timeout",
+		Description: "How long to wait for prewarming before deploying anyway",
+		Default:     "10m",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "watch-window",
+		Description: "How long to keep watching a healthy release for crash loops and flapping health checks, e.g. 30s. Off by default",
+		Default:     "0",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "github-status",
+		Description: "In GitHub Actions, record the deploy as a GitHub deployment and annotate the run with build and health check failures",
+	})
 
 	cmd.Command.Args = cobra.MaximumNArgs(1)
 
 	return cmd
 }
 
-func runDeploy(cmdCtx *cmdctx.CmdContext) error {
+func runDeploy(cmdCtx *cmdctx.CmdContext) (err error) {
 	ctx := createCancellableContext()
 
 	cmdCtx.Status("deploy", cmdctx.STITLE, "Deploying", cmdCtx.AppName)
 
+	gh := startGitHubDeployment(ctx, cmdCtx)
+	defer func() { gh.finish(err) }()
+
 	cmdfmt.PrintBegin(cmdCtx.Out, "Validating app configuration")
 
 	if cmdCtx.AppConfig == nil {
 		cmdCtx.AppConfig = flyctl.NewAppConfig()
 	}
 
-	if extraEnv := cmdCtx.Config.GetStringSlice("env"); len(extraEnv) > 0 {
-		parsedEnv, err := cmdutil.ParseKVStringsToMap(cmdCtx.Config.GetStringSlice("env"))
-		if err != nil {
-			return errors.Wrap(err, "invalid env")
-		}
-		cmdCtx.AppConfig.SetEnvVariables(parsedEnv)
+	releaseLabels, err := cmdutil.ParseKVStringsToMap(cmdCtx.Config.GetStringSlice("label"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid label")}
+	}
+
+	settings, err := deploySettings(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	dryRun := cmdCtx.Config.GetBool("dry-run")
+	skipBuild := cmdCtx.Config.GetBool("skip-build")
+	if skipBuild && !dryRun {
+		return &ValidationError{errors.New("--skip-build can only be used with --dry-run")}
+	}
+
+	if resolved, err := resolveEnvTemplates(cmdCtx); err != nil {
+		return err
+	} else if len(resolved) > 0 {
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Resolved templates in %s\n", strings.Join(resolved, ", "))
+	}
+
+	overrides, err := deployEnvOverrides(cmdCtx)
+	if err != nil {
+		return err
+	}
+	if len(overrides) > 0 {
+		cmdCtx.AppConfig.SetEnvVariables(overrides)
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Setting %s for this release only\n", strings.Join(sortedKeys(overrides), ", "))
 	}
 
 	parsedCfg, err := cmdCtx.Client.API().ParseConfig(cmdCtx.AppName, cmdCtx.AppConfig.Definition)
@@ -117,93 +208,130 @@ func runDeploy(cmdCtx *cmdctx.CmdContext) error {
 			return fmt.Errorf("not possible to validate configuration: server returned %s", err)
 		}
 		for _, error := range parsedCfg.Errors {
-			//	fmt.Println("   ", aurora.Red("✘").String(), error)
-			cmdCtx.Status("deploy", cmdctx.SERROR, "   ", aurora.Red("✘").String(), error)
+			cmdCtx.Status("deploy", cmdctx.SERROR, "   ", aurora.Red(iostreams.Glyph("✘")).String(), error)
 		}
-		return err
+		return &ValidationError{err}
 	}
 	cmdCtx.AppConfig.Definition = parsedCfg.Definition
 	cmdfmt.PrintDone(cmdCtx.Out, "Validating app configuration done")
 
+	if settings.GracePeriod > 0 {
+		n := cmdCtx.AppConfig.SetCheckGracePeriod(settings.GracePeriod)
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Using a %s grace period for %d health check(s)\n", settings.GracePeriod, n)
+	}
+
 	if parsedCfg.Valid && len(parsedCfg.Services) > 0 {
 		cmdfmt.PrintServicesList(cmdCtx.IO, parsedCfg.Services)
 	}
 
+	if settings.Guard && !dryRun {
+		if err := checkDeployGuard(cmdCtx, settings); err != nil {
+			return err
+		}
+	}
+
+	if skipBuild {
+		images := map[string]string{}
+		if image := cmdCtx.Config.GetString("image"); image != "" {
+			images[""] = image
+		}
+		return printDeployDryRun(cmdCtx, images, false)
+	}
+
+	gh.enter(githubPhaseBuild)
+
 	daemonType := imgsrc.NewDockerDaemonType(!cmdCtx.Config.GetBool("remote-only"), !cmdCtx.Config.GetBool("local-only"))
 	resolver := imgsrc.NewResolver(daemonType, cmdCtx.Client.API(), cmdCtx.AppName, cmdCtx.IO)
 
-	var img *imgsrc.DeploymentImage
-
-	var imageRef string
-	if ref := cmdCtx.Config.GetString("image"); ref != "" {
-		imageRef = ref
-	} else if ref := cmdCtx.AppConfig.Image(); ref != "" {
-		imageRef = ref
-	}
+	imageLabel := cmdCtx.Config.GetString("image-label")
+	groupBuilds := cmdCtx.AppConfig.ProcessGroupBuilds()
 
-	if imageRef != "" {
-		opts := imgsrc.RefOptions{
-			AppName:    cmdCtx.AppName,
-			WorkingDir: cmdCtx.WorkingDir,
-			AppConfig:  cmdCtx.AppConfig,
-			Publish:    !cmdCtx.Config.GetBool("build-only"),
-			ImageRef:   imageRef,
-			ImageLabel: cmdCtx.Config.GetString("image-label"),
-		}
+	var img *imgsrc.DeploymentImage
 
-		img, err = resolver.ResolveReference(ctx, cmdCtx.IO, opts)
-		if err != nil {
-			return err
+	// a single process group can be deployed on its own, leaving the images
+	// of every other group in place
+	if group := cmdCtx.Config.GetString("process-group"); group != "" {
+		groupBuild := &flyctl.Build{}
+		if b := groupBuilds[group]; b != nil {
+			*groupBuild = *b
+		} else if cmdCtx.AppConfig.Build != nil {
+			*groupBuild = *cmdCtx.AppConfig.Build
+			groupBuild.Processes = nil
 		}
-	} else {
-		opts := imgsrc.ImageOptions{
-			AppName:    cmdCtx.AppName,
-			WorkingDir: cmdCtx.WorkingDir,
-			AppConfig:  cmdCtx.AppConfig,
-			Publish:    !cmdCtx.Config.GetBool("build-only"),
-			ImageLabel: cmdCtx.Config.GetString("image-label"),
-			Target:     cmdCtx.Config.GetString("build-target"),
-			NoCache:    cmdCtx.Config.GetBool("no-cache"),
-		}
-		if dockerfilePath := cmdCtx.Config.GetString("dockerfile"); dockerfilePath != "" {
-			dockerfilePath, err := filepath.Abs(dockerfilePath)
-			if err != nil {
+		if dockerfile := cmdCtx.Config.GetString("dockerfile"); dockerfile != "" {
+			if groupBuild.Dockerfile, err = filepath.Abs(dockerfile); err != nil {
 				return err
 			}
-			opts.DockerfilePath = dockerfilePath
-		}
-
-		extraArgs, err := cmdutil.ParseKVStringsToMap(cmdCtx.Config.GetStringSlice("build-arg"))
-		if err != nil {
-			return errors.Wrap(err, "invalid build-arg")
 		}
-		opts.ExtraBuildArgs = extraArgs
-
-		img, err = resolver.BuildImage(ctx, cmdCtx.IO, opts)
+		groupBuilds = map[string]*flyctl.Build{group: groupBuild}
+	} else {
+		img, err = resolveDeploymentImage(ctx, cmdCtx, resolver, cmdCtx.AppConfig, cmdCtx.Config.GetString("image"), cmdCtx.Config.GetString("dockerfile"), imageLabel)
 		if err != nil {
 			return err
 		}
-		if img == nil {
-			return errors.New("could not find an image to deploy")
+
+		if !cmdCtx.OutputJSON() {
+			fmt.Fprintf(cmdCtx.Client.IO.Out, "Image: %s\n", img.Tag)
+			fmt.Fprintf(cmdCtx.Client.IO.Out, "Image size: %s\n", humanize.Bytes(uint64(img.Size)))
 		}
 	}
 
-	if img == nil {
-		return errors.New("could not find an image to deploy")
+	groupImages, err := buildProcessGroupImages(ctx, cmdCtx, resolver, groupBuilds, imageLabel)
+	if err != nil {
+		return err
 	}
 
-	fmt.Fprintf(cmdCtx.Client.IO.Out, "Image: %s\n", img.Tag)
-	fmt.Fprintf(cmdCtx.Client.IO.Out, "Image size: %s\n", humanize.Bytes(uint64(img.Size)))
+	printBuildSummary(cmdCtx, img, groupImages, resolver.BuilderMetrics(ctx))
+
+	if img != nil {
+		warnImageMismatches(cmdCtx, img, parsedCfg.Services)
+	}
 
 	if cmdCtx.Config.GetBool("build-only") {
 		return nil
 	}
 
+	if dryRun {
+		images := map[string]string{}
+		if img != nil {
+			images[""] = img.Tag
+		}
+		for group, groupImg := range groupImages {
+			images[group] = groupImg.Tag
+		}
+		return printDeployDryRun(cmdCtx, images, true)
+	}
+
+	if cmdCtx.Config.GetBool("prewarm") {
+		timeout, err := helpers.ParseDuration(cmdCtx.Config.GetString("prewarm-timeout"))
+		if err != nil {
+			return &ValidationError{errors.Wrap(err, "invalid prewarm timeout")}
+		}
+
+		images := []string{}
+		if img != nil {
+			images = append(images, img.Tag)
+		}
+		for _, groupImg := range groupImages {
+			images = append(images, groupImg.Tag)
+		}
+		prewarmImages(ctx, cmdCtx, images, timeout)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
 	cmdfmt.PrintBegin(cmdCtx.Out, "Creating release")
+	gh.enter(githubPhaseRelease)
 
 	input := api.DeployImageInput{
 		AppID: cmdCtx.AppName,
-		Image: img.Tag,
+	}
+	if img != nil {
+		input.Image = img.Tag
+	}
+	for group, groupImg := range groupImages {
+		input.ProcessGroupImages = append(input.ProcessGroupImages, api.ProcessGroupImageInput{Group: group, Image: groupImg.Tag})
 	}
 	if val := cmdCtx.Config.GetString("strategy"); val != "" {
 		input.Strategy = api.StringPointer(strings.ToUpper(val))
@@ -211,6 +339,23 @@ func runDeploy(cmdCtx *cmdctx.CmdContext) error {
 	if cmdCtx.AppConfig != nil && len(cmdCtx.AppConfig.Definition) > 0 {
 		input.Definition = api.DefinitionPtr(cmdCtx.AppConfig.Definition)
 	}
+	input.Message = cmdCtx.Config.GetString("message")
+	input.WaitTimeoutSeconds = int(settings.WaitTimeout.Seconds())
+	input.Labels = releaseLabels
+	if region, err := appPrimaryRegion(cmdCtx); err == nil {
+		input.PrimaryRegion = region
+	}
+
+	// the lock is held until the release command finishes, so concurrent
+	// deploys don't run migrations at the same time
+	migrationLocked := settings.MigrationLock && settings.ReleaseCommand != ""
+	releaseLock := func() {}
+	if migrationLocked {
+		if releaseLock, err = acquireMigrationLock(ctx, cmdCtx, settings); err != nil {
+			return err
+		}
+	}
+	defer func() { releaseLock() }()
 
 	release, releaseCommand, err := cmdCtx.Client.API().DeployImage(input)
 	if err != nil {
@@ -218,11 +363,15 @@ func runDeploy(cmdCtx *cmdctx.CmdContext) error {
 	}
 
 	fmt.Fprintf(cmdCtx.Out, "Release v%d created\n", release.Version)
+	gh.released(release.Version)
 	if releaseCommand != nil {
 		fmt.Fprintf(cmdCtx.Out, "Release command detected: this new release will not be available until the command succeeds.\n")
 	}
 
-	if cmdCtx.Config.GetBool("detach") {
+	detach := cmdCtx.Config.GetBool("detach")
+	if detach && !(migrationLocked && releaseCommand != nil) {
+		printDetached(cmdCtx, release)
+		gh.detach()
 		return nil
 	}
 
@@ -234,26 +383,277 @@ func runDeploy(cmdCtx *cmdctx.CmdContext) error {
 		fmt.Printf("Command: %s\n", releaseCommand.Command)
 
 		err = watchReleaseCommand(ctx, cmdCtx, cmdCtx.Client.API(), releaseCommand.ID)
+		if err != nil && !errors.Is(err, errReleaseCommandFailed) && migrationLocked {
+			// flyctl stopped watching but the command runs on, so leave the
+			// lock to expire rather than let another deploy migrate alongside it
+			releaseLock = func() {}
+			cmdCtx.Status("deploy", cmdctx.SWARN, "The release command may still be running, leaving the migration lock to expire")
+		}
 		if err != nil {
 			return err
 		}
 	}
 
+	releaseLock()
+	releaseLock = func() {}
+
+	if detach {
+		printDetached(cmdCtx, release)
+		gh.detach()
+		return nil
+	}
+
 	if release.DeploymentStrategy == "IMMEDIATE" {
 		terminal.Debug("immediate deployment strategy, nothing to monitor")
 		return nil
 	}
 
+	gh.enter(githubPhaseDeploy)
 	return watchDeployment(ctx, cmdCtx)
 }
 
+// deploySettings - the [deploy] settings of fly.toml, with --wait-timeout
+// and --health-check-grace taking precedence
+func deploySettings(cmdCtx *cmdctx.CmdContext) (flyctl.DeploySettings, error) {
+	settings, err := cmdCtx.AppConfig.DeploySettings()
+	if err != nil {
+		return settings, &ValidationError{err}
+	}
+
+	if value := cmdCtx.Config.GetString("wait-timeout"); value != "" {
+		if settings.WaitTimeout, err = helpers.ParseDuration(value); err != nil {
+			return settings, &ValidationError{errors.Wrap(err, "invalid wait timeout")}
+		}
+	}
+	if value := cmdCtx.Config.GetString("health-check-grace"); value != "" {
+		if settings.GracePeriod, err = helpers.ParseDuration(value); err != nil {
+			return settings, &ValidationError{errors.Wrap(err, "invalid health check grace period")}
+		}
+	}
+
+	if cmdCtx.Config.GetBool("guard") {
+		settings.Guard = true
+	}
+
+	if settings.WaitTimeout < 0 || settings.GracePeriod < 0 {
+		return settings, &ValidationError{errors.New("wait timeout and health check grace period can't be negative")}
+	}
+	if settings.WaitTimeout > 0 && settings.GracePeriod > settings.WaitTimeout {
+		return settings, &ValidationError{fmt.Errorf("grace period %s is longer than the wait timeout %s, checks would never get to pass", settings.GracePeriod, settings.WaitTimeout)}
+	}
+
+	return settings, nil
+}
+
+// deployEnvOverrides - the environment variables from --env-file and --env,
+// which wins, to merge into the release's env block
+func deployEnvOverrides(cmdCtx *cmdctx.CmdContext) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	if path := cmdCtx.Config.GetString("env-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &ValidationError{errors.Wrap(err, "invalid env-file")}
+		}
+		fileEnv, err := cmdutil.ParseEnvFile(string(data))
+		if err != nil {
+			return nil, &ValidationError{errors.Wrapf(err, "invalid env-file %s", path)}
+		}
+		for k, v := range fileEnv {
+			overrides[k] = v
+		}
+	}
+
+	if extraEnv := cmdCtx.Config.GetStringSlice("env"); len(extraEnv) > 0 {
+		parsedEnv, err := cmdutil.ParseKVStringsToMap(extraEnv)
+		if err != nil {
+			return nil, &ValidationError{errors.Wrap(err, "invalid env")}
+		}
+		for k, v := range parsedEnv {
+			overrides[k] = v
+		}
+	}
+
+	return overrides, nil
+}
+
+// resolveDeploymentImage finds or builds the image described by appConfig. An
+// explicit imageRef wins over one in the config, and dockerfile overrides the
+// config's Dockerfile when building.
+func resolveDeploymentImage(ctx context.Context, cmdCtx *cmdctx.CmdContext, resolver *imgsrc.Resolver, appConfig *flyctl.AppConfig, imageRef string, dockerfile string, imageLabel string) (*imgsrc.DeploymentImage, error) {
+	if imageRef == "" {
+		imageRef = appConfig.Image()
+	}
+
+	if imageRef != "" {
+		opts := imgsrc.RefOptions{
+			AppName:    cmdCtx.AppName,
+			WorkingDir: cmdCtx.WorkingDir,
+			AppConfig:  appConfig,
+			Publish:    !cmdCtx.Config.GetBool("build-only"),
+			ImageRef:   imageRef,
+			ImageLabel: imageLabel,
+		}
+
+		return resolver.ResolveReference(ctx, cmdCtx.IO, opts)
+	}
+
+	opts := imgsrc.ImageOptions{
+		AppName:    cmdCtx.AppName,
+		WorkingDir: cmdCtx.WorkingDir,
+		AppConfig:  appConfig,
+		Publish:    !cmdCtx.Config.GetBool("build-only"),
+		ImageLabel: imageLabel,
+		Target:     cmdCtx.Config.GetString("build-target"),
+		NoCache:    cmdCtx.Config.GetBool("no-cache"),
+	}
+
+	if dockerfile == "" && appConfig.Build != nil && appConfig.Build.Dockerfile != "" {
+		dockerfile = appConfig.Build.Dockerfile
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(cmdCtx.WorkingDir, dockerfile)
+		}
+	}
+	if dockerfile != "" {
+		dockerfilePath, err := filepath.Abs(dockerfile)
+		if err != nil {
+			return nil, err
+		}
+		opts.DockerfilePath = dockerfilePath
+	}
+
+	extraArgs, err := cmdutil.ParseKVStringsToMap(cmdCtx.Config.GetStringSlice("build-arg"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid build-arg")
+	}
+	opts.ExtraBuildArgs = extraArgs
+
+	img, err := resolver.BuildImage(ctx, cmdCtx.IO, opts)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, errors.New("could not find an image to deploy")
+	}
+
+	return img, nil
+}
+
+// warnImageMismatches - warns about the ways the image's exposed ports, user
+// and entrypoint disagree with fly.toml, before the release is created
+func warnImageMismatches(cmdCtx *cmdctx.CmdContext, img *imgsrc.DeploymentImage, services []api.Service) {
+	internalPorts := []int{}
+	for _, service := range services {
+		if service.InternalPort > 0 {
+			internalPorts = append(internalPorts, service.InternalPort)
+		}
+	}
+
+	_, hasProcesses := cmdCtx.AppConfig.Definition["processes"]
+
+	for _, warning := range img.Config.Mismatches(internalPorts, hasProcesses) {
+		cmdCtx.Status("deploy", cmdctx.SWARN, "Warning:", warning)
+	}
+}
+
+// buildSummary - the images a deploy built, and the remote builder's stats,
+// written as one JSON line with --json
+type buildSummary struct {
+	Image       string                 `json:"image,omitempty"`
+	ImageSize   int64                  `json:"imageSize,omitempty"`
+	GroupImages map[string]string      `json:"processGroupImages,omitempty"`
+	Builder     *imgsrc.BuilderMetrics `json:"builder,omitempty"`
+}
+
+// printBuildSummary - reports the remote builder's stats after building, or
+// with --json everything that was built
+func printBuildSummary(cmdCtx *cmdctx.CmdContext, img *imgsrc.DeploymentImage, groupImages map[string]*imgsrc.DeploymentImage, metrics *imgsrc.BuilderMetrics) {
+	if cmdCtx.OutputJSON() {
+		summary := buildSummary{Builder: metrics}
+		if img != nil {
+			summary.Image = img.Tag
+			summary.ImageSize = img.Size
+		}
+		for group, groupImg := range groupImages {
+			if summary.GroupImages == nil {
+				summary.GroupImages = map[string]string{}
+			}
+			summary.GroupImages[group] = groupImg.Tag
+		}
+
+		out, err := json.Marshal(map[string]buildSummary{"build": summary})
+		if err == nil {
+			fmt.Fprintln(cmdCtx.Out, string(out))
+		}
+		return
+	}
+
+	if metrics == nil {
+		return
+	}
+
+	cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Remote builder %s was ready after %s\n", metrics.Builder, (time.Duration(metrics.QueueWaitMs) * time.Millisecond).Round(100*time.Millisecond))
+	if metrics.CacheBytes >= 0 {
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Builder cache uses %s\n", humanize.Bytes(uint64(metrics.CacheBytes)))
+	}
+}
+
+// buildProcessGroupImages builds an image for every process group with build
+// settings of its own. Builds run in parallel, each tagged with the group name
+// so they don't overwrite one another in the registry.
+func buildProcessGroupImages(ctx context.Context, cmdCtx *cmdctx.CmdContext, resolver *imgsrc.Resolver, groupBuilds map[string]*flyctl.Build, imageLabel string) (map[string]*imgsrc.DeploymentImage, error) {
+	if len(groupBuilds) == 0 {
+		return nil, nil
+	}
+
+	if imageLabel == "" {
+		imageLabel = fmt.Sprintf("deployment-%d", time.Now().Unix())
+	}
+
+	var mu sync.Mutex
+	images := map[string]*imgsrc.DeploymentImage{}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	for group, groupBuild := range groupBuilds {
+		group := group
+		groupConfig := *cmdCtx.AppConfig
+		groupConfig.Build = groupBuild
+
+		g.Go(func() error {
+			cmdfmt.PrintBegin(cmdCtx.Out, fmt.Sprintf("Building image for process group %s", group))
+
+			img, err := resolveDeploymentImage(ctx, cmdCtx, resolver, &groupConfig, "", "", imageLabel+"-"+group)
+			if err != nil {
+				return errors.Wrapf(err, "process group %s", group)
+			}
+
+			if !cmdCtx.OutputJSON() {
+				fmt.Fprintf(cmdCtx.Client.IO.Out, "Image for %s: %s (%s)\n", group, img.Tag, humanize.Bytes(uint64(img.Size)))
+			}
+
+			mu.Lock()
+			images[group] = img
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+var errReleaseCommandFailed = errors.New("Release command failed, deployment aborted")
+
 func watchReleaseCommand(ctx context.Context, cc *cmdctx.CmdContext, apiClient *api.Client, id string) error {
 	g, ctx := errgroup.WithContext(ctx)
 	interactive := cc.IO.IsInteractive()
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Writer = os.Stderr
-	s.Prefix = "Running release task..."
+	s := iostreams.NewSpinner("Running release task...")
 
 	if interactive {
 		s.Start()
@@ -344,7 +744,7 @@ func watchReleaseCommand(ctx context.Context, cc *cmdctx.CmdContext, apiClient *
 				if rc.Succeeded && interactive {
 					s.FinalMSG = "Running release task...Done\n"
 				} else if rc.Failed {
-					return errors.New("Release command failed, deployment aborted")
+					return errReleaseCommandFailed
 				}
 			}
 		}
@@ -358,26 +758,41 @@ func watchReleaseCommand(ctx context.Context, cc *cmdctx.CmdContext, apiClient *
 func watchDeployment(ctx context.Context, cmdCtx *cmdctx.CmdContext) error {
 	cmdCtx.Status("deploy", cmdctx.STITLE, "Monitoring Deployment")
 
-	interactive := cmdCtx.IO.IsInteractive()
+	interactive := cmdCtx.IO.IsInteractive() && !cmdCtx.IO.Plain()
 
 	endmessage := ""
 
 	monitor := deployment.NewDeploymentMonitor(cmdCtx.Client.API(), cmdCtx.AppName)
 
+	if value := cmdCtx.Config.GetString("monitor-timeout"); value != "" {
+		timeout, err := helpers.ParseDuration(value)
+		if err != nil {
+			return errors.Wrap(err, "invalid monitor timeout")
+		}
+		monitor.ReconnectTimeout = timeout
+	}
+
+	monitor.Reconnecting = func(attempt int, err error) {
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "Lost connection to the deployment monitor, reconnecting (attempt %d): %s\n", attempt, err)
+	}
+
+	var table *allocationTable
+
 	monitor.DeploymentStarted = func(idx int, d *api.DeploymentStatus) error {
 		if idx > 0 {
 			cmdCtx.StatusLn()
 		}
 		cmdCtx.Status("deploy", cmdctx.SINFO, presenters.FormatDeploymentSummary(d))
+		table = newAllocationTable(cmdCtx.Out, cmdCtx.IO.IsStdoutTTY(), cmdCtx.Client.API(), cmdCtx.AppName)
 
 		return nil
 	}
 
 	monitor.DeploymentUpdated = func(d *api.DeploymentStatus, updatedAllocs []*api.AllocationStatus) error {
-		if interactive && !cmdCtx.OutputJSON() {
-			fmt.Fprint(cmdCtx.Out, aec.Up(1))
-			fmt.Fprint(cmdCtx.Out, aec.EraseLine(aec.EraseModes.All))
-			fmt.Fprintln(cmdCtx.Out, presenters.FormatDeploymentAllocSummary(d))
+		if interactive && !cmdCtx.OutputStructured() {
+			if err := table.Render(d); err != nil {
+				return err
+			}
 		} else {
 			for _, alloc := range updatedAllocs {
 				cmdCtx.Status("deploy", cmdctx.SINFO, presenters.FormatAllocSummary(alloc))
@@ -389,6 +804,7 @@ func watchDeployment(ctx context.Context, cmdCtx *cmdctx.CmdContext) error {
 
 	monitor.DeploymentFailed = func(d *api.DeploymentStatus, failedAllocs []*api.AllocationStatus) error {
 		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "v%d %s - %s\n", d.Version, d.Status, d.Description)
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Summary: %s\n", presenters.FormatDeploymentAllocSummary(d))
 
 		if endmessage == "" && d.Status == "failed" {
 			if strings.Contains(d.Description, "no stable release to revert to") {
@@ -400,72 +816,29 @@ func watchDeployment(ctx context.Context, cmdCtx *cmdctx.CmdContext) error {
 
 		if len(failedAllocs) > 0 {
 			cmdCtx.Status("deploy", cmdctx.STITLE, "Failed Instances")
-
-			x := make(chan *api.AllocationStatus)
-			var wg sync.WaitGroup
-			wg.Add(len(failedAllocs))
-
-			for _, a := range failedAllocs {
-				a := a
-				go func() {
-					defer wg.Done()
-					alloc, err := cmdCtx.Client.API().GetAllocationStatus(cmdCtx.AppName, a.ID, 30)
-					if err != nil {
-						cmdCtx.Status("deploy", cmdctx.SERROR, "Error fetching alloc", a.ID, err)
-						return
-					}
-					x <- alloc
-				}()
-			}
-
-			go func() {
-				wg.Wait()
-				close(x)
-			}()
-
-			count := 0
-			for alloc := range x {
-				count++
-				cmdCtx.StatusLn()
-				cmdCtx.Statusf("deploy", cmdctx.SBEGIN, "Failure #%d\n", count)
-				cmdCtx.StatusLn()
-
-				err := cmdCtx.Frender(
-					cmdctx.PresenterOption{
-						Title: "Instance",
-						Presentable: &presenters.Allocations{
-							Allocations: []*api.AllocationStatus{alloc},
-						},
-						Vertical: true,
-					},
-					cmdctx.PresenterOption{
-						Title: "Recent Events",
-						Presentable: &presenters.AllocationEvents{
-							Events: alloc.Events,
-						},
-					},
-				)
-				if err != nil {
-					return err
-				}
-
-				cmdCtx.Status("deploy", cmdctx.STITLE, "Recent Logs")
-				logPresenter := presenters.LogPresenter{HideAllocID: true, HideRegion: true, RemoveNewlines: true}
-				logPresenter.FPrint(cmdCtx.Out, cmdCtx.OutputJSON(), alloc.RecentLogs)
+			if err := printAllocationFailures(cmdCtx, failedAllocs); err != nil {
+				return err
 			}
-
 		}
 
 		return nil
 	}
 
+	var deployedVersion int
+
 	monitor.DeploymentSucceeded = func(d *api.DeploymentStatus) error {
 		cmdCtx.Statusf("deploy", cmdctx.SDONE, "v%d deployed successfully\n", d.Version)
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Summary: %s\n", presenters.FormatDeploymentAllocSummary(d))
+		deployedVersion = d.Version
 		return nil
 	}
 
 	monitor.Start(ctx)
 
+	if monitor.Disconnected() {
+		return reportDeploymentVerdict(cmdCtx, monitor)
+	}
+
 	if err := monitor.Error(); err != nil {
 		return err
 	}
@@ -476,8 +849,139 @@ func watchDeployment(ctx context.Context, cmdCtx *cmdctx.CmdContext) error {
 
 	if !monitor.Success() {
 		cmdCtx.Status("deploy", cmdctx.SINFO, "Troubleshooting guide at https://fly.io/docs/getting-started/troubleshooting/")
-		return ErrAbort
+		return ErrDeployFailed
+	}
+
+	if deployedVersion > 0 {
+		return watchRelease(ctx, cmdCtx, deployedVersion)
 	}
 
 	return nil
 }
+
+// watchRelease keeps watching a release for --watch-window after it's been
+// marked healthy, failing the deploy if its allocations start crash looping
+// or their health checks flap.
+func watchRelease(ctx context.Context, cmdCtx *cmdctx.CmdContext, version int) error {
+	window, err := helpers.ParseDuration(cmdCtx.Config.GetString("watch-window"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid watch window")}
+	}
+	if window <= 0 {
+		return nil
+	}
+
+	cmdCtx.Statusf("deploy", cmdctx.SINFO, "Watching v%d for %s for crash loops and failing health checks\n", version, window)
+
+	watchdog := deployment.NewWatchdog(cmdCtx.Client.API(), cmdCtx.AppName, version)
+	problems, err := watchdog.Watch(ctx, window)
+	if err != nil {
+		// the release itself succeeded, so don't fail the deploy over this
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "Stopped watching v%d: %s\n", version, err)
+		return nil
+	}
+
+	if len(problems) == 0 {
+		cmdCtx.Statusf("deploy", cmdctx.SDONE, "v%d stayed healthy\n", version)
+		return nil
+	}
+
+	cmdCtx.Statusf("deploy", cmdctx.SERROR, "v%d broke after it was deployed\n", version)
+	allocs := make([]*api.AllocationStatus, 0, len(problems))
+	for _, p := range problems {
+		cmdCtx.Statusf("deploy", cmdctx.SERROR, "%s %s\n", p.Alloc.IDShort, p.Reason)
+		allocs = append(allocs, p.Alloc)
+	}
+
+	cmdCtx.Status("deploy", cmdctx.STITLE, "Unstable Instances")
+	if err := printAllocationFailures(cmdCtx, allocs); err != nil {
+		return err
+	}
+
+	cmdCtx.Status("deploy", cmdctx.SINFO, "Troubleshooting guide at https://fly.io/docs/getting-started/troubleshooting/")
+	return ErrDeployFailed
+}
+
+// printAllocationFailures shows the details, recent events and logs of allocs
+func printAllocationFailures(cmdCtx *cmdctx.CmdContext, allocs []*api.AllocationStatus) error {
+	x := make(chan *api.AllocationStatus)
+	var wg sync.WaitGroup
+	wg.Add(len(allocs))
+
+	for _, a := range allocs {
+		a := a
+		go func() {
+			defer wg.Done()
+			alloc, err := cmdCtx.Client.API().GetAllocationStatus(cmdCtx.AppName, a.ID, 30)
+			if err != nil {
+				cmdCtx.Status("deploy", cmdctx.SERROR, "Error fetching alloc", a.ID, err)
+				return
+			}
+			x <- alloc
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(x)
+	}()
+
+	count := 0
+	for alloc := range x {
+		count++
+		cmdCtx.StatusLn()
+		cmdCtx.Statusf("deploy", cmdctx.SBEGIN, "Failure #%d\n", count)
+		cmdCtx.StatusLn()
+
+		err := cmdCtx.Frender(
+			cmdctx.PresenterOption{
+				Title: "Instance",
+				Presentable: &presenters.Allocations{
+					Allocations: []*api.AllocationStatus{alloc},
+				},
+				Vertical: true,
+			},
+			cmdctx.PresenterOption{
+				Title: "Recent Events",
+				Presentable: &presenters.AllocationEvents{
+					Events: alloc.Events,
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		cmdCtx.Status("deploy", cmdctx.STITLE, "Recent Logs")
+		logPresenter := presenters.LogPresenter{HideAllocID: true, HideRegion: true, RemoveNewlines: true}
+		logPresenter.FPrint(cmdCtx.Out, cmdCtx.OutputJSON(), alloc.RecentLogs)
+	}
+
+	return nil
+}
+
+// reportDeploymentVerdict asks the API how the deployment turned out after the
+// monitor gave up reconnecting, since the release carries on without us.
+func reportDeploymentVerdict(cmdCtx *cmdctx.CmdContext, monitor *deployment.DeploymentMonitor) error {
+	cmdCtx.Status("deploy", cmdctx.SWARN, "Could not reconnect to the deployment monitor, fetching the deployment's status")
+
+	d, err := monitor.FinalStatus()
+	if err != nil || d == nil {
+		if err == nil {
+			err = monitor.Error()
+		}
+		return errors.WithMessagef(err, "Unable to determine the outcome of the deployment, which continues server-side. Check on it with '%s status'", flyname.Name())
+	}
+
+	switch {
+	case d.InProgress:
+		return fmt.Errorf("v%d is still %s server-side, check on it with '%s releases watch %d'", d.Version, d.Status, flyname.Name(), d.Version)
+	case d.Successful:
+		cmdCtx.Statusf("deploy", cmdctx.SDONE, "v%d deployed successfully\n", d.Version)
+		return nil
+	default:
+		cmdCtx.Statusf("deploy", cmdctx.SERROR, "v%d %s - %s\n", d.Version, d.Status, d.Description)
+		cmdCtx.Status("deploy", cmdctx.SINFO, "Troubleshooting guide at https://fly.io/docs/getting-started/troubleshooting/")
+		return ErrDeployFailed
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/internal/deployment"
+)
+
+// dryRunImage - an image that would be replaced by a deploy, either side
+// empty when there's none. Images that can't be compared with the current
+// release are listed without Old.
+type dryRunImage struct {
+	Group    string `json:"group,omitempty"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+	Compared bool   `json:"compared"`
+}
+
+// printDeployDryRun - shows how the release a deploy would create differs
+// from the app's current one. images maps process groups to the images
+// that would be deployed, "" for the app's image, which is only compared
+// when resolved, rather than as given to --image.
+func printDeployDryRun(cmdCtx *cmdctx.CmdContext, images map[string]string, resolved bool) error {
+	apiClient := cmdCtx.Client.API()
+
+	current, err := apiClient.GetConfig(cmdCtx.AppName)
+	if err != nil {
+		return err
+	}
+	changes := deployment.DiffDefinitions(current.Definition, cmdCtx.AppConfig.Definition)
+
+	var currentImage string
+	releases, err := apiClient.GetAppReleases(cmdCtx.AppName, 1)
+	if err != nil {
+		return err
+	}
+	if len(releases) > 0 {
+		currentImage = releases[0].ImageRef
+	}
+
+	imageChanges := dryRunImages(images, currentImage, resolved)
+
+	if cmdCtx.OutputStructured() {
+		return cmdCtx.WriteData(map[string]interface{}{
+			"images":  imageChanges,
+			"changes": changes,
+		})
+	}
+
+	fmt.Fprintln(cmdCtx.Out)
+	cmdCtx.Statusf("deploy", cmdctx.STITLE, "Dry run, no release was created\n")
+
+	if len(imageChanges) == 0 && len(changes) == 0 {
+		cmdCtx.Statusf("deploy", cmdctx.SINFO, "%s is up to date, a release would change nothing\n", cmdCtx.AppName)
+		return nil
+	}
+
+	for _, change := range imageChanges {
+		name := "image"
+		if change.Group != "" {
+			name = fmt.Sprintf("image (%s)", change.Group)
+		}
+		if !change.Compared {
+			fmt.Fprintf(cmdCtx.Out, "%s %s: %s (not compared)\n", aurora.Yellow("?"), name, change.New)
+			continue
+		}
+		if change.Old != "" {
+			fmt.Fprintf(cmdCtx.Out, "%s %s: %s\n", aurora.Red("-"), name, change.Old)
+		}
+		fmt.Fprintf(cmdCtx.Out, "%s %s: %s\n", aurora.Green("+"), name, change.New)
+	}
+	for _, change := range changes {
+		if change.Old != "" {
+			fmt.Fprintf(cmdCtx.Out, "%s %s: %s\n", aurora.Red("-"), change.Key, change.Old)
+		}
+		if change.New != "" {
+			fmt.Fprintf(cmdCtx.Out, "%s %s: %s\n", aurora.Green("+"), change.Key, change.New)
+		}
+	}
+
+	compared := 0
+	for _, change := range imageChanges {
+		if change.Compared {
+			compared++
+		}
+	}
+
+	fmt.Fprintln(cmdCtx.Out)
+	cmdCtx.Statusf("deploy", cmdctx.SINFO, "%d setting(s) and %d image(s) would change, deploy without --dry-run to release them\n", len(changes), compared)
+	if uncompared := len(imageChanges) - compared; uncompared > 0 {
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "%d image(s) weren't compared with the current release, as its image for them isn't known\n", uncompared)
+	}
+	return nil
+}
+
+// dryRunImages - the images in images that would change, in group order.
+// Only the app's image, when resolved, can be compared with the current
+// release's: an unresolved --image names it differently, and process group
+// images are only known for the release they're in.
+func dryRunImages(images map[string]string, currentImage string, resolved bool) []dryRunImage {
+	groups := make([]string, 0, len(images))
+	for group := range images {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	changes := []dryRunImage{}
+	for _, group := range groups {
+		change := dryRunImage{Group: group, New: images[group]}
+		if group == "" && resolved {
+			change.Old = currentImage
+			change.Compared = true
+			if change.Old == change.New {
+				continue
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDryRunImages(t *testing.T) {
+	const current = "registry.fly.io/app:deployment-1"
+
+	tests := []struct {
+		name     string
+		images   map[string]string
+		resolved bool
+		want     []dryRunImage
+	}{
+		{
+			name:     "unchanged",
+			images:   map[string]string{"": current},
+			resolved: true,
+			want:     []dryRunImage{},
+		},
+		{
+			name:     "changed",
+			images:   map[string]string{"": "registry.fly.io/app:deployment-2"},
+			resolved: true,
+			want:     []dryRunImage{{Old: current, New: "registry.fly.io/app:deployment-2", Compared: true}},
+		},
+		{
+			name:   "unresolved",
+			images: map[string]string{"": "app:deployment-1"},
+			want:   []dryRunImage{{New: "app:deployment-1"}},
+		},
+		{
+			name:     "process groups",
+			images:   map[string]string{"worker": "registry.fly.io/app:worker", "": current, "web": "registry.fly.io/app:web"},
+			resolved: true,
+			want: []dryRunImage{
+				{Group: "web", New: "registry.fly.io/app:web"},
+				{Group: "worker", New: "registry.fly.io/app:worker"},
+			},
+		},
+		{
+			name:     "nothing built",
+			images:   map[string]string{},
+			resolved: true,
+			want:     []dryRunImage{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := dryRunImages(tt.images, current, tt.resolved); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/internal/ghactions"
+)
+
+// Deploy phases, used to title the annotation of a failure
+const (
+	githubPhaseValidate = "Config validation failed"
+	githubPhaseBuild    = "Build failed"
+	githubPhaseRelease  = "Release failed"
+	githubPhaseDeploy   = "Deployment failed"
+)
+
+// githubDeployment - the GitHub deployment record of a deploy run with
+// --github-status. Its methods do nothing on a nil githubDeployment.
+type githubDeployment struct {
+	cmdCtx  *cmdctx.CmdContext
+	run     *ghactions.Run
+	id      int64
+	phase   string
+	version int
+}
+
+// startGitHubDeployment - records the deploy as a GitHub deployment of the
+// workflow's commit, or returns nil without --github-status or when that
+// fails. A deploy goes ahead either way.
+func startGitHubDeployment(ctx context.Context, cmdCtx *cmdctx.CmdContext) *githubDeployment {
+	// nothing is deployed by a dry run or a build
+	if !cmdCtx.Config.GetBool("github-status") || cmdCtx.Config.GetBool("dry-run") || cmdCtx.Config.GetBool("build-only") {
+		return nil
+	}
+
+	run, err := ghactions.FromEnv(os.Getenv)
+	if err != nil {
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "Not reporting to GitHub: %s\n", err)
+		return nil
+	}
+
+	id, err := run.CreateDeployment(ctx, cmdCtx.AppName, fmt.Sprintf("Deploying %s with flyctl", cmdCtx.AppName))
+	if err != nil {
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "Failed to create a GitHub deployment: %s\n", err)
+		return nil
+	}
+
+	gh := &githubDeployment{cmdCtx: cmdCtx, run: run, id: id, phase: githubPhaseValidate}
+	gh.setStatus(ctx, ghactions.StateInProgress, "Validating the app configuration", "")
+	return gh
+}
+
+// enter - marks the phase the deploy is in
+func (gh *githubDeployment) enter(phase string) {
+	if gh != nil {
+		gh.phase = phase
+	}
+}
+
+// released - notes the version the deploy released
+func (gh *githubDeployment) released(version int) {
+	if gh != nil {
+		gh.version = version
+	}
+}
+
+// detach - leaves the deployment in progress, since a detached deploy
+// doesn't see how it ends
+func (gh *githubDeployment) detach() {
+	if gh == nil {
+		return
+	}
+	gh.setStatus(context.Background(), ghactions.StateInProgress, fmt.Sprintf("Released %s v%d, its rollout isn't monitored", gh.cmdCtx.AppName, gh.version), "")
+	gh.run = nil
+}
+
+// finish - sets the deployment's final status from the deploy's outcome
+// and annotates the workflow run with what failed
+func (gh *githubDeployment) finish(err error) {
+	if gh == nil || gh.run == nil {
+		return
+	}
+	// the deploy's own context may have been cancelled
+	ctx := context.Background()
+
+	if err == nil {
+		var appURL string
+		if app, err := gh.cmdCtx.Client.API().GetAppCompact(gh.cmdCtx.AppName); err == nil && app.Hostname != "" {
+			appURL = "https://" + app.Hostname
+		}
+		description := fmt.Sprintf("Deployed %s", gh.cmdCtx.AppName)
+		if gh.version > 0 {
+			description = fmt.Sprintf("Deployed %s v%d", gh.cmdCtx.AppName, gh.version)
+		}
+		gh.setStatus(ctx, ghactions.StateSuccess, description, appURL)
+		return
+	}
+
+	ghactions.Annotate(os.Stdout, ghactions.LevelError, gh.phase, err.Error())
+	if gh.phase == githubPhaseDeploy {
+		gh.annotateFailingChecks()
+	}
+
+	state := ghactions.StateFailure
+	if gh.phase == githubPhaseValidate {
+		state = ghactions.StateError
+	}
+	gh.setStatus(ctx, state, fmt.Sprintf("%s: %s", gh.phase, err), "")
+}
+
+// annotateFailingChecks - annotates the health checks failing on the
+// released version's instances
+func (gh *githubDeployment) annotateFailingChecks() {
+	status, err := gh.cmdCtx.Client.API().GetAppStatus(gh.cmdCtx.AppName, true)
+	if err != nil {
+		return
+	}
+	for _, alloc := range status.Allocations {
+		if alloc.Version != gh.version {
+			continue
+		}
+		for _, check := range alloc.Checks {
+			if check.Status != "critical" {
+				continue
+			}
+			title := fmt.Sprintf("Health check %s failed on %s in %s", check.Name, alloc.IDShort, alloc.Region)
+			ghactions.Annotate(os.Stdout, ghactions.LevelError, title, strings.TrimSpace(check.Output))
+		}
+	}
+}
+
+func (gh *githubDeployment) setStatus(ctx context.Context, state string, description string, environmentURL string) {
+	if err := gh.run.CreateDeploymentStatus(ctx, gh.id, state, description, environmentURL); err != nil {
+		gh.cmdCtx.Statusf("deploy", cmdctx.SWARN, "Failed to update the GitHub deployment: %s\n", err)
+	}
+}
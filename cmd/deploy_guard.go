@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/deployment"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+// checkDeployGuard - refuses the deploy when the app is already degraded,
+// unless it's forced or the user acknowledges it
+func checkDeployGuard(cmdCtx *cmdctx.CmdContext, settings flyctl.DeploySettings) error {
+	status, err := cmdCtx.Client.API().GetAppStatus(cmdCtx.AppName, true)
+	if err != nil {
+		return fmt.Errorf("check the app's health before deploying: %w", err)
+	}
+	if !status.Deployed {
+		return nil
+	}
+
+	guard := deployment.Guard{Window: settings.GuardWindow, ErrorBudget: settings.ErrorBudget}
+	reasons := guard.Assess(status.Allocations, time.Now())
+	if len(reasons) == 0 {
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Guard: %s is healthy\n", cmdCtx.AppName)
+		return nil
+	}
+
+	cmdCtx.Statusf("deploy", cmdctx.SWARN, "%s is degraded:\n", cmdCtx.AppName)
+	for _, reason := range reasons {
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "  %s\n", reason)
+	}
+
+	if cmdCtx.Config.GetBool("force") {
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "Deploying anyway because of --force\n")
+		return nil
+	}
+
+	confirmed, err := prompt.Confirm("Deploy on top of the degraded release anyway?", "force")
+	if prompt.IsNonInteractiveError(err) {
+		return errors.New("refusing to deploy while the app is degraded, use --force to deploy anyway")
+	} else if err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrAbort
+	}
+
+	return nil
+}
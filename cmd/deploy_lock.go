@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/deployment"
+	"github.com/superfly/flyctl/terminal"
+)
+
+const (
+	// defaultMigrationLockTimeout - how long to wait for another deploy's
+	// migrations without deploy.migration_lock_timeout
+	defaultMigrationLockTimeout = 10 * time.Minute
+	// migrationLockTTL - when a lock left behind by a deploy that died
+	// expires
+	migrationLockTTL = time.Hour
+	// migrationLockPoll - how often to try for a held lock
+	migrationLockPoll = 5 * time.Second
+)
+
+// acquireMigrationLock - waits for the app's migration lock, returning a
+// func that releases it. The error names the holder when the lock couldn't
+// be had in time.
+func acquireMigrationLock(ctx context.Context, cmdCtx *cmdctx.CmdContext, settings flyctl.DeploySettings) (func(), error) {
+	timeout := settings.MigrationLockTimeout
+	if timeout <= 0 {
+		timeout = defaultMigrationLockTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	var email string
+	if user, err := cmdCtx.Client.API().GetCurrentUser(); err == nil {
+		email = user.Email
+	}
+	hostname, _ := os.Hostname()
+	holder := deployment.LockHolder(email, hostname, os.Getenv)
+
+	var waitingFor string
+	for {
+		lock, acquired, err := cmdCtx.Client.API().AcquireAppLock(cmdCtx.AppName, deployment.MigrationLockName, holder, int(migrationLockTTL.Seconds()))
+		if err != nil {
+			return nil, fmt.Errorf("acquire the migration lock: %w", err)
+		}
+
+		if acquired {
+			cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "Acquired the migration lock\n")
+			return func() {
+				if err := cmdCtx.Client.API().ReleaseAppLock(lock.ID); err != nil {
+					terminal.Warnf("Failed to release the migration lock, it expires %s: %v\n", humanize.Time(lock.ExpiresAt), err)
+				}
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the migration lock is held by %s since %s, it expires %s unless released", lock.Holder, humanize.Time(lock.AcquiredAt), humanize.Time(lock.ExpiresAt))
+		}
+		if lock.Holder != waitingFor {
+			waitingFor = lock.Holder
+			cmdCtx.Statusf("deploy", cmdctx.SWARN, "Waiting up to %s for the migration lock, held by %s since %s\n", timeout, lock.Holder, humanize.Time(lock.AcquiredAt))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(migrationLockPoll):
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+)
+
+// prewarmPollInterval - how often prewarm progress is checked
+const prewarmPollInterval = 2 * time.Second
+
+// prewarmImages - pulls each image onto hosts in the app's regions before
+// the release starts replacing instances, so large images don't hold up
+// each region in turn. Prewarming is best effort: failures and timeouts are
+// reported, and the deploy carries on.
+func prewarmImages(ctx context.Context, cmdCtx *cmdctx.CmdContext, images []string, timeout time.Duration) {
+	for _, image := range images {
+		if err := prewarmImage(ctx, cmdCtx, image, timeout); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			cmdCtx.Statusf("deploy", cmdctx.SWARN, "Prewarming %s failed, continuing without it: %s\n", image, err)
+		}
+	}
+}
+
+func prewarmImage(ctx context.Context, cmdCtx *cmdctx.CmdContext, image string, timeout time.Duration) error {
+	prewarm, err := cmdCtx.Client.API().PrewarmImage(cmdCtx.AppName, image)
+	if err != nil {
+		return err
+	}
+
+	cmdCtx.Statusf("deploy", cmdctx.SBEGIN, "Prewarming %s in %d region(s)\n", image, len(prewarm.Regions))
+
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reported := map[string]string{}
+	for {
+		done := true
+		for _, r := range prewarm.Regions {
+			if !r.Done() {
+				done = false
+			}
+			if reported[r.Region] == r.Status {
+				continue
+			}
+			reported[r.Region] = r.Status
+			printPrewarmRegion(cmdCtx, r, time.Since(start))
+		}
+
+		if done {
+			break
+		}
+
+		select {
+		case <-time.After(prewarmPollInterval):
+		case <-waitCtx.Done():
+			if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%s isn't prewarmed everywhere after %s", image, timeout)
+			}
+			return waitCtx.Err()
+		}
+
+		if prewarm, err = cmdCtx.Client.API().GetImagePrewarm(waitCtx, prewarm.ID); err != nil {
+			return err
+		}
+	}
+
+	warm := 0
+	for _, r := range prewarm.Regions {
+		if r.Status == "complete" {
+			warm++
+		}
+	}
+	cmdCtx.Statusf("deploy", cmdctx.SDONE, "Prewarmed %s in %d of %d region(s) in %s\n", image, warm, len(prewarm.Regions), time.Since(start).Round(time.Second))
+
+	return nil
+}
+
+func printPrewarmRegion(cmdCtx *cmdctx.CmdContext, r api.ImagePrewarmRegion, elapsed time.Duration) {
+	switch r.Status {
+	case "pulling":
+		size := ""
+		if r.BytesTotal > 0 {
+			size = fmt.Sprintf(", %s of %s", humanize.Bytes(uint64(r.BytesPulled)), humanize.Bytes(uint64(r.BytesTotal)))
+		}
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "%s: pulling%s\n", r.Region, size)
+	case "complete":
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "%s: ready after %s\n", r.Region, elapsed.Round(time.Second))
+	case "failed":
+		cmdCtx.Statusf("deploy", cmdctx.SWARN, "%s: failed, instances there will pull the image as they start: %s\n", r.Region, r.Error)
+	default:
+		cmdCtx.Statusf("deploy", cmdctx.SDETAIL, "%s: %s\n", r.Region, r.Status)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/morikuni/aec"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmd/presenters"
+)
+
+// oldAllocsInterval - how often the allocations of previous versions are
+// fetched while the live table is redrawn
+const oldAllocsInterval = 5 * time.Second
+
+// allocationTable - a live view of a deployment's allocations alongside those
+// of the versions it's replacing, redrawn in place as they change when out is
+// a terminal, and written again below otherwise
+type allocationTable struct {
+	out    io.Writer
+	tty    bool
+	client *api.Client
+	appID  string
+
+	lines     int
+	old       []*api.AllocationStatus
+	fetchedAt time.Time
+}
+
+func newAllocationTable(out io.Writer, tty bool, client *api.Client, appID string) *allocationTable {
+	return &allocationTable{out: out, tty: tty, client: client, appID: appID}
+}
+
+// Render - redraws the table over the previous one on a terminal
+func (t *allocationTable) Render(d *api.DeploymentStatus) error {
+	allocs := make([]*api.AllocationStatus, 0, len(d.Allocations))
+	for _, alloc := range d.Allocations {
+		a := *alloc
+		a.LatestVersion = true
+		allocs = append(allocs, &a)
+	}
+	allocs = append(allocs, t.oldAllocations(d.Version)...)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, presenters.FormatDeploymentAllocSummary(d))
+	if len(allocs) > 0 {
+		presenter := &presenters.Presenter{
+			Item: &presenters.Allocations{Allocations: allocs},
+			Out:  &buf,
+		}
+		if err := presenter.Render(); err != nil {
+			return err
+		}
+	}
+
+	if t.tty {
+		for i := 0; i < t.lines; i++ {
+			fmt.Fprint(t.out, aec.Up(1))
+			fmt.Fprint(t.out, aec.EraseLine(aec.EraseModes.All))
+		}
+		t.lines = strings.Count(buf.String(), "\n")
+	}
+
+	_, err := buf.WriteTo(t.out)
+	return err
+}
+
+// oldAllocations - the running allocations of versions other than version,
+// refreshed every oldAllocsInterval. They're left out if they can't be fetched.
+func (t *allocationTable) oldAllocations(version int) []*api.AllocationStatus {
+	if time.Since(t.fetchedAt) < oldAllocsInterval {
+		return t.old
+	}
+	t.fetchedAt = time.Now()
+
+	status, err := t.client.GetAppStatus(t.appID, false)
+	if err != nil {
+		return t.old
+	}
+
+	t.old = nil
+	for _, alloc := range status.Allocations {
+		if alloc.Version != version {
+			t.old = append(t.old, alloc)
+		}
+	}
+
+	return t.old
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/internal/deprecation"
+)
+
+// deprecations - commands and flags on their way out, registered as they're
+// deprecated. They're hidden from help unless --show-deprecated is given and
+// warn whenever they're used.
+var deprecations = &deprecation.Registry{}
+
+// applyDeprecations - hides deprecated commands and flags in the tree under
+// root, and shows them again in help when --show-deprecated is given
+func applyDeprecations(root *cobra.Command) {
+	var hidden []func(bool)
+
+	for _, n := range deprecations.All() {
+		cmd, _, err := root.Find(strings.Fields(n.Command))
+		if err != nil || cmd == root {
+			checkErr(fmt.Errorf("deprecation registered for unknown command %q", n.Command))
+		}
+
+		if n.Flag == "" {
+			cmd.Hidden = true
+			hidden = append(hidden, func(h bool) { cmd.Hidden = h })
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(n.Flag)
+		if flag == nil {
+			checkErr(fmt.Errorf("deprecation registered for unknown flag --%s of %q", n.Flag, n.Command))
+		}
+		flag.Hidden = true
+		hidden = append(hidden, func(h bool) { flag.Hidden = h })
+	}
+
+	help := root.HelpFunc()
+	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if show, _ := cmd.Flags().GetBool("show-deprecated"); show {
+			for _, setHidden := range hidden {
+				setHidden(false)
+			}
+		}
+		help(cmd, args)
+	})
+}
+
+// commandPath - the command's path without the binary name, as deprecations
+// are registered
+func commandPath(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+	return strings.Join(path[1:], " ")
+}
+
+// warnDeprecated - warns about any deprecated command or flag being used.
+// With --json the notices are written to stderr as JSON lines.
+func warnDeprecated(ctx *cmdctx.CmdContext, cmd *cobra.Command) {
+	var setFlags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		setFlags = append(setFlags, f.Name)
+	})
+
+	for _, n := range deprecations.ForCommand(commandPath(cmd), setFlags) {
+		if ctx.OutputJSON() {
+			out, _ := json.Marshal(struct {
+				Deprecation deprecation.Notice `json:"deprecation"`
+				Message     string             `json:"message"`
+			}{n, n.Message()})
+			fmt.Fprintln(ctx.IO.ErrOut, string(out))
+			continue
+		}
+
+		ctx.Statusf("flyctl", cmdctx.SWARN, "Warning: %s\n", n.Message())
+	}
+}
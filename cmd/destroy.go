@@ -6,10 +6,10 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 //TODO: Move all output to status styled begin/done updates
@@ -23,6 +23,7 @@ func newDestroyCommand(client *client.Client) *Command {
 	destroy.Args = cobra.ExactArgs(1)
 
 	destroy.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+	addAppOrgFlag(destroy)
 
 	return destroy
 }
@@ -30,20 +31,19 @@ func newDestroyCommand(client *client.Client) *Command {
 func runDestroy(ctx *cmdctx.CmdContext) error {
 	appName := ctx.Args[0]
 
+	if err := checkAppOrg(ctx, appName); err != nil {
+		return err
+	}
+
 	if !ctx.Config.GetBool("yes") {
 		fmt.Println(aurora.Red("Destroying an app is not reversible."))
 
-		confirm := false
-		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Destroy app %s?", appName),
-		}
-		err := survey.AskOne(prompt, &confirm)
-
+		confirmed, err := prompt.Confirm(fmt.Sprintf("Destroy app %s?", appName), "yes")
 		if err != nil {
 			return err
 		}
 
-		if !confirm {
+		if !confirmed {
 			return nil
 		}
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/internal/client"
@@ -47,9 +48,8 @@ func runRecordsList(ctx *cmdctx.CmdContext) error {
 
 	fmt.Printf("Records for domain %s\n", name)
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(records)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(records)
 	}
 
 	table := tablewriter.NewWriter(ctx.Out)
@@ -150,6 +150,13 @@ func runRecordsImport(ctx *cmdctx.CmdContext) error {
 		fmt.Printf("Imported from %s\n", filename)
 	}
 
+	printZoneImportReport(warnings, changes)
+
+	return nil
+}
+
+// printZoneImportReport - prints the warnings and changes of a zone import
+func printZoneImportReport(warnings []api.ImportDnsWarning, changes []api.ImportDnsChange) {
 	fmt.Printf("%d warnings\n", len(warnings))
 	for _, warning := range warnings {
 		fmt.Println("->", warning.Action, warning.Message)
@@ -166,6 +173,4 @@ func runRecordsImport(ctx *cmdctx.CmdContext) error {
 			fmt.Println("-> Updated", change.OldText, "=>", change.NewText)
 		}
 	}
-
-	return nil
 }
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
+	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/docgen"
 
 	"github.com/superfly/flyctl/docstrings"
 
@@ -13,7 +18,24 @@ import (
 
 func newDocsCommand(client *client.Client) *Command {
 	docsStrings := docstrings.Get("docs")
-	return BuildCommand(nil, runLaunchDocs, docsStrings.Usage, docsStrings.Short, docsStrings.Long, client)
+	cmd := BuildCommand(nil, runLaunchDocs, docsStrings.Usage, docsStrings.Short, docsStrings.Long, client)
+
+	generateStrings := docstrings.Get("docs.generate")
+	generateCmd := BuildCommandKS(cmd, nil, generateStrings, client)
+	generateCmd.Args = cobra.NoArgs
+	generateCmd.AddBoolFlag(BoolFlagOpts{Name: "man", Description: "Generate man pages"})
+	generateCmd.AddBoolFlag(BoolFlagOpts{Name: "markdown", Description: "Generate markdown pages"})
+	generateCmd.AddStringFlag(StringFlagOpts{Name: "dir", Description: "The directory to write the pages to, created if it doesn't exist", Default: "."})
+	// the pages are generated from the whole command tree, so this runs
+	// with the cobra command rather than a command context
+	generateCmd.RunE = func(c *cobra.Command, args []string) error {
+		man, _ := c.Flags().GetBool("man")
+		markdown, _ := c.Flags().GetBool("markdown")
+		dir, _ := c.Flags().GetString("dir")
+		return generateDocs(c.Root(), dir, man, markdown)
+	}
+
+	return cmd
 }
 
 const docsURL = "https://fly.io/docs/"
@@ -22,3 +44,31 @@ func runLaunchDocs(ctx *cmdctx.CmdContext) error {
 	fmt.Println("Opening", docsURL)
 	return open.Run(docsURL)
 }
+
+func generateDocs(root *cobra.Command, dir string, man, markdown bool) error {
+	if man == markdown {
+		return &ValidationError{errors.New("choose one of --man or --markdown")}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// the markdown footer's date would make every page differ between runs
+	root.DisableAutoGenTag = true
+
+	if man {
+		header := &docgen.ManHeader{
+			Source: fmt.Sprintf("%s v%s", root.Name(), flyctl.Version),
+			Manual: "Flyctl Manual",
+		}
+		if err := docgen.GenManTree(root, header, dir); err != nil {
+			return err
+		}
+	} else if err := docgen.GenMarkdownTree(root, dir); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(root.OutOrStdout(), "Wrote the reference for %s v%s to %s\n", root.Name(), flyctl.Version, dir)
+	return nil
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/dustin/go-humanize"
@@ -13,7 +15,10 @@ import (
 	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func newDomainsCommand(client *client.Client) *Command {
@@ -33,9 +38,53 @@ func newDomainsCommand(client *client.Client) *Command {
 	registerCmd := BuildCommandKS(cmd, runDomainsRegister, docstrings.Get("domains.register"), client, requireSession)
 	registerCmd.Args = cobra.MaximumNArgs(2)
 
+	autoRenewCmd := BuildCommandKS(cmd, nil, docstrings.Get("domains.autorenew"), client, requireSession)
+
+	enableCmd := BuildCommandKS(autoRenewCmd, runDomainsAutoRenewEnable, docstrings.Get("domains.autorenew.enable"), client, requireSession)
+	enableCmd.Args = cobra.ExactArgs(1)
+
+	disableCmd := BuildCommandKS(autoRenewCmd, runDomainsAutoRenewDisable, docstrings.Get("domains.autorenew.disable"), client, requireSession)
+	disableCmd.Args = cobra.ExactArgs(1)
+	disableCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	expiringCmd := BuildCommandKS(cmd, runDomainsExpiring, docstrings.Get("domains.expiring"), client, requireSession)
+	expiringCmd.Args = cobra.MaximumNArgs(1)
+	expiringCmd.AddStringFlag(StringFlagOpts{
+		Name:        "within",
+		Description: "Show domains expiring within this window, e.g. 60d or 720h",
+		Default:     "30d",
+	})
+
+	zonesCmd := BuildCommandKS(cmd, nil, docstrings.Get("domains.zones"), client, requireSession)
+
+	zonesImportCmd := BuildCommandKS(zonesCmd, runDomainsZonesImport, docstrings.Get("domains.zones.import"), client, requireSession)
+	zonesImportCmd.Args = cobra.ExactArgs(2)
+	zonesImportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "from-provider",
+		Description: "The DNS provider hosting the zone now: cloudflare or route53",
+	})
+	zonesImportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "token",
+		Description: "Cloudflare API token with DNS read access",
+		EnvName:     "CLOUDFLARE_API_TOKEN",
+	})
+	zonesImportCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "watch",
+		Description: "After importing, wait for the domain's nameservers to switch to Fly",
+	})
+	zonesImportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "watch-timeout",
+		Description: "How long to wait for the nameservers to switch with --watch",
+		Default:     "1h",
+	})
+
 	return cmd
 }
 
+// domainRenewalReminderWindow is how close to expiry a domain without auto
+// renew has to be before listings call it out
+const domainRenewalReminderWindow = 30 * 24 * time.Hour
+
 func runDomainsList(ctx *cmdctx.CmdContext) error {
 	var orgSlug string
 	if len(ctx.Args) == 0 {
@@ -54,9 +103,8 @@ func runDomainsList(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(domains)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(domains)
 	}
 
 	table := tablewriter.NewWriter(ctx.Out)
@@ -69,6 +117,12 @@ func runDomainsList(ctx *cmdctx.CmdContext) error {
 
 	table.Render()
 
+	for _, domain := range domains {
+		if expiresWithoutRenewal(domain, domainRenewalReminderWindow) {
+			ctx.Statusf("domains", cmdctx.SWARN, "%s expires %s and will not renew automatically\n", domain.Name, presenters.FormatRelativeTime(domain.ExpiresAt))
+		}
+	}
+
 	return nil
 }
 
@@ -80,9 +134,8 @@ func runDomainsShow(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(domain)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(domain)
 	}
 
 	ctx.Statusf("domains", cmdctx.STITLE, "Domain\n")
@@ -101,6 +154,10 @@ func runDomainsShow(ctx *cmdctx.CmdContext) error {
 		}
 
 		ctx.Statusf("domains", cmdctx.SINFO, fmtstring, "Auto Renew", autorenew)
+
+		if expiresWithoutRenewal(domain, domainRenewalReminderWindow) {
+			ctx.Statusf("domains", cmdctx.SWARN, "Run '%s domains autorenew enable %s' to keep this domain\n", flyname.Name(), domain.Name)
+		}
 	}
 
 	ctx.StatusLn()
@@ -124,8 +181,7 @@ func runDomainsCreate(ctx *cmdctx.CmdContext) error {
 			return err
 		}
 
-		prompt := &survey.Input{Message: "Domain name to add"}
-		err := survey.AskOne(prompt, &name)
+		err := prompt.Ask(&survey.Input{Message: "Domain name to add"}, &name, "")
 		checkErr(err)
 
 		// TODO: Add some domain validation here
@@ -162,8 +218,7 @@ func runDomainsRegister(ctx *cmdctx.CmdContext) error {
 			return err
 		}
 
-		prompt := &survey.Input{Message: "Domain name to add"}
-		err := survey.AskOne(prompt, &name)
+		err := prompt.Ask(&survey.Input{Message: "Domain name to add"}, &name, "")
 		checkErr(err)
 		// TODO: Add some domain validation here
 	} else if len(ctx.Args) == 2 {
@@ -199,7 +254,7 @@ func runDomainsRegister(ctx *cmdctx.CmdContext) error {
 	fmt.Printf("Registration costs $%s per year and will renew automatically after the first year.\n", formattedCost)
 	fmt.Println("Your account will be charged once the domain is registered. This transaction is non-refundable.")
 
-	if !confirm(fmt.Sprintf("Register %s for $%s?", name, formattedCost)) {
+	if !confirm(fmt.Sprintf("Register %s for $%s?", name, formattedCost), "") {
 		return nil
 	}
 
@@ -214,3 +269,110 @@ func runDomainsRegister(ctx *cmdctx.CmdContext) error {
 
 	return nil
 }
+
+func runDomainsAutoRenewEnable(ctx *cmdctx.CmdContext) error {
+	return setDomainAutoRenew(ctx, ctx.Args[0], true)
+}
+
+func runDomainsAutoRenewDisable(ctx *cmdctx.CmdContext) error {
+	name := ctx.Args[0]
+
+	if !ctx.Config.GetBool("yes") && !confirm(fmt.Sprintf("Disable auto renew for %s? The registration will lapse when it expires", name), "yes") {
+		return nil
+	}
+
+	return setDomainAutoRenew(ctx, name, false)
+}
+
+func setDomainAutoRenew(ctx *cmdctx.CmdContext, name string, autoRenew bool) error {
+	domain, err := ctx.Client.API().GetDomain(name)
+	if err != nil {
+		return err
+	}
+
+	if *domain.RegistrationStatus != "REGISTERED" {
+		return fmt.Errorf("%s is not registered through Fly, auto renew is managed by its registrar", domain.Name)
+	}
+
+	domain, err = ctx.Client.API().SetDomainAutoRenew(domain.ID, autoRenew)
+	if err != nil {
+		return err
+	}
+
+	if *domain.AutoRenew {
+		ctx.Statusf("domains", cmdctx.SDONE, "Auto renew enabled for %s, next renewal %s\n", domain.Name, presenters.FormatTime(domain.ExpiresAt))
+	} else {
+		ctx.Statusf("domains", cmdctx.SDONE, "Auto renew disabled for %s, registration expires %s\n", domain.Name, presenters.FormatTime(domain.ExpiresAt))
+	}
+
+	return nil
+}
+
+func runDomainsExpiring(ctx *cmdctx.CmdContext) error {
+	within, err := helpers.ParseDuration(ctx.Config.GetString("within"))
+	if err != nil {
+		return err
+	}
+
+	var orgSlug string
+	if len(ctx.Args) == 0 {
+		org, err := selectOrganization(ctx.Client.API(), "", nil)
+		if err != nil {
+			return err
+		}
+		orgSlug = org.Slug
+	} else {
+		orgSlug = ctx.Args[0]
+	}
+
+	domains, err := ctx.Client.API().GetDomains(orgSlug)
+	if err != nil {
+		return err
+	}
+
+	expiring := []*api.Domain{}
+	for _, domain := range domains {
+		if domain.RegistrationStatus != nil && *domain.RegistrationStatus == "REGISTERED" && time.Until(domain.ExpiresAt) <= within {
+			expiring = append(expiring, domain)
+		}
+	}
+
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt) })
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(expiring)
+	}
+
+	if len(expiring) == 0 {
+		ctx.Statusf("domains", cmdctx.SINFO, "No domains in %s expire within %s\n", orgSlug, ctx.Config.GetString("within"))
+		return nil
+	}
+
+	table := tablewriter.NewWriter(ctx.Out)
+
+	table.SetHeader([]string{"Domain", "Expires At", "Expires", "Auto Renew"})
+
+	for _, domain := range expiring {
+		autorenew := "Disabled"
+		if domain.AutoRenew != nil && *domain.AutoRenew {
+			autorenew = "Enabled"
+		}
+		table.Append([]string{domain.Name, presenters.FormatTime(domain.ExpiresAt), presenters.FormatRelativeTime(domain.ExpiresAt), autorenew})
+	}
+
+	table.Render()
+
+	return nil
+}
+
+// expiresWithoutRenewal reports whether a registered domain will lapse within
+// window because auto renew is off
+func expiresWithoutRenewal(domain *api.Domain, window time.Duration) bool {
+	if domain.RegistrationStatus == nil || *domain.RegistrationStatus != "REGISTERED" {
+		return false
+	}
+	if domain.AutoRenew != nil && *domain.AutoRenew {
+		return false
+	}
+	return time.Until(domain.ExpiresAt) <= window
+}
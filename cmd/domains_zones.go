@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/dnsimport"
+)
+
+// nameserverWatchInterval - how often --watch checks the domain's nameservers
+const nameserverWatchInterval = 30 * time.Second
+
+func runDomainsZonesImport(ctx *cmdctx.CmdContext) error {
+	orgSlug, name := ctx.Args[0], strings.TrimSuffix(ctx.Args[1], ".")
+
+	providerName := ctx.Config.GetString("from-provider")
+	if providerName == "" {
+		return &ValidationError{fmt.Errorf("--from-provider is required, use one of %s", strings.Join(dnsimport.Providers, ", "))}
+	}
+
+	provider, err := dnsimport.NewProvider(providerName, dnsimport.Credentials{
+		Token:           ctx.Config.GetString("token"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	})
+	if err != nil {
+		return &ValidationError{err}
+	}
+
+	watchTimeout, err := helpers.ParseDuration(ctx.Config.GetString("watch-timeout"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid watch timeout")}
+	}
+
+	cancelCtx := createCancellableContext()
+
+	ctx.Statusf("domains", cmdctx.SBEGIN, "Reading %s from %s\n", name, provider.Name())
+	records, providerWarnings, err := provider.Records(cancelCtx, name)
+	if err != nil {
+		return err
+	}
+	for _, warning := range providerWarnings {
+		ctx.Statusf("domains", cmdctx.SWARN, "%s\n", warning)
+	}
+	ctx.Statusf("domains", cmdctx.SDONE, "Found %d records to copy\n", len(records))
+
+	domain, err := findOrCreateDomain(ctx, orgSlug, name)
+	if err != nil {
+		return err
+	}
+
+	warnings, changes, err := ctx.Client.API().ImportDNSRecords(domain.ID, dnsimport.ZoneFile(name, records))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Zone import report for %s\n", domain.Name)
+	printZoneImportReport(warnings, changes)
+
+	// nameservers are assigned with the zone, fetch them if creating it
+	// didn't return them
+	if domain.ZoneNameservers == nil {
+		if domain, err = ctx.Client.API().GetDomain(name); err != nil {
+			return err
+		}
+	}
+	if domain.ZoneNameservers == nil || len(*domain.ZoneNameservers) == 0 {
+		ctx.Statusf("domains", cmdctx.SWARN, "Fly hasn't assigned nameservers to %s yet, check `domains show %s` shortly\n", name, name)
+		return nil
+	}
+	nameservers := *domain.ZoneNameservers
+
+	ctx.StatusLn()
+	ctx.Statusf("domains", cmdctx.STITLE, "Moving %s to Fly\n", name)
+	ctx.Statusf("domains", cmdctx.SINFO, "1. Check the records above match what %s serves\n", provider.Name())
+	ctx.Statusf("domains", cmdctx.SINFO, "2. At your registrar, replace the nameservers of %s with:\n", name)
+	for _, ns := range nameservers {
+		ctx.Statusf("domains", cmdctx.SINFO, "     %s\n", ns)
+	}
+	ctx.Statusf("domains", cmdctx.SINFO, "3. Keep the zone at %s until the change has spread, which can take up to 48 hours\n", provider.Name())
+	ctx.Statusf("domains", cmdctx.SINFO, "   Run this again with --watch, or `domains show %s`, to check progress\n", name)
+
+	if !ctx.Config.GetBool("watch") {
+		return nil
+	}
+
+	ctx.StatusLn()
+	watchCtx, cancel := context.WithTimeout(cancelCtx, watchTimeout)
+	defer cancel()
+
+	return watchNameservers(watchCtx, ctx, name, nameservers)
+}
+
+// findOrCreateDomain - the organization's domain called name, added to it
+// if it isn't there yet
+func findOrCreateDomain(ctx *cmdctx.CmdContext, orgSlug string, name string) (*api.Domain, error) {
+	domains, err := ctx.Client.API().GetDomains(orgSlug)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		if strings.EqualFold(domain.Name, name) {
+			ctx.Statusf("domains", cmdctx.SDETAIL, "Using the existing zone for %s\n", name)
+			return domain, nil
+		}
+	}
+
+	org, err := ctx.Client.API().FindOrganizationBySlug(orgSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Statusf("domains", cmdctx.SBEGIN, "Creating zone %s in organization %s\n", name, org.Slug)
+	return ctx.Client.API().CreateDomain(org.ID, name)
+}
+
+// watchNameservers - waits until name is delegated to the expected
+// nameservers, checking public DNS
+func watchNameservers(ctx context.Context, cmdCtx *cmdctx.CmdContext, name string, expected []string) error {
+	cmdCtx.Statusf("domains", cmdctx.SBEGIN, "Waiting for %s to use Fly's nameservers\n", name)
+
+	want := normalizeNameservers(expected)
+	var last string
+
+	for {
+		records, err := net.DefaultResolver.LookupNS(ctx, name)
+		if err == nil {
+			var current []string
+			for _, ns := range records {
+				current = append(current, ns.Host)
+			}
+			got := normalizeNameservers(current)
+
+			if got == want {
+				cmdCtx.Statusf("domains", cmdctx.SDONE, "%s now uses Fly's nameservers\n", name)
+				return nil
+			}
+			if got != last {
+				cmdCtx.Statusf("domains", cmdctx.SDETAIL, "%s uses %s\n", name, got)
+				last = got
+			}
+		}
+
+		select {
+		case <-time.After(nameserverWatchInterval):
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%s still isn't using Fly's nameservers, changes can take up to 48 hours to spread", name)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+func normalizeNameservers(names []string) string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		out = append(out, strings.ToLower(strings.TrimSuffix(n, ".")))
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
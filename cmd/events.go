@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/nats"
+)
+
+// appEventTypes - the kinds of lifecycle events apps publish
+var appEventTypes = []string{"deploy", "start", "stop", "restart", "check", "oom"}
+
+func newEventsCommand(client *client.Client) *Command {
+	eventsStrings := docstrings.Get("events")
+	cmd := BuildCommandKS(nil, runEvents, eventsStrings, client, requireSession, requireAppName)
+
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "follow",
+		Shorthand:   "f",
+		Description: "Stream events as they happen",
+	})
+	cmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "type",
+		Description: "Only show events of these types: " + strings.Join(appEventTypes, ", "),
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "Only show events in this region",
+	})
+
+	return cmd
+}
+
+// appEvent is a lifecycle event of an app, as published on the event stream
+type appEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	App       string    `json:"app"`
+	Region    string    `json:"region,omitempty"`
+	Instance  string    `json:"instance,omitempty"`
+	Version   int       `json:"version,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// appEventFilter - which events to show, from the --type and --region flags
+type appEventFilter struct {
+	types  map[string]bool
+	region string
+}
+
+func newAppEventFilter(ctx *cmdctx.CmdContext) (appEventFilter, error) {
+	filter := appEventFilter{types: map[string]bool{}, region: ctx.Config.GetString("region")}
+
+	for _, t := range ctx.Config.GetStringSlice("type") {
+		t = strings.ToLower(t)
+		known := false
+		for _, eventType := range appEventTypes {
+			known = known || t == eventType
+		}
+		if !known {
+			return filter, &ValidationError{fmt.Errorf("unknown event type %q, use one of %s", t, strings.Join(appEventTypes, ", "))}
+		}
+		filter.types[t] = true
+	}
+
+	return filter, nil
+}
+
+func (f appEventFilter) match(event appEvent) bool {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return false
+	}
+	return f.region == "" || f.region == event.Region
+}
+
+func runEvents(ctx *cmdctx.CmdContext) error {
+	filter, err := newAppEventFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Config.GetBool("follow") {
+		return followAppEvents(ctx, filter)
+	}
+
+	events, err := recentAppEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	matched := []appEvent{}
+	for _, event := range events {
+		if filter.match(event) {
+			matched = append(matched, event)
+		}
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(matched)
+	}
+
+	if len(matched) == 0 {
+		ctx.Status("events", cmdctx.SINFO, "No recent events, use --follow to wait for new ones")
+		return nil
+	}
+	for _, event := range matched {
+		printAppEvent(ctx, event)
+	}
+
+	return nil
+}
+
+// recentAppEvents - the events of the app's current and recent instances,
+// oldest first. The event stream doesn't keep history, so these come from
+// the instances' event logs.
+func recentAppEvents(ctx *cmdctx.CmdContext) ([]appEvent, error) {
+	status, err := ctx.Client.API().GetAppStatus(ctx.AppName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []appEvent{}
+	for _, alloc := range status.Allocations {
+		for _, e := range alloc.Events {
+			events = append(events, appEvent{
+				Timestamp: e.Timestamp,
+				Type:      allocationEventType(e.Type, e.Message),
+				App:       ctx.AppName,
+				Region:    alloc.Region,
+				Instance:  alloc.IDShort,
+				Version:   alloc.Version,
+				Status:    alloc.Status,
+				Message:   e.Message,
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	return events, nil
+}
+
+// allocationEventType - the event type an instance event is reported as
+func allocationEventType(eventType string, message string) string {
+	switch {
+	case strings.Contains(strings.ToLower(message), "oom") || strings.Contains(strings.ToLower(message), "out of memory"):
+		return "oom"
+	case eventType == "Restarting":
+		return "restart"
+	case eventType == "Started" || eventType == "Received" || eventType == "Task Setup" || eventType == "Driver":
+		return "start"
+	case eventType == "Terminated" || eventType == "Killing" || eventType == "Killed" || eventType == "Not Restarting":
+		return "stop"
+	}
+	return strings.ToLower(eventType)
+}
+
+// followAppEvents - subscribes to the app's events on the event stream,
+// reached over the organization's private network, until interrupted
+func followAppEvents(ctx *cmdctx.CmdContext, filter appEventFilter) error {
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return fmt.Errorf("get app: %w", err)
+	}
+
+	agentclient, err := establishAgent(ctx)
+	if err != nil {
+		return fmt.Errorf("can't establish agent: %s", err)
+	}
+
+	dialer, err := agentclient.Dialer(&app.Organization)
+	if err != nil {
+		return fmt.Errorf("can't build tunnel for %s: %s", app.Organization.Slug, err)
+	}
+
+	cancelCtx := createCancellableContext()
+
+	conn, err := dialer.DialContext(cancelCtx, "tcp", nats.DefaultAddr)
+	if err != nil {
+		return fmt.Errorf("can't reach the event stream: %w", err)
+	}
+
+	nc, err := nats.Connect(conn, nats.Options{
+		User:     app.Organization.Slug,
+		Password: flyctl.GetAPIToken(),
+		Name:     "flyctl",
+		Version:  flyctl.Version,
+	})
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("can't connect to the event stream: %w", err)
+	}
+	defer nc.Close()
+
+	// subscribing by type keeps the server from sending events that would be
+	// filtered out anyway
+	subjects := []string{fmt.Sprintf("events.%s.>", ctx.AppName)}
+	if len(filter.types) > 0 {
+		subjects = subjects[:0]
+		for t := range filter.types {
+			subjects = append(subjects, fmt.Sprintf("events.%s.%s", ctx.AppName, t))
+		}
+		sort.Strings(subjects)
+	}
+	for _, subject := range subjects {
+		if err := nc.Subscribe(subject); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		<-cancelCtx.Done()
+		nc.Close()
+	}()
+
+	if !ctx.OutputStructured() {
+		ctx.Statusf("events", cmdctx.SINFO, "Waiting for events from %s, press Ctrl+C to stop\n", ctx.AppName)
+	}
+
+	for {
+		msg, err := nc.Next()
+		if err != nil {
+			if cancelCtx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("event stream: %w", err)
+		}
+
+		event, err := parseAppEvent(msg)
+		if err != nil {
+			ctx.Statusf("events", cmdctx.SWARN, "Skipping an event on %s: %s\n", msg.Subject, err)
+			continue
+		}
+		if !filter.match(event) {
+			continue
+		}
+
+		if err := writeAppEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+}
+
+// parseAppEvent - decodes an event, taking its app and type from the
+// subject, events.<app>.<type>, when the payload leaves them out
+func parseAppEvent(msg *nats.Msg) (appEvent, error) {
+	var event appEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		return event, err
+	}
+
+	parts := strings.Split(msg.Subject, ".")
+	if event.App == "" && len(parts) > 1 {
+		event.App = parts[1]
+	}
+	if event.Type == "" && len(parts) > 2 {
+		event.Type = parts[2]
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	return event, nil
+}
+
+// writeAppEvent - writes a streamed event, as a JSON line when JSON output
+// was selected so records can be piped as they arrive
+func writeAppEvent(ctx *cmdctx.CmdContext, event appEvent) error {
+	switch {
+	case ctx.OutputJSON():
+		return json.NewEncoder(ctx.Out).Encode(event)
+	case ctx.OutputStructured():
+		return ctx.WriteData(event)
+	}
+
+	printAppEvent(ctx, event)
+	return nil
+}
+
+func printAppEvent(ctx *cmdctx.CmdContext, event appEvent) {
+	where := event.Region
+	if event.Instance != "" {
+		where = strings.TrimSpace(event.Region + " " + event.Instance)
+	}
+	if where == "" {
+		where = "-"
+	}
+
+	fmt.Fprintf(ctx.Out, "%s  %-8s %-16s %s\n", event.Timestamp.UTC().Format(time.RFC3339), event.Type, where, event.Message)
+}
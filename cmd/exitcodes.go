@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+// Exit codes for each class of failure, so scripts and CI can branch on them.
+// These are documented in the root help and shouldn't change.
+const (
+	ExitCodeError        = 1   // anything not covered below
+	ExitCodeValidation   = 2   // invalid arguments, flags or app configuration, or input needed when non-interactive
+	ExitCodeAuth         = 3   // not logged in or the token was rejected
+	ExitCodeNotFound     = 4   // the app or resource doesn't exist
+	ExitCodeDeployFailed = 5   // the deployment failed its health checks
+	ExitCodeAbort        = 130 // the user declined a prompt or interrupted flyctl
+)
+
+// ErrDeployFailed - Error generated when a deployment fails. The reason has
+// already been printed by the deployment monitor.
+var ErrDeployFailed = errors.New("deployment failed")
+
+// ValidationError - An error caused by invalid input rather than a failure
+// talking to the platform
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// ExitCode - The exit code flyctl should return for err
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var validationErr *ValidationError
+
+	switch {
+	case errors.Is(err, ErrAbort), errors.Is(err, context.Canceled), isInterrupt(err):
+		return ExitCodeAbort
+	case errors.Is(err, ErrDeployFailed):
+		return ExitCodeDeployFailed
+	case errors.As(err, &validationErr), prompt.IsNonInteractiveError(err):
+		return ExitCodeValidation
+	case errors.Is(err, client.ErrNoAuthToken):
+		return ExitCodeAuth
+	case strings.Contains(err.Error(), "Could not resolve App"):
+		return ExitCodeNotFound
+	}
+
+	var apiErr *api.ApiError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case 401:
+			return ExitCodeAuth
+		case 404:
+			return ExitCodeNotFound
+		}
+	}
+
+	return ExitCodeError
+}
+
+// markValidationErrors wraps the argument checks of cmd and all its
+// subcommands so their failures exit with ExitCodeValidation
+func markValidationErrors(cmd *cobra.Command) {
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, a []string) error {
+			if err := args(cmd, a); err != nil {
+				return &ValidationError{err}
+			}
+			return nil
+		}
+	}
+
+	for _, sub := range cmd.Commands() {
+		markValidationErrors(sub)
+	}
+}
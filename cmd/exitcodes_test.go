@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no error", nil, 0},
+		{"other error", errors.New("boom"), ExitCodeError},
+		{"api error", &api.ApiError{Message: "server error", Status: 500}, ExitCodeError},
+
+		{"validation", &ValidationError{errors.New("bad flag")}, ExitCodeValidation},
+		{"wrapped validation", fmt.Errorf("deploy: %w", &ValidationError{errors.New("bad config")}), ExitCodeValidation},
+		{"non-interactive", &prompt.NonInteractiveError{Message: "Select region", Flag: "region"}, ExitCodeValidation},
+
+		{"no token", client.ErrNoAuthToken, ExitCodeAuth},
+		{"unauthorized", &api.ApiError{Message: "unauthorized", Status: 401}, ExitCodeAuth},
+
+		{"unknown app", errors.New("Could not resolve App"), ExitCodeNotFound},
+		{"not found", &api.ApiError{Message: "not found", Status: 404}, ExitCodeNotFound},
+		{"wrapped not found", pkgerrors.WithMessage(&api.ApiError{Message: "not found", Status: 404}, "fetching volume"), ExitCodeNotFound},
+
+		{"deploy failed", ErrDeployFailed, ExitCodeDeployFailed},
+		{"wrapped deploy failed", fmt.Errorf("v3: %w", ErrDeployFailed), ExitCodeDeployFailed},
+
+		{"abort", ErrAbort, ExitCodeAbort},
+		{"cancelled", context.Canceled, ExitCodeAbort},
+		{"cancelled in multierror", multierror.Append(nil, context.Canceled), ExitCodeAbort},
+		{"interrupt", errors.New("interrupt"), ExitCodeAbort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
+)
+
+// extensionProvider is a partner whose services can be provisioned for an
+// app. Each gets `extensions <name> create`, documented under
+// extensions.<name> in the help.
+type extensionProvider struct {
+	// name identifies the provider in commands and to the API
+	name string
+	// displayName is how the provider is shown
+	displayName string
+	// secrets are the variables the provider's extensions set on the app
+	secrets []string
+	// addFlags adds the provider's own options to its create command
+	addFlags func(cmd *Command)
+	// options are the provider's own options, from those flags
+	options func(ctx *cmdctx.CmdContext) map[string]string
+}
+
+var extensionProviders = []extensionProvider{
+	{
+		name:        "sentry",
+		displayName: "Sentry",
+		secrets:     []string{"SENTRY_DSN"},
+		addFlags: func(cmd *Command) {
+			cmd.AddStringFlag(StringFlagOpts{
+				Name:        "platform",
+				Description: "The Sentry platform of the project, e.g. node or python, to tailor its setup instructions",
+			})
+		},
+		options: func(ctx *cmdctx.CmdContext) map[string]string {
+			if platform := ctx.Config.GetString("platform"); platform != "" {
+				return map[string]string{"platform": platform}
+			}
+			return nil
+		},
+	},
+}
+
+func newExtensionsCommand(client *client.Client) *Command {
+	extensionsStrings := docstrings.Get("extensions")
+	cmd := BuildCommandKS(nil, nil, extensionsStrings, client, requireSession)
+	cmd.Aliases = []string{"ext"}
+
+	listStrings := docstrings.Get("extensions.list")
+	listCmd := BuildCommandKS(cmd, runExtensionsList, listStrings, client, requireSession, requireAppName)
+	listCmd.Aliases = []string{"ls"}
+
+	for _, provider := range extensionProviders {
+		provider := provider
+
+		providerStrings := docstrings.Get("extensions." + provider.name)
+		providerCmd := BuildCommandKS(cmd, nil, providerStrings, client, requireSession)
+
+		createStrings := docstrings.Get("extensions." + provider.name + ".create")
+		createCmd := BuildCommandKS(providerCmd, func(ctx *cmdctx.CmdContext) error {
+			return runExtensionCreate(ctx, provider)
+		}, createStrings, client, requireSession, requireAppName)
+		createCmd.AddStringFlag(StringFlagOpts{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "The name of the extension, defaults to the app's name",
+		})
+		createCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+		if provider.addFlags != nil {
+			provider.addFlags(createCmd)
+		}
+	}
+
+	return cmd
+}
+
+func runExtensionCreate(ctx *cmdctx.CmdContext, provider extensionProvider) error {
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := confirmSecretsReplaced(ctx, app.Name, provider.secrets); err != nil || !ok {
+		return err
+	}
+
+	input := api.ProvisionExtensionInput{
+		AppID:    app.Name,
+		Provider: provider.name,
+		Name:     ctx.Config.GetString("name"),
+	}
+	if provider.options != nil {
+		input.Options = provider.options(ctx)
+	}
+
+	s := iostreams.NewSpinner(fmt.Sprintf("Provisioning with %s...", provider.displayName))
+	s.Start()
+	extension, err := ctx.Client.API().ProvisionExtension(input)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if len(extension.Environment) > 0 {
+		if _, err := ctx.Client.API().SetSecrets(app.Name, extension.Environment); err != nil {
+			ctx.Statusf("extensions", cmdctx.SERROR, "Created %s but couldn't set %s's secrets, set these yourself:\n", extension.Name, app.Name)
+			for _, key := range sortedKeys(extension.Environment) {
+				fmt.Fprintf(ctx.Out, "  %s=%s\n", key, extension.Environment[key])
+			}
+			return err
+		}
+	}
+
+	if ctx.OutputStructured() {
+		extension.Environment = nil
+		return ctx.WriteData(extension)
+	}
+
+	ctx.Statusf("extensions", cmdctx.SDONE, "Created %s extension %s for %s\n", provider.displayName, extension.Name, app.Name)
+	if len(extension.Environment) > 0 {
+		ctx.Statusf("extensions", cmdctx.SINFO, "Set these secrets on %s: %s\n", app.Name, strings.Join(sortedKeys(extension.Environment), ", "))
+	}
+	if extension.DashboardURL != "" {
+		ctx.Statusf("extensions", cmdctx.SINFO, "Manage it at %s\n", extension.DashboardURL)
+	}
+
+	return nil
+}
+
+func runExtensionsList(ctx *cmdctx.CmdContext) error {
+	extensions, err := ctx.Client.API().GetExtensions(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(extensions)
+	}
+
+	if len(extensions) == 0 {
+		names := make([]string, 0, len(extensionProviders))
+		for _, provider := range extensionProviders {
+			names = append(names, provider.name)
+		}
+		ctx.Statusf("extensions", cmdctx.SINFO, "%s has no extensions, create one with `flyctl extensions <%s> create`\n", ctx.AppName, strings.Join(names, "|"))
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Name", "Provider", "Status", "Dashboard", "Created"})
+	for _, extension := range extensions {
+		table.Append([]string{
+			extension.Name,
+			extensionProviderName(extension.Provider),
+			extension.Status,
+			extension.DashboardURL,
+			humanize.Time(extension.CreatedAt),
+		})
+	}
+	table.Render()
+
+	return nil
+}
+
+// extensionProviderName - how a provider is shown, as the API names it
+// when this version of flyctl doesn't know it
+func extensionProviderName(name string) string {
+	for _, provider := range extensionProviders {
+		if provider.name == name {
+			return provider.displayName
+		}
+	}
+	return name
+}
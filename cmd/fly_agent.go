@@ -5,9 +5,13 @@ import (
 	"log"
 	"os"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/pkg/agent"
 )
 
@@ -81,3 +85,63 @@ func runFlyAgentStop(ctx *cmdctx.CmdContext) error {
 
 	return err
 }
+
+// Answers to the agent start prompt
+const (
+	agentStartAlways = "Yes, and start it automatically from now on"
+	agentStartOnce   = "Yes, just this time"
+	agentStartNever  = "No"
+)
+
+// establishAgent - returns a client for the agent, starting it if it isn't
+// running. On a terminal the user is asked first unless they've already
+// agreed to the agent being started automatically.
+func establishAgent(ctx *cmdctx.CmdContext) (*agent.Client, error) {
+	api := ctx.Client.API()
+
+	if c, err := agent.Running(api); err == nil {
+		return c, nil
+	}
+
+	if !viper.GetBool(flyctl.ConfigAgentAutoStart) {
+		start, err := confirmAgentStart()
+		if err != nil {
+			return nil, err
+		}
+		if !start {
+			return nil, fmt.Errorf("%w, start it with `flyctl agent start`", agent.ErrNotRunning)
+		}
+	}
+
+	ctx.Status("agent", cmdctx.SDETAIL, "Starting the Fly agent")
+
+	return agent.StartDaemon(api, os.Args[0])
+}
+
+// confirmAgentStart - asks whether to start the agent, saving the answer to
+// the config when it's to always start it. It's started without asking when
+// there's no terminal.
+func confirmAgentStart() (bool, error) {
+	answer := ""
+	err := prompt.Ask(&survey.Select{
+		Message: "The Fly agent, which connects to private networks, isn't running. Start it?",
+		Options: []string{agentStartAlways, agentStartOnce, agentStartNever},
+	}, &answer, "")
+
+	switch {
+	case prompt.IsNonInteractiveError(err):
+		// without a terminal to ask on, start it as flyctl always has
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+
+	if answer == agentStartAlways {
+		viper.Set(flyctl.ConfigAgentAutoStart, true)
+		if err := flyctl.SaveConfig(); err != nil {
+			return false, fmt.Errorf("save config: %w", err)
+		}
+	}
+
+	return answer != agentStartNever, nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/helpers"
+)
+
+// Metadata keys recorded on forked apps so they can be found and reaped later
+const (
+	forkSourceMetadataKey    = "fly.fork.source"
+	forkExpiresAtMetadataKey = "fly.fork.expires_at"
+)
+
+func runFork(cmdCtx *cmdctx.CmdContext) error {
+	sourceName := cmdCtx.Args[0]
+
+	ttl, err := helpers.ParseDuration(cmdCtx.Config.GetString("ttl"))
+	if err != nil {
+		return errors.Wrap(err, "invalid ttl")
+	}
+
+	forkName := cmdCtx.Config.GetString("name")
+	if forkName == "" {
+		pr := cmdCtx.Config.GetString("pr")
+		if pr == "" {
+			return errors.New("specify a --name or --pr for the forked app")
+		}
+		forkName = fmt.Sprintf("%s-pr-%s", sourceName, pr)
+	}
+
+	expiresAt := time.Now().Add(ttl).UTC()
+
+	// forking is safe to repeat from CI: an existing fork just has its expiry extended
+	_, err = cmdCtx.Client.API().GetApp(forkName)
+	if err == nil {
+		metadata, err := cmdCtx.Client.API().GetAppMetadata(forkName)
+		if err != nil {
+			return errors.Wrap(err, "Error fetching app metadata")
+		}
+		if metadata[forkSourceMetadataKey] != sourceName {
+			return fmt.Errorf("app %s already exists and is not a fork of %s", forkName, sourceName)
+		}
+	} else {
+		if !api.IsNotFoundError(err) && err.Error() != "Could not resolve App" {
+			return errors.Wrap(err, "Error fetching app")
+		}
+
+		if _, _, err := cloneApp(cmdCtx, sourceName, forkName, cmdCtx.Config.GetString("org")); err != nil {
+			return err
+		}
+
+		if cmdCtx.Config.GetBool("copy-secrets") {
+			if _, err := cmdCtx.Client.API().CopySecrets(sourceName, forkName); err != nil {
+				return errors.WithMessage(err, "Failed to copy secrets")
+			}
+		}
+	}
+
+	_, err = cmdCtx.Client.API().SetAppMetadata(forkName, map[string]string{
+		forkSourceMetadataKey:    sourceName,
+		forkExpiresAtMetadataKey: expiresAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "Failed to set app expiry")
+	}
+
+	if cmdCtx.OutputStructured() {
+		return cmdCtx.WriteData(map[string]interface{}{
+			"name":      forkName,
+			"source":    sourceName,
+			"expiresAt": expiresAt,
+		})
+	}
+
+	cmdCtx.Statusf("fork", cmdctx.SDONE, "%s expires %s, deploy it with '%s deploy -a %s'\n", forkName, expiresAt.Format(time.RFC3339), flyname.Name(), forkName)
+
+	return nil
+}
+
+func runReap(cmdCtx *cmdctx.CmdContext) error {
+	all, err := cmdCtx.Client.API().GetApps(nil)
+	if err != nil {
+		return err
+	}
+
+	orgSlug := cmdCtx.Config.GetString("org")
+	apps := []api.App{}
+	for _, app := range all {
+		if orgSlug == "" || app.Organization.Slug == orgSlug {
+			apps = append(apps, app)
+		}
+	}
+	if err := fetchAppsMetadata(cmdCtx.Client.API(), apps); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expired := []api.App{}
+	for _, app := range apps {
+		expiresAt, ok := forkExpiry(app)
+		if ok && expiresAt.Before(now) {
+			expired = append(expired, app)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].Name < expired[j].Name })
+
+	if len(expired) == 0 {
+		cmdCtx.Status("reap", cmdctx.SINFO, "No expired apps to reap")
+		return nil
+	}
+
+	for _, app := range expired {
+		expiresAt, _ := forkExpiry(app)
+		fmt.Printf("%s (fork of %s) expired %s\n", app.Name, app.Metadata[forkSourceMetadataKey], expiresAt.Format(time.RFC3339))
+	}
+
+	if cmdCtx.Config.GetBool("dry-run") {
+		return nil
+	}
+
+	if !cmdCtx.Config.GetBool("yes") && !confirm(fmt.Sprintf("Destroy %d expired apps?", len(expired)), "yes") {
+		return nil
+	}
+
+	var failed int
+	for _, app := range expired {
+		if err := cmdCtx.Client.API().DeleteApp(app.Name); err != nil {
+			cmdCtx.Statusf("reap", cmdctx.SERROR, "Failed to destroy %s: %s\n", app.Name, err)
+			failed++
+			continue
+		}
+		cmdCtx.Statusf("reap", cmdctx.SDONE, "Destroyed app %s\n", app.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to destroy %d apps", failed)
+	}
+
+	return nil
+}
+
+func forkExpiry(app api.App) (time.Time, bool) {
+	value, ok := app.Metadata[forkExpiresAtMetadataKey]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return expiresAt, true
+}
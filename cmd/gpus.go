@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmd/presenters"
+	"github.com/superfly/flyctl/cmdctx"
+)
+
+// gpuRequest - GPUs requested with --gpu-kind and --gpus
+type gpuRequest struct {
+	Kind  api.GPUKind
+	Count int
+}
+
+func (r *gpuRequest) String() string {
+	return presenters.FormatGPUs(r.Kind.Name, r.Count)
+}
+
+func addGPUFlags(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "gpu-kind",
+		Description: "The kind of GPU to attach, e.g. a100. See 'fly platform regions --capabilities' for availability",
+	})
+	cmd.AddIntFlag(IntFlagOpts{
+		Name:        "gpus",
+		Description: "How many GPUs to attach, defaults to 1 when --gpu-kind is set",
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "yes",
+		Shorthand:   "y",
+		Description: "Accept GPU pricing without confirmation",
+	})
+}
+
+// gpuRequestFromFlags - parses --gpu-kind and --gpus, returning nil when no
+// GPUs were asked for
+func gpuRequestFromFlags(cmdCtx *cmdctx.CmdContext) (*gpuRequest, error) {
+	kindName := strings.ToLower(cmdCtx.Config.GetString("gpu-kind"))
+	count := cmdCtx.Config.GetInt("gpus")
+
+	if kindName == "" {
+		if count != 0 {
+			return nil, &ValidationError{fmt.Errorf("--gpus requires --gpu-kind")}
+		}
+		return nil, nil
+	}
+	if count < 0 {
+		return nil, &ValidationError{fmt.Errorf("--gpus must be at least 1")}
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	kinds, err := cmdCtx.Client.API().PlatformGPUKinds()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, kind := range kinds {
+		if kind.Name == kindName {
+			return &gpuRequest{Kind: kind, Count: count}, nil
+		}
+		names = append(names, kind.Name)
+	}
+	sort.Strings(names)
+
+	return nil, &ValidationError{fmt.Errorf("unknown GPU kind %q, available kinds are: %s", kindName, strings.Join(names, ", "))}
+}
+
+// checkGPURegions - ensures every region can run the requested GPU kind
+func checkGPURegions(cmdCtx *cmdctx.CmdContext, gpus *gpuRequest, regionCodes []string) error {
+	regions, err := cmdCtx.Client.API().PlatformRegionCapabilities()
+	if err != nil {
+		return err
+	}
+
+	byCode := map[string]api.Region{}
+	for _, region := range regions {
+		byCode[region.Code] = region
+	}
+
+	supported := []string{}
+	for _, region := range regions {
+		if regionHasGPUKind(region, gpus.Kind.Name) {
+			supported = append(supported, region.Code)
+		}
+	}
+	sort.Strings(supported)
+
+	for _, code := range regionCodes {
+		region, ok := byCode[code]
+		if ok && regionHasGPUKind(region, gpus.Kind.Name) {
+			continue
+		}
+		if len(supported) == 0 {
+			return fmt.Errorf("%s GPUs aren't available in any region", gpus.Kind.Name)
+		}
+		return fmt.Errorf("%s GPUs aren't available in %s, try one of: %s", gpus.Kind.Name, code, strings.Join(supported, ", "))
+	}
+
+	return nil
+}
+
+func regionHasGPUKind(region api.Region, kind string) bool {
+	if region.Capabilities == nil || !region.Capabilities.GPUs {
+		return false
+	}
+	for _, k := range region.Capabilities.GPUKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmGPUPricing - shows what the GPUs will cost across vmCount VMs and
+// asks to go ahead, unless --yes was passed
+func confirmGPUPricing(cmdCtx *cmdctx.CmdContext, gpus *gpuRequest, vmCount int) bool {
+	if vmCount < 1 {
+		vmCount = 1
+	}
+	total := gpus.Kind.PriceMonth * float32(gpus.Count*vmCount)
+
+	cmdCtx.Statusf("gpus", cmdctx.SINFO, "%s (%d GB) per VM, $%.2f/mo per GPU\n", gpus, gpus.Kind.MemoryGB, gpus.Kind.PriceMonth)
+	cmdCtx.Statusf("gpus", cmdctx.SINFO, "Estimated GPU cost for %d VM(s): $%.2f/mo, billed per second\n", vmCount, total)
+
+	if cmdCtx.Config.GetBool("yes") {
+		return true
+	}
+	return confirm("Continue with GPU pricing?", "yes")
+}
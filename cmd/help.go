@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+// helpTopicPrefix - the docstrings keys of help topics start with this
+const helpTopicPrefix = "topics."
+
+// newHelpCommand - replaces cobra's help command to also show the topics in
+// docstrings, so everything flyctl documents can be read offline
+func newHelpCommand(client *client.Client) *Command {
+	helpStrings := docstrings.Get("help")
+	cmd := BuildCommandKS(nil, nil, helpStrings, client)
+	cmd.ValidArgsFunction = completeHelpArgs
+	// help describes the command tree, so this runs with the cobra command
+	// rather than a command context
+	cmd.RunE = func(c *cobra.Command, args []string) error {
+		return showHelp(c.Root(), c.OutOrStdout(), args)
+	}
+	return cmd
+}
+
+func showHelp(root *cobra.Command, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return root.Help()
+	}
+
+	if cmd, _, err := root.Find(args); err == nil && cmd != root {
+		cmd.InitDefaultHelpFlag()
+		return cmd.Help()
+	}
+
+	if expansion, ok := userAliases()[args[0]]; ok && len(args) == 1 {
+		fmt.Fprintf(w, "%s is an alias for '%s %s'\n\n", args[0], root.Name(), expansion)
+		expanded, err := ExpandAlias(root, args)
+		if err != nil {
+			return err
+		}
+		if cmd, _, err := root.Find(commandWords(expanded)); err == nil && cmd != root {
+			cmd.InitDefaultHelpFlag()
+			return cmd.Help()
+		}
+		return nil
+	}
+
+	name := strings.Join(args, "-")
+	if topic, ok := lookupHelpTopic(name); ok {
+		fmt.Fprintln(w, topic.Long)
+		return nil
+	}
+
+	query := strings.Join(args, " ")
+	matches := searchHelp(root, query)
+	if len(matches) == 0 {
+		return &ValidationError{fmt.Errorf("no command or topic mentions %q, see '%s help help' for the topics", query, root.Name())}
+	}
+
+	fmt.Fprintf(w, "No command or topic named %q, these mention it:\n\n", query)
+	width := 0
+	for _, m := range matches {
+		if len(m[0]) > width {
+			width = len(m[0])
+		}
+	}
+	for _, m := range matches {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, m[0], m[1])
+	}
+	return nil
+}
+
+// commandWords - the leading arguments that aren't flags, the command an
+// expanded alias runs
+func commandWords(args []string) []string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i]
+		}
+	}
+	return args
+}
+
+// helpTopicNames - the names of the help topics, e.g. environment
+func helpTopicNames() []string {
+	names := []string{}
+	for _, key := range docstrings.Keys() {
+		if strings.HasPrefix(key, helpTopicPrefix) {
+			names = append(names, strings.TrimPrefix(key, helpTopicPrefix))
+		}
+	}
+	return names
+}
+
+func lookupHelpTopic(name string) (docstrings.KeyStrings, bool) {
+	for _, topic := range helpTopicNames() {
+		if strings.EqualFold(topic, name) {
+			return docstrings.Get(helpTopicPrefix + topic), true
+		}
+	}
+	return docstrings.KeyStrings{}, false
+}
+
+// searchHelp - the commands and topics whose help mentions query, as pairs
+// of how to show them and their short help
+func searchHelp(root *cobra.Command, query string) [][2]string {
+	query = strings.ToLower(query)
+	mentions := func(texts ...string) bool {
+		for _, t := range texts {
+			if strings.Contains(strings.ToLower(t), query) {
+				return true
+			}
+		}
+		return false
+	}
+
+	matches := [][2]string{}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, c := range cmd.Commands() {
+			if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			if mentions(c.CommandPath(), c.Short, c.Long) {
+				matches = append(matches, [2]string{c.CommandPath(), c.Short})
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+
+	for _, name := range helpTopicNames() {
+		topic := docstrings.Get(helpTopicPrefix + name)
+		if mentions(name, topic.Short, topic.Long) {
+			matches = append(matches, [2]string{fmt.Sprintf("%s help %s", root.Name(), name), topic.Short})
+		}
+	}
+
+	return matches
+}
+
+// completeHelpArgs - completes the commands under those already given, or a
+// topic as the first argument
+func completeHelpArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	parent := cmd.Root()
+	if len(args) > 0 {
+		found, rest, err := parent.Find(args)
+		if err != nil || len(rest) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		parent = found
+	}
+
+	candidates := []string{}
+	for _, c := range parent.Commands() {
+		if c.IsAvailableCommand() {
+			candidates = append(candidates, c.Name())
+		}
+	}
+	if len(args) == 0 {
+		candidates = append(candidates, helpTopicNames()...)
+	}
+	return completions(candidates, toComplete)
+}
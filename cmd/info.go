@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/flyname"
 	"github.com/superfly/flyctl/internal/client"
@@ -43,26 +44,76 @@ func runInfo(ctx *cmdctx.CmdContext) error {
 		return nil
 	}
 
-	err = ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.AppCompact{AppCompact: *app}, HideHeader: true, Vertical: true, Title: "App"})
-	if err != nil {
+	info := appInfo{AppCompact: *app}
+
+	if info.Regions, info.BackupRegions, err = ctx.Client.API().ListAppRegions(app.Name); err != nil {
 		return err
 	}
-
-	// For JSON, everything is included in the previous render, for humans, we need to do some formatting
-	if !ctx.OutputJSON() {
-		err = ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.Services{Services: app.Services}, Title: "Services"})
+	if app.Deployed {
+		size, counts, err := ctx.Client.API().AppVMResources(app.Name)
 		if err != nil {
 			return err
 		}
+		info.VMSize, info.VMCounts = &size, counts
+	}
+	if info.Volumes, err = ctx.Client.API().GetVolumes(app.Name); err != nil {
+		return err
+	}
+	if info.Certificates, err = ctx.Client.API().GetAppCertificates(app.Name); err != nil {
+		return err
+	}
 
-		err = ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.IPAddresses{IPAddresses: app.IPAddresses.Nodes}, Title: "IP Adresses"})
-		if err != nil {
-			return err
-		}
+	if ctx.OutputStructured() {
+		return ctx.WriteData(info)
+	}
 
-		if !app.Deployed {
-			ctx.Status("info", `App has not been deployed yet. Try running "`+flyname.Name()+` deploy --image flyio/hellofly"`)
-		}
+	err = ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.AppCompact{AppCompact: *app}, HideHeader: true, Vertical: true, Title: "App"})
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.Services{Services: app.Services}, Title: "Services"})
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.IPAddresses{IPAddresses: app.IPAddresses.Nodes}, Title: "IP Adresses"})
+	if err != nil {
+		return err
+	}
+
+	if !app.Deployed {
+		ctx.Status("info", `App has not been deployed yet. Try running "`+flyname.Name()+` deploy --image flyio/hellofly"`)
 	}
-	return nil
+
+	views := []cmdctx.PresenterOption{
+		{Presentable: &presenters.Regions{Regions: info.Regions}, Title: "Regions"},
+	}
+	if len(info.BackupRegions) > 0 {
+		views = append(views, cmdctx.PresenterOption{Presentable: &presenters.Regions{Regions: info.BackupRegions}, Title: "Backup Regions"})
+	}
+	if info.VMSize != nil {
+		views = append(views, cmdctx.PresenterOption{Presentable: &presenters.VMResources{Size: *info.VMSize, Counts: info.VMCounts}, Title: "VMs"})
+	}
+	if len(info.Volumes) > 0 {
+		views = append(views, cmdctx.PresenterOption{Presentable: &presenters.Volumes{Volumes: info.Volumes}, Title: "Volumes"})
+	}
+	if len(info.Certificates) > 0 {
+		views = append(views, cmdctx.PresenterOption{Presentable: &presenters.Certificates{Certificates: info.Certificates}, Title: "Certificates"})
+	}
+
+	return ctx.Frender(views...)
+}
+
+// appInfo - everything info shows about an app, for structured output. The
+// app's own fields stay at the top level, as info printed them before.
+type appInfo struct {
+	api.AppCompact
+	Regions       []api.Region
+	BackupRegions []api.Region
+	VMSize        *api.VMSize `json:",omitempty"`
+	// VMCounts is how many VMs each process group runs
+	VMCounts     []api.TaskGroupCount
+	Volumes      []api.Volume
+	Certificates []api.AppCertificateCompact
 }
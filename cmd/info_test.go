@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestAppInfoJSON(t *testing.T) {
+	info := appInfo{
+		AppCompact: api.AppCompact{Name: "web", Hostname: "web.fly.dev"},
+		Regions:    []api.Region{{Code: "iad"}},
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["Hostname"] != "web.fly.dev" || got["Name"] != "web" {
+		t.Errorf("expected the app's fields at the top level, got %s", data)
+	}
+	if _, ok := got["Regions"]; !ok {
+		t.Errorf("expected Regions beside them, got %s", data)
+	}
+}
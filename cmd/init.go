@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 //TODO: Move all output to status styled begin/done updates
@@ -78,6 +80,8 @@ func newInitCommand(client *client.Client) *Command {
 		Description: "Never write a fly.toml file",
 	})
 
+	addNetworkFlag(cmd)
+
 	cmd.AddBoolFlag(BoolFlagOpts{
 		Name:        "generatename",
 		Description: "Always generate a name for the app", Hidden: true,
@@ -119,7 +123,7 @@ func runInit(cmdCtx *cmdctx.CmdContext) error {
 		if helpers.FileExists(configfilename) {
 			if !overwrite {
 				cmdCtx.Status("init", cmdctx.SERROR, "An existing configuration file has been found.")
-				confirmation := confirm(fmt.Sprintf("Overwrite file '%s'", configfilename))
+				confirmation := confirm(fmt.Sprintf("Overwrite file '%s'", configfilename), "overwrite")
 				if !confirmation {
 					return nil
 				}
@@ -143,13 +147,14 @@ func runInit(cmdCtx *cmdctx.CmdContext) error {
 		fmt.Println()
 
 		if name == "" {
-			prompt := &survey.Input{
+			namePrompt := &survey.Input{
 				Message: "App Name (leave blank to use an auto-generated name)",
 			}
-			if err := survey.AskOne(prompt, &name); err != nil {
+			if err := prompt.Ask(namePrompt, &name, "name"); err != nil {
 				if isInterrupt(err) {
 					return nil
 				}
+				return err
 			}
 		} else {
 			fmt.Printf("Selected App Name: %s\n", name)
@@ -213,7 +218,16 @@ func runInit(cmdCtx *cmdctx.CmdContext) error {
 		}
 	}
 	// The creation magic happens here....
-	app, err := cmdCtx.Client.API().CreateApp(name, org.ID, nil)
+	network, err := networkFromFlag(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	app, err := cmdCtx.Client.API().CreateAppWithInput(api.CreateAppInput{
+		Name:           name,
+		OrganizationID: org.ID,
+		Network:        network,
+	})
 	if err != nil {
 		return err
 	}
@@ -224,7 +238,7 @@ func runInit(cmdCtx *cmdctx.CmdContext) error {
 			newAppConfig.Build = &flyctl.Build{Image: imagename}
 			newAppConfig.Definition = app.Config.Definition
 		} else if importfile != "" {
-			if !cmdCtx.OutputJSON() {
+			if !cmdCtx.OutputStructured() {
 				fmt.Printf("Importing configuration from %s\n", importfile)
 			}
 
@@ -252,12 +266,12 @@ func runInit(cmdCtx *cmdctx.CmdContext) error {
 				if err != nil {
 					return err
 				}
-				if !cmdCtx.OutputJSON() {
+				if !cmdCtx.OutputStructured() {
 					fmt.Printf("Importing port %d\n", currentport)
 				}
 			}
 		} else if builtinname != "" {
-			if !cmdCtx.OutputJSON() {
+			if !cmdCtx.OutputStructured() {
 				fmt.Printf("Builtins use port 8080\n")
 			}
 			newAppConfig.SetInternalPort(8080)
@@ -274,9 +288,8 @@ func runInit(cmdCtx *cmdctx.CmdContext) error {
 			newAppConfig.SetInternalPort(internalPort)
 		}
 
-		if cmdCtx.OutputJSON() {
-			cmdCtx.WriteJSON(app)
-			return nil
+		if cmdCtx.OutputStructured() {
+			return cmdCtx.WriteData(app)
 		}
 
 		err = cmdCtx.Frender(cmdctx.PresenterOption{Presentable: &presenters.AppInfo{App: *app}, HideHeader: true, Vertical: true, Title: "New app created"})
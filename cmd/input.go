@@ -13,18 +13,17 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/build/imgsrc/builtins"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func isInterrupt(err error) bool {
 	return err != nil && err.Error() == "interrupt"
 }
 
-func confirm(message string) bool {
-	confirm := false
-	prompt := &survey.Confirm{
-		Message: message,
-	}
-	err := survey.AskOne(prompt, &confirm)
+// confirm asks a yes/no question. flag names the option that skips it, for
+// the error shown when running non-interactively.
+func confirm(message string, flag string) bool {
+	confirm, err := prompt.Confirm(message, flag)
 	checkErr(err)
 
 	return confirm
@@ -60,12 +59,12 @@ func selectOrganization(client *api.Client, slug string, typeFilter *api.Organiz
 	}
 
 	selectedOrg := 0
-	prompt := &survey.Select{
+	orgPrompt := &survey.Select{
 		Message:  "Select organization:",
 		Options:  options,
 		PageSize: 15,
 	}
-	if err := survey.AskOne(prompt, &selectedOrg); err != nil {
+	if err := prompt.Ask(orgPrompt, &selectedOrg, "org"); err != nil {
 		return nil, err
 	}
 
@@ -95,23 +94,37 @@ func selectRegion(client *api.Client, regionCode string) (*api.Region, error) {
 	}
 
 	selectedRegion := 0
-	prompt := &survey.Select{
+	regionPrompt := &survey.Select{
 		Message:  "Select region:",
 		Options:  options,
 		PageSize: 15,
 	}
 
 	if requestRegion != nil {
-		prompt.Default = fmt.Sprintf("%s (%s)", requestRegion.Code, requestRegion.Name)
+		regionPrompt.Default = fmt.Sprintf("%s (%s)", requestRegion.Code, requestRegion.Name)
 	}
 
-	if err := survey.AskOne(prompt, &selectedRegion); err != nil {
+	if err := prompt.Ask(regionPrompt, &selectedRegion, "region"); err != nil {
 		return nil, err
 	}
 
 	return &regions[selectedRegion], nil
 }
 
+// findVMSize - the size called name, or a validation error listing the valid
+// size names
+func findVMSize(vmSizes []api.VMSize, name string) (*api.VMSize, error) {
+	names := make([]string, 0, len(vmSizes))
+	for i := range vmSizes {
+		if vmSizes[i].Name == name {
+			return &vmSizes[i], nil
+		}
+		names = append(names, vmSizes[i].Name)
+	}
+
+	return nil, &ValidationError{fmt.Errorf(`vm size "%s" not found, use one of %s (see 'flyctl platform vm-sizes')`, name, strings.Join(names, ", "))}
+}
+
 func selectVMSize(client *api.Client, vmSizeName string) (*api.VMSize, error) {
 	vmSizes, err := client.PlatformVMSizes()
 	if err != nil {
@@ -119,13 +132,7 @@ func selectVMSize(client *api.Client, vmSizeName string) (*api.VMSize, error) {
 	}
 
 	if vmSizeName != "" {
-		for _, vmSize := range vmSizes {
-			if vmSize.Name == vmSizeName {
-				return &vmSize, nil
-			}
-		}
-
-		return nil, fmt.Errorf(`vm size "%s" not found`, vmSizeName)
+		return findVMSize(vmSizes, vmSizeName)
 	}
 
 	options := []string{}
@@ -135,12 +142,12 @@ func selectVMSize(client *api.Client, vmSizeName string) (*api.VMSize, error) {
 	}
 
 	selectedVMSize := 0
-	prompt := &survey.Select{
+	vmSizePrompt := &survey.Select{
 		Message:  "Select VM size:",
 		Options:  options,
 		PageSize: 15,
 	}
-	if err := survey.AskOne(prompt, &selectedVMSize); err != nil {
+	if err := prompt.Ask(vmSizePrompt, &selectedVMSize, "vm-size"); err != nil {
 		return nil, err
 	}
 
@@ -148,11 +155,11 @@ func selectVMSize(client *api.Client, vmSizeName string) (*api.VMSize, error) {
 }
 
 func inputAppName(defaultName string) (name string, err error) {
-	prompt := &survey.Input{
+	namePrompt := &survey.Input{
 		Message: "App name:",
 		Default: defaultName,
 	}
-	if err := survey.AskOne(prompt, &name); err != nil {
+	if err := prompt.Ask(namePrompt, &name, "name"); err != nil {
 		return name, err
 	}
 
@@ -161,11 +168,11 @@ func inputAppName(defaultName string) (name string, err error) {
 
 func volumeSizeInput(client *api.Client, defaultVal int) (int, error) {
 	var volumeSize int
-	prompt := &survey.Input{
+	sizePrompt := &survey.Input{
 		Message: "Volume size (GB):",
 		Default: strconv.Itoa(defaultVal),
 	}
-	if err := survey.AskOne(prompt, &volumeSize); err != nil {
+	if err := prompt.Ask(sizePrompt, &volumeSize, "volume-size"); err != nil {
 		return 0, err
 	}
 
@@ -270,13 +277,13 @@ func selectBuildtype(commandContext *cmdctx.CmdContext) (string, bool, error) {
 
 	selectedBuilder := 0
 
-	prompt := &survey.Select{
+	builderPrompt := &survey.Select{
 		Message:  "Select builder:",
 		Options:  builders,
 		PageSize: 8,
 	}
 
-	if err := survey.AskOne(prompt, &selectedBuilder); err != nil {
+	if err := prompt.Ask(builderPrompt, &selectedBuilder, "builder"); err != nil {
 		return "", false, err
 	}
 
@@ -315,12 +322,12 @@ func selectBuiltin(commandContext *cmdctx.CmdContext) (string, error) {
 
 	selectedBuiltin := 0
 
-	prompt := &survey.Select{
+	builtinPrompt := &survey.Select{
 		Message:  "Select builtin:",
 		Options:  availablebuiltins,
 		PageSize: 8,
 	}
-	if err := survey.AskOne(prompt, &selectedBuiltin); err != nil {
+	if err := prompt.Ask(builtinPrompt, &selectedBuiltin, "builtin"); err != nil {
 		return "", err
 	}
 
@@ -329,10 +336,10 @@ func selectBuiltin(commandContext *cmdctx.CmdContext) (string, error) {
 }
 
 func selectImage(commandContext *cmdctx.CmdContext) (string, error) {
-	prompt := &survey.Input{Message: "Select Image:", Default: "flyio/hellofly:latest", Help: `The name and tag for the image you want to use.`}
+	imagePrompt := &survey.Input{Message: "Select Image:", Default: "flyio/hellofly:latest", Help: `The name and tag for the image you want to use.`}
 
 	sSelectedImage := ""
-	if err := survey.AskOne(prompt, &sSelectedImage /* survey.WithValidator(isIntPort) */); err != nil {
+	if err := prompt.Ask(imagePrompt, &sSelectedImage, "image" /* survey.WithValidator(isIntPort) */); err != nil {
 		return sSelectedImage, err
 	}
 
@@ -341,11 +348,11 @@ func selectImage(commandContext *cmdctx.CmdContext) (string, error) {
 
 func selectPort(commandContext *cmdctx.CmdContext, defport int) (int, error) {
 	sDefport := strconv.Itoa(defport)
-	prompt := &survey.Input{Message: "Select Internal Port:", Default: sDefport, Help: `The internal port is the port your application uses. External traffic will be directed to this port.
+	portPrompt := &survey.Input{Message: "Select Internal Port:", Default: sDefport, Help: `The internal port is the port your application uses. External traffic will be directed to this port.
 If incorrectly set, health checks may fail and your application deployment will fail.`}
 
 	sSelectedPort := ""
-	if err := survey.AskOne(prompt, &sSelectedPort, survey.WithValidator(isIntPort)); err != nil {
+	if err := prompt.Ask(portPrompt, &sSelectedPort, "port", survey.WithValidator(isIntPort)); err != nil {
 		return -1, err
 	}
 	selectedPort, err := strconv.Atoi(sSelectedPort)
@@ -378,10 +385,10 @@ func isIntPort(val interface{}) error {
 }
 
 func inputUserEmail() (email string, err error) {
-	prompt := &survey.Input{
+	emailPrompt := &survey.Input{
 		Message: "User email:",
 	}
-	if err := survey.AskOne(prompt, &email); err != nil {
+	if err := prompt.Ask(emailPrompt, &email, ""); err != nil {
 		return email, err
 	}
 
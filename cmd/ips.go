@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/terminal"
 
 	"github.com/superfly/flyctl/docstrings"
 
@@ -27,30 +33,64 @@ func newIPAddressesCommand(client *client.Client) *Command {
 	BuildCommandKS(cmd, runPrivateIPAddressesList, ipsPrivateListStrings, client, requireSession, requireAppName)
 
 	ipsAllocateV4Strings := docstrings.Get("ips.allocate-v4")
-	BuildCommandKS(cmd, runAllocateIPAddressV4, ipsAllocateV4Strings, client, requireSession, requireAppName)
+	allocateV4 := BuildCommandKS(cmd, runAllocateIPAddressV4, ipsAllocateV4Strings, client, requireSession, requireAppName)
+	allocateV4.AddBoolFlag(BoolFlagOpts{
+		Name:        "shared",
+		Description: "Allocate a shared IPv4 address, routed to the app by hostname",
+	})
+	addIPRegionFlag(allocateV4)
 
 	ipsAllocateV6Strings := docstrings.Get("ips.allocate-v6")
-	BuildCommandKS(cmd, runAllocateIPAddressV6, ipsAllocateV6Strings, client, requireSession, requireAppName)
+	allocateV6 := BuildCommandKS(cmd, runAllocateIPAddressV6, ipsAllocateV6Strings, client, requireSession, requireAppName)
+	addIPRegionFlag(allocateV6)
 
 	ipsReleaseStrings := docstrings.Get("ips.release")
 	release := BuildCommandKS(cmd, runReleaseIPAddress, ipsReleaseStrings, client, requireSession, requireAppName)
 	release.Args = cobra.ExactArgs(1)
+	release.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	ipsMoveStrings := docstrings.Get("ips.move")
+	move := BuildCommandKS(cmd, runMoveIPAddress, ipsMoveStrings, client, requireSession)
+	move.Args = cobra.ExactArgs(1)
+	move.AddStringFlag(StringFlagOpts{Name: "from", Description: "The app the address is allocated to"})
+	move.AddStringFlag(StringFlagOpts{Name: "to", Description: "The app to move the address to"})
+	move.AddBoolFlag(BoolFlagOpts{Name: "force", Description: "Move the address even if the target app isn't healthy"})
+	move.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
 
 	return cmd
 }
 
+func addIPRegionFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "Announce the address only from this region, rather than every region",
+	})
+}
+
 func runIPAddressesList(commandContext *cmdctx.CmdContext) error {
 	ipAddresses, err := commandContext.Client.API().GetIPAddresses(commandContext.AppName)
 	if err != nil {
 		return err
 	}
 
+	certificates, err := certificatesByAddress(commandContext, ipAddresses)
+	if err != nil {
+		return err
+	}
+
 	return commandContext.Frender(cmdctx.PresenterOption{
-		Presentable: &presenters.IPAddresses{IPAddresses: ipAddresses},
+		Presentable: &presenters.IPAddresses{IPAddresses: ipAddresses, Certificates: certificates},
 	})
 }
 
 func runAllocateIPAddressV4(ctx *cmdctx.CmdContext) error {
+	if ctx.Config.GetBool("shared") {
+		if ctx.Config.GetString("region") != "" {
+			return &ValidationError{fmt.Errorf("shared IPv4 addresses are announced from every region, --region can't be used with --shared")}
+		}
+		return runAllocateIPAddress(ctx, "shared_v4")
+	}
 	return runAllocateIPAddress(ctx, "v4")
 }
 
@@ -60,8 +100,9 @@ func runAllocateIPAddressV6(ctx *cmdctx.CmdContext) error {
 
 func runAllocateIPAddress(commandContext *cmdctx.CmdContext, addrType string) error {
 	appName := commandContext.AppName
+	region := strings.ToLower(commandContext.Config.GetString("region"))
 
-	ipAddress, err := commandContext.Client.API().AllocateIPAddress(appName, addrType)
+	ipAddress, err := commandContext.Client.API().AllocateIPAddress(appName, addrType, region)
 	if err != nil {
 		return err
 	}
@@ -84,15 +125,88 @@ func runReleaseIPAddress(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
+	if !commandContext.Config.GetBool("yes") {
+		certificates, err := certificatesByAddress(commandContext, []api.IPAddress{*ipAddress})
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("Release %s from %s?", ipAddress.Address, appName)
+		if hostnames := certificates[ipAddress.Address]; len(hostnames) > 0 {
+			commandContext.Statusf("ips", cmdctx.SWARN, "These certificates' hostnames resolve to %s and will stop reaching the app: %s\n", ipAddress.Address, strings.Join(hostnames, ", "))
+		}
+		if !confirm(message, "yes") {
+			return nil
+		}
+	}
+
 	if err := commandContext.Client.API().ReleaseIPAddress(ipAddress.ID); err != nil {
 		return err
 	}
 
+	if commandContext.OutputStructured() {
+		return commandContext.WriteData(ipAddress)
+	}
+
 	fmt.Printf("Released %s from %s\n", ipAddress.Address, appName)
 
 	return nil
 }
 
+// certificateLookupTimeout bounds resolving the hostnames of the app's
+// certificates
+const certificateLookupTimeout = 5 * time.Second
+
+// certificatesByAddress - the hostnames of the app's certificates whose DNS
+// resolves to each of addresses, keyed by address. Hostnames that don't
+// resolve are left out.
+func certificatesByAddress(ctx *cmdctx.CmdContext, addresses []api.IPAddress) (map[string][]string, error) {
+	certs, err := ctx.Client.API().GetAppCertificates(ctx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupCtx, cancel := context.WithTimeout(context.Background(), certificateLookupTimeout)
+	defer cancel()
+
+	out := map[string][]string{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, cert := range certs {
+		wg.Add(1)
+		go func(hostname string) {
+			defer wg.Done()
+
+			resolved, err := net.DefaultResolver.LookupIPAddr(lookupCtx, hostname)
+			if err != nil {
+				terminal.Debugf("resolving %s failed: %v\n", hostname, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, addr := range addresses {
+				ip := net.ParseIP(addr.Address)
+				for _, r := range resolved {
+					if r.IP.Equal(ip) {
+						out[addr.Address] = append(out[addr.Address], hostname)
+						break
+					}
+				}
+			}
+		}(cert.Hostname)
+	}
+
+	wg.Wait()
+
+	for _, hostnames := range out {
+		sort.Strings(hostnames)
+	}
+
+	return out, nil
+}
+
 func runPrivateIPAddressesList(commandContext *cmdctx.CmdContext) error {
 	appstatus, err := commandContext.Client.API().GetAppStatus(commandContext.AppName, false)
 	if err != nil {
@@ -105,12 +219,11 @@ func runPrivateIPAddressesList(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if commandContext.OutputJSON() {
-		commandContext.WriteJSON(appstatus.Allocations)
-		return nil
+	if commandContext.OutputStructured() {
+		return commandContext.WriteData(appstatus.Allocations)
 	}
 
-	table := helpers.MakeSimpleTable(commandContext.Out, []string{"ID", "Region", "IP"})
+	table := helpers.MakeSimpleTable(commandContext.Out, []string{"ID", "Process", "Region", "Status", "IP"})
 
 	for _, alloc := range appstatus.Allocations {
 
@@ -124,7 +237,7 @@ func runPrivateIPAddressesList(commandContext *cmdctx.CmdContext) error {
 			}
 		}
 
-		table.Append([]string{alloc.IDShort, region, alloc.PrivateIP})
+		table.Append([]string{alloc.IDShort, alloc.TaskName, region, alloc.Status, alloc.PrivateIP})
 	}
 
 	table.Render()
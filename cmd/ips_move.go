@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/internal/deployment"
+)
+
+func runMoveIPAddress(ctx *cmdctx.CmdContext) error {
+	address := ctx.Args[0]
+	from := ctx.Config.GetString("from")
+	to := ctx.Config.GetString("to")
+
+	if ip := net.ParseIP(address); ip == nil {
+		return fmt.Errorf("Invalid IP address: '%s'", address)
+	}
+	if from == "" || to == "" {
+		return &ValidationError{errors.New("--from and --to are both required")}
+	}
+	if from == to {
+		return &ValidationError{fmt.Errorf("%s is already the address's app", to)}
+	}
+
+	ipAddress, err := ctx.Client.API().FindIPAddress(from, address)
+	if err != nil {
+		return err
+	}
+	if ipAddress == nil {
+		return fmt.Errorf("%s isn't allocated to %s", address, from)
+	}
+	if ipAddress.Type == "shared_v4" {
+		return fmt.Errorf("%s is a shared address, routed by hostname; move the certificates instead", address)
+	}
+
+	source, err := ctx.Client.API().GetApp(from)
+	if err != nil {
+		return err
+	}
+	target, err := ctx.Client.API().GetApp(to)
+	if err != nil {
+		return err
+	}
+	if source.Organization.ID != target.Organization.ID {
+		return fmt.Errorf("%s and %s are in different organizations, addresses can only move within one", from, to)
+	}
+
+	if err := checkIPMoveTarget(ctx, to, ipAddress); err != nil {
+		if !ctx.Config.GetBool("force") {
+			return fmt.Errorf("%w; use --force to move the address anyway", err)
+		}
+		ctx.Statusf("ips", cmdctx.SWARN, "Moving anyway because of --force: %s\n", err)
+	} else {
+		ctx.Statusf("ips", cmdctx.SDONE, "%s is healthy and can take over %s\n", to, address)
+	}
+
+	// hostnames pointed at the address need certificates on the target too
+	ctx.AppName = from
+	certificates, err := certificatesByAddress(ctx, []api.IPAddress{*ipAddress})
+	if err != nil {
+		return err
+	}
+	if hostnames := certificates[ipAddress.Address]; len(hostnames) > 0 {
+		ctx.Statusf("ips", cmdctx.SWARN, "These hostnames resolve to %s, make sure %s has certificates for them: %s\n", address, to, strings.Join(hostnames, ", "))
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Move %s from %s to %s?", address, from, to), "yes") {
+			return nil
+		}
+	}
+
+	moved, err := ctx.Client.API().MoveIPAddress(ipAddress.ID, to)
+	if err != nil {
+		return err
+	}
+
+	// make sure the target has it before reporting success
+	if found, err := ctx.Client.API().FindIPAddress(to, address); err != nil {
+		return err
+	} else if found == nil {
+		return fmt.Errorf("%s was moved but isn't listed on %s yet, check with `flyctl ips list -a %s`", address, to, to)
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(moved)
+	}
+
+	ctx.Statusf("ips", cmdctx.SDONE, "Moved %s from %s to %s\n", moved.Address, from, to)
+
+	return nil
+}
+
+// checkIPMoveTarget - fails unless the app taking over an address has
+// healthy instances to serve it, in the address's region when it's regional
+func checkIPMoveTarget(ctx *cmdctx.CmdContext, appName string, ipAddress *api.IPAddress) error {
+	status, err := ctx.Client.API().GetAppStatus(appName, false)
+	if err != nil {
+		return fmt.Errorf("check %s's health: %w", appName, err)
+	}
+
+	running := 0
+	for _, alloc := range status.Allocations {
+		if alloc.Status != "running" {
+			continue
+		}
+		if ipAddress.Region != "" && alloc.Region != ipAddress.Region {
+			continue
+		}
+		running++
+	}
+	if running == 0 {
+		if ipAddress.Region != "" {
+			return fmt.Errorf("%s has no running instances in %s, where %s is announced", appName, ipAddress.Region, ipAddress.Address)
+		}
+		return fmt.Errorf("%s has no running instances", appName)
+	}
+
+	guard := deployment.Guard{}
+	if reasons := guard.Assess(status.Allocations, time.Now()); len(reasons) > 0 {
+		return fmt.Errorf("%s is degraded: %s", appName, strings.Join(reasons, "; "))
+	}
+
+	return nil
+}
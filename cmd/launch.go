@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -15,6 +16,7 @@ import (
 	"github.com/superfly/flyctl/internal/sourcecode"
 
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func newLaunchCommand(client *client.Client) *Command {
@@ -27,6 +29,8 @@ func newLaunchCommand(client *client.Client) *Command {
 	launchCmd.AddStringFlag(StringFlagOpts{Name: "region", Description: "the region to launch the new app in"})
 	launchCmd.AddStringFlag(StringFlagOpts{Name: "image", Description: "the image to launch"})
 	launchCmd.AddBoolFlag(BoolFlagOpts{Name: "now", Description: "deploy now without confirmation", Default: false})
+	launchCmd.AddBoolFlag(BoolFlagOpts{Name: "consul", Description: "attach Consul without confirmation, when LiteFS is detected"})
+	addNetworkFlag(launchCmd)
 
 	return launchCmd
 }
@@ -75,7 +79,7 @@ func runLaunch(cmdctx *cmdctx.CmdContext) error {
 			cmdctx.AppName = cfg.AppName
 			cmdctx.AppConfig = cfg
 			return runDeploy(cmdctx)
-		} else if confirm("Would you like to copy its configuration to the new app?") {
+		} else if confirm("Would you like to copy its configuration to the new app?", "") {
 			appConfig.Definition = cfg.Definition
 			importedConfig = true
 		}
@@ -133,7 +137,17 @@ func runLaunch(cmdctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	app, err := cmdctx.Client.API().CreateApp(appName, org.ID, &region.Code)
+	network, err := networkFromFlag(cmdctx)
+	if err != nil {
+		return err
+	}
+
+	app, err := cmdctx.Client.API().CreateAppWithInput(api.CreateAppInput{
+		Name:            appName,
+		OrganizationID:  org.ID,
+		PreferredRegion: &region.Code,
+		Network:         network,
+	})
 	if err != nil {
 		return err
 	}
@@ -146,8 +160,15 @@ func runLaunch(cmdctx *cmdctx.CmdContext) error {
 	cmdctx.AppConfig = appConfig
 
 	if srcInfo != nil && (len(srcInfo.Buildpacks) > 0 || srcInfo.Builder != "") {
-		appConfig.SetInternalPort(8080)
-		appConfig.SetEnvVariable("PORT", "8080")
+		appConfig.SetInternalPort(srcInfo.Port)
+		appConfig.SetEnvVariable("PORT", strconv.Itoa(srcInfo.Port))
+	}
+
+	var processCounts map[string]int
+	if srcInfo != nil && !importedConfig {
+		if processCounts, err = configureProcesses(cmdctx, appConfig, srcInfo); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Created app %s in organization %s\n", app.Name, org.Slug)
@@ -158,11 +179,13 @@ func runLaunch(cmdctx *cmdctx.CmdContext) error {
 
 		for k, v := range srcInfo.Secrets {
 			val := ""
-			prompt := fmt.Sprintf("Set secret %s:", k)
-			survey.AskOne(&survey.Input{
-				Message: prompt,
+			secretPrompt := &survey.Input{
+				Message: fmt.Sprintf("Set secret %s:", k),
 				Help:    v,
-			}, &val)
+			}
+			if err := prompt.Ask(secretPrompt, &val, ""); err != nil {
+				return err
+			}
 
 			if val != "" {
 				secrets[k] = val
@@ -180,6 +203,12 @@ func runLaunch(cmdctx *cmdctx.CmdContext) error {
 		}
 	}
 
+	if srcInfo != nil && srcInfo.LiteFS {
+		if err := offerConsul(cmdctx, app); err != nil {
+			return err
+		}
+	}
+
 	if err := writeAppConfig(filepath.Join(dir, "fly.toml"), appConfig); err != nil {
 		return err
 	}
@@ -190,11 +219,39 @@ func runLaunch(cmdctx *cmdctx.CmdContext) error {
 
 	fmt.Println("Your app is ready. Deploy with `flyctl deploy`")
 
-	if !cmdctx.Config.GetBool("now") && !confirm("Would you like to deploy now?") {
+	if !cmdctx.Config.GetBool("now") && !confirm("Would you like to deploy now?", "now") {
+		if len(processCounts) > 0 {
+			printScaleProcessGroups(cmdctx, processCounts)
+		}
+		return nil
+	}
+
+	if err := runDeploy(cmdctx); err != nil {
+		return err
+	}
+
+	if len(processCounts) > 0 {
+		return scaleProcessGroups(cmdctx, processCounts)
+	}
+
+	return nil
+}
+
+// offerConsul - attaches Consul to a LiteFS app, which needs it to elect a
+// primary, when --consul is given or the user agrees
+func offerConsul(ctx *cmdctx.CmdContext, app *api.App) error {
+	ctx.Status("launch", cmdctx.SINFO, "Detected LiteFS, which needs Consul to elect a primary")
+
+	attach := ctx.Config.GetBool("consul")
+	if !attach && prompt.IsInteractive() {
+		attach = confirm("Would you like to attach Consul now?", "consul")
+	}
+	if !attach {
+		ctx.Status("launch", cmdctx.SINFO, "Attach it before deploying with `flyctl consul attach`")
 		return nil
 	}
 
-	return runDeploy(cmdctx)
+	return attachConsul(ctx, app, defaultConsulVariable)
 }
 
 func shouldDeployExistingApp(cc *cmdctx.CmdContext, appName string) (bool, error) {
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/sourcecode"
+)
+
+// Instances launched for each process group. Groups serving the app's
+// services get two so one can be replaced while the other serves requests.
+const (
+	publicProcessCount  = 2
+	privateProcessCount = 1
+)
+
+// configureProcesses - maps a Procfile's process types to process groups in
+// appConfig, routes the services to the groups chosen to be public and turns
+// its release process into the release command. It returns the instance
+// count for each group, nil when there's only one process.
+func configureProcesses(ctx *cmdctx.CmdContext, appConfig *flyctl.AppConfig, srcInfo *sourcecode.SourceInfo) (map[string]int, error) {
+	if srcInfo.ReleaseCommand != "" {
+		deploy, _ := appConfig.Definition["deploy"].(map[string]interface{})
+		if deploy == nil {
+			deploy = map[string]interface{}{}
+		}
+		deploy["release_command"] = srcInfo.ReleaseCommand
+		appConfig.Definition["deploy"] = deploy
+
+		ctx.Statusf("launch", cmdctx.SINFO, "Using the Procfile's release process as the release command: %s\n", srcInfo.ReleaseCommand)
+	}
+
+	if len(srcInfo.Processes) < 2 {
+		return nil, nil
+	}
+
+	processes := map[string]interface{}{}
+	names := []string{}
+	for _, p := range srcInfo.Processes {
+		processes[p.Name] = p.Command
+		names = append(names, p.Name)
+	}
+	appConfig.Definition["processes"] = processes
+
+	ctx.Statusf("launch", cmdctx.SINFO, "Found processes in the Procfile: %s\n", strings.Join(names, ", "))
+
+	public, err := selectPublicProcesses(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(public) == 0 {
+		delete(appConfig.Definition, "services")
+	} else if services, ok := appConfig.Definition["services"].([]interface{}); ok {
+		for _, s := range services {
+			if service, ok := s.(map[string]interface{}); ok {
+				service["processes"] = public
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	for _, name := range names {
+		counts[name] = privateProcessCount
+	}
+	for _, name := range public {
+		counts[name] = publicProcessCount
+	}
+
+	return counts, nil
+}
+
+// selectPublicProcesses - asks which processes receive the app's services,
+// defaulting to web, or the first process when there's no web process
+func selectPublicProcesses(ctx *cmdctx.CmdContext, names []string) ([]string, error) {
+	defaults := []string{names[0]}
+	for _, name := range names {
+		if name == "web" {
+			defaults = []string{name}
+		}
+	}
+
+	indexes, err := prompt.MultiSelect("Which processes should receive public traffic?", names, defaults, "")
+
+	switch {
+	case prompt.IsNonInteractiveError(err):
+		return defaults, nil
+	case err != nil:
+		return nil, err
+	case len(indexes) == 0:
+		ctx.Status("launch", cmdctx.SWARN, "No processes will receive public traffic, the app's services will be removed")
+	}
+
+	selected := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		selected = append(selected, names[i])
+	}
+	return selected, nil
+}
+
+// scaleProcessGroups - sets the instance count of each process group
+func scaleProcessGroups(ctx *cmdctx.CmdContext, counts map[string]int) error {
+	input := make([]api.VMCountInput, 0, len(counts))
+	for group, count := range counts {
+		input = append(input, api.VMCountInput{Group: group, Count: count})
+	}
+	sort.Slice(input, func(i, j int) bool { return input[i].Group < input[j].Group })
+
+	if _, _, err := ctx.Client.API().SetAppGroupVMCounts(ctx.AppName, input); err != nil {
+		return err
+	}
+
+	ctx.Statusf("launch", cmdctx.SDONE, "Scaled process groups: %s\n", formatProcessCounts(counts))
+	return nil
+}
+
+// printScaleProcessGroups - how to scale the process groups once deployed,
+// when launch doesn't deploy
+func printScaleProcessGroups(ctx *cmdctx.CmdContext, counts map[string]int) {
+	ctx.Statusf("launch", cmdctx.SINFO, "After deploying, scale the process groups with `flyctl scale count %s`\n", formatProcessCounts(counts))
+}
+
+// formatProcessCounts - counts as "web=2 worker=1", as scale count takes them
+func formatProcessCounts(counts map[string]int) string {
+	pairs := make([]string, 0, len(counts))
+	for group, count := range counts {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", group, count))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -65,7 +65,7 @@ type appCondensed struct {
 
 func runListApps(commandContext *cmdctx.CmdContext) error {
 
-	asJSON := commandContext.OutputJSON()
+	structured := commandContext.OutputStructured()
 
 	appPart := ""
 
@@ -137,9 +137,8 @@ func runListApps(commandContext *cmdctx.CmdContext) error {
 		sort.Slice(filteredApps, func(i, j int) bool { return filteredApps[i].Name < filteredApps[j].Name })
 	}
 
-	if asJSON {
-		commandContext.WriteJSON(filteredApps)
-		return nil
+	if structured {
+		return commandContext.WriteData(filteredApps)
 	}
 
 	table := helpers.MakeSimpleTable(commandContext.Out, []string{"Name", "Status", "Org", "Deployed"})
@@ -163,7 +162,7 @@ func runListApps(commandContext *cmdctx.CmdContext) error {
 }
 
 func runListOrgs(commandContext *cmdctx.CmdContext) error {
-	asJSON := commandContext.OutputJSON()
+	structured := commandContext.OutputStructured()
 
 	orgs, err := commandContext.Client.API().GetOrganizations(nil)
 
@@ -171,9 +170,8 @@ func runListOrgs(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if asJSON {
-		commandContext.WriteJSON(orgs)
-		return nil
+	if structured {
+		return commandContext.WriteData(orgs)
 	}
 
 	table := helpers.MakeSimpleTable(commandContext.Out, []string{"Name", "Slug", "Type"})
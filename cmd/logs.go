@@ -24,6 +24,9 @@ func newLogsCommand(client *client.Client) *Command {
 		Description: "Filter by region",
 	})
 
+	newLogsShipCommand(cmd, client)
+	newLogsExportCommand(cmd, client)
+
 	return cmd
 }
 
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+// gcsEndpoint - Google Cloud Storage's S3 compatible API, used with HMAC keys
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// logExportPartitions - how long each exported file covers
+var logExportPartitions = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+}
+
+func newLogsExportCommand(parent *Command, client *client.Client) {
+	exportStrings := docstrings.Get("logs.export")
+	exportCmd := BuildCommandKS(parent, runLogsExport, exportStrings, client, requireSession, requireAppName)
+
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "to",
+		Description: "Where to write the logs, as s3://bucket/prefix, gs://bucket/prefix or a local directory",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "window",
+		Shorthand:   "w",
+		Description: "How far back to export, e.g. 24h or 7d",
+		Default:     "24h",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "partition",
+		Description: "How much each file covers: hour or day",
+		Default:     "hour",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "access-key-id",
+		Description: "The access key ID for the bucket, or the HMAC key ID for GCS",
+		EnvName:     "AWS_ACCESS_KEY_ID",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "secret-access-key",
+		Description: "The secret access key for the bucket, or the HMAC secret for GCS",
+		EnvName:     "AWS_SECRET_ACCESS_KEY",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "session-token",
+		Description: "The session token for temporary credentials",
+		EnvName:     "AWS_SESSION_TOKEN",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "s3-region",
+		Description: "The bucket's region",
+		Default:     s3.DefaultRegion,
+		EnvName:     "AWS_REGION",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "endpoint",
+		Description: "The URL of S3 compatible storage, when it isn't AWS",
+		EnvName:     "AWS_ENDPOINT_URL",
+	})
+	exportCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "restart",
+		Description: "Start the export over instead of resuming an interrupted one",
+	})
+}
+
+// logExportDestination is where exported files are written, a bucket and
+// prefix or a local directory
+type logExportDestination struct {
+	url    string
+	bucket string
+	prefix string
+	store  *s3.Client
+}
+
+func newLogExportDestination(ctx *cmdctx.CmdContext, to string) (*logExportDestination, error) {
+	u, err := url.Parse(to)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// a local directory, allowing for Windows drive letters
+		dir, err := filepath.Abs(to)
+		if err != nil {
+			return nil, err
+		}
+		return &logExportDestination{url: dir, prefix: dir}, nil
+	}
+
+	store := &s3.Client{
+		AccessKeyID:     ctx.Config.GetString("access-key-id"),
+		SecretAccessKey: ctx.Config.GetString("secret-access-key"),
+		SessionToken:    ctx.Config.GetString("session-token"),
+		Region:          ctx.Config.GetString("s3-region"),
+		Endpoint:        ctx.Config.GetString("endpoint"),
+	}
+	switch u.Scheme {
+	case "s3":
+	case "gs", "gcs":
+		if store.Endpoint == "" {
+			store.Endpoint = gcsEndpoint
+		}
+		if !ctx.Config.IsSet("s3-region") {
+			store.Region = "auto"
+		}
+	default:
+		return nil, &ValidationError{fmt.Errorf("can't export to %s URLs, use s3://, gs:// or a local directory", u.Scheme)}
+	}
+	if u.Host == "" {
+		return nil, &ValidationError{fmt.Errorf("invalid destination %q, use %s://bucket/prefix", to, u.Scheme)}
+	}
+	if store.AccessKeyID == "" || store.SecretAccessKey == "" {
+		return nil, &ValidationError{fmt.Errorf("credentials are required, set --access-key-id and --secret-access-key or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")}
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+	return &logExportDestination{
+		url:    strings.TrimSuffix(u.Scheme+"://"+u.Host+"/"+prefix, "/"),
+		bucket: u.Host,
+		prefix: prefix,
+		store:  store,
+	}, nil
+}
+
+// write - writes a file named by a slash separated path under the
+// destination, replacing any written by an earlier export
+func (d *logExportDestination) write(ctx context.Context, name string, data []byte) error {
+	if d.store != nil {
+		return d.store.PutObject(ctx, s3.Location{Bucket: d.bucket, Key: path.Join(d.prefix, name)}, data)
+	}
+
+	file := filepath.Join(d.prefix, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	// written aside and renamed so an interruption never leaves half a file
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// logExport is the progress of an export, saved after each file so an
+// interrupted export can be resumed
+type logExport struct {
+	App         string    `json:"app"`
+	Destination string    `json:"destination"`
+	Partition   string    `json:"partition"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	// Next is the start of the first partition not yet exported
+	Next    time.Time `json:"next"`
+	Files   int       `json:"files"`
+	Entries int       `json:"entries"`
+}
+
+func logExportPath(app string) string {
+	return filepath.Join(flyctl.ConfigDir(), "log-exports", app+".json")
+}
+
+func loadLogExport(app string) (*logExport, error) {
+	data, err := os.ReadFile(logExportPath(app))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var export logExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (e *logExport) save() error {
+	path := logExportPath(e.App)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (e *logExport) remove() error {
+	err := os.Remove(logExportPath(e.App))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// logExportFileName - the file a partition is written to, e.g.
+// app/2021/01/02/03.ndjson.gz, or app/2021/01/02.ndjson.gz by day
+func logExportFileName(app string, partition string, start time.Time) string {
+	layout := "2006/01/02/15"
+	if partition == "day" {
+		layout = "2006/01/02"
+	}
+	return app + "/" + start.UTC().Format(layout) + ".ndjson.gz"
+}
+
+// exportedLogEntry is a line of an exported file
+type exportedLogEntry struct {
+	Timestamp string      `json:"timestamp"`
+	Level     string      `json:"level"`
+	Region    string      `json:"region"`
+	Instance  string      `json:"instance"`
+	Message   string      `json:"message"`
+	Meta      interface{} `json:"meta"`
+}
+
+func runLogsExport(ctx *cmdctx.CmdContext) error {
+	to := ctx.Config.GetString("to")
+	if to == "" {
+		return &ValidationError{fmt.Errorf("--to is required, e.g. --to s3://bucket/logs or --to ./logs")}
+	}
+	dest, err := newLogExportDestination(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	partition := ctx.Config.GetString("partition")
+	size, ok := logExportPartitions[partition]
+	if !ok {
+		return &ValidationError{fmt.Errorf("unknown partition %q, use hour or day", partition)}
+	}
+	window, err := helpers.ParseDuration(ctx.Config.GetString("window"))
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid --window: %w", err)}
+	}
+	if window < size {
+		return &ValidationError{fmt.Errorf("--window must be at least a %s when partitioning by %s", partition, partition)}
+	}
+
+	export, err := resumeLogExport(ctx, dest, partition, size, window)
+	if err != nil {
+		return err
+	}
+
+	cancelCtx := createCancellableContext()
+
+	for export.Next.Before(export.End) {
+		start := export.Next
+		end := start.Add(logExportPartitions[export.Partition])
+
+		data, entries, err := fetchLogPartition(cancelCtx, ctx.Client.API(), export.App, start, end)
+		if err == nil && entries > 0 {
+			name := logExportFileName(export.App, export.Partition, start)
+			if err = dest.write(cancelCtx, name, data); err != nil {
+				err = fmt.Errorf("write %s: %w", name, err)
+			} else {
+				export.Files++
+			}
+		}
+		if err != nil {
+			ctx.Statusf("logs", cmdctx.SINFO, "Run the same command again to resume the export from %s\n", start.Format(time.RFC3339))
+			return err
+		}
+
+		export.Entries += entries
+		export.Next = end
+		if err := export.save(); err != nil {
+			return fmt.Errorf("save export progress: %w", err)
+		}
+
+		if !ctx.OutputStructured() {
+			ctx.Statusf("logs", cmdctx.SDETAIL, "%s: %d entries\n", start.Format(time.RFC3339), entries)
+		}
+	}
+
+	if err := export.remove(); err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(map[string]interface{}{
+			"app":         export.App,
+			"destination": export.Destination,
+			"start":       export.Start,
+			"end":         export.End,
+			"files":       export.Files,
+			"entries":     export.Entries,
+		})
+	}
+
+	ctx.Statusf("logs", cmdctx.SDONE, "Exported %d log entries from %s to %s into %d files at %s\n",
+		export.Entries, export.Start.Format(time.RFC3339), export.End.Format(time.RFC3339), export.Files, export.Destination)
+
+	return nil
+}
+
+// resumeLogExport - the saved progress of an export of the app to dest, or
+// a new export of the window up to the start of the current partition
+func resumeLogExport(ctx *cmdctx.CmdContext, dest *logExportDestination, partition string, size time.Duration, window time.Duration) (*logExport, error) {
+	saved, err := loadLogExport(ctx.AppName)
+	if err != nil {
+		return nil, fmt.Errorf("read export progress: %w", err)
+	}
+
+	switch {
+	case saved == nil:
+	case ctx.Config.GetBool("restart"):
+		saved = nil
+	case saved.Destination != dest.url:
+		ctx.Statusf("logs", cmdctx.SWARN, "Discarding the progress of an export to %s\n", saved.Destination)
+		saved = nil
+	default:
+		ctx.Statusf("logs", cmdctx.SINFO, "Resuming the export of %s to %s from %s, use --restart to export a new window\n",
+			saved.Start.Format(time.RFC3339), saved.End.Format(time.RFC3339), saved.Next.Format(time.RFC3339))
+		return saved, nil
+	}
+
+	// only whole partitions are exported, so running the same export again
+	// later writes the same files
+	end := time.Now().UTC().Truncate(size)
+	start := end.Add(-window).Truncate(size)
+
+	export := &logExport{
+		App:         ctx.AppName,
+		Destination: dest.url,
+		Partition:   partition,
+		Start:       start,
+		End:         end,
+		Next:        start,
+	}
+	if err := export.save(); err != nil {
+		return nil, fmt.Errorf("save export progress: %w", err)
+	}
+
+	ctx.Statusf("logs", cmdctx.SBEGIN, "Exporting the logs of %s from %s to %s to %s\n", ctx.AppName, start.Format(time.RFC3339), end.Format(time.RFC3339), dest.url)
+	return export, nil
+}
+
+// fetchLogPartition - the app's logs from start up to end as gzipped JSON
+// lines, and how many entries there are
+func fetchLogPartition(ctx context.Context, client *api.Client, app string, start time.Time, end time.Time) ([]byte, int, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+
+	entries := 0
+	token := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+
+		page, next, err := client.GetAppLogsBetween(app, start, end, token)
+		if err != nil {
+			return nil, 0, fmt.Errorf("fetch logs: %w", err)
+		}
+
+		for _, entry := range page {
+			ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+			if err == nil && (ts.Before(start) || !ts.Before(end)) {
+				continue
+			}
+
+			record := exportedLogEntry{
+				Timestamp: entry.Timestamp,
+				Level:     entry.Level,
+				Region:    entry.Region,
+				Instance:  entry.Instance,
+				Message:   entry.Message,
+				Meta:      entry.Meta,
+			}
+			if record.Region == "" {
+				record.Region = entry.Meta.Region
+			}
+			if record.Instance == "" {
+				record.Instance = entry.Meta.Instance
+			}
+			if err := enc.Encode(record); err != nil {
+				return nil, 0, err
+			}
+			entries++
+		}
+
+		if len(page) == 0 || next == "" || next == token {
+			break
+		}
+		token = next
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), entries, nil
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+// logShipDestinations - where logs can be shipped to
+var logShipDestinations = []string{"datadog", "s3", "http"}
+
+func newLogsShipCommand(parent *Command, client *client.Client) {
+	shipStrings := docstrings.Get("logs.ship")
+	shipCmd := BuildCommandKS(parent, nil, shipStrings, client, requireSession)
+
+	createStrings := docstrings.Get("logs.ship.create")
+	createCmd := BuildCommandKS(shipCmd, runLogsShipCreate, createStrings, client, requireSession)
+	addLogsShipOrgFlag(createCmd)
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "destination",
+		Shorthand:   "d",
+		Description: "Where to ship logs: " + strings.Join(logShipDestinations, ", "),
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Shorthand:   "n",
+		Description: "A name for the shipper, defaults to the destination",
+	})
+	createCmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "app",
+		Shorthand:   "a",
+		Description: "Only ship the logs of these apps, defaults to every app in the organization",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "token",
+		Description: "The Datadog API key, or a bearer token for http",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "site",
+		Description: "The Datadog site to ship to",
+		Default:     "datadoghq.com",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "url",
+		Description: "The URL to POST logs to, for http",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "bucket",
+		Description: "The bucket and optional prefix to write logs to, as s3://bucket/prefix",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "access-key-id",
+		Description: "The access key ID for the bucket",
+		EnvName:     "AWS_ACCESS_KEY_ID",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "secret-access-key",
+		Description: "The secret access key for the bucket",
+		EnvName:     "AWS_SECRET_ACCESS_KEY",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "s3-region",
+		Description: "The bucket's region",
+		Default:     s3.DefaultRegion,
+		EnvName:     "AWS_REGION",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "endpoint",
+		Description: "The URL of S3 compatible storage, when it isn't AWS",
+		EnvName:     "AWS_ENDPOINT_URL",
+	})
+
+	listStrings := docstrings.Get("logs.ship.list")
+	listCmd := BuildCommandKS(shipCmd, runLogsShipList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+	addLogsShipOrgFlag(listCmd)
+
+	deleteStrings := docstrings.Get("logs.ship.delete")
+	deleteCmd := BuildCommandKS(shipCmd, runLogsShipDelete, deleteStrings, client, requireSession)
+	deleteCmd.Aliases = []string{"rm"}
+	deleteCmd.Args = cobra.ExactArgs(1)
+	deleteCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+}
+
+func addLogsShipOrgFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization whose logs to ship",
+		EnvName:     "FLY_ORG",
+	})
+}
+
+func runLogsShipCreate(ctx *cmdctx.CmdContext) error {
+	destination := strings.ToLower(ctx.Config.GetString("destination"))
+
+	config, err := logShipConfig(ctx, destination)
+	if err != nil {
+		return err
+	}
+
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	name := ctx.Config.GetString("name")
+	if name == "" {
+		name = destination
+	}
+
+	shipper, err := ctx.Client.API().CreateLogShipper(api.CreateLogShipperInput{
+		OrganizationID: org.ID,
+		Name:           name,
+		Destination:    destination,
+		Apps:           ctx.Config.GetStringSlice("app"),
+		Config:         config,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(shipper)
+	}
+
+	ctx.Statusf("logs", cmdctx.SDONE, "Shipping the logs of %s in %s to %s (%s)\n", formatShippedApps(shipper.Apps), org.Slug, destination, shipper.ID)
+
+	return nil
+}
+
+// logShipConfig - the destination's settings from the flags, checking the
+// ones it needs are set
+func logShipConfig(ctx *cmdctx.CmdContext, destination string) (map[string]string, error) {
+	require := func(flag string) (string, error) {
+		value := ctx.Config.GetString(flag)
+		if value == "" {
+			return "", &ValidationError{fmt.Errorf("--%s is required to ship logs to %s", flag, destination)}
+		}
+		return value, nil
+	}
+
+	switch destination {
+	case "datadog":
+		token, err := require("token")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"api_key": token, "site": ctx.Config.GetString("site")}, nil
+
+	case "http":
+		endpoint, err := require("url")
+		if err != nil {
+			return nil, err
+		}
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, &ValidationError{fmt.Errorf("invalid url %q, use an http or https URL", endpoint)}
+		}
+		config := map[string]string{"url": endpoint}
+		if token := ctx.Config.GetString("token"); token != "" {
+			config["token"] = token
+		}
+		return config, nil
+
+	case "s3":
+		bucket, err := require("bucket")
+		if err != nil {
+			return nil, err
+		}
+		u, err := url.Parse(bucket)
+		if err != nil || u.Scheme != "s3" || u.Host == "" {
+			return nil, &ValidationError{fmt.Errorf("invalid bucket %q, use s3://bucket/prefix", bucket)}
+		}
+		keyID, err := require("access-key-id")
+		if err != nil {
+			return nil, err
+		}
+		secret, err := require("secret-access-key")
+		if err != nil {
+			return nil, err
+		}
+		config := map[string]string{
+			"bucket":            u.Host,
+			"prefix":            strings.TrimPrefix(u.Path, "/"),
+			"region":            ctx.Config.GetString("s3-region"),
+			"access_key_id":     keyID,
+			"secret_access_key": secret,
+		}
+		if endpoint := ctx.Config.GetString("endpoint"); endpoint != "" {
+			config["endpoint"] = endpoint
+		}
+		return config, nil
+
+	case "":
+		return nil, &ValidationError{fmt.Errorf("--destination is required, use one of %s", strings.Join(logShipDestinations, ", "))}
+	}
+
+	return nil, &ValidationError{fmt.Errorf("unknown destination %q, use one of %s", destination, strings.Join(logShipDestinations, ", "))}
+}
+
+func formatShippedApps(apps []string) string {
+	if len(apps) == 0 {
+		return "all apps"
+	}
+	return strings.Join(apps, ", ")
+}
+
+func runLogsShipList(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	shippers, err := ctx.Client.API().GetLogShippers(org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(shippers)
+	}
+
+	if len(shippers) == 0 {
+		ctx.Statusf("logs", cmdctx.SINFO, "%s isn't shipping logs anywhere, start with `flyctl logs ship create`\n", org.Slug)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Name", "Destination", "Apps", "Status", "Created"})
+	for _, s := range shippers {
+		table.Append([]string{s.ID, s.Name, s.Destination, formatShippedApps(s.Apps), s.Status, humanize.Time(s.CreatedAt)})
+	}
+	table.Render()
+
+	return nil
+}
+
+func runLogsShipDelete(ctx *cmdctx.CmdContext) error {
+	id := ctx.Args[0]
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Stop shipping logs with %s?", id), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DeleteLogShipper(id); err != nil {
+		return err
+	}
+
+	ctx.Statusf("logs", cmdctx.SDONE, "Deleted log shipper %s\n", id)
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmd/presenters"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+// machineWaitInterval - how often machines wait polls the machine's state
+const machineWaitInterval = 1 * time.Second
+
+func newMachinesCommand(client *client.Client) *Command {
+	machinesStrings := docstrings.Get("machines")
+	machinesCmd := BuildCommandKS(nil, nil, machinesStrings, client, requireSession, requireAppName)
+	machinesCmd.Aliases = []string{"machine", "m"}
+
+	listStrings := docstrings.Get("machines.list")
+	listCmd := BuildCommandKS(machinesCmd, runMachinesList, listStrings, client, requireSession, requireAppName)
+	listCmd.Aliases = []string{"ls"}
+	listCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "watch",
+		Shorthand:   "w",
+		Description: "Refresh the list as machines change state",
+	})
+
+	runStrings := docstrings.Get("machines.run")
+	runCmd := BuildCommandKS(machinesCmd, runMachinesRun, runStrings, client, requireSession, requireAppName)
+	runCmd.Args = cobra.ExactArgs(1)
+	runCmd.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Description: "The name of the machine",
+	})
+	runCmd.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "The region to run the machine in",
+	})
+	runCmd.AddStringFlag(StringFlagOpts{
+		Name:        "size",
+		Description: "The VM size of the machine, see 'fly platform vm-sizes'",
+	})
+	addGPUFlags(runCmd)
+
+	cloneStrings := docstrings.Get("machines.clone")
+	cloneCmd := BuildCommandKS(machinesCmd, runMachinesClone, cloneStrings, client, requireSession, requireAppName)
+	cloneCmd.Args = cobra.ExactArgs(1)
+	cloneCmd.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "The region to run the clone in, defaults to the source machine's region",
+	})
+	addGPUFlags(cloneCmd)
+
+	waitStrings := docstrings.Get("machines.wait")
+	waitCmd := BuildCommandKS(machinesCmd, runMachinesWait, waitStrings, client, requireSession, requireAppName)
+	waitCmd.Args = cobra.ExactArgs(1)
+	waitCmd.AddStringFlag(StringFlagOpts{
+		Name:        "state",
+		Description: "The state to wait for: started, stopped or destroyed",
+		Default:     api.MachineStateStarted,
+	})
+	waitCmd.AddStringFlag(StringFlagOpts{
+		Name:        "timeout",
+		Description: "How long to wait before giving up",
+		Default:     "2m",
+	})
+
+	portsStrings := docstrings.Get("machines.ports")
+	portsCmd := BuildCommandKS(machinesCmd, nil, portsStrings, client, requireSession, requireAppName)
+
+	portsListStrings := docstrings.Get("machines.ports.list")
+	portsListCmd := BuildCommandKS(portsCmd, runMachinesPortsList, portsListStrings, client, requireSession, requireAppName)
+	portsListCmd.Args = cobra.ExactArgs(1)
+	portsListCmd.Aliases = []string{"ls"}
+
+	portsAddStrings := docstrings.Get("machines.ports.add")
+	portsAddCmd := BuildCommandKS(portsCmd, runMachinesPortsAdd, portsAddStrings, client, requireSession, requireAppName)
+	portsAddCmd.Args = cobra.ExactArgs(1)
+	portsAddCmd.AddStringFlag(StringFlagOpts{
+		Name:        "port",
+		Description: "The port to expose as EXTERNAL:INTERNAL, e.g. 443:8080",
+	})
+	portsAddCmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "handler",
+		Description: "Handlers for the port, e.g. tls,http",
+	})
+	portsAddCmd.AddStringFlag(StringFlagOpts{
+		Name:        "protocol",
+		Description: "The protocol of the port, tcp or udp",
+		Default:     "tcp",
+	})
+	portsAddCmd.MarkFlagRequired("port")
+
+	portsRemoveStrings := docstrings.Get("machines.ports.remove")
+	portsRemoveCmd := BuildCommandKS(portsCmd, runMachinesPortsRemove, portsRemoveStrings, client, requireSession, requireAppName)
+	portsRemoveCmd.Args = cobra.ExactArgs(1)
+	portsRemoveCmd.Aliases = []string{"rm"}
+	portsRemoveCmd.AddStringFlag(StringFlagOpts{
+		Name:        "port",
+		Description: "The external port to stop exposing",
+	})
+	portsRemoveCmd.AddStringFlag(StringFlagOpts{
+		Name:        "protocol",
+		Description: "The protocol of the port, tcp or udp",
+		Default:     "tcp",
+	})
+	portsRemoveCmd.MarkFlagRequired("port")
+
+	return machinesCmd
+}
+
+func runMachinesList(cmdCtx *cmdctx.CmdContext) error {
+	if cmdCtx.Config.GetBool("watch") {
+		return watchMachines(cmdCtx)
+	}
+
+	machines, err := cmdCtx.Client.API().GetMachines(cmdCtx.AppName)
+	if err != nil {
+		return err
+	}
+
+	return cmdCtx.Frender(cmdctx.PresenterOption{
+		Presentable: &presenters.Machines{Machines: machines},
+	})
+}
+
+func runMachinesRun(cmdCtx *cmdctx.CmdContext) error {
+	input := api.LaunchMachineInput{
+		AppID:  cmdCtx.AppName,
+		Image:  cmdCtx.Args[0],
+		Name:   cmdCtx.Config.GetString("name"),
+		Region: cmdCtx.Config.GetString("region"),
+		Size:   cmdCtx.Config.GetString("size"),
+	}
+
+	gpus, err := gpuRequestFromFlags(cmdCtx)
+	if err != nil {
+		return err
+	}
+	if gpus != nil {
+		if input.Region == "" {
+			return &ValidationError{fmt.Errorf("--region is required when attaching GPUs")}
+		}
+		if err := checkGPURegions(cmdCtx, gpus, []string{input.Region}); err != nil {
+			return err
+		}
+		if !confirmGPUPricing(cmdCtx, gpus, 1) {
+			return nil
+		}
+		input.GPUKind = gpus.Kind.Name
+		input.GPUs = gpus.Count
+	}
+
+	machine, err := cmdCtx.Client.API().LaunchMachine(input)
+	if err != nil {
+		return err
+	}
+
+	return printLaunchedMachine(cmdCtx, machine)
+}
+
+func runMachinesClone(cmdCtx *cmdctx.CmdContext) error {
+	source, err := cmdCtx.Client.API().GetMachine(cmdCtx.AppName, cmdCtx.Args[0])
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return fmt.Errorf("machine %s not found", cmdCtx.Args[0])
+	}
+
+	input := api.CloneMachineInput{
+		AppID:     cmdCtx.AppName,
+		MachineID: source.ID,
+		Region:    cmdCtx.Config.GetString("region"),
+	}
+	region := input.Region
+	if region == "" {
+		region = source.Region
+	}
+
+	gpus, err := gpuRequestFromFlags(cmdCtx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case gpus != nil:
+		if err := checkGPURegions(cmdCtx, gpus, []string{region}); err != nil {
+			return err
+		}
+		if !confirmGPUPricing(cmdCtx, gpus, 1) {
+			return nil
+		}
+		input.GPUKind = gpus.Kind.Name
+		input.GPUs = gpus.Count
+	case source.GPUs > 0 && region != source.Region:
+		// the clone keeps the source's GPUs, so the new region has to have them
+		kept := &gpuRequest{Kind: api.GPUKind{Name: source.GPUKind}, Count: source.GPUs}
+		if err := checkGPURegions(cmdCtx, kept, []string{region}); err != nil {
+			return err
+		}
+	}
+
+	machine, err := cmdCtx.Client.API().CloneMachine(input)
+	if err != nil {
+		return err
+	}
+
+	return printLaunchedMachine(cmdCtx, machine)
+}
+
+func printLaunchedMachine(cmdCtx *cmdctx.CmdContext, machine *api.Machine) error {
+	if cmdCtx.OutputStructured() {
+		return cmdCtx.WriteData(machine)
+	}
+
+	cmdCtx.Statusf("machines", cmdctx.SDONE, "Machine %s is %s in %s\n", machine.ID, machine.State, machine.Region)
+	if machine.GPUs > 0 {
+		cmdCtx.Statusf("machines", cmdctx.SDETAIL, "GPUs: %s\n", presenters.FormatGPUs(machine.GPUKind, machine.GPUs))
+	}
+
+	return nil
+}
+
+func runMachinesWait(cmdCtx *cmdctx.CmdContext) error {
+	machineID := cmdCtx.Args[0]
+
+	state := cmdCtx.Config.GetString("state")
+	switch state {
+	case api.MachineStateStarted, api.MachineStateStopped, api.MachineStateDestroyed:
+	default:
+		return &ValidationError{fmt.Errorf("invalid state %q, use started, stopped or destroyed", state)}
+	}
+
+	timeout, err := helpers.ParseDuration(cmdCtx.Config.GetString("timeout"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid timeout")}
+	}
+
+	ctx, cancel := context.WithTimeout(createCancellableContext(), timeout)
+	defer cancel()
+
+	cmdCtx.Statusf("machines", cmdctx.SBEGIN, "Waiting for machine %s to be %s\n", machineID, state)
+
+	machine, err := waitForMachineState(ctx, cmdCtx, machineID, state)
+	if errors.Is(err, context.DeadlineExceeded) {
+		current := "unknown"
+		if machine != nil {
+			current = machine.State
+		}
+		return fmt.Errorf("timed out after %s waiting for machine %s to be %s, it's %s", timeout, machineID, state, current)
+	}
+	if err != nil {
+		return err
+	}
+
+	cmdCtx.Statusf("machines", cmdctx.SDONE, "Machine %s is %s\n", machineID, state)
+
+	return nil
+}
+
+// waitForMachineState - polls the machine until it reaches state, returning
+// the last state seen. A machine that's gone is treated as destroyed.
+func waitForMachineState(ctx context.Context, cmdCtx *cmdctx.CmdContext, machineID string, state string) (*api.Machine, error) {
+	var last *api.Machine
+
+	for {
+		machine, err := cmdCtx.Client.API().GetMachine(cmdCtx.AppName, machineID)
+		if err != nil {
+			return last, err
+		}
+
+		if machine == nil {
+			if state == api.MachineStateDestroyed {
+				return last, nil
+			}
+			return last, fmt.Errorf("machine %s not found", machineID)
+		}
+
+		if last == nil || last.State != machine.State {
+			cmdCtx.Statusf("machines", cmdctx.SDETAIL, "Machine %s is %s\n", machineID, machine.State)
+		}
+		last = machine
+
+		if machine.State == state {
+			return machine, nil
+		}
+
+		if state != api.MachineStateDestroyed && (machine.State == api.MachineStateDestroying || machine.State == api.MachineStateDestroyed) {
+			return machine, fmt.Errorf("machine %s is %s, it won't become %s", machineID, machine.State, state)
+		}
+
+		select {
+		case <-time.After(machineWaitInterval):
+		case <-ctx.Done():
+			return last, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+)
+
+// machinePortHandlers - the handlers a machine's ports accept, as in fly.toml
+var machinePortHandlers = []string{"http", "tls", "proxy_proto", "pg_tls", "edge_http"}
+
+func runMachinesPortsList(cmdCtx *cmdctx.CmdContext) error {
+	machine, err := getMachineForPorts(cmdCtx, cmdCtx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	return printMachineServices(cmdCtx, machine)
+}
+
+func runMachinesPortsAdd(cmdCtx *cmdctx.CmdContext) error {
+	external, internal, err := parseMachinePort(cmdCtx.Config.GetString("port"))
+	if err != nil {
+		return err
+	}
+
+	protocol := strings.ToLower(cmdCtx.Config.GetString("protocol"))
+	if protocol != "tcp" && protocol != "udp" {
+		return &ValidationError{fmt.Errorf("invalid protocol %q, use tcp or udp", protocol)}
+	}
+
+	handlers, err := parseMachinePortHandlers(cmdCtx.Config.GetStringSlice("handler"))
+	if err != nil {
+		return err
+	}
+	if protocol == "udp" && len(handlers) > 0 {
+		return &ValidationError{fmt.Errorf("handlers can't be used with udp")}
+	}
+
+	machine, err := getMachineForPorts(cmdCtx, cmdCtx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	services, err := addMachinePort(machine.Services, protocol, internal, api.PortHandler{Port: external, Handlers: handlers})
+	if err != nil {
+		return err
+	}
+
+	return updateMachineServices(cmdCtx, machine, services)
+}
+
+func runMachinesPortsRemove(cmdCtx *cmdctx.CmdContext) error {
+	port, err := strconv.Atoi(cmdCtx.Config.GetString("port"))
+	if err != nil || port < 1 || port > 65535 {
+		return &ValidationError{fmt.Errorf("invalid port %q, give the external port to remove", cmdCtx.Config.GetString("port"))}
+	}
+
+	protocol := strings.ToLower(cmdCtx.Config.GetString("protocol"))
+
+	machine, err := getMachineForPorts(cmdCtx, cmdCtx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	services, removed := removeMachinePort(machine.Services, protocol, port)
+	if !removed {
+		return fmt.Errorf("machine %s doesn't expose %s port %d", machine.ID, protocol, port)
+	}
+
+	return updateMachineServices(cmdCtx, machine, services)
+}
+
+func getMachineForPorts(cmdCtx *cmdctx.CmdContext, machineID string) (*api.Machine, error) {
+	machine, err := cmdCtx.Client.API().GetMachineServices(cmdCtx.AppName, machineID)
+	if err != nil {
+		return nil, err
+	}
+	if machine == nil {
+		return nil, fmt.Errorf("machine %s not found", machineID)
+	}
+	return machine, nil
+}
+
+// updateMachineServices - saves the services, failing rather than
+// overwriting them if the machine was changed since it was read
+func updateMachineServices(cmdCtx *cmdctx.CmdContext, machine *api.Machine, services []api.MachineService) error {
+	updated, err := cmdCtx.Client.API().UpdateMachineServices(api.UpdateMachineServicesInput{
+		AppID:           cmdCtx.AppName,
+		MachineID:       machine.ID,
+		ExpectedVersion: machine.Version,
+		Services:        services,
+	})
+	if err != nil {
+		return fmt.Errorf("update machine %s: %w", machine.ID, err)
+	}
+
+	if !cmdCtx.OutputStructured() {
+		cmdCtx.Statusf("machines", cmdctx.SDONE, "Updated the services of machine %s\n", updated.ID)
+	}
+
+	return printMachineServices(cmdCtx, updated)
+}
+
+func printMachineServices(cmdCtx *cmdctx.CmdContext, machine *api.Machine) error {
+	if cmdCtx.OutputStructured() {
+		return cmdCtx.WriteData(machine.Services)
+	}
+
+	if len(machine.Services) == 0 {
+		cmdCtx.Statusf("machines", cmdctx.SINFO, "Machine %s doesn't expose any ports\n", machine.ID)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(cmdCtx.Out, []string{"Protocol", "Port", "Internal Port", "Handlers"})
+	for _, service := range machine.Services {
+		for _, port := range service.Ports {
+			handlers := strings.Join(port.Handlers, ",")
+			if handlers == "" {
+				handlers = "-"
+			}
+			table.Append([]string{service.Protocol, strconv.Itoa(port.Port), strconv.Itoa(service.InternalPort), handlers})
+		}
+	}
+	table.Render()
+
+	return nil
+}
+
+// parseMachinePort - parses "443:8080" into the external and internal ports.
+// A single port is used for both.
+func parseMachinePort(s string) (external int, internal int, err error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) == 1 {
+		parts = append(parts, parts[0])
+	}
+
+	ports := make([]int, 2)
+	for i, part := range parts {
+		port, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || port < 1 || port > 65535 {
+			return 0, 0, &ValidationError{fmt.Errorf("invalid port %q, use EXTERNAL:INTERNAL, e.g. 443:8080", s)}
+		}
+		ports[i] = port
+	}
+
+	return ports[0], ports[1], nil
+}
+
+func parseMachinePortHandlers(values []string) ([]string, error) {
+	handlers := []string{}
+	for _, value := range values {
+		for _, handler := range strings.Split(value, ",") {
+			handler = strings.ToLower(strings.TrimSpace(handler))
+			if handler == "" {
+				continue
+			}
+
+			valid := false
+			for _, h := range machinePortHandlers {
+				if h == handler {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return nil, &ValidationError{fmt.Errorf("invalid handler %q, use %s", handler, strings.Join(machinePortHandlers, ", "))}
+			}
+			handlers = append(handlers, handler)
+		}
+	}
+	return handlers, nil
+}
+
+// addMachinePort - the services with port added to the one routing to the
+// internal port, creating it if there's none. Ports can only be exposed once
+// per protocol.
+func addMachinePort(services []api.MachineService, protocol string, internalPort int, port api.PortHandler) ([]api.MachineService, error) {
+	out := make([]api.MachineService, 0, len(services)+1)
+	added := false
+
+	for _, service := range services {
+		if service.Protocol == protocol {
+			for _, existing := range service.Ports {
+				if existing.Port == port.Port {
+					return nil, &ValidationError{fmt.Errorf("%s port %d is already routed to internal port %d", protocol, port.Port, service.InternalPort)}
+				}
+			}
+		}
+
+		if service.Protocol == protocol && service.InternalPort == internalPort && !added {
+			ports := append(append([]api.PortHandler{}, service.Ports...), port)
+			sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+			service.Ports = ports
+			added = true
+		}
+		out = append(out, service)
+	}
+
+	if !added {
+		out = append(out, api.MachineService{
+			Protocol:     protocol,
+			InternalPort: internalPort,
+			Ports:        []api.PortHandler{port},
+		})
+	}
+
+	return out, nil
+}
+
+// removeMachinePort - the services without the external port, dropping the
+// service if that was its last port
+func removeMachinePort(services []api.MachineService, protocol string, port int) ([]api.MachineService, bool) {
+	out := make([]api.MachineService, 0, len(services))
+	removed := false
+
+	for _, service := range services {
+		if service.Protocol != protocol {
+			out = append(out, service)
+			continue
+		}
+
+		ports := []api.PortHandler{}
+		for _, p := range service.Ports {
+			if p.Port == port {
+				removed = true
+				continue
+			}
+			ports = append(ports, p)
+		}
+		if len(ports) == 0 && len(service.Ports) > 0 {
+			continue
+		}
+		service.Ports = ports
+		out = append(out, service)
+	}
+
+	return out, removed
+}
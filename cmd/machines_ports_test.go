@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestParseMachinePort(t *testing.T) {
+	tests := []struct {
+		in           string
+		wantExternal int
+		wantInternal int
+		wantErr      bool
+	}{
+		{in: "443:8080", wantExternal: 443, wantInternal: 8080},
+		{in: "8080", wantExternal: 8080, wantInternal: 8080},
+		{in: " 80 : 3000 ", wantExternal: 80, wantInternal: 3000},
+		{in: "", wantErr: true},
+		{in: "http", wantErr: true},
+		{in: "0:8080", wantErr: true},
+		{in: "443:65536", wantErr: true},
+		{in: "443:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		external, internal, err := parseMachinePort(tt.in)
+		if tt.wantErr {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("parseMachinePort(%q): expected a validation error, got %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || external != tt.wantExternal || internal != tt.wantInternal {
+			t.Errorf("parseMachinePort(%q) = %d, %d, %v, want %d, %d", tt.in, external, internal, err, tt.wantExternal, tt.wantInternal)
+		}
+	}
+}
+
+func TestParseMachinePortHandlers(t *testing.T) {
+	tests := []struct {
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		{in: nil, want: []string{}},
+		{in: []string{"tls,http"}, want: []string{"tls", "http"}},
+		{in: []string{"TLS", " http "}, want: []string{"tls", "http"}},
+		{in: []string{"tls,,http,"}, want: []string{"tls", "http"}},
+		{in: []string{"https"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseMachinePortHandlers(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseMachinePortHandlers(%q): expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMachinePortHandlers(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestAddMachinePort(t *testing.T) {
+	web := api.MachineService{Protocol: "tcp", InternalPort: 8080, Ports: []api.PortHandler{{Port: 443, Handlers: []string{"tls", "http"}}}}
+
+	tests := []struct {
+		name         string
+		protocol     string
+		internalPort int
+		port         api.PortHandler
+		want         []api.MachineService
+		wantErr      bool
+	}{
+		{
+			name:         "same internal port",
+			protocol:     "tcp",
+			internalPort: 8080,
+			port:         api.PortHandler{Port: 80, Handlers: []string{"http"}},
+			want: []api.MachineService{{Protocol: "tcp", InternalPort: 8080, Ports: []api.PortHandler{
+				{Port: 80, Handlers: []string{"http"}},
+				{Port: 443, Handlers: []string{"tls", "http"}},
+			}}},
+		},
+		{
+			name:         "new internal port",
+			protocol:     "tcp",
+			internalPort: 9090,
+			port:         api.PortHandler{Port: 9090},
+			want:         []api.MachineService{web, {Protocol: "tcp", InternalPort: 9090, Ports: []api.PortHandler{{Port: 9090}}}},
+		},
+		{
+			name:         "same port over udp",
+			protocol:     "udp",
+			internalPort: 8080,
+			port:         api.PortHandler{Port: 443},
+			want:         []api.MachineService{web, {Protocol: "udp", InternalPort: 8080, Ports: []api.PortHandler{{Port: 443}}}},
+		},
+		{
+			name:         "port already exposed",
+			protocol:     "tcp",
+			internalPort: 9090,
+			port:         api.PortHandler{Port: 443},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addMachinePort([]api.MachineService{web}, tt.protocol, tt.internalPort, tt.port)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if len(web.Ports) != 1 {
+				t.Errorf("expected the existing services to be left alone, got %+v", web)
+			}
+		})
+	}
+}
+
+func TestRemoveMachinePort(t *testing.T) {
+	services := []api.MachineService{
+		{Protocol: "tcp", InternalPort: 8080, Ports: []api.PortHandler{{Port: 80}, {Port: 443}}},
+		{Protocol: "tcp", InternalPort: 9090, Ports: []api.PortHandler{{Port: 9090}}},
+		{Protocol: "udp", InternalPort: 5353, Ports: []api.PortHandler{{Port: 53}}},
+	}
+
+	tests := []struct {
+		name        string
+		protocol    string
+		port        int
+		want        []api.MachineService
+		wantRemoved bool
+	}{
+		{
+			name:        "one of several ports",
+			protocol:    "tcp",
+			port:        80,
+			want:        []api.MachineService{{Protocol: "tcp", InternalPort: 8080, Ports: []api.PortHandler{{Port: 443}}}, services[1], services[2]},
+			wantRemoved: true,
+		},
+		{
+			name:        "last port drops the service",
+			protocol:    "tcp",
+			port:        9090,
+			want:        []api.MachineService{services[0], services[2]},
+			wantRemoved: true,
+		},
+		{
+			name:     "other protocol",
+			protocol: "udp",
+			port:     443,
+			want:     services,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed := removeMachinePort(services, tt.protocol, tt.port)
+			if removed != tt.wantRemoved || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, %t, want %+v, %t", got, removed, tt.want, tt.wantRemoved)
+			}
+		})
+	}
+}
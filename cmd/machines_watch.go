@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/inancgumus/screen"
+	"github.com/logrusorgru/aurora"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmd/presenters"
+	"github.com/superfly/flyctl/cmdctx"
+)
+
+// machineWatchInterval - how often machines list --watch refreshes
+const machineWatchInterval = 2 * time.Second
+
+// machineStateChange is written as a JSON line when a watched machine
+// appears, changes state or goes away
+type machineStateChange struct {
+	Timestamp time.Time `json:"timestamp"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	From      string    `json:"from,omitempty"`
+	State     string    `json:"state"`
+	IPs       []string  `json:"ips,omitempty"`
+}
+
+// watchMachines - refreshes the app's machines until interrupted, repainting
+// the table on a terminal and writing state changes as JSON lines otherwise
+func watchMachines(cmdCtx *cmdctx.CmdContext) error {
+	repaint := cmdCtx.IO.IsStdoutTTY() && !cmdCtx.OutputStructured()
+	if cmdCtx.OutputStructured() && !cmdCtx.OutputJSON() {
+		return fmt.Errorf("--watch writes JSON lines, so it can't be used with --yaml or --format")
+	}
+
+	ctx := createCancellableContext()
+	seen := map[string]api.Machine{}
+
+	for {
+		machines, err := cmdCtx.Client.API().GetMachines(cmdCtx.AppName)
+		if err != nil {
+			return err
+		}
+
+		if repaint {
+			screen.Clear()
+			screen.MoveTopLeft()
+			fmt.Fprintf(cmdCtx.Out, "%s %s %s\n\n", aurora.Bold(cmdCtx.AppName), aurora.Italic("at:"), aurora.Bold(time.Now().UTC().Format("15:04:05")))
+			if err := cmdCtx.Frender(cmdctx.PresenterOption{Presentable: &presenters.Machines{Machines: machines}}); err != nil {
+				return err
+			}
+		} else {
+			for _, change := range machineStateChanges(seen, machines, time.Now()) {
+				if err := json.NewEncoder(cmdCtx.Out).Encode(change); err != nil {
+					return err
+				}
+			}
+		}
+
+		seen = map[string]api.Machine{}
+		for _, m := range machines {
+			seen[m.ID] = m
+		}
+
+		select {
+		case <-time.After(machineWatchInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// machineStateChanges - the machines that are new, have changed state or
+// are gone since seen, in ID order. Machines that are gone are reported as
+// destroyed.
+func machineStateChanges(seen map[string]api.Machine, machines []api.Machine, now time.Time) []machineStateChange {
+	changes := []machineStateChange{}
+	current := map[string]bool{}
+
+	for _, m := range machines {
+		current[m.ID] = true
+		previous, ok := seen[m.ID]
+		if ok && previous.State == m.State {
+			continue
+		}
+
+		change := machineStateChange{Timestamp: now, ID: m.ID, Name: m.Name, Region: m.Region, State: m.State}
+		if ok {
+			change.From = previous.State
+		}
+		for _, ip := range m.IPs.Nodes {
+			change.IPs = append(change.IPs, ip.IP)
+		}
+		changes = append(changes, change)
+	}
+
+	for id, m := range seen {
+		if current[id] || m.State == api.MachineStateDestroyed {
+			continue
+		}
+		changes = append(changes, machineStateChange{
+			Timestamp: now,
+			ID:        m.ID,
+			Name:      m.Name,
+			Region:    m.Region,
+			From:      m.State,
+			State:     api.MachineStateDestroyed,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+
+	return changes
+}
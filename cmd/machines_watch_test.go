@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestMachineStateChanges(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	machine := func(id, state string) api.Machine {
+		m := api.Machine{ID: id, Name: "m-" + id, Region: "iad", State: state}
+		m.IPs.Nodes = []api.MachineIP{{IP: "fdaa::" + id}}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		seen     []api.Machine
+		machines []api.Machine
+		want     []machineStateChange
+	}{
+		{
+			name:     "first look",
+			machines: []api.Machine{machine("2", "started"), machine("1", "stopped")},
+			want: []machineStateChange{
+				{Timestamp: now, ID: "1", Name: "m-1", Region: "iad", State: "stopped", IPs: []string{"fdaa::1"}},
+				{Timestamp: now, ID: "2", Name: "m-2", Region: "iad", State: "started", IPs: []string{"fdaa::2"}},
+			},
+		},
+		{
+			name:     "unchanged",
+			seen:     []api.Machine{machine("1", "started")},
+			machines: []api.Machine{machine("1", "started")},
+			want:     []machineStateChange{},
+		},
+		{
+			name:     "changed",
+			seen:     []api.Machine{machine("1", "started")},
+			machines: []api.Machine{machine("1", "stopped")},
+			want: []machineStateChange{
+				{Timestamp: now, ID: "1", Name: "m-1", Region: "iad", From: "started", State: "stopped", IPs: []string{"fdaa::1"}},
+			},
+		},
+		{
+			name: "gone",
+			seen: []api.Machine{machine("1", "started"), machine("2", api.MachineStateDestroyed)},
+			want: []machineStateChange{
+				{Timestamp: now, ID: "1", Name: "m-1", Region: "iad", From: "started", State: api.MachineStateDestroyed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		seen := map[string]api.Machine{}
+		for _, m := range tt.seen {
+			seen[m.ID] = m
+		}
+
+		if got := machineStateChanges(seen, tt.machines, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
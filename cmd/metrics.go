@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/skratchdot/open-golang/open"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/promql"
+)
+
+// metricsDashboardsURL - the hosted Grafana with dashboards for every app
+const metricsDashboardsURL = "https://fly-metrics.net"
+
+// metricsRangePoints - how many points a range query returns without --step
+const metricsRangePoints = 60
+
+func newMetricsCommand(client *client.Client) *Command {
+	metricsStrings := docstrings.Get("metrics")
+	cmd := BuildCommandKS(nil, nil, metricsStrings, client, requireSession)
+
+	queryStrings := docstrings.Get("metrics.query")
+	queryCmd := BuildCommandKS(cmd, runMetricsQuery, queryStrings, client, requireSession)
+	queryCmd.Args = cobra.ExactArgs(1)
+	addMetricsOrgFlag(queryCmd)
+	queryCmd.AddStringFlag(StringFlagOpts{
+		Name:        "range",
+		Description: "Query over this much time up to now, e.g. 1h, instead of at the current time",
+	})
+	queryCmd.AddStringFlag(StringFlagOpts{
+		Name:        "step",
+		Description: "The interval between points of a range query, defaults to a 60th of the range",
+	})
+
+	dashboardsStrings := docstrings.Get("metrics.dashboards")
+	dashboardsCmd := BuildCommandKS(cmd, nil, dashboardsStrings, client, requireSession)
+
+	openStrings := docstrings.Get("metrics.dashboards.open")
+	openCmd := BuildCommandKS(dashboardsCmd, runMetricsDashboardsOpen, openStrings, client, requireSession)
+	addMetricsOrgFlag(openCmd)
+	openCmd.AddStringFlag(StringFlagOpts{
+		Name:        "app",
+		Shorthand:   "a",
+		Description: "Open the dashboard of this app",
+		EnvName:     "FLY_APP",
+	})
+
+	return cmd
+}
+
+func addMetricsOrgFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization whose metrics to use",
+		EnvName:     "FLY_ORG",
+	})
+}
+
+func runMetricsQuery(ctx *cmdctx.CmdContext) error {
+	query := ctx.Args[0]
+
+	var window, step time.Duration
+	if r := ctx.Config.GetString("range"); r != "" {
+		d, err := helpers.ParseDuration(r)
+		if err != nil || d <= 0 {
+			return &ValidationError{fmt.Errorf("invalid range %q", r)}
+		}
+		window = d
+		step = (window / metricsRangePoints).Round(time.Second)
+		if step < time.Second {
+			step = time.Second
+		}
+	}
+	if s := ctx.Config.GetString("step"); s != "" {
+		if window == 0 {
+			return &ValidationError{fmt.Errorf("--step can only be used with --range")}
+		}
+		d, err := helpers.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return &ValidationError{fmt.Errorf("invalid step %q", s)}
+		}
+		step = d
+	}
+
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	metrics := &promql.Client{Org: org.Slug, Token: flyctl.GetAPIToken()}
+	cancelCtx := createCancellableContext()
+
+	var result *promql.Result
+	if window > 0 {
+		end := time.Now()
+		result, err = metrics.QueryRange(cancelCtx, query, end.Add(-window), end, step)
+	} else {
+		result, err = metrics.Query(cancelCtx, query, time.Time{})
+	}
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(result)
+	}
+
+	if len(result.Series) == 0 {
+		ctx.Statusf("metrics", cmdctx.SINFO, "The query returned no data\n")
+		return nil
+	}
+
+	if result.Type == "matrix" {
+		table := helpers.MakeSimpleTable(ctx.Out, []string{"Series", "Points", "Min", "Max", "Last"})
+		for _, series := range result.Series {
+			if len(series.Points) == 0 {
+				continue
+			}
+			min, max := series.Points[0].Value, series.Points[0].Value
+			for _, p := range series.Points {
+				if p.Value < min {
+					min = p.Value
+				}
+				if p.Value > max {
+					max = p.Value
+				}
+			}
+			last := series.Points[len(series.Points)-1].Value
+			table.Append([]string{series.String(), strconv.Itoa(len(series.Points)), formatMetricValue(min), formatMetricValue(max), formatMetricValue(last)})
+		}
+		table.Render()
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Series", "Value"})
+	for _, series := range result.Series {
+		table.Append([]string{series.String(), formatMetricValue(series.Points[0].Value)})
+	}
+	table.Render()
+
+	return nil
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+func runMetricsDashboardsOpen(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"orgSlug": {org.Slug}}
+	dashURL := metricsDashboardsURL + "/"
+	if app := ctx.Config.GetString("app"); app != "" {
+		query.Set("var-app", app)
+		dashURL = metricsDashboardsURL + "/d/fly-app/fly-app"
+	}
+	dashURL += "?" + query.Encode()
+
+	ctx.Status("metrics", cmdctx.SINFO, "Opening", dashURL)
+	return open.Run(dashURL)
+}
@@ -41,6 +41,9 @@ func runMonitor(commandContext *cmdctx.CmdContext) error {
 
 func monitorDeployment(ctx context.Context, commandContext *cmdctx.CmdContext) error {
 	monitor := deployment.NewDeploymentMonitor(commandContext.Client.API(), commandContext.AppName)
+	monitor.Reconnecting = func(attempt int, err error) {
+		commandContext.Statusf("monitor", cmdctx.SWARN, "Lost connection, reconnecting (attempt %d): %s\n", attempt, err)
+	}
 	monitor.DeploymentStarted = func(idx int, d *api.DeploymentStatus) error {
 		if idx > 0 {
 			commandContext.StatusLn()
@@ -131,7 +134,7 @@ func monitorDeployment(ctx context.Context, commandContext *cmdctx.CmdContext) e
 	}
 
 	if !monitor.Success() {
-		return ErrAbort
+		return ErrDeployFailed
 	}
 
 	return nil
@@ -6,11 +6,13 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/logrusorgru/aurora"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 //TODO: Move all output to status styled begin/done updates
@@ -26,6 +28,10 @@ func newMoveCommand(client *client.Client) *Command {
 		Name:        "org",
 		Description: `The organization to move the app to`,
 	})
+	moveCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "recreate-volumes",
+		Description: "Recreate the app's volumes from snapshots in the destination organization",
+	})
 
 	return moveCmd
 }
@@ -50,24 +56,37 @@ func runMove(commandContext *cmdctx.CmdContext) error {
 		return fmt.Errorf("Error setting organization: %s", err)
 	}
 
+	volumes, err := commandContext.Client.API().GetVolumes(appName)
+	if err != nil {
+		return errors.Wrap(err, "Error fetching volumes")
+	}
+
+	printMoveSummary(commandContext, app, volumes, org)
+
 	if !commandContext.Config.GetBool("yes") {
 		fmt.Println(aurora.Red(`Moving an app between organizations requires a complete shutdown and restart. This will result in some app downtime.
 If the app relies on other services within the current organization, it may not come back up in a healthy manner.
 Please confirm you wish to restart this app now?`))
 
-		confirm := false
-		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Move %s from %s to %s?", appName, app.Organization.Slug, org.Slug),
-		}
-		err = survey.AskOne(prompt, &confirm)
+		confirmed, err := prompt.Confirm(fmt.Sprintf("Move %s from %s to %s?", appName, app.Organization.Slug, org.Slug), "yes")
 		if err != nil {
 			return err
 		}
-		if !confirm {
+		if !confirmed {
 			return nil
 		}
 	}
 
+	snapshots := map[string]*api.VolumeSnapshot{}
+	for _, v := range volumes {
+		snapshot, err := commandContext.Client.API().CreateVolumeSnapshot(v.ID)
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to snapshot volume %s, app not moved", v.ID)
+		}
+		snapshots[v.ID] = snapshot
+		commandContext.Statusf("move", cmdctx.SDONE, "Snapshotted volume %s (%s) as %s\n", v.Name, v.ID, snapshot.ID)
+	}
+
 	_, err = commandContext.Client.API().MoveApp(appName, org.ID)
 	if err != nil {
 		return errors.WithMessage(err, "Failed to move app")
@@ -75,5 +94,64 @@ Please confirm you wish to restart this app now?`))
 
 	fmt.Printf("Successfully moved %s to %s\n", appName, org.Slug)
 
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	if !commandContext.Config.GetBool("recreate-volumes") {
+		commandContext.Statusf("move", cmdctx.SINFO, "Volumes weren't recreated in %s, restore them from their snapshots with:\n", org.Slug)
+		for _, v := range volumes {
+			commandContext.Statusf("move", cmdctx.SDETAIL, "  %s\n", restoreVolumeCommand(appName, v, snapshots[v.ID]))
+		}
+		return nil
+	}
+
+	for _, v := range volumes {
+		input := api.CreateVolumeInput{
+			AppID:      appName,
+			Name:       v.Name,
+			Region:     v.Region,
+			SizeGb:     v.SizeGb,
+			Encrypted:  v.Encrypted,
+			SnapshotID: api.StringPointer(snapshots[v.ID].ID),
+		}
+		restored, err := commandContext.Client.API().CreateVolumeWithInput(input)
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to recreate volume %s from snapshot %s", v.Name, snapshots[v.ID].ID)
+		}
+		commandContext.Statusf("move", cmdctx.SDONE, "Recreated volume %s in %s as %s\n", v.Name, v.Region, restored.ID)
+	}
+
 	return nil
 }
+
+// printMoveSummary spells out what moves with the app and what doesn't
+func printMoveSummary(commandContext *cmdctx.CmdContext, app *api.App, volumes []api.Volume, org *api.Organization) {
+	commandContext.Statusf("move", cmdctx.STITLE, "Moving to %s will transfer:\n", org.Slug)
+	commandContext.Statusf("move", cmdctx.SINFO, "  * the app, its releases, secrets and certificates\n")
+	if ips := app.IPAddresses.Nodes; len(ips) > 0 {
+		commandContext.Statusf("move", cmdctx.SINFO, "  * %d IP addresses, which keep their addresses:\n", len(ips))
+		for _, ip := range ips {
+			commandContext.Statusf("move", cmdctx.SDETAIL, "      %s %s\n", ip.Type, ip.Address)
+		}
+	}
+
+	commandContext.Statusf("move", cmdctx.STITLE, "It will not transfer:\n")
+	commandContext.Statusf("move", cmdctx.SINFO, "  * private network (6PN) connectivity to apps in %s\n", app.Organization.Slug)
+	if len(volumes) > 0 {
+		commandContext.Statusf("move", cmdctx.SINFO, "  * %d volumes, which will be snapshotted before the move:\n", len(volumes))
+		for _, v := range volumes {
+			commandContext.Statusf("move", cmdctx.SDETAIL, "      %s %s %dGB in %s\n", v.ID, v.Name, v.SizeGb, v.Region)
+		}
+		if commandContext.Config.GetBool("recreate-volumes") {
+			commandContext.Statusf("move", cmdctx.SINFO, "    and recreated from their snapshots in %s\n", org.Slug)
+		}
+	}
+	commandContext.StatusLn()
+}
+
+// restoreVolumeCommand - the command that recreates a volume from the
+// snapshot taken before the move
+func restoreVolumeCommand(appName string, v api.Volume, snapshot *api.VolumeSnapshot) string {
+	return fmt.Sprintf("%s volumes create %s --app %s --region %s --size %d --encrypted=%t --snapshot-id %s", flyname.Name(), v.Name, appName, v.Region, v.SizeGb, v.Encrypted, snapshot.ID)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+// defaultNetworkName - how the organization's default network is shown
+const defaultNetworkName = "default"
+
+var networkNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+func newNetworksCommand(client *client.Client) *Command {
+	networksStrings := docstrings.Get("networks")
+	cmd := BuildCommandKS(nil, nil, networksStrings, client, requireSession)
+
+	listStrings := docstrings.Get("networks.list")
+	listCmd := BuildCommandKS(cmd, runNetworksList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+	listCmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Description: "The organization whose networks to list",
+	})
+
+	return cmd
+}
+
+// addNetworkFlag - adds --network to commands that create apps
+func addNetworkFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "network",
+		Description: "The private network to isolate the app in, created if it doesn't exist. Apps only reach apps in the same network",
+	})
+}
+
+// networkFromFlag - the --network value, nil for the default network
+func networkFromFlag(ctx *cmdctx.CmdContext) (*string, error) {
+	network := ctx.Config.GetString("network")
+	if network == "" || network == defaultNetworkName {
+		return nil, nil
+	}
+	if !networkNamePattern.MatchString(network) {
+		return nil, &ValidationError{fmt.Errorf("invalid network name %q, use lowercase letters, digits and dashes", network)}
+	}
+	return api.StringPointer(network), nil
+}
+
+// networkSummary is a private network and the apps in it
+type networkSummary struct {
+	Name string   `json:"name"`
+	Apps []string `json:"apps"`
+}
+
+func runNetworksList(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	apps, err := ctx.Client.API().GetApps(nil)
+	if err != nil {
+		return err
+	}
+
+	appNetworks, err := ctx.Client.API().GetAppNetworks()
+	if err != nil {
+		return fmt.Errorf("failed to list the networks of %s's apps: %w", org.Slug, err)
+	}
+
+	byNetwork := map[string][]string{}
+	for _, app := range apps {
+		if app.Organization.Slug != org.Slug {
+			continue
+		}
+		network := appNetworks[app.Name]
+		if network == "" {
+			network = defaultNetworkName
+		}
+		byNetwork[network] = append(byNetwork[network], app.Name)
+	}
+
+	networks := []networkSummary{}
+	for name, names := range byNetwork {
+		sort.Strings(names)
+		networks = append(networks, networkSummary{Name: name, Apps: names})
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		// the default network first, then by name
+		if (networks[i].Name == defaultNetworkName) != (networks[j].Name == defaultNetworkName) {
+			return networks[i].Name == defaultNetworkName
+		}
+		return networks[i].Name < networks[j].Name
+	})
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(networks)
+	}
+
+	if len(networks) == 0 {
+		ctx.Statusf("networks", cmdctx.SINFO, "Organization %s has no apps\n", org.Slug)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Network", "Apps", "App Names"})
+	for _, n := range networks {
+		table.Append([]string{n.Name, strconv.Itoa(len(n.Apps)), strings.Join(n.Apps, ", ")})
+	}
+	table.Render()
+
+	return nil
+}
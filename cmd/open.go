@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
 
+	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/flyname"
 	"github.com/superfly/flyctl/internal/client"
@@ -15,18 +19,22 @@ import (
 func newOpenCommand(client *client.Client) *Command {
 	ks := docstrings.Get("open")
 	opencommand := BuildCommandKS(nil, runOpen, ks, client, requireSession, requireAppName)
+	opencommand.Args = cobra.MaximumNArgs(1)
+	opencommand.AddBoolFlag(BoolFlagOpts{
+		Name:        "https",
+		Description: "Open the app over https",
+	})
 	return opencommand
 }
 
 func runOpen(ctx *cmdctx.CmdContext) error {
 	var path = "/"
 
-	if len(ctx.Args) > 1 {
-		return fmt.Errorf("too many arguments - only one path argument allowed")
-	}
-
 	if len(ctx.Args) > 0 {
 		path = ctx.Args[0]
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
 	}
 
 	app, err := ctx.Client.API().GetApp(ctx.AppName)
@@ -39,15 +47,34 @@ func runOpen(ctx *cmdctx.CmdContext) error {
 		return nil
 	}
 
-	if len(ctx.Args) > 1 {
-		return fmt.Errorf("too many arguments - only one path argument allowed")
+	scheme := "http"
+	if ctx.Config.GetBool("https") {
+		scheme = "https"
 	}
+	appURL := scheme + "://" + app.Hostname + path
 
-	if len(ctx.Args) > 0 {
-		path = ctx.Args[0]
+	if !canOpenBrowser() {
+		fmt.Println(appURL)
+		return nil
 	}
 
-	docsURL := "http://" + app.Hostname + path
-	fmt.Println("Opening", docsURL)
-	return open.Run(docsURL)
+	ctx.Status("open", cmdctx.SINFO, "Opening", appURL)
+	if err := open.Run(appURL); err != nil {
+		ctx.Statusf("open", cmdctx.SWARN, "Couldn't open a browser, copy the URL into one instead\n")
+	}
+	return nil
+}
+
+// canOpenBrowser - whether flyctl runs where a browser can be opened, rather
+// than e.g. over SSH or on a server without a display
+func canOpenBrowser() bool {
+	if os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" {
+		return false
+	}
+
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		return true
+	}
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
 }
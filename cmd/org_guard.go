@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/cmdctx"
+)
+
+// addAppOrgFlag - adds --org to a command that destroys an app, so it can
+// refuse to touch an app that belongs to another organization
+func addAppOrgFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Description: "Only operate on the app if it belongs to this organization",
+	})
+}
+
+// checkAppOrg - fails unless appName belongs to the organization given with
+// --org, before anything is changed. Without --org there's nothing to check.
+func checkAppOrg(ctx *cmdctx.CmdContext, appName string) error {
+	orgSlug := ctx.Config.GetString("org")
+	if orgSlug == "" || appName == "" {
+		return nil
+	}
+
+	app, err := ctx.Client.API().GetAppCompact(appName)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(app.Organization.Slug, orgSlug) {
+		return &ValidationError{fmt.Errorf("app %s belongs to organization %s, not %s; check the app name or --org", app.Name, app.Organization.Slug, orgSlug)}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+func TestCheckAppOrg(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"appcompact": map[string]interface{}{"name": "web", "organization": map[string]string{"slug": "acme"}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	api.SetBaseURL(server.URL)
+	t.Cleanup(func() { api.SetBaseURL("") })
+	t.Setenv("FLY_ACCESS_TOKEN", "token")
+
+	tests := []struct {
+		name        string
+		org         string
+		appName     string
+		wantErr     bool
+		wantLookups int
+	}{
+		{name: "no org", appName: "web"},
+		{name: "no app", org: "acme"},
+		{name: "same org", org: "acme", appName: "web", wantLookups: 1},
+		{name: "same org in capitals", org: "ACME", appName: "web", wantLookups: 1},
+		{name: "other org", org: "personal", appName: "web", wantErr: true, wantLookups: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("orgguardtest.org", tt.org)
+			t.Cleanup(func() { viper.Set("orgguardtest.org", "") })
+			lookups = 0
+
+			ctx := &cmdctx.CmdContext{Client: client.NewClient(), Config: flyctl.ConfigNS("orgguardtest")}
+			err := checkAppOrg(ctx, tt.appName)
+
+			var validationErr *ValidationError
+			if tt.wantErr != errors.As(err, &validationErr) {
+				t.Errorf("got error %v, want a validation error: %t", err, tt.wantErr)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("got %d app lookups, want %d", lookups, tt.wantLookups)
+			}
+		})
+	}
+}
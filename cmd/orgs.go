@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/olekukonko/tablewriter"
@@ -11,6 +12,7 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func newOrgsCommand(client *client.Client) *Command {
@@ -44,24 +46,65 @@ func newOrgsCommand(client *client.Client) *Command {
 	orgsDeleteCommand := BuildCommandKS(orgscmd, runOrgsDelete, orgsDeleteStrings, client, requireSession)
 	orgsDeleteCommand.Args = cobra.ExactArgs(1)
 
+	orgsLeaveStrings := docstrings.Get("orgs.leave")
+	orgsLeaveCommand := BuildCommandKS(orgscmd, runOrgsLeave, orgsLeaveStrings, client, requireSession)
+	orgsLeaveCommand.Args = cobra.ExactArgs(1)
+	orgsLeaveCommand.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	orgsTransferStrings := docstrings.Get("orgs.transfer-ownership")
+	orgsTransferCommand := BuildCommandKS(orgscmd, runOrgsTransferOwnership, orgsTransferStrings, client, requireSession)
+	orgsTransferCommand.Args = cobra.ExactArgs(1)
+	orgsTransferCommand.AddStringFlag(StringFlagOpts{
+		Name:        "to",
+		Description: "Email address of the member who will own the organization",
+	})
+	orgsTransferCommand.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	orgsDNSStrings := docstrings.Get("orgs.dns")
+	orgsDNSCommand := BuildCommandKS(orgscmd, nil, orgsDNSStrings, client, requireSession)
+
+	orgsDNSCheckStrings := docstrings.Get("orgs.dns.check")
+	orgsDNSCheckCommand := BuildCommandKS(orgsDNSCommand, runOrgsDNSCheck, orgsDNSCheckStrings, client, requireSession)
+	orgsDNSCheckCommand.Args = cobra.ExactArgs(1)
+
+	orgsAPITokensStrings := docstrings.Get("orgs.api-tokens")
+	orgsAPITokensCommand := BuildCommandKS(orgscmd, nil, orgsAPITokensStrings, client, requireSession)
+
+	orgsAPITokensAuditStrings := docstrings.Get("orgs.api-tokens.audit")
+	orgsAPITokensAuditCommand := BuildCommandKS(orgsAPITokensCommand, runOrgsAPITokensAudit, orgsAPITokensAuditStrings, client, requireSession)
+	addTokensOrgFlag(orgsAPITokensAuditCommand)
+	orgsAPITokensAuditCommand.AddStringFlag(StringFlagOpts{
+		Name:        "window",
+		Shorthand:   "w",
+		Description: "How far back to look, e.g. 7d or 12h",
+		Default:     "30d",
+	})
+	orgsAPITokensAuditCommand.AddStringFlag(StringFlagOpts{
+		Name:        "token",
+		Description: "Show the operations and source IPs of this token, by ID or name",
+	})
+	orgsAPITokensAuditCommand.AddBoolFlag(BoolFlagOpts{
+		Name:        "unused",
+		Description: "Only show tokens that weren't used in the window",
+	})
+
 	return orgscmd
 }
 
 func runOrgsList(cmdctx *cmdctx.CmdContext) error {
-	asJSON := cmdctx.OutputJSON()
+	structured := cmdctx.OutputStructured()
 
 	personalOrganization, organizations, err := cmdctx.Client.API().GetCurrentOrganizations()
 	if err != nil {
 		return err
 	}
 
-	if asJSON {
+	if structured {
 		type MyOrgs struct {
 			PersonalOrganization api.Organization
 			Organizations        []api.Organization
 		}
-		cmdctx.WriteJSON(MyOrgs{PersonalOrganization: personalOrganization, Organizations: organizations})
-		return nil
+		return cmdctx.WriteData(MyOrgs{PersonalOrganization: personalOrganization, Organizations: organizations})
 	}
 
 	printOrg(personalOrganization, true)
@@ -97,7 +140,7 @@ func printInvite(in api.Invitation, headers bool) {
 }
 
 func runOrgsShow(ctx *cmdctx.CmdContext) error {
-	asJSON := ctx.OutputJSON()
+	structured := ctx.OutputStructured()
 	orgslug := ctx.Args[0]
 
 	org, err := ctx.Client.API().GetOrganizationBySlug(orgslug)
@@ -106,9 +149,8 @@ func runOrgsShow(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if asJSON {
-		ctx.WriteJSON(org)
-		return nil
+	if structured {
+		return ctx.WriteData(org)
 	}
 
 	ctx.Statusf("orgs", cmdctx.STITLE, "Organization\n")
@@ -181,18 +223,19 @@ func runOrgsInvite(ctx *cmdctx.CmdContext) error {
 }
 
 func runOrgsCreate(ctx *cmdctx.CmdContext) error {
-	asJSON := ctx.OutputJSON()
+	structured := ctx.OutputStructured()
 
 	orgname := ""
 
 	if len(ctx.Args) == 0 {
-		prompt := &survey.Input{
+		namePrompt := &survey.Input{
 			Message: "Enter Organization Name:",
 		}
-		if err := survey.AskOne(prompt, &orgname); err != nil {
+		if err := prompt.Ask(namePrompt, &orgname, ""); err != nil {
 			if isInterrupt(err) {
 				return nil
 			}
+			return err
 		}
 	} else {
 		orgname = ctx.Args[0]
@@ -203,12 +246,12 @@ func runOrgsCreate(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if asJSON {
-		ctx.WriteJSON(organization)
-	} else {
-		printOrg(*organization, true)
+	if structured {
+		return ctx.WriteData(organization)
 	}
 
+	printOrg(*organization, true)
+
 	return nil
 }
 
@@ -229,7 +272,7 @@ func runOrgsDelete(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	confirmed := confirm(fmt.Sprintf("Are you sure you want to delete the %s organization?", orgslug))
+	confirmed := confirm(fmt.Sprintf("Are you sure you want to delete the %s organization?", orgslug), "")
 
 	if !confirmed {
 		return nil
@@ -243,3 +286,90 @@ func runOrgsDelete(ctx *cmdctx.CmdContext) error {
 
 	return nil
 }
+
+const orgAdminRole = "ADMIN"
+
+func runOrgsLeave(ctx *cmdctx.CmdContext) error {
+	orgslug := ctx.Args[0]
+
+	org, err := ctx.Client.API().GetOrganizationBySlug(orgslug)
+	if err != nil {
+		return err
+	}
+
+	if org.Type == "PERSONAL" {
+		return errors.New("you can't leave your personal organization")
+	}
+
+	if org.ViewerRole == orgAdminRole {
+		admins := 0
+		for _, m := range org.Members.Edges {
+			if m.Role == orgAdminRole {
+				admins++
+			}
+		}
+		if admins <= 1 {
+			return fmt.Errorf("you are the last admin of %s; transfer ownership with 'orgs transfer-ownership' or delete the organization instead", orgslug)
+		}
+	}
+
+	if !ctx.Config.GetBool("yes") && !confirm(fmt.Sprintf("Are you sure you want to leave the %s organization?", orgslug), "yes") {
+		return nil
+	}
+
+	if err := ctx.Client.API().LeaveOrganization(org.ID); err != nil {
+		return err
+	}
+
+	ctx.Statusf("orgs", cmdctx.SDONE, "You have left %s\n", orgslug)
+
+	return nil
+}
+
+func runOrgsTransferOwnership(ctx *cmdctx.CmdContext) error {
+	orgslug := ctx.Args[0]
+
+	email := ctx.Config.GetString("to")
+	if email == "" {
+		return errors.New("specify the new owner's email with --to")
+	}
+
+	org, err := ctx.Client.API().GetOrganizationBySlug(orgslug)
+	if err != nil {
+		return err
+	}
+
+	if org.Type == "PERSONAL" {
+		return errors.New("personal organizations can't be transferred")
+	}
+
+	if org.ViewerRole != orgAdminRole {
+		return fmt.Errorf("only admins of %s can transfer its ownership", orgslug)
+	}
+
+	var newOwner *api.OrganizationMembershipEdge
+	for i, m := range org.Members.Edges {
+		if strings.EqualFold(m.Node.Email, email) {
+			newOwner = &org.Members.Edges[i]
+			break
+		}
+	}
+	if newOwner == nil {
+		return fmt.Errorf("%s is not a member of %s, invite them with 'orgs invite' first", email, orgslug)
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		ctx.Statusf("orgs", cmdctx.SWARN, "%s will become the owner of %s, including its billing and membership.\n", newOwner.Node.Email, orgslug)
+		if !confirm(fmt.Sprintf("Transfer ownership of %s to %s?", orgslug, newOwner.Node.Email), "yes") {
+			return nil
+		}
+	}
+
+	if _, err := ctx.Client.API().TransferOrganizationOwnership(org.ID, newOwner.Node.ID); err != nil {
+		return err
+	}
+
+	ctx.Statusf("orgs", cmdctx.SDONE, "%s is now the owner of %s\n", newOwner.Node.Email, orgslug)
+
+	return nil
+}
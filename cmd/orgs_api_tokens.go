@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+)
+
+func runOrgsAPITokensAudit(ctx *cmdctx.CmdContext) error {
+	window := ctx.Config.GetString("window")
+	windowDuration, err := helpers.ParseDuration(window)
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid window: %w", err)}
+	}
+	if windowDuration <= 0 {
+		return &ValidationError{fmt.Errorf("--window must be positive")}
+	}
+
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	usage, err := ctx.Client.API().GetTokenUsage(org.Slug, time.Now().Add(-windowDuration))
+	if err != nil {
+		return err
+	}
+
+	if target := ctx.Config.GetString("token"); target != "" {
+		return showTokenUsage(ctx, usage, target, window)
+	}
+
+	if ctx.Config.GetBool("unused") {
+		unused := []api.TokenUsage{}
+		for _, u := range usage {
+			if len(u.Operations) == 0 {
+				unused = append(unused, u)
+			}
+		}
+		usage = unused
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(usage)
+	}
+
+	if len(usage) == 0 {
+		ctx.Statusf("orgs", cmdctx.SINFO, "No tokens to show in %s\n", org.Slug)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Name", "Type", "Last Used", "Calls", "Source IPs", "Notes"})
+	for _, u := range usage {
+		lastUsed := "never"
+		if u.LastUsedAt != nil {
+			lastUsed = humanize.Time(*u.LastUsedAt)
+		}
+		table.Append([]string{
+			u.Token.ID,
+			u.Token.Name,
+			u.Token.Type,
+			lastUsed,
+			strconv.Itoa(tokenCalls(u)),
+			strconv.Itoa(len(u.SourceIPs)),
+			tokenUsageNotes(u, window),
+		})
+	}
+	table.Render()
+
+	ctx.StatusLn()
+	ctx.Statusf("orgs", cmdctx.SINFO, "See what a token was used for with --token <id>\n")
+
+	return nil
+}
+
+func showTokenUsage(ctx *cmdctx.CmdContext, usage []api.TokenUsage, target string, window string) error {
+	var matches []api.TokenUsage
+	for _, u := range usage {
+		if u.Token.ID == target || u.Token.Name == target {
+			matches = append(matches, u)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no token %s, run without --token to list them", target)
+	case 1:
+	default:
+		return fmt.Errorf("%d tokens are named %s, pick one by ID", len(matches), target)
+	}
+	u := matches[0]
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(u)
+	}
+
+	ctx.Statusf("orgs", cmdctx.STITLE, "%s (%s, %s)\n", u.Token.Name, u.Token.ID, u.Token.Type)
+	if len(u.Operations) == 0 {
+		ctx.Statusf("orgs", cmdctx.SINFO, "Not used in the last %s, created %s\n", window, humanize.Time(u.Token.CreatedAt))
+		return nil
+	}
+	if notes := tokenUsageNotes(u, window); notes != "" {
+		ctx.Statusf("orgs", cmdctx.SWARN, "%s\n", notes)
+	}
+
+	operations := append([]api.TokenOperationUsage{}, u.Operations...)
+	sort.SliceStable(operations, func(i, j int) bool { return operations[i].Count > operations[j].Count })
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Operation", "Calls", "Last Used"})
+	for _, op := range operations {
+		table.Append([]string{op.Name, strconv.Itoa(op.Count), humanize.Time(op.LastUsedAt)})
+	}
+	table.Render()
+
+	ctx.StatusLn()
+
+	ips := append([]api.TokenSourceIPUsage{}, u.SourceIPs...)
+	sort.SliceStable(ips, func(i, j int) bool { return ips[i].Count > ips[j].Count })
+	table = helpers.MakeSimpleTable(ctx.Out, []string{"Source IP", "Country", "Calls", "Last Used"})
+	for _, ip := range ips {
+		table.Append([]string{ip.IP, ip.Country, strconv.Itoa(ip.Count), humanize.Time(ip.LastUsedAt)})
+	}
+	table.Render()
+
+	return nil
+}
+
+func tokenCalls(u api.TokenUsage) int {
+	calls := 0
+	for _, op := range u.Operations {
+		calls += op.Count
+	}
+	return calls
+}
+
+// tokenUsageNotes - what's worth an admin's attention about a token: that
+// it's unused and could be revoked, or used from more than one country,
+// which a token kept in one CI system or server shouldn't be
+func tokenUsageNotes(u api.TokenUsage, window string) string {
+	if len(u.Operations) == 0 {
+		return fmt.Sprintf("unused in %s", window)
+	}
+
+	countries := map[string]bool{}
+	for _, ip := range u.SourceIPs {
+		if ip.Country != "" {
+			countries[ip.Country] = true
+		}
+	}
+	if len(countries) > 1 {
+		names := make([]string, 0, len(countries))
+		for country := range countries {
+			names = append(names, country)
+		}
+		sort.Strings(names)
+		return "used from " + strings.Join(names, ", ")
+	}
+
+	return ""
+}
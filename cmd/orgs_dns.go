@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/dnscheck"
+)
+
+func runOrgsDNSCheck(ctx *cmdctx.CmdContext) error {
+	name := strings.TrimSuffix(ctx.Args[0], ".")
+
+	domain, err := ctx.Client.API().GetDomain(name)
+	if err != nil {
+		return err
+	}
+	if domain.ZoneNameservers == nil || len(*domain.ZoneNameservers) == 0 {
+		return fmt.Errorf("%s isn't hosted by Fly, or its nameservers haven't been assigned yet", name)
+	}
+
+	ctx.Statusf("dns", cmdctx.SBEGIN, "Checking the delegation of %s\n", name)
+	report := dnscheck.Check(createCancellableContext(), name, *domain.ZoneNameservers, dnscheck.PublicResolvers)
+
+	if ctx.OutputStructured() {
+		if err := ctx.WriteData(report); err != nil {
+			return err
+		}
+	} else {
+		printDelegationReport(ctx, report)
+	}
+
+	if !report.OK() {
+		if !report.Parent.OK() {
+			return fmt.Errorf("%s isn't delegated to Fly's nameservers, update them at your registrar", report.Domain)
+		}
+		return fmt.Errorf("%s is delegated correctly but hasn't propagated to every resolver yet, this can take up to 48 hours", report.Domain)
+	}
+
+	return nil
+}
+
+func printDelegationReport(ctx *cmdctx.CmdContext, report *dnscheck.Report) {
+	ctx.Statusf("dns", cmdctx.SINFO, "Expected nameservers: %s\n", strings.Join(report.Expected, ", "))
+	ctx.StatusLn()
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Source", "Status", "Nameservers", "Problem"})
+	for _, result := range append([]dnscheck.Result{report.Parent}, report.Resolvers...) {
+		status := "ok"
+		if !result.OK() {
+			status = "mismatch"
+			if result.Error != "" {
+				status = "error"
+			}
+		}
+		table.Append([]string{result.Source, status, strings.Join(result.Nameservers, ", "), formatDelegationProblem(result)})
+	}
+	table.Render()
+}
+
+func formatDelegationProblem(result dnscheck.Result) string {
+	if result.Error != "" {
+		return result.Error
+	}
+
+	problems := []string{}
+	if len(result.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(result.Missing, ", "))
+	}
+	if len(result.Unexpected) > 0 {
+		problems = append(problems, "unexpected "+strings.Join(result.Unexpected, ", "))
+	}
+	return strings.Join(problems, "; ")
+}
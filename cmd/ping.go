@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/agent"
+)
+
+// pingInterval - the pause between probes of the same address
+const pingInterval = 1 * time.Second
+
+func newPingCommand(client *client.Client) *Command {
+	pingStrings := docstrings.Get("ping")
+	cmd := BuildCommandKS(nil, runPing, pingStrings, client, requireSession, requireAppName)
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	cmd.AddIntFlag(IntFlagOpts{
+		Name:        "port",
+		Shorthand:   "p",
+		Description: "The TCP port to probe",
+		Default:     22,
+	})
+	cmd.AddIntFlag(IntFlagOpts{
+		Name:        "count",
+		Shorthand:   "n",
+		Description: "The number of probes to send to each address",
+		Default:     3,
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "timeout",
+		Description: "How long to wait for each probe",
+		Default:     "5s",
+	})
+
+	return cmd
+}
+
+// pingResult is the outcome of probing one address
+type pingResult struct {
+	Address  string          `json:"address"`
+	Sent     int             `json:"sent"`
+	Received int             `json:"received"`
+	Times    []time.Duration `json:"times"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func runPing(ctx *cmdctx.CmdContext) error {
+	host := fmt.Sprintf("%s.internal", ctx.AppName)
+	if len(ctx.Args) > 0 {
+		host = ctx.Args[0]
+	}
+
+	port := ctx.Config.GetInt("port")
+	count := ctx.Config.GetInt("count")
+	if count < 1 {
+		return &ValidationError{fmt.Errorf("--count must be at least 1")}
+	}
+	timeout, err := helpers.ParseDuration(ctx.Config.GetString("timeout"))
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid timeout: %w", err)}
+	}
+
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return fmt.Errorf("get app: %w", err)
+	}
+
+	agentclient, err := establishAgent(ctx)
+	if err != nil {
+		return fmt.Errorf("can't establish agent: %s", err)
+	}
+
+	dialer, err := agentclient.Dialer(&app.Organization)
+	if err != nil {
+		return fmt.Errorf("can't build tunnel for %s: %s", app.Organization.Slug, err)
+	}
+
+	addrs := []string{host}
+	if net.ParseIP(host) == nil {
+		if addrs, err = agentclient.Resolve(&app.Organization, host); err != nil {
+			return fmt.Errorf("can't resolve %s on %s's private network: %w", host, app.Organization.Slug, err)
+		}
+		if !ctx.OutputStructured() {
+			ctx.Statusf("ping", cmdctx.SINFO, "%s resolves to %s\n", host, strings.Join(addrs, ", "))
+		}
+	}
+
+	cancelCtx := createCancellableContext()
+	results := []*pingResult{}
+
+	for _, addr := range addrs {
+		result := &pingResult{Address: addr}
+		results = append(results, result)
+
+		for i := 0; i < count && cancelCtx.Err() == nil; i++ {
+			if i > 0 {
+				time.Sleep(pingInterval)
+			}
+
+			result.Sent++
+			elapsed, err := probeTCP(cancelCtx, dialer, addr, port, timeout)
+			if err != nil {
+				result.Error = err.Error()
+				if !ctx.OutputStructured() {
+					ctx.Statusf("ping", cmdctx.SERROR, "%s port %d: %s\n", addr, port, err)
+				}
+				continue
+			}
+
+			result.Received++
+			result.Times = append(result.Times, elapsed)
+			if !ctx.OutputStructured() {
+				fmt.Fprintf(ctx.Out, "%s port %d: reachable in %s\n", addr, port, elapsed)
+			}
+		}
+	}
+
+	if ctx.OutputStructured() {
+		if err := ctx.WriteData(results); err != nil {
+			return err
+		}
+	} else {
+		ctx.StatusLn()
+		printPingSummary(ctx, results)
+	}
+
+	for _, r := range results {
+		if r.Received == 0 {
+			return fmt.Errorf("%s is unreachable over the private network", r.Address)
+		}
+	}
+
+	return nil
+}
+
+// probeTCP - opens a connection to addr through the tunnel, returning how long
+// it took. A refused connection still shows the address is reachable.
+func probeTCP(ctx context.Context, dialer *agent.Dialer, addr string, port int, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+
+	start := time.Now()
+	done := make(chan dialResult, 1)
+	go func() {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+		done <- dialResult{conn, err}
+	}()
+
+	select {
+	case res := <-done:
+		elapsed := time.Since(start).Round(time.Microsecond)
+		if res.err != nil {
+			if strings.Contains(res.err.Error(), "refused") {
+				return elapsed, nil
+			}
+			return 0, res.err
+		}
+		res.conn.Close()
+		return elapsed, nil
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("no response within %s", timeout)
+		}
+		return 0, ctx.Err()
+	}
+}
+
+func printPingSummary(ctx *cmdctx.CmdContext, results []*pingResult) {
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Address", "Sent", "Received", "Min", "Avg", "Max"})
+
+	for _, r := range results {
+		min, avg, max := "-", "-", "-"
+		if len(r.Times) > 0 {
+			lo, hi, total := r.Times[0], r.Times[0], time.Duration(0)
+			for _, t := range r.Times {
+				if t < lo {
+					lo = t
+				}
+				if t > hi {
+					hi = t
+				}
+				total += t
+			}
+			min, max = lo.String(), hi.String()
+			avg = (total / time.Duration(len(r.Times))).Round(time.Microsecond).String()
+		}
+
+		table.Append([]string{r.Address, strconv.Itoa(r.Sent), strconv.Itoa(r.Received), min, avg, max})
+	}
+
+	table.Render()
+}
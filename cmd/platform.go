@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/skratchdot/open-golang/open"
 	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/statuspage"
 
 	"github.com/superfly/flyctl/docstrings"
 
@@ -18,19 +20,39 @@ func newPlatformCommand(client *client.Client) *Command {
 	cmd := BuildCommandKS(nil, nil, platformStrings, client, requireAppName)
 
 	regionsStrings := docstrings.Get("platform.regions")
-	BuildCommandKS(cmd, runPlatformRegions, regionsStrings, client, requireSession)
+	regionsCmd := BuildCommandKS(cmd, runPlatformRegions, regionsStrings, client, requireSession)
+	regionsCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "capabilities",
+		Description: "Show what each region supports: volumes, dedicated IPs, machines, GPUs and available capacity",
+	})
 
 	vmSizesStrings := docstrings.Get("platform.vmsizes")
-	BuildCommandKS(cmd, runPlatformVMSizes, vmSizesStrings, client, requireSession)
+	vmSizesCmd := BuildCommandKS(cmd, runPlatformVMSizes, vmSizesStrings, client, requireSession)
+	vmSizesCmd.Aliases = []string{"vmsizes"}
 
 	statusStrings := docstrings.Get("platform.status")
-	BuildCommandKS(cmd, runPlatformStatus, statusStrings, client, requireSession, requireAppName)
+	statusCmd := BuildCommandKS(cmd, runPlatformStatus, statusStrings, client)
+	statusCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "open",
+		Description: "Open the status page in a browser instead",
+	})
 
 	return cmd
 }
 
 func runPlatformRegions(ctx *cmdctx.CmdContext) error {
-	regions, _, err := ctx.Client.API().PlatformRegions()
+	if ctx.Config.GetBool("capabilities") {
+		regions, err := ctx.Client.API().PlatformRegionCapabilities()
+		if err != nil {
+			return err
+		}
+
+		return ctx.Frender(cmdctx.PresenterOption{
+			Presentable: &presenters.RegionCapabilities{Regions: regions},
+		})
+	}
+
+	regions, err := ctx.Client.API().PlatformRegionList()
 	if err != nil {
 		return err
 	}
@@ -52,7 +74,42 @@ func runPlatformVMSizes(ctx *cmdctx.CmdContext) error {
 }
 
 func runPlatformStatus(ctx *cmdctx.CmdContext) error {
-	docsURL := "https://status.fly.io/"
-	fmt.Println("Opening", docsURL)
-	return open.Run(docsURL)
+	if ctx.Config.GetBool("open") {
+		ctx.Status("platform", cmdctx.SINFO, "Opening", statuspage.PageURL)
+		return open.Run(statuspage.PageURL)
+	}
+
+	summary, err := statuspage.Fetch(createCancellableContext(), "")
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(summary)
+	}
+
+	ctx.Statusf("platform", cmdctx.STITLE, "%s\n", summary.Status.Description)
+
+	if len(summary.Incidents) > 0 {
+		ctx.StatusLn()
+		table := helpers.MakeSimpleTable(ctx.Out, []string{"Incident", "Status", "Impact", "Started", "Link"})
+		for _, incident := range summary.Incidents {
+			table.Append([]string{incident.Name, incident.Status, incident.Impact, presenters.FormatRelativeTime(incident.CreatedAt), incident.Shortlink})
+		}
+		table.Render()
+	}
+
+	if degraded := summary.Degraded(); len(degraded) > 0 {
+		ctx.StatusLn()
+		table := helpers.MakeSimpleTable(ctx.Out, []string{"Component", "Status"})
+		for _, component := range degraded {
+			table.Append([]string{component.Name, strings.ReplaceAll(component.Status, "_", " ")})
+		}
+		table.Render()
+	}
+
+	ctx.StatusLn()
+	ctx.Statusf("platform", cmdctx.SDETAIL, "Details at %s\n", statuspage.PageURL)
+
+	return nil
 }
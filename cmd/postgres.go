@@ -2,12 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/api"
@@ -16,6 +13,7 @@ import (
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
 )
 
 func newPostgresCommand(client *client.Client) *Command {
@@ -42,6 +40,7 @@ func newPostgresCommand(client *client.Client) *Command {
 	attachCmd.AddStringFlag(StringFlagOpts{Name: "postgres-app", Description: "the postgres cluster to attach to the app"})
 	attachCmd.AddStringFlag(StringFlagOpts{Name: "database-name", Description: "database to use, defaults to a new database with the same name as the app"})
 	attachCmd.AddStringFlag(StringFlagOpts{Name: "variable-name", Description: "the env variable name that will be added to the app. Defaults to DATABASE_URL"})
+	attachCmd.AddStringFlag(StringFlagOpts{Name: "region", Description: "the region the app connects from, defaults to the app's primary region"})
 
 	detachStrngs := docstrings.Get("postgres.detach")
 	detachCmd := BuildCommandKS(cmd, runDetachPostgresCluster, detachStrngs, client, requireSession, requireAppName)
@@ -61,6 +60,11 @@ func newPostgresCommand(client *client.Client) *Command {
 	usersListCmd := BuildCommandKS(usersCmd, runListPostgresUsers, usersListStrings, client, requireSession, requireAppNameAsArg)
 	usersListCmd.Args = cobra.ExactArgs(1)
 
+	newPostgresAdminCommands(dbCmd, usersCmd, client)
+	newPostgresImportCommand(cmd, client)
+	newPostgresBackupCommand(cmd, client)
+	newPostgresMembersCommands(cmd, client)
+
 	return cmd
 }
 
@@ -70,9 +74,8 @@ func runPostgresList(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(apps)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(apps)
 	}
 
 	return ctx.Render(&presenters.Apps{Apps: apps})
@@ -129,9 +132,7 @@ func runCreatePostgresCluster(ctx *cmdctx.CmdContext) error {
 
 	fmt.Fprintf(ctx.Out, "Creating postgres cluster %s in organization %s\n", name, org.Slug)
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Writer = os.Stderr
-	s.Prefix = "Launching..."
+	s := iostreams.NewSpinner("Launching...")
 	s.Start()
 
 	payload, err := ctx.Client.API().CreatePostgresCluster(input)
@@ -147,6 +148,7 @@ func runCreatePostgresCluster(ctx *cmdctx.CmdContext) error {
 	fmt.Printf("  Hostname:    %s.internal\n", payload.App.Name)
 	fmt.Printf("  Proxy Port:  5432\n")
 	fmt.Printf("  PG Port: 5433\n")
+	savePostgresCredentials(payload.App.Name, payload.Username, payload.Password)
 
 	fmt.Println(aurora.Italic("Save your credentials in a secure place, you won't be able to see them again!"))
 	fmt.Println()
@@ -187,10 +189,11 @@ func runAttachPostgresCluster(ctx *cmdctx.CmdContext) error {
 	if varName := ctx.Config.GetString("variable-name"); varName != "" {
 		input.VariableName = api.StringPointer(varName)
 	}
+	if region := defaultToPrimaryRegion(ctx); region != "" {
+		input.Region = api.StringPointer(region)
+	}
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Writer = os.Stderr
-	s.Prefix = "Attaching..."
+	s := iostreams.NewSpinner("Attaching...")
 	s.Start()
 
 	payload, err := ctx.Client.API().AttachPostgresCluster(input)
@@ -210,9 +213,7 @@ func runDetachPostgresCluster(ctx *cmdctx.CmdContext) error {
 	postgresAppName := ctx.Config.GetString("postgres-app")
 	appName := ctx.AppName
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Writer = os.Stderr
-	s.Prefix = "Detaching..."
+	s := iostreams.NewSpinner("Detaching...")
 	s.Start()
 
 	err := ctx.Client.API().DetachPostgresCluster(postgresAppName, appName)
@@ -233,9 +234,8 @@ func runListPostgresDatabases(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(databases)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(databases)
 	}
 
 	table := helpers.MakeSimpleTable(ctx.Out, []string{"Name", "Users"})
@@ -255,9 +255,8 @@ func runListPostgresUsers(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(users)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(users)
 	}
 
 	table := helpers.MakeSimpleTable(ctx.Out, []string{"Username", "Superuser", "Databases"})
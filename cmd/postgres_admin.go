@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/flypg"
+	"github.com/superfly/flyctl/pkg/agent"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// newPostgresAdminCommands - the db and users commands that change the
+// cluster, run on its leader over the private network
+func newPostgresAdminCommands(dbCmd *Command, usersCmd *Command, client *client.Client) {
+	createDBStrings := docstrings.Get("postgres.db.create")
+	createDBCmd := BuildCommandKS(dbCmd, runCreatePostgresDatabase, createDBStrings, client, requireSession, requireAppNameAsArg)
+	createDBCmd.Args = cobra.ExactArgs(2)
+	addPostgresAdminFlags(createDBCmd)
+
+	dropDBStrings := docstrings.Get("postgres.db.drop")
+	dropDBCmd := BuildCommandKS(dbCmd, runDropPostgresDatabase, dropDBStrings, client, requireSession, requireAppNameAsArg)
+	dropDBCmd.Args = cobra.ExactArgs(2)
+	addPostgresAdminFlags(dropDBCmd)
+	dropDBCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	createUserStrings := docstrings.Get("postgres.users.create")
+	createUserCmd := BuildCommandKS(usersCmd, runCreatePostgresUser, createUserStrings, client, requireSession, requireAppNameAsArg)
+	createUserCmd.Args = cobra.ExactArgs(2)
+	addPostgresAdminFlags(createUserCmd)
+	createUserCmd.AddStringFlag(StringFlagOpts{Name: "password", Description: "the user's password, one will be generated for you if you leave this blank"})
+	createUserCmd.AddBoolFlag(BoolFlagOpts{Name: "superuser", Description: "make the user a superuser"})
+
+	grantStrings := docstrings.Get("postgres.users.grant")
+	grantCmd := BuildCommandKS(usersCmd, runGrantPostgresUser, grantStrings, client, requireSession, requireAppNameAsArg)
+	grantCmd.Args = cobra.ExactArgs(2)
+	addPostgresAdminFlags(grantCmd)
+	grantCmd.AddStringFlag(StringFlagOpts{Name: "database", Description: "the database to grant access to"})
+	grantCmd.AddBoolFlag(BoolFlagOpts{Name: "read-only", Description: "only grant access to read"})
+
+	dropUserStrings := docstrings.Get("postgres.users.drop")
+	dropUserCmd := BuildCommandKS(usersCmd, runDropPostgresUser, dropUserStrings, client, requireSession, requireAppNameAsArg)
+	dropUserCmd.Args = cobra.ExactArgs(2)
+	addPostgresAdminFlags(dropUserCmd)
+	dropUserCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+}
+
+func addPostgresAdminFlags(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{Name: "admin-username", Description: "the superuser to run as, instead of the one saved when the cluster was created"})
+	cmd.AddStringFlag(StringFlagOpts{Name: "admin-password", Description: "the superuser's password", EnvName: "FLY_PG_PASSWORD"})
+}
+
+// savePostgresCredentials - keeps a new cluster's superuser credentials for
+// the db and users commands and says where, warning when they can't be
+func savePostgresCredentials(app string, username string, password string) {
+	path, err := flypg.SaveCredentials(flyctl.ConfigDir(), app, flypg.Credentials{Username: username, Password: password})
+	if err != nil {
+		terminal.Warnf("Failed to save the credentials, pass them to the db and users commands with --admin-username and --admin-password: %v\n", err)
+		return
+	}
+	fmt.Printf("The credentials are saved unencrypted in %s, readable only by you, for the db and users commands\n", path)
+}
+
+// postgresAdminClient - a client for the admin API of the cluster's leader,
+// with the credentials from the flags or saved when it was created
+func postgresAdminClient(ctx *cmdctx.CmdContext) (*flypg.Client, error) {
+	leader, err := dialPostgresLeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return flypg.New(leader.dialer.DialContext, leader.privateIP, leader.credentials), nil
+}
+
+// postgresLeaderConn - how to reach a cluster's leader as its superuser
+type postgresLeaderConn struct {
+	credentials flypg.Credentials
+	privateIP   string
+	dialer      *agent.Dialer
+}
+
+// dialPostgresLeader - a tunnel to the cluster's leader, with the superuser
+// credentials from the flags or saved when it was created
+func dialPostgresLeader(ctx *cmdctx.CmdContext) (*postgresLeaderConn, error) {
+	credentials := flypg.Credentials{
+		Username: ctx.Config.GetString("admin-username"),
+		Password: ctx.Config.GetString("admin-password"),
+	}
+	if credentials.Password == "" {
+		saved, err := flypg.LoadCredentials(flyctl.ConfigDir(), ctx.AppName)
+		if err == flypg.ErrNoCredentials {
+			return nil, fmt.Errorf("no saved credentials for %s, pass its superuser's with --admin-username and --admin-password", ctx.AppName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		credentials = saved
+	}
+	if credentials.Username == "" {
+		credentials.Username = "postgres"
+	}
+
+	members, err := listPostgresMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	leader := postgresLeader(members)
+	if leader == nil || leader.PrivateIP == "" {
+		return nil, fmt.Errorf("%s has no leader to run on, check with `flyctl postgres members %s`", ctx.AppName, ctx.AppName)
+	}
+
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	agentclient, err := establishAgent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't establish agent: %s", err)
+	}
+	dialer, err := agentclient.Dialer(&app.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("can't build tunnel for %s: %s", app.Organization.Slug, err)
+	}
+
+	return &postgresLeaderConn{credentials: credentials, privateIP: leader.PrivateIP, dialer: dialer}, nil
+}
+
+func runCreatePostgresDatabase(ctx *cmdctx.CmdContext) error {
+	name := ctx.Args[1]
+
+	pg, err := postgresAdminClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := pg.CreateDatabase(context.Background(), name); err != nil {
+		return err
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Created database %s in %s\n", name, ctx.AppName)
+	return nil
+}
+
+func runDropPostgresDatabase(ctx *cmdctx.CmdContext) error {
+	name := ctx.Args[1]
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Drop database %s from %s? Its data will be lost", name, ctx.AppName), "yes") {
+			return nil
+		}
+	}
+
+	pg, err := postgresAdminClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := pg.DeleteDatabase(context.Background(), name); err != nil {
+		return err
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Dropped database %s from %s\n", name, ctx.AppName)
+	return nil
+}
+
+func runCreatePostgresUser(ctx *cmdctx.CmdContext) error {
+	username := ctx.Args[1]
+
+	password := ctx.Config.GetString("password")
+	generated := password == ""
+	if generated {
+		var err error
+		if password, err = generatePostgresPassword(); err != nil {
+			return err
+		}
+	}
+
+	pg, err := postgresAdminClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := pg.CreateUser(context.Background(), username, password, ctx.Config.GetBool("superuser")); err != nil {
+		return err
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Created user %s in %s\n", username, ctx.AppName)
+	if generated {
+		ctx.Statusf("postgres", cmdctx.SINFO, "Its password is shown once, keep it somewhere safe:\n")
+		fmt.Fprintln(ctx.Out, password)
+	}
+	ctx.Statusf("postgres", cmdctx.SINFO, "Give it access to a database with `flyctl postgres users grant %s %s --database <name>`\n", ctx.AppName, username)
+
+	return nil
+}
+
+func runGrantPostgresUser(ctx *cmdctx.CmdContext) error {
+	username := ctx.Args[1]
+	database := ctx.Config.GetString("database")
+	if database == "" {
+		return &ValidationError{errors.New("--database is required")}
+	}
+
+	pg, err := postgresAdminClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	readOnly := ctx.Config.GetBool("read-only")
+	if err := pg.GrantAccess(context.Background(), username, database, readOnly); err != nil {
+		return err
+	}
+
+	access := "read and write"
+	if readOnly {
+		access = "read"
+	}
+	ctx.Statusf("postgres", cmdctx.SDONE, "%s can now %s %s\n", username, access, database)
+
+	return nil
+}
+
+func runDropPostgresUser(ctx *cmdctx.CmdContext) error {
+	username := ctx.Args[1]
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Drop user %s from %s? Anything connecting as it will lose access", username, ctx.AppName), "yes") {
+			return nil
+		}
+	}
+
+	pg, err := postgresAdminClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := pg.DeleteUser(context.Background(), username); err != nil {
+		return err
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Dropped user %s from %s\n", username, ctx.AppName)
+	return nil
+}
+
+func generatePostgresPassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
+)
+
+func newPostgresBackupCommand(parent *Command, client *client.Client) {
+	backupStrings := docstrings.Get("postgres.backup")
+	backupCmd := BuildCommandKS(parent, nil, backupStrings, client, requireSession)
+
+	listStrings := docstrings.Get("postgres.backup.list")
+	listCmd := BuildCommandKS(backupCmd, runPostgresBackupList, listStrings, client, requireSession, requireAppNameAsArg)
+	listCmd.Aliases = []string{"ls"}
+	listCmd.Args = cobra.ExactArgs(1)
+
+	createStrings := docstrings.Get("postgres.backup.create")
+	createCmd := BuildCommandKS(backupCmd, runPostgresBackupCreate, createStrings, client, requireSession, requireAppNameAsArg)
+	createCmd.Args = cobra.ExactArgs(1)
+
+	restoreStrings := docstrings.Get("postgres.backup.restore")
+	restoreCmd := BuildCommandKS(backupCmd, runPostgresBackupRestore, restoreStrings, client, requireSession, requireAppNameAsArg)
+	restoreCmd.Args = cobra.ExactArgs(1)
+	restoreCmd.AddStringFlag(StringFlagOpts{Name: "name", Description: "the name of the new cluster"})
+	restoreCmd.AddStringFlag(StringFlagOpts{Name: "organization", Description: "the organization that will own the new cluster, defaults to the source cluster's"})
+	restoreCmd.AddStringFlag(StringFlagOpts{Name: "time", Description: "the point in time to restore to, as RFC 3339 or a duration ago like 2h, defaults to the latest archived WAL"})
+	restoreCmd.AddStringFlag(StringFlagOpts{Name: "backup", Description: "the ID of the base backup to restore from, defaults to the latest before --time"})
+	restoreCmd.AddStringFlag(StringFlagOpts{Name: "region", Description: "the region to launch the new cluster in, defaults to the source cluster's"})
+	restoreCmd.AddStringFlag(StringFlagOpts{Name: "vm-size", Description: "the size of the VM, defaults to the source cluster's"})
+	restoreCmd.AddIntFlag(IntFlagOpts{Name: "volume-size", Description: "the size in GB for volumes, defaults to the source cluster's"})
+}
+
+func runPostgresBackupList(ctx *cmdctx.CmdContext) error {
+	backups, wal, err := ctx.Client.API().GetPostgresBackups(ctx.AppName)
+	if err == api.ErrNotFound {
+		return fmt.Errorf("%s isn't a postgres cluster", ctx.AppName)
+	}
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(map[string]interface{}{"backups": backups, "wal": wal})
+	}
+
+	printPostgresWALStatus(ctx, wal)
+	ctx.StatusLn()
+
+	if len(backups) == 0 {
+		ctx.Statusf("postgres", cmdctx.SINFO, "%s has no base backups, take one with `flyctl postgres backup create %s`\n", ctx.AppName, ctx.AppName)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Status", "Trigger", "Size", "WAL Start", "Started", "Completed"})
+	for _, backup := range backups {
+		completed := ""
+		if backup.CompletedAt != nil {
+			completed = humanize.Time(*backup.CompletedAt)
+		}
+		table.Append([]string{backup.ID, backup.Status, backup.Trigger, backup.Size, backup.WALStart, humanize.Time(backup.StartedAt), completed})
+	}
+	table.Render()
+
+	return nil
+}
+
+func printPostgresWALStatus(ctx *cmdctx.CmdContext, wal *api.PostgresWALStatus) {
+	if wal == nil || !wal.Archiving {
+		ctx.Statusf("postgres", cmdctx.SWARN, "WAL archiving is off, backups can only be restored as they were taken\n")
+		return
+	}
+
+	if wal.LastArchivedAt != nil {
+		ctx.Statusf("postgres", cmdctx.SINFO, "WAL archived up to %s, %s\n", wal.LastArchivedWAL, humanize.Time(*wal.LastArchivedAt))
+	} else {
+		ctx.Statusf("postgres", cmdctx.SINFO, "WAL archiving is on, nothing archived yet\n")
+	}
+	if wal.LastFailedAt != nil && (wal.LastArchivedAt == nil || wal.LastFailedAt.After(*wal.LastArchivedAt)) {
+		ctx.Statusf("postgres", cmdctx.SWARN, "Archiving %s failed %s\n", wal.LastFailedWAL, humanize.Time(*wal.LastFailedAt))
+	}
+}
+
+// runPostgresBackupCreate - takes a base backup: the leader is put in backup
+// mode while its volume is snapshotted, so the snapshot is consistent
+func runPostgresBackupCreate(ctx *cmdctx.CmdContext) error {
+	s := iostreams.NewSpinner("Starting backup...")
+	s.Start()
+
+	backup, err := ctx.Client.API().StartPostgresBackup(ctx.AppName)
+	if err != nil {
+		s.Stop()
+		return err
+	}
+
+	s.Suffix = fmt.Sprintf(" Snapshotting volume %s...", backup.VolumeID)
+	snapshot, err := ctx.Client.API().CreateVolumeSnapshot(backup.VolumeID)
+	if err != nil {
+		s.Stop()
+		// the leader must leave backup mode either way
+		if _, finishErr := ctx.Client.API().FinishPostgresBackup(backup.ID, "", err.Error()); finishErr != nil {
+			ctx.Statusf("postgres", cmdctx.SWARN, "Failed to end backup %s: %s\n", backup.ID, finishErr)
+		}
+		return fmt.Errorf("snapshot volume %s: %w", backup.VolumeID, err)
+	}
+
+	backup, err = ctx.Client.API().FinishPostgresBackup(backup.ID, snapshot.ID, "")
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(backup)
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Created backup %s of %s from snapshot %s\n", backup.ID, ctx.AppName, snapshot.ID)
+
+	return nil
+}
+
+func runPostgresBackupRestore(ctx *cmdctx.CmdContext) error {
+	target, err := parseRestoreTime(ctx.Config.GetString("time"), time.Now())
+	if err != nil {
+		return &ValidationError{err}
+	}
+
+	backups, wal, err := ctx.Client.API().GetPostgresBackups(ctx.AppName)
+	if err == api.ErrNotFound {
+		return fmt.Errorf("%s isn't a postgres cluster", ctx.AppName)
+	}
+	if err != nil {
+		return err
+	}
+
+	backup, err := choosePostgresBackup(backups, wal, ctx.Config.GetString("backup"), target)
+	if err != nil {
+		return err
+	}
+
+	source, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	orgSlug := ctx.Config.GetString("organization")
+	if orgSlug == "" {
+		orgSlug = source.Organization.Slug
+	}
+	org, err := selectOrganization(ctx.Client.API(), orgSlug, nil)
+	if err != nil {
+		return err
+	}
+
+	name := ctx.Config.GetString("name")
+	if name == "" {
+		if name, err = inputAppName(""); err != nil {
+			return err
+		}
+	}
+
+	input := api.RestorePostgresClusterInput{
+		SourceAppID:    source.Name,
+		OrganizationID: org.ID,
+		Name:           name,
+		BackupID:       backup.ID,
+	}
+	if target != nil {
+		input.TargetTime = api.StringPointer(target.UTC().Format(time.RFC3339))
+	}
+	if region := ctx.Config.GetString("region"); region != "" {
+		input.Region = api.StringPointer(region)
+	}
+	if vmSize := ctx.Config.GetString("vm-size"); vmSize != "" {
+		input.VMSize = api.StringPointer(vmSize)
+	}
+	if volumeSize := ctx.Config.GetInt("volume-size"); volumeSize > 0 {
+		input.VolumeSizeGB = api.IntPointer(volumeSize)
+	}
+
+	restoredTo := "the latest archived WAL"
+	if target != nil {
+		restoredTo = target.UTC().Format(time.RFC3339)
+	}
+	fmt.Fprintf(ctx.Out, "Restoring %s from backup %s to %s, as postgres cluster %s in organization %s\n", source.Name, backup.ID, restoredTo, name, org.Slug)
+
+	s := iostreams.NewSpinner("Launching...")
+	s.Start()
+
+	payload, err := ctx.Client.API().RestorePostgresCluster(input)
+	if err != nil {
+		s.Stop()
+		return err
+	}
+
+	s.FinalMSG = fmt.Sprintf("Postgres cluster %s created\n", payload.App.Name)
+	s.Stop()
+
+	fmt.Printf("  Username:    %s\n", payload.Username)
+	fmt.Printf("  Password:    %s\n", payload.Password)
+	fmt.Printf("  Hostname:    %s.internal\n", payload.App.Name)
+	savePostgresCredentials(payload.App.Name, payload.Username, payload.Password)
+
+	fmt.Println(aurora.Italic("Save your credentials in a secure place, you won't be able to see them again!"))
+	fmt.Println()
+
+	cancelCtx := createCancellableContext()
+	ctx.AppName = payload.App.Name
+	err = watchDeployment(cancelCtx, ctx)
+	if isCancelledError(err) {
+		err = nil
+	}
+
+	return err
+}
+
+// parseRestoreTime - the point in time to restore to, given as RFC 3339 or
+// as a duration before now. It's nil without one, to restore everything.
+func parseRestoreTime(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		if t.After(now) {
+			return nil, fmt.Errorf("--time %s is in the future", value)
+		}
+		return &t, nil
+	}
+
+	ago, err := helpers.ParseDuration(value)
+	if err != nil || ago < 0 {
+		return nil, fmt.Errorf("invalid --time %q, use RFC 3339 like 2006-01-02T15:04:05Z or a duration ago like 2h", value)
+	}
+	t := now.Add(-ago)
+	return &t, nil
+}
+
+// choosePostgresBackup - the base backup to restore from to reach target:
+// the one with ID id, or the latest completed before target. The archived
+// WAL has to reach target for the backup to be brought up to it.
+func choosePostgresBackup(backups []api.PostgresBackup, wal *api.PostgresWALStatus, id string, target *time.Time) (*api.PostgresBackup, error) {
+	if target != nil && (wal == nil || wal.LastArchivedAt == nil) {
+		return nil, errors.New("the cluster has no archived WAL, restore a backup as it was taken by leaving out --time")
+	}
+	if target != nil && target.After(*wal.LastArchivedAt) {
+		return nil, fmt.Errorf("WAL is only archived up to %s, restore to that time or earlier", wal.LastArchivedAt.UTC().Format(time.RFC3339))
+	}
+
+	var chosen *api.PostgresBackup
+	for i := range backups {
+		backup := &backups[i]
+		if backup.CompletedAt == nil {
+			continue
+		}
+
+		if id != "" {
+			if backup.ID != id {
+				continue
+			}
+			if target != nil && backup.CompletedAt.After(*target) {
+				return nil, fmt.Errorf("backup %s completed at %s, after --time", id, backup.CompletedAt.UTC().Format(time.RFC3339))
+			}
+			return backup, nil
+		}
+
+		if target != nil && backup.CompletedAt.After(*target) {
+			continue
+		}
+		if chosen == nil || backup.CompletedAt.After(*chosen.CompletedAt) {
+			chosen = backup
+		}
+	}
+
+	switch {
+	case id != "":
+		return nil, fmt.Errorf("no completed backup %s, see `flyctl postgres backup list`", id)
+	case chosen == nil && target != nil:
+		return nil, fmt.Errorf("no backup completed before %s", target.UTC().Format(time.RFC3339))
+	case chosen == nil:
+		return nil, errors.New("no completed backups to restore from")
+	}
+
+	return chosen, nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestParseRestoreTime(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in      string
+		want    *time.Time
+		wantErr bool
+	}{
+		{in: ""},
+		{in: "2021-06-01T10:30:00Z", want: timePtr(time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC))},
+		{in: "2h", want: timePtr(now.Add(-2 * time.Hour))},
+		{in: "1d", want: timePtr(now.Add(-24 * time.Hour))},
+		{in: "2021-06-02T00:00:00Z", wantErr: true},
+		{in: "-2h", wantErr: true},
+		{in: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRestoreTime(tt.in, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseRestoreTime(%q): expected an error, got %v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseRestoreTime(%q): %v", tt.in, err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+			t.Errorf("parseRestoreTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestChoosePostgresBackup(t *testing.T) {
+	at := func(hour int) *time.Time {
+		return timePtr(time.Date(2021, 6, 1, hour, 0, 0, 0, time.UTC))
+	}
+
+	backups := []api.PostgresBackup{
+		{ID: "b1", CompletedAt: at(1)},
+		{ID: "b2", CompletedAt: at(5)},
+		{ID: "b3"},
+		{ID: "b4", CompletedAt: at(3)},
+	}
+	wal := &api.PostgresWALStatus{LastArchivedAt: at(8)}
+
+	tests := []struct {
+		name    string
+		wal     *api.PostgresWALStatus
+		id      string
+		target  *time.Time
+		want    string
+		wantErr bool
+	}{
+		{name: "latest", want: "b2"},
+		{name: "latest without wal", wal: &api.PostgresWALStatus{}, want: "b2"},
+		{name: "latest before target", wal: wal, target: at(4), want: "b4"},
+		{name: "by id", id: "b1", want: "b1"},
+		{name: "by id before target", wal: wal, id: "b4", target: at(6), want: "b4"},
+		{name: "by id after target", wal: wal, id: "b2", target: at(4), wantErr: true},
+		{name: "incomplete id", id: "b3", wantErr: true},
+		{name: "unknown id", id: "b9", wantErr: true},
+		{name: "target without wal", wal: &api.PostgresWALStatus{}, target: at(4), wantErr: true},
+		{name: "target past wal", wal: wal, target: at(9), wantErr: true},
+		{name: "target before every backup", wal: wal, target: timePtr(time.Date(2021, 6, 1, 0, 30, 0, 0, time.UTC)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := choosePostgresBackup(backups, tt.wal, tt.id, tt.target)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", tt.name, got.ID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got.ID, tt.want)
+		}
+	}
+
+	if _, err := choosePostgresBackup([]api.PostgresBackup{{ID: "b3"}}, nil, "", nil); err == nil {
+		t.Error("expected an error without completed backups")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
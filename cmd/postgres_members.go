@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
+)
+
+const (
+	// postgresFailoverTimeout - how long a new leader gets to be elected
+	postgresFailoverTimeout = 2 * time.Minute
+	// postgresFailoverPoll - how often the members are checked for it
+	postgresFailoverPoll = 2 * time.Second
+)
+
+func newPostgresMembersCommands(parent *Command, client *client.Client) {
+	membersStrings := docstrings.Get("postgres.members")
+	membersCmd := BuildCommandKS(parent, runPostgresMembers, membersStrings, client, requireSession, requireAppNameAsArg)
+	membersCmd.Args = cobra.ExactArgs(1)
+
+	failoverStrings := docstrings.Get("postgres.failover")
+	failoverCmd := BuildCommandKS(parent, runPostgresFailover, failoverStrings, client, requireSession, requireAppNameAsArg)
+	failoverCmd.Args = cobra.ExactArgs(1)
+	failoverCmd.AddStringFlag(StringFlagOpts{Name: "to", Description: "the replica to promote, by ID or region, defaults to the healthy replica lagging least"})
+	failoverCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	replicasStrings := docstrings.Get("postgres.replicas")
+	replicasCmd := BuildCommandKS(parent, nil, replicasStrings, client, requireSession)
+
+	addStrings := docstrings.Get("postgres.replicas.add")
+	addCmd := BuildCommandKS(replicasCmd, runPostgresReplicasAdd, addStrings, client, requireSession, requireAppNameAsArg)
+	addCmd.Args = cobra.ExactArgs(1)
+	addCmd.AddStringFlag(StringFlagOpts{Name: "region", Description: "the region to add the replica in"})
+	addCmd.AddIntFlag(IntFlagOpts{Name: "volume-size", Description: "the size in GB of the replica's volume, defaults to the leader's"})
+
+	removeStrings := docstrings.Get("postgres.replicas.remove")
+	removeCmd := BuildCommandKS(replicasCmd, runPostgresReplicasRemove, removeStrings, client, requireSession, requireAppNameAsArg)
+	removeCmd.Args = cobra.ExactArgs(2)
+	removeCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+}
+
+func listPostgresMembers(ctx *cmdctx.CmdContext) ([]api.PostgresClusterMember, error) {
+	members, err := ctx.Client.API().ListPostgresMembers(ctx.AppName)
+	if err == api.ErrNotFound {
+		return nil, fmt.Errorf("%s isn't a postgres cluster", ctx.AppName)
+	}
+	return members, err
+}
+
+func runPostgresMembers(ctx *cmdctx.CmdContext) error {
+	members, err := listPostgresMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(members)
+	}
+
+	// the leader first, then replicas by region
+	sort.SliceStable(members, func(i, j int) bool {
+		if (members[i].Role == "leader") != (members[j].Role == "leader") {
+			return members[i].Role == "leader"
+		}
+		return members[i].Region < members[j].Region
+	})
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Region", "Role", "Health", "Lag", "Private IP"})
+	for _, m := range members {
+		health := "healthy"
+		if !m.Healthy {
+			health = "unhealthy"
+		}
+		lag := ""
+		if m.Role != "leader" {
+			lag = humanize.Bytes(uint64(m.LagBytes))
+		}
+		table.Append([]string{m.ID, m.Region, m.Role, health, lag, m.PrivateIP})
+	}
+	table.Render()
+
+	return nil
+}
+
+func runPostgresFailover(ctx *cmdctx.CmdContext) error {
+	members, err := listPostgresMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	leader := postgresLeader(members)
+	if leader == nil {
+		return fmt.Errorf("%s has no leader to fail over from", ctx.AppName)
+	}
+
+	target, err := choosePostgresFailoverTarget(members, ctx.Config.GetString("to"))
+	if err != nil {
+		return err
+	}
+
+	if target.LagBytes > 0 {
+		ctx.Statusf("postgres", cmdctx.SWARN, "%s is %s behind the leader, writes it hasn't received yet will be lost\n", target.ID, humanize.Bytes(uint64(target.LagBytes)))
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Fail over %s from %s (%s) to %s (%s)?", ctx.AppName, leader.ID, leader.Region, target.ID, target.Region), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().FailoverPostgresCluster(ctx.AppName, target.ID); err != nil {
+		return err
+	}
+
+	s := iostreams.NewSpinner("Waiting for the new leader...")
+	s.Start()
+	err = waitForPostgresLeader(ctx, target.ID)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "%s (%s) is now the leader of %s\n", target.ID, target.Region, ctx.AppName)
+
+	return nil
+}
+
+func postgresLeader(members []api.PostgresClusterMember) *api.PostgresClusterMember {
+	for i := range members {
+		if members[i].Role == "leader" {
+			return &members[i]
+		}
+	}
+	return nil
+}
+
+// choosePostgresFailoverTarget - the replica to promote: the one with the ID
+// to, or in the region to, or without to the healthy one lagging least
+func choosePostgresFailoverTarget(members []api.PostgresClusterMember, to string) (*api.PostgresClusterMember, error) {
+	var candidates []*api.PostgresClusterMember
+	for i := range members {
+		m := &members[i]
+		if m.Role == "leader" {
+			if to != "" && m.ID == to {
+				return nil, fmt.Errorf("%s is already the leader", to)
+			}
+			continue
+		}
+		if to != "" && m.ID != to && m.Region != to {
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+
+	if len(candidates) == 0 {
+		if to != "" {
+			return nil, fmt.Errorf("no replica %s, see `flyctl postgres members`", to)
+		}
+		return nil, errors.New("the cluster has no replicas to fail over to, add one with `flyctl postgres replicas add`")
+	}
+
+	var best *api.PostgresClusterMember
+	for _, m := range candidates {
+		if !m.Healthy {
+			continue
+		}
+		if best == nil || m.LagBytes < best.LagBytes {
+			best = m
+		}
+	}
+	if best == nil {
+		ids := []string{}
+		for _, m := range candidates {
+			ids = append(ids, m.ID)
+		}
+		return nil, fmt.Errorf("no healthy replica to fail over to: %s", strings.Join(ids, ", "))
+	}
+
+	return best, nil
+}
+
+func waitForPostgresLeader(ctx *cmdctx.CmdContext, memberID string) error {
+	deadline := time.Now().Add(postgresFailoverTimeout)
+	for {
+		members, err := listPostgresMembers(ctx)
+		if err != nil {
+			return err
+		}
+		if leader := postgresLeader(members); leader != nil && leader.ID == memberID && leader.Healthy {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s isn't the leader after %s, check with `flyctl postgres members %s`", memberID, postgresFailoverTimeout, ctx.AppName)
+		}
+		time.Sleep(postgresFailoverPoll)
+	}
+}
+
+func runPostgresReplicasAdd(ctx *cmdctx.CmdContext) error {
+	region := ctx.Config.GetString("region")
+	if region == "" {
+		return &ValidationError{errors.New("--region is required")}
+	}
+
+	s := iostreams.NewSpinner(fmt.Sprintf("Adding a replica in %s...", region))
+	s.Start()
+	member, err := ctx.Client.API().AddPostgresReplica(ctx.AppName, region, ctx.Config.GetInt("volume-size"))
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(member)
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Added replica %s in %s, see when it's caught up with `flyctl postgres members %s`\n", member.ID, member.Region, ctx.AppName)
+
+	return nil
+}
+
+func runPostgresReplicasRemove(ctx *cmdctx.CmdContext) error {
+	memberID := ctx.Args[1]
+
+	members, err := listPostgresMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var member *api.PostgresClusterMember
+	for i := range members {
+		if members[i].ID == memberID {
+			member = &members[i]
+		}
+	}
+	switch {
+	case member == nil:
+		return fmt.Errorf("no member %s, see `flyctl postgres members %s`", memberID, ctx.AppName)
+	case member.Role == "leader":
+		return fmt.Errorf("%s is the leader, fail over to another member with `flyctl postgres failover` before removing it", memberID)
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Remove replica %s in %s? Its volume will be deleted", member.ID, member.Region), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().RemovePostgresReplica(ctx.AppName, member.ID); err != nil {
+		return err
+	}
+
+	ctx.Statusf("postgres", cmdctx.SDONE, "Removed replica %s in %s\n", member.ID, member.Region)
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestChoosePostgresFailoverTarget(t *testing.T) {
+	members := []api.PostgresClusterMember{
+		{ID: "m1", Region: "iad", Role: "leader", Healthy: true},
+		{ID: "m2", Region: "lhr", Role: "replica", Healthy: true, LagBytes: 4096},
+		{ID: "m3", Region: "ord", Role: "replica", Healthy: true, LagBytes: 512},
+		{ID: "m4", Region: "syd", Role: "replica", Healthy: false},
+	}
+
+	tests := []struct {
+		name    string
+		members []api.PostgresClusterMember
+		to      string
+		want    string
+		wantErr bool
+	}{
+		{name: "least lag", members: members, want: "m3"},
+		{name: "by id", members: members, to: "m2", want: "m2"},
+		{name: "by region", members: members, to: "lhr", want: "m2"},
+		{name: "the leader", members: members, to: "m1", wantErr: true},
+		{name: "unhealthy", members: members, to: "m4", wantErr: true},
+		{name: "unknown", members: members, to: "m9", wantErr: true},
+		{name: "no replicas", members: members[:1], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := choosePostgresFailoverTarget(tt.members, tt.to)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", tt.name, got.ID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got.ID, tt.want)
+		}
+	}
+}
+
+func TestPostgresLeader(t *testing.T) {
+	members := []api.PostgresClusterMember{
+		{ID: "m1", Role: "replica"},
+		{ID: "m2", Role: "leader"},
+	}
+
+	if leader := postgresLeader(members); leader == nil || leader.ID != "m2" {
+		t.Errorf("got %v, want m2", leader)
+	}
+	if leader := postgresLeader(members[:1]); leader != nil {
+		t.Errorf("got %s, want no leader", leader.ID)
+	}
+}
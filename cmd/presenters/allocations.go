@@ -7,11 +7,14 @@ import (
 
 	"github.com/logrusorgru/aurora"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/pkg/iostreams"
 )
 
 type Allocations struct {
 	Allocations   []*api.AllocationStatus
 	BackupRegions []api.Region
+	// AutoStart marks stopped allocations as woken by the next request
+	AutoStart bool
 }
 
 func (p *Allocations) APIStruct() interface{} {
@@ -29,7 +32,7 @@ func (p *Allocations) Records() []map[string]string {
 	for _, alloc := range p.Allocations {
 		version := strconv.Itoa(alloc.Version)
 		if multipleVersions && alloc.LatestVersion {
-			version = version + " " + aurora.Green("⇡").String()
+			version = version + " " + aurora.Green(iostreams.Glyph("⇡")).String()
 		}
 
 		region := alloc.Region
@@ -42,10 +45,15 @@ func (p *Allocations) Records() []map[string]string {
 			}
 		}
 
+		status := formatAllocStatus(alloc)
+		if p.AutoStart && alloc.Status == "stopped" {
+			status = "stopped (wakes on request)"
+		}
+
 		out = append(out, map[string]string{
 			"ID":            alloc.IDShort,
 			"Version":       version,
-			"Status":        formatAllocStatus(alloc),
+			"Status":        status,
 			"Desired":       alloc.DesiredStatus,
 			"Region":        region,
 			"Created":       FormatRelativeTime(alloc.CreatedAt),
@@ -7,6 +7,8 @@ import (
 type Apps struct {
 	App  *api.App
 	Apps []api.App
+	// Wide adds a column for each of the apps' annotations
+	Wide bool
 }
 
 func (p *Apps) APIStruct() interface{} {
@@ -14,7 +16,13 @@ func (p *Apps) APIStruct() interface{} {
 }
 
 func (p *Apps) FieldNames() []string {
-	return []string{"Name", "Owner", "Status", "Latest Deploy"}
+	fields := []string{"Name", "Owner", "Status", "Latest Deploy"}
+	if p.Wide {
+		for _, a := range api.AppAnnotations {
+			fields = append(fields, a.Title)
+		}
+	}
+	return fields
 }
 
 func (p *Apps) Records() []map[string]string {
@@ -30,12 +38,19 @@ func (p *Apps) Records() []map[string]string {
 			latestDeploy = FormatRelativeTime(p.Apps[i].CurrentRelease.CreatedAt)
 		}
 
-		out = append(out, map[string]string{
+		record := map[string]string{
 			"Name":          p.Apps[i].Name,
 			"Owner":         p.Apps[i].Organization.Slug,
 			"Status":        p.Apps[i].Status,
 			"Latest Deploy": latestDeploy,
-		})
+		}
+		if p.Wide {
+			for _, a := range api.AppAnnotations {
+				record[a.Title] = p.Apps[i].Metadata[a.Key]
+			}
+		}
+
+		out = append(out, record)
 	}
 
 	return out
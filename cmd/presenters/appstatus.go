@@ -1,6 +1,7 @@
 package presenters
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/superfly/flyctl/api"
@@ -15,17 +16,24 @@ func (p *AppStatus) APIStruct() interface{} {
 }
 
 func (p *AppStatus) FieldNames() []string {
-	return []string{"Name", "Owner", "Version", "Status", "Hostname"}
+	fields := []string{"Name", "Owner", "Version", "Status", "Hostname", "Autostop"}
+	for _, a := range api.AppAnnotations {
+		if p.AppStatus.Metadata[a.Key] != "" {
+			fields = append(fields, a.Title)
+		}
+	}
+	return fields
 }
 
 func (p *AppStatus) Records() []map[string]string {
 	out := []map[string]string{}
 
 	info := map[string]string{
-		"Name":    p.AppStatus.Name,
-		"Owner":   p.AppStatus.Organization.Slug,
-		"Version": strconv.Itoa(p.AppStatus.Version),
-		"Status":  p.AppStatus.Status,
+		"Name":     p.AppStatus.Name,
+		"Owner":    p.AppStatus.Organization.Slug,
+		"Version":  strconv.Itoa(p.AppStatus.Version),
+		"Status":   p.AppStatus.Status,
+		"Autostop": FormatAutostop(p.AppStatus.Autostop),
 	}
 
 	if len(p.AppStatus.Hostname) > 0 {
@@ -34,7 +42,26 @@ func (p *AppStatus) Records() []map[string]string {
 		info["Hostname"] = "<empty>"
 	}
 
+	for _, a := range api.AppAnnotations {
+		if value := p.AppStatus.Metadata[a.Key]; value != "" {
+			info[a.Title] = value
+		}
+	}
+
 	out = append(out, info)
 
 	return out
 }
+
+// FormatAutostop - a one line summary of an app's autostop settings
+func FormatAutostop(cfg *api.AutostopConfig) string {
+	if cfg == nil || !cfg.AutoStop {
+		return "off"
+	}
+
+	summary := fmt.Sprintf("on, keeps %d running", cfg.MinMachinesRunning)
+	if cfg.AutoStart {
+		summary += ", starts on request"
+	}
+	return summary
+}
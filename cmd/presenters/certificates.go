@@ -0,0 +1,32 @@
+package presenters
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/superfly/flyctl/api"
+)
+
+type Certificates struct {
+	Certificates []api.AppCertificateCompact
+}
+
+func (p *Certificates) APIStruct() interface{} {
+	return p.Certificates
+}
+
+func (p *Certificates) FieldNames() []string {
+	return []string{"Host Name", "Added", "Status"}
+}
+
+func (p *Certificates) Records() []map[string]string {
+	out := []map[string]string{}
+
+	for _, c := range p.Certificates {
+		out = append(out, map[string]string{
+			"Host Name": c.Hostname,
+			"Added":     humanize.Time(c.CreatedAt),
+			"Status":    c.ClientStatus,
+		})
+	}
+
+	return out
+}
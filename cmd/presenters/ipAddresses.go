@@ -1,29 +1,63 @@
 package presenters
 
 import (
+	"strings"
+
 	"github.com/superfly/flyctl/api"
 )
 
 type IPAddresses struct {
 	IPAddresses []api.IPAddress
+	// Certificates are the hostnames of the app's certificates keyed by the
+	// address they resolve to
+	Certificates map[string][]string
+}
+
+// ipAddressWithCertificates - the structured output of an IP address
+type ipAddressWithCertificates struct {
+	api.IPAddress
+	Certificates []string `json:"certificates"`
 }
 
 func (p *IPAddresses) APIStruct() interface{} {
-	return p.IPAddresses
+	if p.Certificates == nil {
+		return p.IPAddresses
+	}
+
+	out := []ipAddressWithCertificates{}
+	for _, ip := range p.IPAddresses {
+		certs := p.Certificates[ip.Address]
+		if certs == nil {
+			certs = []string{}
+		}
+		out = append(out, ipAddressWithCertificates{IPAddress: ip, Certificates: certs})
+	}
+	return out
 }
 
 func (p *IPAddresses) FieldNames() []string {
-	return []string{"Type", "Address", "Created At"}
+	fields := []string{"Type", "Address", "Region", "Created At"}
+	if p.Certificates != nil {
+		fields = append(fields, "Certificates")
+	}
+	return fields
 }
 
 func (p *IPAddresses) Records() []map[string]string {
 	out := []map[string]string{}
 
 	for _, ip := range p.IPAddresses {
+		region := ip.Region
+		if region == "" {
+			region = "global"
+		}
+
 		out = append(out, map[string]string{
-			"Address":    ip.Address,
-			"Type":       ip.Type,
-			"Created At": FormatRelativeTime(ip.CreatedAt),
+			"Address":      ip.Address,
+			"Type":         ip.Type,
+			"Region":       region,
+			"Created At":   FormatRelativeTime(ip.CreatedAt),
+			"Certificates": strings.Join(p.Certificates[ip.Address], ", "),
 		})
 	}
 
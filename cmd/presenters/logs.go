@@ -8,6 +8,7 @@ import (
 
 	"github.com/logrusorgru/aurora"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/pkg/iostreams"
 )
 
 type LogPresenter struct {
@@ -22,7 +23,11 @@ func (lp *LogPresenter) FPrint(w io.Writer, asJSON bool, entries []api.LogEntry)
 	}
 }
 
-var newLineReplacer = strings.NewReplacer("\r\n", aurora.Faint("↩︎").String(), "\n", aurora.Faint("↩︎").String())
+func newLineReplacer() *strings.Replacer {
+	nl := aurora.Faint(iostreams.Glyph("↩︎")).String()
+	return strings.NewReplacer("\r\n", nl, "\n", nl)
+}
+
 var newline = []byte("\n")
 
 func (lp *LogPresenter) printEntry(w io.Writer, asJSON bool, entry api.LogEntry) {
@@ -61,7 +66,7 @@ func (lp *LogPresenter) printEntry(w io.Writer, asJSON bool, entry api.LogEntry)
 
 	if !hadErrorMsg {
 		if lp.RemoveNewlines {
-			_, _ = newLineReplacer.WriteString(w, entry.Message)
+			_, _ = newLineReplacer().WriteString(w, entry.Message)
 		} else {
 			_, _ = w.Write([]byte(entry.Message))
 		}
@@ -0,0 +1,59 @@
+package presenters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+)
+
+type Machines struct {
+	Machines []api.Machine
+}
+
+func (p *Machines) APIStruct() interface{} {
+	return p.Machines
+}
+
+func (p *Machines) FieldNames() []string {
+	return []string{"ID", "Name", "State", "Region", "IPs", "Image", "GPUs", "Created"}
+}
+
+func (p *Machines) Records() []map[string]string {
+	out := []map[string]string{}
+
+	for _, machine := range p.Machines {
+		out = append(out, map[string]string{
+			"ID":      machine.ID,
+			"Name":    machine.Name,
+			"State":   machine.State,
+			"Region":  machine.Region,
+			"IPs":     FormatMachineIPs(machine),
+			"Image":   machine.Image,
+			"GPUs":    FormatGPUs(machine.GPUKind, machine.GPUs),
+			"Created": FormatRelativeTime(machine.CreatedAt),
+		})
+	}
+
+	return out
+}
+
+// FormatMachineIPs lists a machine's addresses, "-" when it has none yet
+func FormatMachineIPs(machine api.Machine) string {
+	ips := make([]string, 0, len(machine.IPs.Nodes))
+	for _, ip := range machine.IPs.Nodes {
+		ips = append(ips, ip.IP)
+	}
+	if len(ips) == 0 {
+		return "-"
+	}
+	return strings.Join(ips, ", ")
+}
+
+// FormatGPUs describes a GPU allocation, e.g. "2 x a100"
+func FormatGPUs(kind string, count int) string {
+	if kind == "" || count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d x %s", count, kind)
+}
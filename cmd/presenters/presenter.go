@@ -1,12 +1,12 @@
 package presenters
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/logrusorgru/aurora"
 	"io"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/superfly/flyctl/internal/render"
 )
 
 // Presentable - Records (and field names) which may be presented by a Presenter
@@ -28,13 +28,15 @@ type Options struct {
 	Vertical   bool
 	HideHeader bool
 	Title      string
-	AsJSON     bool
+	Output     render.Options
+	// Quiet leaves out titles and spacing, printing only the records
+	Quiet bool
 }
 
 // Render - Renders a presenter as a field list or table
 func (p *Presenter) Render() error {
-	if p.Opts.AsJSON {
-		return p.renderJSON()
+	if p.Opts.Output.Structured() {
+		return p.renderData()
 	}
 
 	if p.Opts.Vertical {
@@ -45,7 +47,7 @@ func (p *Presenter) Render() error {
 }
 
 func (p *Presenter) renderTable() error {
-	if p.Opts.Title != "" {
+	if p.Opts.Title != "" && !p.Opts.Quiet {
 		fmt.Fprintln(p.Out, aurora.Bold(p.Opts.Title))
 	}
 
@@ -76,7 +78,9 @@ func (p *Presenter) renderTable() error {
 
 	table.Render()
 
-	fmt.Fprintln(p.Out)
+	if !p.Opts.Quiet {
+		fmt.Fprintln(p.Out)
+	}
 
 	return nil
 }
@@ -84,7 +88,7 @@ func (p *Presenter) renderTable() error {
 func (p *Presenter) renderFieldList() error {
 	table := tablewriter.NewWriter(p.Out)
 
-	if p.Opts.Title != "" {
+	if p.Opts.Title != "" && !p.Opts.Quiet {
 		fmt.Fprintln(p.Out, aurora.Bold(p.Opts.Title))
 	}
 	cols := p.Item.FieldNames()
@@ -100,28 +104,26 @@ func (p *Presenter) renderFieldList() error {
 		}
 		table.Render()
 
-		fmt.Fprintln(p.Out)
+		if !p.Opts.Quiet {
+			fmt.Fprintln(p.Out)
+		}
 	}
 
 	return nil
 }
 
-func (p *Presenter) renderJSON() error {
+func (p *Presenter) renderData() error {
 	var data = p.Item.APIStruct()
 
 	if data == nil {
-		return fmt.Errorf("JSON output not available")
+		return fmt.Errorf("%s output not available", p.Opts.Output.Format)
 	}
 
-	if p.Opts.Title == "" {
-		prettyJSON, err := json.MarshalIndent(data, "", "    ")
-		fmt.Fprintln(p.Out, string(prettyJSON))
-		return err
-	} else {
-		wrapper := make(map[string]interface{}, 1)
-		wrapper[p.Opts.Title] = p.Item.APIStruct()
-		prettyJSON, err := json.MarshalIndent(wrapper, "", "    ")
-		fmt.Fprintln(p.Out, string(prettyJSON))
-		return err
+	// JSON and YAML keep the title as their top level key, while templates
+	// run against the item itself so {{.Name}} works
+	if p.Opts.Title != "" && p.Opts.Output.Format != render.FormatTemplate {
+		data = map[string]interface{}{p.Opts.Title: data}
 	}
+
+	return render.Data(p.Out, p.Opts.Output, data)
 }
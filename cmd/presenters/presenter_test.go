@@ -0,0 +1,47 @@
+package presenters
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func TestRenderTitledTemplate(t *testing.T) {
+	var out bytes.Buffer
+	p := &Presenter{
+		Item: &AppCompact{AppCompact: api.AppCompact{Name: "web", Hostname: "web.fly.dev"}},
+		Out:  &out,
+		Opts: Options{Title: "App", Output: render.Options{Format: render.FormatTemplate, Template: "{{.Name}} {{.Hostname}}"}},
+	}
+
+	if err := p.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "web web.fly.dev\n" {
+		t.Errorf("got %q, want %q", got, "web web.fly.dev\n")
+	}
+}
+
+func TestRenderTitledJSON(t *testing.T) {
+	var out bytes.Buffer
+	p := &Presenter{
+		Item: &AppCompact{AppCompact: api.AppCompact{Name: "web"}},
+		Out:  &out,
+		Opts: Options{Title: "App", Output: render.Options{Format: render.FormatJSON}},
+	}
+
+	if err := p.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]api.AppCompact
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["App"].Name != "web" {
+		t.Errorf("expected the app under its title, got %s", out.String())
+	}
+}
@@ -1,7 +1,10 @@
 package presenters
 
 import (
+	"strings"
+
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/pkg/iostreams"
 )
 
 type Regions struct {
@@ -22,7 +25,7 @@ func (p *Regions) Records() []map[string]string {
 	for _, region := range p.Regions {
 		gateway := ""
 		if region.GatewayAvailable {
-			gateway = "✓"
+			gateway = iostreams.Glyph("✓")
 		}
 		out = append(out, map[string]string{
 			"Code":    region.Code,
@@ -33,3 +36,52 @@ func (p *Regions) Records() []map[string]string {
 
 	return out
 }
+
+// RegionCapabilities - presents regions along with what each supports
+type RegionCapabilities struct {
+	Regions []api.Region
+}
+
+func (p *RegionCapabilities) APIStruct() interface{} {
+	return p.Regions
+}
+
+func (p *RegionCapabilities) FieldNames() []string {
+	return []string{"Code", "Name", "Gateway", "Volumes", "Dedicated IPs", "Machines", "GPUs", "Capacity"}
+}
+
+func (p *RegionCapabilities) Records() []map[string]string {
+	out := []map[string]string{}
+
+	check := func(ok bool) string {
+		if ok {
+			return iostreams.Glyph("✓")
+		}
+		return ""
+	}
+
+	for _, region := range p.Regions {
+		caps := api.RegionCapabilities{}
+		if region.Capabilities != nil {
+			caps = *region.Capabilities
+		}
+
+		gpus := check(caps.GPUs)
+		if caps.GPUs && len(caps.GPUKinds) > 0 {
+			gpus = strings.Join(caps.GPUKinds, ", ")
+		}
+
+		out = append(out, map[string]string{
+			"Code":          region.Code,
+			"Name":          region.Name,
+			"Gateway":       check(region.GatewayAvailable),
+			"Volumes":       check(caps.Volumes),
+			"Dedicated IPs": check(caps.DedicatedIPs),
+			"Machines":      check(caps.Machines),
+			"GPUs":          gpus,
+			"Capacity":      caps.Capacity,
+		})
+	}
+
+	return out
+}
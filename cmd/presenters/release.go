@@ -10,6 +10,8 @@ import (
 type Releases struct {
 	Releases []api.Release
 	Release  *api.Release
+	// ShowImage adds a column with each release's image reference and digest
+	ShowImage bool
 }
 
 func (p *Releases) APIStruct() interface{} {
@@ -17,7 +19,10 @@ func (p *Releases) APIStruct() interface{} {
 }
 
 func (p *Releases) FieldNames() []string {
-	return []string{"Version", "Stable", "Type", "Status", "Description", "User", "Date"}
+	if p.ShowImage {
+		return []string{"Version", "Stable", "Type", "Status", "Image", "Message", "User", "Date"}
+	}
+	return []string{"Version", "Stable", "Type", "Status", "Description", "Message", "User", "Date"}
 }
 
 func (p *Releases) Records() []map[string]string {
@@ -34,6 +39,8 @@ func (p *Releases) Records() []map[string]string {
 			"Status":      release.Status,
 			"Type":        formatReleaseReason(release.Reason),
 			"Description": formatReleaseDescription(release),
+			"Message":     release.Message,
+			"Image":       formatReleaseImage(release),
 			"User":        release.User.Email,
 			"Date":        FormatRelativeTime(release.CreatedAt),
 		})
@@ -60,3 +67,10 @@ func formatReleaseDescription(r api.Release) string {
 	}
 	return r.Description
 }
+
+func formatReleaseImage(r api.Release) string {
+	if r.ImageDigest == "" || strings.Contains(r.ImageRef, "@") {
+		return r.ImageRef
+	}
+	return r.ImageRef + "@" + r.ImageDigest
+}
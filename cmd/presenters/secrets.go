@@ -1,6 +1,8 @@
 package presenters
 
 import (
+	"fmt"
+
 	"github.com/superfly/flyctl/api"
 )
 
@@ -28,3 +30,37 @@ func (p *Secrets) Records() []map[string]string {
 
 	return out
 }
+
+type SecretChanges struct {
+	Changes []api.SecretChange
+}
+
+func (p *SecretChanges) APIStruct() interface{} {
+	return p.Changes
+}
+
+func (p *SecretChanges) FieldNames() []string {
+	return []string{"Name", "Action", "Digest", "Release", "User", "Date"}
+}
+
+func (p *SecretChanges) Records() []map[string]string {
+	out := []map[string]string{}
+
+	for _, change := range p.Changes {
+		release := ""
+		if change.Release != nil {
+			release = fmt.Sprintf("v%d", change.Release.Version)
+		}
+
+		out = append(out, map[string]string{
+			"Name":    change.Name,
+			"Action":  change.Action,
+			"Digest":  change.Digest,
+			"Release": release,
+			"User":    change.User.Email,
+			"Date":    FormatRelativeTime(change.CreatedAt),
+		})
+	}
+
+	return out
+}
@@ -15,7 +15,7 @@ func (p *VMSizes) APIStruct() interface{} {
 }
 
 func (p *VMSizes) FieldNames() []string {
-	return []string{"Name", "CPU Cores", "Memory"}
+	return []string{"Name", "CPU Cores", "Memory", "Price (Month)", "Price (Second)"}
 }
 
 func (p *VMSizes) Records() []map[string]string {
@@ -26,6 +26,9 @@ func (p *VMSizes) Records() []map[string]string {
 			"Name":      size.Name,
 			"CPU Cores": formatCores(size),
 			"Memory":    formatMemory(size),
+
+			"Price (Month)":  fmt.Sprintf("$%.2f", size.PriceMonth),
+			"Price (Second)": fmt.Sprintf("$%.8f", size.PriceSecond),
 		})
 	}
 
@@ -45,3 +48,34 @@ func formatMemory(size api.VMSize) string {
 	}
 	return fmt.Sprintf("%d GB", int(size.MemoryGB))
 }
+
+// VMResources - presents the VM size of an app and how many VMs each of its
+// process groups runs
+type VMResources struct {
+	Size   api.VMSize
+	Counts []api.TaskGroupCount
+}
+
+func (p *VMResources) APIStruct() interface{} {
+	return p.Counts
+}
+
+func (p *VMResources) FieldNames() []string {
+	return []string{"Process Group", "Count", "Size", "CPU Cores", "Memory"}
+}
+
+func (p *VMResources) Records() []map[string]string {
+	out := []map[string]string{}
+
+	for _, tg := range p.Counts {
+		out = append(out, map[string]string{
+			"Process Group": tg.Name,
+			"Count":         fmt.Sprintf("%d", tg.Count),
+			"Size":          p.Size.Name,
+			"CPU Cores":     formatCores(p.Size),
+			"Memory":        formatMemory(p.Size),
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/agent"
+	"github.com/superfly/flyctl/pkg/iostreams"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// defaultRedisPort - the port Redis listens on when the URL doesn't say
+const defaultRedisPort = "6379"
+
+func newRedisCommand(client *client.Client) *Command {
+	redisStrings := docstrings.Get("redis")
+	cmd := BuildCommandKS(nil, nil, redisStrings, client, requireSession)
+
+	createStrings := docstrings.Get("redis.create")
+	createCmd := BuildCommandKS(cmd, runRedisCreate, createStrings, client, requireSession)
+	addRedisOrgFlag(createCmd)
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Shorthand:   "n",
+		Description: "The name of the Redis instance",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "The primary region of the Redis instance",
+	})
+	createCmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "replica-regions",
+		Description: "Regions to add read replicas in, e.g. lhr,syd",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "plan",
+		Description: "The plan of the Redis instance, see the docs for what each includes",
+		Default:     "free",
+	})
+	createCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "enable-eviction",
+		Description: "Evict keys when the instance runs out of memory, rather than failing writes",
+	})
+
+	listStrings := docstrings.Get("redis.list")
+	listCmd := BuildCommandKS(cmd, runRedisList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+	addRedisOrgFlag(listCmd)
+
+	statusStrings := docstrings.Get("redis.status")
+	statusCmd := BuildCommandKS(cmd, runRedisStatus, statusStrings, client, requireSession)
+	statusCmd.Args = cobra.ExactArgs(1)
+
+	connectStrings := docstrings.Get("redis.connect")
+	connectCmd := BuildCommandKS(cmd, runRedisConnect, connectStrings, client, requireSession)
+	connectCmd.Args = cobra.ExactArgs(1)
+
+	destroyStrings := docstrings.Get("redis.destroy")
+	destroyCmd := BuildCommandKS(cmd, runRedisDestroy, destroyStrings, client, requireSession)
+	destroyCmd.Args = cobra.ExactArgs(1)
+	destroyCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	return cmd
+}
+
+func addRedisOrgFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization the Redis instance belongs to",
+		EnvName:     "FLY_ORG",
+	})
+}
+
+func runRedisCreate(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	name := ctx.Config.GetString("name")
+	if name == "" {
+		if name, err = inputAppName(""); err != nil {
+			return err
+		}
+	}
+
+	region, err := selectRegion(ctx.Client.API(), ctx.Config.GetString("region"))
+	if err != nil {
+		return err
+	}
+
+	replicas := ctx.Config.GetStringSlice("replica-regions")
+	for _, r := range replicas {
+		if r == region.Code {
+			return &ValidationError{fmt.Errorf("%s is the primary region, it can't also have a replica", r)}
+		}
+	}
+
+	s := iostreams.NewSpinner("Provisioning...")
+	s.Start()
+
+	instance, err := ctx.Client.API().CreateRedisInstance(api.CreateRedisInstanceInput{
+		OrganizationID:  org.ID,
+		Name:            name,
+		PrimaryRegion:   region.Code,
+		ReadRegions:     replicas,
+		Plan:            ctx.Config.GetString("plan"),
+		EvictionEnabled: ctx.Config.GetBool("enable-eviction"),
+	})
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(instance)
+	}
+
+	ctx.Statusf("redis", cmdctx.SDONE, "Created Redis instance %s in %s\n", instance.Name, org.Slug)
+	ctx.Statusf("redis", cmdctx.SINFO, "Apps in %s can connect with this URL, set it as a secret rather than in fly.toml:\n", org.Slug)
+	fmt.Fprintln(ctx.Out, instance.PrivateURL)
+
+	return nil
+}
+
+func runRedisList(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	instances, err := ctx.Client.API().GetRedisInstances(org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(instances)
+	}
+
+	if len(instances) == 0 {
+		ctx.Statusf("redis", cmdctx.SINFO, "%s has no Redis instances, create one with `flyctl redis create`\n", org.Slug)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Name", "Status", "Plan", "Primary Region", "Replica Regions", "Created"})
+	for _, instance := range instances {
+		table.Append([]string{
+			instance.Name,
+			instance.Status,
+			instance.Plan,
+			instance.PrimaryRegion,
+			strings.Join(instance.ReadRegions, ", "),
+			humanize.Time(instance.CreatedAt),
+		})
+	}
+	table.Render()
+
+	return nil
+}
+
+func runRedisStatus(ctx *cmdctx.CmdContext) error {
+	instance, err := getRedisInstance(ctx, ctx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(instance)
+	}
+
+	eviction := "disabled"
+	if instance.EvictionEnabled {
+		eviction = "enabled"
+	}
+	replicas := strings.Join(instance.ReadRegions, ", ")
+	if replicas == "" {
+		replicas = "none"
+	}
+
+	fields := [][2]string{
+		{"Name", instance.Name},
+		{"Organization", instance.Organization.Slug},
+		{"Status", instance.Status},
+		{"Plan", instance.Plan},
+		{"Primary Region", instance.PrimaryRegion},
+		{"Replica Regions", replicas},
+		{"Eviction", eviction},
+		{"Private URL", instance.PrivateURL},
+		{"Created", humanize.Time(instance.CreatedAt)},
+	}
+	for _, f := range fields {
+		fmt.Fprintf(ctx.Out, "%-16s %s\n", f[0], f[1])
+	}
+
+	return nil
+}
+
+func runRedisConnect(ctx *cmdctx.CmdContext) error {
+	instance, err := getRedisInstance(ctx, ctx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	redisURL, err := url.Parse(instance.PrivateURL)
+	if err != nil || redisURL.Host == "" {
+		return fmt.Errorf("%s has no usable private URL, it may still be provisioning", instance.Name)
+	}
+	remote := redisURL.Host
+	if redisURL.Port() == "" {
+		remote = net.JoinHostPort(redisURL.Hostname(), defaultRedisPort)
+	}
+
+	binary, err := exec.LookPath("redis-cli")
+	if err != nil {
+		return errors.Wrap(err, "redis-cli not found - make sure it's installed and try again")
+	}
+
+	agentclient, err := establishAgent(ctx)
+	if err != nil {
+		return fmt.Errorf("can't establish agent: %s", err)
+	}
+
+	org := &api.Organization{ID: instance.Organization.ID, Slug: instance.Organization.Slug}
+	dialer, err := agentclient.Dialer(org)
+	if err != nil {
+		return fmt.Errorf("can't build tunnel for %s: %s", org.Slug, err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	cancelCtx := createCancellableContext()
+	go proxyConnections(cancelCtx, listener, dialer, remote)
+
+	// the password goes in the environment, as other users can read argv
+	local, password := urlWithoutPassword(*redisURL)
+	local.Host = listener.Addr().String()
+
+	ctx.Statusf("redis", cmdctx.SINFO, "Connecting to %s through a tunnel to %s\n", instance.Name, remote)
+
+	cmd := exec.CommandContext(cancelCtx, binary, "-u", local.String())
+	if password != "" {
+		cmd.Env = append(os.Environ(), "REDISCLI_AUTH="+password)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// urlWithoutPassword - u without the password in its user info, and the
+// password, so a client tool can be given it in its environment instead
+func urlWithoutPassword(u url.URL) (url.URL, string) {
+	if u.User == nil {
+		return u, ""
+	}
+	password, _ := u.User.Password()
+	if username := u.User.Username(); username != "" {
+		u.User = url.User(username)
+	} else {
+		u.User = nil
+	}
+	return u, password
+}
+
+// proxyConnections - forwards each connection accepted by l to addr on the
+// organization's private network, until ctx is done or l is closed
+func proxyConnections(ctx context.Context, l net.Listener, dialer *agent.Dialer, addr string) {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		local, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer local.Close()
+
+			remote, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				terminal.Debugf("can't reach %s: %v\n", addr, err)
+				return
+			}
+			defer remote.Close()
+
+			done := make(chan struct{}, 2)
+			go func() {
+				io.Copy(remote, local)
+				done <- struct{}{}
+			}()
+			go func() {
+				io.Copy(local, remote)
+				done <- struct{}{}
+			}()
+			<-done
+		}()
+	}
+}
+
+func runRedisDestroy(ctx *cmdctx.CmdContext) error {
+	instance, err := getRedisInstance(ctx, ctx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Destroy Redis instance %s? Its data will be lost", instance.Name), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DeleteRedisInstance(instance.ID); err != nil {
+		return err
+	}
+
+	ctx.Statusf("redis", cmdctx.SDONE, "Destroyed Redis instance %s\n", instance.Name)
+
+	return nil
+}
+
+func getRedisInstance(ctx *cmdctx.CmdContext, name string) (*api.RedisInstance, error) {
+	instance, err := ctx.Client.API().GetRedisInstance(name)
+	if err == api.ErrNotFound {
+		return nil, fmt.Errorf("Redis instance %s not found, see `flyctl redis list`", name)
+	}
+	return instance, err
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/docstrings"
@@ -24,8 +29,7 @@ func newRegionsCommand(client *client.Client) *Command {
 	removeCmd.Args = cobra.MinimumNArgs(1)
 
 	setStrings := docstrings.Get("regions.set")
-	setCmd := BuildCommandKS(cmd, runRegionsSet, setStrings, client, requireSession, requireAppName)
-	setCmd.Args = cobra.MinimumNArgs(1)
+	BuildCommandKS(cmd, runRegionsSet, setStrings, client, requireSession, requireAppName)
 
 	setBackupStrings := docstrings.Get("regions.backup")
 	setBackupCmd := BuildCommand(cmd, runBackupRegionsSet, setBackupStrings.Usage, setBackupStrings.Short, setBackupStrings.Long, client, requireSession, requireAppName)
@@ -34,10 +38,22 @@ func newRegionsCommand(client *client.Client) *Command {
 	listStrings := docstrings.Get("regions.list")
 	BuildCommand(cmd, runRegionsList, listStrings.Usage, listStrings.Short, listStrings.Long, client, requireSession, requireAppName)
 
+	primaryStrings := docstrings.Get("regions.primary")
+	BuildCommandKS(cmd, runRegionsPrimary, primaryStrings, client, requireSession, requireAppName)
+
+	setPrimaryStrings := docstrings.Get("regions.set-primary")
+	setPrimaryCmd := BuildCommandKS(cmd, runRegionsSetPrimary, setPrimaryStrings, client, requireSession, requireAppName)
+	setPrimaryCmd.Args = cobra.ExactArgs(1)
+
 	return cmd
 }
 
 func runRegionsAdd(ctx *cmdctx.CmdContext) error {
+	oldRegions, oldBackupRegions, err := ctx.Client.API().ListAppRegions(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
 	input := api.ConfigureRegionsInput{
 		AppID:        ctx.AppName,
 		AllowRegions: ctx.Args,
@@ -48,12 +64,15 @@ func runRegionsAdd(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	printRegions(ctx, regions, backupRegions)
-
-	return nil
+	return printRegionsChange(ctx, oldRegions, oldBackupRegions, regions, backupRegions)
 }
 
 func runRegionsRemove(ctx *cmdctx.CmdContext) error {
+	oldRegions, oldBackupRegions, err := ctx.Client.API().ListAppRegions(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
 	input := api.ConfigureRegionsInput{
 		AppID:       ctx.AppName,
 		DenyRegions: ctx.Args,
@@ -64,9 +83,7 @@ func runRegionsRemove(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	printRegions(ctx, regions, backupRegions)
-
-	return nil
+	return printRegionsChange(ctx, oldRegions, oldBackupRegions, regions, backupRegions)
 }
 
 func runRegionsSet(ctx *cmdctx.CmdContext) error {
@@ -74,12 +91,25 @@ func runRegionsSet(ctx *cmdctx.CmdContext) error {
 	delList := make([]string, 0)
 
 	// Get the Region List
-	regions, _, err := ctx.Client.API().ListAppRegions(ctx.AppName)
+	regions, backupRegions, err := ctx.Client.API().ListAppRegions(ctx.AppName)
 	if err != nil {
 		return err
 	}
 
-	for _, r := range ctx.Args {
+	wanted := ctx.Args
+	if len(wanted) == 0 {
+		if wanted, err = selectRegionCodes(ctx, regions); err != nil {
+			if isInterrupt(err) {
+				return nil
+			}
+			return err
+		}
+		if len(wanted) == 0 {
+			return &ValidationError{fmt.Errorf("select at least one region")}
+		}
+	}
+
+	for _, r := range wanted {
 		found := false
 		for _, er := range regions {
 			if r == er.Code {
@@ -94,7 +124,7 @@ func runRegionsSet(ctx *cmdctx.CmdContext) error {
 
 	for _, er := range regions {
 		found := false
-		for _, r := range ctx.Args {
+		for _, r := range wanted {
 			if r == er.Code {
 				found = true
 				break
@@ -111,14 +141,54 @@ func runRegionsSet(ctx *cmdctx.CmdContext) error {
 		DenyRegions:  delList,
 	}
 
-	newregions, backupRegions, err := ctx.Client.API().ConfigureRegions(input)
+	newregions, newBackupRegions, err := ctx.Client.API().ConfigureRegions(input)
 	if err != nil {
 		return err
 	}
 
-	printRegions(ctx, newregions, backupRegions)
+	return printRegionsChange(ctx, regions, backupRegions, newregions, newBackupRegions)
+}
 
-	return nil
+// selectRegionCodes - asks for the app's region pool with a multi-select of
+// every region, the current pool selected, each with an estimated latency
+// from the nearest region to the user
+func selectRegionCodes(ctx *cmdctx.CmdContext, current []api.Region) ([]string, error) {
+	platformRegions, requestRegion, err := ctx.Client.API().PlatformRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(platformRegions, func(i, j int) bool { return platformRegions[i].Code < platformRegions[j].Code })
+
+	options := make([]string, 0, len(platformRegions))
+	defaults := []string{}
+	for _, r := range platformRegions {
+		label := fmt.Sprintf("%s  %s", r.Code, r.Name)
+		if requestRegion != nil {
+			label = fmt.Sprintf("%s  (~%s)", label, requestRegion.EstimateLatency(r))
+		}
+		options = append(options, label)
+
+		for _, c := range current {
+			if c.Code == r.Code {
+				defaults = append(defaults, label)
+			}
+		}
+	}
+
+	selected, err := prompt.MultiSelect("Select the app's regions:", options, defaults, "")
+	if err != nil {
+		if prompt.IsNonInteractiveError(err) {
+			return nil, &ValidationError{fmt.Errorf("pass the region codes to set as arguments when running non-interactively")}
+		}
+		return nil, err
+	}
+
+	out := make([]string, 0, len(selected))
+	for _, i := range selected {
+		out = append(out, platformRegions[i].Code)
+	}
+	return out, nil
 }
 
 func runRegionsList(ctx *cmdctx.CmdContext) error {
@@ -127,12 +197,15 @@ func runRegionsList(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	printRegions(ctx, regions, backupRegions)
-
-	return nil
+	return printRegions(ctx, regions, backupRegions)
 }
 
 func runBackupRegionsSet(ctx *cmdctx.CmdContext) error {
+	oldRegions, oldBackupRegions, err := ctx.Client.API().ListAppRegions(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
 	input := api.ConfigureRegionsInput{
 		AppID:         ctx.AppName,
 		BackupRegions: ctx.Args,
@@ -143,16 +216,13 @@ func runBackupRegionsSet(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	printRegions(ctx, regions, backupRegions)
-
-	return nil
+	return printRegionsChange(ctx, oldRegions, oldBackupRegions, regions, backupRegions)
 }
 
-func printRegions(ctx *cmdctx.CmdContext, regions []api.Region, backupRegions []api.Region) {
+func printRegions(ctx *cmdctx.CmdContext, regions []api.Region, backupRegions []api.Region) error {
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(regions)
-		return
+	if ctx.OutputStructured() {
+		return ctx.WriteData(regions)
 	}
 
 	verbose := ctx.GlobalConfig.GetBool("verbose")
@@ -184,4 +254,59 @@ func printRegions(ctx *cmdctx.CmdContext, regions []api.Region, backupRegions []
 			ctx.Status("backupRegions", cmdctx.SINFO, r.Code)
 		}
 	}
+
+	return nil
+}
+
+// printRegionsChange - prints what changed in the region pools, then the
+// pools themselves
+func printRegionsChange(ctx *cmdctx.CmdContext, oldRegions, oldBackupRegions, regions, backupRegions []api.Region) error {
+	if !ctx.OutputStructured() {
+		added, removed := diffRegions(oldRegions, regions)
+		addedBackup, removedBackup := diffRegions(oldBackupRegions, backupRegions)
+
+		if len(added)+len(removed)+len(addedBackup)+len(removedBackup) == 0 {
+			ctx.Status("regions", cmdctx.SDETAIL, "No changes to the region pools")
+		}
+		for _, r := range added {
+			ctx.Statusf("regions", cmdctx.SINFO, "+ %s  %s\n", r.Code, r.Name)
+		}
+		for _, r := range removed {
+			ctx.Statusf("regions", cmdctx.SINFO, "- %s  %s\n", r.Code, r.Name)
+		}
+		for _, r := range addedBackup {
+			ctx.Statusf("backupRegions", cmdctx.SINFO, "+ %s  %s (backup)\n", r.Code, r.Name)
+		}
+		for _, r := range removedBackup {
+			ctx.Statusf("backupRegions", cmdctx.SINFO, "- %s  %s (backup)\n", r.Code, r.Name)
+		}
+		ctx.StatusLn()
+	}
+
+	return printRegions(ctx, regions, backupRegions)
+}
+
+// diffRegions - the regions in after but not before, and in before but not
+// after
+func diffRegions(before, after []api.Region) (added, removed []api.Region) {
+	in := func(regions []api.Region, code string) bool {
+		for _, r := range regions {
+			if strings.EqualFold(r.Code, code) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, r := range after {
+		if !in(before, r.Code) {
+			added = append(added, r)
+		}
+	}
+	for _, r := range before {
+		if !in(after, r.Code) {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
 }
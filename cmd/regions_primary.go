@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/tomledit"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// primaryRegionMetadataKey - app metadata recording the primary region, for
+// commands run without the app's fly.toml
+const primaryRegionMetadataKey = "fly.primary_region"
+
+// appPrimaryRegion - the app's primary region from fly.toml, falling back to
+// the one recorded on the app. Empty when neither has one.
+func appPrimaryRegion(ctx *cmdctx.CmdContext) (string, error) {
+	if ctx.AppConfig != nil {
+		if region := ctx.AppConfig.PrimaryRegion(); region != "" {
+			return region, nil
+		}
+	}
+
+	metadata, err := ctx.Client.API().GetAppMetadata(ctx.AppName)
+	if err != nil {
+		return "", err
+	}
+
+	return metadata[primaryRegionMetadataKey], nil
+}
+
+// defaultToPrimaryRegion - the region flag's value, or the app's primary
+// region when it isn't set
+func defaultToPrimaryRegion(ctx *cmdctx.CmdContext) string {
+	if region := ctx.Config.GetString("region"); region != "" {
+		return region
+	}
+
+	region, err := appPrimaryRegion(ctx)
+	if err != nil {
+		terminal.Debugf("looking up the primary region failed: %v\n", err)
+		return ""
+	}
+	return region
+}
+
+func runRegionsPrimary(ctx *cmdctx.CmdContext) error {
+	region, err := appPrimaryRegion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(map[string]string{"primaryRegion": region})
+	}
+
+	if region == "" {
+		ctx.Statusf("regions", cmdctx.SINFO, "%s has no primary region, set one with `flyctl regions set-primary`\n", ctx.AppName)
+		return nil
+	}
+
+	fmt.Fprintln(ctx.Out, region)
+
+	return nil
+}
+
+func runRegionsSetPrimary(ctx *cmdctx.CmdContext) error {
+	code := strings.ToLower(ctx.Args[0])
+
+	platformRegions, err := ctx.Client.API().PlatformRegionList()
+	if err != nil {
+		return err
+	}
+
+	var region *api.Region
+	for i := range platformRegions {
+		if platformRegions[i].Code == code {
+			region = &platformRegions[i]
+			break
+		}
+	}
+	if region == nil {
+		return &ValidationError{fmt.Errorf("unknown region %q, see `flyctl platform regions`", code)}
+	}
+
+	if _, err := ctx.Client.API().SetAppMetadata(ctx.AppName, map[string]string{primaryRegionMetadataKey: code}); err != nil {
+		return err
+	}
+
+	if helpers.FileExists(ctx.ConfigFile) {
+		if err := setConfigPrimaryRegion(ctx.ConfigFile, code); err != nil {
+			return fmt.Errorf("update %s: %w", helpers.PathRelativeToCWD(ctx.ConfigFile), err)
+		}
+		ctx.Statusf("regions", cmdctx.SDETAIL, "Set primary_region in %s\n", helpers.PathRelativeToCWD(ctx.ConfigFile))
+	}
+
+	ctx.Statusf("regions", cmdctx.SDONE, "Primary region of %s is now %s (%s)\n", ctx.AppName, region.Code, region.Name)
+
+	pool, _, err := ctx.Client.API().ListAppRegions(ctx.AppName)
+	if err != nil {
+		return err
+	}
+	for _, r := range pool {
+		if r.Code == code {
+			return nil
+		}
+	}
+	ctx.Statusf("regions", cmdctx.SWARN, "%s isn't in the app's region pool, add it with `flyctl regions add %s`\n", code, code)
+
+	return nil
+}
+
+// setConfigPrimaryRegion - writes primary_region to the config file, keeping
+// the rest of it as it is
+func setConfigPrimaryRegion(path string, region string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	doc, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	doc, err = tomledit.Set(doc, "primary_region", region)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, doc, info.Mode().Perm())
+}
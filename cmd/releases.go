@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
 
@@ -12,13 +17,89 @@ import (
 func newReleasesCommand(client *client.Client) *Command {
 	releasesStrings := docstrings.Get("releases")
 	cmd := BuildCommandKS(nil, runReleases, releasesStrings, client, requireSession, requireAppName)
+	cmd.AddIntFlag(IntFlagOpts{
+		Name:        "limit",
+		Description: "The most releases to list, 0 for all of them",
+		Default:     25,
+	})
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "image",
+		Description: "Show the image reference and digest of each release",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "status",
+		Description: "Only list releases with this status, e.g. succeeded or failed",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "user",
+		Description: "Only list releases made by this user, by email or name",
+	})
+	newReleasesDiagnoseCommand(cmd, client)
+	newReleasesWatchCommand(cmd, client)
+
 	return cmd
 }
 
 func runReleases(ctx *cmdctx.CmdContext) error {
-	releases, err := ctx.Client.API().GetAppReleases(ctx.AppName, 25)
-	if err != nil {
-		return err
+	limit := ctx.Config.GetInt("limit")
+	if limit < 0 {
+		return &ValidationError{errors.New("--limit can't be negative")}
+	}
+
+	filter := releaseFilter{
+		status: ctx.Config.GetString("status"),
+		user:   ctx.Config.GetString("user"),
+	}
+
+	releases := []api.Release{}
+	var after string
+
+	for {
+		pageSize := api.MaxReleasesPageSize
+		if filter.empty() && limit > 0 && limit-len(releases) < pageSize {
+			pageSize = limit - len(releases)
+		}
+
+		page, info, err := ctx.Client.API().GetAppReleasesPage(ctx.AppName, pageSize, after)
+		if err != nil {
+			return err
+		}
+
+		for _, release := range page {
+			if !filter.matches(release) {
+				continue
+			}
+			releases = append(releases, release)
+			if limit > 0 && len(releases) == limit {
+				break
+			}
+		}
+
+		if (limit > 0 && len(releases) == limit) || !info.HasNextPage {
+			break
+		}
+		after = info.EndCursor
+	}
+
+	return ctx.Render(&presenters.Releases{Releases: releases, ShowImage: ctx.Config.GetBool("image")})
+}
+
+// releaseFilter - narrows the releases listed by status and user
+type releaseFilter struct {
+	status string
+	user   string
+}
+
+func (f releaseFilter) empty() bool {
+	return f.status == "" && f.user == ""
+}
+
+func (f releaseFilter) matches(release api.Release) bool {
+	if f.status != "" && !strings.EqualFold(release.Status, f.status) {
+		return false
+	}
+	if f.user != "" && !strings.EqualFold(release.User.Email, f.user) && !strings.EqualFold(release.User.Name, f.user) {
+		return false
 	}
-	return ctx.Render(&presenters.Releases{Releases: releases})
+	return true
 }
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/deployment"
+)
+
+// diagnoseLogInstances - the most failed instances whose logs are fetched
+const diagnoseLogInstances = 3
+
+func newReleasesDiagnoseCommand(parent *Command, client *client.Client) {
+	diagnoseStrings := docstrings.Get("releases.diagnose")
+	diagnoseCmd := BuildCommandKS(parent, runReleasesDiagnose, diagnoseStrings, client, requireSession, requireAppName)
+	diagnoseCmd.Args = cobra.ExactArgs(1)
+}
+
+func runReleasesDiagnose(ctx *cmdctx.CmdContext) error {
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(ctx.Args[0]), "v"))
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid release version %q, e.g. 12 or v12", ctx.Args[0])}
+	}
+
+	apiClient := ctx.Client.API()
+
+	release, err := apiClient.GetAppRelease(ctx.AppName, version)
+	if err == api.ErrNotFound {
+		return fmt.Errorf("%s has no release v%d, see `flyctl releases`", ctx.AppName, version)
+	} else if err != nil {
+		return err
+	}
+
+	lastGood, err := lastGoodRelease(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	status, err := apiClient.GetAppStatus(ctx.AppName, true)
+	if err != nil {
+		return err
+	}
+
+	evidence := deployment.ReleaseEvidence{App: ctx.AppName, Release: release, LastGood: lastGood}
+	fetched := 0
+	for _, alloc := range status.Allocations {
+		if alloc.Version != version {
+			continue
+		}
+		evidence.Allocations = append(evidence.Allocations, alloc)
+
+		if fetched == diagnoseLogInstances || !allocationFailing(alloc) {
+			continue
+		}
+		detail, err := apiClient.GetAllocationStatus(ctx.AppName, alloc.ID, 25)
+		if err != nil {
+			ctx.Statusf("releases", cmdctx.SWARN, "Failed to fetch the logs of %s: %s\n", alloc.IDShort, err)
+			continue
+		}
+		evidence.Logs = append(evidence.Logs, detail.RecentLogs...)
+		fetched++
+	}
+
+	causes := deployment.Diagnose(evidence)
+
+	if ctx.OutputStructured() {
+		data := map[string]interface{}{
+			"version": release.Version,
+			"status":  release.Status,
+			"causes":  causes,
+		}
+		if lastGood != nil {
+			data["lastGoodVersion"] = lastGood.Version
+		}
+		return ctx.WriteData(data)
+	}
+
+	fmt.Fprintf(ctx.Out, "Release v%d, %s %s by %s\n", release.Version, release.Status, humanize.Time(release.CreatedAt), release.User.Email)
+	if release.Description != "" {
+		fmt.Fprintln(ctx.Out, release.Description)
+	}
+	if release.Stable {
+		ctx.Statusf("releases", cmdctx.SWARN, "v%d is a stable release, it may not have failed\n", release.Version)
+	}
+	ctx.StatusLn()
+
+	if len(causes) == 0 {
+		ctx.Statusf("releases", cmdctx.SINFO, "No probable cause found. Look through `flyctl logs -a %s` and `flyctl status --all -a %s`\n", ctx.AppName, ctx.AppName)
+		return nil
+	}
+
+	for i, cause := range causes {
+		fmt.Fprintf(ctx.Out, "%d. %s\n", i+1, cause.Summary)
+		for _, e := range cause.Evidence {
+			fmt.Fprintf(ctx.Out, "   - %s\n", e)
+		}
+		if len(cause.Next) > 0 {
+			fmt.Fprintln(ctx.Out, "   Next:")
+			for _, next := range cause.Next {
+				fmt.Fprintf(ctx.Out, "     %s\n", next)
+			}
+		}
+		fmt.Fprintln(ctx.Out)
+	}
+
+	return nil
+}
+
+// lastGoodRelease - the latest stable release before version, with its
+// config, or nil when there's none
+func lastGoodRelease(ctx *cmdctx.CmdContext, version int) (*api.Release, error) {
+	var after string
+	for {
+		page, info, err := ctx.Client.API().GetAppReleasesPage(ctx.AppName, api.MaxReleasesPageSize, after)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range page {
+			if release.Version < version && release.Stable {
+				return ctx.Client.API().GetAppRelease(ctx.AppName, release.Version)
+			}
+		}
+
+		if !info.HasNextPage {
+			return nil, nil
+		}
+		after = info.EndCursor
+	}
+}
+
+// allocationFailing reports whether an instance failed, restarted or has
+// critical checks
+func allocationFailing(alloc *api.AllocationStatus) bool {
+	return alloc.Failed || alloc.Status == "failed" || alloc.Restarts > 0 || alloc.CriticalCheckCount > 0
+}
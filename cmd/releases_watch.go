@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+func newReleasesWatchCommand(parent *Command, client *client.Client) {
+	watchStrings := docstrings.Get("releases.watch")
+	watchCmd := BuildCommandKS(parent, runReleasesWatch, watchStrings, client, requireSession, requireAppName)
+	watchCmd.Args = cobra.ExactArgs(1)
+	watchCmd.AddStringFlag(StringFlagOpts{
+		Name:        "monitor-timeout",
+		Description: "How long to keep reconnecting to the deployment monitor after losing the connection",
+		Default:     "2m",
+	})
+	watchCmd.AddStringFlag(StringFlagOpts{
+		Name:        "watch-window",
+		Description: "How long to keep watching a healthy release for crash loops and flapping health checks, e.g. 30s. Off by default",
+		Default:     "0",
+	})
+}
+
+// printDetached - tells a detached deploy how to reattach to its release
+func printDetached(cmdCtx *cmdctx.CmdContext, release *api.Release) {
+	if cmdCtx.OutputStructured() {
+		cmdCtx.WriteData(map[string]interface{}{"id": release.ID, "version": release.Version})
+		return
+	}
+	cmdCtx.Statusf("deploy", cmdctx.SINFO, "Release ID %s, watch its rollout with `%s releases watch %s`\n", release.ID, flyname.Name(), release.ID)
+}
+
+func runReleasesWatch(ctx *cmdctx.CmdContext) error {
+	release, err := findRelease(ctx, ctx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := ctx.Client.API().GetDeploymentStatus(ctx.AppName, "")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case d != nil && d.Version > release.Version:
+		// a later release has been deployed since, so the release's own
+		// outcome is all there is to report
+		if release.Status == "failed" {
+			ctx.Statusf("deploy", cmdctx.SERROR, "v%d failed and was superseded by v%d\n", release.Version, d.Version)
+			return ErrDeployFailed
+		}
+		ctx.Statusf("deploy", cmdctx.SINFO, "v%d is %s and was superseded by v%d\n", release.Version, release.Status, d.Version)
+		return nil
+
+	case d != nil && d.Version == release.Version && !d.InProgress:
+		if d.Successful {
+			ctx.Statusf("deploy", cmdctx.SDONE, "v%d deployed successfully\n", d.Version)
+			return nil
+		}
+		ctx.Statusf("deploy", cmdctx.SERROR, "v%d %s - %s\n", d.Version, d.Status, d.Description)
+		ctx.Statusf("deploy", cmdctx.SINFO, "Find out why with `%s releases diagnose %d`\n", flyname.Name(), d.Version)
+		return ErrDeployFailed
+
+	case d == nil || d.Version < release.Version:
+		ctx.Statusf("deploy", cmdctx.SINFO, "Waiting for v%d's deployment to start\n", release.Version)
+	}
+
+	return watchDeployment(createCancellableContext(), ctx)
+}
+
+// findRelease - a release by ID, or by version as 12 or v12
+func findRelease(ctx *cmdctx.CmdContext, ref string) (*api.Release, error) {
+	if version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(ref), "v")); err == nil {
+		release, err := ctx.Client.API().GetAppRelease(ctx.AppName, version)
+		if err == api.ErrNotFound {
+			return nil, fmt.Errorf("%s has no release v%d, see `%s releases`", ctx.AppName, version, flyname.Name())
+		}
+		return release, err
+	}
+
+	var after string
+	for {
+		page, info, err := ctx.Client.API().GetAppReleasesPage(ctx.AppName, api.MaxReleasesPageSize, after)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page {
+			if page[i].ID == ref {
+				return &page[i], nil
+			}
+		}
+		if !info.HasNextPage {
+			return nil, fmt.Errorf("%s has no release %s, see `%s releases`", ctx.AppName, ref, flyname.Name())
+		}
+		after = info.EndCursor
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+func runRename(cmdCtx *cmdctx.CmdContext) error {
+	appName := cmdCtx.Args[0]
+	newName := cmdCtx.Args[1]
+
+	if appName == newName {
+		return fmt.Errorf("app is already named %s", appName)
+	}
+
+	app, err := cmdCtx.Client.API().GetApp(appName)
+	if err != nil {
+		return errors.Wrap(err, "Error fetching app")
+	}
+
+	if !cmdCtx.Config.GetBool("yes") {
+		cmdCtx.Statusf("rename", cmdctx.SWARN, "Renaming changes the app's hostname from %s. Any DNS records pointing at it will need updating.\n", app.Hostname)
+
+		confirmed, err := prompt.Confirm(fmt.Sprintf("Rename %s to %s?", appName, newName), "yes")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	renamed, err := cmdCtx.Client.API().RenameApp(appName, newName)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to rename app")
+	}
+
+	cmdCtx.Statusf("rename", cmdctx.SDONE, "Renamed %s to %s, now available at %s\n", appName, renamed.Name, renamed.Hostname)
+
+	if err := recheckRenamedCertificates(cmdCtx, renamed); err != nil {
+		cmdCtx.Statusf("rename", cmdctx.SWARN, "Could not recheck certificates: %s\n", err)
+	}
+
+	return rewriteRenamedAppConfig(cmdCtx, appName, newName)
+}
+
+// recheckRenamedCertificates asks the platform to revalidate each certificate
+// against the app's new hostname and reports any that now need DNS changes.
+func recheckRenamedCertificates(cmdCtx *cmdctx.CmdContext, app *api.App) error {
+	certs, err := cmdCtx.Client.API().GetAppCertificates(app.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, cert := range certs {
+		updated, _, err := cmdCtx.Client.API().CheckAppCertificate(app.Name, cert.Hostname)
+		if err != nil {
+			return err
+		}
+
+		if updated.ClientStatus == "Ready" {
+			cmdCtx.Statusf("rename", cmdctx.SDETAIL, "Certificate for %s is ready\n", cert.Hostname)
+			continue
+		}
+
+		cmdCtx.Statusf("rename", cmdctx.SWARN, "Certificate for %s is %s, point its CNAME record at %s\n", cert.Hostname, updated.ClientStatus, app.Hostname)
+	}
+
+	return nil
+}
+
+func rewriteRenamedAppConfig(cmdCtx *cmdctx.CmdContext, appName string, newName string) error {
+	configPath, err := flyctl.ResolveConfigFileFromPath(cmdCtx.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	if !helpers.FileExists(configPath) {
+		return nil
+	}
+
+	appConfig, err := flyctl.LoadAppConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	if appConfig.AppName != appName {
+		return nil
+	}
+
+	if appConfig.Composed() {
+		cmdCtx.Statusf("rename", cmdctx.SWARN, "%s uses includes or environment variables, update its app name to %s by hand\n", helpers.PathRelativeToCWD(configPath), newName)
+		return nil
+	}
+
+	if !cmdCtx.Config.GetBool("yes") && !confirm(fmt.Sprintf("Update app name in %s", helpers.PathRelativeToCWD(configPath)), "yes") {
+		return nil
+	}
+
+	appConfig.AppName = newName
+
+	return writeAppConfig(configPath, appConfig)
+}
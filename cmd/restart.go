@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/docstrings"
@@ -12,15 +20,52 @@ import (
 
 //TODO: Move all output to status styled begin/done updates
 
+// rollingRestartInterval is how often a restarted VM is polled during a
+// rolling restart
+const rollingRestartInterval = 2 * time.Second
+
 func newRestartCommand(client *client.Client) *Command {
 	restartStrings := docstrings.Get("restart")
-	restartCmd := BuildCommandKS(nil, runRestart, restartStrings, client, requireSession, requireAppNameAsArg)
+	restartCmd := BuildCommandKS(nil, runRestart, restartStrings, client, requireSession, requireAppNameAsArgUnlessSelecting)
 	restartCmd.Args = cobra.RangeArgs(0, 1)
+	addRestartSelectFlag(restartCmd)
+	restartCmd.AddBoolFlag(BoolFlagOpts{Name: "rolling", Description: "Restart VMs one at a time, waiting for each to pass its health checks"})
+	restartCmd.AddStringFlag(StringFlagOpts{Name: "wait-timeout", Description: "How long to wait for each VM to become healthy during a rolling restart", Default: "5m"})
 
 	return restartCmd
 }
 
+// addRestartSelectFlag - adds --select, for restarting apps chosen from a list
+func addRestartSelectFlag(cmd *Command) {
+	cmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "select",
+		Description: "Choose the apps to restart from a list",
+	})
+}
+
+// requireAppNameAsArgUnlessSelecting - requireAppNameAsArg, except no app is
+// needed when apps are chosen with --select
+func requireAppNameAsArgUnlessSelecting(cmd *Command) Initializer {
+	init := requireAppNameAsArg(cmd)
+	preRun := init.PreRun
+	init.PreRun = func(ctx *cmdctx.CmdContext) error {
+		if ctx.Config.GetBool("select") {
+			return nil
+		}
+		return preRun(ctx)
+	}
+	return init
+}
+
 func runRestart(cmdctx *cmdctx.CmdContext) error {
+	if cmdctx.Config.GetBool("select") {
+		return runSelectedRestart(cmdctx)
+	}
+
+	if cmdctx.Config.GetBool("rolling") {
+		return runRollingRestart(cmdctx)
+	}
+
 	app, err := cmdctx.Client.API().RestartApp(cmdctx.AppName)
 	if err != nil {
 		return err
@@ -29,3 +74,143 @@ func runRestart(cmdctx *cmdctx.CmdContext) error {
 	fmt.Printf("%s is being restarted\n", app.Name)
 	return nil
 }
+
+// runSelectedRestart - restarts each of the apps chosen from a list of the
+// user's apps, carrying on past failures
+func runSelectedRestart(ctx *cmdctx.CmdContext) error {
+	if len(ctx.Args) > 0 {
+		return &ValidationError{fmt.Errorf("an app name can't be given with --select")}
+	}
+
+	apps, err := ctx.Client.API().GetApps(nil)
+	if err != nil {
+		return err
+	}
+	if len(apps) == 0 {
+		return fmt.Errorf("you have no apps to restart")
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	options := make([]string, 0, len(apps))
+	for _, app := range apps {
+		options = append(options, fmt.Sprintf("%s (%s)", app.Name, app.Organization.Slug))
+	}
+
+	selected, err := prompt.MultiSelect("Select the apps to restart:", options, nil, "")
+	if prompt.IsNonInteractiveError(err) {
+		return &ValidationError{fmt.Errorf("--select can't be used when running non-interactively, give an app name instead")}
+	} else if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		ctx.Statusf("restart", cmdctx.SINFO, "No apps selected\n")
+		return nil
+	}
+
+	failed := []string{}
+	for _, i := range selected {
+		ctx.AppName = apps[i].Name
+
+		if ctx.Config.GetBool("rolling") {
+			err = runRollingRestart(ctx)
+		} else {
+			_, err = ctx.Client.API().RestartApp(ctx.AppName)
+		}
+
+		if err != nil {
+			ctx.Statusf("restart", cmdctx.SERROR, "Failed to restart %s: %s\n", ctx.AppName, err)
+			failed = append(failed, ctx.AppName)
+			continue
+		}
+		ctx.Statusf("restart", cmdctx.SDONE, "%s is being restarted\n", ctx.AppName)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restart %d of %d apps: %s", len(failed), len(selected), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func runRollingRestart(ctx *cmdctx.CmdContext) error {
+	timeout, err := helpers.ParseDuration(ctx.Config.GetString("wait-timeout"))
+	if err != nil {
+		return &ValidationError{errors.Wrap(err, "invalid wait timeout")}
+	}
+
+	status, err := ctx.Client.API().GetAppStatus(ctx.AppName, false)
+	if err != nil {
+		return err
+	}
+
+	allocs := []*api.AllocationStatus{}
+	for _, alloc := range status.Allocations {
+		if alloc.Status == "running" {
+			allocs = append(allocs, alloc)
+		}
+	}
+	if len(allocs) == 0 {
+		return fmt.Errorf("%s has no running VMs to restart", ctx.AppName)
+	}
+
+	cancelCtx := createCancellableContext()
+
+	ctx.Statusf("restart", cmdctx.STITLE, "Restarting %d VM(s) of %s one at a time\n", len(allocs), ctx.AppName)
+
+	for i, alloc := range allocs {
+		ctx.Statusf("restart", cmdctx.SBEGIN, "[%d/%d] Restarting VM %s in %s\n", i+1, len(allocs), alloc.IDShort, alloc.Region)
+
+		// the restart count is read with the same query that's polled below, so
+		// the two compare
+		before, err := ctx.Client.API().GetAllocationStatus(ctx.AppName, alloc.ID, 0)
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Client.API().RestartAllocation(ctx.AppName, alloc.ID); err != nil {
+			return errors.Wrapf(err, "could not restart VM %s", alloc.IDShort)
+		}
+
+		if err := waitForRestartedAlloc(cancelCtx, ctx, alloc, before.Restarts, timeout); err != nil {
+			ctx.Statusf("restart", cmdctx.SERROR, "Stopped rolling restart, %d of %d VM(s) restarted\n", i, len(allocs))
+			return err
+		}
+
+		ctx.Statusf("restart", cmdctx.SDONE, "[%d/%d] VM %s is healthy\n", i+1, len(allocs), alloc.IDShort)
+	}
+
+	ctx.Statusf("restart", cmdctx.SDONE, "Restarted %d VM(s) of %s\n", len(allocs), ctx.AppName)
+
+	return nil
+}
+
+// waitForRestartedAlloc waits for alloc's restart count to go past restarts
+// and for it to be running with all of its health checks passing
+func waitForRestartedAlloc(parent context.Context, ctx *cmdctx.CmdContext, alloc *api.AllocationStatus, restarts int, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-time.After(rollingRestartInterval):
+		case <-waitCtx.Done():
+			if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s waiting for VM %s to become healthy", timeout, alloc.IDShort)
+			}
+			return waitCtx.Err()
+		}
+
+		current, err := ctx.Client.API().GetAllocationStatus(ctx.AppName, alloc.ID, 0)
+		if err != nil {
+			return err
+		}
+
+		if current.Failed || current.Status == "failed" {
+			return fmt.Errorf("VM %s failed after restarting", alloc.IDShort)
+		}
+
+		if current.Restarts > restarts && current.Status == "running" && current.Healthy && current.CriticalCheckCount == 0 {
+			return nil
+		}
+	}
+}
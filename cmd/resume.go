@@ -2,12 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
+	"github.com/superfly/flyctl/terminal"
 
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/docstrings"
@@ -25,24 +25,49 @@ func newResumeCommand(client *client.Client) *Command {
 }
 
 func runResume(cmdctx *cmdctx.CmdContext) error {
+	metadata, err := cmdctx.Client.API().GetAppMetadata(cmdctx.AppName)
+	if err != nil {
+		terminal.Warnf("Failed to read the instance counts from before the suspend, resuming with one instance: %v\n", err)
+	}
+
 	app, err := cmdctx.Client.API().ResumeApp(cmdctx.AppName)
 	if err != nil {
 		return err
 	}
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Writer = os.Stderr
-	s.Prefix = fmt.Sprintf("Resuming %s with 1 instance to start ", cmdctx.AppName)
+	// apps resume with one instance, bring back the counts from before the
+	// suspend when they were recorded
+	want := 1
+	if counts := parseGroupCounts(metadata[suspendedCountsMetadataKey]); len(counts) > 0 {
+		if _, _, err := cmdctx.Client.API().SetAppGroupVMCounts(cmdctx.AppName, counts); err != nil {
+			return err
+		}
+		want = 0
+		for _, c := range counts {
+			want += c.Count
+		}
+		if _, err := cmdctx.Client.API().SetAppMetadata(cmdctx.AppName, map[string]string{suspendedCountsMetadataKey: ""}); err != nil {
+			return err
+		}
+	}
+
+	plural := ""
+	if want > 1 {
+		plural = "s"
+	}
+
+	s := iostreams.NewSpinner(fmt.Sprintf("Resuming %s with %d instance%s to start ", cmdctx.AppName, want, plural))
 	s.Start()
 
 	for app.Status != "running" {
+		time.Sleep(suspendPollInterval)
 		app, err = cmdctx.Client.API().GetApp(cmdctx.AppName)
 		if err != nil {
 			return err
 		}
 	}
 
-	s.FinalMSG = fmt.Sprintf("Resume complete - %s is now %s with 1 running instance\n", cmdctx.AppName, app.Status)
+	s.FinalMSG = fmt.Sprintf("Resume complete - %s is now %s with %d instance%s\n", cmdctx.AppName, app.Status, want, plural)
 	s.Stop()
 
 	return nil
@@ -36,14 +36,50 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 	err := viper.BindPFlag(flyctl.ConfigAPIToken, rootCmd.PersistentFlags().Lookup("access-token"))
 	checkErr(err)
 
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output, including API timings")
 	err = viper.BindPFlag(flyctl.ConfigVerboseOutput, rootCmd.PersistentFlags().Lookup("verbose"))
 	checkErr(err)
 
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only show errors and requested data")
+	err = viper.BindPFlag(flyctl.ConfigQuietOutput, rootCmd.PersistentFlags().Lookup("quiet"))
+	checkErr(err)
+
 	rootCmd.PersistentFlags().BoolP("json", "j", false, "json output")
 	err = viper.BindPFlag(flyctl.ConfigJSONOutput, rootCmd.PersistentFlags().Lookup("json"))
 	checkErr(err)
 
+	rootCmd.PersistentFlags().Bool("yaml", false, "yaml output")
+	err = viper.BindPFlag(flyctl.ConfigYAMLOutput, rootCmd.PersistentFlags().Lookup("yaml"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().String("format", "", "Format output using a Go template, e.g. '{{.Name}}', or 'prometheus' for metrics where supported")
+	err = viper.BindPFlag(flyctl.ConfigOutputFormat, rootCmd.PersistentFlags().Lookup("format"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("ascii", false, "Plain ASCII output without colors or unicode glyphs, also enabled by FLY_ASCII=1")
+	err = viper.BindPFlag(flyctl.ConfigASCIIOutput, rootCmd.PersistentFlags().Lookup("ascii"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("no-update-check", false, "Don't check for or announce flyctl updates")
+	err = viper.BindPFlag(flyctl.ConfigNoUpdateCheck, rootCmd.PersistentFlags().Lookup("no-update-check"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("non-interactive", false, "Never prompt, fail instead when input is needed")
+	err = viper.BindPFlag(flyctl.ConfigNonInteractive, rootCmd.PersistentFlags().Lookup("non-interactive"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("debug-http", false, "Trace every API request and response to stderr, also enabled by FLY_DEBUG=http")
+	err = viper.BindPFlag(flyctl.ConfigDebugHTTP, rootCmd.PersistentFlags().Lookup("debug-http"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("debug-http-bodies", false, "Include request and response headers and bodies, with secrets redacted, in the API trace")
+	err = viper.BindPFlag(flyctl.ConfigDebugHTTPBodies, rootCmd.PersistentFlags().Lookup("debug-http-bodies"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().String("debug-http-file", "", "Append the API trace to this file instead of stderr")
+	err = viper.BindPFlag(flyctl.ConfigDebugHTTPFile, rootCmd.PersistentFlags().Lookup("debug-http-file"))
+	checkErr(err)
+
 	rootCmd.PersistentFlags().String("builtinsfile", "", "Load builtins from named file")
 	err = viper.BindPFlag(flyctl.ConfigBuiltinsfile, rootCmd.PersistentFlags().Lookup("builtinsfile"))
 	checkErr(err)
@@ -51,41 +87,58 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 	err = rootCmd.PersistentFlags().MarkHidden("builtinsfile")
 	checkErr(err)
 
+	rootCmd.PersistentFlags().Bool("show-deprecated", false, "Include deprecated commands and flags in help")
+
 	rootCmd.AddCommand(
 		newAppsCommand(client),
 		newAuthCommand(client),
 		newBuildsCommand(client),
+		newCacheCommand(client),
 		newCurlCommand(client),
 		newCertificatesCommand(client),
+		newCompletionCommand(client),
 		newConfigCommand(client),
+		newConsulCommand(client),
+		newContextCommand(client),
 		newDashboardCommand(client),
 		newDeployCommand(client),
 		newDestroyCommand(client),
 		newDocsCommand(client),
+		newEventsCommand(client),
+		newExtensionsCommand(client),
 		newHistoryCommand(client),
 		newInfoCommand(client),
 		newInitCommand(client),
 		newIPAddressesCommand(client),
 		newListCommand(client),
 		newLogsCommand(client),
+		newMachinesCommand(client),
+		newMetricsCommand(client),
 		newMonitorCommand(client),
 		newMoveCommand(client),
+		newNetworksCommand(client),
 		newOpenCommand(client),
+		newPingCommand(client),
 		newPlatformCommand(client),
 		newRegionsCommand(client),
+		newRedisCommand(client),
 		newReleasesCommand(client),
 		newRestartCommand(client),
 		newResumeCommand(client),
 		newScaleCommand(client),
 		newAutoscaleCommand(client),
 		newSecretsCommand(client),
+		newSourcecodeCommand(client),
 		newStatusCommand(client),
+		newStorageCommand(client),
 		newSuspendCommand(client),
+		newTokensCommand(client),
 		newVersionCommand(client),
 		newDNSCommand(client),
 		newDomainsCommand(client),
 		newOrgsCommand(client),
 		newVolumesCommand(client),
+		newWebhooksCommand(client),
 		newWireGuardCommand(client),
 		newSSHCommand(client),
 		newAgentCommand(client),
@@ -95,6 +148,15 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 		newLaunchCommand(client),
 	)
 
+	rootCmd.SetHelpCommand(newHelpCommand(client).Command)
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &ValidationError{err}
+	})
+	markValidationErrors(rootCmd.Command)
+	applyDeprecations(rootCmd.Command)
+	registerCompletions(rootCmd.Command, client)
+
 	return rootCmd.Command
 }
 
@@ -107,7 +169,7 @@ func checkErr(err error) {
 		fmt.Println(aurora.Red("Error"), err)
 	}
 
-	safeExit()
+	safeExit(ExitCode(err))
 }
 
 func isCancelledError(err error) bool {
@@ -128,8 +190,8 @@ func isCancelledError(err error) bool {
 	return false
 }
 
-func safeExit() {
+func safeExit(code int) {
 	flyctl.BackgroundTaskWG.Wait()
 
-	os.Exit(1)
+	os.Exit(code)
 }
@@ -1,15 +1,19 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
 
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyname"
+	"github.com/superfly/flyctl/helpers"
 
 	"github.com/spf13/cobra"
 )
@@ -27,6 +31,7 @@ func newScaleCommand(client *client.Client) *Command {
 		Description: "Memory in MB for the VM",
 		Default:     0,
 	})
+	addGPUFlags(vmCmd)
 
 	memoryCmdStrings := docstrings.Get("scale.memory")
 	memoryCmd := BuildCommandKS(cmd, runScaleMemory, memoryCmdStrings, client, requireSession, requireAppName)
@@ -34,13 +39,27 @@ func newScaleCommand(client *client.Client) *Command {
 
 	countCmdStrings := docstrings.Get("scale.count")
 	countCmd := BuildCommand(cmd, runScaleCount, countCmdStrings.Usage, countCmdStrings.Short, countCmdStrings.Long, client, requireSession, requireAppName)
-	countCmd.Args = cobra.ExactArgs(1)
+	countCmd.Args = cobra.MinimumNArgs(1)
 	countCmd.AddIntFlag((IntFlagOpts{
 		Name:        "max-per-region",
 		Description: "Max number of VMs per region",
 		Default:     -1,
 	}))
 
+	autostopCmdStrings := docstrings.Get("scale.autostop")
+	autostopCmd := BuildCommandKS(cmd, runScaleAutostop, autostopCmdStrings, client, requireSession, requireAppName)
+	autostopCmd.Args = cobra.MaximumNArgs(1)
+	autostopCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "autostart",
+		Description: "Start stopped VMs when a request arrives",
+		Default:     true,
+	})
+	autostopCmd.AddIntFlag(IntFlagOpts{
+		Name:        "min-running",
+		Description: "Number of VMs to keep running when idle",
+		Default:     0,
+	})
+
 	showCmdStrings := docstrings.Get("scale.show")
 	BuildCommand(cmd, runScaleShow, showCmdStrings.Usage, showCmdStrings.Short, showCmdStrings.Long, client, requireSession, requireAppName)
 
@@ -50,9 +69,52 @@ func newScaleCommand(client *client.Client) *Command {
 func runScaleVM(commandContext *cmdctx.CmdContext) error {
 	sizeName := commandContext.Args[0]
 
+	vmSizes, err := commandContext.Client.API().PlatformVMSizes()
+	if err != nil {
+		return err
+	}
+	if _, err := findVMSize(vmSizes, sizeName); err != nil {
+		return err
+	}
+
 	memoryMB := int64(commandContext.Config.GetInt("memory"))
 
-	size, err := commandContext.Client.API().SetAppVMSize(commandContext.AppName, sizeName, memoryMB)
+	input := api.SetVMSizeInput{AppID: commandContext.AppName, SizeName: sizeName, MemoryMb: memoryMB}
+
+	gpus, err := gpuRequestFromFlags(commandContext)
+	if err != nil {
+		return err
+	}
+	if gpus != nil {
+		regions, backupRegions, err := commandContext.Client.API().ListAppRegions(commandContext.AppName)
+		if err != nil {
+			return err
+		}
+		codes := []string{}
+		for _, region := range append(regions, backupRegions...) {
+			codes = append(codes, region.Code)
+		}
+		if err := checkGPURegions(commandContext, gpus, codes); err != nil {
+			return err
+		}
+
+		_, tgCounts, err := commandContext.Client.API().AppVMResources(commandContext.AppName)
+		if err != nil {
+			return err
+		}
+		var vmCount int
+		for _, tg := range tgCounts {
+			vmCount += tg.Count
+		}
+		if !confirmGPUPricing(commandContext, gpus, vmCount) {
+			return nil
+		}
+
+		input.GPUKind = gpus.Kind.Name
+		input.GPUs = gpus.Count
+	}
+
+	size, err := commandContext.Client.API().SetAppVMSizeWithInput(input)
 	if err != nil {
 		return err
 	}
@@ -60,15 +122,13 @@ func runScaleVM(commandContext *cmdctx.CmdContext) error {
 	fmt.Println("Scaled VM Type to", size.Name)
 	fmt.Printf("%15s: %s\n", "CPU Cores", formatCores(size))
 	fmt.Printf("%15s: %s\n", "Memory", formatMemory(size))
+	if size.GPUs > 0 {
+		fmt.Printf("%15s: %s\n", "GPUs", presenters.FormatGPUs(size.GPUKind, size.GPUs))
+	}
 	return nil
 }
 
 func runScaleCount(commandContext *cmdctx.CmdContext) error {
-	count, err := strconv.Atoi(commandContext.Args[0])
-	if err != nil {
-		return err
-	}
-
 	// THIS IS AN OPTION TYPE CAN YOU TELL?
 	maxPerRegionRaw := commandContext.Config.GetInt("max-per-region")
 	maxPerRegion := &maxPerRegionRaw
@@ -77,6 +137,15 @@ func runScaleCount(commandContext *cmdctx.CmdContext) error {
 		maxPerRegion = nil
 	}
 
+	if len(commandContext.Args) > 1 || strings.Contains(commandContext.Args[0], "=") {
+		return runScaleGroupCounts(commandContext, maxPerRegion)
+	}
+
+	count, err := strconv.Atoi(commandContext.Args[0])
+	if err != nil {
+		return err
+	}
+
 	counts, warnings, err := commandContext.Client.API().SetAppVMCount(commandContext.AppName, count, maxPerRegion)
 	if err != nil {
 		return err
@@ -102,6 +171,41 @@ func runScaleCount(commandContext *cmdctx.CmdContext) error {
 	return nil
 }
 
+// runScaleGroupCounts - sets the counts of process groups given as
+// GROUP=COUNT arguments
+func runScaleGroupCounts(commandContext *cmdctx.CmdContext, maxPerRegion *int) error {
+	input := []api.VMCountInput{}
+	for _, arg := range commandContext.Args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return &ValidationError{fmt.Errorf("%q must be in the form GROUP=COUNT", arg)}
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil || count < 0 {
+			return &ValidationError{fmt.Errorf("invalid count for %s: %q", parts[0], parts[1])}
+		}
+		input = append(input, api.VMCountInput{Group: parts[0], Count: count, MaxPerRegion: maxPerRegion})
+	}
+
+	counts, warnings, err := commandContext.Client.API().SetAppGroupVMCounts(commandContext.AppName, input)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		commandContext.Status("scale", cmdctx.SWARN, "Warning:", warning)
+	}
+
+	pairs := []string{}
+	for _, tg := range counts {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", tg.Name, tg.Count))
+	}
+
+	commandContext.Statusf("scale", cmdctx.SDONE, "Count changed to %s\n", strings.Join(pairs, " "))
+
+	return nil
+}
+
 func runScaleShow(commandContext *cmdctx.CmdContext) error {
 	size, tgCounts, err := commandContext.Client.API().AppVMResources(commandContext.AppName)
 	if err != nil {
@@ -116,13 +220,11 @@ func runScaleShow(commandContext *cmdctx.CmdContext) error {
 		}
 	}
 
-	printVMResources(commandContext, size, appCount)
-
-	return nil
+	return printVMResources(commandContext, size, appCount)
 }
 
-func printVMResources(commandContext *cmdctx.CmdContext, vmSize api.VMSize, count int) {
-	if commandContext.OutputJSON() {
+func printVMResources(commandContext *cmdctx.CmdContext, vmSize api.VMSize, count int) error {
+	if commandContext.OutputStructured() {
 		out := struct {
 			api.VMSize
 			Count int
@@ -131,16 +233,72 @@ func printVMResources(commandContext *cmdctx.CmdContext, vmSize api.VMSize, coun
 			Count:  count,
 		}
 
-		prettyJSON, _ := json.MarshalIndent(out, "", "    ")
-		fmt.Fprintln(commandContext.Out, string(prettyJSON))
-		return
+		return commandContext.WriteData(out)
 	}
 
 	fmt.Printf("VM Resources for %s\n", commandContext.AppName)
 
 	fmt.Fprintf(commandContext.Out, "%15s: %s\n", "VM Size", vmSize.Name)
 	fmt.Fprintf(commandContext.Out, "%15s: %s\n", "VM Memory", formatMemory(vmSize))
+	if vmSize.GPUs > 0 {
+		fmt.Fprintf(commandContext.Out, "%15s: %s\n", "VM GPUs", presenters.FormatGPUs(vmSize.GPUKind, vmSize.GPUs))
+	}
 	fmt.Fprintf(commandContext.Out, "%15s: %d\n", "Count", count)
+
+	return nil
+}
+
+func runScaleAutostop(commandContext *cmdctx.CmdContext) error {
+	if len(commandContext.Args) == 0 {
+		cfg, err := commandContext.Client.API().AppAutostopConfig(commandContext.AppName)
+		if err != nil {
+			return err
+		}
+
+		if commandContext.OutputStructured() {
+			return commandContext.WriteData(cfg)
+		}
+
+		fmt.Fprintf(commandContext.Out, "Autostop for %s: %s\n", commandContext.AppName, presenters.FormatAutostop(cfg))
+
+		return nil
+	}
+
+	var autoStop bool
+	switch strings.ToLower(commandContext.Args[0]) {
+	case "on":
+		autoStop = true
+	case "off":
+	default:
+		return &ValidationError{fmt.Errorf("expected on or off, got %q", commandContext.Args[0])}
+	}
+
+	autoStart := commandContext.Config.GetBool("autostart")
+	minRunning := commandContext.Config.GetInt("min-running")
+	if minRunning < 0 {
+		return &ValidationError{errors.New("min-running can't be negative")}
+	}
+
+	// autostop is set per service in fly.toml and applied by the next deploy
+	appConfig := commandContext.AppConfig
+	configPath := helpers.PathRelativeToCWD(commandContext.ConfigFile)
+	if appConfig == nil || appConfig.AppName != commandContext.AppName {
+		return &ValidationError{fmt.Errorf("autostop is set on the services in fly.toml, run this in %s's directory", commandContext.AppName)}
+	}
+	if appConfig.Composed() {
+		return &ValidationError{fmt.Errorf("%s uses includes or environment variables, set auto_stop_machines, auto_start_machines and min_machines_running by hand", configPath)}
+	}
+	if appConfig.SetAutostop(autoStop, autoStart, minRunning) == 0 {
+		return &ValidationError{fmt.Errorf("%s has no services to stop and start", configPath)}
+	}
+
+	if err := writeAppConfig(commandContext.ConfigFile, appConfig); err != nil {
+		return err
+	}
+
+	commandContext.Statusf("scale", cmdctx.SINFO, "Run '%s deploy' to apply the new settings\n", flyname.Name())
+
+	return nil
 }
 
 func runScaleMemory(commandContext *cmdctx.CmdContext) error {
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
 	"github.com/superfly/flyctl/internal/cmdutil"
+	"github.com/superfly/flyctl/internal/prompt"
 
 	"github.com/superfly/flyctl/docstrings"
 
@@ -49,13 +52,24 @@ func newSecretsCommand(client *client.Client) *Command {
 
 	secretsUnsetStrings := docstrings.Get("secrets.unset")
 	unset := BuildCommandKS(cmd, runSecretsUnset, secretsUnsetStrings, client, requireSession, requireAppName)
-	unset.Command.Args = cobra.MinimumNArgs(1)
+	unset.Command.Args = cobra.ArbitraryArgs
+	unset.ValidArgsFunction = completeSecretNames(client)
 
 	unset.AddBoolFlag(BoolFlagOpts{
 		Name:        "detach",
 		Description: "Return immediately instead of monitoring deployment progress",
 	})
 
+	secretsEditStrings := docstrings.Get("secrets.edit")
+	edit := BuildCommandKS(cmd, runSecretsEdit, secretsEditStrings, client, requireSession, requireAppName)
+	edit.AddBoolFlag(BoolFlagOpts{
+		Name:        "detach",
+		Description: "Return immediately instead of monitoring deployment progress",
+	})
+
+	secretsHistoryStrings := docstrings.Get("secrets.history")
+	BuildCommandKS(cmd, runSecretsHistory, secretsHistoryStrings, client, requireSession, requireAppName)
+
 	return cmd
 }
 
@@ -68,6 +82,34 @@ func runListSecrets(ctx *cmdctx.CmdContext) error {
 	return ctx.Render(&presenters.Secrets{Secrets: secrets})
 }
 
+func runSecretsHistory(ctx *cmdctx.CmdContext) error {
+	changes, err := ctx.Client.API().GetAppSecretChanges(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	if len(ctx.Args) > 0 {
+		names := map[string]bool{}
+		for _, name := range ctx.Args {
+			names[name] = true
+		}
+
+		filtered := []api.SecretChange{}
+		for _, change := range changes {
+			if names[change.Name] {
+				filtered = append(filtered, change)
+			}
+		}
+		changes = filtered
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].CreatedAt.After(changes[j].CreatedAt)
+	})
+
+	return ctx.Frender(cmdctx.PresenterOption{Presentable: &presenters.SecretChanges{Changes: changes}})
+}
+
 func runSetSecrets(cc *cmdctx.CmdContext) error {
 	ctx := createCancellableContext()
 
@@ -121,57 +163,15 @@ func runImportSecrets(cc *cmdctx.CmdContext) error {
 		return err
 	}
 
-	secrets := make(map[string]string)
-
 	secretsString, err := ioutil.ReadAll(os.Stdin)
 
 	if err != nil {
 		return err
 	}
 
-	secretsArray := strings.Split(string(secretsString), "\n")
-
-	parsestate := 0
-	parsedkey := ""
-	var parsebuffer strings.Builder
-
-	for _, line := range secretsArray {
-		switch parsestate {
-		case 0:
-			if line != "" {
-				parts := strings.SplitN(line, "=", 2)
-				if strings.HasPrefix(parts[1], "\"\"\"") {
-					// Switch to multiline
-					parsestate = 1
-					parsedkey = parts[0]
-					parsebuffer.WriteString(strings.TrimPrefix(parts[1], "\"\"\""))
-					parsebuffer.WriteString("\n")
-				} else {
-					if len(parts) != 2 {
-						return fmt.Errorf("Secrets must be provided as NAME=VALUE pairs (%s is invalid)", line)
-					}
-					key := parts[0]
-					value := parts[1]
-					secrets[key] = value
-				}
-			}
-		case 1:
-			if strings.HasSuffix(line, "\"\"\"") {
-				// End of multiline
-				parsebuffer.WriteString(strings.TrimSuffix(line, "\"\"\""))
-				secrets[parsedkey] = parsebuffer.String()
-				parsebuffer.Reset()
-				parsestate = 0
-				parsedkey = ""
-			} else {
-				if line != "" {
-					parsebuffer.WriteString(line)
-				}
-				parsebuffer.WriteString("\n")
-			}
-
-		}
-
+	secrets, err := cmdutil.ParseDotenv(string(secretsString))
+	if err != nil {
+		return err
 	}
 
 	if len(secrets) < 1 {
@@ -202,11 +202,121 @@ func runSecretsUnset(cc *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if len(cc.Args) == 0 {
-		return errors.New("Requires at least one secret name")
+	names := cc.Args
+	if len(names) == 0 {
+		if names, err = selectSecretsToUnset(cc); err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			cc.Status("secrets", cmdctx.SINFO, "No secrets selected")
+			return nil
+		}
+	}
+
+	release, err := cc.Client.API().UnsetSecrets(cc.AppName, names)
+	if err != nil {
+		return err
+	}
+
+	if !app.Deployed {
+		cc.Statusf("secrets", cmdctx.SINFO, "Secrets are staged for the first deployment\n")
+		return nil
+	}
+
+	cc.Statusf("secrets", cmdctx.SINFO, "Release v%d created\n", release.Version)
+
+	return watchDeployment(ctx, cc)
+}
+
+// selectSecretsToUnset - asks which of the app's secrets to remove
+func selectSecretsToUnset(cc *cmdctx.CmdContext) ([]string, error) {
+	secrets, err := cc.Client.API().GetAppSecrets(cc.AppName)
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("%s has no secrets", cc.AppName)
+	}
+
+	names := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		names = append(names, secret.Name)
+	}
+	sort.Strings(names)
+
+	selected, err := prompt.MultiSelect("Select the secrets to unset:", names, nil, "")
+	if prompt.IsNonInteractiveError(err) {
+		return nil, errors.New("Requires at least one secret name")
+	} else if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(selected))
+	for _, i := range selected {
+		out = append(out, names[i])
+	}
+	return out, nil
+}
+
+const secretsEditHeader = `# Secrets for %s. Values aren't shown, leave NAME= as it is to keep a
+# secret's value. Give a NAME=VALUE to change or add a secret and delete a
+# line to remove one. Wrap values that span lines in """. Lines starting
+# with # are ignored, and saving the file unchanged makes no changes.
+`
+
+func runSecretsEdit(cc *cmdctx.CmdContext) error {
+	ctx := createCancellableContext()
+
+	app, err := cc.Client.API().GetApp(cc.AppName)
+	if err != nil {
+		return err
+	}
+
+	current, err := cc.Client.API().GetAppSecrets(cc.AppName)
+	if err != nil {
+		return err
 	}
 
-	release, err := cc.Client.API().UnsetSecrets(cc.AppName, cc.Args)
+	var contents strings.Builder
+	fmt.Fprintf(&contents, secretsEditHeader, cc.AppName)
+	existing := make(map[string]bool, len(current))
+	for _, secret := range current {
+		existing[secret.Name] = true
+		fmt.Fprintf(&contents, "%s=\n", secret.Name)
+	}
+
+	edited, err := prompt.Edit(cc.AppName+"-secrets.env", contents.String(), "")
+	if err != nil {
+		return err
+	}
+
+	secrets, err := cmdutil.ParseDotenv(edited)
+	if err != nil {
+		return &ValidationError{err}
+	}
+
+	set, unset, err := secretsChanges(existing, secrets)
+	if err != nil {
+		return &ValidationError{err}
+	}
+
+	if len(set) == 0 && len(unset) == 0 {
+		cc.Status("secrets", cmdctx.SINFO, "No changes to secrets")
+		return nil
+	}
+
+	for _, name := range sortedKeys(set) {
+		if existing[name] {
+			cc.Statusf("secrets", cmdctx.SINFO, "  ~ %s\n", name)
+		} else {
+			cc.Statusf("secrets", cmdctx.SINFO, "  + %s\n", name)
+		}
+	}
+	for _, name := range unset {
+		cc.Statusf("secrets", cmdctx.SINFO, "  - %s\n", name)
+	}
+
+	release, err := cc.Client.API().UpdateSecrets(cc.AppName, set, unset)
 	if err != nil {
 		return err
 	}
@@ -218,5 +328,68 @@ func runSecretsUnset(cc *cmdctx.CmdContext) error {
 
 	cc.Statusf("secrets", cmdctx.SINFO, "Release v%d created\n", release.Version)
 
+	if cc.Config.GetBool("detach") {
+		return nil
+	}
+
 	return watchDeployment(ctx, cc)
 }
+
+// secretsChanges works out the secrets to set and unset to get from the
+// existing secrets to the edited ones, where an empty value keeps the
+// existing secret's value
+func secretsChanges(existing map[string]bool, edited map[string]string) (map[string]string, []string, error) {
+	set := map[string]string{}
+	for name, value := range edited {
+		if value != "" {
+			set[name] = value
+			continue
+		}
+		if !existing[name] {
+			return nil, nil, fmt.Errorf("new secret %s needs a value", name)
+		}
+	}
+
+	unset := []string{}
+	for name := range existing {
+		if _, ok := edited[name]; !ok {
+			unset = append(unset, name)
+		}
+	}
+	sort.Strings(unset)
+
+	return set, unset, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// confirmSecretsReplaced - asks before secrets that a command is about to
+// set replace ones the app already has, unless --yes was given. It's false
+// when the user declines.
+func confirmSecretsReplaced(ctx *cmdctx.CmdContext, appName string, names []string) (bool, error) {
+	existing, err := ctx.Client.API().GetAppSecrets(appName)
+	if err != nil {
+		return false, err
+	}
+
+	replaced := []string{}
+	for _, name := range names {
+		for _, secret := range existing {
+			if secret.Name == name {
+				replaced = append(replaced, name)
+			}
+		}
+	}
+	if len(replaced) == 0 || ctx.Config.GetBool("yes") {
+		return true, nil
+	}
+
+	return confirm(fmt.Sprintf("%s already has %s set, replace them?", appName, strings.Join(replaced, ", ")), "yes"), nil
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/sourcecode"
+)
+
+func newSourcecodeCommand(client *client.Client) *Command {
+	sourcecodeStrings := docstrings.Get("sourcecode")
+	cmd := BuildCommandKS(nil, nil, sourcecodeStrings, client)
+
+	scanStrings := docstrings.Get("sourcecode.scan")
+	scanCmd := BuildCommandKS(cmd, runSourcecodeScan, scanStrings, client)
+	scanCmd.Args = cobra.MaximumNArgs(1)
+	scanCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "report",
+		Description: "Show everything that was detected and what launch would do with it",
+	})
+
+	return cmd
+}
+
+func runSourcecodeScan(ctx *cmdctx.CmdContext) error {
+	dir := "."
+	if len(ctx.Args) > 0 {
+		dir = ctx.Args[0]
+	}
+	if absDir, err := filepath.Abs(dir); err == nil {
+		dir = absDir
+	}
+	if !helpers.DirectoryExists(dir) {
+		return &ValidationError{fmt.Errorf("%s isn't a directory", dir)}
+	}
+
+	si, err := sourcecode.Scan(dir)
+	if err != nil {
+		return err
+	}
+	report := sourcecode.NewReport(dir, si)
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(report)
+	}
+
+	if !report.Detected {
+		ctx.Statusf("sourcecode", cmdctx.SWARN, "Could not find a Dockerfile or detect a buildpack in %s, launch would create a blank app\n", helpers.PathRelativeToCWD(dir))
+		return nil
+	}
+
+	ctx.Statusf("sourcecode", cmdctx.SINFO, "Detected %s app in %s\n", report.Family, helpers.PathRelativeToCWD(dir))
+	if !ctx.Config.GetBool("report") {
+		return nil
+	}
+
+	ctx.StatusLn()
+	row := func(name string, value string) {
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(ctx.Out, "%-16s %s\n", name, value)
+	}
+	if report.DockerfilePath != "" {
+		row("Dockerfile", helpers.PathRelativeToCWD(report.DockerfilePath))
+	} else {
+		row("Builder", report.Builder)
+		row("Buildpacks", strings.Join(report.Buildpacks, " "))
+	}
+	port := ""
+	if report.Port > 0 {
+		port = fmt.Sprint(report.Port)
+	}
+	row("Port", port)
+	row("Release Command", report.ReleaseCommand)
+	if report.LiteFS {
+		row("LiteFS", "detected, launch offers to attach Consul for its leases")
+	}
+	row("Generated Files", strings.Join(report.Files, ", "))
+
+	if len(report.Processes) > 0 {
+		ctx.StatusLn()
+		table := helpers.MakeSimpleTable(ctx.Out, []string{"Process", "Command"})
+		for _, p := range report.Processes {
+			table.Append([]string{p.Name, p.Command})
+		}
+		table.Render()
+	}
+
+	if len(report.Secrets) > 0 {
+		ctx.StatusLn()
+		table := helpers.MakeSimpleTable(ctx.Out, []string{"Secret", "Description"})
+		for _, s := range report.Secrets {
+			table.Append([]string{s.Name, s.Description})
+		}
+		table.Render()
+	}
+
+	return nil
+}
@@ -70,6 +70,32 @@ func newSSHCommand(client *client.Client) *Command {
 		Description: "Region to create WireGuard connection in",
 	})
 
+	broadcast := BuildCommandKS(cmd,
+		runSSHBroadcast,
+		docstrings.Get("ssh.broadcast"),
+		client,
+		requireSession,
+		requireAppName)
+	broadcast.Args = cobra.MinimumNArgs(1)
+
+	broadcast.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "Only run on instances in this region",
+	})
+
+	broadcast.AddStringFlag(StringFlagOpts{
+		Name:        "process-group",
+		Shorthand:   "g",
+		Description: "Only run on instances of this process group",
+	})
+
+	broadcast.AddStringFlag(StringFlagOpts{
+		Name:        "timeout",
+		Default:     "5m",
+		Description: "How long to let the command run",
+	})
+
 	issue := child(cmd, runSSHIssue, "ssh.issue")
 	issue.Args = cobra.MaximumNArgs(3)
 
@@ -121,9 +147,8 @@ func runSSHLog(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(certs)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(certs)
 	}
 
 	table := tablewriter.NewWriter(ctx.Out)
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/pkg/ssh"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// broadcastResult is the outcome of running the command on one instance
+type broadcastResult struct {
+	ID         string `json:"id"`
+	Region     string `json:"region"`
+	ExitCode   int    `json:"exitCode"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+func runSSHBroadcast(ctx *cmdctx.CmdContext) error {
+	client := ctx.Client.API()
+	command := strings.Join(ctx.Args, " ")
+
+	timeout, err := helpers.ParseDuration(ctx.Config.GetString("timeout"))
+	if err != nil {
+		return &ValidationError{fmt.Errorf("invalid timeout: %w", err)}
+	}
+
+	app, err := client.GetApp(ctx.AppName)
+	if err != nil {
+		return fmt.Errorf("get app: %w", err)
+	}
+
+	status, err := client.GetAppStatus(ctx.AppName, false)
+	if err != nil {
+		return err
+	}
+
+	allocs := broadcastTargets(status.Allocations, ctx.Config.GetString("region"), ctx.Config.GetString("process-group"))
+	if len(allocs) == 0 {
+		return fmt.Errorf("no running instances of %s match", ctx.AppName)
+	}
+
+	agentclient, err := establishAgent(ctx)
+	if err != nil {
+		return fmt.Errorf("can't establish agent: %s", err)
+	}
+
+	dialer, err := agentclient.Dialer(&app.Organization)
+	if err != nil {
+		return fmt.Errorf("ssh: can't build tunnel for %s: %s", app.Organization.Slug, err)
+	}
+
+	cert, err := singleUseSSHCertificate(ctx, &app.Organization)
+	if err != nil {
+		return fmt.Errorf("create ssh certificate: %w (if you haven't created a key for your org yet, try `flyctl ssh establish`)", err)
+	}
+
+	pk, err := parsePrivateKey(cert.Key)
+	if err != nil {
+		return fmt.Errorf("parse ssh certificate: %w", err)
+	}
+	pemkey := string(MarshalED25519PrivateKey(pk, "single-use certificate"))
+
+	runCtx, cancel := context.WithTimeout(createCancellableContext(), timeout)
+	defer cancel()
+
+	ctx.Statusf("ssh", cmdctx.SBEGIN, "Running %q on %d instance(s)\n", command, len(allocs))
+
+	var mu sync.Mutex
+	results := make([]broadcastResult, len(allocs))
+	var wg sync.WaitGroup
+
+	for i, alloc := range allocs {
+		wg.Add(1)
+		go func(i int, alloc *api.AllocationStatus) {
+			defer wg.Done()
+
+			prefix := fmt.Sprintf("[%s %s] ", alloc.IDShort, alloc.Region)
+			stdout := &prefixWriter{mu: &mu, w: os.Stdout, prefix: prefix}
+			stderr := &prefixWriter{mu: &mu, w: os.Stderr, prefix: prefix}
+
+			sshClient := &ssh.Client{
+				Addr:        fmt.Sprintf("[%s]:22", alloc.PrivateIP),
+				User:        "root",
+				Dial:        dialer.DialContext,
+				Certificate: cert.Certificate,
+				PrivateKey:  pemkey,
+			}
+			defer sshClient.Close()
+
+			start := time.Now()
+			err := sshClient.Run(runCtx, command, stdout, stderr)
+			stdout.Flush()
+			stderr.Flush()
+
+			result := broadcastResult{
+				ID:         alloc.IDShort,
+				Region:     alloc.Region,
+				ExitCode:   ssh.ExitStatus(err),
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil && result.ExitCode < 0 {
+				result.Error = err.Error()
+				terminal.Debugf("broadcast to %s failed: %v\n", alloc.IDShort, err)
+			}
+			results[i] = result
+		}(i, alloc)
+	}
+
+	wg.Wait()
+
+	if ctx.OutputStructured() {
+		if err := ctx.WriteData(results); err != nil {
+			return err
+		}
+	} else {
+		ctx.StatusLn()
+		table := helpers.MakeSimpleTable(ctx.Out, []string{"Instance", "Region", "Exit Code", "Duration", "Error"})
+		for _, r := range results {
+			exitCode := strconv.Itoa(r.ExitCode)
+			if r.ExitCode < 0 {
+				exitCode = "-"
+			}
+			duration := time.Duration(r.DurationMs) * time.Millisecond
+			table.Append([]string{r.ID, r.Region, exitCode, duration.String(), r.Error})
+		}
+		table.Render()
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.ExitCode != 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed on %d of %d instance(s)", failed, len(results))
+	}
+
+	ctx.Statusf("ssh", cmdctx.SDONE, "Command succeeded on %d instance(s)\n", len(results))
+
+	return nil
+}
+
+// broadcastTargets - the running allocations, limited to a region and process
+// group when they're given
+func broadcastTargets(allocs []*api.AllocationStatus, region, group string) []*api.AllocationStatus {
+	out := []*api.AllocationStatus{}
+	for _, alloc := range allocs {
+		if alloc.Status != "running" || alloc.PrivateIP == "" {
+			continue
+		}
+		if region != "" && alloc.Region != region {
+			continue
+		}
+		if group != "" && alloc.TaskName != group {
+			continue
+		}
+		out = append(out, alloc)
+	}
+	return out
+}
+
+// prefixWriter writes whole lines to w, each starting with prefix. Writers
+// sharing mu never interleave within a line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf.Write(data)
+
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		p.writeLine(p.buf.Next(i + 1))
+	}
+
+	return len(data), nil
+}
+
+// Flush writes any incomplete last line
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() > 0 {
+		p.writeLine(append(p.buf.Bytes(), '\n'))
+		p.buf.Reset()
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprint(p.w, p.prefix)
+	p.w.Write(line)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestBroadcastTargets(t *testing.T) {
+	allocs := []*api.AllocationStatus{
+		{IDShort: "web-ord", Status: "running", Region: "ord", TaskName: "web", PrivateIP: "fdaa::1"},
+		{IDShort: "web-lhr", Status: "running", Region: "lhr", TaskName: "web", PrivateIP: "fdaa::2"},
+		{IDShort: "worker-ord", Status: "running", Region: "ord", TaskName: "worker", PrivateIP: "fdaa::3"},
+		{IDShort: "stopped", Status: "complete", Region: "ord", TaskName: "web", PrivateIP: "fdaa::4"},
+		{IDShort: "no-ip", Status: "running", Region: "ord", TaskName: "web"},
+	}
+
+	tests := []struct {
+		name   string
+		region string
+		group  string
+		want   []string
+	}{
+		{name: "all running", want: []string{"web-ord", "web-lhr", "worker-ord"}},
+		{name: "region", region: "ord", want: []string{"web-ord", "worker-ord"}},
+		{name: "process group", group: "worker", want: []string{"worker-ord"}},
+		{name: "region and process group", region: "lhr", group: "web", want: []string{"web-lhr"}},
+		{name: "no match", region: "nrt", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, alloc := range broadcastTargets(allocs, tt.region, tt.group) {
+				got = append(got, alloc.IDShort)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var mu sync.Mutex
+	var out bytes.Buffer
+	a := &prefixWriter{mu: &mu, w: &out, prefix: "[a] "}
+	b := &prefixWriter{mu: &mu, w: &out, prefix: "[b] "}
+
+	a.Write([]byte("one\ntw"))
+	b.Write([]byte("three"))
+	a.Write([]byte("o\n"))
+	b.Write([]byte("\nfour\nfi"))
+	a.Write([]byte("six"))
+	a.Flush()
+	b.Flush()
+	b.Flush()
+
+	want := "[a] one\n[a] two\n[b] three\n[b] four\n[a] six\n[b] fi\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
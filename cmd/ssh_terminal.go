@@ -4,14 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/briandowns/spinner"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/pkg/agent"
+	"github.com/superfly/flyctl/pkg/iostreams"
 	"github.com/superfly/flyctl/pkg/ssh"
 	"github.com/superfly/flyctl/terminal"
 )
@@ -26,7 +26,7 @@ func runSSHConsole(ctx *cmdctx.CmdContext) error {
 		return fmt.Errorf("get app: %w", err)
 	}
 
-	agentclient, err := agent.Establish(client)
+	agentclient, err := establishAgent(ctx)
 	if err != nil {
 		return fmt.Errorf("can't establish agent: %s\n", err)
 	}
@@ -51,13 +51,13 @@ func runSSHConsole(ctx *cmdctx.CmdContext) error {
 		}
 
 		selected := 0
-		prompt := &survey.Select{
+		instancePrompt := &survey.Select{
 			Message:  "Select instance:",
 			Options:  instances.Labels,
 			PageSize: 15,
 		}
 
-		if err := survey.AskOne(prompt, &selected); err != nil {
+		if err := prompt.Ask(instancePrompt, &selected, "select"); err != nil {
 			return fmt.Errorf("selecting instance: %w", err)
 		}
 
@@ -81,14 +81,14 @@ func spin(in, out string) context.CancelFunc {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	if !helpers.IsTerminal() {
-		fmt.Fprintln(os.Stderr, in)
+		if !iostreams.Quiet() {
+			fmt.Fprintln(os.Stderr, in)
+		}
 		return cancel
 	}
 
 	go func() {
-		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-		s.Writer = os.Stderr
-		s.Prefix = in
+		s := iostreams.NewSpinner(in)
 		s.FinalMSG = out
 		s.Start()
 		defer s.Stop()
@@ -8,11 +8,13 @@ import (
 	"github.com/inancgumus/screen"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/render"
 
 	"github.com/segmentio/textio"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/terminal"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/superfly/flyctl/cmd/presenters"
@@ -44,8 +46,12 @@ func runStatus(ctx *cmdctx.CmdContext) error {
 	refreshCount := 1
 	showDeploymentStatus := ctx.Config.GetBool("deployment")
 
-	if watch && ctx.OutputJSON() {
-		return fmt.Errorf("--watch and --json are not supported together")
+	if watch && ctx.OutputStructured() {
+		return fmt.Errorf("--watch is not supported with --json, --yaml or --format")
+	}
+
+	if ctx.OutputOptions().Format == render.FormatPrometheus {
+		return runStatusMetrics(ctx)
 	}
 
 	for {
@@ -56,7 +62,7 @@ func runStatus(ctx *cmdctx.CmdContext) error {
 			refreshCount = refreshCount - 1
 			if refreshCount == 0 {
 				refreshCount = refreshRate
-				app, err = ctx.Client.API().GetAppStatus(ctx.AppName, ctx.Config.GetBool("all"))
+				app, err = getAppStatus(ctx)
 
 				if err != nil {
 					return err
@@ -84,7 +90,7 @@ func runStatus(ctx *cmdctx.CmdContext) error {
 				continue
 			}
 		} else {
-			app, err = ctx.Client.API().GetAppStatus(ctx.AppName, ctx.Config.GetBool("all"))
+			app, err = getAppStatus(ctx)
 
 			if err != nil {
 				return err
@@ -108,8 +114,8 @@ func runStatus(ctx *cmdctx.CmdContext) error {
 			return err
 		}
 
-		// If JSON output, everything has been printed, so return
-		if !watch && ctx.OutputJSON() {
+		// If structured output, everything has been printed, so return
+		if !watch && ctx.OutputStructured() {
 			return nil
 		}
 
@@ -138,7 +144,7 @@ func runStatus(ctx *cmdctx.CmdContext) error {
 		}
 
 		err = ctx.Frender(cmdctx.PresenterOption{
-			Presentable: &presenters.Allocations{Allocations: app.Allocations, BackupRegions: backupregions},
+			Presentable: &presenters.Allocations{Allocations: app.Allocations, BackupRegions: backupregions, AutoStart: autoStarts(app.Autostop)},
 			Title:       "Instances",
 		})
 
@@ -153,6 +159,11 @@ func runStatus(ctx *cmdctx.CmdContext) error {
 
 }
 
+// autoStarts - whether the app's stopped VMs are started by the next request
+func autoStarts(cfg *api.AutostopConfig) bool {
+	return cfg != nil && cfg.AutoStop && cfg.AutoStart
+}
+
 func runAllocStatus(ctx *cmdctx.CmdContext) error {
 	alloc, err := ctx.Client.API().GetAllocationStatus(ctx.AppName, ctx.Args[0], 25)
 	if err != nil {
@@ -208,3 +219,20 @@ func runAllocStatus(ctx *cmdctx.CmdContext) error {
 
 	return nil
 }
+
+// getAppStatus - the app's status with its annotations, which are fetched
+// separately so status still works where the API has no app metadata
+func getAppStatus(ctx *cmdctx.CmdContext) (*api.AppStatus, error) {
+	app, err := ctx.Client.API().GetAppStatus(ctx.AppName, ctx.Config.GetBool("all"))
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata, err := ctx.Client.API().GetAppMetadata(ctx.AppName); err != nil {
+		terminal.Debugf("failed fetching the metadata of %s: %v\n", ctx.AppName, err)
+	} else {
+		app.Metadata = metadata
+	}
+
+	return app, nil
+}
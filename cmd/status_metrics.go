@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"sort"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// runStatusMetrics - writes the app's status as Prometheus metrics, for
+// scraping through a textfile collector or pushing to a gateway
+func runStatusMetrics(ctx *cmdctx.CmdContext) error {
+	app, err := ctx.Client.API().GetAppStatus(ctx.AppName, false)
+	if err != nil {
+		return err
+	}
+
+	var latest *api.Release
+	if app.Deployed {
+		releases, err := ctx.Client.API().GetAppReleases(ctx.AppName, 1)
+		if err != nil {
+			return err
+		}
+		if len(releases) > 0 {
+			latest = &releases[0]
+		}
+	}
+
+	return ctx.WriteData(appStatusMetrics(app, latest, time.Now()))
+}
+
+// appStatusMetrics - metrics describing the app's instances, their health
+// checks and its latest release. release may be nil.
+func appStatusMetrics(app *api.AppStatus, release *api.Release, now time.Time) []render.Metric {
+	appLabel := map[string]string{"app": app.Name}
+
+	deployed := 0.0
+	if app.Deployed {
+		deployed = 1
+	}
+
+	byStatus := map[string]int{}
+	healthy := 0
+	checks := map[string]int{"passing": 0, "warning": 0, "critical": 0}
+	for _, alloc := range app.Allocations {
+		byStatus[alloc.Status]++
+		if alloc.Healthy {
+			healthy++
+		}
+		checks["passing"] += alloc.PassingCheckCount
+		checks["warning"] += alloc.WarningCheckCount
+		checks["critical"] += alloc.CriticalCheckCount
+	}
+
+	metrics := []render.Metric{
+		{
+			Name:    "fly_app_deployed",
+			Help:    "Whether the app has been deployed",
+			Type:    "gauge",
+			Samples: []render.Sample{{Labels: appLabel, Value: deployed}},
+		},
+		{
+			Name:    "fly_app_instances",
+			Help:    "Instances of the app by status",
+			Type:    "gauge",
+			Samples: labelledCounts(app.Name, "status", byStatus),
+		},
+		{
+			Name:    "fly_app_instances_healthy",
+			Help:    "Instances of the app passing their health checks",
+			Type:    "gauge",
+			Samples: []render.Sample{{Labels: appLabel, Value: float64(healthy)}},
+		},
+		{
+			Name:    "fly_app_health_checks",
+			Help:    "Health checks of the app's instances by state",
+			Type:    "gauge",
+			Samples: labelledCounts(app.Name, "state", checks),
+		},
+	}
+
+	if release != nil {
+		metrics = append(metrics,
+			render.Metric{
+				Name:    "fly_app_release_version",
+				Help:    "Version of the app's latest release",
+				Type:    "gauge",
+				Samples: []render.Sample{{Labels: appLabel, Value: float64(release.Version)}},
+			},
+			render.Metric{
+				Name:    "fly_app_release_age_seconds",
+				Help:    "Seconds since the app's latest release was created",
+				Type:    "gauge",
+				Samples: []render.Sample{{Labels: appLabel, Value: now.Sub(release.CreatedAt).Truncate(time.Second).Seconds()}},
+			},
+		)
+	}
+
+	return metrics
+}
+
+// labelledCounts - a sample for each count, labelled with the app and the
+// count's key, in key order
+func labelledCounts(appName string, label string, counts map[string]int) []render.Sample {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	samples := make([]render.Sample, 0, len(keys))
+	for _, key := range keys {
+		samples = append(samples, render.Sample{
+			Labels: map[string]string{"app": appName, label: key},
+			Value:  float64(counts[key]),
+		})
+	}
+	return samples
+}
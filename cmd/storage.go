@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/pkg/iostreams"
+)
+
+func newStorageCommand(client *client.Client) *Command {
+	storageStrings := docstrings.Get("storage")
+	cmd := BuildCommandKS(nil, nil, storageStrings, client, requireSession)
+
+	createStrings := docstrings.Get("storage.create")
+	createCmd := BuildCommandKS(cmd, runStorageCreate, createStrings, client, requireSession, requireAppName)
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Shorthand:   "n",
+		Description: "The name of the bucket, defaults to the app's name",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "The region to store objects in, defaults to the app's primary region",
+	})
+	createCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "public",
+		Description: "Allow anyone to read objects, without credentials",
+	})
+	createCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	listStrings := docstrings.Get("storage.list")
+	listCmd := BuildCommandKS(cmd, runStorageList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+	listCmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization the buckets belong to",
+		EnvName:     "FLY_ORG",
+	})
+
+	destroyStrings := docstrings.Get("storage.destroy")
+	destroyCmd := BuildCommandKS(cmd, runStorageDestroy, destroyStrings, client, requireSession)
+	destroyCmd.Args = cobra.ExactArgs(1)
+	destroyCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	return cmd
+}
+
+// storageSecretNames - the secrets an app is given to reach its bucket,
+// named so AWS SDKs pick them up without configuration
+var storageSecretNames = []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_REGION", "AWS_ENDPOINT_URL_S3", "BUCKET_NAME"}
+
+func storageSecrets(bucket *api.StorageBucket, credentials *api.StorageCredentials) map[string]string {
+	return map[string]string{
+		"AWS_ACCESS_KEY_ID":     credentials.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY": credentials.SecretAccessKey,
+		"AWS_REGION":            bucket.Region,
+		"AWS_ENDPOINT_URL_S3":   bucket.Endpoint,
+		"BUCKET_NAME":           bucket.Name,
+	}
+}
+
+func runStorageCreate(ctx *cmdctx.CmdContext) error {
+	app, err := ctx.Client.API().GetApp(ctx.AppName)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := confirmSecretsReplaced(ctx, app.Name, storageSecretNames); err != nil || !ok {
+		return err
+	}
+
+	name := ctx.Config.GetString("name")
+	if name == "" {
+		name = app.Name
+	}
+
+	region := defaultToPrimaryRegion(ctx)
+	if region == "" {
+		return &ValidationError{fmt.Errorf("%s has no primary region yet, choose one with --region", app.Name)}
+	}
+
+	s := iostreams.NewSpinner("Creating bucket...")
+	s.Start()
+
+	bucket, credentials, err := ctx.Client.API().CreateStorageBucket(api.CreateStorageBucketInput{
+		OrganizationID: app.Organization.ID,
+		AppID:          api.StringPointer(app.Name),
+		Name:           name,
+		Region:         region,
+		Public:         ctx.Config.GetBool("public"),
+	})
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	secrets := storageSecrets(bucket, credentials)
+	release, err := ctx.Client.API().SetSecrets(app.Name, secrets)
+	if err != nil {
+		// the secret access key can't be fetched again, so show it rather
+		// than leave the bucket unusable
+		ctx.Statusf("storage", cmdctx.SERROR, "Created bucket %s but couldn't set %s's secrets, set these yourself:\n", bucket.Name, app.Name)
+		for _, key := range storageSecretNames {
+			fmt.Fprintf(ctx.Out, "  %s=%s\n", key, secrets[key])
+		}
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(bucket)
+	}
+
+	ctx.Statusf("storage", cmdctx.SDONE, "Created bucket %s in %s\n", bucket.Name, bucket.Region)
+	ctx.Statusf("storage", cmdctx.SINFO, "Set these secrets on %s: %s\n", app.Name, strings.Join(storageSecretNames, ", "))
+	if app.Deployed {
+		ctx.Statusf("storage", cmdctx.SINFO, "Release v%d created\n", release.Version)
+	}
+
+	return nil
+}
+
+func runStorageList(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	buckets, err := ctx.Client.API().GetStorageBuckets(org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(buckets)
+	}
+
+	if len(buckets) == 0 {
+		ctx.Statusf("storage", cmdctx.SINFO, "%s has no buckets, create one with `flyctl storage create`\n", org.Slug)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"Name", "App", "Region", "Public", "Endpoint", "Created"})
+	for _, bucket := range buckets {
+		appName := ""
+		if bucket.App != nil {
+			appName = bucket.App.Name
+		}
+		public := "no"
+		if bucket.Public {
+			public = "yes"
+		}
+		table.Append([]string{bucket.Name, appName, bucket.Region, public, bucket.Endpoint, humanize.Time(bucket.CreatedAt)})
+	}
+	table.Render()
+
+	return nil
+}
+
+func runStorageDestroy(ctx *cmdctx.CmdContext) error {
+	name := ctx.Args[0]
+
+	bucket, err := ctx.Client.API().GetStorageBucket(name)
+	if err == api.ErrNotFound {
+		return fmt.Errorf("bucket %s not found, see `flyctl storage list`", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Destroy bucket %s? Every object in it will be deleted", bucket.Name), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DeleteStorageBucket(bucket.ID); err != nil {
+		return err
+	}
+
+	ctx.Statusf("storage", cmdctx.SDONE, "Destroyed bucket %s\n", bucket.Name)
+	if bucket.App != nil {
+		ctx.Statusf("storage", cmdctx.SINFO, "Its credentials no longer work, remove them from %s with `flyctl secrets unset`\n", bucket.App.Name)
+	}
+
+	return nil
+}
@@ -2,19 +2,30 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/terminal"
 
-	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/pkg/iostreams"
 )
 
 //TODO: Move all output to status styled begin/done updates
 
+// suspendedCountsMetadataKey records the VM counts of a suspended app's
+// process groups, e.g. "app=3,worker=1", so resume can restore them
+const suspendedCountsMetadataKey = "fly.suspend.counts"
+
+// suspendPollInterval is how often suspend and resume check on the app's VMs
+const suspendPollInterval = time.Second
+
 func newSuspendCommand(client *client.Client) *Command {
 
 	suspendStrings := docstrings.Get("suspend")
@@ -26,14 +37,22 @@ func newSuspendCommand(client *client.Client) *Command {
 }
 
 func runSuspend(ctx *cmdctx.CmdContext) error {
-	// appName := ctx.Args[0]
-	// fmt.Println(appName, len(ctx.Args))
-	// if appName == "" {
-	// 	appName = ctx.AppName
-	// }
 	appName := ctx.AppName
 
-	_, err := ctx.Client.API().SuspendApp(appName)
+	counts, err := ctx.Client.API().GetAppVMCount(appName)
+	if err != nil {
+		return err
+	}
+
+	// the counts are only a nicety for resume, so suspend carries on where
+	// they can't be recorded, e.g. when the API predates app metadata
+	if saved := formatGroupCounts(counts); saved != "" {
+		if _, err := ctx.Client.API().SetAppMetadata(appName, map[string]string{suspendedCountsMetadataKey: saved}); err != nil {
+			terminal.Warnf("Failed to record the instance counts, resume will start one instance: %v\n", err)
+		}
+	}
+
+	_, err = ctx.Client.API().SuspendApp(appName)
 	if err != nil {
 		return err
 	}
@@ -47,9 +66,7 @@ func runSuspend(ctx *cmdctx.CmdContext) error {
 
 	allocount := len(appstatus.Allocations)
 
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Writer = os.Stderr
-	s.Prefix = fmt.Sprintf("Suspending %s with %d instances to stop ", appstatus.Name, allocount)
+	s := iostreams.NewSpinner(fmt.Sprintf("Suspending %s with %d instances to stop ", appstatus.Name, allocount))
 	s.Start()
 
 	for allocount > 0 {
@@ -58,6 +75,7 @@ func runSuspend(ctx *cmdctx.CmdContext) error {
 			plural = "s"
 		}
 		s.Prefix = fmt.Sprintf("Suspending %s with %d instance%s to stop ", appstatus.Name, allocount, plural)
+		time.Sleep(suspendPollInterval)
 		appstatus, err = ctx.Client.API().GetAppStatus(ctx.AppName, false)
 		if err != nil {
 			return err
@@ -70,3 +88,34 @@ func runSuspend(ctx *cmdctx.CmdContext) error {
 
 	return nil
 }
+
+// formatGroupCounts - the non-zero process group counts as "group=count"
+// pairs, sorted by group
+func formatGroupCounts(counts []api.TaskGroupCount) string {
+	pairs := []string{}
+	for _, tg := range counts {
+		if tg.Count > 0 {
+			pairs = append(pairs, fmt.Sprintf("%s=%d", tg.Name, tg.Count))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// parseGroupCounts - the counts recorded by formatGroupCounts, skipping any
+// pair that doesn't parse
+func parseGroupCounts(value string) []api.VMCountInput {
+	counts := []api.VMCountInput{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil || count < 1 {
+			continue
+		}
+		counts = append(counts, api.VMCountInput{Group: parts[0], Count: count})
+	}
+	return counts
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/promql"
+)
+
+func newTokensCommand(client *client.Client) *Command {
+	tokensStrings := docstrings.Get("tokens")
+	cmd := BuildCommandKS(nil, nil, tokensStrings, client, requireSession)
+
+	createStrings := docstrings.Get("tokens.create")
+	createCmd := BuildCommandKS(cmd, nil, createStrings, client, requireSession)
+
+	metricsStrings := docstrings.Get("tokens.create.metrics")
+	metricsCmd := BuildCommandKS(createCmd, runTokensCreateMetrics, metricsStrings, client, requireSession)
+	addTokensOrgFlag(metricsCmd)
+	metricsCmd.AddStringFlag(StringFlagOpts{
+		Name:        "name",
+		Shorthand:   "n",
+		Description: "A name to tell the token apart from others, e.g. where it's used",
+	})
+
+	listStrings := docstrings.Get("tokens.list")
+	listCmd := BuildCommandKS(cmd, runTokensList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+	addTokensOrgFlag(listCmd)
+
+	revokeStrings := docstrings.Get("tokens.revoke")
+	revokeCmd := BuildCommandKS(cmd, runTokensRevoke, revokeStrings, client, requireSession)
+	revokeCmd.Args = cobra.ExactArgs(1)
+	revokeCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	return cmd
+}
+
+func addTokensOrgFlag(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization the tokens belong to",
+		EnvName:     "FLY_ORG",
+	})
+}
+
+func runTokensCreateMetrics(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	name := ctx.Config.GetString("name")
+	if name == "" {
+		name = "metrics"
+	}
+
+	token, err := ctx.Client.API().CreateMetricsToken(org.ID, name)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(token)
+	}
+
+	ctx.Statusf("tokens", cmdctx.SDONE, "Created metrics token %s (%s) for %s\n", token.Name, token.ID, org.Slug)
+	ctx.Statusf("tokens", cmdctx.SINFO, "The token can only read the organization's metrics. It's shown once, keep it somewhere safe:\n")
+	fmt.Fprintln(ctx.Out, token.Token)
+	ctx.StatusLn()
+	ctx.Statusf("tokens", cmdctx.SINFO, "Add %s/%s as a Prometheus data source with the header\n", promql.DefaultBaseURL, org.Slug)
+	ctx.Statusf("tokens", cmdctx.SINFO, "Authorization: Bearer <token>, or federate from %s/%s/federate\n", promql.DefaultBaseURL, org.Slug)
+
+	return nil
+}
+
+func runTokensList(ctx *cmdctx.CmdContext) error {
+	org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := ctx.Client.API().GetMetricsTokens(org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(tokens)
+	}
+
+	if len(tokens) == 0 {
+		ctx.Statusf("tokens", cmdctx.SINFO, "%s has no tokens, create one with `flyctl tokens create metrics`\n", org.Slug)
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Name", "Type", "Created", "Last Used"})
+	for _, token := range tokens {
+		lastUsed := "never"
+		if token.LastUsedAt != nil {
+			lastUsed = humanize.Time(*token.LastUsedAt)
+		}
+		table.Append([]string{token.ID, token.Name, "metrics", humanize.Time(token.CreatedAt), lastUsed})
+	}
+	table.Render()
+
+	return nil
+}
+
+func runTokensRevoke(ctx *cmdctx.CmdContext) error {
+	id := ctx.Args[0]
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Revoke token %s? Anything using it will lose access", id), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DeleteMetricsToken(id); err != nil {
+		return err
+	}
+
+	ctx.Statusf("tokens", cmdctx.SDONE, "Revoked token %s\n", id)
+
+	return nil
+}
@@ -44,7 +44,7 @@ func runVersion(ctx *cmdctx.CmdContext) error {
 		update.InitState(stateFilePath, saveInstall)
 	}
 
-	if ctx.OutputJSON() {
+	if ctx.OutputStructured() {
 		type flyctlBuild struct {
 			Name         string
 			Version      string
@@ -53,11 +53,11 @@ func runVersion(ctx *cmdctx.CmdContext) error {
 			OS           string
 			Architecture string
 		}
-		ctx.WriteJSON(flyctlBuild{Name: flyname.Name(), Version: flyctl.Version, Commit: flyctl.Commit, BuildDate: flyctl.BuildDate, OS: runtime.GOOS, Architecture: runtime.GOARCH})
-	} else {
-		fmt.Printf("%s v%s %s/%s Commit: %s BuildDate: %s\n", flyname.Name(), flyctl.Version, runtime.GOOS, runtime.GOARCH, flyctl.Commit, flyctl.BuildDate)
+		return ctx.WriteData(flyctlBuild{Name: flyname.Name(), Version: flyctl.Version, Commit: flyctl.Commit, BuildDate: flyctl.BuildDate, OS: runtime.GOOS, Architecture: runtime.GOARCH})
 	}
 
+	fmt.Printf("%s v%s %s/%s Commit: %s BuildDate: %s\n", flyname.Name(), flyctl.Version, runtime.GOOS, runtime.GOARCH, flyctl.Commit, flyctl.BuildDate)
+
 	return nil
 }
 
@@ -3,17 +3,20 @@ package cmd
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/dustin/go-humanize"
 	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/terminal"
 
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/prompt"
 )
 
 func newVolumesCommand(client *client.Client) *Command {
@@ -45,6 +48,22 @@ func newVolumesCommand(client *client.Client) *Command {
 		Default:     true,
 	})
 
+	createCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "require-unique-zone",
+		Description: "Place the volume in a separate hardware zone from the app's other volumes of the same name (default: true)",
+		Default:     true,
+	})
+
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "host-affinity",
+		Description: "Place the volume on the same host as this volume ID, where the region supports it. Requires --require-unique-zone=false",
+	})
+
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "snapshot-id",
+		Description: "Restore the volume from this snapshot ID",
+	})
+
 	deleteStrings := docstrings.Get("volumes.delete")
 	deleteCmd := BuildCommandKS(volumesCmd, runDeleteVolume, deleteStrings, client, requireSession)
 	deleteCmd.Args = cobra.ExactArgs(1)
@@ -54,6 +73,8 @@ func newVolumesCommand(client *client.Client) *Command {
 	showCmd := BuildCommandKS(volumesCmd, runShowVolume, showStrings, client, requireSession)
 	showCmd.Args = cobra.ExactArgs(1)
 
+	newVolumeSnapshotsCommand(volumesCmd, client)
+
 	return volumesCmd
 }
 
@@ -70,9 +91,8 @@ func runListVolumes(ctx *cmdctx.CmdContext) error {
 		return nil
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(volumes)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(volumes)
 	}
 
 	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Name", "Size", "Region", "Attached VM", "Created At"})
@@ -108,14 +128,41 @@ func runCreateVolume(ctx *cmdctx.CmdContext) error {
 		return fmt.Errorf("--region <region> flag required")
 	}
 
-	sizeGb := ctx.Config.GetInt("size")
+	input := api.CreateVolumeInput{
+		AppID:     appid,
+		Name:      volName,
+		Region:    region,
+		SizeGb:    ctx.Config.GetInt("size"),
+		Encrypted: ctx.Config.GetBool("encrypted"),
+	}
+
+	// unique zones are the API's default, so it's only sent to relax it
+	requireUniqueZone := ctx.Config.GetBool("require-unique-zone")
+	if !requireUniqueZone {
+		input.RequireUniqueZone = api.BoolPointer(false)
+	}
+
+	if hostAffinity := ctx.Config.GetString("host-affinity"); hostAffinity != "" {
+		if requireUniqueZone {
+			return &ValidationError{fmt.Errorf("--host-affinity places volumes on the same host, use it with --require-unique-zone=false")}
+		}
+		input.HostAffinityVolumeID = api.StringPointer(hostAffinity)
+	}
 
-	volume, err := ctx.Client.API().CreateVolume(appid, volName, region, sizeGb, ctx.Config.GetBool("encrypted"))
+	if snapshotID := ctx.Config.GetString("snapshot-id"); snapshotID != "" {
+		input.SnapshotID = api.StringPointer(snapshotID)
+	}
+
+	volume, err := ctx.Client.API().CreateVolumeWithInput(input)
 
 	if err != nil {
 		return err
 	}
 
+	if ctx.OutputStructured() {
+		return ctx.WriteData(volume)
+	}
+
 	fmt.Printf("%10s: %s\n", "ID", volume.ID)
 	fmt.Printf("%10s: %s\n", "Name", volume.Name)
 	fmt.Printf("%10s: %s\n", "Region", volume.Region)
@@ -133,17 +180,12 @@ func runDeleteVolume(ctx *cmdctx.CmdContext) error {
 	if !ctx.Config.GetBool("yes") {
 		fmt.Println(aurora.Red("Deleting a volume is not reversible."))
 
-		confirm := false
-		prompt := &survey.Confirm{
-			Message: fmt.Sprintf("Delete volume %s?", volID),
-		}
-		err := survey.AskOne(prompt, &confirm)
-
+		confirmed, err := prompt.Confirm(fmt.Sprintf("Delete volume %s?", volID), "yes")
 		if err != nil {
 			return err
 		}
 
-		if !confirm {
+		if !confirmed {
 			return nil
 		}
 	}
@@ -168,9 +210,18 @@ func runShowVolume(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(volume)
-		return nil
+	// placement is a nicety, the volume is shown without it where the API
+	// doesn't report it
+	placement, err := ctx.Client.API().GetVolumePlacement(volID)
+	if err != nil {
+		terminal.Debug("error fetching volume placement:", err)
+	} else {
+		volume.Zone = placement.Zone
+		volume.Host = placement.Host
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(volume)
 	}
 
 	fmt.Printf("%10s: %s\n", "ID", volume.ID)
@@ -178,7 +229,41 @@ func runShowVolume(ctx *cmdctx.CmdContext) error {
 	fmt.Printf("%10s: %s\n", "Region", volume.Region)
 	fmt.Printf("%10s: %d\n", "Size GB", volume.SizeGb)
 	fmt.Printf("%10s: %t\n", "Encrypted", volume.Encrypted)
+	if placement != nil {
+		fmt.Printf("%10s: %s\n", "Zone", volume.Zone)
+		fmt.Printf("%10s: %s\n", "Host", volume.Host.ID)
+	}
 	fmt.Printf("%10s: %s\n", "Created at", volume.CreatedAt.Format(time.RFC822))
 
+	if shared, err := volumesSharingHost(ctx, volume); err != nil {
+		terminal.Debug("error checking volume placement:", err)
+	} else if len(shared) > 0 {
+		fmt.Println()
+		fmt.Println(aurora.Yellow(fmt.Sprintf("Other %s volumes on this host: %s", volume.Name, strings.Join(shared, ", "))))
+	}
+
 	return nil
 }
+
+// volumesSharingHost lists the IDs of the current app's other volumes with
+// the same name as volume that are placed on the same host, which defeats
+// redundancy
+func volumesSharingHost(ctx *cmdctx.CmdContext, volume *api.Volume) ([]string, error) {
+	if volume.Host.ID == "" || ctx.AppName == "" {
+		return nil, nil
+	}
+
+	volumes, err := ctx.Client.API().GetVolumesPlacement(ctx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := []string{}
+	for _, v := range volumes {
+		if v.ID != volume.ID && v.Name == volume.Name && v.Host.ID == volume.Host.ID {
+			shared = append(shared, v.ID)
+		}
+	}
+
+	return shared, nil
+}
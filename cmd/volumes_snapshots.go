@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+func newVolumeSnapshotsCommand(parent *Command, client *client.Client) {
+	snapshotsStrings := docstrings.Get("volumes.snapshots")
+	snapshotsCmd := BuildCommandKS(parent, nil, snapshotsStrings, client, requireSession)
+	snapshotsCmd.Aliases = []string{"snapshot", "snaps"}
+
+	listStrings := docstrings.Get("volumes.snapshots.list")
+	listCmd := BuildCommandKS(snapshotsCmd, runListVolumeSnapshots, listStrings, client, requireSession)
+	listCmd.Args = cobra.ExactArgs(1)
+
+	exportStrings := docstrings.Get("volumes.snapshots.export")
+	exportCmd := BuildCommandKS(snapshotsCmd, runExportVolumeSnapshot, exportStrings, client, requireSession)
+	exportCmd.Args = cobra.ExactArgs(1)
+
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "to",
+		Description: "Where to export the snapshot to, as s3://bucket/path",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "access-key-id",
+		Description: "The access key ID for the bucket",
+		EnvName:     "AWS_ACCESS_KEY_ID",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "secret-access-key",
+		Description: "The secret access key for the bucket",
+		EnvName:     "AWS_SECRET_ACCESS_KEY",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "session-token",
+		Description: "The session token for temporary credentials",
+		EnvName:     "AWS_SESSION_TOKEN",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "s3-region",
+		Description: "The bucket's region",
+		Default:     s3.DefaultRegion,
+		EnvName:     "AWS_REGION",
+	})
+	exportCmd.AddStringFlag(StringFlagOpts{
+		Name:        "endpoint",
+		Description: "The URL of S3 compatible storage, when it isn't AWS",
+		EnvName:     "AWS_ENDPOINT_URL",
+	})
+	exportCmd.AddIntFlag(IntFlagOpts{
+		Name:        "part-size",
+		Description: "The size of each uploaded part in megabytes",
+		Default:     64,
+	})
+	exportCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "restart",
+		Description: "Start the export over instead of resuming an interrupted one",
+	})
+}
+
+func runListVolumeSnapshots(ctx *cmdctx.CmdContext) error {
+	snapshots, err := ctx.Client.API().GetVolumeSnapshots(ctx.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(snapshots)
+	}
+
+	if len(snapshots) == 0 {
+		ctx.Statusf("volumes", cmdctx.SINFO, "Volume %s has no snapshots\n", ctx.Args[0])
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "Size", "Digest", "Created At"})
+	for _, s := range snapshots {
+		table.Append([]string{s.ID, formatSnapshotSize(s.Size), s.Digest, humanize.Time(s.CreatedAt)})
+	}
+	table.Render()
+
+	return nil
+}
+
+func formatSnapshotSize(size string) string {
+	bytes, err := strconv.ParseUint(size, 10, 64)
+	if err != nil {
+		return size
+	}
+	return humanize.Bytes(bytes)
+}
+
+// snapshotExport is the progress of an export, saved after each part so an
+// interrupted export can be resumed
+type snapshotExport struct {
+	SnapshotID  string    `json:"snapshot_id"`
+	Destination string    `json:"destination"`
+	UploadID    string    `json:"upload_id"`
+	PartSize    int64     `json:"part_size"`
+	Parts       []s3.Part `json:"parts"`
+	// HashState is the state of the snapshot's digest after the parts
+	HashState []byte `json:"hash_state"`
+}
+
+func (e *snapshotExport) offset() int64 {
+	var offset int64
+	for _, p := range e.Parts {
+		offset += p.Size
+	}
+	return offset
+}
+
+func snapshotExportPath(snapshotID string) string {
+	return filepath.Join(flyctl.ConfigDir(), "snapshot-exports", snapshotID+".json")
+}
+
+func loadSnapshotExport(snapshotID string) (*snapshotExport, error) {
+	data, err := os.ReadFile(snapshotExportPath(snapshotID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var export snapshotExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (e *snapshotExport) save() error {
+	path := snapshotExportPath(e.SnapshotID)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (e *snapshotExport) remove() error {
+	err := os.Remove(snapshotExportPath(e.SnapshotID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func runExportVolumeSnapshot(ctx *cmdctx.CmdContext) error {
+	snapshotID := ctx.Args[0]
+
+	to := ctx.Config.GetString("to")
+	if to == "" {
+		return &ValidationError{fmt.Errorf("--to is required, e.g. --to s3://bucket/path")}
+	}
+	loc, err := s3.ParseURL(to)
+	if err != nil {
+		return &ValidationError{err}
+	}
+
+	store := &s3.Client{
+		AccessKeyID:     ctx.Config.GetString("access-key-id"),
+		SecretAccessKey: ctx.Config.GetString("secret-access-key"),
+		SessionToken:    ctx.Config.GetString("session-token"),
+		Region:          ctx.Config.GetString("s3-region"),
+		Endpoint:        ctx.Config.GetString("endpoint"),
+	}
+	if store.AccessKeyID == "" || store.SecretAccessKey == "" {
+		return &ValidationError{fmt.Errorf("credentials are required, set --access-key-id and --secret-access-key or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")}
+	}
+
+	partSize := int64(ctx.Config.GetInt("part-size")) << 20
+	if partSize < s3.MinPartSize {
+		return &ValidationError{fmt.Errorf("--part-size must be at least %d", s3.MinPartSize>>20)}
+	}
+
+	snapshot, err := ctx.Client.API().GetVolumeSnapshot(snapshotID)
+	if err != nil {
+		return fmt.Errorf("get snapshot %s: %w", snapshotID, err)
+	}
+	snapshot.DownloadURL, err = ctx.Client.API().GetVolumeSnapshotDownloadURL(snapshotID)
+	if err != nil {
+		return fmt.Errorf("snapshot %s can't be exported, the API doesn't offer a download of its data: %w", snapshotID, err)
+	}
+	if snapshot.DownloadURL == "" {
+		return fmt.Errorf("snapshot %s can't be downloaded yet, try again once it's complete", snapshotID)
+	}
+
+	size, _ := strconv.ParseInt(snapshot.Size, 10, 64)
+	// parts are grown to fit the snapshot into the most parts an upload can have
+	if size > 0 && size/partSize >= s3.MaxParts {
+		partSize = size/(s3.MaxParts-1) + 1
+	}
+
+	wantDigest := strings.TrimPrefix(snapshot.Digest, "sha256:")
+	if wantDigest == "" {
+		ctx.Statusf("volumes", cmdctx.SWARN, "Snapshot %s has no digest, its checksum can't be verified\n", snapshotID)
+	}
+
+	cancelCtx := createCancellableContext()
+
+	export, digest, err := resumeSnapshotExport(ctx, cancelCtx, store, loc, snapshotID, partSize)
+	if err != nil {
+		return err
+	}
+
+	if err := uploadSnapshotParts(ctx, cancelCtx, store, loc, snapshot, export, digest, size); err != nil {
+		if cancelCtx.Err() != nil || !s3.IsNoSuchUpload(err) {
+			ctx.Statusf("volumes", cmdctx.SINFO, "Run the same command again to resume the export from part %d\n", len(export.Parts)+1)
+		}
+		return err
+	}
+
+	if len(export.Parts) == 0 {
+		if err := store.AbortMultipartUpload(cancelCtx, loc, export.UploadID); err != nil {
+			ctx.Statusf("volumes", cmdctx.SWARN, "Failed to discard the upload: %s\n", err)
+		}
+		if err := export.remove(); err != nil {
+			return err
+		}
+		return fmt.Errorf("snapshot %s is empty, there's nothing to export", snapshotID)
+	}
+
+	gotDigest := hex.EncodeToString(digest.Sum(nil))
+	if wantDigest != "" && gotDigest != wantDigest {
+		if err := store.AbortMultipartUpload(cancelCtx, loc, export.UploadID); err != nil {
+			ctx.Statusf("volumes", cmdctx.SWARN, "Failed to discard the upload: %s\n", err)
+		}
+		if err := export.remove(); err != nil {
+			return err
+		}
+		return fmt.Errorf("snapshot checksum mismatch, got sha256:%s, expected sha256:%s. The upload was discarded", gotDigest, wantDigest)
+	}
+
+	if err := store.CompleteMultipartUpload(cancelCtx, loc, export.UploadID, export.Parts); err != nil {
+		return fmt.Errorf("complete upload: %w", err)
+	}
+	if err := export.remove(); err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(map[string]interface{}{
+			"snapshotId":  snapshotID,
+			"destination": loc.String(),
+			"size":        export.offset(),
+			"digest":      "sha256:" + gotDigest,
+		})
+	}
+
+	ctx.Statusf("volumes", cmdctx.SDONE, "Exported snapshot %s to %s (%s, sha256:%s)\n", snapshotID, loc, humanize.Bytes(uint64(export.offset())), gotDigest)
+
+	return nil
+}
+
+// resumeSnapshotExport - the saved progress of an export to loc, checked
+// against the parts in storage, or a new export when there's none
+func resumeSnapshotExport(ctx *cmdctx.CmdContext, cancelCtx context.Context, store *s3.Client, loc s3.Location, snapshotID string, partSize int64) (*snapshotExport, hash.Hash, error) {
+	digest := sha256.New()
+
+	saved, err := loadSnapshotExport(snapshotID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read export progress: %w", err)
+	}
+
+	switch {
+	case saved == nil:
+	case ctx.Config.GetBool("restart"):
+		if saved.Destination != loc.String() {
+			saved = nil
+			break
+		}
+		if err := store.AbortMultipartUpload(cancelCtx, loc, saved.UploadID); err != nil && !s3.IsNoSuchUpload(err) {
+			ctx.Statusf("volumes", cmdctx.SWARN, "Failed to discard the previous upload: %s\n", err)
+		}
+		saved = nil
+	case saved.Destination != loc.String():
+		ctx.Statusf("volumes", cmdctx.SWARN, "Discarding the progress of an export to %s\n", saved.Destination)
+		saved = nil
+	default:
+		uploaded, err := store.ListParts(cancelCtx, loc, saved.UploadID)
+		if s3.IsNoSuchUpload(err) {
+			ctx.Statusf("volumes", cmdctx.SWARN, "The interrupted upload no longer exists, starting over\n")
+			saved = nil
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if !partsUploaded(saved.Parts, uploaded) {
+			ctx.Statusf("volumes", cmdctx.SWARN, "The interrupted upload is missing parts, starting over\n")
+			saved = nil
+			break
+		}
+		if err := digest.(encoding.BinaryUnmarshaler).UnmarshalBinary(saved.HashState); err != nil {
+			return nil, nil, fmt.Errorf("read export progress: %w", err)
+		}
+
+		ctx.Statusf("volumes", cmdctx.SINFO, "Resuming the export to %s after %d parts (%s)\n", loc, len(saved.Parts), humanize.Bytes(uint64(saved.offset())))
+		return saved, digest, nil
+	}
+
+	uploadID, err := store.CreateMultipartUpload(cancelCtx, loc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("start upload to %s: %w", loc, err)
+	}
+
+	export := &snapshotExport{
+		SnapshotID:  snapshotID,
+		Destination: loc.String(),
+		UploadID:    uploadID,
+		PartSize:    partSize,
+		Parts:       []s3.Part{},
+	}
+	if err := export.save(); err != nil {
+		return nil, nil, fmt.Errorf("save export progress: %w", err)
+	}
+
+	ctx.Statusf("volumes", cmdctx.SBEGIN, "Exporting snapshot %s to %s\n", snapshotID, loc)
+	return export, digest, nil
+}
+
+// partsUploaded reports whether storage has each of the saved parts
+func partsUploaded(saved []s3.Part, uploaded []s3.Part) bool {
+	etags := map[int]string{}
+	for _, p := range uploaded {
+		etags[p.Number] = p.ETag
+	}
+	for _, p := range saved {
+		if etags[p.Number] != p.ETag {
+			return false
+		}
+	}
+	return true
+}
+
+// uploadSnapshotParts - downloads the snapshot from the export's offset,
+// uploading it in parts and saving the progress after each
+func uploadSnapshotParts(ctx *cmdctx.CmdContext, cancelCtx context.Context, store *s3.Client, loc s3.Location, snapshot *api.VolumeSnapshot, export *snapshotExport, digest hash.Hash, size int64) error {
+	offset := export.offset()
+
+	req, err := http.NewRequestWithContext(cancelCtx, http.MethodGet, snapshot.DownloadURL, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("download snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+	case resp.StatusCode == http.StatusOK:
+		// the whole snapshot was sent, skip what's been uploaded
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			return fmt.Errorf("download snapshot: %w", err)
+		}
+	default:
+		return fmt.Errorf("download snapshot: %s", resp.Status)
+	}
+
+	buf := make([]byte, export.PartSize)
+	for {
+		n, err := io.ReadFull(resp.Body, buf)
+		if err == io.EOF {
+			return nil
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("download snapshot: %w", err)
+		}
+		last := err == io.ErrUnexpectedEOF
+
+		number := len(export.Parts) + 1
+		part, err := store.UploadPart(cancelCtx, loc, export.UploadID, number, buf[:n])
+		if err != nil {
+			return fmt.Errorf("upload part %d: %w", number, err)
+		}
+
+		digest.Write(buf[:n])
+		state, err := digest.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return err
+		}
+		export.Parts = append(export.Parts, part)
+		export.HashState = state
+		if err := export.save(); err != nil {
+			return fmt.Errorf("save export progress: %w", err)
+		}
+
+		if !ctx.OutputStructured() {
+			progress := humanize.Bytes(uint64(export.offset()))
+			if size > 0 {
+				progress += " of " + humanize.Bytes(uint64(size))
+			}
+			ctx.Statusf("volumes", cmdctx.SDETAIL, "Uploaded part %d, %s\n", number, progress)
+		}
+
+		if last {
+			return nil
+		}
+	}
+}
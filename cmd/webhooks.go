@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+// webhookEvents - the events webhooks can be notified of. release covers
+// releases succeeding and failing.
+var webhookEvents = []string{"release", "release.failed"}
+
+// webhookFormats - how notifications are posted
+var webhookFormats = []string{"slack", "json"}
+
+func newWebhooksCommand(client *client.Client) *Command {
+	webhooksStrings := docstrings.Get("webhooks")
+	cmd := BuildCommandKS(nil, nil, webhooksStrings, client, requireSession)
+
+	createStrings := docstrings.Get("webhooks.create")
+	createCmd := BuildCommandKS(cmd, runWebhooksCreate, createStrings, client, requireSession)
+	addWebhookScopeFlags(createCmd)
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "url",
+		Description: "The URL to post notifications to",
+	})
+	createCmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "event",
+		Description: "An event to notify of: " + strings.Join(webhookEvents, ", ") + ". Can be specified multiple times.",
+		Default:     []string{"release"},
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "payload",
+		Description: "How to post notifications: " + strings.Join(webhookFormats, ", ") + ". Defaults to slack for Slack webhook URLs, json otherwise",
+	})
+
+	listStrings := docstrings.Get("webhooks.list")
+	listCmd := BuildCommandKS(cmd, runWebhooksList, listStrings, client, requireSession)
+	listCmd.Aliases = []string{"ls"}
+	addWebhookScopeFlags(listCmd)
+
+	deleteStrings := docstrings.Get("webhooks.delete")
+	deleteCmd := BuildCommandKS(cmd, runWebhooksDelete, deleteStrings, client, requireSession)
+	deleteCmd.Aliases = []string{"rm"}
+	deleteCmd.Args = cobra.ExactArgs(1)
+	deleteCmd.AddBoolFlag(BoolFlagOpts{Name: "yes", Shorthand: "y", Description: "Accept all confirmations"})
+
+	testStrings := docstrings.Get("webhooks.test")
+	testCmd := BuildCommandKS(cmd, runWebhooksTest, testStrings, client, requireSession)
+	testCmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func addWebhookScopeFlags(cmd *Command) {
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "app",
+		Shorthand:   "a",
+		Description: "Only notify of this app's events",
+		EnvName:     "FLY_APP",
+	})
+	cmd.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Shorthand:   "o",
+		Description: "The organization to notify of events in, when no app is given",
+		EnvName:     "FLY_ORG",
+	})
+}
+
+// webhookScope - the organization of --app, or --org when no app is given
+func webhookScope(ctx *cmdctx.CmdContext) (*api.Organization, string, error) {
+	appName := ctx.Config.GetString("app")
+	if appName == "" {
+		org, err := selectOrganization(ctx.Client.API(), ctx.Config.GetString("org"), nil)
+		return org, "", err
+	}
+
+	app, err := ctx.Client.API().GetAppCompact(appName)
+	if err != nil {
+		return nil, "", err
+	}
+	return &app.Organization, app.Name, nil
+}
+
+func runWebhooksCreate(ctx *cmdctx.CmdContext) error {
+	endpoint := ctx.Config.GetString("url")
+	if endpoint == "" {
+		return &ValidationError{fmt.Errorf("--url is required")}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return &ValidationError{fmt.Errorf("invalid url %q, use an https URL", endpoint)}
+	}
+
+	events := ctx.Config.GetStringSlice("event")
+	for _, event := range events {
+		if !containsString(webhookEvents, event) {
+			return &ValidationError{fmt.Errorf("unknown event %q, use one of %s", event, strings.Join(webhookEvents, ", "))}
+		}
+	}
+
+	format := strings.ToLower(ctx.Config.GetString("payload"))
+	switch {
+	case format == "" && u.Host == "hooks.slack.com":
+		format = "slack"
+	case format == "":
+		format = "json"
+	case !containsString(webhookFormats, format):
+		return &ValidationError{fmt.Errorf("unknown payload %q, use one of %s", format, strings.Join(webhookFormats, ", "))}
+	}
+
+	org, appName, err := webhookScope(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := ctx.Client.API().CreateWebhook(api.CreateWebhookInput{
+		OrganizationID: org.ID,
+		AppID:          appName,
+		URL:            endpoint,
+		Events:         events,
+		Format:         format,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(webhook)
+	}
+
+	ctx.Statusf("webhooks", cmdctx.SDONE, "Notifying %s of %s events of %s (%s)\n", u.Host, strings.Join(webhook.Events, ", "), formatWebhookScope(webhook, org.Slug), webhook.ID)
+	ctx.Statusf("webhooks", cmdctx.SINFO, "Send a test notification with `flyctl webhooks test %s`\n", webhook.ID)
+
+	return nil
+}
+
+func formatWebhookScope(webhook *api.Webhook, orgSlug string) string {
+	if webhook.AppName != "" {
+		return webhook.AppName
+	}
+	return "every app in " + orgSlug
+}
+
+func runWebhooksList(ctx *cmdctx.CmdContext) error {
+	org, appName, err := webhookScope(ctx)
+	if err != nil {
+		return err
+	}
+
+	all, err := ctx.Client.API().GetWebhooks(org.Slug)
+	if err != nil {
+		return err
+	}
+
+	// an app is notified through its own webhooks and its organization's
+	webhooks := []api.Webhook{}
+	for _, w := range all {
+		if appName == "" || w.AppName == "" || w.AppName == appName {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(webhooks)
+	}
+
+	if len(webhooks) == 0 {
+		ctx.Statusf("webhooks", cmdctx.SINFO, "No webhooks, add one with `flyctl webhooks create`\n")
+		return nil
+	}
+
+	table := helpers.MakeSimpleTable(ctx.Out, []string{"ID", "URL", "Events", "App", "Payload", "Created"})
+	for _, w := range webhooks {
+		app := w.AppName
+		if app == "" {
+			app = "all"
+		}
+		table.Append([]string{w.ID, redactWebhookURL(w.URL), strings.Join(w.Events, ", "), app, w.Format, humanize.Time(w.CreatedAt)})
+	}
+	table.Render()
+
+	return nil
+}
+
+// redactWebhookURL - a webhook's URL without its path and query, which
+// often hold its secret, e.g. for Slack
+func redactWebhookURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Path == "" || u.Path == "/") && u.RawQuery == "" {
+		return endpoint
+	}
+	return fmt.Sprintf("%s://%s/...", u.Scheme, u.Host)
+}
+
+func runWebhooksDelete(ctx *cmdctx.CmdContext) error {
+	id := ctx.Args[0]
+
+	if !ctx.Config.GetBool("yes") {
+		if !confirm(fmt.Sprintf("Stop notifying webhook %s?", id), "yes") {
+			return nil
+		}
+	}
+
+	if err := ctx.Client.API().DeleteWebhook(id); err != nil {
+		return err
+	}
+
+	ctx.Statusf("webhooks", cmdctx.SDONE, "Deleted webhook %s\n", id)
+
+	return nil
+}
+
+func runWebhooksTest(ctx *cmdctx.CmdContext) error {
+	id := ctx.Args[0]
+
+	delivery, err := ctx.Client.API().TestWebhook(id)
+	if err != nil {
+		return err
+	}
+
+	if ctx.OutputStructured() {
+		return ctx.WriteData(delivery)
+	}
+
+	switch {
+	case delivery.Error != "":
+		return fmt.Errorf("failed to notify webhook %s: %s", id, delivery.Error)
+	case delivery.StatusCode < 200 || delivery.StatusCode > 299:
+		return fmt.Errorf("webhook %s responded with %d", id, delivery.StatusCode)
+	}
+
+	ctx.Statusf("webhooks", cmdctx.SDONE, "Notified webhook %s, it responded with %d in %dms\n", id, delivery.StatusCode, delivery.DurationMs)
+
+	return nil
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/client"
+)
+
+func TestWebhooksList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		data := map[string]interface{}{}
+		if strings.Contains(string(body), "webhooks") {
+			data["organization"] = map[string]interface{}{
+				"webhooks": map[string]interface{}{
+					"nodes": []map[string]interface{}{
+						{"id": "wh_org", "url": "https://example.com/all", "events": []string{"release"}},
+						{"id": "wh_web", "url": "https://example.com/web", "events": []string{"release"}, "appName": "web"},
+						{"id": "wh_api", "url": "https://example.com/api", "events": []string{"release"}, "appName": "api"},
+					},
+				},
+			}
+		} else {
+			data["appcompact"] = map[string]interface{}{"name": "web", "organization": map[string]string{"slug": "acme"}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	api.SetBaseURL(server.URL)
+	t.Cleanup(func() { api.SetBaseURL("") })
+	t.Setenv("FLY_ACCESS_TOKEN", "token")
+
+	viper.Set("webhookstest.app", "web")
+	t.Cleanup(func() { viper.Set("webhookstest.app", "") })
+
+	var out bytes.Buffer
+	ctx := &cmdctx.CmdContext{
+		Client:       client.NewClient(),
+		Config:       flyctl.ConfigNS("webhookstest"),
+		GlobalConfig: flyctl.ConfigNS("webhookstestglobal"),
+		Out:          &out,
+	}
+	if err := runWebhooksList(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := map[string]string{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 4 && strings.HasPrefix(fields[0], "wh_") {
+			rows[fields[0]] = fields[3]
+		}
+	}
+
+	want := map[string]string{"wh_org": "all", "wh_web": "web"}
+	if len(rows) != len(want) {
+		t.Fatalf("got webhooks %v, want %v in:\n%s", rows, want, out.String())
+	}
+	for id, app := range want {
+		if rows[id] != app {
+			t.Errorf("got app %q for %s, want %q", rows[id], id, app)
+		}
+	}
+}
+
+func TestRedactWebhookURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "https://hooks.slack.com/services/T000/B000/XXXX", want: "https://hooks.slack.com/..."},
+		{in: "https://example.com/hooks?token=secret", want: "https://example.com/..."},
+		{in: "https://example.com/?token=secret", want: "https://example.com/..."},
+		{in: "https://example.com", want: "https://example.com"},
+		{in: "https://example.com/", want: "https://example.com/"},
+		{in: "://not a url", want: "://not a url"},
+	}
+
+	for _, tt := range tests {
+		if got := redactWebhookURL(tt.in); got != tt.want {
+			t.Errorf("redactWebhookURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
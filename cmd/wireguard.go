@@ -18,6 +18,7 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/internal/wireguard"
 )
 
@@ -30,9 +31,26 @@ func newWireGuardCommand(client *client.Client) *Command {
 	}
 
 	child(cmd, runWireGuardList, "wireguard.list").Args = cobra.MaximumNArgs(1)
-	child(cmd, runWireGuardCreate, "wireguard.create").Args = cobra.MaximumNArgs(4)
+	create := child(cmd, runWireGuardCreate, "wireguard.create")
+	create.Args = cobra.MaximumNArgs(4)
+	create.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Description: "Gateway region for the peer, defaults to the one with the lowest latency",
+	})
+
 	child(cmd, runWireGuardRemove, "wireguard.remove").Args = cobra.MaximumNArgs(2)
 
+	relocate := child(cmd, runWireGuardRelocate, "wireguard.relocate")
+	relocate.Args = cobra.ExactArgs(1)
+	relocate.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Description: "Gateway region to move the peer to, defaults to the one with the lowest latency",
+	})
+	relocate.AddStringFlag(StringFlagOpts{
+		Name:        "org",
+		Description: "The organization the peer belongs to",
+	})
+
 	tokens := child(cmd, nil, "wireguard.token")
 
 	child(tokens, runWireGuardTokenList, "wireguard.token.list").Args = cobra.MaximumNArgs(1)
@@ -45,25 +63,25 @@ func newWireGuardCommand(client *client.Client) *Command {
 	return cmd
 }
 
-func argOrPromptImpl(ctx *cmdctx.CmdContext, nth int, prompt string, first bool) (string, error) {
+func argOrPromptImpl(ctx *cmdctx.CmdContext, nth int, message string, first bool) (string, error) {
 	if len(ctx.Args) >= (nth + 1) {
 		return ctx.Args[nth], nil
 	}
 
 	val := ""
-	err := survey.AskOne(&survey.Input{
-		Message: prompt,
-	}, &val)
+	err := prompt.Ask(&survey.Input{
+		Message: message,
+	}, &val, "")
 
 	return val, err
 }
 
-func argOrPromptLoop(ctx *cmdctx.CmdContext, nth int, prompt, last string) (string, error) {
-	return argOrPromptImpl(ctx, nth, prompt, last == "")
+func argOrPromptLoop(ctx *cmdctx.CmdContext, nth int, message, last string) (string, error) {
+	return argOrPromptImpl(ctx, nth, message, last == "")
 }
 
-func argOrPrompt(ctx *cmdctx.CmdContext, nth int, prompt string) (string, error) {
-	return argOrPromptImpl(ctx, nth, prompt, true)
+func argOrPrompt(ctx *cmdctx.CmdContext, nth int, message string) (string, error) {
+	return argOrPromptImpl(ctx, nth, message, true)
 }
 
 func orgByArg(ctx *cmdctx.CmdContext) (*api.Organization, error) {
@@ -94,9 +112,8 @@ func runWireGuardList(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(peers)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(peers)
 	}
 
 	table := tablewriter.NewWriter(ctx.Out)
@@ -207,6 +224,17 @@ func runWireGuardCreate(ctx *cmdctx.CmdContext) error {
 		name = ctx.Args[2]
 	}
 
+	if flagRegion := ctx.Config.GetString("region"); flagRegion != "" {
+		region = flagRegion
+	}
+
+	if region == "" {
+		region, err = selectGatewayRegion(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	state, err := wireguard.Create(ctx.Client.API(), org, region, name)
 	if err != nil {
 		return err
@@ -263,6 +291,63 @@ func runWireGuardRemove(ctx *cmdctx.CmdContext) error {
 	return wireguard.PruneInvalidPeers(ctx.Client.API())
 }
 
+func runWireGuardRelocate(ctx *cmdctx.CmdContext) error {
+	client := ctx.Client.API()
+	name := ctx.Args[0]
+
+	org, err := selectOrganization(client, ctx.Config.GetString("org"), nil)
+	if err != nil {
+		return err
+	}
+
+	region := ctx.Config.GetString("region")
+	if region == "" {
+		region, err = selectGatewayRegion(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx.Statusf("wireguard", cmdctx.SBEGIN, "Moving WireGuard peer \"%s\" for organization %s to region %s\n", name, org.Slug, region)
+
+	peer, err := wireguard.Relocate(client, org, name, region)
+	if err != nil {
+		return err
+	}
+
+	ctx.Statusf("wireguard", cmdctx.SDONE, "Moved peer. Keys and address are unchanged; point the peer's Endpoint at %s:51820\n", peer.Endpointip)
+
+	return nil
+}
+
+// selectGatewayRegion ranks the WireGuard gateways by their estimated
+// latency, shows the nearest few and returns the nearest
+func selectGatewayRegion(ctx *cmdctx.CmdContext) (string, error) {
+	region, latencies, err := wireguard.FastestGatewayRegion(ctx.Client.API())
+	if err != nil {
+		return "", err
+	}
+
+	if len(latencies) == 0 {
+		ctx.Statusf("wireguard", cmdctx.SINFO, "Using the nearest gateway region %s\n", region.Code)
+		return region.Code, nil
+	}
+
+	ctx.Status("wireguard", cmdctx.SINFO, "Estimated latency to WireGuard gateways:")
+	for i, l := range latencies {
+		if i == 5 {
+			break
+		}
+		marker := " "
+		if l.Region.Code == region.Code {
+			marker = "*"
+		}
+		ctx.Statusf("wireguard", cmdctx.SINFO, " %s %-4s %-24s ~%s\n", marker, l.Region.Code, l.Region.Name, l.Latency)
+	}
+
+	return region.Code, nil
+}
+
 func runWireGuardTokenList(ctx *cmdctx.CmdContext) error {
 	client := ctx.Client.API()
 
@@ -276,9 +361,8 @@ func runWireGuardTokenList(ctx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if ctx.OutputJSON() {
-		ctx.WriteJSON(tokens)
-		return nil
+	if ctx.OutputStructured() {
+		return ctx.WriteData(tokens)
 	}
 
 	table := tablewriter.NewWriter(ctx.Out)
@@ -14,6 +14,7 @@ import (
 	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/internal/client"
+	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/pkg/iostreams"
 )
 
@@ -36,7 +37,7 @@ type CmdContext struct {
 
 type PresenterOption struct {
 	Presentable presenters.Presentable
-	AsJSON      bool
+	Output      render.Options
 	Vertical    bool
 	HideHeader  bool
 	Title       string
@@ -79,7 +80,8 @@ func (commandContext *CmdContext) Render(presentable presenters.Presentable) err
 		Item: presentable,
 		Out:  os.Stdout,
 		Opts: presenters.Options{
-			AsJSON: commandContext.OutputJSON(),
+			Output: commandContext.OutputOptions(),
+			Quiet:  commandContext.Quiet(),
 		},
 	}
 
@@ -95,7 +97,8 @@ func (commandContext *CmdContext) render(out io.Writer, views ...PresenterOption
 				Vertical:   v.Vertical,
 				HideHeader: v.HideHeader,
 				Title:      v.Title,
-				AsJSON:     v.AsJSON,
+				Output:     v.Output,
+				Quiet:      commandContext.Quiet(),
 			},
 		}
 
@@ -109,10 +112,10 @@ func (commandContext *CmdContext) render(out io.Writer, views ...PresenterOption
 
 // Frender - render a view to a Writer
 func (commandContext *CmdContext) Frender(views ...PresenterOption) error {
-	// If JSON output wanted, set in all views
-	if commandContext.OutputJSON() {
+	// If structured output wanted, set in all views
+	if output := commandContext.OutputOptions(); output.Structured() {
 		for i := range views {
-			views[i].AsJSON = true
+			views[i].Output = output
 		}
 	}
 
@@ -124,9 +127,9 @@ func (commandContext *CmdContext) FrenderPrefix(prefix string, views ...Presente
 	// If JSON output wanted, set in all views
 	p := textio.NewPrefixWriter(commandContext.IO.Out, "    ")
 
-	if commandContext.OutputJSON() {
+	if output := commandContext.OutputOptions(); output.Structured() {
 		for i := range views {
-			views[i].AsJSON = true
+			views[i].Output = output
 		}
 	}
 
@@ -143,17 +146,21 @@ type JSON struct {
 func (commandContext *CmdContext) StatusLn() {
 	outputJSON := commandContext.OutputJSON()
 
-	if outputJSON {
+	if outputJSON || commandContext.Quiet() {
 		// Do nothing for JSON
 		return
 	}
 
-	fmt.Fprintln(commandContext.IO.Out)
+	fmt.Fprintln(commandContext.statusOut())
 }
 
 func (commandContext *CmdContext) Status(source string, status string, args ...interface{}) {
 	outputJSON := commandContext.OutputJSON()
 
+	if commandContext.suppressed(status) {
+		return
+	}
+
 	var message strings.Builder
 
 	for i, v := range args {
@@ -172,7 +179,7 @@ func (commandContext *CmdContext) Status(source string, status string, args ...i
 		fmt.Fprintln(commandContext.IO.Out, string(outbuf))
 		return
 	} else {
-		fmt.Fprintln(commandContext.IO.Out, statusToEffect(status, message.String()))
+		fmt.Fprintln(commandContext.statusOut(), statusToEffect(status, message.String()))
 	}
 }
 
@@ -200,6 +207,10 @@ func statusToEffect(status string, message string) string {
 func (commandContext *CmdContext) Statusf(source string, status string, format string, args ...interface{}) {
 	outputJSON := commandContext.OutputJSON()
 
+	if commandContext.suppressed(status) {
+		return
+	}
+
 	message := fmt.Sprintf(format, args...)
 
 	if outputJSON {
@@ -210,15 +221,77 @@ func (commandContext *CmdContext) Statusf(source string, status string, format s
 		fmt.Fprintln(commandContext.IO.Out, string(outbuf))
 		return
 	} else {
-		fmt.Fprint(commandContext.IO.Out, statusToEffect(status, message))
+		fmt.Fprint(commandContext.statusOut(), statusToEffect(status, message))
 	}
 }
 
-func (commandContext *CmdContext) WriteJSON(myData interface{}) {
-	outBuf, _ := json.MarshalIndent(myData, "", "    ")
-	fmt.Fprintln(commandContext.IO.Out, string(outBuf))
+// Quiet - true when --quiet was given and only errors and requested data
+// should be written
+func (commandContext *CmdContext) Quiet() bool {
+	return commandContext.GlobalConfig.GetBool(flyctl.ConfigQuietOutput)
+}
+
+// Verbose - true when --verbose was given
+func (commandContext *CmdContext) Verbose() bool {
+	return commandContext.GlobalConfig.GetBool(flyctl.ConfigVerboseOutput)
+}
+
+// suppressed - whether a status message should be dropped. Quiet mode keeps
+// only warnings and errors.
+func (commandContext *CmdContext) suppressed(status string) bool {
+	if !commandContext.Quiet() {
+		return false
+	}
+	return status != SERROR && status != SWARN
+}
+
+// statusOut - where status messages go. They're moved to stderr when YAML or
+// template output is selected so they don't end up mixed into the data.
+func (commandContext *CmdContext) statusOut() io.Writer {
+	if commandContext.OutputStructured() {
+		return commandContext.IO.ErrOut
+	}
+	return commandContext.IO.Out
 }
 
+// WriteData - write data in the selected output format, defaulting to JSON
+func (commandContext *CmdContext) WriteData(data interface{}) error {
+	output := commandContext.OutputOptions()
+	if !output.Structured() {
+		output.Format = render.FormatJSON
+	}
+
+	return render.Data(commandContext.IO.Out, output, data)
+}
+
+// OutputOptions - the output format selected with --json, --yaml or --format.
+// --format prometheus selects metrics output rather than a template.
+func (commandContext *CmdContext) OutputOptions() render.Options {
+	if tmpl := commandContext.GlobalConfig.GetString(flyctl.ConfigOutputFormat); tmpl != "" {
+		if tmpl == string(render.FormatPrometheus) {
+			return render.Options{Format: render.FormatPrometheus}
+		}
+		return render.Options{Format: render.FormatTemplate, Template: tmpl}
+	}
+
+	if commandContext.GlobalConfig.GetBool(flyctl.ConfigYAMLOutput) {
+		return render.Options{Format: render.FormatYAML}
+	}
+
+	if commandContext.GlobalConfig.GetBool(flyctl.ConfigJSONOutput) {
+		return render.Options{Format: render.FormatJSON}
+	}
+
+	return render.Options{}
+}
+
+// OutputJSON - whether JSON output was selected. Status messages are written
+// as JSON lines in this mode.
 func (commandContext *CmdContext) OutputJSON() bool {
-	return commandContext.GlobalConfig.GetBool(flyctl.ConfigJSONOutput)
+	return commandContext.OutputOptions().Format == render.FormatJSON
+}
+
+// OutputStructured - whether any machine readable output format was selected
+func (commandContext *CmdContext) OutputStructured() bool {
+	return commandContext.OutputOptions().Structured()
 }
@@ -5,7 +5,14 @@ func Get(key string) KeyStrings {
 	switch key {
 	case "agent":
 		return KeyStrings{"agent <command>", "Commands that manage the Fly agent",
-			`Commands that manage the Fly agent`,
+			`Commands that manage the Fly agent, which keeps the WireGuard
+connections to private networks used by commands like ssh.
+
+When a command needs the agent and it isn't running, flyctl offers to
+start it on a terminal, and starts it without asking otherwise, e.g. in
+scripts and CI. Answering to start it automatically saves agent_auto_start
+to the flyctl config so you aren't asked again. Set FLY_AGENT_AUTO_START
+to true to start it without asking.`,
 		}
 	case "agent.daemon-start":
 		return KeyStrings{"daemon-start", "Run the Fly agent as a service (manually)",
@@ -29,34 +36,86 @@ func Get(key string) KeyStrings {
 Start with the CREATE command to register your application.
 The LIST command will list all currently registered applications.`,
 		}
+	case "apps.clone":
+		return KeyStrings{"clone <SOURCE> <DEST>", "Create a copy of an existing application",
+			`The APPS CLONE command will create a new application with the same
+regions, VM size and instance count of each process group as an existing one,
+in the same or another organization. The source app's configuration is written
+to fly.<DEST>.toml so the copy can be deployed with 'deploy -c'. Secrets are only
+copied when --copy-secrets is given and confirmed.`,
+		}
 	case "apps.create":
 		return KeyStrings{"create [APPNAME]", "Create a new application",
 			`The APPS CREATE command will both register a new application 
 with the Fly platform and create the fly.toml file which controls how 
 the application will be deployed. The --builder flag allows a cloud native 
 buildpack to be specified which will be used instead of a Dockerfile to 
-create the application image when it is deployed.`,
+create the application image when it is deployed.
+
+With --network, the app is placed in a separate private network of its
+organization and can only reach apps in the same network over 6PN.`,
 		}
 	case "apps.destroy":
 		return KeyStrings{"destroy [APPNAME]", "Permanently destroys an app",
-			`The APPS DESTROY command will remove an application 
-from the Fly platform.`,
+			`The APPS DESTROY command will remove an application
+from the Fly platform. With --org the app is only destroyed if it belongs to
+that organization.`,
+		}
+	case "apps.errors":
+		return KeyStrings{"errors [APPNAME]", "Summarize an app's recent crashes by cause",
+			`Group the crashes of an app's instances over the last --window, 24h by
+default, by cause: OOM kills, non-zero exit codes, restart loops and
+failures without an exit event. Each cause shows how often it happened,
+on which instances and in which regions, and the latest message.`,
+		}
+	case "apps.fork":
+		return KeyStrings{"fork <APPNAME>", "Create a short lived copy of an app, e.g. for a pull request preview",
+			`The APPS FORK command will create a copy of an application with the
+same regions and scale, named after --name or <APPNAME>-pr-<PR> when --pr is
+given. The fork expires after --ttl and can then be destroyed with APPS REAP.
+Forking an app that was already forked from the same source just extends its
+expiry, so the command is safe to run on every CI build.`,
 		}
 	case "apps.list":
 		return KeyStrings{"list", "List applications",
 			`The APPS LIST command will show the applications currently
 registered and available to this user. The list will include applications 
 from all the organizations the user is a member of. Each application will 
-be shown with its name, owner and when it was last deployed.`,
+be shown with its name, owner and when it was last deployed.
+
+Use --all-orgs to fetch every app of each organization separately, in
+parallel, rather than the first 400 apps. Use --group-by org to show a
+section for each organization, with its app count and how many apps are in
+each status.
+
+Use --wide to add each app's description, owner team, repository and on-call
+contact, set with 'apps set-metadata'.`,
 		}
 	case "apps.move":
 		return KeyStrings{"move [APPNAME]", "Move an app to another organization",
 			`The APPS MOVE command will move an application to another 
-organization the current user belongs to.`,
+organization the current user belongs to. IP addresses move with the app.
+Volumes are snapshotted before the move and, with --recreate-volumes, restored
+from those snapshots in the destination organization. Without it, the
+commands that restore them are printed after the move.`,
+		}
+	case "apps.reap":
+		return KeyStrings{"reap", "Destroy forked apps which have expired",
+			`The APPS REAP command will destroy every app created with APPS FORK
+whose time to live has passed. Use --dry-run to list them without destroying.`,
+		}
+	case "apps.rename":
+		return KeyStrings{"rename <APPNAME> <NEWNAME>", "Rename an application",
+			`The APPS RENAME command will rename an application, keeping its
+releases, secrets, volumes and certificates. The app's fly.dev hostname changes
+with the name, so DNS records pointing at the old hostname will need updating.
+If the local fly.toml refers to the old name, it can be rewritten in place.`,
 		}
 	case "apps.restart":
 		return KeyStrings{"restart [APPNAME]", "Restart an application",
-			`The APPS RESTART command will restart all running vms.`,
+			`The APPS RESTART command will restart all running vms.
+
+With --select, choose any number of your apps from a list and restart each.`,
 		}
 	case "apps.resume":
 		return KeyStrings{"resume [APPNAME]", "Resume an application",
@@ -65,6 +124,23 @@ The application will resume with its original region pool and a min count of one
 meaning there will be one running instance once restarted. Use SCALE SET MIN= to raise
 the number of configured instances.`,
 		}
+	case "apps.set-description":
+		return KeyStrings{"set-description <DESCRIPTION>", "Describe what an app is for",
+			`Save a description of the app, shown by status and 'apps list --wide', to
+tell apps with similar names apart. An empty description, "", removes it.
+The same as 'apps set-metadata description=<DESCRIPTION>'.`,
+		}
+	case "apps.set-metadata":
+		return KeyStrings{"set-metadata KEY=VALUE ...", "Annotate an app with its team, repository and on-call contact",
+			`Annotate the app with who owns it and where to find them, as
+description=TEXT, owner=TEAM, repository=URL and oncall=CONTACT, e.g.
+
+  flyctl apps set-metadata owner=payments repository=https://github.com/acme/web
+
+An empty value, like oncall=, removes it. The annotations are shown by
+status and 'apps list --wide', and with --json are in the Metadata of both,
+keyed fly.description, fly.owner_team, fly.repository and fly.oncall.`,
+		}
 	case "apps.suspend":
 		return KeyStrings{"suspend [APPNAME]", "Suspend an application",
 			`The APPS SUSPEND command will suspend an application. 
@@ -84,6 +160,19 @@ If you do have and account, begin with the AUTH LOGIN subcommand.`,
 registries. This allows you to push images directly to fly from 
 the docker cli.`,
 		}
+	case "auth.impersonate":
+		return KeyStrings{"impersonate [<email>]", "Act as an organization member to debug their permissions",
+			`For organization admins, run commands as a member would, with their
+role, to find out why something isn't permitted. Commands aren't
+simulated: anything the member's role permits takes effect, as if they had
+run it, so stick to commands that only read while impersonating.
+
+Choose the organization with --org and give a --reason, which is recorded
+in the organization's audit log along with every command run while
+impersonating. Sessions end after --duration, 1h by default, or when
+'flyctl auth impersonate --stop' is run. Every command shows a banner
+while impersonating.`,
+		}
 	case "auth.login":
 		return KeyStrings{"login", "Log in a user",
 			`Logs a user into the Fly platform. Supports browser-based, 
@@ -100,6 +189,29 @@ To continue interacting with Fly, the user will need to log in again.`,
 			`Creates a new fly account. The command opens the browser 
 and sends the user to a form to provide appropriate credentials.`,
 		}
+	case "auth.ssh-keys":
+		return KeyStrings{"ssh-keys", "Manage the public SSH keys on your account",
+			`Commands that list, add and remove the public SSH keys on your account.
+Where the platform supports it, these keys are accepted for SSH access to
+your VMs alongside the certificates issued by 'flyctl ssh issue'.`,
+		}
+	case "auth.ssh-keys.add":
+		return KeyStrings{"add [<public-key-file>]", "Add public SSH keys to your account",
+			`Add the public keys in a file, such as ~/.ssh/id_ed25519.pub or an
+authorized_keys file, or - to read them from stdin. Use --from-github to
+import the keys a GitHub user has published. Keys already on your account
+are skipped.`,
+		}
+	case "auth.ssh-keys.list":
+		return KeyStrings{"list", "List your SSH keys and their fingerprints",
+			`List the public SSH keys on your account with their SHA256
+fingerprints, as 'ssh-keygen -l' shows them.`,
+		}
+	case "auth.ssh-keys.remove":
+		return KeyStrings{"remove <id|name|fingerprint>", "Remove an SSH key from your account",
+			`Remove a public SSH key from your account, chosen by its ID, name or
+fingerprint.`,
+		}
 	case "auth.token":
 		return KeyStrings{"token", "Show the current auth token",
 			`Shows the authentication token that is currently in use. 
@@ -176,6 +288,16 @@ the builtin "Dockerfile" with default settings and other information.`,
 the builtin "Dockerfile" with an apps settings included
 and other information.`,
 		}
+	case "cache":
+		return KeyStrings{"cache", "Manage cached API responses",
+			`flyctl caches organization lists for 5 minutes, and region and VM size
+lists for an hour, so commands that look them up repeatedly stay fast.`,
+		}
+	case "cache.clear":
+		return KeyStrings{"clear", "Clear cached API responses",
+			`Remove every cached API response, so the next lookups fetch
+fresh data.`,
+		}
 	case "certs":
 		return KeyStrings{"certs", "Manage certificates",
 			`Manages the certificates associated with a deployed application. 
@@ -213,32 +335,99 @@ Takes hostname as a parameter to locate the certificate.`,
 		}
 	case "checks.handlers":
 		return KeyStrings{"handlers", "Manage health check handlers",
-			`Manage health check handlers`,
+			`Manage the handlers that are notified when an app's health checks
+fail. Handlers belong to an organization and can post to Slack or page
+someone through PagerDuty.`,
 		}
 	case "checks.handlers.create":
 		return KeyStrings{"create", "Create a health check handler",
-			`Create a health check handler`,
+			`Create a Slack or PagerDuty health check handler for an organization.
+Slack handlers need an incoming webhook URL and optionally a channel,
+PagerDuty handlers need an integration key. Missing values are prompted for.`,
 		}
 	case "checks.handlers.delete":
 		return KeyStrings{"delete <organization> <handler-name>", "Delete a health check handler",
-			`Delete a health check handler`,
+			`Delete a health check handler from an organization. Use --yes to
+skip the confirmation.`,
 		}
 	case "checks.handlers.list":
-		return KeyStrings{"list", "List health check handlers",
-			`List health check handlers`,
+		return KeyStrings{"list [<organization>]", "List health check handlers",
+			`List the health check handlers of an organization, prompting for the
+organization when it isn't given.`,
 		}
 	case "checks.list":
 		return KeyStrings{"list", "List app health checks",
-			`List app health checks`,
+			`List the current status of the app's health checks, grouped by
+allocation. Use --check-name to only list checks with that name, and --json
+for machine readable output.`,
+		}
+	case "checks.wait":
+		return KeyStrings{"wait", "Wait for an app's health checks to pass",
+			`Wait until every health check of the app is passing, for example as a
+step in a CI pipeline after 'flyctl deploy --detach'. Gives up with an
+error, listing the checks that aren't passing, after --timeout (5m by
+default). Use --check-name to only wait for checks with that name. An app
+without health checks, or none with that name, has nothing to wait for and
+succeeds right away.`,
+		}
+	case "completion":
+		return KeyStrings{"completion <bash|zsh|fish|powershell>", "Generate a shell completion script",
+			`Print a script that completes flyctl commands and flags in your shell. Besides
+commands and flags, -a completes your apps' names, --org your organizations,
+--region region codes, and secrets unset the app's secret names. The names are
+fetched from the API and cached for a minute.
+
+Load it in every session, e.g. for bash:
+
+  echo 'source <(flyctl completion bash)' >> ~/.bashrc
+
+for zsh, with compinit enabled:
+
+  flyctl completion zsh > "${fpath[1]}/_flyctl"
+
+for fish:
+
+  flyctl completion fish > ~/.config/fish/completions/flyctl.fish`,
 		}
 	case "config":
 		return KeyStrings{"config", "Manage an app's configuration",
-			`The CONFIG commands allow you to work with an application's configuration.`,
+			`The CONFIG commands allow you to work with an application's configuration.
+
+A fly.toml can share settings with others by naming them in include, a
+path or list of paths relative to it. Included files are loaded first and
+the including file's settings override theirs, table by table:
+
+  include = "fly.base.toml"
+
+Settings that differ between deployments of the same app, such as staging
+and production, can go in [environments], each table overriding the rest
+of the config when it's chosen with --environment or FLY_ENVIRONMENT:
+
+  app = "myapp"
+
+  [environments.staging]
+    app = "myapp-staging"
+    primary_region = "lhr"
+
+    [environments.staging.env]
+      LOG_LEVEL = "debug"
+
+Pass --config to use a different config file, e.g. --config fly.staging.toml.
+
+String values can refer to environment variables as ${NAME}, or
+${NAME:-default} to fall back when NAME isn't set. A variable that isn't
+set and has no default is an error. Write $${ for a literal ${.`,
 		}
 	case "config.display":
 		return KeyStrings{"display", "Display an app's configuration",
 			`Display an application's configuration. The configuration is presented 
-in JSON format. The configuration data is retrieved from the Fly service.`,
+in JSON format. The configuration data is retrieved from the Fly service.
+
+With --resolved, the local config file is displayed instead, with the
+templates in its env values resolved as deploy resolves them, e.g.
+CANONICAL_HOST = "{{ .AppName }}.fly.dev" or PRIMARY_REGION = "{{ .Region }}".
+Templates are only resolved with env_templates = true in the [deploy] section
+of fly.toml; otherwise env values are used as they are.`,
 		}
 	case "config.env":
 		return KeyStrings{"env", "Display an app's runtime environment variables",
@@ -250,14 +439,99 @@ secrets and another for config file defined environment variables.`,
 			`Save an application's configuration locally. The configuration data is 
 retrieved from the Fly service and saved in TOML format.`,
 		}
+	case "config.schema":
+		return KeyStrings{"schema", "Print the JSON schema for fly.toml",
+			`Print the JSON schema flyctl uses to validate fly.toml, for editors
+and other tools that offer completion and checking of config files.`,
+		}
+	case "config.set":
+		return KeyStrings{"set KEY=VALUE ...", "Set keys in the app config file",
+			`Set keys in the app config file, leaving its comments and ordering
+as they are. Keys are dotted paths to a setting:
+
+  fly config set env.LOG_LEVEL=debug services.internal_port=3000
+
+When an app has several [[services]], or other lists of tables, choose one
+by position, e.g. services[1].internal_port. Values that aren't valid TOML,
+and values replacing a string, are written as strings. Missing tables are
+added at the end of the file. Nothing is written unless every change
+succeeds.`,
+		}
+	case "config.unset":
+		return KeyStrings{"unset KEY ...", "Remove keys from the app config file",
+			`Remove keys from the app config file, leaving its comments and
+ordering as they are. Keys are dotted paths as for config set:
+
+  fly config unset env.LOG_LEVEL`,
+		}
 	case "config.validate":
 		return KeyStrings{"validate", "Validate an app's config file",
 			`Validates an application's config file against the Fly platform to 
-ensure it is correct and meaningful to the platform.`,
+ensure it is correct and meaningful to the platform. 
+
+Use --local to check the file against the schema bundled with flyctl instead.
+This works offline, without logging in or the app existing, but can't catch
+problems only the platform knows about. Settings the schema doesn't know are
+allowed, with a warning in case they're typos.`,
+		}
+	case "consul":
+		return KeyStrings{"consul", "Attach Consul to an app for leader election",
+			`Attach and detach a Consul cluster, run by Fly, for an app to hold leases
+and elect leaders with. LiteFS uses it to pick the primary of a replicated
+SQLite database.`,
+		}
+	case "consul.attach":
+		return KeyStrings{"attach", "Attach Consul to an app",
+			`Provision a Consul URL for the app, with a token scoped to its keys, and
+set it as the FLY_CONSUL_URL secret, or the one given with --variable-name.
+Point LiteFS at it in litefs.yml:
+
+  lease:
+    type: "consul"
+    consul:
+      url: "${FLY_CONSUL_URL}"
+
+Attaching again sets the same URL. launch offers to attach Consul when it
+finds a litefs.yml.`,
+		}
+	case "consul.detach":
+		return KeyStrings{"detach", "Detach Consul from an app",
+			`Revoke the app's Consul URL, deleting its keys, and unset the secret it
+was set as. LiteFS can't elect a primary until Consul is attached again.`,
+		}
+	case "context":
+		return KeyStrings{"context", "Select the app, organization and environment of a project",
+			`Select the app, organization and environment commands use when run inside a
+project, so they don't need -a, --org or --environment. The selection is kept
+in .fly/context.toml in the project's directory, and applies in its
+subdirectories too.
+
+The flags and FLY_APP, FLY_ORG and FLY_ENVIRONMENT still override it, and
+the app in fly.toml takes precedence over one selected this way. The search
+for .fly/context.toml stops below the home directory, whose .fly is flyctl's
+own. Add .fly/ to .gitignore to keep the selection to yourself.`,
+		}
+	case "context.set":
+		return KeyStrings{"set KEY=VALUE ...", "Select an app, organization or environment",
+			`Select the project's app, organization or environment, given as app=NAME,
+org=SLUG and environment=NAME. An empty value, like app=, clears it. The
+selection is saved in the project's existing .fly directory, or in one
+created in the working directory.`,
+		}
+	case "context.show":
+		return KeyStrings{"show", "Show the project's selection",
+			`Show the app, organization and environment selected for the project, and
+the file they're kept in.`,
 		}
 	case "curl":
-		return KeyStrings{"curl <url>", "Run a performance test against a url",
-			`Run a performance test against a url.`,
+		return KeyStrings{"curl <url>", "Time a request to a url from Fly regions",
+			`Send an HTTP request to a url from Fly regions and show the status,
+DNS, connect, TLS, time to first byte and total time from each, fastest
+first. Use it to see which regions are close to a service before setting an
+app's region pool.
+
+Requests are sent from every region, or only those given with --regions, or
+chosen from a list with --select.`,
 		}
 	case "dashboard":
 		return KeyStrings{"dashboard", "Open web browser on Fly Web UI for this app",
@@ -276,15 +550,95 @@ Use the --config/-c flag to select a specific toml configuration file.
 
 Use the --image/-i flag to specify a local or remote image to deploy.
 
-Use the --detach flag to return immediately from starting the deployment rather
-than monitoring the deployment progress.
+Use the --detach flag to return as soon as the release is created rather
+than monitoring the deployment progress, e.g. in CI jobs with tight timeouts.
+It prints the release's ID; reattach to the rollout later with
+'flyctl releases watch <id>'.
+
+Use --env NAME=VALUE, as many times as needed, or --env-file with a file of
+NAME=VALUE lines to add environment variables to this release only, for
+example to pass a version or commit. They're merged over the [env] section of
+fly.toml, which is left unchanged, and --env wins over --env-file. As in a
+.env file, quotes around values are removed and lines may start with export.
+
+Use --dry-run to see what a deploy would change before releasing it. The
+image is built and the config validated, then the image and every setting that
+differs from the current release are listed, and no release is created. Add
+--skip-build to only diff the config. An --image given with it isn't
+resolved, so it's listed as not compared, as are process group images.
+
+In GitHub Actions, use --github-status to record the deploy as a GitHub
+deployment of the workflow's commit, to an environment named after the app.
+Its status follows the deploy, and build, release and health check failures
+are annotated on the workflow run. The job has to pass GITHUB_TOKEN in its
+environment and be granted the deployments: write permission.
+
+Use --message to describe the release and --label KEY=VALUE, as many times as
+needed, to annotate it. Both are shown by flyctl releases and included in its
+JSON output.
+
+While monitoring in a terminal, a table of the new version's instances and
+those of the version being replaced is kept up to date, with their status,
+health checks and restarts. A summary is shown once the deployment finishes,
+and flyctl exits with an error if it failed.
+
+If the connection drops while monitoring, flyctl reconnects and picks up where
+it left off. Use --monitor-timeout to set how long it keeps trying before
+fetching the deployment's final status instead.
+
+Apps that are slow to boot, like JVM apps or ones loading ML models, can be
+given longer to become healthy. Use --wait-timeout to set how long instances
+get to pass their health checks before the deployment fails, and
+--health-check-grace to override the grace period of every health check for
+this deployment. Keep them as defaults in fly.toml instead, as wait_timeout and
+health_check_grace in the [deploy] section; the flags win.
+
+With env_templates = true in the [deploy] section, env values in fly.toml
+can use {{ .AppName }} and {{ .Region }}, the app's primary region, which
+are resolved when deploying.
+
+With --watch-window, e.g. --watch-window 30s, flyctl keeps watching a release
+once it's healthy and fails the deploy, showing the affected instances' events
+and logs, if they start crash looping or their health checks flap.
+
+After building on a remote builder, flyctl shows how long the builder took to
+be ready and how much disk its image layers and build cache use. With --json these are included in the build summary line.
+
+Process groups declared under [build.processes.<group>] in fly.toml are built
+as images of their own, in parallel, alongside the app's default image. Use the
+--process-group flag to build and deploy a single group's image, optionally
+with --dockerfile.
+
+With --guard, or guard = true in the [deploy] section of fly.toml, flyctl
+checks the app before deploying and refuses to deploy over a degraded release:
+when more of its health checks are critical than error_budget allows (a
+fraction, default 0), or instances failed or restarted repeatedly within
+guard_window (default 15m). It asks for confirmation in a terminal; use
+--force to deploy anyway.
+
+With migration_lock = true in the [deploy] section of fly.toml, flyctl takes
+the app's migration lock before creating a release with a release_command and
+holds it until the command finishes, so deploys from concurrent CI jobs can't
+run migrations at the same time. A deploy that finds the lock held shows who
+holds it (user, host and CI job) and waits up to migration_lock_timeout
+(default 10m) before failing. With --detach, flyctl still waits for the
+release command before releasing the lock and returning. If flyctl stops
+before seeing the release command finish, it leaves the lock in place since
+the command may still be running. Locks left behind expire after an hour.
+
+With --prewarm, the images are pulled onto hosts in each of the app's regions
+before the release starts replacing instances, showing each region's progress.
+This shortens the gap while very large images are pulled region by region.
+Regions that fail to prewarm pull the image as usual, and after
+--prewarm-timeout (default 10m) the deploy carries on regardless.
 
 Use flyctl monitor to restart monitoring deployment progress`,
 		}
 	case "destroy":
 		return KeyStrings{"destroy [APPNAME]", "Permanently destroys an app",
-			`The DESTROY command will remove an application 
-from the Fly platform.`,
+			`The DESTROY command will remove an application
+from the Fly platform. With --org the app is only destroyed if it belongs to
+that organization.`,
 		}
 	case "dns-records":
 		return KeyStrings{"dns-records", "Manage DNS records",
@@ -307,7 +661,22 @@ imports from StdIn.`,
 	case "docs":
 		return KeyStrings{"docs", "View Fly documentation",
 			`View Fly documentation on the Fly.io website. This command will open a 
-browser to view the content.`,
+browser to view the content.
+
+Without a network connection, use 'flyctl help <command>' and
+'flyctl help <topic>', or generate flyctl's reference as man pages or
+markdown with 'flyctl docs generate'.`,
+		}
+	case "docs.generate":
+		return KeyStrings{"generate", "Generate flyctl's reference as man pages or markdown",
+			`Write a page for every flyctl command to a directory, as man pages with --man
+or as markdown with --markdown. The pages cover the same commands, flags and
+help as 'flyctl help', for the installed version.
+
+To read the man pages, e.g.:
+
+  flyctl docs generate --man --dir ~/.local/share/man/man1
+  man flyctl-deploy`,
 		}
 	case "domains":
 		return KeyStrings{"domains", "Manage domains",
@@ -317,6 +686,25 @@ browser to view the content.`,
 		return KeyStrings{"add [org] [name]", "Add a domain",
 			`Add a domain to an organization`,
 		}
+	case "domains.autorenew":
+		return KeyStrings{"autorenew <command>", "Manage automatic renewal of registered domains",
+			`Enable or disable automatic renewal of a domain registered through Fly`,
+		}
+	case "domains.autorenew.disable":
+		return KeyStrings{"disable <domain>", "Disable automatic renewal",
+			`Stop renewing the domain automatically. The registration will lapse
+at its expiry date unless renewal is enabled again.`,
+		}
+	case "domains.autorenew.enable":
+		return KeyStrings{"enable <domain>", "Enable automatic renewal",
+			`Renew the domain automatically before its registration expires`,
+		}
+	case "domains.expiring":
+		return KeyStrings{"expiring [<org>]", "List domains with registrations expiring soon",
+			`List registered domains in an organization whose registration expires
+within the window given by --within (default 30d), along with their
+auto renew setting.`,
+		}
 	case "domains.list":
 		return KeyStrings{"list [<org>]", "List domains",
 			`List domains for an organization`,
@@ -329,6 +717,61 @@ browser to view the content.`,
 		return KeyStrings{"show <domain>", "Show domain",
 			`Show information about a domain`,
 		}
+	case "domains.zones":
+		return KeyStrings{"zones", "Manage DNS zones hosted by Fly",
+			`Manage DNS zones hosted by Fly`,
+		}
+	case "domains.zones.import":
+		return KeyStrings{"import <org> <domain>", "Copy a zone from another DNS provider to Fly",
+			`Copy a domain's DNS zone from the provider hosting it now to Fly.
+The records are read from the provider, the zone is created in the
+organization if it doesn't exist and the records are copied into it. Then
+the steps to switch the domain's nameservers are shown.
+
+--from-provider chooses the provider:
+
+  cloudflare  needs an API token with DNS read access, from --token or
+              CLOUDFLARE_API_TOKEN
+  route53     needs AWS credentials from AWS_ACCESS_KEY_ID and
+              AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN if temporary
+
+SOA and the zone's own NS records aren't copied, Fly serves its own. Records
+that can't be copied, such as Route 53 aliases, are listed as warnings.
+
+Use --watch to wait, for up to --watch-timeout (default 1h), until public DNS
+shows the domain using Fly's nameservers.`,
+		}
+	case "events":
+		return KeyStrings{"events", "Show an app's lifecycle events",
+			`Show the recent lifecycle events of an app's instances: starts, stops,
+restarts and OOM kills.
+
+With --follow, events are streamed as they happen from the platform's event
+stream, over the organization's private network, along with deploys and
+health check transitions. Filter
+them with --type and --region. With --json, each event is written as a JSON
+line as it arrives, ready to feed dashboards and incident timelines.`,
+		}
+	case "extensions":
+		return KeyStrings{"extensions", "Provision and manage extensions from partners",
+			`Provision services from partners, like error tracking, for an app. The
+credentials an extension needs are set as the app's secrets.`,
+		}
+	case "extensions.list":
+		return KeyStrings{"list", "List an app's extensions",
+			`List the extensions provisioned for an app`,
+		}
+	case "extensions.sentry":
+		return KeyStrings{"sentry", "Error tracking with Sentry",
+			`Provision Sentry projects to track an app's errors`,
+		}
+	case "extensions.sentry.create":
+		return KeyStrings{"create", "Create a Sentry project for an app",
+			`Create a Sentry project for an app and set its DSN as the app's
+SENTRY_DSN secret, which Sentry's SDKs read without further configuration.
+Use --platform to tailor the project's setup instructions to the app's
+language or framework.`,
+		}
 	case "flyctl":
 		return KeyStrings{"flyctl", "The Fly CLI",
 			`flyctl is a command line interface to the Fly.io platform.
@@ -337,12 +780,63 @@ It allows users to manage authentication, application initialization,
 deployment, network configuration, logging and more with just the 
 one command.
 
-Initialize an app with the init command
+Initialize an app with the launch command
 Deploy an app with the deploy command
 View a deployed web application with the open command
 Check the status of an application with the status command
 
-To read more, use the docs command to view Fly's help on the web.`,
+To read more, use the docs command to view Fly's help on the web, or read
+about environment variables and flyctl's settings offline with
+'flyctl help environment' and 'flyctl help config-file'.
+
+Use --quiet/-q to show only errors and the data a command was asked for,
+leaving out spinners, progress messages, table titles and update notices, so
+flyctl's output can be used in scripts and Makefiles. Or use --verbose/-v to
+also show how long each API request took.
+
+To report API problems, --debug-http (or FLY_DEBUG=http) traces every API
+request and response to stderr. Add --debug-http-bodies to include their
+headers and bodies, with tokens, credentials and secret values redacted, or
+--debug-http-file to write the trace to a file.
+
+Deprecated commands and flags warn when they're used, naming what to use
+instead and the version they'll be removed in. With --json the warnings
+are written to stderr as JSON lines. They're left out of help unless
+--show-deprecated is given.
+
+On Windows, colors are turned on in consoles that support them, and symbols
+the console can't draw are replaced with ASCII. Use --ascii, or FLY_ASCII=1,
+to force plain ASCII output without colors or symbols anywhere.
+
+API queries that hit server errors, rate limits or network timeouts are
+retried with backoff. Changes, like deploys, are only retried when the API
+refused the connection or asked to retry later, so they never run twice. Set
+api_max_retries (default 3) and api_retry_max_delay (default 5s) in
+~/.fly/config.yml to tune this.
+
+Exit codes:
+  0    success
+  1    unclassified error
+  2    invalid arguments, flags or app configuration, or a prompt that
+       couldn't be shown when running non-interactively
+  3    not logged in or access token rejected
+  4    app or resource not found
+  5    deployment failed
+  130  aborted by the user`,
+		}
+	case "help":
+		return KeyStrings{"help [command | topic]", "Help about any command or topic",
+			`Show the help of a command, e.g. 'flyctl help deploy' or
+'flyctl help scale count', or of a topic, which covers what isn't a single
+command. Everything is built into flyctl, so it works offline.
+
+Topics:
+
+  aliases        Your own names for commands, defined in config.yml
+  config-file    flyctl's own settings in ~/.fly/config.yml
+  environment    Environment variables flyctl reads
+
+With anything else, the commands and topics that mention it are listed.`,
 		}
 	case "history":
 		return KeyStrings{"history", "List an app's change history",
@@ -356,7 +850,13 @@ events and their results.`,
 Information includes the application's
 * name, owner, version, status and hostname
 * services
-* IP addresses`,
+* IP addresses
+* regions and backup regions
+* VM size and count, per process group
+* volumes
+* certificates and their status
+
+Use --json to get all of it as a single object.`,
 		}
 	case "init":
 		return KeyStrings{"init [APPNAME]", "Initialize a new application",
@@ -364,7 +864,10 @@ Information includes the application's
 with the Fly platform and create the fly.toml file which controls how 
 the application will be deployed. The --builder flag allows a cloud native 
 buildpack to be specified which will be used instead of a Dockerfile to 
-create the application image when it is deployed.`,
+create the application image when it is deployed.
+
+With --network, the app is placed in a separate private network of its
+organization and can only reach apps in the same network over 6PN.`,
 		}
 	case "ips":
 		return KeyStrings{"ips", "Manage IP addresses for apps",
@@ -375,28 +878,56 @@ and IPv6 addresses.`,
 		}
 	case "ips.allocate-v4":
 		return KeyStrings{"allocate-v4", "Allocate an IPv4 address",
-			`Allocates an IPv4 address to the application.`,
+			`Allocates an IPv4 address to the application. With --shared, allocates
+a shared IPv4 address instead, which is used by many apps and routes
+requests to this one by hostname, so only works for HTTP and TLS services.
+Use --region to announce a dedicated address from a single region.`,
 		}
 	case "ips.allocate-v6":
 		return KeyStrings{"allocate-v6", "Allocate an IPv6 address",
-			`Allocates an IPv6 address to the application.`,
+			`Allocates an IPv6 address to the application. Use --region to
+announce it from a single region.`,
 		}
 	case "ips.list":
 		return KeyStrings{"list", "List allocated IP addresses",
-			`Lists the IP addresses allocated to the application.`,
+			`Lists the IP addresses allocated to the application, with the region
+each is announced from and the certificates whose hostnames resolve to it.`,
+		}
+	case "ips.move":
+		return KeyStrings{"move <address>", "Move a dedicated IP address to another app",
+			`Moves a dedicated IP address from the app given with --from to the app
+given with --to, in the same organization, for blue/green cutovers between
+apps. The target must have running instances, in the address's region when
+it's announced from one, with passing health checks, unless --force is
+given. The address is routed to the target before it stops being routed
+to the source, so connections aren't refused during the move. Hostnames
+resolving to the address are listed, as the target needs certificates for
+them. Use --yes to skip the confirmation.`,
 		}
 	case "ips.private":
 		return KeyStrings{"private", "List instances private IP addresses",
 			`List instances private IP addresses, accessible from within the
-Fly network`,
+Fly network. These are the 6PN (private IPv6 network) addresses that
+<app>.internal and <region>.<app>.internal resolve to. Use 'flyctl ping'
+to check they're reachable.`,
 		}
 	case "ips.release":
 		return KeyStrings{"release [ADDRESS]", "Release an IP address",
-			`Releases an IP address from the application.`,
+			`Releases an IP address from the application, after confirming. The
+certificates whose hostnames resolve to the address are listed first, as
+they'll stop reaching the app. Use --yes to skip the confirmation.`,
 		}
 	case "launch":
 		return KeyStrings{"launch", "Launch a new app",
-			`Create and configure a new app from source code or an image reference.`,
+			`Create and configure a new app from source code or an image reference.
+
+When the source has a Procfile with several process types, each becomes a
+process group in fly.toml. You choose which groups receive the app's public
+services; they get two instances each and the other groups one, applied
+after the first deploy. A release process becomes the release command.
+
+With --network, the app is placed in a separate private network of its
+organization and can only reach apps in the same network over 6PN.`,
 		}
 	case "list":
 		return KeyStrings{"list", "Lists your Fly resources",
@@ -433,6 +964,142 @@ the Fly platform.
 Logs can be filtered to a specific instance using the --instance/-i flag or 
 to all instances running in a specific region using the --region/-r flag.`,
 		}
+	case "logs.export":
+		return KeyStrings{"export --to s3://bucket/prefix|gs://bucket/prefix|<dir>", "Archive a window of an app's logs to storage",
+			`Export the app's logs from the last --window, 24h unless given, to object
+storage or a local directory, for archiving without running a log shipper.
+
+Logs are written as gzipped JSON lines, a file per --partition, named
+<app>/YYYY/MM/DD/HH.ndjson.gz by hour or <app>/YYYY/MM/DD.ndjson.gz by day.
+Only whole partitions up to the start of the current one are exported, so
+running the same export again later rewrites the same files rather than
+overlapping them.
+
+  s3  --access-key-id and --secret-access-key or AWS_ACCESS_KEY_ID and
+      AWS_SECRET_ACCESS_KEY, --s3-region, and --endpoint for S3 compatible
+      storage
+  gs  HMAC keys for Google Cloud Storage, given the same way
+
+Progress is saved after each file. If an export is interrupted, run it again
+to resume where it stopped, or pass --restart to export a new window.`,
+		}
+	case "logs.ship":
+		return KeyStrings{"ship", "Ship an organization's logs to external services",
+			`Commands that configure the organization's log shipper, which forwards
+app logs from the platform's log stream to an external service. There's no
+log shipper app to deploy.`,
+		}
+	case "logs.ship.create":
+		return KeyStrings{"create --destination datadog|s3|http", "Start shipping logs to a destination",
+			`Start shipping the logs of the organization's apps, or only those given
+with --app, to a destination:
+
+  datadog  --token with a Datadog API key, and --site if it isn't datadoghq.com
+  http     --url to POST batches of JSON logs to, and an optional bearer --token
+  s3       --bucket as s3://bucket/prefix, with --access-key-id and
+           --secret-access-key or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY,
+           --s3-region, and --endpoint for S3 compatible storage`,
+		}
+	case "logs.ship.delete":
+		return KeyStrings{"delete <id>", "Stop shipping logs to a destination",
+			`Delete a log shipper, so logs are no longer shipped to its destination`,
+		}
+	case "logs.ship.list":
+		return KeyStrings{"list", "List the organization's log shippers",
+			`List where the organization's logs are shipped to, and for which apps`,
+		}
+	case "machines":
+		return KeyStrings{"machines", "Commands that manage an app's machines",
+			`Commands that manage an app's machines, VMs run directly through
+the machines API rather than by deploying releases.`,
+		}
+	case "machines.clone":
+		return KeyStrings{"clone <id>", "Clone a machine",
+			`Create a new machine with the same configuration as an existing one.
+Use --region to run the clone elsewhere, and --gpu-kind and --gpus to
+change the GPUs attached to it.`,
+		}
+	case "machines.list":
+		return KeyStrings{"list", "List an app's machines",
+			`List an app's machines with their state, region, IPs, image and the
+GPUs attached to each.
+
+With --watch, the list refreshes until interrupted. On a terminal the table
+is redrawn in place; otherwise each machine that appears, changes state or
+goes away is written as a JSON line, for scripts to follow.`,
+		}
+	case "machines.ports":
+		return KeyStrings{"ports", "Manage the ports a machine exposes",
+			`Manage the ports a machine exposes to the internet, without
+recreating the machine.`,
+		}
+	case "machines.ports.add":
+		return KeyStrings{"add <id> --port EXTERNAL:INTERNAL", "Expose a port on a machine",
+			`Expose a port on a machine, routing it to an internal port. For
+example, to serve HTTPS on port 443 from port 8080 of the machine:
+
+    flyctl machines ports add <id> --port 443:8080 --handler tls,http
+
+Each external port can only be exposed once per protocol. The update
+fails if the machine's configuration changed since it was read, run
+the command again to retry.`,
+		}
+	case "machines.ports.list":
+		return KeyStrings{"list <id>", "List the ports a machine exposes",
+			`List the ports a machine exposes, with the internal port each is
+routed to and its handlers.`,
+		}
+	case "machines.ports.remove":
+		return KeyStrings{"remove <id> --port PORT", "Stop exposing a port on a machine",
+			`Stop exposing an external port on a machine. A service left without
+ports is removed.`,
+		}
+	case "machines.run":
+		return KeyStrings{"run <image>", "Run a machine from an image",
+			`Run a new machine from an image, optionally choosing its name, region
+and VM size.
+
+GPUs can be attached with --gpu-kind and --gpus, the region must have
+that kind of GPU. The GPU cost is shown before the machine is created,
+pass --yes to skip the confirmation.
+
+e.g. flyctl machines run my-model:latest --region ord --gpu-kind a100 --gpus 1`,
+		}
+	case "machines.wait":
+		return KeyStrings{"wait <id>", "Wait for a machine to reach a state",
+			`Wait for a machine to reach a state, checking it every second. Use
+--state to choose started (the default), stopped or destroyed, and
+--timeout to limit how long to wait, 2m by default.
+
+Exits with an error if the timeout passes first, or if the machine is
+destroyed while waiting for another state. Useful in deployment scripts
+that start or stop machines and need to know when they're done.`,
+		}
+	case "metrics":
+		return KeyStrings{"metrics", "Query an organization's metrics",
+			`Commands for querying the metrics Fly collects from your apps, from the
+organization's Prometheus endpoint.`,
+		}
+	case "metrics.dashboards":
+		return KeyStrings{"dashboards", "Open metrics dashboards",
+			`Commands for the hosted Grafana dashboards of an organization's metrics`,
+		}
+	case "metrics.dashboards.open":
+		return KeyStrings{"open", "Open the metrics dashboards in a browser",
+			`Open the organization's metrics dashboards in a browser, or with --app
+the dashboard of one app.`,
+		}
+	case "metrics.query":
+		return KeyStrings{"query <promql>", "Run a PromQL query",
+			`Run a PromQL query against the organization's Prometheus endpoint and
+show each series' value, e.g.
+
+  flyctl metrics query 'sum(rate(fly_app_http_responses_count[5m])) by (app)'
+
+With --range, the query is evaluated over that much time up to now, and the
+number of points, minimum, maximum and last value of each series are shown.
+--step sets the interval between points. Use --json for every point.`,
+		}
 	case "monitor":
 		return KeyStrings{"monitor", "Monitor deployments",
 			`Monitor application deployments and other activities. Use --verbose/-v
@@ -441,12 +1108,29 @@ to get details of every instance . Control-C to stop output.`,
 	case "move":
 		return KeyStrings{"move [APPNAME]", "Move an app to another organization",
 			`The MOVE command will move an application to another 
-organization the current user belongs to.`,
+organization the current user belongs to. IP addresses move with the app.
+Volumes are snapshotted before the move and, with --recreate-volumes, restored
+from those snapshots in the destination organization. Without it, the
+commands that restore them are printed after the move.`,
+		}
+	case "networks":
+		return KeyStrings{"networks", "Manage private networks",
+			`Commands for the private (6PN) networks apps are isolated in. Apps
+join a network when they're created with --network; apps created without it
+share the organization's default network.`,
+		}
+	case "networks.list":
+		return KeyStrings{"list", "List an organization's private networks",
+			`List the private networks of an organization and the apps in each.
+Apps can only reach apps in the same network.`,
 		}
 	case "open":
 		return KeyStrings{"open [PATH]", "Open browser to current deployed application",
 			`Open browser to current deployed application. If an optional path is specified, this is appended to the
-URL for deployed application.`,
+URL for deployed application. Use --https to open it over https.
+
+Where no browser can be opened, like over SSH or on a server without a
+display, the URL is printed instead.`,
 		}
 	case "orgs":
 		return KeyStrings{"orgs", "Commands for managing Fly organizations",
@@ -454,6 +1138,21 @@ URL for deployed application.`,
 destroy organizations. 
 Organization admins can also invite or remove users from Organizations.`,
 		}
+	case "orgs.api-tokens":
+		return KeyStrings{"api-tokens <command>", "Inspect an organization's deploy and org tokens",
+			`Commands that inspect the deploy and org tokens of an organization.`,
+		}
+	case "orgs.api-tokens.audit":
+		return KeyStrings{"audit", "Show what an organization's tokens have been used for",
+			`Show how each of an organization's deploy and org tokens was used over
+--window: the number of calls, when it was last used and from how many
+addresses. Tokens unused in the window may no longer be needed, and tokens
+used from more than one country may have leaked. --unused shows only the
+unused tokens.
+
+Pass --token with a token's ID or name to see the commands and API
+endpoints it called and the addresses it called them from.`,
+		}
 	case "orgs.create":
 		return KeyStrings{"create <org>", "Create an organization",
 			`Create a new organization. Other users can be invited to join the 
@@ -463,11 +1162,29 @@ organization later.`,
 		return KeyStrings{"delete <org>", "Delete an organization",
 			`Delete an existing organization.`,
 		}
+	case "orgs.dns":
+		return KeyStrings{"dns <command>", "Check the DNS of an organization's domains",
+			`Commands that check the DNS of the domains hosted by an organization.`,
+		}
+	case "orgs.dns.check":
+		return KeyStrings{"check <domain>", "Check a domain is delegated to Fly's nameservers",
+			`Check that a domain hosted by Fly is delegated to the nameservers of its
+zone. The parent zone's nameservers are asked what the registrar has set, and
+several public resolvers are asked what they currently serve, which can lag
+for up to 48 hours after a change. Any missing or unexpected nameservers are
+reported, and the command fails unless every source agrees.`,
+		}
 	case "orgs.invite":
 		return KeyStrings{"invite <org> <email>", "Invite user (by email) to organization",
 			`Invite a user, by email, to join organization. The invitation will be
 sent, and the user will be pending until they respond. See also orgs revoke.`,
 		}
+	case "orgs.leave":
+		return KeyStrings{"leave <org>", "Leave an organization",
+			`Remove yourself from an organization. The last admin of an organization
+can't leave it; transfer ownership to another member or delete the organization
+instead.`,
+		}
 	case "orgs.list":
 		return KeyStrings{"list", "Lists organizations for current user",
 			`Lists organizations available to current user.`,
@@ -488,6 +1205,21 @@ user by email.`,
 Includes name, slug and type. Summarizes user permissions, DNS zones and
 associated member. Details full list of members and roles.`,
 		}
+	case "orgs.transfer-ownership":
+		return KeyStrings{"transfer-ownership <org> --to <email>", "Transfer ownership of an organization to another member",
+			`Make another member of the organization its owner. The new owner
+must already be a member; invite them first with orgs invite if they aren't.`,
+		}
+	case "ping":
+		return KeyStrings{"ping [<host>]", "Check connectivity to a host on the private network",
+			`Check that a host on the app organization's private network is
+reachable through the WireGuard tunnel of the Fly agent. The host defaults
+to <app>.internal and can be any .internal name or 6PN address.
+
+The name is resolved on the private network and each address it resolves
+to is probed by opening a TCP connection, to port 22 by default. A refused
+connection still counts as reachable. Fails if any address is unreachable.`,
+		}
 	case "platform":
 		return KeyStrings{"platform", "Fly platform information",
 			`The PLATFORM commands are for users looking for information 
@@ -495,15 +1227,26 @@ about the Fly platform.`,
 		}
 	case "platform.regions":
 		return KeyStrings{"regions", "List regions",
-			`View a list of regions where Fly has edges and/or datacenters`,
+			`View a list of regions where Fly has edges and/or datacenters, with
+whether each has a gateway for WireGuard peers. The JSON output also includes
+each region's coordinates.
+
+With --capabilities, also shows what each region supports: volumes,
+dedicated IPs, machines, GPUs and a hint of how much capacity is
+available (normal, constrained or full). Combine with --json to choose
+regions from scripts.`,
 		}
 	case "platform.status":
 		return KeyStrings{"status", "Show current platform status",
-			`Show current Fly platform status in a browser`,
+			`Show the current Fly platform status from the status page: the overall
+status, any unresolved incidents and the components, such as regions, that
+aren't fully operational. Use --json for machine readable output, or --open to
+view the status page in a browser.`,
 		}
 	case "platform.vmsizes":
 		return KeyStrings{"vm-sizes", "List VM Sizes",
-			`View a list of VM sizes which can be used with the FLYCTL SCALE VM command`,
+			`View a list of VM sizes which can be used with the FLYCTL SCALE VM command,
+with their CPU cores, memory and price. Use --json for machine readable output.`,
 		}
 	case "postgres":
 		return KeyStrings{"postgres", "Manage postgres clusters",
@@ -511,7 +1254,33 @@ about the Fly platform.`,
 		}
 	case "postgres.attach":
 		return KeyStrings{"attach", "Attach a postgres cluster to an app",
-			`Attach a postgres cluster to an app`,
+			`Attach a postgres cluster to an app. The connection string prefers
+the cluster's instances in --region, the app's primary region by default.`,
+		}
+	case "postgres.backup":
+		return KeyStrings{"backup", "Manage backups of a postgres cluster",
+			`Take base backups of a postgres cluster and restore them, with its
+archived WAL, into a new cluster as of a point in time.`,
+		}
+	case "postgres.backup.create":
+		return KeyStrings{"create <postgres-cluster-name>", "Take a base backup of a cluster now",
+			`Take a base backup of a cluster now, on top of its scheduled backups.
+The leader is put in backup mode while its volume is snapshotted, so the
+snapshot is consistent.`,
+		}
+	case "postgres.backup.list":
+		return KeyStrings{"list <postgres-cluster-name>", "List a cluster's base backups and WAL archiving status",
+			`List a cluster's base backups, newest first, and how far its WAL has
+been archived. A cluster can be restored to any time between its oldest
+completed backup and its latest archived WAL.`,
+		}
+	case "postgres.backup.restore":
+		return KeyStrings{"restore <postgres-cluster-name>", "Restore a backup into a new cluster",
+			`Restore a cluster's backup into a new cluster, leaving the source
+untouched. With --time, as RFC 3339 or a duration ago like 2h, the latest
+backup completed before then is restored and archived WAL replayed up to
+it; otherwise the latest backup is brought up to the latest archived WAL.
+Use --backup to pick a backup by ID instead.`,
 		}
 	case "postgres.create":
 		return KeyStrings{"create", "Create a postgres cluster",
@@ -522,8 +1291,15 @@ about the Fly platform.`,
 			`manage databases in a cluster`,
 		}
 	case "postgres.db.create":
-		return KeyStrings{"create <postgres-cluster-name>", "create a database in a cluster",
-			`create a database in a cluster`,
+		return KeyStrings{"create <postgres-cluster-name> <database-name>", "Create a database in a cluster",
+			`Create a database in a cluster. Runs on the cluster's leader over your
+organization's private network, as the superuser saved when the cluster
+was created, or the one given with --admin-username and --admin-password.`,
+		}
+	case "postgres.db.drop":
+		return KeyStrings{"drop <postgres-cluster-name> <database-name>", "Drop a database from a cluster",
+			`Drop a database from a cluster, deleting its data. Runs on the cluster's
+leader over your organization's private network, like db create.`,
 		}
 	case "postgres.db.list":
 		return KeyStrings{"list <postgres-cluster-name>", "list databases in a cluster",
@@ -533,22 +1309,104 @@ about the Fly platform.`,
 		return KeyStrings{"detach", "Detach a postgres cluster from an app",
 			`Detach a postgres cluster from an app`,
 		}
+	case "postgres.failover":
+		return KeyStrings{"failover <postgres-cluster-name>", "Promote a replica to leader",
+			`Promote a replica of a cluster to leader, demoting the current leader to
+a replica, and wait for the new leader to be healthy. Pick the replica
+with --to, by ID or region; otherwise the healthy replica lagging least is
+promoted. Writes a lagging replica hasn't received are lost.`,
+		}
+	case "postgres.import":
+		return KeyStrings{"import <postgres-cluster-name> --source <connection-string>", "Import a database from another Postgres server",
+			`Import a database from another Postgres server into a cluster, streaming
+pg_dump of --source into pg_restore on the cluster's leader over your
+organization's private network. Nothing is written to disk on the way.
+
+The database is imported into --database, or one named after the source's,
+and created if it doesn't exist. Use --no-owner when the source's roles don't
+exist in the cluster, and --clean to drop existing objects before they're
+recreated, e.g. when importing again.
+
+pg_dump and pg_restore must be installed, ideally matching the source's
+Postgres version. The import runs as the superuser saved when the cluster
+was created, or the one given with --admin-username and --admin-password.`,
+		}
 	case "postgres.list":
 		return KeyStrings{"list", "list postgres clusters",
 			`list postgres clusters`,
 		}
+	case "postgres.members":
+		return KeyStrings{"members <postgres-cluster-name>", "List a cluster's members, their roles and lag",
+			`List the instances of a cluster: the leader and its replicas, with
+their region, health and how far each replica lags behind the leader.`,
+		}
+	case "postgres.replicas":
+		return KeyStrings{"replicas", "Manage a cluster's read replicas",
+			`Add and remove a cluster's read replicas`,
+		}
+	case "postgres.replicas.add":
+		return KeyStrings{"add <postgres-cluster-name>", "Add a read replica in a region",
+			`Add a read replica in --region, with a volume of its own the size of
+the leader's unless --volume-size is given.`,
+		}
+	case "postgres.replicas.remove":
+		return KeyStrings{"remove <postgres-cluster-name> <member-id>", "Remove a read replica",
+			`Remove a read replica and delete its volume. The leader can't be
+removed; fail over to another member first.`,
+		}
 	case "postgres.users":
 		return KeyStrings{"users", "manage users in a cluster",
 			`manage users in a cluster`,
 		}
 	case "postgres.users.create":
-		return KeyStrings{"create <postgres-cluster-name>", "create a user in a cluster",
-			`create a user in a cluster`,
+		return KeyStrings{"create <postgres-cluster-name> <username>", "Create a user in a cluster",
+			`Create a user in a cluster, with --password or a generated password
+shown once. Runs on the cluster's leader over your organization's private
+network, as the superuser saved when the cluster was created, or the one
+given with --admin-username and --admin-password.`,
+		}
+	case "postgres.users.drop":
+		return KeyStrings{"drop <postgres-cluster-name> <username>", "Drop a user from a cluster",
+			`Drop a user from a cluster. Anything connecting as the user loses
+access. Runs on the cluster's leader like users create.`,
+		}
+	case "postgres.users.grant":
+		return KeyStrings{"grant <postgres-cluster-name> <username>", "Give a user access to a database",
+			`Give a user read and write access to --database, or only read with
+--read-only. Runs on the cluster's leader like users create.`,
 		}
 	case "postgres.users.list":
 		return KeyStrings{"list <postgres-cluster-name>", "list users in a cluster",
 			`list users in a cluster`,
 		}
+	case "redis":
+		return KeyStrings{"redis", "Launch and manage Redis databases",
+			`Launch and manage managed Redis instances, provided by Upstash. Instances
+belong to an organization and are reached over its private network.`,
+		}
+	case "redis.connect":
+		return KeyStrings{"connect <name>", "Open redis-cli connected to a Redis instance",
+			`Open an interactive redis-cli session with a Redis instance, through a
+tunnel to the organization's private network. redis-cli must be installed.`,
+		}
+	case "redis.create":
+		return KeyStrings{"create", "Create a Redis instance",
+			`Create a Redis instance in an organization, with its primary in --region
+and read replicas in --replica-regions. The private URL that's printed
+works from any app in the organization; store it as a secret.`,
+		}
+	case "redis.destroy":
+		return KeyStrings{"destroy <name>", "Destroy a Redis instance",
+			`Destroy a Redis instance and delete its data`,
+		}
+	case "redis.list":
+		return KeyStrings{"list", "List an organization's Redis instances",
+			`List the Redis instances of an organization`,
+		}
+	case "redis.status":
+		return KeyStrings{"status <name>", "Show a Redis instance's status and private URL",
+			`Show a Redis instance's status, plan, regions and private URL`,
+		}
 	case "regions":
 		return KeyStrings{"regions", "Manage regions",
 			`Configure the region placement rules for an application.`,
@@ -565,37 +1423,104 @@ about the Fly platform.`,
 		return KeyStrings{"list", "Shows the list of regions the app is allowed to run in",
 			`Shows the list of regions the app is allowed to run in.`,
 		}
+	case "regions.primary":
+		return KeyStrings{"primary", "Shows the app's primary region",
+			`Shows the app's primary region, primary_region in fly.toml or, without
+a fly.toml that sets it, the one recorded on the app.`,
+		}
 	case "regions.remove":
 		return KeyStrings{"remove REGION ...", "Prevent the app from running in the provided regions",
 			`Prevent the app from running in the provided regions`,
 		}
 	case "regions.set":
-		return KeyStrings{"set REGION ...", "Sets the region pool with provided regions",
-			`Sets the region pool with provided regions`,
+		return KeyStrings{"set [REGION...]", "Sets the region pool with provided regions",
+			`Sets the region pool with provided regions. Regions
+not given are removed from the pool.
+
+Without arguments, choose the regions from a list of all regions, with
+the current pool selected. Each region shows a rough estimate of its
+latency from where you are.
+
+The regions added to and removed from the pool are listed after the
+change is made.`,
+		}
+	case "regions.set-primary":
+		return KeyStrings{"set-primary REGION", "Sets the app's primary region",
+			`Sets the app's primary region, recording it on the app and as
+primary_region in fly.toml when there is one.
+
+Deploys run the release command in the primary region, and postgres
+attach uses it when no region is given.`,
 		}
 	case "releases":
 		return KeyStrings{"releases", "List app releases",
 			`List all the releases of the application onto the Fly platform, 
-including type, when, success/fail and which user triggered the release.`,
+including type, when, success/fail and which user triggered the release.
+
+Release messages given with deploy --message are shown alongside. With --json,
+each release includes its message and labels, e.g. to generate a changelog.
+
+The latest 25 releases are listed, use --limit to list more, or 0 for all of
+them. Use --status and --user to only list releases with a status or made by a
+user, and --image to show the image reference and digest of each release.`,
+		}
+	case "releases.diagnose":
+		return KeyStrings{"diagnose <version>", "Find out why a release failed",
+			`Work out why a release failed, from its deployment's placement errors,
+its instances' events, health checks and logs, and its config and image
+compared to the last good release before it.
+
+Probable causes are listed likeliest first, with what points to each and
+commands to dig further or fix it: image pull failures and instances that
+couldn't be placed come before crashes, running out of memory and failing
+health checks. Changes since the last good release rank first when nothing
+else explains the failure.`,
+		}
+	case "releases.watch":
+		return KeyStrings{"watch <id|version>", "Watch a release's rollout",
+			`Reattach to the rollout of a release, by the ID 'deploy --detach' printed or
+by version, and watch it as deploy would have. When the rollout is already
+over, its outcome is reported straight away. Exits non-zero if the release
+failed, so it can gate a later step in CI.`,
 		}
 	case "restart":
 		return KeyStrings{"restart [APPNAME]", "Restart an application",
-			`The RESTART command will restart all running vms.`,
+			`The RESTART command will restart all running vms.
+
+With --rolling, VMs are restarted one at a time. Each VM must be running
+with its health checks passing, within --wait-timeout, before the next one
+is restarted. The restart stops at the first VM that doesn't become healthy.
+Use 'flyctl vm restart' to restart a single VM.
+
+With --select, choose any number of your apps from a list and restart each.`,
 		}
 	case "resume":
 		return KeyStrings{"resume [APPNAME]", "Resume an application",
-			`The RESUME command will restart a previously suspended application. 
-The application will resume with its original region pool and a min count of one
-meaning there will be one running instance once restarted. Use SCALE SET MIN= to raise
-the number of configured instances.`,
+			`The RESUME command will restart a previously suspended application.
+The application will resume with its original region pool and the instance
+counts it had when it was suspended. Apps suspended without recorded counts
+resume with a min count of one, use SCALE COUNT to raise the number of
+instances.`,
 		}
 	case "scale":
 		return KeyStrings{"scale", "Scale app resources",
 			`Scale application resources`,
 		}
+	case "scale.autostop":
+		return KeyStrings{"autostop [on|off]", "Stop idle VMs and start them on request",
+			`Configure whether the app's VMs are stopped when idle and started again
+when a request arrives, so HTTP apps only run while they're in use. Without
+an argument the current settings are shown.
+
+On and off set auto_stop_machines, auto_start_machines and
+min_machines_running on every service in fly.toml, and the next deploy
+applies them. Use --min-running to keep some VMs running when idle and
+--autostart=false to leave stopped VMs stopped.`,
+		}
 	case "scale.count":
-		return KeyStrings{"count <count>", "Change an app's VM count to the given value",
-			`Change an app's VM count to the given value. 
+		return KeyStrings{"count <count> | <group>=<count>...", "Change an app's VM count to the given value",
+			`Change an app's VM count to the given value. For apps with
+process groups, give each group's count instead, e.g. web=2 worker=1.
 
 For pricing, see https://fly.io/docs/about/pricing/`,
 		}
@@ -623,6 +1548,12 @@ For dedicated vms, this should be a multiple of 1024MB.
 
 For shared vms, this can be 256MB or a a multiple of 1024MB.
 
+GPUs can be attached with --gpu-kind and --gpus, every region the app
+runs in must have that kind of GPU. The GPU cost is shown before
+scaling, pass --yes to skip the confirmation.
+
+e.g. flyctl scale vm dedicated-cpu-8x --gpu-kind a100 --gpus 1
+
 For pricing, see https://fly.io/docs/about/pricing/`,
 		}
 	case "secrets":
@@ -633,6 +1564,24 @@ Secrets are provided to applications at runtime as ENV variables. Names are
 case sensitive and stored as-is, so ensure names are appropriate for
 the application and vm environment.`,
 		}
+	case "secrets.edit":
+		return KeyStrings{"edit [flags]", "Edit an app's secrets in your editor",
+			`Open the application's secrets in $VISUAL or $EDITOR, one NAME= line per
+secret with values left blank. Add NAME=VALUE lines to add or change secrets
+and delete lines to remove them. Secrets left as NAME= keep their value.
+
+Only the secrets that changed are applied, together in a single release.`,
+		}
+	case "secrets.history":
+		return KeyStrings{"history [NAME...]", "Show when secrets were set or unset",
+			`Show when each of the application's secrets was set or unset, newest
+first, with the user who changed it and the release that carried the change.
+Values are never shown, only the digest of the value that was set. Pass
+secret names to only show their changes.
+
+Compare the release versions with 'flyctl releases' to line up secret changes
+with deployments.`,
+		}
 	case "secrets.import":
 		return KeyStrings{"import [flags]", "Read secrets in name=value from stdin",
 			`Set one or more encrypted secrets for an application. Values
@@ -655,14 +1604,36 @@ the application and vm environment.
 Any value that equals "-" will be assigned from STDIN instead of args.`,
 		}
 	case "secrets.unset":
-		return KeyStrings{"unset [flags] NAME NAME ...", "Remove encrypted secrets from an app",
+		return KeyStrings{"unset [flags] [NAME NAME ...]", "Remove encrypted secrets from an app",
 			`Remove encrypted secrets from the application. Unsetting a 
-secret removes its availability to the application.`,
+secret removes its availability to the application.
+
+Without names, choose the secrets to remove from a list of the app's secrets.`,
+		}
+	case "sourcecode":
+		return KeyStrings{"sourcecode", "Inspect an app's source code",
+			`Inspect source code the way launch does`,
+		}
+	case "sourcecode.scan":
+		return KeyStrings{"scan [<directory>]", "Show what launch detects in source code",
+			`Scan source code, the current directory by default, and show what kind of
+app launch would detect. With --report, everything detected is shown: the
+Dockerfile or builder and buildpacks, the port the app listens on, Procfile
+processes and release command, the secrets launch would ask for and the
+files it would generate. Use --json for the full report as JSON.`,
 		}
 	case "ssh":
 		return KeyStrings{"ssh <command>", "Commands that manage SSH credentials",
 			`Commands that manage SSH credentials`,
 		}
+	case "ssh.broadcast":
+		return KeyStrings{"broadcast [flags] -- <command>", "Run a command on every running instance of the current app.",
+			`Run a command on every running instance of the current app at once.
+Output is interleaved line by line, each line prefixed with the instance and
+its region, and a summary of every instance's exit code is printed at the end.
+With -region or -process-group, only matching instances run the command. Fails
+if the command fails on any instance.`,
+		}
 	case "ssh.console":
 		return KeyStrings{"console [<host>]", "Connect to a running instance of the current app.",
 			`Connect to a running instance of the current app; with -select, choose instance from list.`,
@@ -692,19 +1663,126 @@ WireGuard region to use for the connection.`,
 		return KeyStrings{"status", "Show app status",
 			`Show the application's current status including application 
 details, tasks, most recent deployment details and in which regions it is 
-currently allocated.`,
+currently allocated.
+
+With --format prometheus, writes the status as Prometheus metrics instead:
+instances by status, healthy instances, health checks by state, and the
+latest release's version and age. Run it from cron into a node_exporter
+textfile directory to monitor the app without any other infrastructure.
+
+The app's description, owner team, repository and on-call contact are shown
+when they've been set with 'apps set-metadata'.`,
 		}
 	case "status.instance":
 		return KeyStrings{"instance [instance-id]", "Show instance status",
 			`Show the instance's current status including logs, checks, 
 and events.`,
 		}
+	case "storage":
+		return KeyStrings{"storage", "Provision and manage object storage buckets",
+			`Provision and manage S3 compatible object storage buckets for apps`,
+		}
+	case "storage.create":
+		return KeyStrings{"create", "Create a bucket for an app",
+			`Create an object storage bucket for an app, in the app's organization.
+Credentials for the bucket are set as the app's secrets AWS_ACCESS_KEY_ID,
+AWS_SECRET_ACCESS_KEY, AWS_REGION, AWS_ENDPOINT_URL_S3 and BUCKET_NAME,
+which AWS SDKs read without further configuration.`,
+		}
+	case "storage.destroy":
+		return KeyStrings{"destroy <name>", "Destroy a bucket",
+			`Destroy an object storage bucket and every object in it`,
+		}
+	case "storage.list":
+		return KeyStrings{"list", "List an organization's buckets",
+			`List the object storage buckets of an organization`,
+		}
 	case "suspend":
 		return KeyStrings{"suspend [APPNAME]", "Suspend an application",
-			`The SUSPEND command will suspend an application. 
+			`The SUSPEND command will suspend an application.
 All instances will be halted leaving the application running nowhere.
-It will continue to consume networking resources (IP address). See RESUME
-for details on restarting it.`,
+Its configuration, volumes and IP addresses are kept, so it will continue to
+consume networking resources (IP address). The instance count of each process
+group is recorded so RESUME can restore it.`,
+		}
+	case "tokens":
+		return KeyStrings{"tokens", "Manage an organization's access tokens",
+			`Commands for tokens with limited access to an organization, for use by
+other services instead of a personal access token.`,
+		}
+	case "tokens.create":
+		return KeyStrings{"create", "Create a token",
+			`Create a token with limited access to an organization`,
+		}
+	case "tokens.create.metrics":
+		return KeyStrings{"metrics", "Create a read-only token for an organization's metrics",
+			`Create a token that can only read the organization's Prometheus
+metrics, for an external Grafana or a Prometheus server federating them. The
+token is shown once. Name it with --name to tell where it's used.`,
+		}
+	case "tokens.list":
+		return KeyStrings{"list", "List an organization's tokens",
+			`List an organization's tokens, with when each was created and last used`,
+		}
+	case "tokens.revoke":
+		return KeyStrings{"revoke <id>", "Revoke a token",
+			`Revoke a token, immediately cutting off anything using it`,
+		}
+	case "topics.aliases":
+		return KeyStrings{"aliases", "Your own names for commands, defined in config.yml",
+			`Aliases name a command with the flags you always give it, so a team can
+share how it runs flyctl. Define them under alias in ~/.fly/config.yml:
+
+  alias:
+    dep: deploy --remote-only --strategy rolling
+    rel: releases --image
+
+Then 'flyctl dep -a myapp' runs 'flyctl deploy --remote-only --strategy
+rolling -a myapp': an alias given as the first argument is replaced by what
+it stands for, and the rest of the arguments follow it. Quote arguments with
+spaces as in a shell.
+
+Aliases can't redefine flyctl's commands, one with a command's name is
+ignored with a warning. 'flyctl help <alias>' shows what an alias runs.`,
+		}
+	case "topics.config-file":
+		return KeyStrings{"config-file", "flyctl's own settings in ~/.fly/config.yml",
+			`flyctl keeps its own settings, as opposed to an app's settings in fly.toml,
+in config.yml in ~/.fly. It's YAML, written by commands like 'flyctl auth
+login', and can be edited by hand:
+
+  access_token          The API token commands authenticate with
+  api_base_url          The API to talk to, https://api.fly.io by default
+  api_max_retries       How many times failed API requests are retried
+  api_retry_max_delay   The longest wait between retries, e.g. 30s
+  agent_auto_start      Start the Fly agent without asking
+  no_update_check       Don't check for or announce flyctl updates
+  non_interactive       Never prompt, fail instead when input is needed
+  registry_host         The registry images are pushed to
+  alias                 Your own names for commands, see 'flyctl help aliases'
+
+Every setting can be overridden by an environment variable, named FLY_ and
+the setting in capitals, e.g. FLY_NON_INTERACTIVE=true. See
+'flyctl help environment'.`,
+		}
+	case "topics.environment":
+		return KeyStrings{"environment", "Environment variables flyctl reads",
+			`Besides the settings in config.yml (see 'flyctl help config-file'), each
+of which can be set as FLY_ and its name in capitals, flyctl reads:
+
+  FLY_API_TOKEN          An API token, taking precedence over the one logged in
+  FLY_ACCESS_TOKEN       The same as --access-token
+  FLY_APP                The app commands work on, like -a
+  FLY_APP_CONFIG         The app's config file, like --config
+  FLY_ORG                The organization, like --org
+  FLY_ENVIRONMENT        The [environments] table of fly.toml to use
+  FLY_ASCII              Set to 1 for ASCII output without colors or glyphs
+  FLY_DEBUG              Set to http to trace API requests, like --debug-http
+  FLY_NO_UPDATE_CHECK    Don't check for or announce flyctl updates
+  FLY_WIREGUARD_TOKEN    The token 'wireguard token start|update' use
+
+Flags given on the command line take precedence over the environment, and
+the environment over a project's .fly/context.toml and config.yml.`,
 		}
 	case "version":
 		return KeyStrings{"version", "Show version information for the flyctl command",
@@ -722,7 +1800,9 @@ command to update the application.`,
 		}
 	case "vm.restart":
 		return KeyStrings{"restart <vm-id>", "Restart a VM",
-			`Request for a VM to be asynchronously restarted.`,
+			`Request for a VM to be asynchronously restarted. VM IDs are listed
+by 'flyctl status'. Use 'flyctl restart --rolling' to restart every VM one
+at a time.`,
 		}
 	case "vm.status":
 		return KeyStrings{"status <vm-id>", "Show a VM's status",
@@ -730,7 +1810,8 @@ command to update the application.`,
 		}
 	case "vm.stop":
 		return KeyStrings{"stop <vm-id>", "Stop a VM",
-			`Request for a VM to be asynchronously stopped.`,
+			`Request for a VM to be asynchronously stopped. VM IDs are listed
+by 'flyctl status'.`,
 		}
 	case "volumes":
 		return KeyStrings{"volumes <command>", "Volume management commands",
@@ -740,7 +1821,15 @@ command to update the application.`,
 		return KeyStrings{"create <volumename>", "Create new volume for app",
 			`Create new volume for app. --region flag must be included to specify
 region the volume exists in. --size flag is optional, defaults to 10,
-sets the size as the number of gigabytes the volume will consume.`,
+sets the size as the number of gigabytes the volume will consume.
+
+Volumes with the same name are placed in separate hardware zones so a host
+failure can't take out every copy. Pass --require-unique-zone=false to relax
+this, and --host-affinity <volume-id> to place the volume on the same host as
+another where the region supports it.
+
+Pass --snapshot-id to restore the volume from a snapshot, e.g. one taken by
+the move command.`,
 		}
 	case "volumes.delete":
 		return KeyStrings{"delete <id>", "Delete a volume from the app",
@@ -754,7 +1843,66 @@ number to operate. This can be found through the volumes list command`,
 	case "volumes.show":
 		return KeyStrings{"show <id>", "Show details of an app's volume",
 			`Show details of an app's volume. Requires the volume's ID
-number to operate. This can be found through the volumes list command`,
+number to operate. This can be found through the volumes list command
+
+The volume's hardware zone and host are shown where the API reports them,
+along with a warning if other volumes of the same name share its host.`,
+		}
+	case "volumes.snapshots":
+		return KeyStrings{"snapshots", "Manage volume snapshots",
+			`Commands for listing and exporting volume snapshots`,
+		}
+	case "volumes.snapshots.export":
+		return KeyStrings{"export <snapshot-id> --to s3://bucket/path", "Export a volume snapshot to S3 compatible storage",
+			`Copy a volume snapshot to S3, or S3 compatible storage with --endpoint,
+for keeping backups outside of Fly.
+
+Credentials are read from --access-key-id, --secret-access-key and
+--session-token, or AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+AWS_SESSION_TOKEN. The bucket's region is read from --s3-region or AWS_REGION.
+
+The snapshot is uploaded in parts, and its progress saved after each one. An
+interrupted export resumes from the last uploaded part when run again, or
+starts over with --restart. The snapshot's SHA256 digest is verified before
+the upload is completed; on a mismatch the upload is discarded.
+
+Exporting needs the API to offer a download of the snapshot's data, which it
+doesn't for every organization; the export fails before uploading anything
+when it isn't offered.`,
+		}
+	case "volumes.snapshots.list":
+		return KeyStrings{"list <volume-id>", "List a volume's snapshots",
+			`List the snapshots of a volume, with their sizes and digests.`,
+		}
+	case "webhooks":
+		return KeyStrings{"webhooks", "Notify Slack or a URL of releases",
+			`Manage webhooks the platform posts notifications to when releases succeed
+or fail, however they were deployed. A webhook notifies of the events of one
+app, or of every app in an organization.`,
+		}
+	case "webhooks.create":
+		return KeyStrings{"create", "Create a webhook",
+			`Create a webhook that's posted to on the events given with --event, release
+by default. release notifies of releases succeeding and failing, and
+release.failed only of failures.
+
+Use --app to only notify of an app's events, or --org to notify of every app
+in an organization. Slack incoming webhook URLs are posted Slack messages,
+other URLs a JSON body; choose with --payload.`,
+		}
+	case "webhooks.delete":
+		return KeyStrings{"delete <id>", "Delete a webhook",
+			`Delete a webhook, which stops its notifications.`,
+		}
+	case "webhooks.list":
+		return KeyStrings{"list", "List webhooks",
+			`List the webhooks of an organization, or with --app those that notify of
+the app's events, including its organization's.`,
+		}
+	case "webhooks.test":
+		return KeyStrings{"test <id>", "Send a test notification to a webhook",
+			`Have the platform post a test notification to a webhook and show how it
+responded.`,
 		}
 	case "wireguard":
 		return KeyStrings{"wireguard <command>", "Commands that manage WireGuard peer connections",
@@ -762,12 +1910,20 @@ number to operate. This can be found through the volumes list command`,
 		}
 	case "wireguard.create":
 		return KeyStrings{"create [org] [region] [name]", "Add a WireGuard peer connection",
-			`Add a WireGuard peer connection to an organization`,
+			`Add a WireGuard peer connection to an organization. Unless a region
+is given, the gateway region with the lowest latency is used, estimated from
+its distance to the edge region the API is reached through.`,
 		}
 	case "wireguard.list":
 		return KeyStrings{"list [<org>]", "List all WireGuard peer connections",
 			`List all WireGuard peer connections`,
 		}
+	case "wireguard.relocate":
+		return KeyStrings{"relocate <name>", "Move a WireGuard peer to another gateway region",
+			`Move a WireGuard peer to the gateway in another region, keeping its
+keys and address so the local configuration only needs its Endpoint updated.
+Defaults to the region with the lowest latency.`,
+		}
 	case "wireguard.remove":
 		return KeyStrings{"remove [org] [name]", "Remove a WireGuard peer connection",
 			`Remove a WireGuard peer connection from an organization`,
@@ -799,3 +1955,280 @@ number to operate. This can be found through the volumes list command`,
 	}
 	panic("unknown command key " + key)
 }
+
+// Keys - Every key with document strings, parents before their children
+func Keys() []string {
+	return []string{
+		"agent",
+		"agent.daemon-start",
+		"agent.restart",
+		"agent.start",
+		"agent.stop",
+		"apps",
+		"apps.clone",
+		"apps.create",
+		"apps.destroy",
+		"apps.errors",
+		"apps.fork",
+		"apps.list",
+		"apps.move",
+		"apps.reap",
+		"apps.rename",
+		"apps.restart",
+		"apps.resume",
+		"apps.set-description",
+		"apps.set-metadata",
+		"apps.suspend",
+		"auth",
+		"auth.docker",
+		"auth.impersonate",
+		"auth.login",
+		"auth.logout",
+		"auth.signup",
+		"auth.ssh-keys",
+		"auth.ssh-keys.add",
+		"auth.ssh-keys.list",
+		"auth.ssh-keys.remove",
+		"auth.token",
+		"auth.whoami",
+		"autoscale",
+		"autoscale.balanced",
+		"autoscale.disable",
+		"autoscale.set",
+		"autoscale.show",
+		"autoscale.standard",
+		"builds",
+		"builds.list",
+		"builds.logs",
+		"builtins",
+		"builtins.list",
+		"builtins.show",
+		"builtins.show-app",
+		"cache",
+		"cache.clear",
+		"certs",
+		"certs.add",
+		"certs.check",
+		"certs.list",
+		"certs.remove",
+		"certs.show",
+		"checks",
+		"checks.handlers",
+		"checks.handlers.create",
+		"checks.handlers.delete",
+		"checks.handlers.list",
+		"checks.list",
+		"checks.wait",
+		"completion",
+		"config",
+		"config.display",
+		"config.env",
+		"config.save",
+		"config.schema",
+		"config.set",
+		"config.unset",
+		"config.validate",
+		"consul",
+		"consul.attach",
+		"consul.detach",
+		"context",
+		"context.set",
+		"context.show",
+		"curl",
+		"dashboard",
+		"dashboard.metrics",
+		"deploy",
+		"destroy",
+		"dns-records",
+		"dns-records.export",
+		"dns-records.import",
+		"dns-records.list",
+		"docs",
+		"docs.generate",
+		"domains",
+		"domains.add",
+		"domains.autorenew",
+		"domains.autorenew.disable",
+		"domains.autorenew.enable",
+		"domains.expiring",
+		"domains.list",
+		"domains.register",
+		"domains.show",
+		"domains.zones",
+		"domains.zones.import",
+		"events",
+		"extensions",
+		"extensions.list",
+		"extensions.sentry",
+		"extensions.sentry.create",
+		"flyctl",
+		"help",
+		"history",
+		"info",
+		"init",
+		"ips",
+		"ips.allocate-v4",
+		"ips.allocate-v6",
+		"ips.list",
+		"ips.move",
+		"ips.private",
+		"ips.release",
+		"launch",
+		"list",
+		"list.apps",
+		"list.orgs",
+		"logs",
+		"logs.export",
+		"logs.ship",
+		"logs.ship.create",
+		"logs.ship.delete",
+		"logs.ship.list",
+		"machines",
+		"machines.clone",
+		"machines.list",
+		"machines.ports",
+		"machines.ports.add",
+		"machines.ports.list",
+		"machines.ports.remove",
+		"machines.run",
+		"machines.wait",
+		"metrics",
+		"metrics.dashboards",
+		"metrics.dashboards.open",
+		"metrics.query",
+		"monitor",
+		"move",
+		"networks",
+		"networks.list",
+		"open",
+		"orgs",
+		"orgs.api-tokens",
+		"orgs.api-tokens.audit",
+		"orgs.create",
+		"orgs.delete",
+		"orgs.dns",
+		"orgs.dns.check",
+		"orgs.invite",
+		"orgs.leave",
+		"orgs.list",
+		"orgs.remove",
+		"orgs.revoke",
+		"orgs.show",
+		"orgs.transfer-ownership",
+		"ping",
+		"platform",
+		"platform.regions",
+		"platform.status",
+		"platform.vmsizes",
+		"postgres",
+		"postgres.attach",
+		"postgres.backup",
+		"postgres.backup.create",
+		"postgres.backup.list",
+		"postgres.backup.restore",
+		"postgres.create",
+		"postgres.db",
+		"postgres.db.create",
+		"postgres.db.drop",
+		"postgres.db.list",
+		"postgres.detach",
+		"postgres.failover",
+		"postgres.import",
+		"postgres.list",
+		"postgres.members",
+		"postgres.replicas",
+		"postgres.replicas.add",
+		"postgres.replicas.remove",
+		"postgres.users",
+		"postgres.users.create",
+		"postgres.users.drop",
+		"postgres.users.grant",
+		"postgres.users.list",
+		"redis",
+		"redis.connect",
+		"redis.create",
+		"redis.destroy",
+		"redis.list",
+		"redis.status",
+		"regions",
+		"regions.add",
+		"regions.backup",
+		"regions.list",
+		"regions.primary",
+		"regions.remove",
+		"regions.set",
+		"regions.set-primary",
+		"releases",
+		"releases.diagnose",
+		"releases.watch",
+		"restart",
+		"resume",
+		"scale",
+		"scale.autostop",
+		"scale.count",
+		"scale.memory",
+		"scale.show",
+		"scale.vm",
+		"secrets",
+		"secrets.edit",
+		"secrets.history",
+		"secrets.import",
+		"secrets.list",
+		"secrets.set",
+		"secrets.unset",
+		"sourcecode",
+		"sourcecode.scan",
+		"ssh",
+		"ssh.broadcast",
+		"ssh.console",
+		"ssh.establish",
+		"ssh.issue",
+		"ssh.log",
+		"ssh.shell",
+		"status",
+		"status.instance",
+		"storage",
+		"storage.create",
+		"storage.destroy",
+		"storage.list",
+		"suspend",
+		"tokens",
+		"tokens.create",
+		"tokens.create.metrics",
+		"tokens.list",
+		"tokens.revoke",
+		"topics.aliases",
+		"topics.config-file",
+		"topics.environment",
+		"version",
+		"version.update",
+		"vm",
+		"vm.restart",
+		"vm.status",
+		"vm.stop",
+		"volumes",
+		"volumes.create",
+		"volumes.delete",
+		"volumes.list",
+		"volumes.show",
+		"volumes.snapshots",
+		"volumes.snapshots.export",
+		"volumes.snapshots.list",
+		"webhooks",
+		"webhooks.create",
+		"webhooks.delete",
+		"webhooks.list",
+		"webhooks.test",
+		"wireguard",
+		"wireguard.create",
+		"wireguard.list",
+		"wireguard.relocate",
+		"wireguard.remove",
+		"wireguard.token",
+		"wireguard.token.create",
+		"wireguard.token.delete",
+		"wireguard.token.list",
+		"wireguard.token.start",
+		"wireguard.token.update",
+	}
+}
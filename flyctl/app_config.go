@@ -26,6 +26,10 @@ type AppConfig struct {
 	AppName    string
 	Build      *Build
 	Definition map[string]interface{}
+
+	// composed is set when the config was loaded from includes or had
+	// environment variables interpolated, so isn't what's in its file
+	composed bool
 }
 
 type Build struct {
@@ -37,6 +41,12 @@ type Build struct {
 	Settings map[string]interface{}
 	// Or...
 	Image string
+	// Or...
+	Dockerfile string
+
+	// Processes holds build settings for process groups which need an
+	// image of their own, keyed by process group name
+	Processes map[string]*Build
 }
 
 func NewAppConfig() *AppConfig {
@@ -45,7 +55,17 @@ func NewAppConfig() *AppConfig {
 	}
 }
 
+// LoadAppConfig loads an app config file. The files it names in `include`
+// are loaded first and overridden by its own settings, and ${VAR} or
+// ${VAR:-default} in string values are replaced from the environment.
 func LoadAppConfig(configFile string) (*AppConfig, error) {
+	return LoadAppConfigEnvironment(configFile, "")
+}
+
+// LoadAppConfigEnvironment loads an app config file like LoadAppConfig, then
+// applies the settings of environment from its [environments] section over
+// the rest. No environment is applied when it's empty.
+func LoadAppConfigEnvironment(configFile string, environment string) (*AppConfig, error) {
 	fullConfigFilePath, err := filepath.Abs(configFile)
 	if err != nil {
 		return nil, err
@@ -55,22 +75,40 @@ func LoadAppConfig(configFile string) (*AppConfig, error) {
 		Definition: map[string]interface{}{},
 	}
 
-	file, err := os.Open(fullConfigFilePath)
+	if ConfigFormatFromPath(fullConfigFilePath) != TOMLFormat {
+		return nil, errors.New("Unsupported config file format")
+	}
+
+	data, err := loadConfigData(fullConfigFilePath, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	_, included := data[includeKey]
+	delete(data, includeKey)
 
-	switch ConfigFormatFromPath(fullConfigFilePath) {
-	case TOMLFormat:
-		err = appConfig.unmarshalTOML(file)
-	default:
-		return nil, errors.New("Unsupported config file format")
+	_, interpolated, err := interpolateEnv(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(fullConfigFilePath), err)
+	}
+
+	_, hasEnvironments := data[environmentsKey]
+	if err := applyEnvironment(data, environment); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(fullConfigFilePath), err)
 	}
 
+	appConfig.composed = included || interpolated || hasEnvironments
+
+	err = appConfig.unmarshalNativeMap(data)
+
 	return &appConfig, err
 }
 
+// Composed reports whether the config was assembled from included files or
+// had environment variables interpolated. Writing it back would flatten it.
+func (ac *AppConfig) Composed() bool {
+	return ac.composed
+}
+
 func (ac *AppConfig) HasDefinition() bool {
 	return len(ac.Definition) > 0
 }
@@ -83,6 +121,15 @@ func (ac *AppConfig) HasBuiltin() bool {
 	return ac.Build != nil && ac.Build.Builtin != ""
 }
 
+// ProcessGroupBuilds returns the build settings for process groups which
+// declare their own image, keyed by process group name
+func (ac *AppConfig) ProcessGroupBuilds() map[string]*Build {
+	if ac.Build == nil {
+		return nil
+	}
+	return ac.Build.Processes
+}
+
 func (ac *AppConfig) Image() string {
 	if ac.Build == nil {
 		return ""
@@ -99,78 +146,121 @@ func (ac *AppConfig) WriteTo(w io.Writer, format ConfigFormat) error {
 	return fmt.Errorf("Unsupported format: %s", format)
 }
 
-func (ac *AppConfig) unmarshalTOML(r io.Reader) error {
-	var data map[string]interface{}
-
-	if _, err := toml.DecodeReader(r, &data); err != nil {
-		return err
-	}
-
-	return ac.unmarshalNativeMap(data)
-}
-
 func (ac *AppConfig) unmarshalNativeMap(data map[string]interface{}) error {
 	if appName, ok := (data["app"]).(string); ok {
 		ac.AppName = appName
 	}
 	delete(data, "app")
 	if buildConfig, ok := (data["build"]).(map[string]interface{}); ok {
-		insection := false
-		b := Build{
-			Args:       map[string]string{},
-			Settings:   map[string]interface{}{},
-			Buildpacks: []string{},
-		}
-		for k, v := range buildConfig {
-			switch k {
-			case "builder":
-				b.Builder = fmt.Sprint(v)
-				insection = true
-			case "buildpacks":
-				if bpSlice, ok := v.([]interface{}); ok {
-					for _, argV := range bpSlice {
-						b.Buildpacks = append(b.Buildpacks, fmt.Sprint(argV))
-					}
+		ac.Build = unmarshalBuild(buildConfig)
+	}
+
+	delete(data, "build")
+
+	ac.Definition = data
+
+	return nil
+}
+
+func unmarshalBuild(buildConfig map[string]interface{}) *Build {
+	insection := false
+	b := Build{
+		Args:       map[string]string{},
+		Settings:   map[string]interface{}{},
+		Buildpacks: []string{},
+		Processes:  map[string]*Build{},
+	}
+	for k, v := range buildConfig {
+		switch k {
+		case "builder":
+			b.Builder = fmt.Sprint(v)
+			insection = true
+		case "buildpacks":
+			if bpSlice, ok := v.([]interface{}); ok {
+				for _, argV := range bpSlice {
+					b.Buildpacks = append(b.Buildpacks, fmt.Sprint(argV))
 				}
-				insection = true
-			case "args":
-				if argMap, ok := v.(map[string]interface{}); ok {
-					for argK, argV := range argMap {
-						b.Args[argK] = fmt.Sprint(argV)
-					}
+			}
+			insection = true
+		case "args":
+			if argMap, ok := v.(map[string]interface{}); ok {
+				for argK, argV := range argMap {
+					b.Args[argK] = fmt.Sprint(argV)
 				}
-				insection = true
-			case "builtin":
-				b.Builtin = fmt.Sprint(v)
-				insection = true
-			case "settings":
-				if settingsMap, ok := v.(map[string]interface{}); ok {
-					for settingK, settingV := range settingsMap {
-						b.Settings[settingK] = settingV //fmt.Sprint(argV)
-					}
+			}
+			insection = true
+		case "builtin":
+			b.Builtin = fmt.Sprint(v)
+			insection = true
+		case "settings":
+			if settingsMap, ok := v.(map[string]interface{}); ok {
+				for settingK, settingV := range settingsMap {
+					b.Settings[settingK] = settingV //fmt.Sprint(argV)
 				}
-				insection = true
-			case "image":
-				b.Image = fmt.Sprint(v)
-				insection = true
-			default:
-				if !insection {
-					b.Args[k] = fmt.Sprint(v)
+			}
+			insection = true
+		case "image":
+			b.Image = fmt.Sprint(v)
+			insection = true
+		case "dockerfile":
+			b.Dockerfile = fmt.Sprint(v)
+			insection = true
+		case "processes":
+			if processMap, ok := v.(map[string]interface{}); ok {
+				for group, groupV := range processMap {
+					if groupConfig, ok := groupV.(map[string]interface{}); ok {
+						if groupBuild := unmarshalBuild(groupConfig); groupBuild != nil {
+							b.Processes[group] = groupBuild
+						}
+					}
 				}
 			}
-		}
-		if b.Builder != "" || b.Builtin != "" || b.Image != "" || len(b.Args) > 0 {
-			ac.Build = &b
+			insection = true
+		default:
+			if !insection {
+				b.Args[k] = fmt.Sprint(v)
+			}
 		}
 	}
-
-	delete(data, "build")
-
-	ac.Definition = data
-
+	if b.Builder != "" || b.Builtin != "" || b.Image != "" || b.Dockerfile != "" || len(b.Args) > 0 || len(b.Processes) > 0 {
+		return &b
+	}
 	return nil
 }
 
+func marshalBuild(b *Build) map[string]interface{} {
+	buildData := map[string]interface{}{}
+	if b.Builder != "" {
+		buildData["builder"] = b.Builder
+	}
+	if len(b.Buildpacks) > 0 {
+		buildData["buildpacks"] = b.Buildpacks
+	}
+	if len(b.Args) > 0 {
+		buildData["args"] = b.Args
+	}
+	if b.Builtin != "" {
+		buildData["builtin"] = b.Builtin
+		if len(b.Settings) > 0 {
+			buildData["settings"] = b.Settings
+		}
+	}
+	if b.Image != "" {
+		buildData["image"] = b.Image
+	}
+	if b.Dockerfile != "" {
+		buildData["dockerfile"] = b.Dockerfile
+	}
+	if len(b.Processes) > 0 {
+		processData := map[string]interface{}{}
+		for group, groupBuild := range b.Processes {
+			processData[group] = marshalBuild(groupBuild)
+		}
+		buildData["processes"] = processData
+	}
+	return buildData
+}
+
 func (ac AppConfig) marshalTOML(w io.Writer) error {
 	encoder := toml.NewEncoder(w)
 
@@ -187,26 +277,7 @@ func (ac AppConfig) marshalTOML(w io.Writer) error {
 	rawData = ac.Definition
 
 	if ac.Build != nil {
-		buildData := map[string]interface{}{}
-		if ac.Build.Builder != "" {
-			buildData["builder"] = ac.Build.Builder
-		}
-		if len(ac.Build.Buildpacks) > 0 {
-			buildData["buildpacks"] = ac.Build.Buildpacks
-		}
-		if len(ac.Build.Args) > 0 {
-			buildData["args"] = ac.Build.Args
-		}
-		if ac.Build.Builtin != "" {
-			buildData["builtin"] = ac.Build.Builtin
-			if len(ac.Build.Settings) > 0 {
-				buildData["settings"] = ac.Build.Settings
-			}
-		}
-		if ac.Build.Image != "" {
-			buildData["image"] = ac.Build.Image
-		}
-		rawData["build"] = buildData
+		rawData["build"] = marshalBuild(ac.Build)
 	}
 
 	if len(ac.Definition) > 0 {
@@ -241,6 +312,14 @@ func (ac *AppConfig) WriteToFile(filename string) error {
 	return ac.WriteTo(file, ConfigFormatFromPath(filename))
 }
 
+// PrimaryRegion returns the primary_region setting, empty when there's none
+func (ac *AppConfig) PrimaryRegion() string {
+	if region, ok := ac.Definition["primary_region"].(string); ok {
+		return region
+	}
+	return ""
+}
+
 // HasServices - Does this config have a services section
 func (ac *AppConfig) HasServices() bool {
 	_, ok := ac.Definition["services"].([]interface{})
@@ -263,6 +342,20 @@ func (ac *AppConfig) SetInternalPort(port int) bool {
 	return false
 }
 
+// SetAutostop - sets whether every service stops its idle VMs and starts
+// them again on request, returning how many services were changed
+func (ac *AppConfig) SetAutostop(autoStop, autoStart bool, minRunning int) int {
+	services := configTables(ac.Definition["services"])
+
+	for _, service := range services {
+		service["auto_stop_machines"] = autoStop
+		service["auto_start_machines"] = autoStart
+		service["min_machines_running"] = minRunning
+	}
+
+	return len(services)
+}
+
 func (ac *AppConfig) GetInternalPort() (int, error) {
 	tmpservices, ok := ac.Definition["services"]
 
@@ -285,17 +378,19 @@ func (ac *AppConfig) GetInternalPort() (int, error) {
 	return 8080, nil
 }
 
+// SetEnvVariables merges vals into the config's env block
 func (ac *AppConfig) SetEnvVariables(vals map[string]string) {
-	var env map[string]string
-
-	if rawEnv, ok := ac.Definition["env"]; ok {
-		if castEnv, ok := rawEnv.(map[string]string); ok {
-			env = castEnv
+	env := map[string]string{}
+
+	// env is a map[string]interface{} when it was loaded from fly.toml
+	switch rawEnv := ac.Definition["env"].(type) {
+	case map[string]string:
+		env = rawEnv
+	case map[string]interface{}:
+		for k, v := range rawEnv {
+			env[k] = fmt.Sprint(v)
 		}
 	}
-	if env == nil {
-		env = map[string]string{}
-	}
 
 	for k, v := range vals {
 		env[k] = v
@@ -1,7 +1,9 @@
 package flyctl
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/stretchr/testify/assert"
@@ -40,3 +42,185 @@ func TestLoadTOMLAppConfigWithServices(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, p.Definition, rawData)
 }
+
+func TestLoadTOMLAppConfigWithProcessGroupBuilds(t *testing.T) {
+	path := "./testdata/build-processes.toml"
+	p, err := LoadAppConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, p.Build.Builder, "builder/name")
+	assert.Equal(t, p.ProcessGroupBuilds()["worker"].Dockerfile, "worker.Dockerfile")
+	assert.Nil(t, p.ProcessGroupBuilds()["web"])
+}
+
+func TestLoadTOMLAppConfigWithIncludeAndInterpolation(t *testing.T) {
+	os.Setenv("TEST_DATABASE_URL", "postgres://staging")
+	defer os.Unsetenv("TEST_DATABASE_URL")
+
+	p, err := LoadAppConfig("./testdata/include-staging.toml")
+	assert.NoError(t, err)
+	assert.True(t, p.Composed())
+	assert.Equal(t, "staging-app", p.AppName)
+	assert.Equal(t, map[string]interface{}{
+		"LOG_LEVEL":    "debug",
+		"REGION":       "iad",
+		"DATABASE_URL": "postgres://staging",
+		"LITERAL":      "${NOT_A_VAR}",
+	}, p.Definition["env"])
+	assert.Len(t, p.Definition["services"], 1)
+	assert.NotContains(t, p.Definition, "include")
+}
+
+func TestLoadTOMLAppConfigWithMissingEnvVar(t *testing.T) {
+	os.Unsetenv("TEST_DATABASE_URL")
+
+	_, err := LoadAppConfig("./testdata/include-staging.toml")
+	assert.EqualError(t, err, "include-staging.toml: environment variable TEST_DATABASE_URL is not set, use ${TEST_DATABASE_URL:-default} to give it a default")
+}
+
+func TestInterpolateEnvString(t *testing.T) {
+	os.Setenv("TEST_REGION", "iad")
+	defer os.Unsetenv("TEST_REGION")
+	os.Unsetenv("TEST_UNSET")
+
+	tests := []struct {
+		in      string
+		want    string
+		changed bool
+	}{
+		{"plain", "plain", false},
+		{"${TEST_REGION}", "iad", true},
+		{"${TEST_UNSET:-ord}", "ord", true},
+		{"${TEST_REGION:-ord}", "iad", true},
+		{"$${TEST_REGION}", "${TEST_REGION}", true},
+		{"price: $$5", "price: $$5", false},
+		{"$$$${TEST_REGION}", "$$${TEST_REGION}", true},
+		{"$HOME", "$HOME", false},
+	}
+
+	for _, tt := range tests {
+		out, changed, err := interpolateEnvString(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, out, tt.in)
+		assert.Equal(t, tt.changed, changed, tt.in)
+	}
+
+	_, _, err := interpolateEnvString("a-${TEST_UNSET}-b")
+	assert.EqualError(t, err, "environment variable TEST_UNSET is not set, use ${TEST_UNSET:-default} to give it a default")
+}
+
+func TestLoadTOMLAppConfigWithIncludeCycle(t *testing.T) {
+	_, err := LoadAppConfig("./testdata/include-cycle.toml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "includes itself")
+}
+
+func TestLoadTOMLAppConfigWithEnvironment(t *testing.T) {
+	p, err := LoadAppConfigEnvironment("./testdata/environments.toml", "staging")
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp-staging", p.AppName)
+	assert.Equal(t, "lhr", p.Definition["primary_region"])
+	assert.Equal(t, "lhr", p.PrimaryRegion())
+	assert.Equal(t, map[string]interface{}{"LOG_LEVEL": "debug", "PORT": "8080"}, p.Definition["env"])
+	assert.NotContains(t, p.Definition, "environments")
+
+	p, err = LoadAppConfig("./testdata/environments.toml")
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp", p.AppName)
+	assert.Equal(t, "iad", p.PrimaryRegion())
+	assert.NotContains(t, p.Definition, "environments")
+
+	_, err = LoadAppConfigEnvironment("./testdata/environments.toml", "production")
+	assert.EqualError(t, err, `environments.toml: environment "production" not found, defined environments are: staging`)
+}
+
+func TestSetEnvVariablesMergesLoadedEnv(t *testing.T) {
+	p, err := LoadAppConfig("./testdata/environments.toml")
+	assert.NoError(t, err)
+
+	p.SetEnvVariables(map[string]string{"LOG_LEVEL": "debug", "VERSION": "abc123"})
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "PORT": "8080", "VERSION": "abc123"}, p.Definition["env"])
+}
+
+func TestDeploySettings(t *testing.T) {
+	p, err := LoadAppConfig("./testdata/deploy-settings.toml")
+	assert.NoError(t, err)
+
+	settings, err := p.DeploySettings()
+	assert.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, settings.WaitTimeout)
+	assert.Equal(t, 2*time.Minute, settings.GracePeriod)
+	assert.Equal(t, "bin/migrate", settings.ReleaseCommand)
+	assert.True(t, settings.Guard)
+	assert.Equal(t, 30*time.Minute, settings.GuardWindow)
+	assert.Equal(t, 0.25, settings.ErrorBudget)
+	assert.True(t, settings.MigrationLock)
+	assert.Equal(t, 5*time.Minute, settings.MigrationLockTimeout)
+
+	p.Definition["deploy"] = map[string]interface{}{"health_check_grace": "3m"}
+	grace, err := p.DeploySettings()
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Minute, grace.GracePeriod)
+
+	assert.Equal(t, 2, p.SetCheckGracePeriod(settings.GracePeriod))
+	for _, service := range configTables(p.Definition["services"]) {
+		for _, kind := range []string{"tcp_checks", "http_checks"} {
+			for _, check := range configTables(service[kind]) {
+				assert.Equal(t, "2m0s", check["grace_period"])
+			}
+		}
+	}
+}
+
+func TestSetAutostop(t *testing.T) {
+	p := NewAppConfig()
+	assert.Equal(t, 0, p.SetAutostop(true, true, 1))
+
+	p.Definition["services"] = []interface{}{
+		map[string]interface{}{"internal_port": int64(8080)},
+		map[string]interface{}{"internal_port": int64(9090), "auto_stop_machines": true},
+	}
+	assert.Equal(t, 2, p.SetAutostop(false, true, 0))
+	for _, service := range configTables(p.Definition["services"]) {
+		assert.Equal(t, false, service["auto_stop_machines"])
+		assert.Equal(t, true, service["auto_start_machines"])
+		assert.Equal(t, 0, service["min_machines_running"])
+	}
+}
+
+func TestResolveEnvTemplates(t *testing.T) {
+	p, err := LoadAppConfig("./testdata/env-templates.toml")
+	assert.NoError(t, err)
+	assert.True(t, p.HasEnvTemplates())
+
+	resolved, err := p.ResolveEnvTemplates(EnvTemplateVars{AppName: p.AppName, Region: p.PrimaryRegion()})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"CANONICAL_HOST", "PRIMARY_REGION"}, resolved)
+	assert.Equal(t, map[string]string{"PRIMARY_REGION": "ord", "CANONICAL_HOST": "myapp.fly.dev", "PORT": "8080"}, p.Definition["env"])
+	assert.False(t, p.HasEnvTemplates())
+
+	p, err = LoadAppConfig("./testdata/env-templates.toml")
+	assert.NoError(t, err)
+	_, err = p.ResolveEnvTemplates(EnvTemplateVars{AppName: "myapp"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env PRIMARY_REGION")
+
+	p, err = LoadAppConfig("./testdata/env-templates.toml")
+	assert.NoError(t, err)
+	delete(p.Definition, "deploy")
+	assert.False(t, p.HasEnvTemplates())
+	resolved, err = p.ResolveEnvTemplates(EnvTemplateVars{})
+	assert.NoError(t, err)
+	assert.Empty(t, resolved)
+	assert.Equal(t, "{{ .AppName }}.fly.dev", p.env()["CANONICAL_HOST"])
+}
+
+func TestValidateLocallyIsPermissive(t *testing.T) {
+	p, err := LoadAppConfig("./testdata/schema-permissive.toml")
+	assert.NoError(t, err)
+
+	problems, unknown, err := p.ValidateLocally()
+	assert.NoError(t, err)
+	// services default to tcp, and settings newer than the schema are allowed
+	assert.Empty(t, problems)
+	assert.Equal(t, []string{"metrics.scrape_interval"}, unknown)
+}
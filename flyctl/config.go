@@ -9,15 +9,30 @@ const (
 	ConfigAPIBaseURL      = "api_base_url"
 	ConfigAppName         = "app"
 	ConfigVerboseOutput   = "verbose"
+	ConfigQuietOutput     = "quiet"
 	ConfigJSONOutput      = "json"
+	ConfigYAMLOutput      = "yaml"
+	ConfigOutputFormat    = "format"
+	ConfigASCIIOutput     = "ascii"
 	ConfigBuiltinsfile    = "builtins_file"
 	ConfigGQLErrorLogging = "gqlerrorlogging"
 	ConfigInstaller       = "installer"
+	ConfigNoUpdateCheck   = "no_update_check"
+	ConfigNonInteractive  = "non_interactive"
+	ConfigDebugHTTP       = "debug_http"
+	ConfigDebugHTTPBodies = "debug_http_bodies"
+	ConfigDebugHTTPFile   = "debug_http_file"
+	ConfigAPIMaxRetries   = "api_max_retries"
+	ConfigAPIRetryDelay   = "api_retry_max_delay"
 	BuildKitNodeID        = "buildkit_node_id"
 
 	ConfigWireGuardState = "wire_guard_state"
+	ConfigImpersonation  = "impersonation"
+	ConfigAgentAutoStart = "agent_auto_start"
 
 	ConfigRegistryHost = "registry_host"
+
+	ConfigAliases = "alias"
 )
 
 const NSRoot = "flyctl"
@@ -0,0 +1,207 @@
+package flyctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// includeKey is the fly.toml key naming other config files to load first.
+// Settings in the including file override the included ones.
+const includeKey = "include"
+
+// environmentsKey is the fly.toml table of named environments, each
+// overriding settings of the rest of the config, e.g. [environments.staging]
+const environmentsKey = "environments"
+
+// loadConfigData loads a TOML config file along with the files it includes,
+// merged into one map. Paths in an include are relative to the file that
+// includes them. seen holds the files already being loaded, to catch cycles.
+// The include setting is left in the map, for the caller to check.
+func loadConfigData(path string, seen []string) (map[string]interface{}, error) {
+	for _, s := range seen {
+		if s == path {
+			chain := make([]string, 0, len(seen)+1)
+			for _, p := range append(seen, path) {
+				chain = append(chain, filepath.Base(p))
+			}
+			return nil, fmt.Errorf("%s includes itself: %s", filepath.Base(path), strings.Join(chain, " -> "))
+		}
+	}
+	seen = append(seen, path)
+
+	var data map[string]interface{}
+	if _, err := toml.DecodeFile(path, &data); err != nil {
+		return nil, err
+	}
+
+	includes, err := configIncludes(data[includeKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if len(includes) == 0 {
+		return data, nil
+	}
+
+	merged := map[string]interface{}{}
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		included, err := loadConfigData(include, seen)
+		if err != nil {
+			return nil, err
+		}
+		delete(included, includeKey)
+		mergeConfigData(merged, included)
+	}
+	mergeConfigData(merged, data)
+
+	return merged, nil
+}
+
+// applyEnvironment merges the named environment's settings over data and
+// removes the environments table, which isn't part of the app's definition
+func applyEnvironment(data map[string]interface{}, name string) error {
+	environments := map[string]interface{}{}
+	if v, ok := data[environmentsKey]; ok {
+		if environments, ok = v.(map[string]interface{}); !ok {
+			return fmt.Errorf("environments must be a table of environment tables")
+		}
+	}
+	delete(data, environmentsKey)
+
+	if name == "" {
+		return nil
+	}
+
+	overrides, ok := environments[name].(map[string]interface{})
+	if !ok {
+		names := make([]string, 0, len(environments))
+		for n := range environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("environment %q not found, no environments are defined", name)
+		}
+		return fmt.Errorf("environment %q not found, defined environments are: %s", name, strings.Join(names, ", "))
+	}
+
+	mergeConfigData(data, overrides)
+
+	return nil
+}
+
+// configIncludes reads the include setting, a path or a list of them
+func configIncludes(v interface{}) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		paths := make([]string, 0, len(v))
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("include must be a path or a list of paths")
+			}
+			paths = append(paths, s)
+		}
+		return paths, nil
+	}
+	return nil, fmt.Errorf("include must be a path or a list of paths")
+}
+
+// mergeConfigData merges src into dst. Tables are merged key by key, any
+// other value in src, arrays included, replaces the one in dst.
+func mergeConfigData(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcTable, srcIsTable := value.(map[string]interface{})
+		dstTable, dstIsTable := dst[key].(map[string]interface{})
+		if srcIsTable && dstIsTable {
+			mergeConfigData(dstTable, srcTable)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// envVarPattern matches ${NAME} and ${NAME:-default}, and $${ which escapes a
+// literal ${
+var envVarPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolateEnv replaces environment variable references in the string
+// values of data, reporting whether any were found
+func interpolateEnv(v interface{}) (interface{}, bool, error) {
+	switch v := v.(type) {
+	case string:
+		return interpolateEnvString(v)
+	case map[string]interface{}:
+		changed := false
+		for key, value := range v {
+			out, c, err := interpolateEnv(value)
+			if err != nil {
+				return nil, false, err
+			}
+			v[key] = out
+			changed = changed || c
+		}
+		return v, changed, nil
+	case []interface{}:
+		changed := false
+		for i := range v {
+			out, c, err := interpolateEnv(v[i])
+			if err != nil {
+				return nil, false, err
+			}
+			v[i] = out
+			changed = changed || c
+		}
+		return v, changed, nil
+	case []map[string]interface{}:
+		changed := false
+		for _, table := range v {
+			_, c, err := interpolateEnv(table)
+			if err != nil {
+				return nil, false, err
+			}
+			changed = changed || c
+		}
+		return v, changed, nil
+	}
+	return v, false, nil
+}
+
+func interpolateEnvString(s string) (interface{}, bool, error) {
+	var missing []string
+
+	out := envVarPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+
+		m := envVarPattern.FindStringSubmatch(ref)
+		if value, ok := os.LookupEnv(m[1]); ok {
+			return value
+		}
+		if m[2] != "" {
+			return m[3]
+		}
+		missing = append(missing, m[1])
+		return ref
+	})
+
+	if len(missing) > 0 {
+		return nil, false, fmt.Errorf("environment variable %s is not set, use ${%s:-default} to give it a default", missing[0], missing[0])
+	}
+
+	return out, out != s, nil
+}
@@ -0,0 +1,130 @@
+package flyctl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/superfly/flyctl/helpers"
+)
+
+// DeploySettings - the settings in fly.toml's [deploy] section that tune how
+// a deployment is monitored
+type DeploySettings struct {
+	// WaitTimeout is how long allocations get to pass their health checks
+	// before the deployment fails, zero for the platform's default
+	WaitTimeout time.Duration
+	// GracePeriod overrides the grace period of every health check, zero to
+	// keep each check's own
+	GracePeriod time.Duration
+	// ReleaseCommand runs in a temporary VM before the release is deployed
+	ReleaseCommand string
+	// Guard refuses deploys while the app is degraded
+	Guard bool
+	// GuardWindow is how far back the guard looks, zero for its default
+	GuardWindow time.Duration
+	// ErrorBudget is the fraction of health checks the guard lets fail
+	ErrorBudget float64
+	// MigrationLock holds the app's migration lock while the release command
+	// runs, so concurrent deploys can't run it at the same time
+	MigrationLock bool
+	// MigrationLockTimeout is how long to wait for another deploy to release
+	// the lock, zero for the default
+	MigrationLockTimeout time.Duration
+}
+
+// DeploySettings - reads wait_timeout, health_check_grace, release_command,
+// the guard settings and the migration lock settings from [deploy]
+func (ac *AppConfig) DeploySettings() (DeploySettings, error) {
+	var settings DeploySettings
+
+	deploy, ok := ac.Definition["deploy"].(map[string]interface{})
+	if !ok {
+		return settings, nil
+	}
+
+	var err error
+	if settings.WaitTimeout, err = configDuration(deploy["wait_timeout"]); err != nil {
+		return settings, fmt.Errorf("invalid deploy.wait_timeout: %w", err)
+	}
+	if settings.GracePeriod, err = configDuration(deploy["health_check_grace"]); err != nil {
+		return settings, fmt.Errorf("invalid deploy.health_check_grace: %w", err)
+	}
+	if cmd, ok := deploy["release_command"].(string); ok {
+		settings.ReleaseCommand = cmd
+	}
+	if guard, ok := deploy["guard"].(bool); ok {
+		settings.Guard = guard
+	}
+	if settings.GuardWindow, err = configDuration(deploy["guard_window"]); err != nil {
+		return settings, fmt.Errorf("invalid deploy.guard_window: %w", err)
+	}
+	switch budget := deploy["error_budget"].(type) {
+	case nil:
+	case float64:
+		settings.ErrorBudget = budget
+	case int64:
+		settings.ErrorBudget = float64(budget)
+	default:
+		return settings, fmt.Errorf("invalid deploy.error_budget: %v isn't a number", budget)
+	}
+	if settings.ErrorBudget < 0 || settings.ErrorBudget >= 1 {
+		return settings, fmt.Errorf("invalid deploy.error_budget: %v must be at least 0 and less than 1", settings.ErrorBudget)
+	}
+	if lock, ok := deploy["migration_lock"].(bool); ok {
+		settings.MigrationLock = lock
+	}
+	if settings.MigrationLockTimeout, err = configDuration(deploy["migration_lock_timeout"]); err != nil {
+		return settings, fmt.Errorf("invalid deploy.migration_lock_timeout: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SetCheckGracePeriod - sets the grace period of every service health check,
+// returning how many were changed
+func (ac *AppConfig) SetCheckGracePeriod(d time.Duration) int {
+	var changed int
+
+	for _, service := range configTables(ac.Definition["services"]) {
+		for _, kind := range []string{"tcp_checks", "http_checks", "script_checks"} {
+			for _, check := range configTables(service[kind]) {
+				check["grace_period"] = d.String()
+				changed++
+			}
+		}
+	}
+
+	return changed
+}
+
+// configTables - the tables of an array of tables, however it was decoded
+func configTables(v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case []map[string]interface{}:
+		return v
+	case []interface{}:
+		tables := []map[string]interface{}{}
+		for _, item := range v {
+			if table, ok := item.(map[string]interface{}); ok {
+				tables = append(tables, table)
+			}
+		}
+		return tables
+	}
+	return nil
+}
+
+// configDuration - a duration given in milliseconds or as a string like "10s"
+func configDuration(v interface{}) (time.Duration, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return time.Duration(v) * time.Millisecond, nil
+	case float64:
+		return time.Duration(v) * time.Millisecond, nil
+	case string:
+		return helpers.ParseDuration(v)
+	}
+	return 0, fmt.Errorf("%v isn't a duration", v)
+}
@@ -0,0 +1,111 @@
+package flyctl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// EnvTemplateVars are the values available to templates in env values, e.g.
+// CANONICAL_HOST = "{{ .AppName }}.fly.dev". Empty values are left out, so
+// templates using them fail rather than resolving to nothing.
+type EnvTemplateVars struct {
+	AppName string
+	Region  string
+}
+
+func (v EnvTemplateVars) data() map[string]string {
+	data := map[string]string{}
+	if v.AppName != "" {
+		data["AppName"] = v.AppName
+	}
+	if v.Region != "" {
+		data["Region"] = v.Region
+	}
+	return data
+}
+
+// envTemplatesEnabled reports whether env_templates = true in [deploy] opts
+// in to templates, so existing values containing {{ are otherwise left alone
+func (ac *AppConfig) envTemplatesEnabled() bool {
+	deploy, _ := ac.Definition["deploy"].(map[string]interface{})
+	enabled, _ := deploy["env_templates"].(bool)
+	return enabled
+}
+
+// HasEnvTemplates reports whether templates are turned on and any env value
+// is one
+func (ac *AppConfig) HasEnvTemplates() bool {
+	if !ac.envTemplatesEnabled() {
+		return false
+	}
+	for _, value := range ac.env() {
+		if strings.Contains(value, "{{") {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveEnvTemplates replaces the templates in env values with their
+// values from vars, returning the names of the env variables it changed.
+// Nothing changes unless templates are turned on.
+func (ac *AppConfig) ResolveEnvTemplates(vars EnvTemplateVars) ([]string, error) {
+	if !ac.envTemplatesEnabled() {
+		return nil, nil
+	}
+
+	env := ac.env()
+	data := vars.data()
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resolved := []string{}
+	for _, name := range names {
+		value := env[name]
+		if !strings.Contains(value, "{{") {
+			continue
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %w", name, err)
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("env %s: %w, available variables are .AppName and .Region", name, err)
+		}
+
+		env[name] = out.String()
+		resolved = append(resolved, name)
+	}
+
+	if len(resolved) > 0 {
+		ac.Definition["env"] = env
+	}
+
+	return resolved, nil
+}
+
+// env - a copy of the config's env block as strings
+func (ac *AppConfig) env() map[string]string {
+	env := map[string]string{}
+
+	switch rawEnv := ac.Definition["env"].(type) {
+	case map[string]string:
+		for k, v := range rawEnv {
+			env[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range rawEnv {
+			env[k] = fmt.Sprint(v)
+		}
+	}
+
+	return env
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/cache"
 	"github.com/superfly/flyctl/terminal"
 	"gopkg.in/yaml.v2"
 )
@@ -24,6 +25,8 @@ func InitConfig() {
 	}
 
 	initViper()
+
+	api.SetResponseCache(cache.New(CacheDir()))
 }
 
 // ConfigDir - Returns Directory holding the Config file
@@ -31,6 +34,11 @@ func ConfigDir() string {
 	return configDir
 }
 
+// CacheDir - Returns the directory cached API responses are kept in
+func CacheDir() string {
+	return filepath.Join(configDir, "cache")
+}
+
 // ConfigFilePath - returns the path to the config file
 func ConfigFilePath() string {
 	return path.Join(configDir, "config.yml")
@@ -71,6 +79,27 @@ func initViper() {
 
 	api.SetBaseURL(viper.GetString(ConfigAPIBaseURL))
 	api.SetErrorLog(viper.GetBool(ConfigGQLErrorLogging))
+	api.SetRetryPolicy(apiRetryPolicy())
+}
+
+// apiRetryPolicy - the API retry policy, with overrides from config.yml
+func apiRetryPolicy() api.RetryPolicy {
+	policy := api.DefaultRetryPolicy
+
+	if viper.IsSet(ConfigAPIMaxRetries) {
+		policy.MaxRetries = viper.GetInt(ConfigAPIMaxRetries)
+	}
+
+	if value := viper.GetString(ConfigAPIRetryDelay); value != "" {
+		delay, err := helpers.ParseDuration(value)
+		if err != nil {
+			terminal.Warnf("Ignoring invalid %s in config: %s\n", ConfigAPIRetryDelay, err)
+		} else {
+			policy.MaxDelay = delay
+		}
+	}
+
+	return policy
 }
 
 func loadConfig() error {
@@ -122,7 +151,7 @@ func GetAPIToken() string {
 
 }
 
-var writeableConfigKeys = []string{ConfigAPIToken, ConfigInstaller, ConfigWireGuardState, BuildKitNodeID}
+var writeableConfigKeys = []string{ConfigAPIToken, ConfigInstaller, ConfigWireGuardState, ConfigImpersonation, ConfigAgentAutoStart, BuildKitNodeID}
 
 func SaveConfig() error {
 	BackgroundTaskWG.Add(1)
@@ -0,0 +1,56 @@
+package flyctl
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Impersonation - an org admin's session acting as one of the org's members
+type Impersonation struct {
+	ID        string
+	User      string
+	Org       string
+	Role      string
+	ExpiresAt time.Time
+}
+
+// GetImpersonation - returns the impersonation session in use, nil when there
+// isn't one or it has expired
+func GetImpersonation() *Impersonation {
+	settings := viper.GetStringMapString(ConfigImpersonation)
+	if settings["id"] == "" {
+		return nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, settings["expires_at"])
+	if err != nil || time.Now().After(expiresAt) {
+		return nil
+	}
+
+	return &Impersonation{
+		ID:        settings["id"],
+		User:      settings["user"],
+		Org:       settings["org"],
+		Role:      settings["role"],
+		ExpiresAt: expiresAt,
+	}
+}
+
+// SetImpersonation - saves the impersonation session to use, nil to stop
+// impersonating
+func SetImpersonation(imp *Impersonation) error {
+	if imp == nil {
+		viper.Set(ConfigImpersonation, map[string]string{})
+	} else {
+		viper.Set(ConfigImpersonation, map[string]string{
+			"id":         imp.ID,
+			"user":       imp.User,
+			"org":        imp.Org,
+			"role":       imp.Role,
+			"expires_at": imp.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+
+	return SaveConfig()
+}
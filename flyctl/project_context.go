@@ -0,0 +1,79 @@
+package flyctl
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ProjectContextDir - the per-project directory flyctl keeps its state in
+const ProjectContextDir = ".fly"
+
+const projectContextFileName = "context.toml"
+
+// ProjectContext - the app, organization and environment selected for a
+// project, so commands run inside it don't need -a, --org or --environment
+type ProjectContext struct {
+	App         string `toml:"app,omitempty"`
+	Org         string `toml:"org,omitempty"`
+	Environment string `toml:"environment,omitempty"`
+	// Path is the file the context is kept in
+	Path string `toml:"-"`
+}
+
+// NewProjectContext - an empty context for the project in dir
+func NewProjectContext(dir string) *ProjectContext {
+	return &ProjectContext{Path: filepath.Join(dir, ProjectContextDir, projectContextFileName)}
+}
+
+// FindProjectContext - the context of the project dir is in, looked up in
+// dir and its parents like git does, or nil when there's none. The search
+// stops below the home directory, whose .fly is flyctl's config directory.
+func FindProjectContext(dir string) (*ProjectContext, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	home, _ := os.UserHomeDir()
+
+	for {
+		if home != "" && dir == filepath.Clean(home) {
+			return nil, nil
+		}
+
+		path := filepath.Join(dir, ProjectContextDir, projectContextFileName)
+		if _, err := os.Stat(path); err == nil {
+			context := &ProjectContext{Path: path}
+			if _, err := toml.DecodeFile(path, context); err != nil {
+				return nil, err
+			}
+			return context, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Empty reports whether nothing is selected
+func (c *ProjectContext) Empty() bool {
+	return c.App == "" && c.Org == "" && c.Environment == ""
+}
+
+// Save - writes the context to its file, creating the .fly directory
+func (c *ProjectContext) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, buf.Bytes(), 0644)
+}
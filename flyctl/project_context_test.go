@@ -0,0 +1,54 @@
+package flyctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectContext(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "web", "src")
+	assert.NoError(t, os.MkdirAll(nested, 0755))
+
+	context, err := FindProjectContext(nested)
+	assert.NoError(t, err)
+	assert.Nil(t, context)
+
+	saved := NewProjectContext(root)
+	saved.App = "web-staging"
+	saved.Environment = "staging"
+	assert.NoError(t, saved.Save())
+
+	context, err = FindProjectContext(nested)
+	assert.NoError(t, err)
+	assert.Equal(t, "web-staging", context.App)
+	assert.Equal(t, "staging", context.Environment)
+	assert.Equal(t, "", context.Org)
+	assert.Equal(t, filepath.Join(root, ".fly", "context.toml"), context.Path)
+}
+
+func TestProjectContextStopsAtHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := filepath.Join(home, "web")
+	assert.NoError(t, os.MkdirAll(project, 0755))
+
+	saved := NewProjectContext(home)
+	saved.App = "everything"
+	assert.NoError(t, saved.Save())
+
+	context, err := FindProjectContext(project)
+	assert.NoError(t, err)
+	assert.Nil(t, context)
+
+	saved = NewProjectContext(project)
+	saved.App = "web"
+	assert.NoError(t, saved.Save())
+
+	context, err = FindProjectContext(project)
+	assert.NoError(t, err)
+	assert.Equal(t, "web", context.App)
+}
@@ -0,0 +1,33 @@
+package flyctl
+
+import (
+	_ "embed"
+
+	"github.com/superfly/flyctl/internal/schema"
+)
+
+// AppConfigSchema is the JSON schema for fly.toml, bundled so configs can be
+// validated offline and editors can offer completion
+//
+//go:embed appconfig.schema.json
+var AppConfigSchema []byte
+
+// ValidateLocally checks the config against AppConfigSchema without calling
+// the API, returning a description of each problem found and the path of each
+// setting the schema doesn't know, which may be a typo
+func (ac *AppConfig) ValidateLocally() (problems []string, unknown []string, err error) {
+	s, err := schema.Parse(AppConfigSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if problems, err = s.Validate(ac.Definition); err != nil {
+		return nil, nil, err
+	}
+
+	if unknown, err = s.Unknown(ac.Definition); err != nil {
+		return nil, nil, err
+	}
+
+	return problems, unknown, nil
+}
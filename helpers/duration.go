@@ -1,7 +1,10 @@
 package helpers
 
 import (
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,3 +25,17 @@ func Duration(d time.Duration, dicimal int) time.Duration {
 
 	return d
 }
+
+// ParseDuration extends time.ParseDuration with a "d" suffix for whole days,
+// e.g. "60d", which is how longer windows are usually expressed on the command line
+func ParseDuration(s string) (time.Duration, error) {
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
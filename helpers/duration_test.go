@@ -0,0 +1,21 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	d, err := ParseDuration("60d")
+	assert.NoError(t, err)
+	assert.Equal(t, 60*24*time.Hour, d)
+
+	d, err = ParseDuration("36h")
+	assert.NoError(t, err)
+	assert.Equal(t, 36*time.Hour, d)
+
+	_, err = ParseDuration("xd")
+	assert.Error(t, err)
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/pelletier/go-toml"
 )
 
+// docKeys - every key with document strings, parents before their children
+var docKeys []string
+
 func main() {
 	readFile := os.Args[1]
 
@@ -26,6 +29,12 @@ func main() {
 	dumpMap("", mapped)
 
 	fmt.Println("}\npanic(\"unknown command key \" + key)\n}")
+
+	fmt.Println("\n// Keys - Every key with document strings, parents before their children\nfunc Keys() []string {\nreturn []string{")
+	for _, key := range docKeys {
+		fmt.Printf("%q,\n", key)
+	}
+	fmt.Println("}\n}")
 }
 
 func dumpMap(prefix string, m map[string]interface{}) {
@@ -34,6 +43,7 @@ func dumpMap(prefix string, m map[string]interface{}) {
 		usage := m["usage"].(string)
 		short := m["shortHelp"].(string)
 		long := m["longHelp"].(string)
+		docKeys = append(docKeys, prefix)
 		fmt.Printf("case \"%s\":\nreturn KeyStrings{\"%s\",\"%s\",\n    `%s`,\n}\n",
 			prefix, strings.TrimSpace(usage), strings.TrimSpace(short), strings.TrimSpace(long))
 	}
@@ -0,0 +1,82 @@
+package imgsrc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	dockerclient "github.com/docker/docker/client"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// BuilderMetrics describes the remote builder's side of a build, to tell
+// when builds need a larger builder
+type BuilderMetrics struct {
+	Builder string `json:"builder"`
+	// QueueWaitMs is how long it took the builder to be ready for the build
+	QueueWaitMs int64 `json:"queueWaitMs"`
+	// CacheBytes is the disk used by image layers and the build cache after
+	// the build, -1 when unknown
+	CacheBytes int64 `json:"cacheBytes"`
+}
+
+// remoteBuild records what's known about the remote builder before building
+type remoteBuild struct {
+	mu        sync.Mutex
+	builder   string
+	queueWait time.Duration
+	readyAt   time.Time
+}
+
+func (rb *remoteBuild) ready(builder string, queueWait time.Duration) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.builder = builder
+	rb.queueWait = queueWait
+	rb.readyAt = time.Now()
+}
+
+// BuilderMetrics returns the remote builder's stats for the builds made so
+// far, nil when they were built locally
+func (r *Resolver) BuilderMetrics(ctx context.Context) *BuilderMetrics {
+	rb := r.dockerFactory.remote
+	if rb == nil {
+		return nil
+	}
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.readyAt.IsZero() {
+		return nil
+	}
+
+	m := &BuilderMetrics{
+		Builder:     rb.builder,
+		QueueWaitMs: rb.queueWait.Milliseconds(),
+		CacheBytes:  -1,
+	}
+
+	if docker, err := r.dockerFactory.buildFn(ctx); err == nil {
+		m.CacheBytes = dockerCacheUsage(ctx, docker)
+	}
+
+	return m
+}
+
+// dockerCacheUsage returns the disk used by image layers and the build cache,
+// or -1 if the daemon couldn't say
+func dockerCacheUsage(ctx context.Context, docker *dockerclient.Client) int64 {
+	du, err := docker.DiskUsage(ctx)
+	if err != nil {
+		terminal.Debugf("error fetching docker disk usage: %v\n", err)
+		return -1
+	}
+
+	size := du.LayersSize
+	for _, bc := range du.BuildCache {
+		size += bc.Size
+	}
+	return size
+}
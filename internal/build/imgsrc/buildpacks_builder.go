@@ -79,9 +79,10 @@ func (s *buildpacksBuilder) Run(ctx context.Context, dockerFactory *dockerClient
 	}
 
 	return &DeploymentImage{
-		ID:   img.ID,
-		Tag:  opts.Tag,
-		Size: img.Size,
+		ID:     img.ID,
+		Tag:    opts.Tag,
+		Size:   img.Size,
+		Config: inspectImageConfig(ctx, docker, img.ID),
 	}, nil
 }
 
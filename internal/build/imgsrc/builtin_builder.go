@@ -97,9 +97,10 @@ func (ds *builtinBuilder) Run(ctx context.Context, dockerFactory *dockerClientFa
 	fmt.Println(img)
 
 	return &DeploymentImage{
-		ID:   img.ID,
-		Tag:  opts.Tag,
-		Size: img.Size,
+		ID:     img.ID,
+		Tag:    opts.Tag,
+		Size:   img.Size,
+		Config: newImageConfig(img.Config),
 	}, nil
 
 }
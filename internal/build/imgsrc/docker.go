@@ -30,6 +30,8 @@ import (
 type dockerClientFactory struct {
 	mode    DockerDaemonType
 	buildFn func(ctx context.Context) (*dockerclient.Client, error)
+	// remote is set when building on a remote builder
+	remote *remoteBuild
 }
 
 func newDockerClientFactory(daemonType DockerDaemonType, apiClient *api.Client, appName string, streams *iostreams.IOStreams) *dockerClientFactory {
@@ -53,17 +55,21 @@ func newDockerClientFactory(daemonType DockerDaemonType, apiClient *api.Client,
 	if daemonType.AllowRemote() {
 		terminal.Debug("trying remote docker daemon")
 		var cachedDocker *dockerclient.Client
+		remote := &remoteBuild{}
 
 		return &dockerClientFactory{
-			mode: DockerDaemonTypeRemote,
+			mode:   DockerDaemonTypeRemote,
+			remote: remote,
 			buildFn: func(ctx context.Context) (*dockerclient.Client, error) {
 				if cachedDocker != nil {
 					return cachedDocker, nil
 				}
-				c, err := newRemoteDockerClient(ctx, apiClient, appName, streams)
+				start := time.Now()
+				c, builder, err := newRemoteDockerClient(ctx, apiClient, appName, streams)
 				if err != nil {
 					return nil, err
 				}
+				remote.ready(builder, time.Since(start))
 				cachedDocker = c
 				return cachedDocker, nil
 			},
@@ -161,10 +167,12 @@ func (e *remoteBuilderError) Error() string {
 	return fmt.Sprintf("remote builder %s error %s", e.RemoteBuilderName, e.Err)
 }
 
-func newRemoteDockerClient(ctx context.Context, apiClient *api.Client, appName string, streams *iostreams.IOStreams) (*dockerclient.Client, error) {
+// newRemoteDockerClient connects to the app's remote builder, returning a
+// client for it and the builder's app name
+func newRemoteDockerClient(ctx context.Context, apiClient *api.Client, appName string, streams *iostreams.IOStreams) (*dockerclient.Client, string, error) {
 	host, remoteBuilderAppName, err := remoteBuilderURL(apiClient, appName)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	terminal.Debugf("Remote Docker builder host: %s\n", host)
@@ -237,7 +245,7 @@ func newRemoteDockerClient(ctx context.Context, apiClient *api.Client, appName s
 	if err = eg.Wait(); err != nil {
 		captureRemoteBuilderError(err, remoteBuilderAppName)
 
-		return nil, err
+		return nil, "", err
 	}
 
 	if err := ctx.Err(); err != nil {
@@ -246,15 +254,15 @@ func newRemoteDockerClient(ctx context.Context, apiClient *api.Client, appName s
 		streams.StopProgressIndicator()
 		if errors.Is(err, context.DeadlineExceeded) {
 			terminal.Warnf("Remote builder did not start on time. Check remote builder logs with `flyctl logs -a %s`\n", remoteBuilderAppName)
-			return nil, errors.New("remote builder app unavailable")
+			return nil, "", errors.New("remote builder app unavailable")
 		}
 
-		return nil, err
+		return nil, "", err
 	}
 
 	streams.StopProgressIndicatorMsg(fmt.Sprintf("Remote builder %s ready", remoteBuilderAppName))
 
-	return <-clientCh, nil
+	return <-clientCh, remoteBuilderAppName, nil
 }
 
 func captureRemoteBuilderError(err error, builderAppName string) {
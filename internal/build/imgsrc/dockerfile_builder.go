@@ -166,9 +166,10 @@ func (ds *dockerfileBuilder) Run(ctx context.Context, dockerFactory *dockerClien
 	}
 
 	return &DeploymentImage{
-		ID:   img.ID,
-		Tag:  opts.Tag,
-		Size: img.Size,
+		ID:     img.ID,
+		Tag:    opts.Tag,
+		Size:   img.Size,
+		Config: newImageConfig(img.Config),
 	}, nil
 }
 
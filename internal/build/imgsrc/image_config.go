@@ -0,0 +1,109 @@
+package imgsrc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// ImageConfig is the part of an image's config that has to agree with the
+// app's fly.toml
+type ImageConfig struct {
+	ExposedPorts []int
+	User         string
+	Entrypoint   []string
+	Cmd          []string
+}
+
+func newImageConfig(cfg *container.Config) *ImageConfig {
+	if cfg == nil {
+		return nil
+	}
+
+	ic := &ImageConfig{
+		User:       cfg.User,
+		Entrypoint: cfg.Entrypoint,
+		Cmd:        cfg.Cmd,
+	}
+	for port := range cfg.ExposedPorts {
+		if port.Proto() != "tcp" {
+			continue
+		}
+		ic.ExposedPorts = append(ic.ExposedPorts, port.Int())
+	}
+	sort.Ints(ic.ExposedPorts)
+
+	return ic
+}
+
+// inspectImageConfig returns the config of a local image, nil when it can't
+// be inspected
+func inspectImageConfig(ctx context.Context, docker *dockerclient.Client, imageID string) *ImageConfig {
+	img, _, err := docker.ImageInspectWithRaw(ctx, imageID)
+	if err != nil {
+		terminal.Debugf("could not inspect image %s: %v\n", imageID, err)
+		return nil
+	}
+	return newImageConfig(img.Config)
+}
+
+// Mismatches returns warnings for the ways the image disagrees with the
+// internal ports of the app's services. Without processes in fly.toml to give
+// the commands, the image has to have something to run.
+func (c *ImageConfig) Mismatches(internalPorts []int, hasProcesses bool) []string {
+	if c == nil {
+		return nil
+	}
+
+	warnings := []string{}
+
+	if len(c.ExposedPorts) > 0 {
+		for _, port := range internalPorts {
+			if !containsInt(c.ExposedPorts, port) {
+				warnings = append(warnings, fmt.Sprintf("fly.toml says internal_port %d but image exposes %s", port, formatPorts(c.ExposedPorts)))
+			}
+		}
+	}
+
+	if !c.runsAsRoot() {
+		for _, port := range internalPorts {
+			if port < 1024 {
+				warnings = append(warnings, fmt.Sprintf("internal_port %d is privileged but image runs as user %q, which usually can't listen on it", port, c.User))
+			}
+		}
+	}
+
+	if len(c.Entrypoint) == 0 && len(c.Cmd) == 0 && !hasProcesses {
+		warnings = append(warnings, "image has no ENTRYPOINT or CMD and fly.toml has no processes, VMs will have nothing to run")
+	}
+
+	return warnings
+}
+
+func (c *ImageConfig) runsAsRoot() bool {
+	user := strings.SplitN(c.User, ":", 2)[0]
+	return user == "" || user == "root" || user == "0"
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPorts(ports []int) string {
+	out := make([]string, len(ports))
+	for i, port := range ports {
+		out[i] = strconv.Itoa(port)
+	}
+	return strings.Join(out, ", ")
+}
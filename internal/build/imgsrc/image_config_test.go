@@ -0,0 +1,67 @@
+package imgsrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageConfigMismatches(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *ImageConfig
+		internalPorts []int
+		hasProcesses  bool
+		expected      []string
+	}{
+		{
+			name:          "matching",
+			config:        &ImageConfig{ExposedPorts: []int{8080}, Cmd: []string{"server"}},
+			internalPorts: []int{8080},
+			expected:      []string{},
+		},
+		{
+			name:          "nothing exposed",
+			config:        &ImageConfig{Cmd: []string{"server"}},
+			internalPorts: []int{3000},
+			expected:      []string{},
+		},
+		{
+			name:          "port mismatch",
+			config:        &ImageConfig{ExposedPorts: []int{8080, 9090}, Cmd: []string{"server"}},
+			internalPorts: []int{3000},
+			expected:      []string{"fly.toml says internal_port 3000 but image exposes 8080, 9090"},
+		},
+		{
+			name:          "privileged port as non-root",
+			config:        &ImageConfig{User: "app:app", Cmd: []string{"server"}},
+			internalPorts: []int{80},
+			expected:      []string{`internal_port 80 is privileged but image runs as user "app:app", which usually can't listen on it`},
+		},
+		{
+			name:     "nothing to run",
+			config:   &ImageConfig{},
+			expected: []string{"image has no ENTRYPOINT or CMD and fly.toml has no processes, VMs will have nothing to run"},
+		},
+		{
+			name:         "processes to run",
+			config:       &ImageConfig{},
+			hasProcesses: true,
+			expected:     []string{},
+		},
+		{
+			name:     "entrypoint only",
+			config:   &ImageConfig{Entrypoint: []string{"/docker-entrypoint.sh"}},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.config.Mismatches(test.internalPorts, test.hasProcesses))
+		})
+	}
+
+	var unknown *ImageConfig
+	assert.Empty(t, unknown.Mismatches([]int{8080}, false))
+}
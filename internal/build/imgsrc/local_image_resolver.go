@@ -85,9 +85,10 @@ func (s *localImageResolver) Run(ctx context.Context, dockerFactory *dockerClien
 	}
 
 	di := &DeploymentImage{
-		ID:   img.ID,
-		Tag:  opts.Tag,
-		Size: img.Size,
+		ID:     img.ID,
+		Tag:    opts.Tag,
+		Size:   img.Size,
+		Config: inspectImageConfig(ctx, docker, img.ID),
 	}
 
 	return di, nil
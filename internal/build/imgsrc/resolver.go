@@ -39,6 +39,9 @@ type DeploymentImage struct {
 	ID   string
 	Tag  string
 	Size int64
+	// Config is nil when the image's config couldn't be inspected, e.g. for
+	// images resolved from a remote registry
+	Config *ImageConfig
 }
 
 type Resolver struct {
@@ -0,0 +1,100 @@
+// Package cache stores API responses on disk for a short time so repeated
+// lookups within and across commands don't refetch the same data.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/terminal"
+)
+
+// Disk caches values as JSON files in a directory
+type Disk struct {
+	dir string
+}
+
+// New returns a cache stored in dir
+func New(dir string) *Disk {
+	return &Disk{dir: dir}
+}
+
+type entry struct {
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Value     json.RawMessage `json:"value"`
+}
+
+func (d *Disk) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
+
+// Get loads the unexpired value stored under key into v
+func (d *Disk) Get(key string, v interface{}) bool {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || time.Now().After(e.ExpiresAt) {
+		return false
+	}
+
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		terminal.Debug("error reading cached", key, err)
+		return false
+	}
+
+	return true
+}
+
+// Set stores v under key for ttl. Failures are only logged, the cache is an
+// optimization.
+func (d *Disk) Set(key string, v interface{}, ttl time.Duration) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		terminal.Debug("error caching", key, err)
+		return
+	}
+
+	data, err := json.Marshal(entry{ExpiresAt: time.Now().Add(ttl), Value: value})
+	if err != nil {
+		terminal.Debug("error caching", key, err)
+		return
+	}
+
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		terminal.Debug("error creating cache directory", err)
+		return
+	}
+
+	if err := os.WriteFile(d.path(key), data, 0600); err != nil {
+		terminal.Debug("error caching", key, err)
+	}
+}
+
+// Delete removes every value whose key starts with prefix
+func (d *Disk) Delete(prefix string) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), prefix) {
+			os.Remove(filepath.Join(d.dir, f.Name()))
+		}
+	}
+}
+
+// Clear removes everything in the cache
+func (d *Disk) Clear() error {
+	err := os.RemoveAll(d.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
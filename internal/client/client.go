@@ -38,6 +38,9 @@ func (c *Client) InitApi() bool {
 	apiToken := flyctl.GetAPIToken()
 	if apiToken != "" {
 		apiClient := api.NewClient(apiToken, flyctl.Version)
+		if imp := flyctl.GetImpersonation(); imp != nil {
+			apiClient.SetImpersonation(imp.ID)
+		}
 		c.api = apiClient
 	}
 	return c.Authenticated()
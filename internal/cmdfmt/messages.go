@@ -9,10 +9,23 @@ import (
 
 // extract message printing from cmdctx until we find a better way to do this
 
+var quiet bool
+
+// SetQuiet - Sets whether progress messages should be suppressed
+func SetQuiet(q bool) {
+	quiet = q
+}
+
 func PrintBegin(w io.Writer, args ...interface{}) {
+	if quiet {
+		return
+	}
 	fmt.Fprintln(w, aurora.Green("==> "+fmt.Sprint(args...)))
 }
 
 func PrintDone(w io.Writer, args ...interface{}) {
+	if quiet {
+		return
+	}
 	fmt.Fprintln(w, aurora.Gray(20, "--> "+fmt.Sprint(args...)))
 }
@@ -9,6 +9,9 @@ import (
 )
 
 func PrintServicesList(s *iostreams.IOStreams, services []api.Service) {
+	if quiet {
+		return
+	}
 	fmt.Fprintln(s.Out, aurora.Bold("Services"))
 	for _, svc := range services {
 		fmt.Fprintln(s.Out, svc.Description)
@@ -0,0 +1,91 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDotenv parses NAME=VALUE lines into a map. Values wrapped in triple
+// quotes may span lines, and blank lines and lines starting with # are
+// skipped.
+func ParseDotenv(data string) (map[string]string, error) {
+	return parseDotenv(data, false)
+}
+
+// ParseEnvFile parses a .env style file like ParseDotenv, except that single
+// and double quotes around a value are removed and lines may start with export.
+func ParseEnvFile(data string) (map[string]string, error) {
+	return parseDotenv(data, true)
+}
+
+func parseDotenv(data string, shell bool) (map[string]string, error) {
+	out := make(map[string]string)
+
+	parsestate := 0
+	parsedkey := ""
+	var parsebuffer strings.Builder
+
+	for _, line := range strings.Split(data, "\n") {
+		switch parsestate {
+		case 0:
+			if line == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+
+			pair := line
+			if shell {
+				pair = strings.TrimPrefix(strings.TrimSpace(line), "export ")
+			}
+
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("Values must be provided as NAME=VALUE pairs (%s is invalid)", line)
+			}
+			if shell {
+				parts[0] = strings.TrimSpace(parts[0])
+			}
+
+			if strings.HasPrefix(parts[1], `"""`) {
+				// Switch to multiline
+				parsestate = 1
+				parsedkey = parts[0]
+				parsebuffer.WriteString(strings.TrimPrefix(parts[1], `"""`))
+				parsebuffer.WriteString("\n")
+			} else if shell {
+				out[parts[0]] = unquote(parts[1])
+			} else {
+				out[parts[0]] = parts[1]
+			}
+		case 1:
+			if strings.HasSuffix(line, `"""`) {
+				// End of multiline
+				parsebuffer.WriteString(strings.TrimSuffix(line, `"""`))
+				out[parsedkey] = parsebuffer.String()
+				parsebuffer.Reset()
+				parsestate = 0
+				parsedkey = ""
+			} else {
+				if line != "" {
+					parsebuffer.WriteString(line)
+				}
+				parsebuffer.WriteString("\n")
+			}
+		}
+	}
+
+	if parsestate == 1 {
+		return nil, fmt.Errorf("the value of %s is missing its closing \"\"\"", parsedkey)
+	}
+
+	return out, nil
+}
+
+// unquote removes a matching pair of single or double quotes around value
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if q := value[0]; (q == '"' || q == '\'') && value[len(value)-1] == q {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
@@ -0,0 +1,105 @@
+package cmdutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "plain",
+			data: "LOG_LEVEL=debug\nPORT=8080\n",
+			want: map[string]string{"LOG_LEVEL": "debug", "PORT": "8080"},
+		},
+		{
+			name: "comments and blank lines",
+			data: "# settings\n\nLOG_LEVEL=debug\n  # indented comment\n",
+			want: map[string]string{"LOG_LEVEL": "debug"},
+		},
+		{
+			name: "double quotes",
+			data: `GREETING="hello world"`,
+			want: map[string]string{"GREETING": "hello world"},
+		},
+		{
+			name: "single quotes",
+			data: `PATTERN='a $b c'`,
+			want: map[string]string{"PATTERN": "a $b c"},
+		},
+		{
+			name: "unmatched quotes are kept",
+			data: "A=\"open\nB=close'\nC='\n",
+			want: map[string]string{"A": `"open`, "B": "close'", "C": "'"},
+		},
+		{
+			name: "empty quotes",
+			data: `EMPTY=""`,
+			want: map[string]string{"EMPTY": ""},
+		},
+		{
+			name: "export",
+			data: "export DATABASE_URL=\"postgres://db\"\nexport   PORT=8080\n",
+			want: map[string]string{"DATABASE_URL": "postgres://db", "PORT": "8080"},
+		},
+		{
+			name: "equals in value",
+			data: "QUERY=a=b",
+			want: map[string]string{"QUERY": "a=b"},
+		},
+		{
+			name: "multiline",
+			data: "KEY=\"\"\"line one\nline two\"\"\"\nNEXT=1",
+			want: map[string]string{"KEY": "line one\nline two", "NEXT": "1"},
+		},
+		{
+			name:    "missing equals",
+			data:    "LOG_LEVEL",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated multiline",
+			data:    "KEY=\"\"\"line one\nline two",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEnvFile(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDotenvKeepsValuesAsWritten(t *testing.T) {
+	got, err := ParseDotenv("GREETING=\"hello world\"\nPATTERN='a $b c'\nexport PORT=8080\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"GREETING":    `"hello world"`,
+		"PATTERN":     "'a $b c'",
+		"export PORT": "8080",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
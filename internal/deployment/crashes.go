@@ -0,0 +1,149 @@
+package deployment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// exitCodePattern finds the exit code in a termination event's message,
+// e.g. "Exit Code: 137, Signal: 9"
+var exitCodePattern = regexp.MustCompile(`(?i)exit code:?\s*(-?\d+)`)
+
+// oomExitCode is the exit code of a process killed with SIGKILL, which is
+// how the kernel's OOM killer stops it
+const oomExitCode = 137
+
+// CrashGroup is a set of instance crashes with the same cause
+type CrashGroup struct {
+	Cause     string    `json:"cause"`
+	Count     int       `json:"count"`
+	Instances []string  `json:"instances"`
+	Regions   []string  `json:"regions"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	// Example is the message of the latest crash
+	Example string `json:"example"`
+}
+
+// SummarizeCrashes groups the crashes of allocs since since by cause: OOM
+// kills, non-zero exit codes, restart loops and failures with no event to
+// explain them. The most frequent causes come first.
+func SummarizeCrashes(allocs []*api.AllocationStatus, since time.Time) []CrashGroup {
+	groups := map[string]*CrashGroup{}
+	add := func(cause string, alloc *api.AllocationStatus, at time.Time, message string) {
+		g, ok := groups[cause]
+		if !ok {
+			g = &CrashGroup{Cause: cause, FirstSeen: at, LastSeen: at}
+			groups[cause] = g
+		}
+		g.Count++
+		g.Instances = appendUnique(g.Instances, alloc.IDShort)
+		g.Regions = appendUnique(g.Regions, alloc.Region)
+		if at.Before(g.FirstSeen) {
+			g.FirstSeen = at
+		}
+		if !at.Before(g.LastSeen) {
+			g.LastSeen = at
+			g.Example = message
+		}
+	}
+
+	for _, alloc := range allocs {
+		explained := false
+		restarts := []api.AllocationEvent{}
+
+		for _, event := range alloc.Events {
+			if event.Timestamp.Before(since) {
+				continue
+			}
+			if event.Type == "Restarting" {
+				restarts = append(restarts, event)
+			}
+			if cause := crashCause(event); cause != "" {
+				add(cause, alloc, event.Timestamp, event.Message)
+				explained = true
+			}
+		}
+
+		if len(restarts) >= crashLoopRestarts {
+			last := restarts[len(restarts)-1]
+			add("restart loop", alloc, last.Timestamp, fmt.Sprintf("restarted %d times", len(restarts)))
+		}
+
+		if !explained && (alloc.Failed || alloc.Status == "failed") && !alloc.UpdatedAt.Before(since) {
+			add("failed", alloc, alloc.UpdatedAt, failedChecks(alloc))
+		}
+	}
+
+	summary := make([]CrashGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.Instances)
+		sort.Strings(g.Regions)
+		summary = append(summary, *g)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].Cause < summary[j].Cause
+	})
+
+	return summary
+}
+
+// crashCause - why an event's instance crashed, empty when it isn't a crash
+func crashCause(event api.AllocationEvent) string {
+	message := strings.ToLower(event.Message)
+	if strings.Contains(message, "oom") || strings.Contains(message, "out of memory") {
+		return "OOM killed"
+	}
+
+	if event.Type != "Terminated" {
+		return ""
+	}
+	m := exitCodePattern.FindStringSubmatch(event.Message)
+	if m == nil {
+		return ""
+	}
+	code, err := strconv.Atoi(m[1])
+	if err != nil || code == 0 {
+		return ""
+	}
+	if code == oomExitCode {
+		return "killed (exit code 137, often out of memory)"
+	}
+	return fmt.Sprintf("exit code %d", code)
+}
+
+// failedChecks - the critical checks of a failed allocation, to hint at
+// why it failed
+func failedChecks(alloc *api.AllocationStatus) string {
+	names := map[string]bool{}
+	for _, check := range alloc.Checks {
+		if check.Status == "critical" {
+			names[check.Name] = true
+		}
+	}
+	if len(names) == 0 {
+		return "failed without an exit event"
+	}
+	return "failed with critical checks: " + strings.Join(sortedNames(names), ", ")
+}
+
+func appendUnique(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,54 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestSummarizeCrashes(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-24 * time.Hour)
+
+	summary := SummarizeCrashes([]*api.AllocationStatus{
+		{IDShort: "a", Region: "iad", Events: []api.AllocationEvent{
+			{Type: "Terminated", Message: "Exit Code: 1, Signal: 0", Timestamp: now.Add(-3 * time.Hour)},
+			{Type: "Restarting", Timestamp: now.Add(-3 * time.Hour)},
+			{Type: "Terminated", Message: "Exit Code: 1, Signal: 0", Timestamp: now.Add(-2 * time.Hour)},
+			{Type: "Restarting", Timestamp: now.Add(-2 * time.Hour)},
+			// before the window
+			{Type: "Terminated", Message: "Exit Code: 2", Timestamp: now.Add(-48 * time.Hour)},
+		}},
+		{IDShort: "b", Region: "lhr", Events: []api.AllocationEvent{
+			{Type: "Driver Failure", Message: "task killed: OOM", Timestamp: now.Add(-time.Hour)},
+			{Type: "Terminated", Message: "Exit Code: 0", Timestamp: now.Add(-time.Hour)},
+		}},
+		{IDShort: "c", Region: "iad", Status: "failed", Failed: true, UpdatedAt: now.Add(-time.Minute), Checks: []api.CheckState{
+			{Name: "http", Status: "critical"},
+		}},
+	}, since)
+
+	if len(summary) != 4 {
+		t.Fatalf("expected 4 groups, got %+v", summary)
+	}
+
+	exits := summary[0]
+	if exits.Cause != "exit code 1" || exits.Count != 2 || len(exits.Instances) != 1 || exits.Regions[0] != "iad" {
+		t.Errorf("unexpected first group %+v", exits)
+	}
+	if !exits.FirstSeen.Equal(now.Add(-3*time.Hour)) || !exits.LastSeen.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("unexpected times %v - %v", exits.FirstSeen, exits.LastSeen)
+	}
+
+	causes := []string{summary[1].Cause, summary[2].Cause, summary[3].Cause}
+	want := []string{"OOM killed", "failed", "restart loop"}
+	for i := range want {
+		if causes[i] != want[i] {
+			t.Fatalf("expected causes %v, got %v", want, causes)
+		}
+	}
+	if summary[2].Example != "failed with critical checks: http" {
+		t.Errorf("unexpected example %q", summary[2].Example)
+	}
+}
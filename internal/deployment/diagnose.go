@@ -0,0 +1,350 @@
+package deployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// imagePullPatterns are how image pull failures show up in events and logs
+var imagePullPatterns = []string{
+	"pull access denied",
+	"manifest unknown",
+	"failed to pull",
+	"error pulling image",
+	"image not found",
+	"unauthorized: authentication required",
+}
+
+// schedulerPatterns are how placement failures show up in a deployment's
+// description
+var schedulerPatterns = []string{
+	"no capacity",
+	"insufficient",
+	"could not find",
+	"could not place",
+	"constraint",
+	"no volumes",
+	"placement failed",
+}
+
+// ReleaseEvidence is what's known about a release that failed
+type ReleaseEvidence struct {
+	App     string
+	Release *api.Release
+	// LastGood is the latest stable release before it, nil when there's none
+	LastGood *api.Release
+	// Allocations are the release's instances, with their events and checks
+	Allocations []*api.AllocationStatus
+	// Logs are the recent logs of its failed instances
+	Logs []api.LogEntry
+}
+
+// Cause is a probable reason a release failed, with what points to it and
+// commands to dig further or fix it
+type Cause struct {
+	Summary  string   `json:"summary"`
+	Evidence []string `json:"evidence"`
+	Next     []string `json:"next"`
+	// score ranks causes, the likeliest first
+	score int
+}
+
+// ConfigChange is a setting that differs between two configs, with its
+// values as JSON. A side is empty when the setting isn't there.
+type ConfigChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Diagnose ranks the probable causes of a release's failure. Failures that
+// stop instances from starting at all, like image pulls and placement,
+// outrank crashes and failing checks, and config changes since the last
+// good release come last unless nothing else explains the failure.
+func Diagnose(e ReleaseEvidence) []Cause {
+	causes := []Cause{}
+	add := func(c Cause) {
+		if c.Summary != "" {
+			causes = append(causes, c)
+		}
+	}
+
+	add(diagnoseImagePull(e))
+	add(diagnoseScheduler(e))
+	add(diagnoseReleaseCommand(e))
+	for _, c := range diagnoseCrashes(e) {
+		add(c)
+	}
+	add(diagnoseChecks(e))
+	add(diagnoseConfig(e, len(causes) == 0))
+
+	sort.SliceStable(causes, func(i, j int) bool { return causes[i].score > causes[j].score })
+	return causes
+}
+
+func diagnoseImagePull(e ReleaseEvidence) Cause {
+	evidence := []string{}
+	for _, alloc := range e.Allocations {
+		for _, event := range alloc.Events {
+			if containsAny(event.Message, imagePullPatterns) {
+				evidence = appendUnique(evidence, fmt.Sprintf("%s: %s", alloc.IDShort, event.Message))
+			}
+		}
+	}
+	for _, entry := range e.Logs {
+		if containsAny(entry.Message, imagePullPatterns) {
+			evidence = appendUnique(evidence, entry.Message)
+		}
+	}
+	if len(evidence) == 0 {
+		return Cause{}
+	}
+
+	image := e.Release.ImageRef
+	if image == "" {
+		image = "<image>"
+	}
+	return Cause{
+		Summary:  fmt.Sprintf("The image %s couldn't be pulled", image),
+		Evidence: limit(evidence, 3),
+		Next: []string{
+			fmt.Sprintf("docker pull %s", image),
+			"flyctl deploy --remote-only",
+		},
+		score: 100,
+	}
+}
+
+func diagnoseScheduler(e ReleaseEvidence) Cause {
+	status := e.Release.DeploymentStatus
+	if status == nil {
+		return Cause{}
+	}
+
+	evidence := []string{}
+	if containsAny(status.Description, schedulerPatterns) {
+		evidence = append(evidence, status.Description)
+	}
+	if status.DesiredCount > 0 && status.PlacedCount < status.DesiredCount {
+		evidence = append(evidence, fmt.Sprintf("%d of %d instances were placed", status.PlacedCount, status.DesiredCount))
+	}
+	if len(evidence) == 0 {
+		return Cause{}
+	}
+
+	return Cause{
+		Summary:  "Instances couldn't be placed in the app's regions",
+		Evidence: evidence,
+		Next: []string{
+			fmt.Sprintf("flyctl regions list -a %s", e.App),
+			fmt.Sprintf("flyctl volumes list -a %s", e.App),
+			fmt.Sprintf("flyctl scale show -a %s", e.App),
+		},
+		score: 90,
+	}
+}
+
+func diagnoseReleaseCommand(e ReleaseEvidence) Cause {
+	text := strings.ToLower(e.Release.Description + " " + e.Release.Reason)
+	if e.Release.DeploymentStatus != nil {
+		text += " " + strings.ToLower(e.Release.DeploymentStatus.Description)
+	}
+	if !strings.Contains(text, "release command") || !strings.Contains(text, "fail") {
+		return Cause{}
+	}
+
+	return Cause{
+		Summary:  "The release command failed, so the release wasn't deployed",
+		Evidence: []string{strings.TrimSpace(e.Release.Description)},
+		Next: []string{
+			fmt.Sprintf("flyctl logs -a %s", e.App),
+			"Run the release command locally against a copy of your data",
+		},
+		score: 95,
+	}
+}
+
+func diagnoseCrashes(e ReleaseEvidence) []Cause {
+	causes := []Cause{}
+	for _, g := range SummarizeCrashes(e.Allocations, time.Time{}) {
+		// failures without an exit are explained by their checks
+		if g.Cause == "failed" {
+			continue
+		}
+
+		instance := g.Instances[0]
+		next := []string{
+			fmt.Sprintf("flyctl logs -a %s -i %s", e.App, instance),
+			fmt.Sprintf("flyctl status instance %s -a %s", instance, e.App),
+		}
+		c := Cause{
+			Summary:  fmt.Sprintf("Instances crashed: %s", g.Cause),
+			Evidence: []string{fmt.Sprintf("%d times on %s in %s", g.Count, strings.Join(g.Instances, ", "), strings.Join(g.Regions, ", "))},
+			Next:     next,
+			score:    60 + g.Count,
+		}
+		if g.Example != "" {
+			c.Evidence = append(c.Evidence, g.Example)
+		}
+
+		if strings.Contains(g.Cause, "OOM") || strings.Contains(g.Cause, "137") {
+			c.Summary = fmt.Sprintf("Instances ran out of memory: %s", g.Cause)
+			c.Next = append([]string{fmt.Sprintf("flyctl scale memory <mb> -a %s", e.App)}, next...)
+			c.score = 80 + g.Count
+		}
+		causes = append(causes, c)
+	}
+	return causes
+}
+
+func diagnoseChecks(e ReleaseEvidence) Cause {
+	evidence := []string{}
+	for _, alloc := range e.Allocations {
+		for _, check := range alloc.Checks {
+			if check.Status != "critical" {
+				continue
+			}
+			line := fmt.Sprintf("%s on %s is critical", check.Name, alloc.IDShort)
+			if output := strings.TrimSpace(check.Output); output != "" {
+				line += ": " + firstLine(output)
+			}
+			evidence = appendUnique(evidence, line)
+		}
+	}
+	if len(evidence) == 0 {
+		return Cause{}
+	}
+
+	return Cause{
+		Summary:  "Health checks failed",
+		Evidence: limit(evidence, 5),
+		Next: []string{
+			fmt.Sprintf("flyctl checks list -a %s", e.App),
+			"Check the app listens on the internal_port in fly.toml, on 0.0.0.0",
+		},
+		score: 50,
+	}
+}
+
+// diagnoseConfig - the config changes since the last good release, ranked
+// higher when nothing else explains the failure
+func diagnoseConfig(e ReleaseEvidence, unexplained bool) Cause {
+	if e.LastGood == nil {
+		return Cause{}
+	}
+
+	evidence := []string{}
+	if e.LastGood.ImageRef != "" && e.Release.ImageRef != "" && e.LastGood.ImageRef != e.Release.ImageRef {
+		evidence = append(evidence, fmt.Sprintf("image: %s -> %s", e.LastGood.ImageRef, e.Release.ImageRef))
+	}
+	if e.LastGood.Config != nil && e.Release.Config != nil {
+		for _, change := range DiffDefinitions(e.LastGood.Config.Definition, e.Release.Config.Definition) {
+			evidence = append(evidence, fmt.Sprintf("%s: %s -> %s", change.Key, orNone(change.Old), orNone(change.New)))
+		}
+	}
+	if len(evidence) == 0 {
+		return Cause{}
+	}
+
+	score := 30
+	if unexplained {
+		score = 70
+	}
+	next := []string{}
+	if e.LastGood.ImageRef != "" {
+		next = append(next, fmt.Sprintf("flyctl deploy -a %s --image %s", e.App, e.LastGood.ImageRef))
+	}
+	next = append(next, fmt.Sprintf("flyctl config display -a %s", e.App))
+
+	return Cause{
+		Summary:  fmt.Sprintf("Changes since v%d, the last good release", e.LastGood.Version),
+		Evidence: evidence,
+		Next:     next,
+		score:    score,
+	}
+}
+
+// DiffDefinitions lists the settings that differ between two app configs,
+// with nested settings as dotted keys, e.g. services.0.internal_port
+func DiffDefinitions(old, new api.Definition) []ConfigChange {
+	oldValues := map[string]string{}
+	newValues := map[string]string{}
+	flattenDefinition("", map[string]interface{}(old), oldValues)
+	flattenDefinition("", map[string]interface{}(new), newValues)
+
+	keys := map[string]bool{}
+	for k := range oldValues {
+		keys[k] = true
+	}
+	for k := range newValues {
+		keys[k] = true
+	}
+
+	changes := []ConfigChange{}
+	for _, k := range sortedNames(keys) {
+		if oldValues[k] != newValues[k] {
+			changes = append(changes, ConfigChange{Key: k, Old: oldValues[k], New: newValues[k]})
+		}
+	}
+	return changes
+}
+
+func flattenDefinition(prefix string, value interface{}, out map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenDefinition(join(k), child, out)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenDefinition(join(strconv.Itoa(i)), child, out)
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprint(v))
+		}
+		out[prefix] = string(data)
+	}
+}
+
+func containsAny(s string, patterns []string) bool {
+	s = strings.ToLower(s)
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+func limit(list []string, n int) []string {
+	if len(list) > n {
+		return append(list[:n:n], fmt.Sprintf("and %d more", len(list)-n))
+	}
+	return list
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
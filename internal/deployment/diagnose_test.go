@@ -0,0 +1,109 @@
+package deployment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestDiagnose(t *testing.T) {
+	release := &api.Release{
+		Version:  12,
+		ImageRef: "registry.fly.io/app:v12",
+		Config: &api.AppConfig{Definition: api.Definition{
+			"env":      map[string]interface{}{"PORT": "3000"},
+			"services": []interface{}{map[string]interface{}{"internal_port": 3000}},
+		}},
+		DeploymentStatus: &api.DeploymentStatus{Description: "Failed due to unhealthy allocations", DesiredCount: 2, PlacedCount: 2},
+	}
+	lastGood := &api.Release{
+		Version:  10,
+		ImageRef: "registry.fly.io/app:v10",
+		Config: &api.AppConfig{Definition: api.Definition{
+			"env":      map[string]interface{}{"PORT": "8080", "DEBUG": "1"},
+			"services": []interface{}{map[string]interface{}{"internal_port": 8080}},
+		}},
+	}
+
+	causes := Diagnose(ReleaseEvidence{
+		App:      "app",
+		Release:  release,
+		LastGood: lastGood,
+		Allocations: []*api.AllocationStatus{
+			{IDShort: "a", Region: "iad", Events: []api.AllocationEvent{
+				{Type: "Driver Failure", Message: "task killed: OOM"},
+			}},
+			{IDShort: "b", Region: "iad", Checks: []api.CheckState{
+				{Name: "http", Status: "critical", Output: "connection refused\nmore"},
+				{Name: "tcp", Status: "passing"},
+			}},
+		},
+	})
+
+	summaries := []string{}
+	for _, c := range causes {
+		summaries = append(summaries, c.Summary)
+	}
+	want := []string{
+		"Instances ran out of memory: OOM killed",
+		"Health checks failed",
+		"Changes since v10, the last good release",
+	}
+	if strings.Join(summaries, "|") != strings.Join(want, "|") {
+		t.Fatalf("got causes %q", summaries)
+	}
+
+	if causes[1].Evidence[0] != "http on b is critical: connection refused" {
+		t.Errorf("unexpected check evidence %q", causes[1].Evidence)
+	}
+	config := strings.Join(causes[2].Evidence, "\n")
+	for _, line := range []string{
+		"image: registry.fly.io/app:v10 -> registry.fly.io/app:v12",
+		`env.DEBUG: "1" -> (none)`,
+		`env.PORT: "8080" -> "3000"`,
+		"services.0.internal_port: 8080 -> 3000",
+	} {
+		if !strings.Contains(config, line) {
+			t.Errorf("config evidence missing %q in\n%s", line, config)
+		}
+	}
+}
+
+func TestDiagnosePlacementAndPull(t *testing.T) {
+	causes := Diagnose(ReleaseEvidence{
+		App: "app",
+		Release: &api.Release{
+			ImageRef:         "registry.fly.io/app:v3",
+			DeploymentStatus: &api.DeploymentStatus{Description: "could not place: no capacity in syd", DesiredCount: 2, PlacedCount: 1},
+		},
+		Allocations: []*api.AllocationStatus{
+			{IDShort: "a", Events: []api.AllocationEvent{{Type: "Driver Failure", Message: "Failed to pull image: manifest unknown"}}},
+		},
+	})
+
+	if len(causes) != 2 {
+		t.Fatalf("expected 2 causes, got %+v", causes)
+	}
+	if !strings.HasPrefix(causes[0].Summary, "The image registry.fly.io/app:v3") {
+		t.Errorf("expected the pull failure first, got %q", causes[0].Summary)
+	}
+	if len(causes[1].Evidence) != 2 || causes[1].Evidence[1] != "1 of 2 instances were placed" {
+		t.Errorf("unexpected placement evidence %q", causes[1].Evidence)
+	}
+}
+
+func TestDiagnoseUnexplainedConfigChange(t *testing.T) {
+	causes := Diagnose(ReleaseEvidence{
+		App:      "app",
+		Release:  &api.Release{ImageRef: "app:v2"},
+		LastGood: &api.Release{Version: 1, ImageRef: "app:v1"},
+	})
+
+	if len(causes) != 1 || causes[0].score != 70 {
+		t.Fatalf("expected the config change to rank as the likely cause, got %+v", causes)
+	}
+	if causes[0].Next[0] != "flyctl deploy -a app --image app:v1" {
+		t.Errorf("unexpected next steps %q", causes[0].Next)
+	}
+}
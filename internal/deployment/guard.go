@@ -0,0 +1,95 @@
+package deployment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// DefaultGuardWindow is how far back the guard looks without a window
+const DefaultGuardWindow = 15 * time.Minute
+
+// Guard decides whether an app is healthy enough to deploy over, so fixes
+// aren't stacked on an unstable release without anyone noticing
+type Guard struct {
+	// Window is how far back to look for failures and restarts
+	Window time.Duration
+	// ErrorBudget is the fraction of health checks that may be critical
+	ErrorBudget float64
+}
+
+// Assess returns why the app, with allocs, is too degraded to deploy over.
+// It's empty when the app is healthy enough.
+func (g Guard) Assess(allocs []*api.AllocationStatus, now time.Time) []string {
+	window := g.Window
+	if window <= 0 {
+		window = DefaultGuardWindow
+	}
+	since := now.Add(-window)
+
+	reasons := []string{}
+
+	var total, critical int
+	failingChecks := map[string]bool{}
+	for _, alloc := range allocs {
+		if alloc.Status != "running" {
+			continue
+		}
+		total += alloc.PassingCheckCount + alloc.WarningCheckCount + alloc.CriticalCheckCount
+		critical += alloc.CriticalCheckCount
+		for _, check := range alloc.Checks {
+			if check.Status == "critical" {
+				failingChecks[check.Name] = true
+			}
+		}
+	}
+	if total > 0 && float64(critical)/float64(total) > g.ErrorBudget {
+		reason := fmt.Sprintf("%d of %d health checks are critical", critical, total)
+		if len(failingChecks) > 0 {
+			reason += " (" + strings.Join(sortedNames(failingChecks), ", ") + ")"
+		}
+		if g.ErrorBudget > 0 {
+			reason += fmt.Sprintf(", over the %.0f%% error budget", g.ErrorBudget*100)
+		}
+		reasons = append(reasons, reason)
+	}
+
+	failed := []string{}
+	looping := []string{}
+	for _, alloc := range allocs {
+		if (alloc.Failed || alloc.Status == "failed") && !alloc.UpdatedAt.Before(since) {
+			failed = append(failed, alloc.IDShort)
+			continue
+		}
+
+		restarts := 0
+		for _, event := range alloc.Events {
+			if event.Type == "Restarting" && !event.Timestamp.Before(since) {
+				restarts++
+			}
+		}
+		if restarts >= crashLoopRestarts {
+			looping = append(looping, fmt.Sprintf("%s (%d restarts)", alloc.IDShort, restarts))
+		}
+	}
+	if len(failed) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d instance(s) failed in the last %s: %s", len(failed), window, strings.Join(failed, ", ")))
+	}
+	if len(looping) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d instance(s) restarting repeatedly in the last %s: %s", len(looping), window, strings.Join(looping, ", ")))
+	}
+
+	return reasons
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
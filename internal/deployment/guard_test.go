@@ -0,0 +1,62 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestGuardHealthy(t *testing.T) {
+	now := time.Now()
+	g := Guard{Window: 10 * time.Minute}
+
+	reasons := g.Assess([]*api.AllocationStatus{
+		{IDShort: "a", Status: "running", PassingCheckCount: 2},
+		// failed before the window
+		{IDShort: "b", Status: "failed", Failed: true, UpdatedAt: now.Add(-time.Hour)},
+		// a single restart isn't a crash loop
+		{IDShort: "c", Status: "running", PassingCheckCount: 2, Events: []api.AllocationEvent{
+			{Type: "Restarting", Timestamp: now.Add(-time.Minute)},
+		}},
+	}, now)
+
+	if len(reasons) != 0 {
+		t.Errorf("expected no reasons, got %v", reasons)
+	}
+}
+
+func TestGuardDegraded(t *testing.T) {
+	now := time.Now()
+	g := Guard{Window: 10 * time.Minute, ErrorBudget: 0.25}
+
+	reasons := g.Assess([]*api.AllocationStatus{
+		{IDShort: "a", Status: "running", PassingCheckCount: 1, CriticalCheckCount: 1, Checks: []api.CheckState{
+			{Name: "http", Status: "critical"},
+			{Name: "tcp", Status: "passing"},
+		}},
+		{IDShort: "b", Status: "running", PassingCheckCount: 2, Events: []api.AllocationEvent{
+			{Type: "Restarting", Timestamp: now.Add(-5 * time.Minute)},
+			{Type: "Restarting", Timestamp: now.Add(-2 * time.Minute)},
+			{Type: "Restarting", Timestamp: now.Add(-time.Hour)},
+		}},
+		{IDShort: "c", Status: "failed", Failed: true, UpdatedAt: now.Add(-time.Minute)},
+	}, now)
+
+	// a quarter of the checks failing is within the budget
+	want := []string{
+		"1 instance(s) failed in the last 10m0s: c",
+		"1 instance(s) restarting repeatedly in the last 10m0s: b (2 restarts)",
+	}
+	if len(reasons) != 2 || reasons[0] != want[0] || reasons[1] != want[1] {
+		t.Fatalf("unexpected reasons %q", reasons)
+	}
+
+	g.ErrorBudget = 0.1
+	reasons = g.Assess([]*api.AllocationStatus{
+		{IDShort: "a", Status: "running", PassingCheckCount: 3, CriticalCheckCount: 1, Checks: []api.CheckState{{Name: "http", Status: "critical"}}},
+	}, now)
+	if len(reasons) != 1 || reasons[0] != "1 of 4 health checks are critical (http), over the 10% error budget" {
+		t.Errorf("unexpected reasons %q", reasons)
+	}
+}
@@ -0,0 +1,64 @@
+package deployment
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MigrationLockName is the name of the app lock held while a release
+// command runs
+const MigrationLockName = "migrations"
+
+// LockHolder describes who is deploying, so whoever waits on a lock can
+// tell which deploy holds it: the user, the host and, in CI, the job.
+// getenv looks up environment variables.
+func LockHolder(user string, hostname string, getenv func(string) string) string {
+	parts := []string{}
+	if user != "" {
+		parts = append(parts, user)
+	}
+	if hostname != "" {
+		parts = append(parts, "on "+hostname)
+	}
+	if job := ciJob(getenv); job != "" {
+		parts = append(parts, "("+job+")")
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, " ")
+}
+
+// ciJob - the CI job flyctl is running in, preferably as a link to it
+func ciJob(getenv func(string) string) string {
+	switch {
+	case getenv("GITHUB_ACTIONS") != "":
+		if server, repo, run := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID"); server != "" && repo != "" && run != "" {
+			return fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, run)
+		}
+		return "GitHub Actions"
+	case getenv("GITLAB_CI") != "":
+		if url := getenv("CI_JOB_URL"); url != "" {
+			return url
+		}
+		return "GitLab CI"
+	case getenv("CIRCLECI") != "":
+		if url := getenv("CIRCLE_BUILD_URL"); url != "" {
+			return url
+		}
+		return "CircleCI"
+	case getenv("BUILDKITE") != "":
+		if url := getenv("BUILDKITE_BUILD_URL"); url != "" {
+			return url
+		}
+		return "Buildkite"
+	case getenv("JENKINS_URL") != "":
+		if url := getenv("BUILD_URL"); url != "" {
+			return url
+		}
+		return "Jenkins"
+	case getenv("CI") != "" && getenv("CI") != "false" && getenv("CI") != "0":
+		return "CI"
+	}
+	return ""
+}
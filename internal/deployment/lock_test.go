@@ -0,0 +1,30 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockHolder(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	assert.Equal(t, "jo@example.com on laptop", LockHolder("jo@example.com", "laptop", env(nil)))
+
+	assert.Equal(t, "jo@example.com on runner-1 (https://github.com/acme/web/actions/runs/42)", LockHolder("jo@example.com", "runner-1", env(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_SERVER_URL": "https://github.com",
+		"GITHUB_REPOSITORY": "acme/web",
+		"GITHUB_RUN_ID":     "42",
+	})))
+
+	assert.Equal(t, "on runner (https://gitlab.com/acme/web/-/jobs/7)", LockHolder("", "runner", env(map[string]string{
+		"GITLAB_CI":  "true",
+		"CI_JOB_URL": "https://gitlab.com/acme/web/-/jobs/7",
+	})))
+
+	assert.Equal(t, "(CI)", LockHolder("", "", env(map[string]string{"CI": "1"})))
+	assert.Equal(t, "unknown", LockHolder("", "", env(map[string]string{"CI": "false"})))
+}
@@ -3,10 +3,14 @@ package deployment
 import (
 	"context"
 	"errors"
+	"io"
+	"net"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/jpillora/backoff"
 	"github.com/superfly/flyctl/api"
 )
 
@@ -24,11 +28,18 @@ func NewDeploymentMonitor(client *api.Client, appID string) *DeploymentMonitor {
 type DeploymentMonitor struct {
 	AppID string
 
+	// ReconnectTimeout is how long to keep retrying after the connection to
+	// the API drops before giving up. Defaults to DefaultReconnectTimeout.
+	ReconnectTimeout time.Duration
+
 	client       *api.Client
 	err          error
 	successCount int
 	failureCount int
+	deploymentID string
+	disconnected bool
 
+	Reconnecting        func(attempt int, err error)
 	DeploymentStarted   func(idx int, deployment *api.DeploymentStatus) error
 	DeploymentUpdated   func(deployment *api.DeploymentStatus, updatedAllocs []*api.AllocationStatus) error
 	DeploymentFailed    func(deployment *api.DeploymentStatus, failedAllocs []*api.AllocationStatus) error
@@ -37,6 +48,8 @@ type DeploymentMonitor struct {
 
 var pollInterval = 750 * time.Millisecond
 
+const DefaultReconnectTimeout = 2 * time.Minute
+
 func (dm *DeploymentMonitor) start(ctx context.Context) <-chan *deploymentStatus {
 	statusCh := make(chan *deploymentStatus)
 
@@ -58,6 +71,11 @@ func (dm *DeploymentMonitor) start(ctx context.Context) <-chan *deploymentStatus
 
 		var delay time.Duration
 
+		// connection failures are retried with backoff, keeping currentID so
+		// monitoring resumes where it left off once the API is reachable again
+		reconnect := &backoff.Backoff{Min: pollInterval, Max: 10 * time.Second}
+		var disconnectedAt time.Time
+
 		processFn := func() error {
 			deployment, err := dm.client.GetDeploymentStatus(dm.AppID, currentID)
 			if err != nil {
@@ -91,6 +109,7 @@ func (dm *DeploymentMonitor) start(ctx context.Context) <-chan *deploymentStatus
 				currentDeployment.number = num
 				statusCh <- currentDeployment
 				currentID = deployment.ID
+				dm.deploymentID = currentID
 			}
 
 			currentDeployment.Update(deployment)
@@ -114,7 +133,27 @@ func (dm *DeploymentMonitor) start(ctx context.Context) <-chan *deploymentStatus
 		for {
 			select {
 			case <-time.After(delay):
-				switch err := processFn(); err {
+				err := processFn()
+				if err != nil && isTransientError(err) {
+					if disconnectedAt.IsZero() {
+						disconnectedAt = time.Now()
+					}
+					if time.Since(disconnectedAt) > dm.reconnectTimeout() {
+						dm.disconnected = true
+						dm.err = multierror.Append(err)
+						return
+					}
+					if dm.Reconnecting != nil {
+						dm.Reconnecting(int(reconnect.Attempt())+1, err)
+					}
+					delay = reconnect.Duration()
+					continue
+				}
+
+				disconnectedAt = time.Time{}
+				reconnect.Reset()
+
+				switch err {
 				case nil:
 					// we're still monitoring, ensure the poll interval is > 0 and continue
 					delay = pollInterval
@@ -152,6 +191,48 @@ func (dm *DeploymentMonitor) Error() error {
 	return dm.err
 }
 
+// Disconnected reports whether monitoring stopped because the API couldn't be
+// reached within ReconnectTimeout. The deployment carries on server-side.
+func (dm *DeploymentMonitor) Disconnected() bool {
+	return dm.disconnected
+}
+
+// FinalStatus fetches the current status of the last deployment seen, or the
+// latest deployment if none was seen, for reporting a verdict after the
+// monitor disconnects.
+func (dm *DeploymentMonitor) FinalStatus() (*api.DeploymentStatus, error) {
+	return dm.client.GetDeploymentStatus(dm.AppID, dm.deploymentID)
+}
+
+func (dm *DeploymentMonitor) reconnectTimeout() time.Duration {
+	if dm.ReconnectTimeout > 0 {
+		return dm.ReconnectTimeout
+	}
+	return DefaultReconnectTimeout
+}
+
+// isTransientError reports whether err looks like a dropped connection or a
+// flaky response rather than a problem with the request itself
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	if api.IsServerError(err) {
+		return true
+	}
+
+	msg := err.Error()
+	return strings.HasPrefix(msg, "server returned a non-200 status code") ||
+		strings.HasPrefix(msg, "reading body") ||
+		strings.HasPrefix(msg, "decoding response")
+}
+
 func (dm *DeploymentMonitor) Start(ctx context.Context) {
 	for deployment := range dm.start(ctx) {
 		if dm.DeploymentStarted != nil {
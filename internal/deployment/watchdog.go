@@ -0,0 +1,134 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// DefaultWatchInterval is how often the watchdog polls allocations
+const DefaultWatchInterval = 5 * time.Second
+
+// crashLoopRestarts is how many restarts within the watch window mark an
+// allocation as crash looping
+const crashLoopRestarts = 2
+
+// NewWatchdog creates a watchdog for the allocations of release version of appID
+func NewWatchdog(client *api.Client, appID string, version int) *Watchdog {
+	return &Watchdog{
+		AppID:    appID,
+		Version:  version,
+		Interval: DefaultWatchInterval,
+		client:   client,
+		allocs:   map[string]*watchedAlloc{},
+	}
+}
+
+// Watchdog keeps an eye on a release after it has been marked healthy, to
+// catch allocations that start crash looping or whose health checks flap.
+type Watchdog struct {
+	AppID    string
+	Version  int
+	Interval time.Duration
+
+	client *api.Client
+	allocs map[string]*watchedAlloc
+}
+
+type watchedAlloc struct {
+	alloc           *api.AllocationStatus
+	firstRestarts   int
+	healthy         bool
+	healthChanges   int
+	becameUnhealthy bool
+}
+
+// AllocationProblem describes an allocation that misbehaved after release
+type AllocationProblem struct {
+	Alloc  *api.AllocationStatus
+	Reason string
+}
+
+// Watch polls the release's allocations for window and returns the ones that
+// crash looped, failed or had flapping health checks. It returns early, with
+// what it saw so far, if ctx is cancelled.
+func (w *Watchdog) Watch(ctx context.Context, window time.Duration) ([]AllocationProblem, error) {
+	deadline := time.Now().Add(window)
+
+	for {
+		status, err := w.client.GetAppStatus(w.AppID, false)
+		if err != nil {
+			return nil, err
+		}
+		w.observe(status.Allocations)
+
+		if time.Now().Add(w.Interval).After(deadline) {
+			return w.Problems(), nil
+		}
+
+		select {
+		case <-time.After(w.Interval):
+		case <-ctx.Done():
+			return w.Problems(), nil
+		}
+	}
+}
+
+func (w *Watchdog) observe(allocs []*api.AllocationStatus) {
+	for _, alloc := range allocs {
+		if alloc.Version != w.Version {
+			continue
+		}
+
+		seen, ok := w.allocs[alloc.ID]
+		if !ok {
+			w.allocs[alloc.ID] = &watchedAlloc{
+				alloc:         alloc,
+				firstRestarts: alloc.Restarts,
+				healthy:       alloc.Healthy,
+			}
+			continue
+		}
+
+		if alloc.Healthy != seen.healthy {
+			seen.healthChanges++
+			if !alloc.Healthy {
+				seen.becameUnhealthy = true
+			}
+		}
+		seen.healthy = alloc.Healthy
+		seen.alloc = alloc
+	}
+}
+
+// Problems returns the allocations that have misbehaved so far
+func (w *Watchdog) Problems() []AllocationProblem {
+	problems := []AllocationProblem{}
+
+	for _, seen := range w.allocs {
+		restarts := seen.alloc.Restarts - seen.firstRestarts
+
+		var reason string
+		switch {
+		case seen.alloc.Failed || seen.alloc.Status == "failed":
+			reason = "failed"
+		case restarts >= crashLoopRestarts:
+			reason = fmt.Sprintf("crash looping, restarted %d times", restarts)
+		case seen.healthChanges > 1 && seen.becameUnhealthy:
+			reason = fmt.Sprintf("health checks flapping, changed %d times", seen.healthChanges)
+		case seen.becameUnhealthy && !seen.healthy:
+			reason = "health checks failing"
+		default:
+			continue
+		}
+
+		problems = append(problems, AllocationProblem{Alloc: seen.alloc, Reason: reason})
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Alloc.ID < problems[j].Alloc.ID })
+
+	return problems
+}
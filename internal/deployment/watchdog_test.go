@@ -0,0 +1,41 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestWatchdogProblems(t *testing.T) {
+	w := NewWatchdog(nil, "app", 3)
+
+	w.observe([]*api.AllocationStatus{
+		{ID: "stable", Version: 3, Healthy: true},
+		{ID: "looping", Version: 3, Healthy: true, Restarts: 1},
+		{ID: "flapping", Version: 3, Healthy: true},
+		{ID: "old", Version: 2, Healthy: true},
+	})
+	w.observe([]*api.AllocationStatus{
+		{ID: "stable", Version: 3, Healthy: true},
+		{ID: "looping", Version: 3, Healthy: false, Restarts: 2},
+		{ID: "flapping", Version: 3, Healthy: false},
+		{ID: "old", Version: 2, Failed: true},
+	})
+	w.observe([]*api.AllocationStatus{
+		{ID: "stable", Version: 3, Healthy: true},
+		{ID: "looping", Version: 3, Healthy: true, Restarts: 3},
+		{ID: "flapping", Version: 3, Healthy: true},
+	})
+
+	problems := w.Problems()
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d: %+v", len(problems), problems)
+	}
+
+	if problems[0].Alloc.ID != "flapping" || problems[0].Reason != "health checks flapping, changed 2 times" {
+		t.Errorf("unexpected problem %q: %s", problems[0].Alloc.ID, problems[0].Reason)
+	}
+	if problems[1].Alloc.ID != "looping" || problems[1].Reason != "crash looping, restarted 2 times" {
+		t.Errorf("unexpected problem %q: %s", problems[1].Alloc.ID, problems[1].Reason)
+	}
+}
@@ -0,0 +1,84 @@
+// Package deprecation keeps track of commands and flags that are on their way
+// out, so they're announced the same way wherever they're used.
+package deprecation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notice describes a deprecated command, or a flag of a command
+type Notice struct {
+	// Command is the command's path without the binary name, e.g. "autoscale set"
+	Command string `json:"command"`
+	// Flag is the deprecated flag's name, empty when the whole command is deprecated
+	Flag string `json:"flag,omitempty"`
+	// Replacement is what to use instead, e.g. "fly scale"
+	Replacement string `json:"replacement,omitempty"`
+	// RemovedIn is the version the command or flag will be removed in
+	RemovedIn string `json:"removed_in,omitempty"`
+}
+
+// Subject names the deprecated command or flag
+func (n Notice) Subject() string {
+	if n.Flag != "" {
+		return fmt.Sprintf("the --%s flag of `fly %s`", n.Flag, n.Command)
+	}
+	return fmt.Sprintf("`fly %s`", n.Command)
+}
+
+// Message is the standard warning for the notice
+func (n Notice) Message() string {
+	var b strings.Builder
+	b.WriteString(n.Subject())
+	b.WriteString(" is deprecated")
+	if n.RemovedIn != "" {
+		fmt.Fprintf(&b, " and will be removed in %s", n.RemovedIn)
+	}
+	if n.Replacement != "" {
+		fmt.Fprintf(&b, ", use `%s` instead", n.Replacement)
+	}
+	return b.String()
+}
+
+// Registry holds the known deprecations
+type Registry struct {
+	notices []Notice
+}
+
+// Register adds notices to the registry
+func (r *Registry) Register(notices ...Notice) {
+	r.notices = append(r.notices, notices...)
+}
+
+// All returns every registered notice
+func (r *Registry) All() []Notice {
+	return r.notices
+}
+
+// ForCommand returns the notices that apply when command runs with the flags
+// in setFlags. Deprecating a command deprecates its subcommands too.
+func (r *Registry) ForCommand(command string, setFlags []string) []Notice {
+	var out []Notice
+
+	for _, n := range r.notices {
+		if n.Flag == "" {
+			if command == n.Command || strings.HasPrefix(command, n.Command+" ") {
+				out = append(out, n)
+			}
+			continue
+		}
+
+		if command != n.Command {
+			continue
+		}
+		for _, flag := range setFlags {
+			if flag == n.Flag {
+				out = append(out, n)
+				break
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,36 @@
+package deprecation
+
+import "testing"
+
+func TestForCommand(t *testing.T) {
+	var r Registry
+	r.Register(
+		Notice{Command: "autoscale", Replacement: "fly scale", RemovedIn: "v0.1.0"},
+		Notice{Command: "deploy", Flag: "old", Replacement: "--new"},
+	)
+
+	if got := r.ForCommand("autoscale set", nil); len(got) != 1 {
+		t.Errorf("expected subcommand to inherit its parent's deprecation, got %+v", got)
+	}
+	if got := r.ForCommand("autoscaler", nil); len(got) != 0 {
+		t.Errorf("expected no notices for a command sharing a prefix, got %+v", got)
+	}
+	if got := r.ForCommand("deploy", []string{"image"}); len(got) != 0 {
+		t.Errorf("expected no notices without the deprecated flag, got %+v", got)
+	}
+	if got := r.ForCommand("deploy", []string{"image", "old"}); len(got) != 1 || got[0].Flag != "old" {
+		t.Errorf("expected the flag notice, got %+v", got)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	n := Notice{Command: "autoscale", Replacement: "fly scale", RemovedIn: "v0.1.0"}
+	if got, want := n.Message(), "`fly autoscale` is deprecated and will be removed in v0.1.0, use `fly scale` instead"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	n = Notice{Command: "deploy", Flag: "old"}
+	if got, want := n.Message(), "the --old flag of `fly deploy` is deprecated"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,286 @@
+// Package dnscheck verifies that a domain is delegated to the nameservers
+// that host its zone, both at the parent zone and from public resolvers.
+package dnscheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/sync/errgroup"
+)
+
+// queryTimeout bounds each DNS query
+const queryTimeout = 5 * time.Second
+
+// Resolver is a public recursive resolver used to check propagation
+type Resolver struct {
+	Name    string
+	Address string
+}
+
+// PublicResolvers are checked by Check unless others are given
+var PublicResolvers = []Resolver{
+	{Name: "Google", Address: "8.8.8.8"},
+	{Name: "Cloudflare", Address: "1.1.1.1"},
+	{Name: "Quad9", Address: "9.9.9.9"},
+	{Name: "OpenDNS", Address: "208.67.222.222"},
+}
+
+// Result is what one source reports as the domain's nameservers
+type Result struct {
+	// Source describes where the answer came from, e.g. "parent (a.gtld-servers.net)"
+	Source      string   `json:"source"`
+	Nameservers []string `json:"nameservers"`
+	// Missing are expected nameservers the source didn't report
+	Missing []string `json:"missing,omitempty"`
+	// Unexpected are nameservers the source reported that weren't expected
+	Unexpected []string `json:"unexpected,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// OK reports whether the source answered with exactly the expected nameservers
+func (r Result) OK() bool {
+	return r.Error == "" && len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// Report is the outcome of checking a domain's delegation
+type Report struct {
+	Domain   string   `json:"domain"`
+	Expected []string `json:"expected"`
+	// Parent is the delegation published by the parent zone, i.e. what the
+	// registrar has set
+	Parent Result `json:"parent"`
+	// Resolvers are the answers of public resolvers, which lag the parent
+	// while the change propagates
+	Resolvers []Result `json:"resolvers"`
+}
+
+// OK reports whether every source agrees with the expected nameservers
+func (r *Report) OK() bool {
+	if !r.Parent.OK() {
+		return false
+	}
+	for _, res := range r.Resolvers {
+		if !res.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Check compares domain's delegation at its parent zone and at resolvers
+// with the expected nameservers
+func Check(ctx context.Context, domain string, expected []string, resolvers []Resolver) *Report {
+	domain = normalize(domain)
+	expected = normalizeAll(expected)
+
+	report := &Report{
+		Domain:    domain,
+		Expected:  expected,
+		Resolvers: make([]Result, len(resolvers)),
+	}
+
+	var g errgroup.Group
+
+	g.Go(func() error {
+		server, nameservers, err := parentDelegation(ctx, domain)
+		report.Parent = newResult("parent", server, expected, nameservers, err)
+		return nil
+	})
+
+	for i, resolver := range resolvers {
+		i, resolver := i, resolver
+		g.Go(func() error {
+			nameservers, err := queryNS(ctx, resolver.Address, domain, true)
+			report.Resolvers[i] = newResult(resolver.Name, resolver.Address, expected, nameservers, err)
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return report
+}
+
+func newResult(name, server string, expected, got []string, err error) Result {
+	result := Result{Source: name}
+	if server != "" {
+		result.Source = fmt.Sprintf("%s (%s)", name, server)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Nameservers = normalizeAll(got)
+	result.Missing, result.Unexpected = Compare(expected, result.Nameservers)
+	return result
+}
+
+// Compare returns the expected nameservers missing from got, and the ones in
+// got that weren't expected
+func Compare(expected, got []string) (missing, unexpected []string) {
+	want := map[string]bool{}
+	for _, ns := range normalizeAll(expected) {
+		want[ns] = true
+	}
+	have := map[string]bool{}
+	for _, ns := range normalizeAll(got) {
+		have[ns] = true
+		if !want[ns] {
+			unexpected = append(unexpected, ns)
+		}
+	}
+	for ns := range want {
+		if !have[ns] {
+			missing = append(missing, ns)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
+
+// ParentZone returns the zone a domain is delegated from, e.g. "com" for
+// "example.com"
+func ParentZone(domain string) string {
+	domain = normalize(domain)
+	if i := strings.Index(domain, "."); i >= 0 {
+		return domain[i+1:]
+	}
+	return "."
+}
+
+// parentDelegation asks one of the parent zone's nameservers which
+// nameservers domain is delegated to, returning the server that answered
+func parentDelegation(ctx context.Context, domain string) (string, []string, error) {
+	parent := ParentZone(domain)
+
+	parentNS, err := net.DefaultResolver.LookupNS(ctx, parent)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not find the nameservers of %s: %w", parent, err)
+	}
+	if len(parentNS) == 0 {
+		return "", nil, fmt.Errorf("%s has no nameservers", parent)
+	}
+
+	var lastErr error
+	for _, i := range rand.Perm(len(parentNS)) {
+		server := normalize(parentNS[i].Host)
+
+		addrs, err := net.DefaultResolver.LookupHost(ctx, server)
+		if err != nil || len(addrs) == 0 {
+			lastErr = fmt.Errorf("could not resolve %s: %w", server, err)
+			continue
+		}
+
+		nameservers, err := queryNS(ctx, addrs[0], domain, false)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return server, nameservers, nil
+	}
+
+	return "", nil, lastErr
+}
+
+// queryNS asks server for the NS records of domain. Parent zones answer
+// non-recursive queries with a referral, so the authority section counts too.
+func queryNS(ctx context.Context, server, domain string, recursive bool) ([]string, error) {
+	name, err := dnsmessage.NewName(domain + ".")
+	if err != nil {
+		return nil, err
+	}
+
+	id := uint16(rand.Intn(1 << 16))
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: id, RecursionDesired: recursive},
+		Questions: []dnsmessage.Question{
+			{Name: name, Type: dnsmessage.TypeNS, Class: dnsmessage.ClassINET},
+		},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(server, "53"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(packed); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp dnsmessage.Message
+		if err := resp.Unpack(buf[:n]); err != nil || resp.ID != id {
+			continue
+		}
+
+		return nameserversIn(&resp, domain)
+	}
+}
+
+func nameserversIn(resp *dnsmessage.Message, domain string) ([]string, error) {
+	switch resp.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, fmt.Errorf("%s doesn't exist", domain)
+	default:
+		return nil, fmt.Errorf("lookup failed: %s", resp.RCode)
+	}
+
+	var out []string
+	for _, section := range [][]dnsmessage.Resource{resp.Answers, resp.Authorities} {
+		for _, rr := range section {
+			ns, ok := rr.Body.(*dnsmessage.NSResource)
+			if !ok || normalize(rr.Header.Name.String()) != domain {
+				continue
+			}
+			out = append(out, normalize(ns.NS.String()))
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+	}
+
+	return nil, errors.New("no NS records")
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func normalizeAll(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		out = append(out, normalize(n))
+	}
+	sort.Strings(out)
+	return out
+}
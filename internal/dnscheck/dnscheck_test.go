@@ -0,0 +1,49 @@
+package dnscheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestCompare(t *testing.T) {
+	missing, unexpected := Compare(
+		[]string{"ns1.fly-dns.net", "NS2.fly-dns.net."},
+		[]string{"ns2.fly-dns.net.", "dana.ns.cloudflare.com"},
+	)
+
+	assert.Equal(t, []string{"ns1.fly-dns.net"}, missing)
+	assert.Equal(t, []string{"dana.ns.cloudflare.com"}, unexpected)
+}
+
+func TestParentZone(t *testing.T) {
+	assert.Equal(t, "com", ParentZone("example.com."))
+	assert.Equal(t, "example.co.uk", ParentZone("shop.example.co.uk"))
+	assert.Equal(t, ".", ParentZone("com"))
+}
+
+func TestNameserversInReferral(t *testing.T) {
+	resp := &dnsmessage.Message{
+		Authorities: []dnsmessage.Resource{
+			nsResource("example.com.", "ns1.fly-dns.net."),
+			nsResource("example.com.", "NS2.fly-dns.net."),
+			nsResource("other.com.", "ns.elsewhere.net."),
+		},
+	}
+
+	nameservers, err := nameserversIn(resp, "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns1.fly-dns.net", "ns2.fly-dns.net"}, nameservers)
+
+	resp.RCode = dnsmessage.RCodeNameError
+	_, err = nameserversIn(resp, "example.com")
+	assert.EqualError(t, err, "example.com doesn't exist")
+}
+
+func nsResource(name, ns string) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: dnsmessage.TypeNS, Class: dnsmessage.ClassINET},
+		Body:   &dnsmessage.NSResource{NS: dnsmessage.MustNewName(ns)},
+	}
+}
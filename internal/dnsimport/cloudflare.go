@@ -0,0 +1,166 @@
+package dnsimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const cloudflareBaseURL = "https://api.cloudflare.com/client/v4"
+
+// Cloudflare reads zones with a Cloudflare API token that can read DNS
+type Cloudflare struct {
+	Token string
+	// BaseURL overrides the Cloudflare API's URL
+	BaseURL string
+	Client  *http.Client
+}
+
+func (cf *Cloudflare) Name() string {
+	return "Cloudflare"
+}
+
+type cloudflareResponse struct {
+	Success bool
+	Errors  []struct {
+		Message string
+	}
+	Result     json.RawMessage
+	ResultInfo struct {
+		Page       int `json:"page"`
+		TotalPages int `json:"total_pages"`
+	} `json:"result_info"`
+}
+
+type cloudflareRecord struct {
+	Name     string
+	Type     string
+	Content  string
+	TTL      int
+	Priority *int
+	Proxied  bool
+}
+
+func (cf *Cloudflare) Records(ctx context.Context, zone string) ([]Record, []string, error) {
+	var zones []struct{ ID string }
+	if _, err := cf.get(ctx, "/zones?name="+url.QueryEscape(zone), &zones); err != nil {
+		return nil, nil, err
+	}
+	if len(zones) == 0 {
+		return nil, nil, fmt.Errorf("zone %s not found in Cloudflare", zone)
+	}
+
+	var records []Record
+	var warnings []string
+
+	for page := 1; ; page++ {
+		var batch []cloudflareRecord
+		info, err := cf.get(ctx, fmt.Sprintf("/zones/%s/dns_records?per_page=100&page=%d", zones[0].ID, page), &batch)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, cr := range batch {
+			r, err := cr.record()
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			if skipped(zone, r) {
+				continue
+			}
+			if cr.Proxied {
+				warnings = append(warnings, fmt.Sprintf("%s %s is proxied by Cloudflare, it will point straight at %s once moved", r.Type, r.Name, cr.Content))
+			}
+			records = append(records, r)
+		}
+
+		if info.ResultInfo.Page >= info.ResultInfo.TotalPages {
+			break
+		}
+	}
+
+	return records, warnings, nil
+}
+
+// record converts a Cloudflare record to zone file form
+func (cr cloudflareRecord) record() (Record, error) {
+	ttl := cr.TTL
+	if ttl <= 1 {
+		// 1 is Cloudflare's automatic TTL
+		ttl = 300
+	}
+
+	r := Record{Name: cr.Name, Type: cr.Type, TTL: ttl}
+
+	switch cr.Type {
+	case "A", "AAAA":
+		r.Data = cr.Content
+	case "CNAME", "NS", "PTR":
+		r.Data = fqdn(cr.Content)
+	case "TXT":
+		r.Data = quoteTXT(cr.Content)
+	case "CAA":
+		r.Data = cr.Content
+	case "MX":
+		if cr.Priority == nil {
+			return r, fmt.Errorf("MX %s has no priority, skipping it", cr.Name)
+		}
+		r.Data = strconv.Itoa(*cr.Priority) + " " + fqdn(cr.Content)
+	case "SRV":
+		// content is "weight port target", priority is separate
+		fields := strings.Fields(cr.Content)
+		if cr.Priority == nil || len(fields) != 3 {
+			return r, fmt.Errorf("SRV %s is malformed, skipping it", cr.Name)
+		}
+		r.Data = fmt.Sprintf("%d %s %s %s", *cr.Priority, fields[0], fields[1], fqdn(fields[2]))
+	default:
+		return r, fmt.Errorf("%s records aren't supported, skipping %s", cr.Type, cr.Name)
+	}
+
+	return r, nil
+}
+
+func (cf *Cloudflare) get(ctx context.Context, path string, result interface{}) (*cloudflareResponse, error) {
+	base := cf.BaseURL
+	if base == "" {
+		base = cloudflareBaseURL
+	}
+	client := cf.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+cf.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body cloudflareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("unexpected response from Cloudflare: %s", resp.Status)
+	}
+	if !body.Success {
+		if len(body.Errors) > 0 {
+			return nil, fmt.Errorf("cloudflare: %s", body.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("cloudflare: %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(body.Result, result); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
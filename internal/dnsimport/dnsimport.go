@@ -0,0 +1,110 @@
+// Package dnsimport reads the records of a DNS zone hosted by another
+// provider, so the zone can be moved to Fly.
+package dnsimport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Record is a DNS record in zone file form
+type Record struct {
+	// Name is the record's fully qualified name, without the trailing dot
+	Name string
+	Type string
+	TTL  int
+	// Data is the record's data as written in a zone file, e.g.
+	// "10 mail.example.com." for an MX record
+	Data string
+}
+
+// Provider reads zones from a DNS provider
+type Provider interface {
+	Name() string
+	// Records returns the zone's records, and warnings about the ones that
+	// can't be copied
+	Records(ctx context.Context, zone string) ([]Record, []string, error)
+}
+
+// Credentials authenticate with a provider. Cloudflare uses Token, Route 53
+// the AWS access key fields.
+type Credentials struct {
+	Token           string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Providers lists the providers zones can be imported from
+var Providers = []string{"cloudflare", "route53"}
+
+// NewProvider returns the named provider
+func NewProvider(name string, creds Credentials) (Provider, error) {
+	switch strings.ToLower(name) {
+	case "cloudflare":
+		if creds.Token == "" {
+			return nil, fmt.Errorf("a Cloudflare API token is required")
+		}
+		return &Cloudflare{Token: creds.Token}, nil
+	case "route53":
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("an AWS access key ID and secret access key are required")
+		}
+		return &Route53{AccessKeyID: creds.AccessKeyID, SecretAccessKey: creds.SecretAccessKey, SessionToken: creds.SessionToken}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q, use one of %s", name, strings.Join(Providers, ", "))
+}
+
+// skipped reports whether a record belongs to the zone's current host rather
+// than its content. Fly serves its own SOA and apex NS records.
+func skipped(zone string, r Record) bool {
+	return r.Type == "SOA" || (r.Type == "NS" && strings.EqualFold(r.Name, zone))
+}
+
+// ZoneFile writes records as a zone file for zone, sorted by name and type
+func ZoneFile(zone string, records []Record) string {
+	sorted := append([]Record(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", zone)
+	for _, r := range sorted {
+		fmt.Fprintf(&b, "%s.\t%d\tIN\t%s\t%s\n", r.Name, r.TTL, r.Type, r.Data)
+	}
+	return b.String()
+}
+
+// fqdn adds the trailing dot to a hostname in record data
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// quoteTXT quotes TXT record content, split into the 255 byte strings a TXT
+// record is made of
+func quoteTXT(content string) string {
+	if strings.HasPrefix(content, `"`) {
+		return content
+	}
+
+	var parts []string
+	for len(content) > 255 {
+		parts = append(parts, content[:255])
+		content = content[255:]
+	}
+	parts = append(parts, content)
+
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(strings.ReplaceAll(p, `\`, `\\`), `"`, `\"`) + `"`
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,75 @@
+package dnsimport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCloudflareRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"success":false,"errors":[{"message":"Invalid API Token"}]}`)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/zones":
+			fmt.Fprint(w, `{"success":true,"result":[{"id":"z1"}]}`)
+		case r.URL.Path == "/zones/z1/dns_records" && r.URL.Query().Get("page") == "1":
+			fmt.Fprint(w, `{"success":true,"result_info":{"page":1,"total_pages":2},"result":[
+				{"name":"example.com","type":"A","content":"1.2.3.4","ttl":1,"proxied":true},
+				{"name":"example.com","type":"NS","content":"ns1.cloudflare.com","ttl":3600},
+				{"name":"example.com","type":"MX","content":"mail.example.com","ttl":3600,"priority":10}
+			]}`)
+		case r.URL.Path == "/zones/z1/dns_records":
+			fmt.Fprint(w, `{"success":true,"result_info":{"page":2,"total_pages":2},"result":[
+				{"name":"www.example.com","type":"CNAME","content":"example.com","ttl":300},
+				{"name":"example.com","type":"TXT","content":"v=spf1 -all","ttl":300},
+				{"name":"example.com","type":"LOC","content":"51 30 12.748 N","ttl":300}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cf := &Cloudflare{Token: "token", BaseURL: server.URL}
+	records, warnings, err := cf.Records(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `$ORIGIN example.com.
+example.com.	300	IN	A	1.2.3.4
+example.com.	3600	IN	MX	10 mail.example.com.
+example.com.	300	IN	TXT	"v=spf1 -all"
+www.example.com.	300	IN	CNAME	example.com.
+`
+	if got := ZoneFile("example.com", records); got != want {
+		t.Errorf("got zone file:\n%s\nwant:\n%s", got, want)
+	}
+
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "proxied") || !strings.Contains(warnings[1], "LOC") {
+		t.Errorf("unexpected warnings %q", warnings)
+	}
+
+	cf.Token = "wrong"
+	if _, _, err := cf.Records(context.Background(), "example.com"); err == nil || err.Error() != "cloudflare: Invalid API Token" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	if got, want := quoteTXT(long), `"`+strings.Repeat("a", 255)+`" "`+strings.Repeat("a", 45)+`"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := quoteTXT(`say "hi"`), `"say \"hi\""`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
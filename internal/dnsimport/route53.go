@@ -0,0 +1,143 @@
+package dnsimport
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/internal/sigv4"
+)
+
+const route53BaseURL = "https://route53.amazonaws.com"
+
+// Route53 reads zones with AWS credentials allowed to list hosted zones and
+// their record sets
+type Route53 struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// BaseURL overrides the Route 53 API's URL
+	BaseURL string
+	Client  *http.Client
+}
+
+func (r53 *Route53) Name() string {
+	return "Route 53"
+}
+
+type route53Zones struct {
+	HostedZones []struct {
+		ID   string `xml:"Id"`
+		Name string
+	} `xml:"HostedZones>HostedZone"`
+}
+
+type route53RecordSets struct {
+	RecordSets []struct {
+		Name        string
+		Type        string
+		TTL         int
+		Values      []string  `xml:"ResourceRecords>ResourceRecord>Value"`
+		AliasTarget *struct{} `xml:"AliasTarget"`
+		// SetIdentifier is set on record sets with a routing policy
+		SetIdentifier string
+	} `xml:"ResourceRecordSets>ResourceRecordSet"`
+	IsTruncated    bool
+	NextRecordName string
+	NextRecordType string
+}
+
+func (r53 *Route53) Records(ctx context.Context, zone string) ([]Record, []string, error) {
+	var zones route53Zones
+	if err := r53.get(ctx, "/2013-04-01/hostedzonesbyname", url.Values{"dnsname": {zone}, "maxitems": {"1"}}, &zones); err != nil {
+		return nil, nil, err
+	}
+	if len(zones.HostedZones) == 0 || !strings.EqualFold(strings.TrimSuffix(zones.HostedZones[0].Name, "."), zone) {
+		return nil, nil, fmt.Errorf("zone %s not found in Route 53", zone)
+	}
+	zoneID := zones.HostedZones[0].ID
+
+	var records []Record
+	var warnings []string
+
+	params := url.Values{"maxitems": {"300"}}
+	for {
+		var sets route53RecordSets
+		if err := r53.get(ctx, "/2013-04-01"+zoneID+"/rrset", params, &sets); err != nil {
+			return nil, nil, err
+		}
+
+		for _, set := range sets.RecordSets {
+			name := strings.ReplaceAll(strings.TrimSuffix(set.Name, "."), `\052`, "*")
+
+			switch {
+			case set.AliasTarget != nil:
+				warnings = append(warnings, fmt.Sprintf("%s %s is a Route 53 alias, which can't be copied, skipping it", set.Type, name))
+				continue
+			case set.SetIdentifier != "":
+				warnings = append(warnings, fmt.Sprintf("%s %s uses Route 53 routing policies, copying all its values as one record set", set.Type, name))
+			}
+
+			for _, value := range set.Values {
+				r := Record{Name: name, Type: set.Type, TTL: set.TTL, Data: value}
+				if skipped(zone, r) {
+					continue
+				}
+				records = append(records, r)
+			}
+		}
+
+		if !sets.IsTruncated {
+			break
+		}
+		params = url.Values{"maxitems": {"300"}, "name": {sets.NextRecordName}, "type": {sets.NextRecordType}}
+	}
+
+	return records, warnings, nil
+}
+
+func (r53 *Route53) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	base := r53.BaseURL
+	if base == "" {
+		base = route53BaseURL
+	}
+	client := r53.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path+"?"+sigv4.CanonicalQuery(params), nil)
+	if err != nil {
+		return err
+	}
+	creds := sigv4.Credentials{AccessKeyID: r53.AccessKeyID, SecretAccessKey: r53.SecretAccessKey, SessionToken: r53.SessionToken}
+	sigv4.Sign(req, creds, "us-east-1", "route53", sigv4.HashPayload(nil), time.Now())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `xml:"Error>Message"`
+		}
+		if xml.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("route 53: %s", apiErr.Message)
+		}
+		return fmt.Errorf("route 53: %s", resp.Status)
+	}
+
+	return xml.Unmarshal(body, result)
+}
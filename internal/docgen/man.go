@@ -0,0 +1,178 @@
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ManHeader is what goes in the .TH line at the top of every man page
+type ManHeader struct {
+	// Section is the manual section, 1 when empty
+	Section string
+	// Date defaults to now
+	Date *time.Time
+	// Source is where the command comes from, e.g. flyctl v0.0.200
+	Source string
+	// Manual is the name of the manual, e.g. Flyctl Manual
+	Manual string
+}
+
+func (h *ManHeader) section() string {
+	if h == nil || h.Section == "" {
+		return "1"
+	}
+	return h.Section
+}
+
+// GenManTree writes a man page for cmd and all its descendants to dir, named
+// by their command paths, e.g. flyctl-apps-list.1. The header may be nil.
+func GenManTree(cmd *cobra.Command, header *ManHeader, dir string) error {
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		if err := GenManTree(c, header, dir); err != nil {
+			return err
+		}
+	}
+
+	basename := manName(cmd) + "." + header.section()
+	f, err := os.Create(filepath.Join(dir, basename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return GenMan(cmd, header, f)
+}
+
+// GenMan writes the man page of a single command in roff
+func GenMan(cmd *cobra.Command, header *ManHeader, w io.Writer) error {
+	cmd.InitDefaultHelpCmd()
+	cmd.InitDefaultHelpFlag()
+
+	if header == nil {
+		header = &ManHeader{}
+	}
+	date := time.Now()
+	if header.Date != nil {
+		date = *header.Date
+	}
+
+	buf := new(bytes.Buffer)
+	name := manName(cmd)
+
+	fmt.Fprintf(buf, ".TH %q %q %q %q %q\n", strings.ToUpper(name), header.section(), date.Format("Jan 2006"), header.Source, header.Manual)
+	buf.WriteString(".nh\n.ad l\n\n")
+
+	buf.WriteString(".SH NAME\n")
+	fmt.Fprintf(buf, "%s \\- %s\n\n", manEscape(name), manEscape(cmd.Short))
+
+	buf.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(buf, "\\fB%s\\fP\n\n", manEscape(cmd.UseLine()))
+
+	description := cmd.Long
+	if description == "" {
+		description = cmd.Short
+	}
+	buf.WriteString(".SH DESCRIPTION\n")
+	writeManParagraphs(buf, description)
+
+	if len(cmd.Aliases) > 0 {
+		buf.WriteString(".SH ALIASES\n")
+		fmt.Fprintf(buf, "%s\n\n", manEscape(strings.Join(cmd.Aliases, ", ")))
+	}
+
+	writeManFlags(buf, "OPTIONS", cmd.NonInheritedFlags())
+	writeManFlags(buf, "OPTIONS INHERITED FROM PARENT COMMANDS", cmd.InheritedFlags())
+
+	if cmd.HasExample() {
+		buf.WriteString(".SH EXAMPLE\n.PP\n.RS\n.nf\n")
+		fmt.Fprintf(buf, "%s\n", manEscape(cmd.Example))
+		buf.WriteString(".fi\n.RE\n\n")
+	}
+
+	if hasSeeAlso(cmd) {
+		seeAlso := []string{}
+		if cmd.HasParent() {
+			seeAlso = append(seeAlso, manReference(cmd.Parent(), header))
+		}
+		children := cmd.Commands()
+		sort.Sort(byName(children))
+		for _, c := range children {
+			if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			seeAlso = append(seeAlso, manReference(c, header))
+		}
+		buf.WriteString(".SH SEE ALSO\n")
+		fmt.Fprintf(buf, "%s\n", strings.Join(seeAlso, ", "))
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// manName - the page name of a command, its path joined with dashes
+func manName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+}
+
+func manReference(cmd *cobra.Command, header *ManHeader) string {
+	return fmt.Sprintf("\\fB%s(%s)\\fP", manEscape(manName(cmd)), header.section())
+}
+
+func writeManParagraphs(buf *bytes.Buffer, text string) {
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		fmt.Fprintf(buf, ".PP\n%s\n", manEscape(strings.TrimSpace(paragraph)))
+	}
+	buf.WriteString("\n")
+}
+
+func writeManFlags(buf *bytes.Buffer, title string, flags *pflag.FlagSet) {
+	if !flags.HasAvailableFlags() {
+		return
+	}
+
+	fmt.Fprintf(buf, ".SH %s\n", title)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" {
+			return
+		}
+
+		buf.WriteString(".TP\n")
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			fmt.Fprintf(buf, "\\fB\\-%s\\fP, ", f.Shorthand)
+		}
+		fmt.Fprintf(buf, "\\fB\\-\\-%s\\fP", manEscape(f.Name))
+		if f.Value.Type() != "bool" {
+			fmt.Fprintf(buf, "=%s", manEscape(fmt.Sprintf("%q", f.DefValue)))
+		}
+		fmt.Fprintf(buf, "\n%s\n", manEscape(f.Usage))
+	})
+	buf.WriteString("\n")
+}
+
+// manEscape - text as roff shows it literally: backslashes and dashes are
+// escaped, and lines that would read as requests are guarded
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
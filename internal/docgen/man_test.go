@@ -0,0 +1,68 @@
+package docgen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTree() *cobra.Command {
+	root := &cobra.Command{Use: "flyctl", Short: "The Fly CLI"}
+	root.PersistentFlags().StringP("access-token", "t", "", "Fly API Access Token")
+
+	apps := &cobra.Command{Use: "apps", Short: "Manage apps"}
+	list := &cobra.Command{
+		Use:     "list",
+		Short:   "List apps",
+		Long:    "The list of apps.\n\n.dotted lines stay text",
+		Example: `flyctl apps list --org personal`,
+		Run:     func(*cobra.Command, []string) {},
+	}
+	list.Flags().String("org", "", "The org to list")
+	list.Flags().Bool("all", false, "Include suspended apps")
+	list.Flags().Bool("secret", false, "Not documented")
+	list.Flags().MarkHidden("secret")
+
+	apps.AddCommand(list)
+	root.AddCommand(apps)
+	return root
+}
+
+func TestGenMan(t *testing.T) {
+	root := testTree()
+	list, _, _ := root.Find([]string{"apps", "list"})
+
+	date := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	buf := new(bytes.Buffer)
+	assert.NoError(t, GenMan(list, &ManHeader{Date: &date, Source: "flyctl", Manual: "Flyctl Manual"}, buf))
+	page := buf.String()
+
+	assert.Contains(t, page, `.TH "FLYCTL-APPS-LIST" "1" "Mar 2021" "flyctl" "Flyctl Manual"`)
+	assert.Contains(t, page, "flyctl\\-apps\\-list \\- List apps")
+	assert.Contains(t, page, "\\fB\\-\\-org\\fP=\"\"\nThe org to list")
+	assert.Contains(t, page, "\\fB\\-\\-all\\fP\nInclude suspended apps")
+	assert.NotContains(t, page, "secret")
+	assert.Contains(t, page, "\\fB\\-t\\fP, \\fB\\-\\-access\\-token\\fP")
+	assert.Contains(t, page, "\\&.dotted lines stay text")
+	assert.Contains(t, page, "\\fBflyctl\\-apps(1)\\fP")
+}
+
+func TestGenManTree(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, GenManTree(testTree(), nil, dir))
+
+	for _, name := range []string{"flyctl.1", "flyctl-apps.1", "flyctl-apps-list.1"} {
+		_, err := ioutil.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestManEscape(t *testing.T) {
+	assert.Equal(t, `C:\eapps \-\-org`, manEscape(`C:\apps --org`))
+	assert.Equal(t, "text\n\\&'quoted", manEscape("text\n'quoted"))
+}
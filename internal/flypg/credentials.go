@@ -0,0 +1,61 @@
+package flypg
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoCredentials is returned by LoadCredentials when none were saved for
+// a cluster
+var ErrNoCredentials = errors.New("flypg: no saved credentials")
+
+// Credentials are a cluster's superuser credentials
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func credentialsPath(dir string, app string) string {
+	return filepath.Join(dir, "postgres", app+".json")
+}
+
+// SaveCredentials keeps the superuser credentials of the cluster app in
+// dir, unencrypted but readable only by the user, as they can't be fetched
+// again. It returns the path of the file.
+func SaveCredentials(dir string, app string, credentials Credentials) (string, error) {
+	path := credentialsPath(dir, app)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+
+	// WriteFile keeps the mode of a file that's already there
+	return path, os.Chmod(path, 0600)
+}
+
+// LoadCredentials returns the credentials saved for the cluster app in dir
+func LoadCredentials(dir string, app string) (Credentials, error) {
+	var credentials Credentials
+
+	data, err := ioutil.ReadFile(credentialsPath(dir, app))
+	if os.IsNotExist(err) {
+		return credentials, ErrNoCredentials
+	}
+	if err != nil {
+		return credentials, err
+	}
+
+	err = json.Unmarshal(data, &credentials)
+	return credentials, err
+}
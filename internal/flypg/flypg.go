@@ -0,0 +1,156 @@
+// Package flypg is a client for the admin API of Fly Postgres clusters,
+// which manages databases and users on the leader over the private network
+package flypg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// AdminPort is the port the admin API listens on, on every member
+const AdminPort = "5500"
+
+// requestTimeout bounds each admin API call
+const requestTimeout = 30 * time.Second
+
+// DialFunc opens connections on the cluster's private network
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Database is a database in the cluster and the users with access to it
+type Database struct {
+	Name  string   `json:"name"`
+	Users []string `json:"users"`
+}
+
+// User is a role in the cluster
+type User struct {
+	Username  string   `json:"username"`
+	Superuser bool     `json:"superuser"`
+	Databases []string `json:"databases"`
+}
+
+// Client calls the admin API of a cluster member
+type Client struct {
+	baseURL     string
+	http        *http.Client
+	credentials Credentials
+}
+
+// New returns a client for the member at host, reached with dial and
+// authenticated with credentials
+func New(dial DialFunc, host string, credentials Credentials) *Client {
+	return &Client{
+		baseURL: "http://" + net.JoinHostPort(host, AdminPort),
+		http: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{DialContext: dial},
+		},
+		credentials: credentials,
+	}
+}
+
+// response is the envelope of every admin API response
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+}
+
+// do calls the command at path with body, decoding its result into out
+// when it's not nil
+func (c *Client) do(ctx context.Context, path string, body interface{}, out interface{}) error {
+	method := http.MethodGet
+	var payload []byte
+	if body != nil {
+		method = http.MethodPost
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("the cluster rejected the credentials for %s", c.credentials.Username)
+	}
+
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("unexpected response from the admin API: %s", resp.Status)
+	}
+	if r.Error != "" {
+		return fmt.Errorf("%s", r.Error)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response from the admin API: %s", resp.Status)
+	}
+
+	if out != nil && len(r.Result) > 0 {
+		return json.Unmarshal(r.Result, out)
+	}
+	return nil
+}
+
+func (c *Client) ListDatabases(ctx context.Context) ([]Database, error) {
+	var databases []Database
+	if err := c.do(ctx, "/commands/databases/list", nil, &databases); err != nil {
+		return nil, err
+	}
+	return databases, nil
+}
+
+func (c *Client) CreateDatabase(ctx context.Context, name string) error {
+	return c.do(ctx, "/commands/databases/create", map[string]string{"name": name}, nil)
+}
+
+func (c *Client) DeleteDatabase(ctx context.Context, name string) error {
+	return c.do(ctx, "/commands/databases/delete", map[string]string{"name": name}, nil)
+}
+
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	if err := c.do(ctx, "/commands/users/list", nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (c *Client) CreateUser(ctx context.Context, username string, password string, superuser bool) error {
+	return c.do(ctx, "/commands/users/create", map[string]interface{}{
+		"username":  username,
+		"password":  password,
+		"superuser": superuser,
+	}, nil)
+}
+
+func (c *Client) DeleteUser(ctx context.Context, username string) error {
+	return c.do(ctx, "/commands/users/delete", map[string]string{"username": username}, nil)
+}
+
+// GrantAccess gives username access to database, read-only or to read and
+// write
+func (c *Client) GrantAccess(ctx context.Context, username string, database string, readOnly bool) error {
+	return c.do(ctx, "/commands/users/grant", map[string]interface{}{
+		"username": username,
+		"database": database,
+		"readonly": readOnly,
+	}, nil)
+}
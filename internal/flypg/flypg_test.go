@@ -0,0 +1,92 @@
+package flypg
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClient - a client whose connections all go to server
+func testClient(server *httptest.Server, credentials Credentials) *Client {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	return New(dial, "fdaa::2", credentials)
+}
+
+func TestClient(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "postgres" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "[fdaa::2]:5500", r.Host)
+
+		switch r.URL.Path {
+		case "/commands/databases/list":
+			w.Write([]byte(`{"result":[{"name":"app","users":["app"]}]}`))
+		case "/commands/users/grant":
+			got = map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"result":true}`))
+		case "/commands/databases/create":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"database \"app\" already exists"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := testClient(server, Credentials{Username: "postgres", Password: "secret"})
+
+	databases, err := c.ListDatabases(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []Database{{Name: "app", Users: []string{"app"}}}, databases)
+
+	assert.NoError(t, c.GrantAccess(ctx, "reporting", "app", true))
+	assert.Equal(t, map[string]interface{}{"username": "reporting", "database": "app", "readonly": true}, got)
+
+	assert.EqualError(t, c.CreateDatabase(ctx, "app"), `database "app" already exists`)
+
+	c = testClient(server, Credentials{Username: "postgres", Password: "wrong"})
+	_, err = c.ListUsers(ctx)
+	assert.EqualError(t, err, "the cluster rejected the credentials for postgres")
+}
+
+func TestCredentials(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadCredentials(dir, "pg")
+	assert.Equal(t, ErrNoCredentials, err)
+
+	path, err := SaveCredentials(dir, "pg", Credentials{Username: "postgres", Password: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "postgres", "pg.json"), path)
+
+	credentials, err := LoadCredentials(dir, "pg")
+	assert.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "postgres", Password: "secret"}, credentials)
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// a file left readable by others is locked down when overwritten
+	assert.NoError(t, os.Chmod(path, 0644))
+	_, err = SaveCredentials(dir, "pg", Credentials{Username: "postgres", Password: "secret2"})
+	assert.NoError(t, err)
+	info, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
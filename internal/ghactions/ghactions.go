@@ -0,0 +1,181 @@
+// Package ghactions integrates with GitHub Actions: it records deployments
+// and their statuses through the GitHub API and writes workflow annotations.
+package ghactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the GitHub API, used when GITHUB_API_URL isn't set
+const DefaultAPIURL = "https://api.github.com"
+
+// Deployment states, see
+// https://docs.github.com/en/rest/deployments/statuses
+const (
+	StateInProgress = "in_progress"
+	StateSuccess    = "success"
+	StateFailure    = "failure"
+	StateError      = "error"
+)
+
+// ErrNotActions is returned by FromEnv outside of GitHub Actions
+var ErrNotActions = errors.New("not running in GitHub Actions")
+
+// Run is the workflow run flyctl is running in, and a client for its
+// repository
+type Run struct {
+	APIURL     string
+	Repository string
+	// SHA is the commit the workflow runs for
+	SHA string
+	// URL links to the run's logs, empty when unknown
+	URL   string
+	Token string
+
+	client *http.Client
+}
+
+// FromEnv reads the workflow run from the variables GitHub Actions sets.
+// The token is read from GITHUB_TOKEN, which workflows have to pass on.
+// getenv looks up environment variables.
+func FromEnv(getenv func(string) string) (*Run, error) {
+	if getenv("GITHUB_ACTIONS") != "true" {
+		return nil, ErrNotActions
+	}
+
+	run := &Run{
+		APIURL:     strings.TrimSuffix(getenv("GITHUB_API_URL"), "/"),
+		Repository: getenv("GITHUB_REPOSITORY"),
+		SHA:        getenv("GITHUB_SHA"),
+		Token:      getenv("GITHUB_TOKEN"),
+		client:     &http.Client{Timeout: 15 * time.Second},
+	}
+	if run.APIURL == "" {
+		run.APIURL = DefaultAPIURL
+	}
+	if server, id := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_RUN_ID"); server != "" && id != "" {
+		run.URL = fmt.Sprintf("%s/%s/actions/runs/%s", server, run.Repository, id)
+	}
+
+	switch {
+	case run.Repository == "" || run.SHA == "":
+		return nil, errors.New("GITHUB_REPOSITORY and GITHUB_SHA must be set")
+	case run.Token == "":
+		return nil, errors.New("GITHUB_TOKEN must be set, pass it with env: GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }} and grant the job deployments: write")
+	}
+	return run, nil
+}
+
+// CreateDeployment records a deployment of the run's commit to environment,
+// returning its ID
+func (r *Run) CreateDeployment(ctx context.Context, environment string, description string) (int64, error) {
+	body := map[string]interface{}{
+		"ref":               r.SHA,
+		"environment":       environment,
+		"description":       description,
+		"auto_merge":        false,
+		"required_contexts": []string{},
+	}
+
+	var deployment struct {
+		ID int64 `json:"id"`
+	}
+	if err := r.post(ctx, fmt.Sprintf("/repos/%s/deployments", r.Repository), body, &deployment); err != nil {
+		return 0, err
+	}
+	return deployment.ID, nil
+}
+
+// CreateDeploymentStatus sets the state of a deployment. environmentURL is
+// where the deployed app can be reached, omitted when empty.
+func (r *Run) CreateDeploymentStatus(ctx context.Context, id int64, state string, description string, environmentURL string) error {
+	body := map[string]interface{}{
+		"state":       state,
+		"description": truncate(description, 140),
+	}
+	if r.URL != "" {
+		body["log_url"] = r.URL
+	}
+	if environmentURL != "" {
+		body["environment_url"] = environmentURL
+	}
+	return r.post(ctx, fmt.Sprintf("/repos/%s/deployments/%d/statuses", r.Repository, id), body, nil)
+}
+
+func (r *Run) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.APIURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.Token)
+
+	client := r.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(msg, &failure) == nil && failure.Message != "" {
+			return fmt.Errorf("GitHub returned %s: %s", resp.Status, failure.Message)
+		}
+		return fmt.Errorf("GitHub returned %s", resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Annotation levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNotice  = "notice"
+)
+
+// Annotate writes a workflow command that shows message in the run's
+// summary, see
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+func Annotate(w io.Writer, level string, title string, message string) {
+	fmt.Fprintf(w, "::%s title=%s::%s\n", level, escapeProperty(title), escapeData(message))
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n-3] + "..."
+	}
+	return s
+}
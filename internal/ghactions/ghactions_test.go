@@ -0,0 +1,86 @@
+package ghactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestFromEnv(t *testing.T) {
+	_, err := FromEnv(env(nil))
+	assert.Equal(t, ErrNotActions, err)
+
+	_, err = FromEnv(env(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "acme/web",
+		"GITHUB_SHA":        "abc123",
+	}))
+	assert.Error(t, err)
+
+	run, err := FromEnv(env(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "acme/web",
+		"GITHUB_SHA":        "abc123",
+		"GITHUB_TOKEN":      "token",
+		"GITHUB_SERVER_URL": "https://github.com",
+		"GITHUB_RUN_ID":     "42",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultAPIURL, run.APIURL)
+	assert.Equal(t, "https://github.com/acme/web/actions/runs/42", run.URL)
+}
+
+func TestDeployment(t *testing.T) {
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies[r.URL.Path] = body
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7}`))
+	}))
+	defer server.Close()
+
+	run := &Run{APIURL: server.URL, Repository: "acme/web", SHA: "abc123", Token: "token", URL: "https://github.com/acme/web/actions/runs/42"}
+
+	id, err := run.CreateDeployment(context.Background(), "web", "Deploying web")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.Equal(t, "abc123", bodies["/repos/acme/web/deployments"]["ref"])
+
+	err = run.CreateDeploymentStatus(context.Background(), id, StateSuccess, "Deployed v3", "https://web.fly.dev")
+	assert.NoError(t, err)
+	status := bodies["/repos/acme/web/deployments/7/statuses"]
+	assert.Equal(t, "success", status["state"])
+	assert.Equal(t, "https://web.fly.dev", status["environment_url"])
+	assert.Equal(t, run.URL, status["log_url"])
+}
+
+func TestDeploymentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	run := &Run{APIURL: server.URL, Repository: "acme/web", SHA: "abc123", Token: "token"}
+	_, err := run.CreateDeployment(context.Background(), "web", "")
+	assert.EqualError(t, err, "GitHub returned 403 Forbidden: Resource not accessible by integration")
+}
+
+func TestAnnotate(t *testing.T) {
+	var out bytes.Buffer
+	Annotate(&out, LevelError, "Health check: http, on a1", "GET / returned 500\n100% broken")
+	assert.Equal(t, "::error title=Health check%3A http%2C on a1::GET / returned 500%0A100%25 broken\n", out.String())
+}
@@ -0,0 +1,221 @@
+// Package nats is a minimal client for the NATS protocol, enough to
+// subscribe to the platform's event stream over the private network
+package nats
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultAddr is the event stream's address on an organization's private
+// network
+const DefaultAddr = "[fdaa::3]:4223"
+
+// maxPayload caps message sizes when the server doesn't say
+const maxPayload = 8 << 20
+
+// Options are sent to the server when connecting
+type Options struct {
+	User     string
+	Password string
+	Name     string
+	Version  string
+}
+
+// Msg is a message received on a subscription
+type Msg struct {
+	Subject string
+	Data    []byte
+}
+
+// ServerError is an -ERR sent by the server
+type ServerError struct {
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return "nats: " + e.Message
+}
+
+// Conn is a connection to a NATS server
+type Conn struct {
+	conn       net.Conn
+	r          *bufio.Reader
+	mu         sync.Mutex
+	sid        int
+	maxPayload int
+}
+
+type serverInfo struct {
+	ServerID   string `json:"server_id"`
+	MaxPayload int    `json:"max_payload"`
+}
+
+type connectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"pass,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version,omitempty"`
+	Protocol int    `json:"protocol"`
+}
+
+// Connect performs the NATS handshake over conn, returning once the server
+// has accepted the connection
+func Connect(conn net.Conn, opts Options) (*Conn, error) {
+	c := &Conn{conn: conn, r: bufio.NewReader(conn), maxPayload: maxPayload}
+
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	op, args := splitOp(line)
+	if op != "INFO" {
+		return nil, fmt.Errorf("nats: expected INFO from server, got %q", op)
+	}
+	var info serverInfo
+	if err := json.Unmarshal([]byte(args), &info); err != nil {
+		return nil, fmt.Errorf("nats: invalid INFO: %w", err)
+	}
+	if info.MaxPayload > 0 {
+		c.maxPayload = info.MaxPayload
+	}
+
+	connect, err := json.Marshal(connectOptions{
+		User:     opts.User,
+		Password: opts.Password,
+		Name:     opts.Name,
+		Lang:     "go",
+		Version:  opts.Version,
+		Protocol: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.write("CONNECT " + string(connect) + "\r\nPING\r\n"); err != nil {
+		return nil, err
+	}
+
+	// the server answers the PING once it has accepted the CONNECT
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		op, args := splitOp(line)
+		switch op {
+		case "PONG":
+			return c, nil
+		case "-ERR":
+			return nil, &ServerError{strings.Trim(args, "'")}
+		case "+OK", "INFO":
+		default:
+			return nil, fmt.Errorf("nats: unexpected %q while connecting", op)
+		}
+	}
+}
+
+// Subscribe starts receiving messages on subject, which may use the *
+// and > wildcards
+func (c *Conn) Subscribe(subject string) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("nats: invalid subject %q", subject)
+	}
+
+	c.mu.Lock()
+	c.sid++
+	sid := c.sid
+	c.mu.Unlock()
+
+	return c.write(fmt.Sprintf("SUB %s %d\r\n", subject, sid))
+}
+
+// Next blocks until the next message arrives on any subscription, answering
+// the server's pings while it waits
+func (c *Conn) Next() (*Msg, error) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		op, args := splitOp(line)
+		switch op {
+		case "MSG":
+			return c.readMsg(args)
+		case "PING":
+			if err := c.write("PONG\r\n"); err != nil {
+				return nil, err
+			}
+		case "-ERR":
+			return nil, &ServerError{strings.Trim(args, "'")}
+		case "PONG", "+OK", "INFO":
+		default:
+			return nil, fmt.Errorf("nats: unexpected %q from server", op)
+		}
+	}
+}
+
+// Close closes the connection, unblocking Next
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// readMsg reads the payload of a MSG with args
+// <subject> <sid> [reply-to] <#bytes>
+func (c *Conn) readMsg(args string) (*Msg, error) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 && len(fields) != 4 {
+		return nil, fmt.Errorf("nats: malformed MSG %q", args)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 || size > c.maxPayload {
+		return nil, fmt.Errorf("nats: malformed MSG size %q", fields[len(fields)-1])
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return nil, err
+	}
+	if string(data[size:]) != "\r\n" {
+		return nil, errors.New("nats: MSG payload isn't terminated")
+	}
+
+	return &Msg{Subject: fields[0], Data: data[:size]}, nil
+}
+
+func (c *Conn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *Conn) write(s string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := io.WriteString(c.conn, s)
+	return err
+}
+
+// splitOp splits a protocol line into its operation, uppercased, and the
+// rest of it
+func splitOp(line string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	op := strings.ToUpper(parts[0])
+	if len(parts) == 1 {
+		return op, ""
+	}
+	return op, strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,91 @@
+package nats
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeServer runs script against the client end of a pipe: lines starting
+// with "> " are sent to the client, others are expected from it
+func fakeServer(t *testing.T, script []string) net.Conn {
+	client, server := net.Pipe()
+
+	go func() {
+		defer server.Close()
+		r := bufio.NewReader(server)
+		for _, step := range script {
+			if strings.HasPrefix(step, "> ") {
+				if _, err := server.Write([]byte(step[2:])); err != nil {
+					return
+				}
+				continue
+			}
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if !strings.HasPrefix(line, step) {
+				t.Errorf("expected %q from client, got %q", step, line)
+				return
+			}
+		}
+	}()
+
+	return client
+}
+
+func TestSubscribe(t *testing.T) {
+	conn := fakeServer(t, []string{
+		"> INFO {\"server_id\":\"test\",\"max_payload\":1024}\r\n",
+		`CONNECT {"verbose":false,"pedantic":false,"user":"personal","pass":"token"`,
+		"PING",
+		"> +OK\r\nPONG\r\n",
+		"SUB events.myapp.> 1",
+		"> PING\r\n",
+		"PONG",
+		"> MSG events.myapp.deploy 1 11\r\nhello\r\nworl\r\n",
+		"> msg events.myapp.start 1 reply.to 2\r\nhi\r\n",
+	})
+
+	c, err := Connect(conn, Options{User: "personal", Password: "token", Name: "flyctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.Subscribe("events.myapp.>"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := c.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "events.myapp.deploy" || string(msg.Data) != "hello\r\nworl" {
+		t.Errorf("unexpected message %q: %q", msg.Subject, msg.Data)
+	}
+
+	msg, err = c.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "events.myapp.start" || string(msg.Data) != "hi" {
+		t.Errorf("unexpected message %q: %q", msg.Subject, msg.Data)
+	}
+}
+
+func TestConnectRejected(t *testing.T) {
+	conn := fakeServer(t, []string{
+		"> INFO {}\r\n",
+		"CONNECT",
+		"PING",
+		"> -ERR 'Authorization Violation'\r\n",
+	})
+
+	_, err := Connect(conn, Options{User: "personal", Password: "wrong"})
+	if err == nil || err.Error() != "nats: Authorization Violation" {
+		t.Fatalf("expected an authorization error, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package prompt asks the user for input. Every prompt goes through here so
+// that flyctl fails fast, naming the flag to use instead, when nobody is
+// around to answer.
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/google/shlex"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/update"
+)
+
+// NonInteractiveError - returned instead of prompting when flyctl is running
+// non-interactively
+type NonInteractiveError struct {
+	Message string
+	Flag    string
+}
+
+func (e *NonInteractiveError) Error() string {
+	if e.Flag == "" {
+		return fmt.Sprintf("can't prompt for %q when running non-interactively", e.Message)
+	}
+	return fmt.Sprintf("can't prompt for %q when running non-interactively, use --%s", e.Message, e.Flag)
+}
+
+// IsNonInteractiveError reports whether err came from a prompt that couldn't
+// be shown
+func IsNonInteractiveError(err error) bool {
+	var nonInteractive *NonInteractiveError
+	return errors.As(err, &nonInteractive)
+}
+
+// IsInteractive reports whether prompts can be shown: stdin and stdout are
+// both terminals, --non-interactive wasn't given and this isn't a CI job.
+func IsInteractive() bool {
+	if viper.GetBool(flyctl.ConfigNonInteractive) || update.IsCI() {
+		return false
+	}
+
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Ask shows p and stores the answer in response. When running
+// non-interactively it returns a NonInteractiveError naming flag, the flag
+// that supplies the same value, instead. flag may be empty when there's none.
+func Ask(p survey.Prompt, response interface{}, flag string, opts ...survey.AskOpt) error {
+	if !IsInteractive() {
+		return &NonInteractiveError{Message: message(p), Flag: flag}
+	}
+
+	return survey.AskOne(p, response, opts...)
+}
+
+// Confirm asks a yes/no question, defaulting to no
+func Confirm(message string, flag string) (bool, error) {
+	confirm := false
+	err := Ask(&survey.Confirm{Message: message}, &confirm, flag)
+	return confirm, err
+}
+
+// multiSelectPageSize - how many options a checkbox list shows at once
+const multiSelectPageSize = 15
+
+// MultiSelect shows options as a checkbox list with defaults checked and
+// returns the indexes of the chosen options, in order. Typing filters the
+// list. flag is as for Ask.
+func MultiSelect(message string, options []string, defaults []string, flag string) ([]int, error) {
+	selected := []int{}
+	err := Ask(&survey.MultiSelect{
+		Message:  message,
+		Options:  options,
+		Default:  defaults,
+		PageSize: multiSelectPageSize,
+	}, &selected, flag)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Ints(selected)
+	return selected, nil
+}
+
+func message(p survey.Prompt) string {
+	switch p := p.(type) {
+	case *survey.Input:
+		return p.Message
+	case *survey.Password:
+		return p.Message
+	case *survey.Confirm:
+		return p.Message
+	case *survey.Select:
+		return p.Message
+	case *survey.MultiSelect:
+		return p.Message
+	case *survey.Multiline:
+		return p.Message
+	case *survey.Editor:
+		return p.Message
+	}
+	return "input"
+}
+
+// Edit opens contents in the user's $VISUAL or $EDITOR and returns the edited
+// text. name is used for the temporary file so editors can pick a syntax, and
+// to describe what's being edited when running non-interactively.
+func Edit(name string, contents string, flag string) (string, error) {
+	if !IsInteractive() {
+		return "", &NonInteractiveError{Message: name, Flag: flag}
+	}
+
+	dir, err := os.MkdirTemp("", "flyctl-edit")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		return "", err
+	}
+
+	args, err := shlex.Split(editor())
+	if err != nil || len(args) == 0 {
+		return "", fmt.Errorf("can't run editor %q", editor())
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(edited), nil
+}
+
+func editor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := os.Getenv(env); e != "" {
+			return e
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
@@ -0,0 +1,117 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/flyctl"
+)
+
+func nonInteractive(t *testing.T) {
+	viper.Set(flyctl.ConfigNonInteractive, true)
+	t.Cleanup(func() { viper.Set(flyctl.ConfigNonInteractive, false) })
+}
+
+func TestNonInteractivePrompts(t *testing.T) {
+	nonInteractive(t)
+
+	if IsInteractive() {
+		t.Fatal("expected --non-interactive to disable prompts")
+	}
+
+	tests := []struct {
+		name        string
+		ask         func() error
+		wantMessage string
+		wantFlag    string
+	}{
+		{
+			name: "input",
+			ask: func() error {
+				var name string
+				return Ask(&survey.Input{Message: "App name:"}, &name, "name")
+			},
+			wantMessage: "App name:",
+			wantFlag:    "name",
+		},
+		{
+			name: "select",
+			ask: func() error {
+				var region int
+				return Ask(&survey.Select{Message: "Select region:", Options: []string{"ord", "lhr"}}, &region, "region")
+			},
+			wantMessage: "Select region:",
+			wantFlag:    "region",
+		},
+		{
+			name: "password without a flag",
+			ask: func() error {
+				var password string
+				return Ask(&survey.Password{Message: "Password:"}, &password, "")
+			},
+			wantMessage: "Password:",
+		},
+		{
+			name: "confirm",
+			ask: func() error {
+				_, err := Confirm("Destroy myapp?", "yes")
+				return err
+			},
+			wantMessage: "Destroy myapp?",
+			wantFlag:    "yes",
+		},
+		{
+			name: "multi-select",
+			ask: func() error {
+				_, err := MultiSelect("Select the app's regions:", []string{"ord", "lhr"}, nil, "")
+				return err
+			},
+			wantMessage: "Select the app's regions:",
+		},
+		{
+			name: "edit",
+			ask: func() error {
+				_, err := Edit("fly.toml", "app = \"myapp\"\n", "config")
+				return err
+			},
+			wantMessage: "fly.toml",
+			wantFlag:    "config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ask()
+
+			var nonInteractive *NonInteractiveError
+			if !errors.As(err, &nonInteractive) {
+				t.Fatalf("expected a NonInteractiveError, got %v", err)
+			}
+			if nonInteractive.Message != tt.wantMessage || nonInteractive.Flag != tt.wantFlag {
+				t.Errorf("got message %q and flag %q, want %q and %q", nonInteractive.Message, nonInteractive.Flag, tt.wantMessage, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestNonInteractiveError(t *testing.T) {
+	withFlag := &NonInteractiveError{Message: "Select region:", Flag: "region"}
+	if got, want := withFlag.Error(), `can't prompt for "Select region:" when running non-interactively, use --region`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	withoutFlag := &NonInteractiveError{Message: "Password:"}
+	if got, want := withoutFlag.Error(), `can't prompt for "Password:" when running non-interactively`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if !IsNonInteractiveError(fmt.Errorf("launch: %w", withFlag)) {
+		t.Error("expected a wrapped NonInteractiveError to be recognized")
+	}
+	if IsNonInteractiveError(errors.New("interrupt")) {
+		t.Error("expected other errors not to be recognized")
+	}
+}
@@ -0,0 +1,216 @@
+// Package promql runs PromQL queries against an organization's Prometheus
+// endpoint through the Prometheus HTTP API.
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is where organizations' Prometheus endpoints are, under
+// the organization's slug
+const DefaultBaseURL = "https://api.fly.io/prometheus"
+
+// Client queries the Prometheus endpoint of an organization
+type Client struct {
+	// BaseURL defaults to DefaultBaseURL
+	BaseURL string
+	Org     string
+	Token   string
+	HTTP    *http.Client
+}
+
+// Result is a query's result. Vectors and scalars have one point per
+// series, matrices one per step.
+type Result struct {
+	Type   string   `json:"resultType"`
+	Series []Series `json:"result"`
+}
+
+// Series is a set of labels and their values
+type Series struct {
+	Labels map[string]string `json:"metric"`
+	Points []Point           `json:"values"`
+}
+
+// Point is a value at a time
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// String formats the labels as {a="1", b="2"}, with __name__ in front
+func (s Series) String() string {
+	names := make([]string, 0, len(s.Labels))
+	for name := range s.Labels {
+		if name != "__name__" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, s.Labels[name]))
+	}
+
+	return s.Labels["__name__"] + "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Query evaluates query at a time, now when it's zero
+func (c *Client) Query(ctx context.Context, query string, at time.Time) (*Result, error) {
+	params := url.Values{"query": {query}}
+	if !at.IsZero() {
+		params.Set("time", formatTime(at))
+	}
+	return c.get(ctx, "/api/v1/query", params)
+}
+
+// QueryRange evaluates query every step from start to end
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*Result, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	return c.get(ctx, "/api/v1/query_range", params)
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', 3, 64)
+}
+
+type response struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values) (*Result, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	u := strings.TrimSuffix(base, "/") + "/" + url.PathEscape(c.Org) + path + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("prometheus: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("prometheus: invalid response: %w", err)
+	}
+	if body.Status != "success" {
+		if body.Error == "" {
+			return nil, fmt.Errorf("prometheus: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("prometheus: %s: %s", body.ErrorType, body.Error)
+	}
+
+	return parseResult(body.Data.ResultType, body.Data.Result)
+}
+
+// parseResult converts the API's [time, "value"] pairs into series of points
+func parseResult(resultType string, raw json.RawMessage) (*Result, error) {
+	result := &Result{Type: resultType, Series: []Series{}}
+
+	switch resultType {
+	case "vector":
+		var vector []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &vector); err != nil {
+			return nil, err
+		}
+		for _, v := range vector {
+			p, err := parsePoint(v.Value)
+			if err != nil {
+				return nil, err
+			}
+			result.Series = append(result.Series, Series{Labels: v.Metric, Points: []Point{p}})
+		}
+	case "matrix":
+		var matrix []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		}
+		if err := json.Unmarshal(raw, &matrix); err != nil {
+			return nil, err
+		}
+		for _, m := range matrix {
+			series := Series{Labels: m.Metric, Points: make([]Point, 0, len(m.Values))}
+			for _, v := range m.Values {
+				p, err := parsePoint(v)
+				if err != nil {
+					return nil, err
+				}
+				series.Points = append(series.Points, p)
+			}
+			result.Series = append(result.Series, series)
+		}
+	case "scalar":
+		var scalar []interface{}
+		if err := json.Unmarshal(raw, &scalar); err != nil {
+			return nil, err
+		}
+		p, err := parsePoint(scalar)
+		if err != nil {
+			return nil, err
+		}
+		result.Series = append(result.Series, Series{Labels: map[string]string{}, Points: []Point{p}})
+	default:
+		return nil, fmt.Errorf("prometheus: unsupported result type %q", resultType)
+	}
+
+	return result, nil
+}
+
+func parsePoint(pair []interface{}) (Point, error) {
+	if len(pair) != 2 {
+		return Point{}, fmt.Errorf("prometheus: invalid sample %v", pair)
+	}
+	ts, ok := pair[0].(float64)
+	if !ok {
+		return Point{}, fmt.Errorf("prometheus: invalid sample time %v", pair[0])
+	}
+	s, ok := pair[1].(string)
+	if !ok {
+		return Point{}, fmt.Errorf("prometheus: invalid sample value %v", pair[1])
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("prometheus: invalid sample value %q", s)
+	}
+
+	sec := int64(ts)
+	return Point{Time: time.Unix(sec, int64((ts-float64(sec))*1e9)).UTC(), Value: value}, nil
+}
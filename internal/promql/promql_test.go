@@ -0,0 +1,94 @@
+package promql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/my-org/api/v1/query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("query") != `sum(rate(fly_app_http_responses_count[5m])) by (region)` {
+			t.Errorf("unexpected query %s", r.URL.Query().Get("query"))
+		}
+
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"region":"iad"},"value":[1620000000.5,"12.25"]},
+			{"metric":{"region":"lhr"},"value":[1620000000.5,"3"]}
+		]}}`)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Org: "my-org", Token: "token"}
+	result, err := c.Query(context.Background(), `sum(rate(fly_app_http_responses_count[5m])) by (region)`, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Type != "vector" || len(result.Series) != 2 {
+		t.Fatalf("got %+v", result)
+	}
+	iad := result.Series[0]
+	if iad.String() != `{region="iad"}` {
+		t.Errorf("got labels %s", iad)
+	}
+	if len(iad.Points) != 1 || iad.Points[0].Value != 12.25 {
+		t.Errorf("got points %+v", iad.Points)
+	}
+	if want := time.Unix(1620000000, 5e8).UTC(); !iad.Points[0].Time.Equal(want) {
+		t.Errorf("got time %s, want %s", iad.Points[0].Time, want)
+	}
+}
+
+func TestQueryRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("step") != "60" {
+			t.Errorf("unexpected step %s", r.URL.Query().Get("step"))
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"__name__":"up","app":"web"},"values":[[1620000000,"1"],[1620000060,"0"]]}
+		]}}`)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Org: "my-org"}
+	end := time.Unix(1620000060, 0)
+	result, err := c.QueryRange(context.Background(), "up", end.Add(-time.Minute), end, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Type != "matrix" || len(result.Series) != 1 || len(result.Series[0].Points) != 2 {
+		t.Fatalf("got %+v", result)
+	}
+	if result.Series[0].String() != `up{app="web"}` {
+		t.Errorf("got labels %s", result.Series[0])
+	}
+	if result.Series[0].Points[1].Value != 0 {
+		t.Errorf("got points %+v", result.Series[0].Points)
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error at char 4"}`)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Org: "my-org"}
+	_, err := c.Query(context.Background(), "sum(", time.Time{})
+	if err == nil || err.Error() != "prometheus: bad_data: parse error at char 4" {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Package render writes command output in the machine readable formats
+// selected with the global --json, --yaml and --format flags.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Format - an output format for structured data
+type Format string
+
+const (
+	// FormatText is flyctl's regular human readable output
+	FormatText     Format = ""
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatTemplate Format = "template"
+	// FormatPrometheus is the Prometheus text exposition format, selected
+	// with --format prometheus by commands that report metrics
+	FormatPrometheus Format = "prometheus"
+)
+
+// Options - the output format and, for FormatTemplate, the Go template to use
+type Options struct {
+	Format   Format
+	Template string
+}
+
+// Structured reports whether output should be data rather than text
+func (o Options) Structured() bool {
+	return o.Format != FormatText
+}
+
+// Data writes data to w in the format selected by opts. Templates are executed
+// once for each element when data is a slice, and once otherwise.
+func Data(w io.Writer, opts Options, data interface{}) error {
+	switch opts.Format {
+	case FormatJSON:
+		return JSON(w, data)
+	case FormatYAML:
+		return YAML(w, data)
+	case FormatTemplate:
+		return Template(w, opts.Template, data)
+	case FormatPrometheus:
+		metrics, ok := data.([]Metric)
+		if !ok {
+			return fmt.Errorf("this command doesn't support the %s format", FormatPrometheus)
+		}
+		return Prometheus(w, metrics)
+	default:
+		return fmt.Errorf("unsupported output format %q", opts.Format)
+	}
+}
+
+// JSON writes data to w as indented JSON
+func JSON(w io.Writer, data interface{}) error {
+	out, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// YAML writes data to w as YAML, using the same field names as JSON output
+func YAML(w io.Writer, data interface{}) error {
+	// round trip through JSON so the json struct tags name the fields
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal(jsonData, &value); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Template executes the Go template text against data, writing to w
+func Template(w io.Writer, text string, data interface{}) error {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+
+	items := []interface{}{data}
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items = make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(w, item); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testApp struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func TestTemplateRendersEachElementOfASlice(t *testing.T) {
+	var buf bytes.Buffer
+	apps := []testApp{{Name: "one", Status: "running"}, {Name: "two", Status: "dead"}}
+
+	err := Data(&buf, Options{Format: FormatTemplate, Template: "{{.Name}} {{.Status}}"}, apps)
+	assert.NoError(t, err)
+	assert.Equal(t, "one running\ntwo dead\n", buf.String())
+}
+
+func TestYAMLUsesJSONFieldNames(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Data(&buf, Options{Format: FormatYAML}, testApp{Name: "one", Status: "running"})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: one\nstatus: running\n", buf.String())
+}
+
+func TestInvalidTemplate(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Data(&buf, Options{Format: FormatTemplate, Template: "{{.Name"}, testApp{})
+	assert.Error(t, err)
+}
+
+func TestPrometheus(t *testing.T) {
+	var buf bytes.Buffer
+
+	metrics := []Metric{
+		{
+			Name: "fly_app_allocations",
+			Help: "Instances of the app by status",
+			Type: "gauge",
+			Samples: []Sample{
+				{Labels: map[string]string{"status": "running", "app": "my-app"}, Value: 3},
+				{Labels: map[string]string{"status": "failed", "app": `say "hi"`}, Value: 0.5},
+			},
+		},
+		{Name: "fly_up", Samples: []Sample{{Value: 1}}},
+	}
+
+	err := Data(&buf, Options{Format: FormatPrometheus}, metrics)
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP fly_app_allocations Instances of the app by status
+# TYPE fly_app_allocations gauge
+fly_app_allocations{app="my-app",status="running"} 3
+fly_app_allocations{app="say \"hi\"",status="failed"} 0.5
+fly_up 1
+`, buf.String())
+}
+
+func TestPrometheusNeedsMetrics(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Data(&buf, Options{Format: FormatPrometheus}, testApp{})
+	assert.EqualError(t, err, "this command doesn't support the prometheus format")
+}
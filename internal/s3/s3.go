@@ -0,0 +1,286 @@
+// Package s3 writes objects to S3 compatible object storage, large ones with
+// multipart uploads, which can be picked up again after an interruption.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/internal/sigv4"
+)
+
+// DefaultRegion is used when the client has no region
+const DefaultRegion = "us-east-1"
+
+// MinPartSize is the smallest size S3 accepts for parts other than the last
+const MinPartSize = 5 << 20
+
+// MaxParts is the most parts an upload can have
+const MaxParts = 10000
+
+// Location is an object's bucket and key
+type Location struct {
+	Bucket string
+	Key    string
+}
+
+// ParseURL parses s3://bucket/path/to/key
+func ParseURL(s string) (Location, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Location{}, err
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return Location{}, fmt.Errorf("invalid S3 URL %q, use s3://bucket/path", s)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+	if key == "" || strings.HasSuffix(key, "/") {
+		return Location{}, fmt.Errorf("invalid S3 URL %q, the path must name an object", s)
+	}
+
+	return Location{Bucket: u.Host, Key: key}, nil
+}
+
+func (l Location) String() string {
+	return "s3://" + l.Bucket + "/" + l.Key
+}
+
+// Client writes to a bucket with AWS credentials
+type Client struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Region defaults to DefaultRegion
+	Region string
+	// Endpoint is the URL of S3 compatible storage, addressed path style.
+	// Empty for AWS.
+	Endpoint string
+	HTTP     *http.Client
+}
+
+// Part is an uploaded part of a multipart upload
+type Part struct {
+	Number int    `xml:"PartNumber"`
+	ETag   string `xml:"ETag"`
+	Size   int64  `xml:"Size"`
+}
+
+// Error is an error returned by the storage
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("s3: %s (%d)", e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+// IsNoSuchUpload reports whether err is because the upload doesn't exist,
+// e.g. it was completed, aborted or expired
+func IsNoSuchUpload(err error) bool {
+	var s3Err *Error
+	return errors.As(err, &s3Err) && s3Err.Code == "NoSuchUpload"
+}
+
+// PutObject writes data to loc in a single request, replacing any object
+// already there. Objects larger than MinPartSize are better uploaded in parts.
+func (c *Client) PutObject(ctx context.Context, loc Location, data []byte) error {
+	return c.do(ctx, http.MethodPut, loc, nil, data, nil)
+}
+
+// CreateMultipartUpload starts an upload to loc, returning its ID
+func (c *Client) CreateMultipartUpload(ctx context.Context, loc Location) (string, error) {
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := c.do(ctx, http.MethodPost, loc, url.Values{"uploads": {""}}, nil, &result); err != nil {
+		return "", err
+	}
+	return result.UploadID, nil
+}
+
+// UploadPart uploads data as part number of the upload. The storage checks
+// the data against its MD5 and SHA256 digests.
+func (c *Client) UploadPart(ctx context.Context, loc Location, uploadID string, number int, data []byte) (Part, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+
+	var etag string
+	err := c.request(ctx, http.MethodPut, loc, query, data, func(resp *http.Response) error {
+		etag = resp.Header.Get("ETag")
+		return nil
+	})
+	if err != nil {
+		return Part{}, err
+	}
+
+	return Part{Number: number, ETag: etag, Size: int64(len(data))}, nil
+}
+
+// ListParts returns the parts uploaded so far, in order
+func (c *Client) ListParts(ctx context.Context, loc Location, uploadID string) ([]Part, error) {
+	parts := []Part{}
+	marker := ""
+
+	for {
+		query := url.Values{"uploadId": {uploadID}}
+		if marker != "" {
+			query.Set("part-number-marker", marker)
+		}
+
+		var result struct {
+			Parts                []Part `xml:"Part"`
+			IsTruncated          bool
+			NextPartNumberMarker string
+		}
+		if err := c.do(ctx, http.MethodGet, loc, query, nil, &result); err != nil {
+			return nil, err
+		}
+		parts = append(parts, result.Parts...)
+
+		if !result.IsTruncated || result.NextPartNumberMarker == "" {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, nil
+}
+
+// CompleteMultipartUpload assembles the parts into the object
+func (c *Client) CompleteMultipartUpload(ctx context.Context, loc Location, uploadID string, parts []Part) error {
+	type completedPart struct {
+		Number int    `xml:"PartNumber"`
+		ETag   string `xml:"ETag"`
+	}
+	request := struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{}
+	for _, p := range parts {
+		request.Parts = append(request.Parts, completedPart{p.Number, p.ETag})
+	}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	// completing can fail after the response has started, with an error in
+	// a 200 response
+	var result struct {
+		XMLName xml.Name
+		Code    string
+		Message string
+	}
+	if err := c.do(ctx, http.MethodPost, loc, url.Values{"uploadId": {uploadID}}, body, &result); err != nil {
+		return err
+	}
+	if result.XMLName.Local == "Error" {
+		return &Error{StatusCode: http.StatusOK, Code: result.Code, Message: result.Message}
+	}
+
+	return nil
+}
+
+// AbortMultipartUpload discards the upload and its parts
+func (c *Client) AbortMultipartUpload(ctx context.Context, loc Location, uploadID string) error {
+	return c.do(ctx, http.MethodDelete, loc, url.Values{"uploadId": {uploadID}}, nil, nil)
+}
+
+// do sends a request, decoding the XML response into result when it's set
+func (c *Client) do(ctx context.Context, method string, loc Location, query url.Values, body []byte, result interface{}) error {
+	return c.request(ctx, method, loc, query, body, func(resp *http.Response) error {
+		if result == nil {
+			return nil
+		}
+		return xml.NewDecoder(resp.Body).Decode(result)
+	})
+}
+
+func (c *Client) request(ctx context.Context, method string, loc Location, query url.Values, body []byte, handle func(*http.Response) error) error {
+	endpoint, path := c.objectURL(loc)
+	rawQuery := sigv4.CanonicalQuery(query)
+
+	u := endpoint + path
+	if rawQuery != "" {
+		u += "?" + rawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	if len(body) > 0 {
+		sum := md5.Sum(body)
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	payloadHash := sigv4.HashPayload(body)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds := sigv4.Credentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}
+	sigv4.Sign(req, creds, c.region(), "s3", payloadHash, time.Now())
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		s3Err := &Error{StatusCode: resp.StatusCode, Code: resp.Status}
+		data, _ := io.ReadAll(resp.Body)
+		var apiErr struct {
+			Code    string
+			Message string
+		}
+		if xml.Unmarshal(data, &apiErr) == nil && apiErr.Code != "" {
+			s3Err.Code, s3Err.Message = apiErr.Code, apiErr.Message
+		}
+		return s3Err
+	}
+
+	return handle(resp)
+}
+
+// objectURL returns the endpoint and the escaped path of the object, in the
+// bucket's subdomain on AWS and path style elsewhere
+func (c *Client) objectURL(loc Location) (string, string) {
+	key := "/" + sigv4.Escape(loc.Key, false)
+
+	if c.Endpoint != "" {
+		return strings.TrimSuffix(c.Endpoint, "/"), "/" + sigv4.Escape(loc.Bucket, true) + key
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", loc.Bucket, c.region()), key
+}
+
+func (c *Client) region() string {
+	if c.Region == "" {
+		return DefaultRegion
+	}
+	return c.Region
+}
@@ -0,0 +1,137 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseURL(t *testing.T) {
+	loc, err := ParseURL("s3://backups/volumes/snap 1.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Bucket != "backups" || loc.Key != "volumes/snap 1.tar" {
+		t.Errorf("got %+v", loc)
+	}
+
+	for _, bad := range []string{"https://backups/key", "s3:///key", "s3://backups", "s3://backups/dir/"} {
+		if _, err := ParseURL(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestMultipartUpload(t *testing.T) {
+	uploaded := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.EscapedPath() != "/bucket/dir/snap%201" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+
+		query := r.URL.Query()
+		_, initiate := query["uploads"]
+		switch {
+		case r.Method == http.MethodPost && initiate:
+			fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut:
+			if r.Header.Get("Content-MD5") == "" {
+				t.Error("part uploaded without Content-MD5")
+			}
+			body, _ := io.ReadAll(r.Body)
+			uploaded[query.Get("partNumber")] = string(body)
+			w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+		case r.Method == http.MethodGet && query.Get("part-number-marker") == "":
+			fmt.Fprint(w, `<ListPartsResult><Part><PartNumber>1</PartNumber><ETag>"etag-1"</ETag><Size>5</Size></Part>`+
+				`<IsTruncated>true</IsTruncated><NextPartNumberMarker>1</NextPartNumberMarker></ListPartsResult>`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `<ListPartsResult><Part><PartNumber>2</PartNumber><ETag>"etag-2"</ETag><Size>5</Size></Part>`+
+				`<IsTruncated>false</IsTruncated></ListPartsResult>`)
+		case r.Method == http.MethodPost && query.Get("uploadId") == "upload-1":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `<Part><PartNumber>2</PartNumber><ETag>&#34;etag-2&#34;</ETag></Part>`) {
+				t.Errorf("unexpected completion %s", body)
+			}
+			fmt.Fprint(w, `<CompleteMultipartUploadResult></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchUpload</Code><Message>The specified upload does not exist.</Message></Error>`)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{AccessKeyID: "key", SecretAccessKey: "secret", Endpoint: server.URL}
+	ctx := context.Background()
+	loc := Location{Bucket: "bucket", Key: "dir/snap 1"}
+
+	uploadID, err := c.CreateMultipartUpload(ctx, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploadID != "upload-1" {
+		t.Fatalf("got upload ID %q", uploadID)
+	}
+
+	for i, data := range []string{"hello", "world"} {
+		part, err := c.UploadPart(ctx, loc, uploadID, i+1, []byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.ETag != fmt.Sprintf(`"etag-%d"`, i+1) || part.Size != 5 {
+			t.Errorf("got part %+v", part)
+		}
+	}
+	if uploaded["1"] != "hello" || uploaded["2"] != "world" {
+		t.Errorf("got parts %v", uploaded)
+	}
+
+	parts, err := c.ListParts(ctx, loc, uploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || parts[1].Number != 2 {
+		t.Fatalf("got parts %+v", parts)
+	}
+
+	if err := c.CompleteMultipartUpload(ctx, loc, uploadID, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.AbortMultipartUpload(ctx, loc, uploadID)
+	if !IsNoSuchUpload(err) {
+		t.Errorf("expected NoSuchUpload, got %v", err)
+	}
+}
+
+func TestPutObject(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/bucket/logs/app/2021/01/02/03.ndjson.gz" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		body, _ := io.ReadAll(r.Body)
+		got = string(body)
+	}))
+	defer server.Close()
+
+	c := &Client{AccessKeyID: "key", SecretAccessKey: "secret", Endpoint: server.URL}
+	loc := Location{Bucket: "bucket", Key: "logs/app/2021/01/02/03.ndjson.gz"}
+	if err := c.PutObject(context.Background(), loc, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if got != "hello" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,284 @@
+// Package schema validates documents against the subset of JSON Schema used
+// by the schemas bundled with flyctl: type, properties, required,
+// additionalProperties, items, enum, minimum and maximum. It also finds the
+// settings a schema doesn't describe, to warn about.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is a parsed JSON schema
+type Schema struct {
+	Type                 types              `json:"type"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []interface{}      `json:"enum"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+// types is a schema's type, which may be a single type or a list
+type types []string
+
+func (t *types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = types{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// additional is additionalProperties, either a boolean or a schema
+type additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// Parse reads a JSON schema
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Validate checks doc, any value that can be encoded as JSON, against s and
+// returns a description of each problem found
+func (s *Schema) Validate(doc interface{}) ([]string, error) {
+	// round trip through JSON so every number is a float64 and every object
+	// a map[string]interface{}, whatever doc was decoded from
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	problems := []string{}
+	s.validate("", normalized, &problems)
+	return problems, nil
+}
+
+func (s *Schema) validate(path string, v interface{}, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		where := path
+		if where == "" {
+			where = "(root)"
+		}
+		*problems = append(*problems, where+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		report("expected %s, got %s", strings.Join(s.Type, " or "), typeOf(v))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		report("must be one of %s", formatEnum(s.Enum))
+	}
+
+	switch v := v.(type) {
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			report("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			report("must be at most %v", *s.Maximum)
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				report("%s is required", name)
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			child := join(path, k)
+			if prop, ok := s.Properties[k]; ok {
+				prop.validate(child, v[k], problems)
+				continue
+			}
+			if s.AdditionalProperties == nil {
+				continue
+			}
+			if !s.AdditionalProperties.Allowed {
+				report("unknown setting %s", k)
+				continue
+			}
+			if s.AdditionalProperties.Schema != nil {
+				s.AdditionalProperties.Schema.validate(child, v[k], problems)
+			}
+		}
+	}
+}
+
+// Unknown returns the path of each setting in doc that s doesn't describe:
+// keys of objects that list their properties without saying whether others
+// are allowed. They're valid, but often typos, so worth a warning.
+func (s *Schema) Unknown(doc interface{}) ([]string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	unknown := []string{}
+	s.unknown("", normalized, &unknown)
+	return unknown, nil
+}
+
+func (s *Schema) unknown(path string, v interface{}, unknown *[]string) {
+	switch v := v.(type) {
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.unknown(fmt.Sprintf("%s[%d]", path, i), item, unknown)
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			child := join(path, k)
+			switch {
+			case s.Properties[k] != nil:
+				s.Properties[k].unknown(child, v[k], unknown)
+			case s.AdditionalProperties != nil:
+				if s.AdditionalProperties.Schema != nil {
+					s.AdditionalProperties.Schema.unknown(child, v[k], unknown)
+				}
+			case len(s.Properties) > 0:
+				*unknown = append(*unknown, child)
+			}
+		}
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func (t types) matches(v interface{}) bool {
+	for _, name := range t {
+		switch name {
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := v.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "null":
+			if v == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprintf("%v", e)
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string"},
+		"port": {"type": ["integer", "string"], "minimum": 1},
+		"mode": {"type": "string", "enum": ["a", "b"]},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"env": {"type": "object", "additionalProperties": {"type": "string"}},
+		"strict": {
+			"type": "object",
+			"properties": {"id": {"type": "integer"}},
+			"required": ["id"],
+			"additionalProperties": false
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := map[string]interface{}{
+		"name":   "app",
+		"port":   int64(0),
+		"mode":   "c",
+		"tags":   []string{"x", "y"},
+		"env":    map[string]interface{}{"A": "1", "B": 2},
+		"strict": map[string]interface{}{"typo": true},
+		"extra":  1.5,
+	}
+
+	problems, err := s.Validate(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"env.B: expected string, got integer",
+		"mode: must be one of a, b",
+		"port: must be at least 1",
+		"strict: id is required",
+		"strict: unknown setting typo",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Validate() = %q, want %q", problems, want)
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := map[string]interface{}{
+		"name":   "app",
+		"env":    map[string]interface{}{"ANYTHING": "1"},
+		"strict": map[string]interface{}{"id": 1, "typo": true},
+		"extra":  1.5,
+	}
+
+	unknown, err := s.Unknown(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// strict disallows others, which Validate reports as a problem
+	want := []string{"extra"}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("Unknown() = %q, want %q", unknown, want)
+	}
+}
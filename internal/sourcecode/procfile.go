@@ -0,0 +1,83 @@
+package sourcecode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/superfly/flyctl/terminal"
+)
+
+// releaseProcess is the Procfile process type run once before each release,
+// rather than kept running
+const releaseProcess = "release"
+
+// Process - a process type from a Procfile
+type Process struct {
+	Name    string
+	Command string
+}
+
+var procfileLine = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// ParseProcfile parses the "name: command" lines of a Procfile, in order.
+// Blank lines and comments are skipped.
+func ParseProcfile(r io.Reader) ([]Process, error) {
+	processes := []Process{}
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		m := procfileLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("Procfile line %d: expected \"name: command\"", n)
+		}
+		if seen[m[1]] {
+			return nil, fmt.Errorf("Procfile line %d: process %q is defined twice", n, m[1])
+		}
+		seen[m[1]] = true
+
+		processes = append(processes, Process{Name: m[1], Command: strings.TrimSpace(m[2])})
+	}
+
+	return processes, scanner.Err()
+}
+
+// configureProcfile - adds the processes of the Procfile in sourceDir, if
+// there is one, to si. The release process becomes the release command. A
+// malformed Procfile is left out with a warning.
+func configureProcfile(sourceDir string, si *SourceInfo) error {
+	f, err := os.Open(filepath.Join(sourceDir, "Procfile"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	processes, err := ParseProcfile(f)
+	if err != nil {
+		terminal.Warnf("Ignoring the Procfile, %v\n", err)
+		return nil
+	}
+
+	for _, p := range processes {
+		if p.Name == releaseProcess {
+			si.ReleaseCommand = p.Command
+			continue
+		}
+		si.Processes = append(si.Processes, p)
+	}
+
+	return nil
+}
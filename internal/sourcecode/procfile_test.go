@@ -0,0 +1,64 @@
+package sourcecode
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProcfile(t *testing.T) {
+	processes, err := ParseProcfile(strings.NewReader(`
+# processes
+web: bundle exec puma -C config/puma.rb
+worker:   bundle exec sidekiq
+release: bin/rails db:migrate
+`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Process{
+		{Name: "web", Command: "bundle exec puma -C config/puma.rb"},
+		{Name: "worker", Command: "bundle exec sidekiq"},
+		{Name: "release", Command: "bin/rails db:migrate"},
+	}, processes)
+}
+
+func TestParseProcfileErrors(t *testing.T) {
+	_, err := ParseProcfile(strings.NewReader("web: ./server\nnot a process\n"))
+	assert.EqualError(t, err, `Procfile line 2: expected "name: command"`)
+
+	_, err = ParseProcfile(strings.NewReader("web: ./server\nweb: ./other\n"))
+	assert.EqualError(t, err, `Procfile line 2: process "web" is defined twice`)
+}
+
+func TestScanReadsProcfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Gemfile", "source 'https://rubygems.org'\n")
+	writeFile(t, dir, "Procfile", "web: puma\nworker: sidekiq\nrelease: rake db:migrate\n")
+
+	si, err := Scan(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ruby", si.Family)
+	assert.Equal(t, []Process{{Name: "web", Command: "puma"}, {Name: "worker", Command: "sidekiq"}}, si.Processes)
+	assert.Equal(t, "rake db:migrate", si.ReleaseCommand)
+}
+
+func TestScanIgnoresMalformedProcfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Gemfile", "source 'https://rubygems.org'\n")
+	writeFile(t, dir, "Procfile", "web: puma\nnot a process\n")
+
+	si, err := Scan(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ruby", si.Family)
+	assert.Empty(t, si.Processes)
+	assert.Empty(t, si.ReleaseCommand)
+}
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
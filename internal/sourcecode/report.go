@@ -0,0 +1,60 @@
+package sourcecode
+
+import "sort"
+
+// Report is everything a scan of source code detected, and what launch
+// would do with it, for review before launching
+type Report struct {
+	Dir            string         `json:"dir"`
+	Detected       bool           `json:"detected"`
+	Family         string         `json:"family,omitempty"`
+	DockerfilePath string         `json:"dockerfilePath,omitempty"`
+	Builder        string         `json:"builder,omitempty"`
+	Buildpacks     []string       `json:"buildpacks"`
+	Port           int            `json:"port,omitempty"`
+	Secrets        []ReportSecret `json:"secrets"`
+	Processes      []Process      `json:"processes"`
+	ReleaseCommand string         `json:"releaseCommand,omitempty"`
+	LiteFS         bool           `json:"litefs"`
+	// Files are the files launch would generate
+	Files []string `json:"files"`
+}
+
+// ReportSecret is a secret the app is expected to need, which launch asks
+// for
+type ReportSecret struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// NewReport - the report of si, as scanned from sourceDir. si is nil when
+// nothing was detected.
+func NewReport(sourceDir string, si *SourceInfo) *Report {
+	r := &Report{
+		Dir:        sourceDir,
+		Buildpacks: []string{},
+		Secrets:    []ReportSecret{},
+		Processes:  []Process{},
+		Files:      []string{"fly.toml"},
+	}
+	if si == nil {
+		return r
+	}
+
+	r.Detected = true
+	r.Family = si.Family
+	r.DockerfilePath = si.DockerfilePath
+	r.Builder = si.Builder
+	r.Port = si.Port
+	r.ReleaseCommand = si.ReleaseCommand
+	r.LiteFS = si.LiteFS
+	r.Buildpacks = append(r.Buildpacks, si.Buildpacks...)
+	r.Processes = append(r.Processes, si.Processes...)
+
+	for name, description := range si.Secrets {
+		r.Secrets = append(r.Secrets, ReportSecret{Name: name, Description: description})
+	}
+	sort.Slice(r.Secrets, func(i, j int) bool { return r.Secrets[i].Name < r.Secrets[j].Name })
+
+	return r
+}
@@ -1,18 +1,34 @@
 package sourcecode
 
 import (
+	"bufio"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/superfly/flyctl/helpers"
 )
 
+// buildpackPort is the port apps built with buildpacks are told to listen
+// on, with the PORT environment variable
+const buildpackPort = 8080
+
 type SourceInfo struct {
 	Family         string
 	DockerfilePath string
 	Builder        string
 	Buildpacks     []string
 	Secrets        map[string]string
+	// Port is the port the app listens on, zero when it's unknown
+	Port int
+	// Processes are the long running process types of a Procfile, and
+	// ReleaseCommand its release process
+	Processes      []Process
+	ReleaseCommand string
+	// LiteFS is set when the app replicates SQLite with LiteFS, which needs
+	// Consul to elect a primary
+	LiteFS bool
 }
 
 func Scan(sourceDir string) (*SourceInfo, error) {
@@ -30,6 +46,10 @@ func Scan(sourceDir string) (*SourceInfo, error) {
 			return nil, err
 		}
 		if si != nil {
+			if err := configureProcfile(sourceDir, si); err != nil {
+				return nil, err
+			}
+			si.LiteFS = checksPass(sourceDir, fileExists(liteFSConfigs...))
 			return si, nil
 		}
 	}
@@ -41,6 +61,9 @@ func SuggestAppName(sourceDir string) string {
 	return filepath.Base(sourceDir)
 }
 
+// liteFSConfigs are where LiteFS configs are kept in a source tree
+var liteFSConfigs = []string{"litefs.yml", "litefs.yaml", "etc/litefs.yml"}
+
 type sourceScanner func(sourceDir string) (*SourceInfo, error)
 
 func fileExists(filenames ...string) checkFn {
@@ -79,6 +102,12 @@ func configureDockerfile(sourceDir string) (*SourceInfo, error) {
 		Family:         "Dockerfile",
 	}
 
+	port, err := dockerfileExposedPort(s.DockerfilePath)
+	if err != nil {
+		return nil, err
+	}
+	s.Port = port
+
 	return s, nil
 }
 
@@ -90,6 +119,7 @@ func configureRuby(sourceDir string) (*SourceInfo, error) {
 	s := &SourceInfo{
 		Builder: "heroku/buildpacks:20",
 		Family:  "Ruby",
+		Port:    buildpackPort,
 	}
 
 	return s, nil
@@ -104,6 +134,7 @@ func configureGo(sourceDir string) (*SourceInfo, error) {
 		Builder:    "paketobuildpacks/builder:base",
 		Buildpacks: []string{"gcr.io/paketo-buildpacks/go"},
 		Family:     "Go",
+		Port:       buildpackPort,
 	}
 
 	return s, nil
@@ -117,6 +148,7 @@ func configureNode(sourceDir string) (*SourceInfo, error) {
 	s := &SourceInfo{
 		Builder: "heroku/buildpacks:20",
 		Family:  "NodeJS",
+		Port:    buildpackPort,
 	}
 
 	return s, nil
@@ -131,6 +163,7 @@ func configureElixir(sourceDir string) (*SourceInfo, error) {
 		Builder:    "heroku/buildpacks:18",
 		Buildpacks: []string{"https://cnb-shim.herokuapp.com/v1/hashnuke/elixir"},
 		Family:     "Elixir",
+		Port:       buildpackPort,
 		Secrets: map[string]string{
 			"SECRET_KEY_BASE": "The input secret for the application key generator. Use something long and random.",
 		},
@@ -138,3 +171,28 @@ func configureElixir(sourceDir string) (*SourceInfo, error) {
 
 	return s, nil
 }
+
+// dockerfileExposedPort - the first port a Dockerfile EXPOSEs, zero when it
+// doesn't or it's given by a variable
+func dockerfileExposedPort(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "EXPOSE") {
+			continue
+		}
+		port, err := strconv.Atoi(strings.SplitN(fields[1], "/", 2)[0])
+		if err != nil {
+			return 0, nil
+		}
+		return port, nil
+	}
+
+	return 0, scanner.Err()
+}
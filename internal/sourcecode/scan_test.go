@@ -0,0 +1,63 @@
+package sourcecode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanDockerfilePort(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "Dockerfile", "FROM nginx\nexpose 80/tcp 443\nCMD [\"nginx\"]\n")
+
+	si, err := Scan(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Dockerfile", si.Family)
+	assert.Equal(t, 80, si.Port)
+
+	writeFile(t, dir, "Dockerfile", "FROM nginx\nEXPOSE $PORT\n")
+	si, err = Scan(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, si.Port)
+}
+
+func TestNewReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "mix.exs", "defmodule App.MixProject do\nend\n")
+	writeFile(t, dir, "Procfile", "web: mix phx.server\nrelease: mix ecto.migrate\n")
+
+	si, err := Scan(dir)
+	assert.NoError(t, err)
+
+	r := NewReport(dir, si)
+	assert.True(t, r.Detected)
+	assert.Equal(t, "Elixir", r.Family)
+	assert.Equal(t, 8080, r.Port)
+	assert.Equal(t, []string{"https://cnb-shim.herokuapp.com/v1/hashnuke/elixir"}, r.Buildpacks)
+	assert.Equal(t, []ReportSecret{{Name: "SECRET_KEY_BASE", Description: si.Secrets["SECRET_KEY_BASE"]}}, r.Secrets)
+	assert.Equal(t, []Process{{Name: "web", Command: "mix phx.server"}}, r.Processes)
+	assert.Equal(t, "mix ecto.migrate", r.ReleaseCommand)
+	assert.Equal(t, []string{"fly.toml"}, r.Files)
+
+	empty := NewReport(filepath.Join(dir, "empty"), nil)
+	assert.False(t, empty.Detected)
+	assert.Equal(t, []Process{}, empty.Processes)
+}
+
+func TestScanLiteFS(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module app\n")
+
+	si, err := Scan(dir)
+	assert.NoError(t, err)
+	assert.False(t, si.LiteFS)
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "etc"), 0755))
+	writeFile(t, dir, "etc/litefs.yml", "lease:\n  type: consul\n")
+	si, err = Scan(dir)
+	assert.NoError(t, err)
+	assert.True(t, si.LiteFS)
+	assert.True(t, NewReport(dir, si).LiteFS)
+}
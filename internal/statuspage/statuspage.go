@@ -0,0 +1,95 @@
+// Package statuspage reads the platform's current status and incidents from
+// its public status page API.
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultURL is the status page's summary endpoint
+const DefaultURL = "https://status.fly.io/api/v2/summary.json"
+
+// PageURL is the status page for people
+const PageURL = "https://status.fly.io/"
+
+// Summary is the platform's overall status, its components and the incidents
+// that haven't been resolved
+type Summary struct {
+	Status struct {
+		// Indicator is one of none, minor, major or critical
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Components []Component `json:"components"`
+	Incidents  []Incident  `json:"incidents"`
+}
+
+// Component is a part of the platform, e.g. a region or the API
+type Component struct {
+	Name string `json:"name"`
+	// Status is one of operational, degraded_performance, partial_outage or
+	// major_outage
+	Status string `json:"status"`
+	Group  bool   `json:"group"`
+}
+
+// Operational reports whether the component is working normally
+func (c Component) Operational() bool {
+	return c.Status == "operational"
+}
+
+// Incident is an unresolved incident
+type Incident struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Impact    string    `json:"impact"`
+	Shortlink string    `json:"shortlink"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Degraded returns the components that aren't operational
+func (s *Summary) Degraded() []Component {
+	out := []Component{}
+	for _, c := range s.Components {
+		if !c.Group && !c.Operational() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Fetch reads the summary from url, DefaultURL when it's empty
+func Fetch(ctx context.Context, url string) (*Summary, error) {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not reach the status page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status page returned %s", resp.Status)
+	}
+
+	var summary Summary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("could not read the status page: %w", err)
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,50 @@
+package statuspage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const summaryJSON = `{
+  "status": {"indicator": "minor", "description": "Minor Service Outage"},
+  "components": [
+    {"name": "Regions", "status": "partial_outage", "group": true},
+    {"name": "Amsterdam, Netherlands (AMS)", "status": "partial_outage", "group": false},
+    {"name": "API", "status": "operational", "group": false}
+  ],
+  "incidents": [
+    {"name": "Elevated deploy errors in AMS", "status": "investigating", "impact": "minor", "shortlink": "https://stspg.io/abc"}
+  ]
+}`
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(summaryJSON))
+	}))
+	defer server.Close()
+
+	summary, err := Fetch(context.Background(), server.URL)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "minor", summary.Status.Indicator)
+	assert.Len(t, summary.Incidents, 1)
+	assert.Equal(t, "investigating", summary.Incidents[0].Status)
+
+	degraded := summary.Degraded()
+	assert.Len(t, degraded, 1)
+	assert.Equal(t, "Amsterdam, Netherlands (AMS)", degraded[0].Name)
+}
+
+func TestFetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := Fetch(context.Background(), server.URL)
+	assert.EqualError(t, err, "status page returned 502 Bad Gateway")
+}
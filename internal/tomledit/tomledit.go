@@ -0,0 +1,409 @@
+// Package tomledit changes individual keys of a TOML document in place,
+// leaving its comments, ordering and formatting alone.
+package tomledit
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Set sets the key at path, e.g. "env.LOG_LEVEL" or "services[0].internal_port",
+// to value. Values that aren't valid TOML, and values replacing a string, are
+// written as strings. Missing tables are added at the end of the document.
+func Set(doc []byte, path string, value string) ([]byte, error) {
+	tablePath, key, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(doc)
+	sections, err := scan(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := resolveTable(sections, tablePath)
+	if err != nil {
+		return nil, err
+	}
+
+	section := sections.find(table)
+	if section == nil {
+		// tables of tables that don't exist yet can only be plain tables
+		if strings.Contains(table, "[") {
+			return nil, fmt.Errorf("%s not found", table)
+		}
+		lines = appendTable(lines, table, key, formatValue(value, ""))
+		return validate(joinLines(lines))
+	}
+
+	if kv := section.key(key); kv != nil {
+		literal := formatValue(value, kv.value)
+		replaced := kv.indent + kv.name + kv.separator + literal + kv.comment
+		lines = append(lines[:kv.start], append([]string{replaced}, lines[kv.end+1:]...)...)
+		return validate(joinLines(lines))
+	}
+
+	indent := ""
+	if section.header >= 0 {
+		indent = "  "
+	}
+	insertAt := section.header + 1
+	if len(section.keys) > 0 {
+		last := section.keys[len(section.keys)-1]
+		indent = last.indent
+		insertAt = last.end + 1
+	}
+
+	line := indent + quoteKey(key) + " = " + formatValue(value, "")
+	lines = append(lines[:insertAt], append([]string{line}, lines[insertAt:]...)...)
+
+	return validate(joinLines(lines))
+}
+
+// Unset removes the key at path. Removing a key that isn't set is an error.
+func Unset(doc []byte, path string) ([]byte, error) {
+	tablePath, key, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(doc)
+	sections, err := scan(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := resolveTable(sections, tablePath)
+	if err != nil {
+		return nil, err
+	}
+
+	section := sections.find(table)
+	if section == nil {
+		return nil, fmt.Errorf("%s is not set", path)
+	}
+
+	kv := section.key(key)
+	if kv == nil {
+		return nil, fmt.Errorf("%s is not set", path)
+	}
+
+	lines = append(lines[:kv.start], lines[kv.end+1:]...)
+
+	return validate(joinLines(lines))
+}
+
+// segment is one part of a path, with the index when it names an entry in
+// an array of tables
+type segment struct {
+	name  string
+	index int // -1 when not given
+}
+
+var segmentPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)(?:\[(\d+)\])?$`)
+
+func parsePath(path string) ([]segment, string, error) {
+	parts := strings.Split(path, ".")
+
+	var segments []segment
+	for _, part := range parts[:len(parts)-1] {
+		m := segmentPattern.FindStringSubmatch(part)
+		if m == nil {
+			return nil, "", fmt.Errorf("invalid key %q", path)
+		}
+		s := segment{name: m[1], index: -1}
+		if m[2] != "" {
+			s.index, _ = strconv.Atoi(m[2])
+		}
+		segments = append(segments, s)
+	}
+
+	key := parts[len(parts)-1]
+	if !keyPattern.MatchString(key) {
+		return nil, "", fmt.Errorf("invalid key %q", path)
+	}
+
+	return segments, key, nil
+}
+
+// keyValue is a key's line, or lines when its value spans several
+type keyValue struct {
+	name      string
+	indent    string
+	separator string
+	value     string
+	comment   string
+	start     int
+	end       int
+}
+
+// section is a table's header and keys. The root table has header -1.
+type section struct {
+	id     string
+	header int
+	keys   []*keyValue
+}
+
+func (s *section) key(name string) *keyValue {
+	for _, kv := range s.keys {
+		if unquoteKey(kv.name) == name {
+			return kv
+		}
+	}
+	return nil
+}
+
+type sections struct {
+	list []*section
+	// arrays counts the entries of each array of tables, by id
+	arrays map[string]int
+}
+
+func (s *sections) find(id string) *section {
+	for _, sec := range s.list {
+		if sec.id == id {
+			return sec
+		}
+	}
+	return nil
+}
+
+var (
+	headerPattern   = regexp.MustCompile(`^\s*(\[\[?)\s*([^\]]+?)\s*\]\]?\s*(#.*)?$`)
+	keyValuePattern = regexp.MustCompile(`^(\s*)("[^"]*"|'[^']*'|[A-Za-z0-9_-]+)(\s*=\s*)(.*)$`)
+	keyPattern      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// scan finds the tables and top level keys of each table in lines
+func scan(lines []string) (*sections, error) {
+	root := &section{id: "", header: -1}
+	s := &sections{list: []*section{root}, arrays: map[string]int{}}
+	current := root
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := headerPattern.FindStringSubmatch(line); m != nil && !strings.Contains(line, "=") {
+			id := s.headerID(strings.Split(m[2], "."), m[1] == "[[")
+			current = &section{id: id, header: i}
+			s.list = append(s.list, current)
+			continue
+		}
+
+		m := keyValuePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		value, comment, end, err := valueExtent(lines, i, m[4])
+		if err != nil {
+			return nil, err
+		}
+		current.keys = append(current.keys, &keyValue{
+			name:      m[2],
+			indent:    m[1],
+			separator: m[3],
+			value:     value,
+			comment:   comment,
+			start:     i,
+			end:       end,
+		})
+		i = end
+	}
+
+	return s, nil
+}
+
+// headerID names a table header's table, with the index of each array of
+// tables it's in, e.g. [[services.ports]] in the second service is
+// "services[1].ports[n]"
+func (s *sections) headerID(names []string, isArray bool) string {
+	var id string
+	for i, name := range names {
+		name = unquoteKey(strings.TrimSpace(name))
+		if id != "" {
+			id += "."
+		}
+		id += name
+
+		if i == len(names)-1 && isArray {
+			n := s.arrays[id]
+			s.arrays[id] = n + 1
+			return fmt.Sprintf("%s[%d]", id, n)
+		}
+		if n, ok := s.arrays[id]; ok {
+			id = fmt.Sprintf("%s[%d]", id, n-1)
+		}
+	}
+	return id
+}
+
+// resolveTable names the table at path, picking the only entry of an array
+// of tables when no index is given
+func resolveTable(s *sections, path []segment) (string, error) {
+	var id string
+	for _, seg := range path {
+		if id != "" {
+			id += "."
+		}
+		id += seg.name
+
+		count, isArray := s.arrays[id]
+		switch {
+		case isArray && seg.index >= 0:
+			if seg.index >= count {
+				return "", fmt.Errorf("%s has %d entries", id, count)
+			}
+			id = fmt.Sprintf("%s[%d]", id, seg.index)
+		case isArray && count == 1:
+			id += "[0]"
+		case isArray:
+			return "", fmt.Errorf("%s has %d entries, choose one with %s[0] to %s[%d]", id, count, seg.name, seg.name, count-1)
+		case seg.index >= 0:
+			return "", fmt.Errorf("%s is not a list of tables", id)
+		}
+	}
+	return id, nil
+}
+
+// valueExtent finds the end of the value starting on line i, following
+// multi-line strings and arrays, and splits off a trailing comment
+func valueExtent(lines []string, i int, rest string) (string, string, int, error) {
+	trimmed := strings.TrimSpace(rest)
+
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(trimmed, quote) {
+			if strings.Count(trimmed, quote) >= 2 {
+				return trimmed, "", i, nil
+			}
+			for end := i + 1; end < len(lines); end++ {
+				if strings.Contains(lines[end], quote) {
+					return trimmed, "", end, nil
+				}
+			}
+			return "", "", 0, fmt.Errorf("line %d: unterminated multi-line string", i+1)
+		}
+	}
+
+	value, comment := splitComment(rest)
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		depth := bracketDepth(value)
+		end := i
+		for depth > 0 && end+1 < len(lines) {
+			end++
+			v, _ := splitComment(lines[end])
+			depth += bracketDepth(v)
+		}
+		if depth > 0 {
+			return "", "", 0, fmt.Errorf("line %d: unterminated array", i+1)
+		}
+		if end > i {
+			comment = ""
+		}
+		return strings.TrimSpace(value), comment, end, nil
+	}
+
+	return strings.TrimSpace(value), comment, i, nil
+}
+
+// splitComment splits a trailing comment, with the space before it, off a
+// line, ignoring # inside strings
+func splitComment(s string) (string, string) {
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			value := strings.TrimRight(s[:i], " \t")
+			return value, s[len(value):]
+		}
+	}
+	return s, ""
+}
+
+func bracketDepth(s string) int {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// formatValue turns value into a TOML literal. previous is the literal it
+// replaces, if any, so a string stays a string.
+func formatValue(value string, previous string) string {
+	isString := strings.HasPrefix(previous, `"`) || strings.HasPrefix(previous, `'`)
+
+	var probe map[string]interface{}
+	if _, err := toml.Decode("v = "+value, &probe); err == nil {
+		if _, literalIsString := probe["v"].(string); literalIsString || !isString {
+			return value
+		}
+	}
+
+	return strconv.Quote(value)
+}
+
+func quoteKey(key string) string {
+	if keyPattern.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+func appendTable(lines []string, table string, key string, literal string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return append(lines, "["+table+"]", "  "+quoteKey(key)+" = "+literal)
+}
+
+func splitLines(doc []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(doc), "\n"), "\n")
+}
+
+func joinLines(lines []string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// validate makes sure an edit left a valid document
+func validate(doc []byte) ([]byte, error) {
+	var data map[string]interface{}
+	if _, err := toml.Decode(string(bytes.TrimSpace(doc)), &data); err != nil {
+		return nil, fmt.Errorf("the change would leave an invalid config: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,209 @@
+package tomledit
+
+import (
+	"testing"
+)
+
+const doc = `# fly.toml for myapp
+app = "myapp"
+
+[env]
+  # how chatty to be
+  LOG_LEVEL = "info" # or debug
+  PORTS = [
+    "80",
+    "443",
+  ]
+
+[[services]]
+  internal_port = 8080
+  protocol = "tcp"
+
+  [[services.ports]]
+    handlers = ["http"]
+    port = 80
+`
+
+func TestSet(t *testing.T) {
+	cases := []struct {
+		name  string
+		path  string
+		value string
+		want  string
+	}{
+		{
+			name:  "replaces a value keeping its comment",
+			path:  "env.LOG_LEVEL",
+			value: "debug",
+			want: `# fly.toml for myapp
+app = "myapp"
+
+[env]
+  # how chatty to be
+  LOG_LEVEL = "debug" # or debug
+  PORTS = [
+    "80",
+    "443",
+  ]
+
+[[services]]
+  internal_port = 8080
+  protocol = "tcp"
+
+  [[services.ports]]
+    handlers = ["http"]
+    port = 80
+`,
+		},
+		{
+			name:  "replaces a multi-line value and adds a key after it",
+			path:  "env.REGION",
+			value: "lhr",
+			want: `# fly.toml for myapp
+app = "myapp"
+
+[env]
+  # how chatty to be
+  LOG_LEVEL = "info" # or debug
+  PORTS = [
+    "80",
+    "443",
+  ]
+  REGION = "lhr"
+
+[[services]]
+  internal_port = 8080
+  protocol = "tcp"
+
+  [[services.ports]]
+    handlers = ["http"]
+    port = 80
+`,
+		},
+		{
+			name:  "picks the only entry of an array of tables",
+			path:  "services.internal_port",
+			value: "3000",
+			want: `# fly.toml for myapp
+app = "myapp"
+
+[env]
+  # how chatty to be
+  LOG_LEVEL = "info" # or debug
+  PORTS = [
+    "80",
+    "443",
+  ]
+
+[[services]]
+  internal_port = 3000
+  protocol = "tcp"
+
+  [[services.ports]]
+    handlers = ["http"]
+    port = 80
+`,
+		},
+		{
+			name:  "adds a missing table",
+			path:  "deploy.strategy",
+			value: "rolling",
+			want: doc + `
+[deploy]
+  strategy = "rolling"
+`,
+		},
+		{
+			name:  "adds a top level key before the first table",
+			path:  "primary_region",
+			value: "lhr",
+			want: `# fly.toml for myapp
+app = "myapp"
+primary_region = "lhr"
+
+[env]
+  # how chatty to be
+  LOG_LEVEL = "info" # or debug
+  PORTS = [
+    "80",
+    "443",
+  ]
+
+[[services]]
+  internal_port = 8080
+  protocol = "tcp"
+
+  [[services.ports]]
+    handlers = ["http"]
+    port = 80
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Set([]byte(doc), tc.path, tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetKeepsStringsStrings(t *testing.T) {
+	got, err := Set([]byte("[env]\n  PORT = \"8080\"\n"), "env.PORT", "3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[env]\n  PORT = \"3000\"\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSetAmbiguousArray(t *testing.T) {
+	twoServices := "[[services]]\n  internal_port = 8080\n\n[[services]]\n  internal_port = 9090\n"
+
+	if _, err := Set([]byte(twoServices), "services.internal_port", "3000"); err == nil {
+		t.Fatal("expected an error choosing between services")
+	}
+
+	got, err := Set([]byte(twoServices), "services[1].internal_port", "3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[[services]]\n  internal_port = 8080\n\n[[services]]\n  internal_port = 3000\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestUnset(t *testing.T) {
+	got, err := Unset([]byte(doc), "env.PORTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# fly.toml for myapp
+app = "myapp"
+
+[env]
+  # how chatty to be
+  LOG_LEVEL = "info" # or debug
+
+[[services]]
+  internal_port = 8080
+  protocol = "tcp"
+
+  [[services.ports]]
+    handlers = ["http"]
+    port = 80
+`
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := Unset([]byte(doc), "env.MISSING"); err == nil {
+		t.Error("expected an error unsetting a missing key")
+	}
+}
@@ -42,6 +42,18 @@ func InitState(configPath string, channel string) error {
 	return saveState(configPath, state)
 }
 
+// CachedRelease returns the newest release recorded by a previous update check
+// if it is newer than currentVersion. It never touches the network.
+func CachedRelease(configPath string, currentVersion string) *Release {
+	state, _ := loadState(configPath)
+
+	if state.LatestRelease != nil && isGreaterThan(currentVersion, state.LatestRelease.Version) {
+		return state.LatestRelease
+	}
+
+	return nil
+}
+
 func CheckForUpdate(ctx context.Context, configPath string, currentVersion string) (*Release, error) {
 	state, _ := loadState(configPath)
 	if state.Channel == "" {
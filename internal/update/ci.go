@@ -0,0 +1,41 @@
+package update
+
+import "os"
+
+// ciEnvVars are set by common CI providers. Based on
+// https://github.com/watson/ci-info/blob/HEAD/vendors.json
+var ciEnvVars = []string{
+	"BUILD_NUMBER",           // Jenkins, TeamCity
+	"RUN_ID",                 // TaskCluster, dsari
+	"CONTINUOUS_INTEGRATION", // Travis CI, Cirrus CI
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"CIRCLECI",
+	"TRAVIS",
+	"BUILDKITE",
+	"DRONE",
+	"TF_BUILD", // Azure Pipelines
+	"TEAMCITY_VERSION",
+	"JENKINS_URL",
+	"CODEBUILD_BUILD_ID", // AWS CodeBuild
+	"BITBUCKET_BUILD_NUMBER",
+	"SEMAPHORE",
+	"APPVEYOR",
+	"NETLIFY",
+	"VERCEL",
+}
+
+// IsCI reports whether flyctl appears to be running inside a CI environment
+func IsCI() bool {
+	if ci := os.Getenv("CI"); ci != "" && ci != "false" && ci != "0" {
+		return true
+	}
+
+	for _, name := range ciEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+
+	return false
+}
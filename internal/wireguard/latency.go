@@ -0,0 +1,57 @@
+package wireguard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// GatewayLatency - the estimated round trip time to a region's gateway
+type GatewayLatency struct {
+	Region  api.Region
+	Latency time.Duration
+}
+
+// RankGatewayRegions returns the regions that have a gateway, nearest to
+// from first, with the round trip time estimated from their distance
+func RankGatewayRegions(from api.Region, regions []api.Region) []GatewayLatency {
+	latencies := []GatewayLatency{}
+	for _, region := range regions {
+		if !region.GatewayAvailable {
+			continue
+		}
+		latencies = append(latencies, GatewayLatency{Region: region, Latency: from.EstimateLatency(region)})
+	}
+
+	sort.SliceStable(latencies, func(i, j int) bool {
+		return latencies[i].Latency < latencies[j].Latency
+	})
+
+	return latencies
+}
+
+// FastestGatewayRegion returns the gateway region with the lowest latency
+// along with the ranking. API requests are routed through the edge region
+// nearest the user, so gateways are ranked by their distance from it. When
+// the API doesn't report that region it falls back to the nearest gateway
+// region the API picks, without a ranking.
+func FastestGatewayRegion(apiClient *api.Client) (*api.Region, []GatewayLatency, error) {
+	regions, requestRegion, err := apiClient.PlatformRegions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if requestRegion != nil {
+		if latencies := RankGatewayRegions(*requestRegion, regions); len(latencies) > 0 {
+			return &latencies[0].Region, latencies, nil
+		}
+	}
+
+	region, err := apiClient.ClosestWireguardGatewayRegion()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return region, nil, nil
+}
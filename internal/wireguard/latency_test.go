@@ -0,0 +1,39 @@
+package wireguard
+
+import (
+	"testing"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestRankGatewayRegions(t *testing.T) {
+	ord := api.Region{Code: "ord", Latitude: 41.98, Longitude: -87.90, GatewayAvailable: true}
+	iad := api.Region{Code: "iad", Latitude: 38.94, Longitude: -77.45, GatewayAvailable: true}
+	lhr := api.Region{Code: "lhr", Latitude: 51.47, Longitude: -0.45, GatewayAvailable: true}
+	nrt := api.Region{Code: "nrt", Latitude: 35.76, Longitude: 140.39, GatewayAvailable: true}
+	den := api.Region{Code: "den", Latitude: 39.86, Longitude: -104.67}
+
+	latencies := RankGatewayRegions(ord, []api.Region{nrt, lhr, den, iad, ord})
+
+	got := []string{}
+	for _, l := range latencies {
+		got = append(got, l.Region.Code)
+	}
+	want := []string{"ord", "iad", "lhr", "nrt"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	if latencies[0].Latency.Milliseconds() != 1 {
+		t.Errorf("expected the request region to be ~1ms, got %s", latencies[0].Latency)
+	}
+	// ~6,300km
+	if ms := latencies[2].Latency.Milliseconds(); ms < 55 || ms > 75 {
+		t.Errorf("expected ord to lhr to be ~63ms, got %dms", ms)
+	}
+}
@@ -149,6 +149,32 @@ func setWireGuardStateForOrg(orgSlug string, s *wg.WireGuardState) error {
 	return setWireGuardState(states)
 }
 
+// Relocate moves the named peer to regionCode, keeping its keys. flyctl's own
+// saved state is updated when it refers to the peer, and the new gateway is
+// returned so other configs can be pointed at it.
+func Relocate(apiClient *api.Client, org *api.Organization, name, regionCode string) (*api.CreatedWireGuardPeer, error) {
+	peer, err := apiClient.RelocateWireGuardPeer(org, name, regionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := getWireGuardStateForOrg(org.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if state != nil && state.Name == name {
+		state.Region = regionCode
+		state.Peer = *peer
+
+		if err := setWireGuardStateForOrg(org.Slug, state); err != nil {
+			return nil, err
+		}
+	}
+
+	return peer, nil
+}
+
 func PruneInvalidPeers(apiClient *api.Client) error {
 	state, err := GetWireGuardState()
 	if err != nil {
@@ -11,6 +11,8 @@ import (
 	"github.com/getsentry/sentry-go"
 	"github.com/hashicorp/go-multierror"
 	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/cmd"
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/flyname"
@@ -58,16 +60,22 @@ func main() {
 
 	flyctl.InitConfig()
 
-	updateChan := make(chan *update.Release)
-	go func() {
-		defer update.PostUpgradeCleanup()
-
-		rel, err := checkForUpdate(flyctl.Version)
-		if err != nil {
-			terminal.Debug("error checking for update:", err)
-		}
-		updateChan <- rel
-	}()
+	// the update check runs alongside the command so it never delays startup;
+	// any notice is shown once the command has finished. It starts once cobra
+	// has parsed the flags, so --no-update-check is known by then.
+	var updateChan chan *update.Release
+	cobra.OnInitialize(func() {
+		updateChan = make(chan *update.Release, 1)
+		go func() {
+			defer update.PostUpgradeCleanup()
+
+			rel, err := checkForUpdate(flyctl.Version)
+			if err != nil {
+				terminal.Debug("error checking for update:", err)
+			}
+			updateChan <- rel
+		}()
+	})
 
 	client := client.NewClient()
 
@@ -81,13 +89,12 @@ func main() {
 	// fmt.Println("resolved to", cmd.Use)
 	// checkErr(err)
 
-	update := <-updateChan
-	if update != nil {
-		fmt.Fprintln(os.Stderr, aurora.Yellow(fmt.Sprintf("Update available %s -> %s", flyctl.Version, update.Version)))
-		fmt.Fprintln(os.Stderr, aurora.Yellow(fmt.Sprintf("Run \"%s\" to upgrade", aurora.Bold(flyname.Name()+" version update"))))
-	}
+	args, err := cmd.ExpandAlias(root, os.Args[1:])
+	checkErr(err)
+	root.SetArgs(args)
 
-	_, err := root.ExecuteC()
+	_, err = root.ExecuteC()
+	showUpdateNotice(updateChan)
 	checkErr(err)
 }
 
@@ -96,11 +103,11 @@ func checkErr(err error) {
 		return
 	}
 
-	if !isCancelledError(err) {
+	if !isCancelledError(err) && err != cmd.ErrDeployFailed {
 		fmt.Println(aurora.Red("Error"), err)
 	}
 
-	safeExit()
+	safeExit(cmd.ExitCode(err))
 }
 
 func isCancelledError(err error) bool {
@@ -121,10 +128,42 @@ func isCancelledError(err error) bool {
 	return false
 }
 
-func safeExit() {
+func safeExit(code int) {
 	flyctl.BackgroundTaskWG.Wait()
 
-	os.Exit(1)
+	os.Exit(code)
+}
+
+// updateNoticeTimeout bounds how long a finished command waits on an update
+// check that is still in flight
+const updateNoticeTimeout = 500 * time.Millisecond
+
+func showUpdateNotice(updateChan <-chan *update.Release) {
+	if viper.GetBool(flyctl.ConfigNoUpdateCheck) || viper.GetBool(flyctl.ConfigQuietOutput) || isCompletionRequest() {
+		return
+	}
+
+	stateFilePath := filepath.Join(flyctl.ConfigDir(), "state.yml")
+
+	// fall back to whatever an earlier check recorded when the check is still
+	// running, or never started because no command ran, e.g. for --help
+	var rel *update.Release
+	if updateChan == nil {
+		rel = update.CachedRelease(stateFilePath, flyctl.Version)
+	} else {
+		select {
+		case rel = <-updateChan:
+		case <-time.After(updateNoticeTimeout):
+			rel = update.CachedRelease(stateFilePath, flyctl.Version)
+		}
+	}
+
+	if rel == nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, aurora.Yellow(fmt.Sprintf("Update available %s -> %s", flyctl.Version, rel.Version)))
+	fmt.Fprintln(os.Stderr, aurora.Yellow(fmt.Sprintf("Run \"%s\" to upgrade", aurora.Bold(flyname.Name()+" version update"))))
 }
 
 func checkForUpdate(currentVersion string) (*update.Release, error) {
@@ -133,7 +172,11 @@ func checkForUpdate(currentVersion string) (*update.Release, error) {
 	}
 
 	stateFilePath := filepath.Join(flyctl.ConfigDir(), "state.yml")
-	return update.CheckForUpdate(context.Background(), stateFilePath, currentVersion)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return update.CheckForUpdate(ctx, stateFilePath, currentVersion)
 }
 
 func shouldCheckForUpdate() bool {
@@ -142,23 +185,26 @@ func shouldCheckForUpdate() bool {
 		return true
 	}
 
-	if os.Getenv("FLY_NO_UPDATE_CHECK") != "" {
+	// completions run on every tab, and print nothing but candidates
+	if isCompletionRequest() {
+		return false
+	}
+
+	if os.Getenv("FLY_NO_UPDATE_CHECK") != "" || viper.GetBool(flyctl.ConfigNoUpdateCheck) {
 		return false
 	}
 	if os.Getenv("CODESPACES") != "" {
 		return false
 	}
 
-	if flyctl.Environment != "production" || isCI() || !cmdutil.IsTerminal(os.Stdout) || !cmdutil.IsTerminal(os.Stderr) {
+	if flyctl.Environment != "production" || update.IsCI() || !cmdutil.IsTerminal(os.Stdout) || !cmdutil.IsTerminal(os.Stderr) {
 		return false
 	}
 
 	return true
 }
 
-// based on https://github.com/watson/ci-info/blob/HEAD/index.js
-func isCI() bool {
-	return os.Getenv("CI") != "" || // GitHub Actions, Travis CI, CircleCI, Cirrus CI, GitLab CI, AppVeyor, CodeShip, dsari
-		os.Getenv("BUILD_NUMBER") != "" || // Jenkins, TeamCity
-		os.Getenv("RUN_ID") != "" // TaskCluster, dsari
+// isCompletionRequest reports whether the shell is asking for completions
+func isCompletionRequest() bool {
+	return len(os.Args) > 1 && (os.Args[1] == cobra.ShellCompRequestCmd || os.Args[1] == cobra.ShellCompNoDescRequestCmd)
 }
@@ -28,6 +28,10 @@ var (
 	ErrCantBind = errors.New("can't bind agent socket")
 )
 
+// Version is the version of the commands the agent understands. Bump it when
+// adding one, so clients restart agents started by an older flyctl.
+const Version = 2
+
 type Server struct {
 	listener      *net.UnixListener
 	ctx           context.Context
@@ -80,6 +84,8 @@ func (s *Server) handle(c net.Conn) {
 		"probe":     s.handleProbe,
 		"establish": s.handleEstablish,
 		"instances": s.handleInstances,
+		"resolve":   s.handleResolve,
+		"version":   s.handleVersion,
 	}
 
 	handler, ok := cmds[args[0]]
@@ -188,6 +194,12 @@ func (s *Server) handlePing(c net.Conn, args []string) error {
 	return writef(c, "pong %d", os.Getpid())
 }
 
+// handleVersion returns the version of the commands the agent understands.
+// Agents from before there was a version reply "bad command".
+func (s *Server) handleVersion(c net.Conn, args []string) error {
+	return writef(c, "ok %d", Version)
+}
+
 func findOrganization(client *api.Client, slug string) (*api.Organization, error) {
 	orgs, err := client.GetOrganizations(nil)
 	if err != nil {
@@ -359,6 +371,37 @@ func (s *Server) handleInstances(c net.Conn, args []string) error {
 	return writef(c, "ok %s", out.String())
 }
 
+// lookupHost resolves a .internal name to its 6PN addresses
+func lookupHost(tunnel *wg.Tunnel, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return tunnel.Resolver().LookupHost(ctx, host)
+}
+
+// handleResolve returns the addresses a name resolves to on the private
+// network.
+func (s *Server) handleResolve(c net.Conn, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("resolve: malformed resolve command: %v", args)
+	}
+
+	tunnel, err := s.tunnelFor(args[1])
+	if err != nil {
+		return fmt.Errorf("resolve: can't build tunnel: %s", err)
+	}
+
+	addrs, err := lookupHost(tunnel, args[2])
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", args[2], err)
+	}
+
+	out := &bytes.Buffer{}
+	json.NewEncoder(out).Encode(addrs)
+
+	return writef(c, "ok %s", out.String())
+}
+
 func (s *Server) handleConnect(c net.Conn, args []string) error {
 	log.Printf("incoming connect: %v", args)
 
@@ -514,17 +557,35 @@ func captureWireguardConnErr(err error, org string) {
 	)
 }
 
-/// Establish starts the daemon if necessary and returns a client
-func Establish(apiClient *api.Client) (*Client, error) {
+// ErrNotRunning is returned by Running when no agent answers
+var ErrNotRunning = errors.New("the Fly agent isn't running")
+
+// Running returns a client for the agent if it's already running, restarting
+// it when it's older than this flyctl
+func Running(apiClient *api.Client) (*Client, error) {
 	c, err := DefaultClient(apiClient)
-	if err == nil {
-		_, err := c.Ping()
-		if err == nil {
-			return c, nil
-		}
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+
+	if _, err := c.Ping(); err != nil {
+		return nil, ErrNotRunning
 	}
 
-	fmt.Println("command", os.Args[0])
+	if version, err := c.Version(); err != nil || version < Version {
+		terminal.Debugf("restarting the Fly agent, it's older than flyctl (%d < %d, %v)\n", version, Version, err)
+		c.Kill()
+		return StartDaemon(apiClient, os.Args[0])
+	}
+
+	return c, nil
+}
+
+/// Establish starts the daemon if necessary and returns a client
+func Establish(apiClient *api.Client) (*Client, error) {
+	if c, err := Running(apiClient); err == nil {
+		return c, nil
+	}
 
 	return StartDaemon(apiClient, os.Args[0])
 }
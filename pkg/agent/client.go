@@ -106,6 +106,35 @@ func (c *Client) Ping() (int, error) {
 	return pid, err
 }
 
+// Version returns the version of the commands the agent understands
+func (c *Client) Version() (int, error) {
+	var version int
+
+	err := c.withConnection(func(conn net.Conn) error {
+		writef(conn, "version")
+
+		conn.SetReadDeadline(time.Now().Add(defaultTimeout))
+
+		reply, err := read(conn)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.HasPrefix(reply, []byte("ok ")) {
+			return fmt.Errorf("version failed: %s", string(reply))
+		}
+
+		version, err = strconv.Atoi(string(reply[3:]))
+		if err != nil {
+			return fmt.Errorf("version failed: malformed response: %w", err)
+		}
+
+		return nil
+	})
+
+	return version, err
+}
+
 func (c *Client) Establish(slug string) error {
 	return c.withConnection(func(conn net.Conn) error {
 		writef(conn, "establish %s", slug)
@@ -173,6 +202,32 @@ func (c *Client) Instances(o *api.Organization, app string) (*Instances, error)
 	return instances, err
 }
 
+// Resolve returns the private network addresses host resolves to
+func (c *Client) Resolve(o *api.Organization, host string) ([]string, error) {
+	var addrs []string
+
+	err := c.withConnection(func(conn net.Conn) error {
+		writef(conn, "resolve %s %s", o.Slug, host)
+
+		reply, err := read(conn)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.HasPrefix(reply, []byte("ok ")) {
+			return fmt.Errorf("resolve failed: %s", string(reply))
+		}
+
+		if err := json.Unmarshal(reply[3:], &addrs); err != nil {
+			return fmt.Errorf("resolve failed: malformed response: %s", err)
+		}
+
+		return nil
+	})
+
+	return addrs, err
+}
+
 type Dialer struct {
 	Org     *api.Organization
 	Timeout time.Duration
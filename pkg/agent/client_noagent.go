@@ -53,6 +53,10 @@ func (c *Client) Ping() (int, error) {
 	return 0, nil
 }
 
+func (c *Client) Version() (int, error) {
+	return Version, nil
+}
+
 func (c *Client) Establish(slug string) error {
 	if c.Client == nil {
 		return fmt.Errorf("no client set for stub agent")
@@ -110,6 +114,16 @@ func (c *Client) Instances(o *api.Organization, app string) (*Instances, error)
 	return ret, nil
 }
 
+// Resolve returns the private network addresses host resolves to
+func (c *Client) Resolve(o *api.Organization, host string) ([]string, error) {
+	tunnel, err := c.tunnelFor(o.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("can't build tunnel: %s", err)
+	}
+
+	return lookupHost(tunnel, host)
+}
+
 type Dialer struct {
 	Org    *api.Organization
 	tunnel *wg.Tunnel
@@ -0,0 +1,182 @@
+package flyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/deployment"
+)
+
+// ErrDeployFailed is returned by Deploy when the deployment doesn't become
+// healthy. The DeployResult describes what happened.
+var ErrDeployFailed = errors.New("flyapi: deployment failed")
+
+// ErrReleaseCommandFailed is returned by Deploy when the release command
+// fails, which stops the deployment
+var ErrReleaseCommandFailed = errors.New("flyapi: release command failed")
+
+// ConfigError is returned by Deploy when the app configuration is invalid
+type ConfigError struct {
+	Errors []string
+}
+
+func (e *ConfigError) Error() string {
+	return "flyapi: invalid app configuration: " + strings.Join(e.Errors, "; ")
+}
+
+// DeployOptions configures Deploy. Images are deployed as already built, in
+// the Fly registry or a public registry.
+type DeployOptions struct {
+	// Image is the image to deploy, e.g. registry.fly.io/myapp:deployment-123
+	Image string
+	// ConfigPath is an optional fly.toml whose settings are deployed
+	ConfigPath string
+	// Environment applies the named [environments] section of ConfigPath
+	Environment string
+	// Env overrides environment variables in the configuration
+	Env map[string]string
+	// Strategy is one of canary, rolling, bluegreen or immediate
+	Strategy string
+	// Message describes the release, e.g. what changed
+	Message string
+	// Labels annotate the release with arbitrary key/value pairs
+	Labels map[string]string
+	// Detach returns as soon as the release is created rather than waiting
+	// for the deployment to finish
+	Detach bool
+}
+
+// DeployResult describes the outcome of Deploy
+type DeployResult struct {
+	Release *Release `json:"release"`
+	// Deployment is the final state of the rollout, nil when detached or when
+	// the immediate strategy was used
+	Deployment *Deployment `json:"deployment,omitempty"`
+	// FailedInstances lists the instances that failed their health checks
+	FailedInstances []Instance `json:"failedInstances,omitempty"`
+}
+
+// Deploy releases opts.Image to appName and, unless detached, waits for the
+// deployment to finish. ctx bounds the wait, the deployment itself carries on
+// if it's cancelled.
+func (c *Client) Deploy(ctx context.Context, appName string, opts DeployOptions) (*DeployResult, error) {
+	if opts.Image == "" {
+		return nil, errors.New("flyapi: an image is required")
+	}
+
+	appConfig := flyctl.NewAppConfig()
+	if opts.ConfigPath != "" {
+		cfg, err := flyctl.LoadAppConfigEnvironment(opts.ConfigPath, opts.Environment)
+		if err != nil {
+			return nil, err
+		}
+		appConfig = cfg
+	}
+	if len(opts.Env) > 0 {
+		appConfig.SetEnvVariables(opts.Env)
+	}
+
+	parsedCfg, err := c.api.ParseConfig(appName, appConfig.Definition)
+	if err != nil {
+		if parsedCfg != nil && len(parsedCfg.Errors) > 0 {
+			return nil, &ConfigError{Errors: parsedCfg.Errors}
+		}
+		return nil, err
+	}
+
+	img, err := c.api.ResolveImageForApp(appName, opts.Image)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, fmt.Errorf("flyapi: image %s not found", opts.Image)
+	}
+
+	input := api.DeployImageInput{
+		AppID: appName,
+		Image: img.Ref,
+	}
+	if opts.Strategy != "" {
+		input.Strategy = api.StringPointer(strings.ToUpper(opts.Strategy))
+	}
+	if len(appConfig.Definition) > 0 {
+		input.Definition = api.DefinitionPtr(appConfig.Definition)
+	}
+	input.Message = opts.Message
+	input.Labels = opts.Labels
+
+	release, releaseCommand, err := c.api.DeployImage(input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeployResult{Release: newRelease(release)}
+
+	if opts.Detach {
+		return result, nil
+	}
+
+	if releaseCommand != nil {
+		if err := c.waitForReleaseCommand(ctx, releaseCommand.ID); err != nil {
+			return result, err
+		}
+	}
+
+	if release.DeploymentStrategy == "IMMEDIATE" {
+		return result, nil
+	}
+
+	monitor := deployment.NewDeploymentMonitor(c.api, appName)
+	monitor.DeploymentSucceeded = func(d *api.DeploymentStatus) error {
+		result.Deployment = newDeployment(d)
+		return nil
+	}
+	monitor.DeploymentFailed = func(d *api.DeploymentStatus, failedAllocs []*api.AllocationStatus) error {
+		result.Deployment = newDeployment(d)
+		for _, alloc := range failedAllocs {
+			result.FailedInstances = append(result.FailedInstances, newInstance(alloc))
+		}
+		return nil
+	}
+
+	monitor.Start(ctx)
+
+	if err := monitor.Error(); err != nil {
+		return result, err
+	}
+	if !monitor.Success() {
+		return result, ErrDeployFailed
+	}
+
+	return result, nil
+}
+
+func (c *Client) waitForReleaseCommand(ctx context.Context, id string) error {
+	var errorCount int
+
+	for {
+		rc, err := c.api.GetReleaseCommand(ctx, id)
+		if err != nil {
+			errorCount++
+			if errorCount >= 3 {
+				return err
+			}
+		} else if !rc.InProgress {
+			if rc.Failed {
+				return ErrReleaseCommandFailed
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
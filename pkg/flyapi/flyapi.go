@@ -0,0 +1,87 @@
+// Package flyapi is a supported Go interface to the operations flyctl
+// performs, for tools that want to deploy apps, manage secrets and check on
+// status without shelling out to flyctl and scraping its output.
+//
+// The types here are part of the package's API and only change in backwards
+// compatible ways, unlike the flyctl api package they wrap.
+package flyapi
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flyctl"
+)
+
+// DefaultBaseURL is the Fly API clients talk to unless given WithBaseURL
+const DefaultBaseURL = "https://api.fly.io"
+
+// ErrNoAccessToken is returned by New when no access token is given
+var ErrNoAccessToken = errors.New("flyapi: an access token is required")
+
+// Client performs flyctl operations against the Fly API
+type Client struct {
+	api *api.Client
+}
+
+type options struct {
+	baseURL string
+}
+
+// Option configures a Client created with New
+type Option func(*options)
+
+// WithBaseURL makes the Client talk to the API at baseURL rather than
+// DefaultBaseURL
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates a Client authenticated with accessToken
+func New(accessToken string, opts ...Option) (*Client, error) {
+	if accessToken == "" {
+		return nil, ErrNoAccessToken
+	}
+
+	o := options{baseURL: DefaultBaseURL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Client{api: api.NewClientWithBaseURL(o.baseURL, accessToken, flyctl.Version)}, nil
+}
+
+// Release is a version of an app
+type Release struct {
+	ID          string `json:"id"`
+	Version     int    `json:"version"`
+	Stable      bool   `json:"stable"`
+	InProgress  bool   `json:"inProgress"`
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	// Message and Labels are the annotations given when deploying
+	Message string            `json:"message,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func newRelease(r *api.Release) *Release {
+	if r == nil {
+		return nil
+	}
+
+	return &Release{
+		ID:          r.ID,
+		Version:     r.Version,
+		Stable:      r.Stable,
+		InProgress:  r.InProgress,
+		Reason:      r.Reason,
+		Description: r.Description,
+		Status:      r.Status,
+		Message:     r.Message,
+		Labels:      r.Labels,
+	}
+}
@@ -0,0 +1,78 @@
+package flyapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/superfly/flyctl/pkg/flyapi"
+)
+
+func TestStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("got request to %s, want /graphql", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("got Authorization %q, want Bearer token", got)
+		}
+
+		var req struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(req.Query, "appstatus:app") {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": []map[string]string{{"message": "unexpected query"}},
+			})
+			return
+		}
+		if req.Variables["appName"] != "my-app" {
+			t.Errorf("got appName %v, want my-app", req.Variables["appName"])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"appstatus": map[string]interface{}{
+					"name":         "my-app",
+					"status":       "running",
+					"deployed":     true,
+					"version":      3,
+					"hostname":     "my-app.fly.dev",
+					"organization": map[string]string{"slug": "acme"},
+					"allocations": []map[string]interface{}{
+						{"id": "a1b2c3d4", "version": 3, "region": "iad", "status": "running", "healthy": true, "passingCheckCount": 1},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := flyapi.New("token", flyapi.WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := client.Status("my-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.App != "my-app" || status.Org != "acme" || status.Version != 3 || !status.Deployed {
+		t.Errorf("got %+v", status)
+	}
+	if len(status.Instances) != 1 || status.Instances[0].ID != "a1b2c3d4" || !status.Instances[0].Healthy || status.Instances[0].PassingChecks != 1 {
+		t.Errorf("got instances %+v", status.Instances)
+	}
+}
+
+func TestNewRequiresAccessToken(t *testing.T) {
+	if _, err := flyapi.New(""); err != flyapi.ErrNoAccessToken {
+		t.Errorf("got %v, want ErrNoAccessToken", err)
+	}
+}
@@ -0,0 +1,48 @@
+package flyapi
+
+import (
+	"time"
+)
+
+// Secret is the name and digest of an app secret. Values can't be read back.
+type Secret struct {
+	Name      string    `json:"name"`
+	Digest    string    `json:"digest"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Secrets lists the secrets set on appName
+func (c *Client) Secrets(appName string) ([]Secret, error) {
+	secrets, err := c.api.GetAppSecrets(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Secret, 0, len(secrets))
+	for _, s := range secrets {
+		out = append(out, Secret{Name: s.Name, Digest: s.Digest, CreatedAt: s.CreatedAt})
+	}
+
+	return out, nil
+}
+
+// SetSecrets sets secrets on appName, returning the release that rolls them out
+func (c *Client) SetSecrets(appName string, secrets map[string]string) (*Release, error) {
+	release, err := c.api.SetSecrets(appName, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelease(release), nil
+}
+
+// UnsetSecrets removes the named secrets from appName, returning the release
+// that rolls out the change
+func (c *Client) UnsetSecrets(appName string, names []string) (*Release, error) {
+	release, err := c.api.UnsetSecrets(appName, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelease(release), nil
+}
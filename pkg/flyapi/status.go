@@ -0,0 +1,100 @@
+package flyapi
+
+import (
+	"github.com/superfly/flyctl/api"
+)
+
+// Status is the current state of an app and its instances
+type Status struct {
+	App        string      `json:"app"`
+	Org        string      `json:"org"`
+	Status     string      `json:"status"`
+	Deployed   bool        `json:"deployed"`
+	Version    int         `json:"version"`
+	Hostname   string      `json:"hostname"`
+	Deployment *Deployment `json:"deployment,omitempty"`
+	Instances  []Instance  `json:"instances"`
+}
+
+// Instance is a running copy of an app
+type Instance struct {
+	ID            string `json:"id"`
+	Version       int    `json:"version"`
+	Region        string `json:"region"`
+	Status        string `json:"status"`
+	DesiredStatus string `json:"desiredStatus"`
+	Healthy       bool   `json:"healthy"`
+	Restarts      int    `json:"restarts"`
+	PassingChecks int    `json:"passingChecks"`
+	FailingChecks int    `json:"failingChecks"`
+}
+
+func newInstance(alloc *api.AllocationStatus) Instance {
+	return Instance{
+		ID:            alloc.ID,
+		Version:       alloc.Version,
+		Region:        alloc.Region,
+		Status:        alloc.Status,
+		DesiredStatus: alloc.DesiredStatus,
+		Healthy:       alloc.Healthy,
+		Restarts:      alloc.Restarts,
+		PassingChecks: alloc.PassingCheckCount,
+		FailingChecks: alloc.CriticalCheckCount,
+	}
+}
+
+// Deployment is the rollout of a release to an app's instances
+type Deployment struct {
+	ID             string `json:"id"`
+	Version        int    `json:"version"`
+	Status         string `json:"status"`
+	Description    string `json:"description"`
+	InProgress     bool   `json:"inProgress"`
+	Successful     bool   `json:"successful"`
+	DesiredCount   int    `json:"desiredCount"`
+	HealthyCount   int    `json:"healthyCount"`
+	UnhealthyCount int    `json:"unhealthyCount"`
+}
+
+func newDeployment(d *api.DeploymentStatus) *Deployment {
+	if d == nil {
+		return nil
+	}
+
+	return &Deployment{
+		ID:             d.ID,
+		Version:        d.Version,
+		Status:         d.Status,
+		Description:    d.Description,
+		InProgress:     d.InProgress,
+		Successful:     d.Successful,
+		DesiredCount:   d.DesiredCount,
+		HealthyCount:   d.HealthyCount,
+		UnhealthyCount: d.UnhealthyCount,
+	}
+}
+
+// Status returns the state of appName and its running instances
+func (c *Client) Status(appName string) (*Status, error) {
+	app, err := c.api.GetAppStatus(appName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		App:        app.Name,
+		Org:        app.Organization.Slug,
+		Status:     app.Status,
+		Deployed:   app.Deployed,
+		Version:    app.Version,
+		Hostname:   app.Hostname,
+		Deployment: newDeployment(app.DeploymentStatus),
+		Instances:  []Instance{},
+	}
+
+	for _, alloc := range app.Allocations {
+		status.Instances = append(status.Instances, newInstance(alloc))
+	}
+
+	return status, nil
+}
@@ -0,0 +1,76 @@
+package iostreams
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+)
+
+// asciiOutput is set when glyphs should be replaced with ASCII, because the
+// terminal can't draw them or --ascii was given
+var asciiOutput bool
+
+// quietOutput is set by --quiet, when spinners and other decorative output
+// are left out
+var quietOutput bool
+
+// asciiGlyphs are the ASCII replacements for the glyphs flyctl prints
+var asciiGlyphs = map[string]string{
+	"✔": "OK",
+	"✓": "OK",
+	"✘": "x",
+	"⇡": "^",
+	"•": "*",
+	"→": "->",
+	"…": "...",
+	"↩︎": "\\n",
+}
+
+// EnvASCIIForced - whether FLY_ASCII asks for ASCII only output
+func EnvASCIIForced() bool {
+	return os.Getenv("FLY_ASCII") != "" && os.Getenv("FLY_ASCII") != "0"
+}
+
+// ASCII - whether glyphs are being replaced with ASCII
+func ASCII() bool {
+	return asciiOutput
+}
+
+// Glyph - returns g, or its ASCII replacement when the terminal can't draw it
+func Glyph(g string) string {
+	if !asciiOutput {
+		return g
+	}
+	if r, ok := asciiGlyphs[g]; ok {
+		return r
+	}
+	return g
+}
+
+// Quiet - whether decorative output is suppressed
+func Quiet() bool {
+	return quietOutput
+}
+
+// NewSpinner - a spinner on stderr showing prefix. It writes nothing in
+// quiet mode, so callers can start and stop it regardless.
+func NewSpinner(prefix string) *spinner.Spinner {
+	s := spinner.New(SpinnerCharSet(), 100*time.Millisecond)
+	s.Writer = os.Stderr
+	if quietOutput {
+		s.Writer = ioutil.Discard
+	}
+	s.Prefix = prefix
+	return s
+}
+
+// SpinnerCharSet - the spinner frames to use, plain ASCII when glyphs can't
+// be drawn
+func SpinnerCharSet() []string {
+	if asciiOutput {
+		return spinner.CharSets[9]
+	}
+	return spinner.CharSets[11]
+}
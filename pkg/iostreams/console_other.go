@@ -0,0 +1,12 @@
+//go:build !windows
+// +build !windows
+
+package iostreams
+
+import "os"
+
+// prepareConsole - terminals other than Windows consoles handle ANSI escapes
+// and unicode glyphs without any setup
+func prepareConsole(f *os.File) (ansi bool, unicode bool) {
+	return true, true
+}
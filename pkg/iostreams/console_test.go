@@ -0,0 +1,64 @@
+package iostreams
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestGlyph(t *testing.T) {
+	t.Cleanup(func() { asciiOutput = false })
+
+	asciiOutput = false
+	if got := Glyph("✘"); got != "✘" {
+		t.Errorf("expected unicode glyph, got %q", got)
+	}
+
+	asciiOutput = true
+	if got := Glyph("✘"); got != "x" {
+		t.Errorf("expected ASCII fallback, got %q", got)
+	}
+	if got := Glyph("?"); got != "?" {
+		t.Errorf("expected unknown glyph unchanged, got %q", got)
+	}
+}
+
+func TestForcePlain(t *testing.T) {
+	t.Cleanup(func() { asciiOutput = false })
+
+	io, _, out, _ := Test()
+	io.colorEnabled = true
+	io.ForcePlain()
+	io.ForcePlain()
+
+	if io.ColorEnabled() || !ASCII() || !io.Plain() {
+		t.Fatal("expected plain ASCII output without color")
+	}
+
+	io.Out.Write([]byte("\x1b[32mhello\x1b[0m\n"))
+	if got := out.String(); got != "hello\n" {
+		t.Errorf("expected escape sequences stripped, got %q", got)
+	}
+}
+
+func TestSetQuiet(t *testing.T) {
+	t.Cleanup(func() { quietOutput = false })
+
+	io, _, _, errOut := Test()
+	io.progressIndicatorEnabled = true
+	io.SetQuiet(true)
+
+	if !Quiet() {
+		t.Fatal("expected quiet output")
+	}
+
+	io.StartProgressIndicatorMsg("working")
+	io.StopProgressIndicator()
+	if io.progressIndicator != nil || errOut.Len() != 0 {
+		t.Errorf("expected no progress indicator, got %q", errOut.String())
+	}
+
+	s := NewSpinner("working")
+	if s.Writer != ioutil.Discard {
+		t.Error("expected the spinner to write nowhere")
+	}
+}
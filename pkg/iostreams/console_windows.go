@@ -0,0 +1,39 @@
+//go:build windows
+// +build windows
+
+package iostreams
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// prepareConsole - turns on ANSI escape handling for f, reporting whether it
+// worked and whether the terminal can draw unicode glyphs. Consoles hosted by
+// ConPTY, such as Windows Terminal, handle both. The legacy console can't do
+// either, so colors are translated by go-colorable and glyphs replaced.
+func prepareConsole(f *os.File) (ansi bool, unicode bool) {
+	handle := windows.Handle(f.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		// not a console, e.g. a pipe or a cygwin/msys pty which does its own
+		// escape handling
+		return true, true
+	}
+
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING == 0 {
+		if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+			return false, false
+		}
+	}
+
+	return true, isConPTYHost()
+}
+
+// isConPTYHost - whether the terminal is a modern host with unicode fonts
+// rather than conhost with its raster fonts
+func isConPTYHost() bool {
+	return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") != "" || os.Getenv("ConEmuANSI") == "ON"
+}
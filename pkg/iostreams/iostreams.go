@@ -30,6 +30,7 @@ type IOStreams struct {
 	// the original (non-colorable) output stream
 	originalOut   io.Writer
 	colorEnabled  bool
+	plain         bool
 	is256enabled  bool
 	terminalTheme string
 
@@ -55,6 +56,40 @@ func (s *IOStreams) ColorEnabled() bool {
 	return s.colorEnabled
 }
 
+// SetASCII - sets whether glyphs are replaced with ASCII
+func (s *IOStreams) SetASCII(ascii bool) {
+	asciiOutput = ascii
+}
+
+// SetQuiet - sets whether spinners and other decorative output are
+// suppressed, leaving only results and errors
+func (s *IOStreams) SetQuiet(quiet bool) {
+	quietOutput = quiet
+	if quiet {
+		s.progressIndicatorEnabled = false
+	}
+}
+
+// ForcePlain - ASCII only output, for --ascii and FLY_ASCII. Glyphs are
+// replaced and colors, spinners and other escape sequences are left out.
+func (s *IOStreams) ForcePlain() {
+	asciiOutput = true
+	if s.plain {
+		return
+	}
+
+	s.plain = true
+	s.colorEnabled = false
+	s.progressIndicatorEnabled = false
+	s.Out = colorable.NewNonColorable(s.Out)
+	s.ErrOut = colorable.NewNonColorable(s.ErrOut)
+}
+
+// Plain - whether plain output was forced with ForcePlain
+func (s *IOStreams) Plain() bool {
+	return s.plain
+}
+
 func (s *IOStreams) ColorSupport256() bool {
 	return s.is256enabled
 }
@@ -234,7 +269,7 @@ func (s *IOStreams) StartProgressIndicatorMsg(msg string) {
 	if !s.progressIndicatorEnabled {
 		return
 	}
-	sp := spinner.New(spinner.CharSets[11], 250*time.Millisecond, spinner.WithWriter(s.ErrOut))
+	sp := spinner.New(SpinnerCharSet(), 250*time.Millisecond, spinner.WithWriter(s.ErrOut))
 	sp.Prefix = appendMissingCharacter(msg, ' ')
 	sp.Start()
 	s.progressIndicator = sp
@@ -321,12 +356,16 @@ func System() *IOStreams {
 
 	pagerCommand := os.Getenv("PAGER")
 
+	// this has to happen before go-colorable looks at the console mode
+	ansi, unicode := prepareConsole(os.Stdout)
+	prepareConsole(os.Stderr)
+
 	io := &IOStreams{
 		In:           os.Stdin,
 		originalOut:  os.Stdout,
 		Out:          colorable.NewColorable(os.Stdout),
 		ErrOut:       colorable.NewColorable(os.Stderr),
-		colorEnabled: EnvColorForced() || (!EnvColorDisabled() && stdoutIsTTY),
+		colorEnabled: EnvColorForced() || (!EnvColorDisabled() && stdoutIsTTY && ansi),
 		is256enabled: Is256ColorSupported(),
 		pagerCommand: pagerCommand,
 	}
@@ -335,6 +374,12 @@ func System() *IOStreams {
 		io.progressIndicatorEnabled = true
 	}
 
+	if EnvASCIIForced() {
+		io.ForcePlain()
+	} else if !unicode {
+		io.SetASCII(true)
+	}
+
 	// prevent duplicate isTerminal queries now that we know the answer
 	io.SetStdoutTTY(stdoutIsTTY)
 	io.SetStderrTTY(stderrIsTTY)
@@ -3,6 +3,7 @@ package ssh
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
 	"net"
 
@@ -91,3 +92,48 @@ func (c *Client) Shell(ctx context.Context, term *Terminal, cmd string) error {
 
 	return term.attach(ctx, sess, cmd)
 }
+
+// Run runs cmd without a terminal, copying its output to stdout and stderr.
+// A command that exits non-zero returns an error, see ExitStatus.
+func (c *Client) Run(ctx context.Context, cmd string, stdout, stderr io.Writer) error {
+	if c.client == nil {
+		if err := c.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	sess, err := c.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	sess.Stdout = stdout
+	sess.Stderr = stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sess.Run(cmd)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		sess.Signal(ssh.SIGTERM)
+		return ctx.Err()
+	}
+}
+
+// ExitStatus returns the exit status of a command that failed in Run, or -1
+// when err isn't a command exiting
+func ExitStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus()
+	}
+	return -1
+}